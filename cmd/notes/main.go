@@ -9,6 +9,15 @@
 //	--render-light  Force light-theme markdown rendering (sets CLI_NOTES_GLAMOUR_STYLE=light).
 //	--configure     Re-run the interactive configurator to change the notes directory.
 //	--version       Print the application version and commit hash, then exit.
+//	--doctor        Same as the doctor command.
+//	--export-zip    Zip the notes directory into a timestamped archive, then exit.
+//	--export-include-managed  Include the managed .cli-notes folder in --export-zip.
+//
+// Commands:
+//
+//	doctor                         Check the notes directory for names that differ only by case and templates that do not resolve, report drafts usage, then exit.
+//	agenda [today|week|next-week]  Print the notes dated in the range (default today), then exit.
+//	state export|import <file>     Write the workspace's pins, positions, open counts, and saved views to file, or merge them from it.
 //
 // Environment:
 //
@@ -54,6 +63,10 @@ func main() {
 	renderLight := flag.Bool("render-light", false, "render markdown using a light theme")
 	configure := flag.Bool("configure", false, "run configurator to choose the notes directory")
 	showVersion := flag.Bool("version", false, "print version and exit")
+	doctor := flag.Bool("doctor", false, "same as the doctor command")
+	exportZip := flag.String("export-zip", "", "zip the notes directory into `path` (a directory or .zip file) and exit")
+	exportManaged := flag.Bool("export-include-managed", false, "include the managed .cli-notes folder in --export-zip")
+	flag.Usage = usage
 	flag.Parse()
 
 	if *showVersion {
@@ -61,6 +74,48 @@ func main() {
 		return
 	}
 
+	if *doctor || flag.Arg(0) == "doctor" {
+		problems, err := app.RunDoctor(os.Stdout)
+		if err != nil {
+			log.Error("run doctor", "error", err)
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		if problems > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "agenda" {
+		if err := app.RunAgenda(os.Stdout, flag.Arg(1)); err != nil {
+			log.Error("print agenda", "error", err)
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "state" {
+		if err := app.RunState(os.Stdout, flag.Arg(1), flag.Arg(2)); err != nil {
+			log.Error("transfer workspace state", "error", err)
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *exportZip != "" {
+		archivePath, count, err := app.RunExportZip(*exportZip, *exportManaged)
+		if err != nil {
+			log.Error("export workspace", "error", err)
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported %d file(s) to %s\n", count, archivePath)
+		return
+	}
+
 	if *renderLight {
 		_ = os.Setenv("CLI_NOTES_GLAMOUR_STYLE", "light")
 	}
@@ -92,7 +147,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithReportFocus())
 	if _, err := p.Run(); err != nil {
 		log.Error("run bubbletea program", "error", err)
 		fmt.Fprintln(os.Stderr, "error:", err)
@@ -100,6 +155,20 @@ func main() {
 	}
 }
 
+// usage prints the commands and flags for -h and flag errors.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "Usage: notes [flags] [command]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+	fmt.Fprintln(out, "  doctor                          check the notes directory for problems and exit")
+	fmt.Fprintln(out, "  agenda [today|week|next-week]   print the notes dated in the range and exit")
+	fmt.Fprintln(out, "  state export|import <file>      export or merge the workspace's pins, positions, and saved views")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Flags:")
+	flag.PrintDefaults()
+}
+
 func versionString() string {
 	return fmt.Sprintf("notes %s (%s)", buildVersion, buildCommit)
 }
@@ -0,0 +1,344 @@
+// agenda.go implements the agenda popup (`C`), the "today: N" footer badge,
+// and `notes agenda`, which prints the same view for scripts.
+//
+// A note is on the agenda when its frontmatter `event` or `date` parses as a
+// calendar date (NoteMetadata.When) or, failing that, when its filename is a
+// date such as a journal entry (2025-02-07.md). The popup shows one range at
+// a time — today, this week, or next week (weeks start on Monday), cycled
+// with Tab — grouped by day. Within a day, date-only notes come first and
+// notes with a time of day follow in time order. Archived notes are skipped.
+//
+// Entries come from the search index. The badge recounts only when the index
+// version or the day changes; until the index has been built (it is built
+// lazily), a one-off background scan at startup provides the count.
+package app
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// agendaRange is the span of days shown by the agenda.
+type agendaRange int
+
+const (
+	agendaToday agendaRange = iota
+	agendaThisWeek
+	agendaNextWeek
+)
+
+// agendaRangeLabels are the popup titles, in agendaRange order.
+var agendaRangeLabels = []string{"Today", "This week", "Next week"}
+
+// agendaEntry is one note on the agenda.
+type agendaEntry struct {
+	path    string
+	title   string
+	when    time.Time
+	hasTime bool
+}
+
+// agendaDay groups the entries of one calendar day.
+type agendaDay struct {
+	day     time.Time
+	entries []agendaEntry
+}
+
+// agendaBadgeState caches the footer count for one notes root and day.
+type agendaBadgeState struct {
+	root      string
+	day       string
+	version   int
+	fromIndex bool
+	count     int
+}
+
+// agendaBadgeMsg carries the startup scan's count of notes dated today.
+type agendaBadgeMsg struct {
+	root  string
+	day   string
+	count int
+}
+
+// parseAgendaRange maps a CLI argument to a range; empty means today.
+func parseAgendaRange(name string) (agendaRange, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "today":
+		return agendaToday, nil
+	case "week", "this-week":
+		return agendaThisWeek, nil
+	case "next-week", "next":
+		return agendaNextWeek, nil
+	default:
+		return agendaToday, fmt.Errorf("unknown agenda range %q (use today, week, or next-week)", name)
+	}
+}
+
+// agendaRangeBounds returns the half-open span [start, end) of r around now,
+// in now's location.
+func agendaRangeBounds(r agendaRange, now time.Time) (time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch r {
+	case agendaThisWeek, agendaNextWeek:
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		if r == agendaNextWeek {
+			monday = monday.AddDate(0, 0, 7)
+		}
+		return monday, monday.AddDate(0, 0, 7)
+	default:
+		return today, today.AddDate(0, 0, 1)
+	}
+}
+
+// agendaEntryFor returns the agenda entry of a note in loc, if it has a date.
+// Date-only values keep their calendar day whatever loc is.
+func agendaEntryFor(path string, meta NoteMetadata, loc *time.Location) (agendaEntry, bool) {
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	title := strings.TrimSpace(meta.Title)
+	if title == "" {
+		title = stem
+	}
+	entry := agendaEntry{path: path, title: title}
+	switch {
+	case !meta.When.IsZero() && meta.WhenHasTime:
+		entry.when, entry.hasTime = meta.When.In(loc), true
+	case !meta.When.IsZero():
+		entry.when = time.Date(meta.When.Year(), meta.When.Month(), meta.When.Day(), 0, 0, 0, 0, loc)
+	default:
+		day, err := time.ParseInLocation("2006-01-02", stem, loc)
+		if err != nil {
+			return agendaEntry{}, false
+		}
+		entry.when = day
+	}
+	return entry, true
+}
+
+// agendaEntries returns the indexed notes dated within [start, end), sorted.
+func (i *searchIndex) agendaEntries(start, end time.Time) []agendaEntry {
+	var out []agendaEntry
+	for _, doc := range i.docs {
+		if doc.item.isDir || !hasSuffixCaseInsensitive(doc.item.name, ".md") || isArchivedPath(i.root, doc.item.path) {
+			continue
+		}
+		entry, ok := agendaEntryFor(doc.item.path, doc.metadata, start.Location())
+		if !ok || entry.when.Before(start) || !entry.when.Before(end) {
+			continue
+		}
+		out = append(out, entry)
+	}
+	sortAgendaEntries(out)
+	return out
+}
+
+// sortAgendaEntries orders entries by day; within a day date-only entries
+// come first, then timed entries by time, then by title and path.
+func sortAgendaEntries(entries []agendaEntry) {
+	sort.SliceStable(entries, func(a, b int) bool {
+		ea, eb := entries[a], entries[b]
+		if da, db := agendaDayKey(ea.when), agendaDayKey(eb.when); da != db {
+			return da < db
+		}
+		if ea.hasTime != eb.hasTime {
+			return !ea.hasTime
+		}
+		if ea.hasTime && !ea.when.Equal(eb.when) {
+			return ea.when.Before(eb.when)
+		}
+		if ta, tb := strings.ToLower(ea.title), strings.ToLower(eb.title); ta != tb {
+			return ta < tb
+		}
+		return ea.path < eb.path
+	})
+}
+
+// groupAgenda splits sorted entries into days.
+func groupAgenda(entries []agendaEntry) []agendaDay {
+	var days []agendaDay
+	for _, entry := range entries {
+		if n := len(days); n > 0 && agendaDayKey(days[n-1].day) == agendaDayKey(entry.when) {
+			days[n-1].entries = append(days[n-1].entries, entry)
+			continue
+		}
+		day := time.Date(entry.when.Year(), entry.when.Month(), entry.when.Day(), 0, 0, 0, 0, entry.when.Location())
+		days = append(days, agendaDay{day: day, entries: []agendaEntry{entry}})
+	}
+	return days
+}
+
+// agendaDayKey identifies the calendar day of t in its own location.
+func agendaDayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// agendaRangeTitle describes a range, e.g. "This week (Mon Feb 3 – Sun Feb 9)".
+func agendaRangeTitle(r agendaRange, start, end time.Time) string {
+	if r == agendaToday {
+		return fmt.Sprintf("%s (%s)", agendaRangeLabels[r], start.Format("Mon Jan 2"))
+	}
+	return fmt.Sprintf("%s (%s – %s)", agendaRangeLabels[r], start.Format("Mon Jan 2"), end.AddDate(0, 0, -1).Format("Mon Jan 2"))
+}
+
+// agendaEntryLabel formats an entry as its time (or "all day") and title.
+func agendaEntryLabel(entry agendaEntry) string {
+	when := "all day"
+	if entry.hasTime {
+		when = entry.when.Format("15:04")
+	}
+	return fmt.Sprintf("%-7s  %s", when, entry.title)
+}
+
+// openAgendaPopup shows the agenda for today.
+func (m *Model) openAgendaPopup() {
+	if m.searchIndex == nil {
+		return
+	}
+	start := time.Now()
+	if err := m.searchIndex.ensureBuilt(); err != nil {
+		m.setStatusError("Error building agenda", err, "root", m.notesDir)
+		return
+	}
+	if elapsed := time.Since(start); m.isSlowOp(elapsed) {
+		m.reportSlowOp(slowOpReport{op: slowOpSearch, phase: slowPhaseIndexBuild, elapsed: elapsed, items: len(m.searchIndex.docs), itemsNoun: "indexed entries"})
+	}
+	m.openOverlay(overlayAgenda)
+	m.showHelp = false
+	m.agendaRange = agendaToday
+	m.loadAgenda()
+	m.status = "Agenda: Tab to change range, Enter to open, Esc to close"
+}
+
+// loadAgenda fills the popup entries for the current range.
+func (m *Model) loadAgenda() {
+	start, end := agendaRangeBounds(m.agendaRange, appNow())
+	m.agendaEntries = m.searchIndex.agendaEntries(start, end)
+	m.agendaCursor = 0
+}
+
+// handleAgendaPopupKey routes key presses while the agenda popup is visible.
+func (m *Model) handleAgendaPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	switch m.popupAction(msg) {
+	case popupActionAgendaNext:
+		m.agendaRange = (m.agendaRange + 1) % agendaRange(len(agendaRangeLabels))
+		m.loadAgenda()
+		return m, nil
+	case popupActionAgendaPrev:
+		m.agendaRange = (m.agendaRange + agendaRange(len(agendaRangeLabels)) - 1) % agendaRange(len(agendaRangeLabels))
+		m.loadAgenda()
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.agendaCursor, len(m.agendaEntries))
+	if !handled {
+		return m, nil
+	}
+	if closePressed {
+		m.closeOverlay()
+		m.status = "Agenda closed"
+		return m, nil
+	}
+	if len(m.agendaEntries) == 0 {
+		return m, nil
+	}
+	m.agendaCursor = next
+	if selectPressed {
+		path := m.agendaEntries[m.agendaCursor].path
+		m.closeOverlay()
+		m.expandParentDirs(path)
+		m.rebuildTreeKeep(path)
+		m.status = "Opened from agenda: " + m.displayRelative(path)
+		return m, m.setFocusedFile(path)
+	}
+	return m, nil
+}
+
+// agendaFooterSegment returns "today: N" when notes are dated today.
+func (m *Model) agendaFooterSegment() string {
+	now := appNow()
+	day := agendaDayKey(now)
+	if idx := m.searchIndex; idx != nil && idx.ready {
+		badge := m.agendaBadge
+		if !badge.fromIndex || badge.root != m.notesDir || badge.day != day || badge.version != idx.version {
+			start, end := agendaRangeBounds(agendaToday, now)
+			m.agendaBadge = agendaBadgeState{root: m.notesDir, day: day, version: idx.version, fromIndex: true, count: len(idx.agendaEntries(start, end))}
+		}
+	}
+	if m.agendaBadge.root != m.notesDir || m.agendaBadge.day != day || m.agendaBadge.count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("today: %d", m.agendaBadge.count)
+}
+
+// scanAgendaBadge counts today's notes in the background so the badge shows
+// before the search index is first built.
+func (m *Model) scanAgendaBadge() tea.Cmd {
+	root := m.notesDir
+	return func() tea.Msg {
+		idx := newSearchIndex(root)
+		if err := idx.build(); err != nil {
+			appLog.Warn("scan agenda", "root", root, "error", err)
+			return nil
+		}
+		now := appNow()
+		start, end := agendaRangeBounds(agendaToday, now)
+		return agendaBadgeMsg{root: root, day: agendaDayKey(now), count: len(idx.agendaEntries(start, end))}
+	}
+}
+
+// handleAgendaBadge stores the startup scan unless the live index took over.
+func (m *Model) handleAgendaBadge(msg agendaBadgeMsg) (tea.Model, tea.Cmd) {
+	if msg.root != m.notesDir || (m.agendaBadge.fromIndex && m.agendaBadge.root == m.notesDir) {
+		return m, nil
+	}
+	m.agendaBadge = agendaBadgeState{root: msg.root, day: msg.day, count: msg.count}
+	return m, nil
+}
+
+// RunAgenda prints the agenda of the configured notes directory for
+// rangeName (today, week, or next-week) to out.
+func RunAgenda(out io.Writer, rangeName string) error {
+	r, err := parseAgendaRange(rangeName)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	idx := newSearchIndex(cfg.NotesDir)
+	if err := idx.build(); err != nil {
+		return err
+	}
+	writeAgenda(out, idx, r, appNow())
+	return nil
+}
+
+// writeAgenda prints the plain-text agenda for r.
+func writeAgenda(out io.Writer, idx *searchIndex, r agendaRange, now time.Time) {
+	start, end := agendaRangeBounds(r, now)
+	fmt.Fprintf(out, "Agenda: %s\n", agendaRangeTitle(r, start, end))
+	days := groupAgenda(idx.agendaEntries(start, end))
+	if len(days) == 0 {
+		fmt.Fprintln(out, "No dated notes")
+		return
+	}
+	for _, day := range days {
+		fmt.Fprintf(out, "\n%s\n", day.day.Format("Mon Jan 2"))
+		for _, entry := range day.entries {
+			rel, err := filepath.Rel(idx.root, entry.path)
+			if err != nil {
+				rel = entry.path
+			}
+			fmt.Fprintf(out, "  %s  (%s)\n", agendaEntryLabel(entry), filepath.ToSlash(rel))
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseNoteDateHandlesDateOnlyAndZones(t *testing.T) {
+	ny := time.FixedZone("EST", -5*3600)
+
+	when, hasTime, ok := parseNoteDate("2025-02-07", ny)
+	if !ok || hasTime || !when.Equal(time.Date(2025, 2, 7, 0, 0, 0, 0, ny)) {
+		t.Fatalf("date-only: got %v %v %v", when, hasTime, ok)
+	}
+	when, hasTime, ok = parseNoteDate("2025-02-07 09:30", ny)
+	if !ok || !hasTime || when.Hour() != 9 || when.Location() != ny {
+		t.Fatalf("local time: got %v %v %v", when, hasTime, ok)
+	}
+	// 01:00 UTC on the 8th is still the evening of the 7th in New York.
+	when, hasTime, ok = parseNoteDate("2025-02-08T01:00:00Z", ny)
+	if !ok || !hasTime || agendaDayKey(when) != "2025-02-07" || when.Hour() != 20 {
+		t.Fatalf("zoned time: got %v %v %v", when, hasTime, ok)
+	}
+	if when, _, ok := parseNoteDate("Feb 7, 2025", ny); !ok || agendaDayKey(when) != "2025-02-07" {
+		t.Fatalf("month-name date: got %v %v", when, ok)
+	}
+	if _, _, ok := parseNoteDate("next friday", ny); ok {
+		t.Fatal("expected free-form dates to be ignored")
+	}
+}
+
+func TestNoteMetadataWhenPrefersEvent(t *testing.T) {
+	meta, _ := parseFrontmatterAndBody("---\ndate: 2025-01-01\nevent: 2025-02-07 14:00\n---\nbody\n")
+	if !meta.WhenHasTime || agendaDayKey(meta.When) != "2025-02-07" {
+		t.Fatalf("expected event to win, got %v %v", meta.When, meta.WhenHasTime)
+	}
+	if len(meta.Extra) != 1 || meta.Extra[0].Key != "event" {
+		t.Fatalf("expected event to stay in Extra, got %+v", meta.Extra)
+	}
+	meta, _ = parseFrontmatterAndBody("---\ndate: someday\n---\n")
+	if !meta.When.IsZero() {
+		t.Fatalf("expected unparseable date to leave When zero, got %v", meta.When)
+	}
+}
+
+func TestAgendaEntryKeepsDateOnlyDayAcrossZones(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+	meta := NoteMetadata{When: time.Date(2025, 2, 7, 0, 0, 0, 0, time.UTC)}
+	entry, ok := agendaEntryFor("/n/meeting.md", meta, tokyo)
+	if !ok || entry.hasTime || !entry.when.Equal(time.Date(2025, 2, 7, 0, 0, 0, 0, tokyo)) {
+		t.Fatalf("expected date-only entry on Feb 7 in loc, got %+v", entry)
+	}
+	entry, ok = agendaEntryFor("/n/journal/2025-02-09.md", NoteMetadata{}, tokyo)
+	if !ok || entry.title != "2025-02-09" || agendaDayKey(entry.when) != "2025-02-09" {
+		t.Fatalf("expected date-named file entry, got %+v %v", entry, ok)
+	}
+	if _, ok := agendaEntryFor("/n/plain.md", NoteMetadata{}, tokyo); ok {
+		t.Fatal("expected undated note to be skipped")
+	}
+}
+
+func TestSortAndGroupAgenda(t *testing.T) {
+	loc := time.FixedZone("X", 2*3600)
+	day := func(d, h, min int) time.Time { return time.Date(2025, 2, d, h, min, 0, 0, loc) }
+	entries := []agendaEntry{
+		{path: "d", title: "Late", when: day(7, 16, 0), hasTime: true},
+		{path: "c", title: "Early", when: day(7, 9, 15), hasTime: true},
+		{path: "e", title: "Next day", when: day(8, 0, 0)},
+		{path: "b", title: "beta", when: day(7, 0, 0)},
+		{path: "a", title: "Alpha", when: day(7, 0, 0)},
+	}
+	sortAgendaEntries(entries)
+	var order []string
+	for _, entry := range entries {
+		order = append(order, entry.path)
+	}
+	if got := strings.Join(order, ","); got != "a,b,c,d,e" {
+		t.Fatalf("unexpected order %s", got)
+	}
+	days := groupAgenda(entries)
+	if len(days) != 2 || len(days[0].entries) != 4 || len(days[1].entries) != 1 {
+		t.Fatalf("unexpected grouping %+v", days)
+	}
+	if !days[1].day.Equal(day(8, 0, 0)) {
+		t.Fatalf("expected second group on Feb 8, got %v", days[1].day)
+	}
+}
+
+func TestAgendaRangeBoundsStartWeeksOnMonday(t *testing.T) {
+	sunday := time.Date(2025, 2, 9, 18, 0, 0, 0, time.UTC)
+	start, end := agendaRangeBounds(agendaThisWeek, sunday)
+	if start.Format("2006-01-02") != "2025-02-03" || end.Format("2006-01-02") != "2025-02-10" {
+		t.Fatalf("this week: %v – %v", start, end)
+	}
+	start, end = agendaRangeBounds(agendaNextWeek, sunday)
+	if start.Format("2006-01-02") != "2025-02-10" || end.Format("2006-01-02") != "2025-02-17" {
+		t.Fatalf("next week: %v – %v", start, end)
+	}
+	start, end = agendaRangeBounds(agendaToday, sunday)
+	if !start.Equal(time.Date(2025, 2, 9, 0, 0, 0, 0, time.UTC)) || end.Sub(start) != 24*time.Hour {
+		t.Fatalf("today: %v – %v", start, end)
+	}
+}
+
+func TestAgendaPopupCyclesRangesAndOpensNote(t *testing.T) {
+	now := time.Date(2025, 2, 7, 8, 0, 0, 0, time.Local)
+	withFixedNow(t, now)
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "standup.md"), "---\ntitle: Standup\nevent: 2025-02-07 09:30\n---\nnotes\n")
+	mustWriteFile(t, filepath.Join(root, "journal", "2025-02-07.md"), "today\n")
+	mustWriteFile(t, filepath.Join(root, "review.md"), "---\ndate: 2025-02-12\n---\n")
+	mustWriteFile(t, filepath.Join(root, ArchiveDirName, "2025-02-07.md"), "archived\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.openAgendaPopup()
+	if !m.isOverlay(overlayAgenda) || len(m.agendaEntries) != 2 {
+		t.Fatalf("expected 2 entries today, got %+v", m.agendaEntries)
+	}
+	if m.agendaEntries[0].title != "2025-02-07" || m.agendaEntries[1].title != "Standup" {
+		t.Fatalf("expected all-day entry before timed entry, got %+v", m.agendaEntries)
+	}
+	if got := m.agendaFooterSegment(); got != "today: 2" {
+		t.Fatalf("expected today badge, got %q", got)
+	}
+
+	_, _ = m.handleAgendaPopupKey(tea.KeyMsg{Type: tea.KeyTab})
+	if m.agendaRange != agendaThisWeek || len(m.agendaEntries) != 2 {
+		t.Fatalf("expected this week with 2 entries, got %v %+v", m.agendaRange, m.agendaEntries)
+	}
+	_, _ = m.handleAgendaPopupKey(tea.KeyMsg{Type: tea.KeyTab})
+	if m.agendaRange != agendaNextWeek || len(m.agendaEntries) != 1 || m.agendaEntries[0].title != "review" {
+		t.Fatalf("expected next week with the review, got %v %+v", m.agendaRange, m.agendaEntries)
+	}
+
+	_, _ = m.handleAgendaPopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.isOverlay(overlayAgenda) || m.currentFile != filepath.Join(root, "review.md") {
+		t.Fatalf("expected review to open, overlay=%v current=%q", m.overlay, m.currentFile)
+	}
+}
+
+func TestAgendaBadgeFollowsIndexChanges(t *testing.T) {
+	now := time.Date(2025, 2, 7, 8, 0, 0, 0, time.Local)
+	withFixedNow(t, now)
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "plain.md"), "plain\n")
+	m := newTestCRUDModel(root)
+	m.searchIndex = newSearchIndex(root)
+
+	msg := m.scanAgendaBadge()()
+	_, _ = m.Update(msg)
+	if got := m.agendaFooterSegment(); got != "" {
+		t.Fatalf("expected no badge, got %q", got)
+	}
+	_, _ = m.Update(agendaBadgeMsg{root: root, day: "2025-02-07", count: 4})
+	if got := m.agendaFooterSegment(); got != "today: 4" {
+		t.Fatalf("expected startup scan badge, got %q", got)
+	}
+
+	if err := m.searchIndex.ensureBuilt(); err != nil {
+		t.Fatalf("build index: %v", err)
+	}
+	if got := m.agendaFooterSegment(); got != "" {
+		t.Fatalf("expected live index to replace the scan, got %q", got)
+	}
+	path := filepath.Join(root, "meeting.md")
+	mustWriteFile(t, path, "---\ndate: 2025-02-07\n---\n")
+	m.searchIndex.upsertPath(path)
+	if got := m.agendaFooterSegment(); got != "today: 1" {
+		t.Fatalf("expected badge to follow the index, got %q", got)
+	}
+}
+
+func TestWriteAgendaPrintsGroupedDays(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "---\ntitle: Kickoff\ndate: 2025-02-04 10:00\n---\n")
+	mustWriteFile(t, filepath.Join(root, "journal", "2025-02-06.md"), "entry\n")
+	idx := newSearchIndex(root)
+	if err := idx.build(); err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	var out bytes.Buffer
+	writeAgenda(&out, idx, agendaThisWeek, time.Date(2025, 2, 5, 12, 0, 0, 0, time.Local))
+	want := "Agenda: This week (Mon Feb 3 – Sun Feb 9)\n\nTue Feb 4\n  10:00    Kickoff  (a.md)\n\nThu Feb 6\n  all day  2025-02-06  (journal/2025-02-06.md)\n"
+	if out.String() != want {
+		t.Fatalf("unexpected agenda:\n%q\nwant\n%q", out.String(), want)
+	}
+	if _, err := parseAgendaRange("later"); err == nil {
+		t.Fatal("expected unknown range error")
+	}
+}
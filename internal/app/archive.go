@@ -0,0 +1,158 @@
+// archive.go implements the archive workflow.
+//
+// Archiving (`A`) moves the selected note or folder into <notesDir>/archive/
+// at the same relative subpath (notes/projects/x.md → archive/projects/x.md)
+// and records where it came from in per-workspace state. Pressing `A` on an
+// item inside the archive restores it to the recorded location, falling back
+// to the path obtained by stripping the archive prefix, and recreates any
+// parent folders that have since been removed.
+//
+// The archive folder is hidden from the tree unless "show archived" (`a`) is
+// on, and search skips archived notes unless the query contains in:archive.
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// archiveDir returns the absolute path of the workspace archive folder.
+func (m *Model) archiveDir() string {
+	return filepath.Join(m.notesDir, ArchiveDirName)
+}
+
+// isArchivedPath reports whether path is the archive folder or inside it.
+func isArchivedPath(root, path string) bool {
+	archive := filepath.Join(root, ArchiveDirName)
+	return path == archive || strings.HasPrefix(path, archive+string(os.PathSeparator))
+}
+
+// withoutArchived drops the archive folder and everything under it.
+func withoutArchived(root string, items []treeItem) []treeItem {
+	out := items[:0:0]
+	for _, item := range items {
+		if !isArchivedPath(root, item.path) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// toggleShowArchived shows or hides the archive folder in the tree.
+func (m *Model) toggleShowArchived() {
+	m.showArchived = !m.showArchived
+	m.rebuildTreeKeep(m.selectedPath())
+	if m.showArchived {
+		m.status = "Showing archived notes"
+	} else {
+		m.status = "Hiding archived notes"
+	}
+}
+
+// toggleArchiveSelected archives the selected item, or restores it when it
+// already lives in the archive.
+func (m *Model) toggleArchiveSelected() {
+	item := m.selectedItem()
+	if item == nil {
+		m.status = "No item selected"
+		return
+	}
+	switch {
+	case item.path == m.notesDir:
+		m.status = "Cannot archive the root notes directory"
+	case item.path == m.archiveDir():
+		m.status = "Cannot archive the archive folder"
+	case isArchivedPath(m.notesDir, item.path):
+		m.unarchivePath(item.path)
+	default:
+		m.archivePath(item.path)
+	}
+}
+
+// archivePath moves path into the archive at its relative subpath.
+func (m *Model) archivePath(path string) {
+	rel, err := filepath.Rel(m.notesDir, path)
+	if err != nil || !isWithinRoot(m.notesDir, path) {
+		m.status = "Invalid archive target"
+		return
+	}
+	dest := filepath.Join(m.archiveDir(), rel)
+	if _, err := os.Stat(dest); err == nil {
+		m.status = "Already in archive: " + m.displayRelative(dest)
+		return
+	}
+	if !m.relocatePath(path, dest, "Error archiving item") {
+		return
+	}
+	if m.archiveOrigins == nil {
+		m.archiveOrigins = map[string]string{}
+	}
+	m.archiveOrigins[dest] = path
+	m.saveAppState()
+
+	keep := dest
+	if !m.showArchived {
+		keep = filepath.Dir(path)
+	}
+	m.rebuildTreeKeep(keep)
+	m.status = "Archived: " + rel
+}
+
+// unarchivePath moves an archived item back to where it was archived from.
+func (m *Model) unarchivePath(path string) {
+	origin := m.archiveOrigins[path]
+	if origin == "" || !isWithinRoot(m.notesDir, origin) || isArchivedPath(m.notesDir, origin) {
+		rel, err := filepath.Rel(m.archiveDir(), path)
+		if err != nil {
+			m.status = "Invalid archive target"
+			return
+		}
+		origin = filepath.Join(m.notesDir, rel)
+	}
+	if _, err := os.Stat(origin); err == nil {
+		m.status = "Original location already exists: " + m.displayRelative(origin)
+		return
+	}
+	if !m.relocatePath(path, origin, "Error restoring item") {
+		return
+	}
+	delete(m.archiveOrigins, path)
+	m.saveAppState()
+	m.expandParentDirs(origin)
+	m.rebuildTreeKeep(origin)
+	m.status = "Restored: " + m.displayRelative(origin)
+}
+
+// relocatePath moves oldPath to newPath (creating missing parent folders)
+// and remaps expansion, state, caches, the search index, and the current
+// file the same way a move does. A destination (or missing parent folder)
+// that differs from an existing sibling only by case is refused. It reports
+// whether the move succeeded.
+func (m *Model) relocatePath(oldPath, newPath, errStatus string) bool {
+	parent := filepath.Dir(newPath)
+	created := missingDirs(m.notesDir, parent)
+	if status, collides := firstCaseCollision(oldPath, append(created, newPath)...); collides {
+		m.status = status
+		return false
+	}
+	if err := os.MkdirAll(parent, DirPermission); err != nil {
+		m.setStatusError(errStatus, err, "path", parent)
+		return false
+	}
+	if err := movePathWithFallback(oldPath, newPath); err != nil {
+		m.setStatusError(errStatus, err, "from", oldPath, "to", newPath)
+		return false
+	}
+
+	m.remapExpandedPaths(oldPath, newPath)
+	m.remapStatePaths(oldPath, newPath)
+	m.remapTreeMetadataPath(oldPath, newPath)
+	m.currentFile = replacePathPrefix(m.currentFile, oldPath, newPath)
+	m.applyMutationEffects(mutationEffects{
+		removePaths: []string{oldPath},
+		upsertPaths: append(created, newPath),
+		refreshGit:  true,
+	})
+	return true
+}
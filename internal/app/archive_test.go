@@ -0,0 +1,126 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func selectTreePath(t *testing.T, m *Model, path string) {
+	t.Helper()
+	m.rebuildTreeKeep(path)
+	if m.selectedPath() != path {
+		t.Fatalf("expected %q to be selectable in tree", path)
+	}
+}
+
+func TestArchiveAndRestoreRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	projects := filepath.Join(root, "projects")
+	note := filepath.Join(projects, "x.md")
+	mustWriteFile(t, note, "# X\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.expanded[projects] = true
+	m.pinnedPaths[note] = true
+	selectTreePath(t, m, note)
+
+	m.toggleArchiveSelected()
+
+	archived := filepath.Join(root, ArchiveDirName, "projects", "x.md")
+	if _, err := os.Stat(archived); err != nil {
+		t.Fatalf("expected archived note at %q: %v", archived, err)
+	}
+	if m.status != "Archived: "+filepath.Join("projects", "x.md") {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	if m.archiveOrigins[archived] != note {
+		t.Fatalf("expected origin %q recorded, got %q", note, m.archiveOrigins[archived])
+	}
+	if !m.pinnedPaths[archived] {
+		t.Fatal("expected pinned state to follow the archived note")
+	}
+	assertTreeHasPath(t, m.items, filepath.Join(root, ArchiveDirName), false)
+
+	state, err := loadAppState(root)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if state.ArchivedFrom[archived] != note {
+		t.Fatalf("expected persisted origin, got %v", state.ArchivedFrom)
+	}
+
+	// The original folder is gone by the time the note is restored.
+	if err := os.Remove(projects); err != nil {
+		t.Fatalf("remove projects: %v", err)
+	}
+	m.toggleShowArchived()
+	m.expanded[filepath.Join(root, ArchiveDirName)] = true
+	m.expanded[filepath.Join(root, ArchiveDirName, "projects")] = true
+	selectTreePath(t, m, archived)
+
+	m.toggleArchiveSelected()
+
+	if _, err := os.Stat(note); err != nil {
+		t.Fatalf("expected note restored to %q: %v", note, err)
+	}
+	if _, ok := m.archiveOrigins[archived]; ok {
+		t.Fatal("expected archive record to be removed after restore")
+	}
+	if m.selectedPath() != note {
+		t.Fatalf("expected restored note selected, got %q", m.selectedPath())
+	}
+}
+
+func TestUnarchiveRefusesWhenOriginExists(t *testing.T) {
+	root := t.TempDir()
+	archived := filepath.Join(root, ArchiveDirName, "x.md")
+	mustWriteFile(t, archived, "old\n")
+	mustWriteFile(t, filepath.Join(root, "x.md"), "new\n")
+	m := newTestCRUDModel(root)
+	m.showArchived = true
+	m.expanded[filepath.Join(root, ArchiveDirName)] = true
+	selectTreePath(t, m, archived)
+
+	m.toggleArchiveSelected()
+
+	if m.status != "Original location already exists: x.md" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	if _, err := os.Stat(archived); err != nil {
+		t.Fatalf("expected archived note to stay put: %v", err)
+	}
+}
+
+func TestArchiveFolderHiddenUnlessShown(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, ArchiveDirName, "old.md"), "old\n")
+	mustWriteFile(t, filepath.Join(root, "keep.md"), "keep\n")
+	m := newTestCRUDModel(root)
+
+	m.rebuildTreeKeep(root)
+	assertTreeHasPath(t, m.items, filepath.Join(root, ArchiveDirName), false)
+	assertTreeHasPath(t, m.items, filepath.Join(root, "keep.md"))
+
+	m.toggleShowArchived()
+	assertTreeHasPath(t, m.items, filepath.Join(root, ArchiveDirName))
+}
+
+func TestSearchSkipsArchiveUnlessInArchive(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "live.md"), "zeus\n")
+	mustWriteFile(t, filepath.Join(root, ArchiveDirName, "old.md"), "zeus\n")
+
+	idx := newSearchIndex(root)
+	if err := idx.ensureBuilt(); err != nil {
+		t.Fatalf("build index: %v", err)
+	}
+
+	got := relPathSet(root, idx.search("zeus"))
+	expectContains(t, got, "live.md")
+	expectNotContains(t, got, filepath.Join(ArchiveDirName, "old.md"))
+
+	got = relPathSet(root, idx.search("zeus in:archive"))
+	expectContains(t, got, "live.md")
+	expectContains(t, got, filepath.Join(ArchiveDirName, "old.md"))
+}
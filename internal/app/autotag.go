@@ -0,0 +1,107 @@
+// autotag.go adds tags to new notes based on the folder they are created in.
+//
+// Rules live in <notes_dir>/.cli-notes/autotag.json, so every workspace has
+// its own:
+//
+//	{
+//	    "rules": [
+//	        {"folder": "meetings", "tags": ["meeting"]},
+//	        {"folder": "projects/acme", "tags": ["acme", "client"]}
+//	    ]
+//	}
+//
+// folder is relative to the notes directory and matches notes created in it
+// or any of its subfolders, by whole path segments ("meetings" does not match
+// "meetings-old"); "." matches every note. All matching rules apply, and their
+// tags are merged into the frontmatter tags after template expansion, keeping
+// tags the template already set. The file is read on each note creation, so
+// edits take effect without a restart. A missing file means no rules; an
+// unreadable or invalid one is logged, noted in the status line, and ignored.
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// autoTagRule tags notes created under Folder with Tags.
+type autoTagRule struct {
+	Folder string   `json:"folder"`
+	Tags   []string `json:"tags"`
+}
+
+// autoTagFile is the on-disk shape of autotag.json.
+type autoTagFile struct {
+	Rules []autoTagRule `json:"rules"`
+}
+
+// autoTagPath returns the rules file of the workspace rooted at notesDir.
+func autoTagPath(notesDir string) string {
+	return filepath.Join(notesDir, managedNotesDirName, AutoTagFileName)
+}
+
+// loadAutoTagRules reads the workspace's rules. A missing file yields no
+// rules and no error.
+func loadAutoTagRules(notesDir string) ([]autoTagRule, error) {
+	data, err := os.ReadFile(autoTagPath(notesDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file autoTagFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", AutoTagFileName, err)
+	}
+	return file.Rules, nil
+}
+
+// autoTagsFor returns the tags of every rule whose folder contains the note
+// at path, normalized and in rule order.
+func autoTagsFor(rules []autoTagRule, notesDir, path string) []string {
+	rel, err := filepath.Rel(notesDir, filepath.Dir(path))
+	if err != nil {
+		return nil
+	}
+	var tags []string
+	for _, rule := range rules {
+		folder := filepath.Clean(filepath.FromSlash(strings.TrimSpace(rule.Folder)))
+		if folder == "." || rel == folder || strings.HasPrefix(rel, folder+string(filepath.Separator)) {
+			tags = append(tags, rule.Tags...)
+		}
+	}
+	return normalizeTagList(tags)
+}
+
+// applyAutoTags merges tags into the frontmatter tags of content. Content is
+// returned unchanged when every tag is already present.
+func applyAutoTags(content string, tags []string) string {
+	if len(tags) == 0 {
+		return content
+	}
+	doc := parseFrontmatterDoc(content)
+	existing := doc.tags()
+	merged := normalizeTagList(append(existing, tags...))
+	if len(merged) == len(existing) {
+		return content
+	}
+	doc.setTags(merged)
+	return doc.String()
+}
+
+// autoTagNewNote applies the workspace's rules to the content of a note about
+// to be created at path. It returns a status suffix when the rules could not
+// be loaded.
+func (m *Model) autoTagNewNote(content, path string) (string, string) {
+	rules, err := loadAutoTagRules(m.notesDir)
+	if err != nil {
+		appLog.Warn("load autotag rules", "path", autoTagPath(m.notesDir), "error", err)
+		return content, " (" + AutoTagFileName + " ignored: " + err.Error() + ")"
+	}
+	return applyAutoTags(content, autoTagsFor(rules, m.notesDir, path)), ""
+}
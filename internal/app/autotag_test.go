@@ -0,0 +1,86 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeAutoTagRules(t *testing.T, root, content string) {
+	t.Helper()
+	mustWriteFile(t, autoTagPath(root), content)
+}
+
+func TestSaveNewNoteAppliesAutoTagRules(t *testing.T) {
+	root := t.TempDir()
+	writeAutoTagRules(t, root, `{"rules": [
+		{"folder": "meetings", "tags": ["meeting"]},
+		{"folder": "meetings/acme", "tags": ["acme", "Meeting"]},
+		{"folder": "projects", "tags": ["project"]}
+	]}`)
+	acme := filepath.Join(root, "meetings", "acme")
+	if err := os.MkdirAll(acme, DirPermission); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	m := newTestCRUDModel(root)
+	m.newParent = acme
+	m.input.SetValue("kickoff")
+	m.selectedTemplate = &noteTemplate{content: "---\ntags: [planning]\n---\n# {{title}}\n"}
+	settlePendingOp(m.saveNewNote())
+
+	got, err := os.ReadFile(filepath.Join(acme, "kickoff.md"))
+	if err != nil {
+		t.Fatalf("read created note: %v", err)
+	}
+	meta, body := parseFrontmatterAndBody(string(got))
+	if want := "planning,meeting,acme"; strings.Join(meta.Tags, ",") != want {
+		t.Fatalf("expected tags %q, got %v in %q", want, meta.Tags, string(got))
+	}
+	if strings.TrimSpace(body) != "# kickoff" {
+		t.Fatalf("expected body kept, got %q", body)
+	}
+	if m.status != "Created note: kickoff.md" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
+func TestSaveNewNoteOutsideRuleFoldersIsUntagged(t *testing.T) {
+	root := t.TempDir()
+	writeAutoTagRules(t, root, `{"rules": [{"folder": "meetings", "tags": ["meeting"]}]}`)
+	other := filepath.Join(root, "meetings-old")
+	if err := os.MkdirAll(other, DirPermission); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	m := newTestCRUDModel(root)
+	m.newParent = other
+	m.input.SetValue("retro")
+	settlePendingOp(m.saveNewNote())
+
+	got, err := os.ReadFile(filepath.Join(other, "retro.md"))
+	if err != nil {
+		t.Fatalf("read created note: %v", err)
+	}
+	if meta, _ := parseFrontmatterAndBody(string(got)); len(meta.Tags) != 0 {
+		t.Fatalf("expected no tags outside meetings/, got %v", meta.Tags)
+	}
+}
+
+func TestSaveNewNoteIgnoresInvalidAutoTagFile(t *testing.T) {
+	root := t.TempDir()
+	writeAutoTagRules(t, root, `{"rules": [`)
+
+	m := newTestCRUDModel(root)
+	m.newParent = root
+	m.input.SetValue("plain")
+	settlePendingOp(m.saveNewNote())
+
+	if _, err := os.Stat(filepath.Join(root, "plain.md")); err != nil {
+		t.Fatalf("expected the note created anyway: %v", err)
+	}
+	if !strings.HasPrefix(m.status, "Created note: plain.md (autotag.json ignored: ") {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
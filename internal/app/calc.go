@@ -0,0 +1,456 @@
+// calc.go implements the editor's inline calculator.
+//
+// Alt+= evaluates an arithmetic expression: the selection when there is one,
+// otherwise the expression that ends at the cursor on the current line (the
+// longest run of numbers and operators before it that parses, so
+// "Total: 12*3" evaluates "12*3"). Alt++ sums a selected column of numbers,
+// taking the last number on each selected line and ignoring the text around
+// it, which totals lists like "- Rent: 1200".
+//
+// Expressions are numbers, + - * / % (remainder), unary signs, and
+// parentheses; a number may carry a k (thousand) or M (million) suffix. They
+// are parsed by a small recursive-descent evaluator (evalCalcExpression), so
+// nothing but arithmetic is ever run. With calc_result "append" (the
+// default) the result is written after the expression as " = <result>" (or
+// on a new "= <result>" line for sums); "replace" swaps the expression for
+// the result. Division by zero and results too large for a float64 leave the
+// buffer unchanged and report the problem in the status bar.
+//
+// calc_decimal_comma reads and writes numbers with a decimal comma ("1,5"),
+// and "." then groups thousands in sums.
+package app
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+var (
+	errCalcDivisionByZero = errors.New("division by zero")
+	errCalcOverflow       = errors.New("result is too large")
+)
+
+// calcMaxDepth bounds parenthesis nesting so a pathological selection cannot
+// exhaust the stack.
+const calcMaxDepth = 64
+
+// calcSuffixes are the multipliers a number may end with.
+var calcSuffixes = map[rune]float64{'k': 1e3, 'K': 1e3, 'M': 1e6}
+
+// evalCalcExpression evaluates an arithmetic expression. With decimalComma
+// the decimal separator is "," instead of ".".
+func evalCalcExpression(expr string, decimalComma bool) (float64, error) {
+	p := &calcParser{src: []rune(expr), decimalComma: decimalComma}
+	p.skipSpace()
+	if p.pos == len(p.src) {
+		return 0, errors.New("empty expression")
+	}
+	v, err := p.expr(0)
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return 0, fmt.Errorf("unexpected %q", p.src[p.pos])
+	}
+	return v, nil
+}
+
+// calcParser is the evaluator's state: the input and the read position.
+type calcParser struct {
+	src          []rune
+	pos          int
+	decimalComma bool
+}
+
+func (p *calcParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space rune, or 0 at the end.
+func (p *calcParser) peek() rune {
+	p.skipSpace()
+	if p.pos == len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// expr parses a sum: term (("+" | "-") term)*.
+func (p *calcParser) expr(depth int) (float64, error) {
+	v, err := p.term(depth)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return v, nil
+		}
+		p.pos++
+		rhs, err := p.term(depth)
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			v += rhs
+		} else {
+			v -= rhs
+		}
+		if err := calcCheck(v); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// term parses a product: unary (("*" | "/" | "%") unary)*.
+func (p *calcParser) term(depth int) (float64, error) {
+	v, err := p.unary(depth)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' && op != '%' {
+			return v, nil
+		}
+		p.pos++
+		rhs, err := p.unary(depth)
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			v *= rhs
+		case '/':
+			if rhs == 0 {
+				return 0, errCalcDivisionByZero
+			}
+			v /= rhs
+		case '%':
+			if rhs == 0 {
+				return 0, errCalcDivisionByZero
+			}
+			v = math.Mod(v, rhs)
+		}
+		if err := calcCheck(v); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// unary parses a signed operand: ("+" | "-") unary | primary.
+func (p *calcParser) unary(depth int) (float64, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		v, err := p.unary(depth)
+		return -v, err
+	case '+':
+		p.pos++
+		return p.unary(depth)
+	}
+	return p.primary(depth)
+}
+
+// primary parses a number or a parenthesized expression.
+func (p *calcParser) primary(depth int) (float64, error) {
+	r := p.peek()
+	if r == '(' {
+		if depth >= calcMaxDepth {
+			return 0, errors.New("too many nested parentheses")
+		}
+		p.pos++
+		v, err := p.expr(depth + 1)
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, errors.New("missing )")
+		}
+		p.pos++
+		return v, nil
+	}
+	if r == 0 {
+		return 0, errors.New("expression ends early")
+	}
+	return p.number()
+}
+
+// number parses digits with an optional fraction and k/M suffix.
+func (p *calcParser) number() (float64, error) {
+	sep := '.'
+	if p.decimalComma {
+		sep = ','
+	}
+	start := p.pos
+	digits := 0
+	for p.pos < len(p.src) && (isASCIIDigit(p.src[p.pos]) || p.src[p.pos] == sep) {
+		if p.src[p.pos] == sep && strings.ContainsRune(string(p.src[start:p.pos]), sep) {
+			break
+		}
+		if p.src[p.pos] != sep {
+			digits++
+		}
+		p.pos++
+	}
+	if digits == 0 {
+		if p.pos < len(p.src) {
+			return 0, fmt.Errorf("unexpected %q", p.src[start])
+		}
+		return 0, errors.New("expression ends early")
+	}
+	literal := strings.Replace(string(p.src[start:p.pos]), string(sep), ".", 1)
+	v, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return 0, errCalcOverflow
+	}
+	if p.pos < len(p.src) {
+		if mult, ok := calcSuffixes[p.src[p.pos]]; ok {
+			p.pos++
+			v *= mult
+		}
+	}
+	return v, calcCheck(v)
+}
+
+// calcCheck reports an intermediate result that no longer fits a float64.
+func calcCheck(v float64) error {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return errCalcOverflow
+	}
+	return nil
+}
+
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// formatCalcNumber renders a result without float noise: whole numbers
+// without a fraction, others rounded to ten decimals with trailing zeros
+// dropped.
+func formatCalcNumber(v float64, decimalComma bool) string {
+	if v == 0 {
+		v = 0 // no "-0"
+	}
+	var s string
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		s = strconv.FormatFloat(v, 'f', 0, 64)
+	} else {
+		s = strings.TrimRight(strconv.FormatFloat(v, 'f', 10, 64), "0")
+		s = strings.TrimSuffix(s, ".")
+		if s == "-0" {
+			s = "0"
+		}
+	}
+	if decimalComma {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
+
+// calcExpressionBefore finds the expression that ends at the end of line: the
+// earliest start from which the text evaluates. A trailing "=" (as in
+// "2*3 =") is allowed and reported by hasEquals. A math error from a
+// complete expression is returned rather than trying shorter ones.
+func calcExpressionBefore(line []rune, decimalComma bool) (start, end int, value float64, hasEquals bool, err error) {
+	end = len(line)
+	for end > 0 && unicode.IsSpace(line[end-1]) {
+		end--
+	}
+	if end > 0 && line[end-1] == '=' {
+		hasEquals = true
+		end--
+		for end > 0 && unicode.IsSpace(line[end-1]) {
+			end--
+		}
+	}
+	first := end
+	for first > 0 && isCalcRune(line[first-1], decimalComma) {
+		first--
+	}
+	for start = first; start < end; start++ {
+		if unicode.IsSpace(line[start]) || (start > 0 && !isCalcBoundary(line[start-1])) {
+			continue
+		}
+		expr := string(line[start:end])
+		if !strings.ContainsFunc(expr, isASCIIDigit) {
+			break
+		}
+		value, err = evalCalcExpression(expr, decimalComma)
+		if err == nil || errors.Is(err, errCalcDivisionByZero) || errors.Is(err, errCalcOverflow) {
+			return start, end, value, hasEquals, err
+		}
+	}
+	return 0, 0, 0, false, errors.New("no expression before the cursor")
+}
+
+// isCalcRune reports whether r can appear in an expression.
+func isCalcRune(r rune, decimalComma bool) bool {
+	if isASCIIDigit(r) || unicode.IsSpace(r) || strings.ContainsRune("+-*/%().", r) {
+		return r != '.' || !decimalComma
+	}
+	if r == ',' {
+		return decimalComma
+	}
+	_, ok := calcSuffixes[r]
+	return ok
+}
+
+// isCalcBoundary reports whether an expression may start right after r, so
+// that "x2+3" does not evaluate as "2+3".
+func isCalcBoundary(r rune) bool {
+	return !unicode.IsLetter(r) && !isASCIIDigit(r) && r != '.' && r != ','
+}
+
+// calcNumberPatterns find a number (with optional thousands grouping and
+// k/M suffix) in a line being summed, for decimal points and decimal commas.
+var (
+	calcNumberPattern      = regexp.MustCompile(`(?:^|[^\w.,])([-+]?(?:\d{1,3}(?:,\d{3})+(?:\.\d+)?|\d+(?:\.\d+)?)[kKM]?)\b`)
+	calcNumberPatternComma = regexp.MustCompile(`(?:^|[^\w.,])([-+]?(?:\d{1,3}(?:\.\d{3})+(?:,\d+)?|\d+(?:,\d+)?)[kKM]?)\b`)
+)
+
+// sumCalcColumn adds the last number of every line in text and returns the
+// total and how many lines had a number.
+func sumCalcColumn(text string, decimalComma bool) (float64, int, error) {
+	pattern, group := calcNumberPattern, ","
+	if decimalComma {
+		pattern, group = calcNumberPatternComma, "."
+	}
+	total := 0.0
+	count := 0
+	for _, line := range strings.Split(text, "\n") {
+		matches := pattern.FindAllStringSubmatch(line, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		literal := strings.ReplaceAll(matches[len(matches)-1][1], group, "")
+		v, err := evalCalcExpression(literal, decimalComma)
+		if err != nil {
+			return 0, 0, err
+		}
+		total += v
+		if err := calcCheck(total); err != nil {
+			return 0, 0, err
+		}
+		count++
+	}
+	return total, count, nil
+}
+
+// evaluateCalcAtCursor implements Alt+=: it evaluates the selection, or the
+// expression before the cursor, and writes the result.
+func (m *Model) evaluateCalcAtCursor() {
+	runes := []rune(m.editor.Value())
+	var start, end int
+	var value float64
+	var hasEquals bool
+	var err error
+	if selStart, selEnd, ok := m.editorSelectionRange(); ok {
+		start, end, value, hasEquals, err = calcExpressionSelection(runes[selStart:selEnd], m.calcDecimalComma)
+		start, end = selStart+start, selStart+end
+	} else {
+		cursor := m.currentEditorCursorOffset()
+		lineStart, _ := lineBoundsAtOffset(runes, cursor)
+		start, end, value, hasEquals, err = calcExpressionBefore(runes[lineStart:cursor], m.calcDecimalComma)
+		start, end = lineStart+start, lineStart+end
+	}
+	if err != nil {
+		m.status = "Calc error: " + err.Error()
+		return
+	}
+
+	expr := strings.TrimSpace(string(runes[start:end]))
+	result := formatCalcNumber(value, m.calcDecimalComma)
+	if m.calcResult == config.CalcResultReplace {
+		m.replaceEditorRange(start, end, result)
+	} else {
+		insert := " = " + result
+		if hasEquals {
+			// Keep the "=" the user typed and write the result after it.
+			for end < len(runes) && runes[end] != '=' {
+				end++
+			}
+			end++
+			insert = " " + result
+			if end < len(runes) && runes[end] == ' ' {
+				end++
+				insert = result
+			}
+		}
+		m.replaceEditorRange(end, end, insert)
+	}
+	m.clearEditorSelection()
+	m.status = "Calculated " + expr + " = " + result
+}
+
+// calcExpressionSelection evaluates selected text, allowing surrounding
+// space and a trailing "=". start and end bound the expression in text.
+func calcExpressionSelection(text []rune, decimalComma bool) (start, end int, value float64, hasEquals bool, err error) {
+	end = len(text)
+	for end > 0 && unicode.IsSpace(text[end-1]) {
+		end--
+	}
+	if end > 0 && text[end-1] == '=' {
+		hasEquals = true
+		end--
+	}
+	for start < end && unicode.IsSpace(text[start]) {
+		start++
+	}
+	for end > start && unicode.IsSpace(text[end-1]) {
+		end--
+	}
+	value, err = evalCalcExpression(string(text[start:end]), decimalComma)
+	return start, end, value, hasEquals, err
+}
+
+// sumCalcSelection implements Alt++: it totals the last number of each
+// selected line and writes the sum.
+func (m *Model) sumCalcSelection() {
+	start, end, ok := m.editorSelectionRange()
+	if !ok {
+		m.status = "Select lines of numbers to sum"
+		return
+	}
+	runes := []rune(m.editor.Value())
+	total, count, err := sumCalcColumn(string(runes[start:end]), m.calcDecimalComma)
+	if err != nil {
+		m.status = "Calc error: " + err.Error()
+		return
+	}
+	if count == 0 {
+		m.status = "No numbers in the selection"
+		return
+	}
+	result := formatCalcNumber(total, m.calcDecimalComma)
+	if m.calcResult == config.CalcResultReplace {
+		m.replaceEditorRange(start, end, result)
+	} else {
+		_, lineEnd := lineBoundsAtOffset(runes, max(start, end-1))
+		m.replaceEditorRange(lineEnd, lineEnd, "\n= "+result)
+	}
+	m.clearEditorSelection()
+	m.status = fmt.Sprintf("Sum of %d numbers = %s", count, result)
+}
+
+// replaceEditorRange replaces the runes in [start, end) with text and puts
+// the cursor after it.
+func (m *Model) replaceEditorRange(start, end int, text string) {
+	runes := []rune(m.editor.Value())
+	insert := []rune(text)
+	updated := make([]rune, 0, len(runes)-(end-start)+len(insert))
+	updated = append(updated, runes[:start]...)
+	updated = append(updated, insert...)
+	updated = append(updated, runes[end:]...)
+	m.setEditorValueAndCursorOffset(string(updated), start+len(insert))
+}
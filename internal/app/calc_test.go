@@ -0,0 +1,177 @@
+package app
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func altKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: true}
+}
+
+func TestEvalCalcExpression(t *testing.T) {
+	tests := []struct {
+		expr  string
+		comma bool
+		want  float64
+	}{
+		{"1 + 2 * 3", false, 7},
+		{"(1 + 2) * 3", false, 9},
+		{"10 - 4 - 3", false, 3},
+		{"2 * -3", false, -6},
+		{"-(2 + 3)", false, -5},
+		{"7 % 4", false, 3},
+		{"1.5k + 2M", false, 2001500},
+		{"1,5 * 2", true, 3},
+		{".5 + 5.", false, 5.5},
+		{"120 / 8 / 3", false, 5},
+	}
+	for _, tt := range tests {
+		got, err := evalCalcExpression(tt.expr, tt.comma)
+		if err != nil || got != tt.want {
+			t.Errorf("evalCalcExpression(%q) = %v, %v; want %v", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestEvalCalcExpressionErrors(t *testing.T) {
+	for expr, want := range map[string]string{
+		"":           "empty expression",
+		"1 +":        "expression ends early",
+		"(1 + 2":     "missing )",
+		"2 x 3":      `unexpected 'x'`,
+		"1.5":        `unexpected '.'`, // with a decimal comma
+		"os.Exit(1)": `unexpected 'o'`,
+		"1.2.3":      `unexpected '.'`,
+	} {
+		comma := expr == "1.5"
+		if _, err := evalCalcExpression(expr, comma); err == nil || err.Error() != want {
+			t.Errorf("evalCalcExpression(%q) error = %v, want %q", expr, err, want)
+		}
+	}
+	if _, err := evalCalcExpression("5 / (2 - 2)", false); !errors.Is(err, errCalcDivisionByZero) {
+		t.Errorf("expected division by zero, got %v", err)
+	}
+	if _, err := evalCalcExpression("5 % 0", false); !errors.Is(err, errCalcDivisionByZero) {
+		t.Errorf("expected division by zero for %%, got %v", err)
+	}
+	if _, err := evalCalcExpression("1"+strings.Repeat("0", 308)+" * 10", false); !errors.Is(err, errCalcOverflow) {
+		t.Errorf("expected overflow, got %v", err)
+	}
+	if _, err := evalCalcExpression(strings.Repeat("(", 100)+"1"+strings.Repeat(")", 100), false); err == nil {
+		t.Error("expected deep nesting to be refused")
+	}
+}
+
+func TestFormatCalcNumber(t *testing.T) {
+	for _, tt := range []struct {
+		v     float64
+		comma bool
+		want  string
+	}{
+		{36, false, "36"},
+		{0.1 + 0.2, false, "0.3"},
+		{-2.5, true, "-2,5"},
+		{math.Copysign(0, -1), false, "0"},
+		{1.0 / 3, false, "0.3333333333"},
+	} {
+		if got := formatCalcNumber(tt.v, tt.comma); got != tt.want {
+			t.Errorf("formatCalcNumber(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestCalcEvaluatesExpressionBeforeCursor(t *testing.T) {
+	for _, tt := range []struct{ line, want string }{
+		{"Budget: 12*3", "Budget: 12*3 = 36"},
+		{"work 5+5", "work 5+5 = 10"},
+		{"id42 7*6", "id42 7*6 = 42"},
+		{"hours (2+1.5)*2 =", "hours (2+1.5)*2 = 7"},
+	} {
+		m := newFocusedEditModel(tt.line)
+		m.handleEditNoteKey(altKey('='))
+		if got := m.editor.Value(); got != tt.want {
+			t.Errorf("Alt+= on %q gave %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	m := newFocusedEditModel("note\ncost 10/0")
+	m.handleEditNoteKey(altKey('='))
+	if m.editor.Value() != "note\ncost 10/0" || m.status != "Calc error: division by zero" {
+		t.Fatalf("expected no change and an error, got %q / %q", m.editor.Value(), m.status)
+	}
+	m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	if m.editor.Value() != "note\ncost 10/0" {
+		t.Fatalf("expected nothing to undo, got %q", m.editor.Value())
+	}
+
+	m = newFocusedEditModel("just words")
+	m.handleEditNoteKey(altKey('='))
+	if m.editor.Value() != "just words" || m.status != "Calc error: no expression before the cursor" {
+		t.Fatalf("expected no change, got %q / %q", m.editor.Value(), m.status)
+	}
+}
+
+func TestCalcEvaluatesSelection(t *testing.T) {
+	value := "total 2k + 500 in euros"
+	m := newFocusedEditModel(value)
+	selectEditorRange(m, value, 6, 14)
+	m.handleEditNoteKey(altKey('='))
+	if got := m.editor.Value(); got != "total 2k + 500 = 2500 in euros" {
+		t.Fatalf("unexpected append result %q", got)
+	}
+
+	m = newFocusedEditModel(value)
+	m.calcResult = config.CalcResultReplace
+	selectEditorRange(m, value, 6, 14)
+	m.handleEditNoteKey(altKey('='))
+	if got := m.editor.Value(); got != "total 2500 in euros" || m.status != "Calculated 2k + 500 = 2500" {
+		t.Fatalf("unexpected replace result %q / %q", got, m.status)
+	}
+	m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	if got := m.editor.Value(); got != value {
+		t.Fatalf("expected undo to restore the expression, got %q", got)
+	}
+
+	value = "rate 2,5 * 4"
+	m = newFocusedEditModel(value)
+	m.calcDecimalComma = true
+	m.handleEditNoteKey(altKey('='))
+	if got := m.editor.Value(); got != "rate 2,5 * 4 = 10" {
+		t.Fatalf("unexpected decimal-comma result %q", got)
+	}
+}
+
+func TestCalcSumsSelectedColumn(t *testing.T) {
+	value := "# Budget\n- Rent: 1,200\n- Food 350.50\nno number here\n- Misc -50\nafter"
+	start := strings.Index(value, "- Rent")
+	end := strings.Index(value, "\nafter")
+	m := newFocusedEditModel(value)
+	selectEditorRange(m, value, start, end)
+	m.handleEditNoteKey(altKey('+'))
+	want := "# Budget\n- Rent: 1,200\n- Food 350.50\nno number here\n- Misc -50\n= 1500.5\nafter"
+	if got := m.editor.Value(); got != want || m.status != "Sum of 3 numbers = 1500.5" {
+		t.Fatalf("unexpected sum.\nwant: %q\ngot:  %q (%s)", want, got, m.status)
+	}
+
+	value = "a 1.000,5\nb 2k"
+	m = newFocusedEditModel(value)
+	m.calcDecimalComma = true
+	m.calcResult = config.CalcResultReplace
+	selectEditorRange(m, value, 0, len(value))
+	m.handleEditNoteKey(altKey('+'))
+	if got := m.editor.Value(); got != "3000,5" {
+		t.Fatalf("unexpected decimal-comma sum %q", got)
+	}
+
+	m = newFocusedEditModel("1\n2")
+	m.handleEditNoteKey(altKey('+'))
+	if m.editor.Value() != "1\n2" || m.status != "Select lines of numbers to sum" {
+		t.Fatalf("expected a selection to be required, got %q / %q", m.editor.Value(), m.status)
+	}
+}
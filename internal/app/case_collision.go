@@ -0,0 +1,148 @@
+// case_collision.go guards against note and folder names that differ only by
+// case, such as "README.md" and "Readme.md".
+//
+// On case-insensitive filesystems (APFS and NTFS by default) the two names
+// refer to the same file, so creating one silently overwrites the other. On
+// Linux both files coexist, and the workspace then breaks when it is synced
+// to such a machine. Creating, renaming, moving (including archiving, inbox
+// moves, and folder toggles through relocatePath), duplicating, converting
+// inbox lines, and restoring from the trash therefore refuse any name that matches an existing sibling case-insensitively, on
+// every platform.
+//
+// The filesystem's behavior is probed once per workspace (caseInsensitiveFS)
+// so that a case-only rename of an item ("readme.md" → "README.md") is not
+// mistaken for a collision with itself, and `notes doctor` lists pairs that
+// already exist.
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// caseCollisionPair is two sibling paths whose names differ only by case,
+// relative to the notes directory.
+type caseCollisionPair struct {
+	first  string
+	second string
+}
+
+// probeCaseInsensitive reports whether the filesystem holding dir resolves
+// names case-insensitively. It creates a temporary lower-case file in dir and
+// checks whether its upper-case spelling refers to the same file.
+func probeCaseInsensitive(dir string) (bool, error) {
+	f, err := os.CreateTemp(dir, ".cli-notes-case-probe-*")
+	if err != nil {
+		return false, err
+	}
+	name := f.Name()
+	_ = f.Close()
+	defer os.Remove(name)
+
+	lowerInfo, err := os.Stat(name)
+	if err != nil {
+		return false, err
+	}
+	upperInfo, err := os.Stat(filepath.Join(dir, strings.ToUpper(filepath.Base(name))))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(lowerInfo, upperInfo), nil
+}
+
+// detectFilesystemCase probes the current notes directory. Probe failures
+// (e.g. a read-only workspace) are logged and treated as case-sensitive,
+// which only makes case-only renames stricter.
+func (m *Model) detectFilesystemCase() {
+	insensitive, err := probeCaseInsensitive(m.notesDir)
+	if err != nil {
+		appLog.Warn("probe filesystem case sensitivity", "path", m.notesDir, "error", err)
+	}
+	m.caseInsensitiveFS = insensitive
+}
+
+// caseCollision returns the name of an existing sibling of path whose name
+// differs from path's base name only by case, or "" if there is none. self is
+// the item being renamed or moved and is never reported; pass "" when
+// creating.
+func caseCollision(path, self string) string {
+	dir := filepath.Dir(path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	base := filepath.Base(path)
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == base || !strings.EqualFold(name, base) {
+			continue
+		}
+		if self != "" && filepath.Join(dir, name) == self {
+			continue
+		}
+		return name
+	}
+	return ""
+}
+
+// firstCaseCollision checks paths in order and returns the refusal status for
+// the first one that collides by case.
+func firstCaseCollision(self string, paths ...string) (string, bool) {
+	for _, path := range paths {
+		if name := caseCollision(path, self); name != "" {
+			return fmt.Sprintf("'%s' already exists (names differ only by case)", name), true
+		}
+	}
+	return "", false
+}
+
+// findCaseCollisions walks root and returns every pair of siblings whose
+// names differ only by case, sorted by path. The managed .cli-notes folder is
+// skipped.
+func findCaseCollisions(root string) ([]caseCollisionPair, error) {
+	var pairs []caseCollisionPair
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && shouldSkipManagedPath(d.Name()) {
+			return filepath.SkipDir
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		seen := map[string]string{}
+		for _, entry := range entries {
+			folded := strings.ToLower(entry.Name())
+			if first, ok := seen[folded]; ok {
+				rel, _ := filepath.Rel(root, path)
+				pairs = append(pairs, caseCollisionPair{
+					first:  filepath.Join(rel, first),
+					second: filepath.Join(rel, entry.Name()),
+				})
+				continue
+			}
+			seen[folded] = entry.Name()
+		}
+		return nil
+	})
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].first != pairs[j].first {
+			return pairs[i].first < pairs[j].first
+		}
+		return pairs[i].second < pairs[j].second
+	})
+	return pairs, err
+}
@@ -0,0 +1,286 @@
+package app
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tempDirIsCaseInsensitive answers the probe's question independently, so
+// tests can assert the right behavior on whichever filesystem runs them.
+func tempDirIsCaseInsensitive(t *testing.T) bool {
+	t.Helper()
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, "probe.txt"), "x")
+	_, err := os.Stat(filepath.Join(dir, "PROBE.TXT"))
+	return err == nil
+}
+
+func TestProbeCaseInsensitiveMatchesFilesystem(t *testing.T) {
+	dir := t.TempDir()
+	got, err := probeCaseInsensitive(dir)
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if want := tempDirIsCaseInsensitive(t); got != want {
+		t.Fatalf("probe reported case-insensitive=%v, filesystem is %v", got, want)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Fatalf("expected probe file to be removed, found %d entries", len(entries))
+	}
+}
+
+func TestCaseCollisionFindsSiblingDifferingOnlyByCase(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "README.md")
+	mustWriteFile(t, existing, "keep\n")
+
+	if got := caseCollision(filepath.Join(root, "Readme.md"), ""); got != "README.md" {
+		t.Fatalf("expected collision with README.md, got %q", got)
+	}
+	if got := caseCollision(existing, ""); got != "" {
+		t.Fatalf("exact name is not a case collision, got %q", got)
+	}
+	if got := caseCollision(filepath.Join(root, "Readme.md"), existing); got != "" {
+		t.Fatalf("expected the renamed item itself to be ignored, got %q", got)
+	}
+}
+
+func TestSaveNewNoteRefusesCaseCollision(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "README.md")
+	mustWriteFile(t, existing, "keep\n")
+
+	m := newTestCRUDModel(root)
+	m.newParent = root
+	m.input.SetValue("Readme")
+	_, _ = settlePendingOp(m.saveNewNote())
+
+	if want := "'README.md' already exists (names differ only by case)"; m.status != want {
+		t.Fatalf("expected %q, got %q", want, m.status)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "keep\n" {
+		t.Fatalf("expected existing note untouched, got %q", data)
+	}
+}
+
+func TestSaveNewNoteRefusesCaseCollisionInCreatedFolder(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "projects", "a.md"), "a\n")
+
+	m := newTestCRUDModel(root)
+	m.createMissingDirs = true
+	m.newParent = root
+	m.input.SetValue("Projects/new")
+	_, _ = settlePendingOp(m.saveNewNote())
+
+	if tempDirIsCaseInsensitive(t) {
+		// "Projects" resolves to the existing folder, so the note lands there.
+		if _, err := os.Stat(filepath.Join(root, "projects", "new.md")); err != nil {
+			t.Fatalf("expected note inside the existing folder: %v", err)
+		}
+		return
+	}
+	if !strings.Contains(m.status, "'projects' already exists") {
+		t.Fatalf("expected folder case collision, got %q", m.status)
+	}
+}
+
+func TestSaveRenameItemAllowsCaseOnlyRename(t *testing.T) {
+	root := t.TempDir()
+	oldPath := filepath.Join(root, "readme.md")
+	mustWriteFile(t, oldPath, "hello\n")
+
+	m := newTestCRUDModel(root)
+	m.detectFilesystemCase()
+	m.mode = modeRenameItem
+	m.actionPath = oldPath
+	m.input.SetValue("README.md")
+	_, _ = settlePendingOp(m.saveRenameItem())
+
+	if m.mode != modeBrowse {
+		t.Fatalf("expected case-only rename to succeed, got status %q", m.status)
+	}
+	var names []string
+	entries, _ := os.ReadDir(root)
+	for _, entry := range entries {
+		if !shouldSkipManagedPath(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) != 1 || names[0] != "README.md" {
+		t.Fatalf("expected only README.md, got %v", names)
+	}
+}
+
+func TestSaveRenameItemRefusesCaseCollisionWithSibling(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "README.md"), "keep\n")
+	oldPath := filepath.Join(root, "notes.md")
+	mustWriteFile(t, oldPath, "other\n")
+
+	m := newTestCRUDModel(root)
+	m.detectFilesystemCase()
+	m.mode = modeRenameItem
+	m.actionPath = oldPath
+	m.input.SetValue("Readme.md")
+	_, _ = settlePendingOp(m.saveRenameItem())
+
+	if m.mode != modeRenameItem {
+		t.Fatalf("expected rename to be refused, got status %q", m.status)
+	}
+	if _, err := os.Stat(oldPath); err != nil {
+		t.Fatalf("expected source to stay in place: %v", err)
+	}
+}
+
+func TestArchiveRefusesCaseCollisionInArchive(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, ArchiveDirName, "Projects", "old.md"), "old\n")
+	projects := filepath.Join(root, "projects")
+	note := filepath.Join(projects, "x.md")
+	mustWriteFile(t, note, "# X\n")
+
+	m := newTestCRUDModel(root)
+	m.expanded[projects] = true
+	selectTreePath(t, m, note)
+	m.toggleArchiveSelected()
+
+	if _, err := os.Stat(note); err != nil {
+		t.Fatalf("expected note to stay in place, got status %q: %v", m.status, err)
+	}
+	if !tempDirIsCaseInsensitive(t) && !strings.Contains(m.status, "differ only by case") {
+		t.Fatalf("expected case collision status, got %q", m.status)
+	}
+}
+
+func TestDuplicateRefusesCaseCollision(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "plan.md")
+	mustWriteFile(t, note, "# Plan\n")
+	mustWriteFile(t, filepath.Join(root, "Taken.md"), "")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeDuplicateItem
+	m.actionPath = note
+	m.input.SetValue("taken")
+	m.saveDuplicateItem()
+
+	if m.mode != modeDuplicateItem {
+		t.Fatalf("expected duplicate to be refused, got status %q", m.status)
+	}
+	entries, _ := os.ReadDir(root)
+	for _, entry := range entries {
+		if entry.Name() == "taken.md" {
+			t.Fatal("expected no copy to be written")
+		}
+	}
+}
+
+func TestInboxConvertRefusesCaseCollision(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "Plan.md")
+	mustWriteFile(t, existing, "# Plan\n")
+	capture := filepath.Join(root, DefaultInboxDir, InboxFileName)
+	mustWriteFile(t, capture, "- new plan\n")
+
+	m := newTestCRUDModel(root)
+	m.startInbox()
+	m.input.SetValue("plan")
+	m.handleInboxKey(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.mode != modeInbox || m.inboxIndex != 0 {
+		t.Fatalf("expected to stay on the line, got mode %v index %d (status %q)", m.mode, m.inboxIndex, m.status)
+	}
+	if body, _ := os.ReadFile(existing); string(body) != "# Plan\n" {
+		t.Fatalf("expected existing note untouched, got %q", body)
+	}
+	if captured, _ := os.ReadFile(capture); string(captured) != "- new plan\n" {
+		t.Fatalf("expected line left unprocessed, got %q", captured)
+	}
+	if !tempDirIsCaseInsensitive(t) && !strings.Contains(m.status, "differ only by case") {
+		t.Fatalf("expected case collision status, got %q", m.status)
+	}
+}
+
+func TestRestoreFromTrashRefusesCaseCollision(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "plan.md")
+	mustWriteFile(t, note, "# old\n")
+	m := newTestCRUDModel(root)
+	if err := m.moveToTrash(note); err != nil {
+		t.Fatalf("trash: %v", err)
+	}
+	existing := filepath.Join(root, "Plan.md")
+	mustWriteFile(t, existing, "# new\n")
+
+	entries := m.listTrash()
+	if len(entries) != 1 {
+		t.Fatalf("expected one trash entry, got %d", len(entries))
+	}
+	if m.restoreFromTrash(entries[0]) {
+		t.Fatal("expected restore over a case-only clash to be refused")
+	}
+	if body, _ := os.ReadFile(existing); string(body) != "# new\n" {
+		t.Fatalf("expected existing note untouched, got %q", body)
+	}
+	if _, err := os.Stat(entries[0].path); err != nil {
+		t.Fatalf("expected item to stay in the trash: %v", err)
+	}
+	if !tempDirIsCaseInsensitive(t) && !strings.Contains(m.status, "differ only by case") {
+		t.Fatalf("expected case collision status, got %q", m.status)
+	}
+}
+
+func TestFindCaseCollisionsAndDoctorReport(t *testing.T) {
+	if tempDirIsCaseInsensitive(t) {
+		t.Skip("case-colliding siblings cannot coexist on this filesystem")
+	}
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "README.md"), "a\n")
+	mustWriteFile(t, filepath.Join(root, "Readme.md"), "b\n")
+	mustWriteFile(t, filepath.Join(root, "work", "Plan.md"), "c\n")
+	mustWriteFile(t, filepath.Join(root, "work", "plan.md"), "d\n")
+	mustWriteFile(t, filepath.Join(root, managedNotesDirName, "X.md"), "e\n")
+	mustWriteFile(t, filepath.Join(root, managedNotesDirName, "x.md"), "f\n")
+
+	pairs, err := findCaseCollisions(root)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	want := []caseCollisionPair{
+		{first: "README.md", second: "Readme.md"},
+		{first: filepath.Join("work", "Plan.md"), second: filepath.Join("work", "plan.md")},
+	}
+	if len(pairs) != len(want) || pairs[0] != want[0] || pairs[1] != want[1] {
+		t.Fatalf("expected %v, got %v", want, pairs)
+	}
+
+	var out bytes.Buffer
+	problems, err := doctorReport(&out, root, "")
+	if err != nil || problems != 2 {
+		t.Fatalf("expected 2 problems, got %d (err %v)", problems, err)
+	}
+	if !strings.Contains(out.String(), "README.md  <->  Readme.md") {
+		t.Fatalf("expected pair in report:\n%s", out.String())
+	}
+}
+
+func TestDoctorReportClean(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "a\n")
+
+	var out bytes.Buffer
+	problems, err := doctorReport(&out, root, "")
+	if err != nil || problems != 0 {
+		t.Fatalf("expected clean report, got %d problems (err %v)", problems, err)
+	}
+	if !strings.Contains(out.String(), "OK: no names differ only by case") {
+		t.Fatalf("unexpected report:\n%s", out.String())
+	}
+}
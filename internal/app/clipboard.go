@@ -2,10 +2,22 @@ package app
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/atotto/clipboard"
 )
 
+// writeClipboard puts text on the system clipboard. Tests replace it to
+// avoid touching the real clipboard.
+var writeClipboard = clipboard.WriteAll
+
+// readClipboard reads text from the system clipboard. Tests replace it
+// alongside writeClipboard.
+var readClipboard = clipboard.ReadAll
+
+// killRingSize caps the editor's internal kill ring.
+const killRingSize = 10
+
 // copyCurrentNoteContentToClipboard copies the raw text content of the
 // currently displayed note to the system clipboard.
 //
@@ -21,7 +33,7 @@ func (m *Model) copyCurrentNoteContentToClipboard() {
 		m.status = "No note content to copy"
 		return
 	}
-	if err := clipboard.WriteAll(content); err != nil {
+	if err := writeClipboard(content); err != nil {
 		m.setStatusError("Clipboard copy failed", err)
 		return
 	}
@@ -41,7 +53,7 @@ func (m *Model) copyCurrentNotePathToClipboard() {
 		m.status = "No note selected"
 		return
 	}
-	if err := clipboard.WriteAll(m.currentFile); err != nil {
+	if err := writeClipboard(m.currentFile); err != nil {
 		m.setStatusError("Clipboard copy failed", err)
 		return
 	}
@@ -49,17 +61,24 @@ func (m *Model) copyCurrentNotePathToClipboard() {
 }
 
 // pasteFromClipboardIntoEditor reads text from the system clipboard and
-// inserts it at the current cursor position in the editor textarea.
+// inserts it at the current cursor position in the editor textarea, or in
+// place of the selection when one is active.
 //
 // This function is only active in edit mode (modeEditNote). It clears any
 // active editor selection after pasting so the cursor moves to the end of
-// the inserted text. If the clipboard is empty or unreadable, the status
-// bar is updated with an appropriate message.
+// the inserted text. When the system clipboard is unreadable or empty, the
+// newest kill-ring entry is pasted instead, so copy/cut/paste round-trips
+// inside the app without a clipboard provider (e.g. over SSH).
 func (m *Model) pasteFromClipboardIntoEditor() {
 	if m.mode != modeEditNote {
 		return
 	}
-	value, err := clipboard.ReadAll()
+	value, err := readClipboard()
+	source := "clipboard"
+	if (err != nil || value == "") && len(m.killRing) > 0 {
+		value, err = m.killRing[len(m.killRing)-1], nil
+		source = "internal clipboard"
+	}
 	if err != nil {
 		m.setStatusError("Clipboard paste failed", err)
 		return
@@ -68,7 +87,61 @@ func (m *Model) pasteFromClipboardIntoEditor() {
 		m.status = "Clipboard is empty"
 		return
 	}
-	m.editor.InsertString(value)
+	if start, end, ok := m.editorSelectionRange(); ok {
+		runes := []rune(m.editor.Value())
+		updated := string(runes[:start]) + value + string(runes[end:])
+		m.setEditorValueAndCursorOffset(updated, start+len([]rune(value)))
+	} else {
+		m.editor.InsertString(value)
+	}
+	m.clearEditorSelection()
+	m.status = "Pasted from " + source
+}
+
+// copyEditorSelection copies the selected editor text to the clipboard and
+// the kill ring. With cut set the range is removed as well, leaving the
+// cursor where it started. It reports whether there was a selection.
+func (m *Model) copyEditorSelection(cut bool) bool {
+	start, end, ok := m.editorSelectionRange()
+	if !ok {
+		m.status = "No selection (Shift+Arrows or Alt+S to select)"
+		return false
+	}
+	runes := []rune(m.editor.Value())
+	text := string(runes[start:end])
+	m.pushKillRing(text)
+	clipErr := writeClipboard(text)
+	if cut {
+		m.setEditorValueAndCursorOffset(string(runes[:start])+string(runes[end:]), start)
+	}
 	m.clearEditorSelection()
-	m.status = "Pasted from clipboard"
+
+	verb := "Copied "
+	if cut {
+		verb = "Cut "
+	}
+	m.status = verb + selectionSizeLabel(text)
+	if clipErr != nil {
+		appLog.Warn("write clipboard", "error", clipErr)
+		m.status += " (system clipboard unavailable; kept for Ctrl+V in the app)"
+	}
+	return true
+}
+
+// pushKillRing records text as the newest kill-ring entry, dropping the
+// oldest beyond killRingSize.
+func (m *Model) pushKillRing(text string) {
+	m.killRing = append(m.killRing, text)
+	if len(m.killRing) > killRingSize {
+		m.killRing = m.killRing[len(m.killRing)-killRingSize:]
+	}
+}
+
+// selectionSizeLabel describes copied text for the status bar: a line count
+// for multi-line text, otherwise a character count.
+func selectionSizeLabel(text string) string {
+	if lines := strings.Count(strings.TrimSuffix(text, "\n"), "\n") + 1; lines > 1 {
+		return fmt.Sprintf("%d lines", lines)
+	}
+	return fmt.Sprintf("%d chars", len([]rune(text)))
 }
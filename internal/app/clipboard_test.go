@@ -0,0 +1,74 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func withFakeClipboardRead(t *testing.T, value string, err error) {
+	t.Helper()
+	prev := readClipboard
+	readClipboard = func() (string, error) { return value, err }
+	t.Cleanup(func() { readClipboard = prev })
+}
+
+func TestCopyEditorSelectionMultiLine(t *testing.T) {
+	copied := withFakeClipboard(t, nil)
+	m := newFocusedEditModel("")
+	m.editor.SetWidth(120)
+	value := "alpha\nbeta\ngamma\n"
+	selectEditorRange(m, value, 2, len("alpha\nbeta\nga"))
+
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if *copied != "pha\nbeta\nga" || m.status != "Copied 3 lines" {
+		t.Fatalf("copied %q status %q", *copied, m.status)
+	}
+	if m.editor.Value() != value || m.hasEditorSelectionAnchor() {
+		t.Fatalf("expected the text kept and the selection cleared, got %q", m.editor.Value())
+	}
+
+	selectEditorRange(m, value, 0, 5)
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c"), Alt: true})
+	if *copied != "alpha" || m.status != "Copied 5 chars" {
+		t.Fatalf("copied %q status %q", *copied, m.status)
+	}
+}
+
+func TestCutAndPasteRoundTripWithoutSystemClipboard(t *testing.T) {
+	withFakeClipboard(t, errors.New("no clipboard provider"))
+	withFakeClipboardRead(t, "", errors.New("no clipboard provider"))
+	m := newFocusedEditModel("")
+	m.editor.SetWidth(120)
+	selectEditorRange(m, "one two three\nfour\n", len("one "), len("one two three\nfo"))
+
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyCtrlX})
+	if m.editor.Value() != "one ur\n" || m.currentEditorCursorOffset() != len("one ") {
+		t.Fatalf("after cut: %q cursor %d", m.editor.Value(), m.currentEditorCursorOffset())
+	}
+	if m.status != "Cut 2 lines (system clipboard unavailable; kept for Ctrl+V in the app)" {
+		t.Fatalf("status %q", m.status)
+	}
+
+	// Pasting over a selection replaces it.
+	selectEditorRange(m, m.editor.Value(), len("one "), len("one ur"))
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyCtrlV})
+	if m.editor.Value() != "one two three\nfo\n" || m.status != "Pasted from internal clipboard" {
+		t.Fatalf("after paste: %q status %q", m.editor.Value(), m.status)
+	}
+
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	if m.editor.Value() != "one ur\n" {
+		t.Fatalf("expected undo to restore the pre-paste text, got %q", m.editor.Value())
+	}
+}
+
+func TestCopyWithoutSelectionReportsIt(t *testing.T) {
+	copied := withFakeClipboard(t, nil)
+	m := newFocusedEditModel("text")
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyCtrlX})
+	if *copied != "" || m.editor.Value() != "text" || len(m.killRing) != 0 {
+		t.Fatalf("expected nothing copied, clipboard %q value %q", *copied, m.editor.Value())
+	}
+}
@@ -0,0 +1,254 @@
+// command_palette.go implements the command palette (: by default), a
+// filterable list of every browse action with its description and the keys
+// currently bound to it, so remapped or rarely used actions stay reachable.
+//
+// Typed characters filter the list with a fuzzy match on the description,
+// the action name, and the keys (commandPaletteScore); the shared popup keys
+// move and select, except plain characters bound to navigation. Enter runs
+// the action through runBrowseAction, exactly as its key would. Actions that
+// cannot run in the current context (git actions outside a repository, note
+// actions with no note open) are listed dimmed and refuse to run.
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteEntry is one action listed in the command palette.
+type paletteEntry struct {
+	action      string
+	description string
+	keys        string
+	available   bool
+}
+
+// paletteNoteActions need an open note to do anything.
+var paletteNoteActions = map[string]bool{
+	actionEditNote:      true,
+	actionCopyContent:   true,
+	actionCopyPath:      true,
+	actionOutline:       true,
+	actionMetadata:      true,
+	actionNoteStats:     true,
+	actionWikiLinks:     true,
+	actionIssues:        true,
+	actionIssueNext:     true,
+	actionIssuePrev:     true,
+	actionHeadingCase:   true,
+	actionGitDiff:       true,
+	actionGitLog:        true,
+	actionMetadataStrip: true,
+}
+
+// openCommandPalette shows the command palette with every action listed.
+func (m *Model) openCommandPalette() {
+	m.openOverlay(overlayCommandPalette)
+	m.showHelp = false
+	m.paletteInput = textinput.New()
+	m.paletteInput.Prompt = "> "
+	m.paletteInput.Placeholder = "Type to filter actions"
+	m.paletteInput.CharLimit = InputCharLimit
+	m.paletteInput.Focus()
+	m.updateCommandPalette()
+	m.status = "Command palette: type to filter, Enter to run, Esc to cancel"
+}
+
+// handleCommandPaletteKey routes key presses while the palette is visible.
+// Plain characters go to the filter, so letters bound to popup navigation
+// (j, k, g) can still be typed; only a character remapped to popup.select or
+// popup.close keeps that meaning.
+func (m *Model) handleCommandPaletteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	typed := (msg.Type == tea.KeyRunes && !msg.Alt) || msg.Type == tea.KeySpace
+	if action := m.popupAction(msg); !typed || action == actionPopupSelect || action == actionPopupClose {
+		next, selectPressed, closePressed, handled := m.popupListKey(msg, m.paletteCursor, len(m.paletteEntries))
+		if handled {
+			if closePressed {
+				m.closeOverlay()
+				m.status = "Command palette closed"
+				return m, nil
+			}
+			m.paletteCursor = next
+			if selectPressed {
+				return m.runPaletteEntry()
+			}
+			return m, nil
+		}
+	}
+	before := m.paletteInput.Value()
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	if m.paletteInput.Value() != before {
+		m.updateCommandPalette()
+	}
+	return m, cmd
+}
+
+// runPaletteEntry closes the palette and runs the selected action.
+func (m *Model) runPaletteEntry() (tea.Model, tea.Cmd) {
+	if m.paletteCursor >= len(m.paletteEntries) {
+		return m, nil
+	}
+	entry := m.paletteEntries[m.paletteCursor]
+	if !entry.available {
+		m.status = entry.description + " is not available here"
+		return m, nil
+	}
+	m.closeOverlay()
+	m.status = ""
+	return m.runBrowseAction(entry.action)
+}
+
+// updateCommandPalette rebuilds the listed actions for the current filter,
+// best matches first.
+func (m *Model) updateCommandPalette() {
+	query := strings.TrimSpace(m.paletteInput.Value())
+	entries := m.commandPaletteEntries()
+	if query != "" {
+		scores := map[string]int{}
+		matched := entries[:0]
+		for _, entry := range entries {
+			score, ok := commandPaletteScore(query, entry)
+			if ok {
+				scores[entry.action] = score
+				matched = append(matched, entry)
+			}
+		}
+		entries = matched
+		sort.SliceStable(entries, func(i, j int) bool {
+			return scores[entries[i].action] < scores[entries[j].action]
+		})
+	}
+	m.paletteEntries = entries
+	m.paletteCursor = 0
+}
+
+// commandPaletteEntries lists every browse action in help-panel order
+// (actions without a help entry last, by name), with its current keys and
+// whether it can run now.
+func (m *Model) commandPaletteEntries() []paletteEntry {
+	seen := map[string]bool{}
+	var entries []paletteEntry
+	add := func(action, description string, available bool) {
+		if seen[action] || action == actionCommandPalette {
+			return
+		}
+		seen[action] = true
+		if description == "" {
+			description = action
+		}
+		if paletteNoteActions[action] && m.currentFile == "" {
+			available = false
+		}
+		entries = append(entries, paletteEntry{
+			action:      action,
+			description: description,
+			keys:        m.allActionKeys(action, ""),
+			available:   available,
+		})
+	}
+	for _, e := range browseActionHelp {
+		add(e.action, e.description, true)
+	}
+	for _, e := range gitActionHelp {
+		add(e.action, e.description, m.git.isRepo)
+	}
+	for _, action := range keymapActions() {
+		add(action, actionDescription(action), true)
+	}
+	return entries
+}
+
+// commandPaletteScore fuzzy-matches query against an entry: every query
+// character must appear in order in the description, action name, or keys.
+// Lower scores are better; matches in the description, at word starts, and
+// in runs rank first, and unavailable actions rank after available ones.
+func commandPaletteScore(query string, entry paletteEntry) (int, bool) {
+	best, found := 0, false
+	for i, field := range []string{entry.description, entry.action, entry.keys} {
+		score, ok := fuzzyScore(strings.ToLower(query), strings.ToLower(field))
+		if !ok {
+			continue
+		}
+		score += i * 50
+		if !found || score < best {
+			best, found = score, true
+		}
+	}
+	if found && !entry.available {
+		best += 1000
+	}
+	return best, found
+}
+
+// fuzzyScore matches the runes of query in order within text, scoring the
+// gaps between them; a match at the start of a word costs nothing.
+func fuzzyScore(query, text string) (int, bool) {
+	target := []rune(text)
+	score, pos := 0, 0
+	for _, r := range query {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		start := pos
+		for pos < len(target) && target[pos] != r {
+			pos++
+		}
+		if pos == len(target) {
+			return 0, false
+		}
+		wordStart := pos == 0 || !unicode.IsLetter(target[pos-1]) && !unicode.IsDigit(target[pos-1])
+		if !wordStart {
+			score += pos - start + 1
+		}
+		pos++
+	}
+	return score, true
+}
+
+// renderCommandPalette draws the filter input and the matching actions, with
+// their keys right-aligned and unavailable actions dimmed.
+func (m *Model) renderCommandPalette(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	m.paletteInput.Width = max(0, innerWidth-lipgloss.Width(m.paletteInput.Prompt)-1)
+	lines := []string{
+		titleStyle.Render("Command Palette"),
+		m.paletteInput.View(),
+		"",
+	}
+	limit := max(0, innerHeight-len(lines)-1)
+	start := 0
+	if limit > 0 {
+		start = max(0, m.paletteCursor-limit+1)
+	}
+	for i := start; i < min(start+limit, len(m.paletteEntries)); i++ {
+		entry := m.paletteEntries[i]
+		keys := truncate(entry.keys, max(0, innerWidth/3))
+		name := truncate(entry.description, max(0, innerWidth-lipgloss.Width(keys)-1))
+		gap := max(1, innerWidth-lipgloss.Width(name)-lipgloss.Width(keys))
+		line := name + strings.Repeat(" ", gap) + keys
+		switch {
+		case i == m.paletteCursor:
+			line = selectedStyle.Render(line)
+		case !entry.available:
+			line = mutedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(m.paletteEntries) == 0 {
+		lines = append(lines, mutedStyle.Render("No matching actions"))
+	}
+	lines = append(lines, mutedStyle.Render(fmt.Sprintf("%d actions  Enter: run  Esc: cancel", len(m.paletteEntries))))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
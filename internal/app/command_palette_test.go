@@ -0,0 +1,104 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func newPaletteTestModel(t *testing.T) *Model {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "# A\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.loadKeybindings(config.Config{
+		Keybindings: map[string]config.KeyList{actionExport: {"ctrl+e"}},
+	})
+	return m
+}
+
+func typePalette(m *Model, text string) {
+	for _, r := range text {
+		m.handleCommandPaletteKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestCommandPaletteRunsFilteredAction(t *testing.T) {
+	m := newPaletteTestModel(t)
+	m.handleBrowseKey(":")
+	if m.overlay != overlayCommandPalette || len(m.paletteEntries) < 30 {
+		t.Fatalf("expected the palette with every action, got overlay %v and %d entries", m.overlay, len(m.paletteEntries))
+	}
+
+	typePalette(m, "exp note")
+	if len(m.paletteEntries) == 0 || m.paletteEntries[0].action != actionExport {
+		t.Fatalf("expected export to match first, got %+v", m.paletteEntries)
+	}
+	if m.paletteEntries[0].keys != "Ctrl+E" {
+		t.Fatalf("expected the remapped key to be listed, got %q", m.paletteEntries[0].keys)
+	}
+	view := m.renderCommandPalette(70, 20)
+	if !strings.Contains(view, "Export note") || !strings.Contains(view, "Ctrl+E") {
+		t.Fatalf("expected the export row with its key in the palette:\n%s", view)
+	}
+
+	m.handleCommandPaletteKey(keyPress("enter"))
+	if m.overlay != overlayExport {
+		t.Fatalf("expected Enter to run the export action, got overlay %v", m.overlay)
+	}
+}
+
+func TestCommandPaletteTypesNavigationLetters(t *testing.T) {
+	m := newPaletteTestModel(t)
+	m.openCommandPalette()
+	typePalette(m, "jump")
+	if m.paletteInput.Value() != "jump" {
+		t.Fatalf("expected letters to reach the filter, got %q", m.paletteInput.Value())
+	}
+	for _, entry := range m.paletteEntries {
+		if _, ok := fuzzyScore("jump", strings.ToLower(entry.description+" "+entry.action+" "+entry.keys)); !ok {
+			t.Fatalf("unexpected non-matching entry %+v", entry)
+		}
+	}
+	m.handleCommandPaletteKey(tea.KeyMsg{Type: tea.KeyDown})
+	if m.paletteCursor != 1 {
+		t.Fatalf("expected arrow keys to move the cursor, got %d", m.paletteCursor)
+	}
+	m.handleCommandPaletteKey(keyPress("esc"))
+	if m.overlay != overlayNone {
+		t.Fatalf("expected Esc to close the palette, got overlay %v", m.overlay)
+	}
+}
+
+func TestCommandPaletteDimsUnavailableActions(t *testing.T) {
+	m := newPaletteTestModel(t)
+	m.openCommandPalette()
+	available := map[string]bool{}
+	for _, entry := range m.paletteEntries {
+		available[entry.action] = entry.available
+	}
+	if available[actionGitPush] || available[actionEditNote] || !available[actionNewNote] {
+		t.Fatalf("expected git and note actions unavailable outside a repo with no note, got %v", available)
+	}
+
+	typePalette(m, "git push")
+	if len(m.paletteEntries) == 0 || m.paletteEntries[0].action != actionGitPush {
+		t.Fatalf("expected git push to match, got %+v", m.paletteEntries)
+	}
+	m.handleCommandPaletteKey(keyPress("enter"))
+	if m.overlay != overlayCommandPalette || m.status != "Git push is not available here" {
+		t.Fatalf("expected the palette to refuse an unavailable action, got %v / %q", m.overlay, m.status)
+	}
+
+	m.git.isRepo = true
+	m.currentFile = filepath.Join(m.notesDir, "a.md")
+	m.updateCommandPalette()
+	if !m.paletteEntries[0].available {
+		t.Fatalf("expected git push available inside a repo, got %+v", m.paletteEntries[0])
+	}
+}
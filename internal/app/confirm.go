@@ -0,0 +1,68 @@
+// confirm.go implements the shared yes/no prompt (modeConfirm) shown before
+// actions that would throw work away: quitting during a focus session
+// (focus.go) and switching workspaces with unsaved edits or drafts
+// (workspace_export.go).
+//
+// The question is shown in the footer status. `y` runs the confirmed action;
+// `n`, Enter, and Esc cancel and return to the mode the prompt was raised
+// from, so a guarded action never proceeds by accident.
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmPrompt describes a pending yes/no question.
+type confirmPrompt struct {
+	// question is shown in the status bar and should end with "(y/n)".
+	question string
+	// yesHint and noHint label the footer key hints.
+	yesHint string
+	noHint  string
+	// cancelStatus replaces the question when the prompt is declined.
+	cancelStatus string
+	// returnMode is restored when the prompt is declined.
+	returnMode mode
+	// onConfirm runs after `y`, with the prompt already cleared.
+	onConfirm func() (tea.Model, tea.Cmd)
+}
+
+// askConfirm enters modeConfirm with p.
+func (m *Model) askConfirm(p confirmPrompt) {
+	m.confirm = &p
+	m.mode = modeConfirm
+	m.status = p.question
+}
+
+// handleConfirmKey answers the pending prompt.
+func (m *Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	p := m.confirm
+	if p == nil {
+		m.mode = modeBrowse
+		return m, nil
+	}
+	switch strings.ToLower(msg.String()) {
+	case "y":
+		m.confirm = nil
+		m.mode = p.returnMode
+		return p.onConfirm()
+	case "esc", "n", "enter":
+		m.confirm = nil
+		m.mode = p.returnMode
+		m.status = p.cancelStatus
+	}
+	return m, nil
+}
+
+// confirmFooterHints returns the footer key hints of the pending prompt.
+func (m *Model) confirmFooterHints() []string {
+	if m.confirm == nil {
+		return []string{"y confirm", "n/Esc cancel"}
+	}
+	return []string{"y " + m.confirm.yesHint, "n/Esc " + m.confirm.noHint}
+}
@@ -23,11 +23,43 @@ const (
 	// WorkspacePopupHeight is the fixed height of workspace chooser popup.
 	WorkspacePopupHeight = 12
 	// ExportPopupHeight is the fixed height of export chooser popup.
-	ExportPopupHeight = 8
+	ExportPopupHeight = 15
+	// HeadingCasePopupHeight is the fixed height of the heading case popup.
+	HeadingCasePopupHeight = 8
+	// AgendaPopupHeight is the minimum height of the agenda popup.
+	AgendaPopupHeight = 12
+	// KeymapPopupHeight is the minimum height of the keybindings popup.
+	KeymapPopupHeight = 14
+	// CommandPalettePopupHeight is the minimum height of the command palette.
+	CommandPalettePopupHeight = 14
 	// WikiLinksPopupHeight is the fixed height of wiki links popup.
 	WikiLinksPopupHeight = 14
+	// IssuesPopupHeight is the fixed height of the current-note issues popup.
+	IssuesPopupHeight = 14
+	// MetadataPopupHeight is the fixed height of the frontmatter metadata popup.
+	MetadataPopupHeight = 14
+	// GitPanelPopupHeight is the minimum height of the git panel popup.
+	GitPanelPopupHeight = 16
+	// SavedViewsPopupHeight is the minimum height of the saved-views popup.
+	SavedViewsPopupHeight = 10
+	// TagBrowserPopupHeight is the minimum height of the tag browser popup.
+	TagBrowserPopupHeight = 12
+	// MaintenancePopupHeight is the fixed height of the maintenance popup.
+	MaintenancePopupHeight = 8
+	// TagFilterPopupHeight is the minimum height of the tag filter popup.
+	TagFilterPopupHeight = 10
+	// TrashPopupHeight is the minimum height of the trash restore popup.
+	TrashPopupHeight = 14
+	// NoteStatsPopupHeight is the minimum height of the note stats popup.
+	NoteStatsPopupHeight = 16
+	// GitDiffPopupHeight is the minimum height of the git diff popup.
+	GitDiffPopupHeight = 12
+	// GitLogPopupHeight is the minimum height of the git history popup.
+	GitLogPopupHeight = 12
 	// WikiAutocompletePopupHeight is popup height for edit autocomplete.
 	WikiAutocompletePopupHeight = 10
+	// PopupPageRows is how far popup.page_up/page_down move a list cursor.
+	PopupPageRows = 5
 
 	// FooterMinRows is the default number of rows reserved for the bottom
 	// status/help area. The app targets two rows on typical terminal widths.
@@ -35,6 +67,10 @@ const (
 	// FooterMaxRows is the expanded footer height used when content does not
 	// fit within FooterMinRows.
 	FooterMaxRows = 3
+
+	// MetadataStripMaxLines caps the height of the frontmatter summary strip
+	// shown under the preview header.
+	MetadataStripMaxLines = 2
 )
 
 // Input limits define maximum sizes for user input
@@ -45,14 +81,42 @@ const (
 
 // Rendering constants control render timing and optimization
 const (
-	// RenderDebounce is the delay before triggering a render after window resize
+	// RenderDebounce is the longest delay before a render starts; the adaptive
+	// delay grows with the predicted render cost up to this cap
 	RenderDebounce = 500 * time.Millisecond
 
+	// RenderInstantThreshold is the predicted render cost below which a
+	// render is dispatched without any delay
+	RenderInstantThreshold = 20 * time.Millisecond
+
+	// RenderNavBurstInterval is the gap between render requests below which
+	// navigation counts as fast (keys held down or repeated quickly)
+	RenderNavBurstInterval = 100 * time.Millisecond
+
+	// RenderNavRestDelay is how long the cursor must rest during fast
+	// navigation before a render is dispatched
+	RenderNavRestDelay = 150 * time.Millisecond
+
+	// RenderPlaceholderDelay is the expected wait from which the preview is
+	// replaced by the rendering placeholder
+	RenderPlaceholderDelay = 100 * time.Millisecond
+
 	// RenderWidthBucket is the granularity for width-based render caching
 	// Widths are rounded to nearest multiple of this value
 	RenderWidthBucket = 20
+
+	// MaxHighlightedCodeLines is the longest fenced code block the preview
+	// syntax-highlights; longer blocks render as plain text so a huge block
+	// cannot stall the render
+	MaxHighlightedCodeLines = 2000
 )
 
+// ModeTransitionKeyGuard is how long key presses are dropped after a key
+// changes the mode or opens/closes a popup. Keys buffered before the
+// transition are dispatched within this window; deliberate human key presses
+// arrive later.
+const ModeTransitionKeyGuard = 50 * time.Millisecond
+
 // File system permissions
 const (
 	// DirPermission is the permission mode for newly created directories
@@ -69,6 +133,34 @@ const (
 	// discarded to prevent unbounded memory growth during long editing
 	// sessions.
 	MaxUndoHistory = 1000
+
+	// EditorSoftTabWidth is the number of spaces Tab inserts in edit mode
+	// when the wiki autocomplete popup is closed.
+	EditorSoftTabWidth = 4
+)
+
+// Footer metrics constants
+const (
+	// ReadingWordsPerMinute is the reading speed used for the footer's
+	// estimated reading time.
+	ReadingWordsPerMinute = 200
+	// LiveMetricsDebounceBytes is the editor buffer size from which footer
+	// metrics are recomputed after a pause in typing instead of on every
+	// keystroke.
+	LiveMetricsDebounceBytes = 32 * 1024
+	// LiveMetricsDebounce is the pause in typing after which metrics for
+	// large buffers are recomputed.
+	LiveMetricsDebounce = 300 * time.Millisecond
+)
+
+// Focus session constants
+const (
+	// FocusTickInterval is the longest gap between focus timer checks; the
+	// footer countdown shows whole minutes, so finer ticks add nothing.
+	FocusTickInterval = 15 * time.Second
+	// FocusHistoryLimit is the number of completed focus sessions kept in
+	// the workspace state file.
+	FocusHistoryLimit = 500
 )
 
 // Search constants
@@ -85,4 +177,94 @@ const (
 	// DefaultFileWatchInterval is the poll interval used when no valid config
 	// override is available.
 	DefaultFileWatchInterval = 2 * time.Second
+	// FileEventDebounce is how long the event watcher waits after the last
+	// filesystem event before delivering a batch, so a git pull or sync burst
+	// causes one refresh.
+	FileEventDebounce = 300 * time.Millisecond
+)
+
+// Journal constants
+const (
+	// DefaultJournalDir is the notes-relative folder for daily notes when
+	// journal_dir is not configured.
+	DefaultJournalDir = "journal"
+	// DefaultJournalTemplate seeds a new daily note when journal_template is
+	// not configured.
+	DefaultJournalTemplate = "# {{date}}\n"
+)
+
+// Template constants
+const (
+	// FolderTemplateFileName is the per-folder default template. New notes
+	// created in the folder (or any subfolder without its own) start from it
+	// instead of showing the template picker.
+	FolderTemplateFileName = ".cli-notes-template.md"
+	// DailyTemplateFileName is the template in the templates directory that
+	// seeds new daily notes; it takes precedence over journal_template.
+	DailyTemplateFileName = "daily.md"
+	// TemplatePartialsDirName is the folder holding {{> name}} partials, in
+	// the templates directory and in a workspace's managed .cli-notes
+	// directory (see template_compose.go).
+	TemplatePartialsDirName = "partials"
+	// AutoTagFileName holds a workspace's folder-based tagging rules, inside
+	// its managed .cli-notes directory (see autotag.go).
+	AutoTagFileName = "autotag.json"
+)
+
+// Inbox constants
+const (
+	// DefaultInboxDir is the notes-relative inbox folder when inbox_dir is
+	// not configured.
+	DefaultInboxDir = "inbox"
+	// InboxFileName is the capture file inside the inbox folder whose
+	// bullet lines are processed one by one.
+	InboxFileName = "inbox.md"
+)
+
+// Folder toggle constants
+const (
+	// DefaultToggleFolderFirst and DefaultToggleFolderSecond are the folders
+	// the folder toggle moves notes between when toggle_folders is not set.
+	DefaultToggleFolderFirst  = "active"
+	DefaultToggleFolderSecond = "done"
+)
+
+// Note age color constants
+const (
+	// DefaultNoteAgeFreshDays and DefaultNoteAgeStaleDays are the note age
+	// thresholds used when note_age_days is not set: notes modified within
+	// a week are fresh, notes untouched for over 90 days are stale.
+	DefaultNoteAgeFreshDays = 7
+	DefaultNoteAgeStaleDays = 90
+)
+
+// Encryption constants
+const (
+	// EncryptedNoteExt is appended to a note's name when it is encrypted,
+	// so notes/secret.md is stored as notes/secret.md.enc.
+	EncryptedNoteExt = ".enc"
+	// EncryptedNoteMagic starts every encrypted note file.
+	EncryptedNoteMagic = "CLINOTES-ENC1\n"
+)
+
+// Archive constants
+const (
+	// ArchiveDirName is the notes-relative folder that archived notes and
+	// folders are moved into, preserving their relative subpath.
+	ArchiveDirName = "archive"
+)
+
+// Git constants
+const (
+	// GitOperationTimeout is how long a background git pull, push, or commit
+	// holds the operation interlock before changes are allowed again even
+	// without a result.
+	GitOperationTimeout = 2 * time.Minute
+)
+
+// Tree constants
+const (
+	// MaxExpandAllDirs bounds how many folders expand-all opens, so a huge
+	// vault does not produce an unusably long tree.
+	MaxExpandAllDirs = 5000
 )
@@ -0,0 +1,32 @@
+// describe_key.go implements the describe-key mode (Alt+D by default): the
+// next key pressed in browse mode is looked up with actionForKey and its
+// action and help description are shown in the status bar, without running
+// the action. It helps users learn the defaults and check custom keymaps.
+package app
+
+import "fmt"
+
+// startDescribeKey waits for the next browse key and describes it.
+func (m *Model) startDescribeKey() {
+	m.describeKeyPending = true
+	m.status = "Press a key to see its action"
+}
+
+// describeKey reports the action bound to key and leaves describe mode.
+func (m *Model) describeKey(key string) {
+	m.describeKeyPending = false
+	label := humanizeKeyLabel(key)
+	if label == "" {
+		label = key
+	}
+	action := m.actionForKey(key)
+	if action == "" {
+		m.status = label + " is unbound"
+		return
+	}
+	if description := actionDescription(action); description != "" {
+		m.status = fmt.Sprintf("%s runs %s: %s", label, action, description)
+		return
+	}
+	m.status = fmt.Sprintf("%s runs %s", label, action)
+}
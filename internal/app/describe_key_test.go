@@ -0,0 +1,57 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func TestDescribeKeyReportsMappedActionWithoutRunningIt(t *testing.T) {
+	m := &Model{
+		cursor: 0,
+		items:  []treeItem{{name: "a"}, {name: "b"}},
+	}
+	m.loadKeybindings(config.Config{})
+
+	_, _ = m.handleBrowseKey("alt+d")
+	if !m.describeKeyPending {
+		t.Fatal("expected describe mode after alt+d")
+	}
+	_, _ = m.handleBrowseKey("j")
+	if m.cursor != 0 {
+		t.Fatalf("expected j not to move the cursor, got %d", m.cursor)
+	}
+	if want := "J runs tree.cursor.down: Move selection down"; m.status != want {
+		t.Fatalf("status = %q, want %q", m.status, want)
+	}
+	if m.describeKeyPending {
+		t.Fatal("expected describe mode to end after one key")
+	}
+
+	_, _ = m.handleBrowseKey("j")
+	if m.cursor != 1 {
+		t.Fatalf("expected j to move the cursor again, got %d", m.cursor)
+	}
+}
+
+func TestDescribeKeyReportsUnboundKey(t *testing.T) {
+	m := &Model{}
+	m.loadKeybindings(config.Config{})
+
+	m.startDescribeKey()
+	_, _ = m.handleBrowseKey("ctrl+q")
+	if want := "Ctrl+Q is unbound"; m.status != want {
+		t.Fatalf("status = %q, want %q", m.status, want)
+	}
+}
+
+func TestDescribeKeyFollowsCustomBinding(t *testing.T) {
+	m := &Model{}
+	m.loadKeybindings(config.Config{Keybindings: map[string]config.KeyList{actionNewNote: {"ctrl+q"}}})
+
+	m.startDescribeKey()
+	m.describeKey("ctrl+q")
+	if want := "Ctrl+Q runs note.new: New note"; m.status != want {
+		t.Fatalf("status = %q, want %q", m.status, want)
+	}
+}
@@ -0,0 +1,75 @@
+// doctor.go implements `notes doctor`, a read-only check of the configured
+// notes directory for problems that are invisible in the TUI but break the
+// workspace elsewhere — names that differ only by case, and templates whose
+// partials or inheritance do not resolve. It also reports the drafts count
+// and size, which is informational and never counts as a problem.
+package app
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// RunDoctor checks the configured notes directory and writes a report to out.
+// It returns the number of problems found; the error is non-nil only when
+// the check itself could not run.
+func RunDoctor(out io.Writer) (int, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return 0, err
+	}
+	return doctorReport(out, cfg.NotesDir, cfg.TemplatesDir)
+}
+
+// doctorReport runs the checks against notesDir and templatesDir.
+func doctorReport(out io.Writer, notesDir, templatesDir string) (int, error) {
+	fmt.Fprintf(out, "Notes directory: %s\n", notesDir)
+	insensitive, err := probeCaseInsensitive(notesDir)
+	switch {
+	case err != nil:
+		fmt.Fprintf(out, "Filesystem: case sensitivity unknown (%v)\n", err)
+	case insensitive:
+		fmt.Fprintln(out, "Filesystem: case-insensitive")
+	default:
+		fmt.Fprintln(out, "Filesystem: case-sensitive")
+	}
+
+	if usage, err := scanDraftUsage(notesDir); err != nil {
+		fmt.Fprintf(out, "Drafts: unknown (%v)\n", err)
+	} else {
+		fmt.Fprintf(out, "Drafts: %s\n", usage)
+	}
+
+	templateProblems := 0
+	if templatesDir != "" {
+		problems, count, err := lintTemplates(templatesDir, notesDir)
+		switch {
+		case err != nil:
+			fmt.Fprintf(out, "Templates: unknown (%v)\n", err)
+		case len(problems) == 0:
+			fmt.Fprintf(out, "OK: %d template(s) resolve\n", count)
+		default:
+			fmt.Fprintf(out, "Found %d template problem(s):\n", len(problems))
+			for _, problem := range problems {
+				fmt.Fprintf(out, "  %s\n", problem)
+			}
+		}
+		templateProblems = len(problems)
+	}
+
+	pairs, err := findCaseCollisions(notesDir)
+	if err != nil {
+		return 0, fmt.Errorf("scan notes directory %q: %w", notesDir, err)
+	}
+	if len(pairs) == 0 {
+		fmt.Fprintln(out, "OK: no names differ only by case")
+		return templateProblems, nil
+	}
+	fmt.Fprintf(out, "Found %d name(s) differing only by case; they collide on case-insensitive filesystems:\n", len(pairs))
+	for _, pair := range pairs {
+		fmt.Fprintf(out, "  %s  <->  %s\n", pair.first, pair.second)
+	}
+	return templateProblems + len(pairs), nil
+}
@@ -0,0 +1,236 @@
+// draft_retention.go bounds the drafts directory (<notes_dir>/.cli-notes/.drafts/).
+//
+// Drafts are full copies of the edited note, and without a policy they pile
+// up: drafts of notes deleted long ago, and snapshots nobody will recover.
+// Whenever a workspace is opened (startup and workspace switch), before the
+// recovery prompt is built, applyDraftRetention purges:
+//
+//   - drafts last written more than draft_max_age_days ago;
+//   - drafts whose note no longer exists once they have been skipped in the
+//     recovery prompt draft_orphan_skips times (Esc counts as a skip, and
+//     the count is kept in the draft file);
+//   - the oldest drafts while the total size exceeds draft_max_total_mb.
+//
+// Drafts that are shown or queued in the recovery prompt, and the draft of
+// the note being edited, are never purged. Every purge is logged with its
+// reason. The trash popup (Ctrl+T) and `notes doctor` report the current
+// drafts count and size.
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// draftRetention holds the draft retention policy. A zero field disables
+// its rule.
+type draftRetention struct {
+	maxAge      time.Duration
+	maxBytes    int64
+	orphanSkips int
+}
+
+// draftRetentionFromConfig builds the policy from normalized config values.
+func draftRetentionFromConfig(cfg config.Config) draftRetention {
+	return draftRetention{
+		maxAge:      time.Duration(cfg.DraftMaxAgeDays) * 24 * time.Hour,
+		maxBytes:    int64(cfg.DraftMaxTotalMB) << 20,
+		orphanSkips: cfg.DraftOrphanSkips,
+	}
+}
+
+// draftFile is a parsed draft together with its size on disk.
+type draftFile struct {
+	record draftRecord
+	size   int64
+}
+
+// draftPurge is a draft selected for removal and the rule that selected it.
+type draftPurge struct {
+	file   draftFile
+	reason string
+}
+
+// draftsDirFor returns the drafts directory of the notes root.
+func draftsDirFor(root string) string {
+	return filepath.Join(root, managedNotesDirName, ".drafts")
+}
+
+// listDraftFiles reads every draft in dir. Unreadable or malformed files
+// are logged and left alone.
+func listDraftFiles(dir string) ([]draftFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]draftFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			appLog.Warn("read draft", "path", path, "error", readErr)
+			continue
+		}
+		var record draftRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			appLog.Warn("parse draft", "path", path, "error", err)
+			continue
+		}
+		record.DraftPath = path
+		files = append(files, draftFile{record: record, size: int64(len(data))})
+	}
+	return files, nil
+}
+
+// draftSourceMissing reports whether the note a draft belongs to is gone.
+func draftSourceMissing(record draftRecord) bool {
+	_, err := os.Stat(record.SourcePath)
+	return os.IsNotExist(err)
+}
+
+// planDraftPurges applies the policy to files and returns the drafts to
+// remove. Drafts whose path is in protected are kept whatever their age or
+// size, though they still count toward the size cap.
+func planDraftPurges(files []draftFile, policy draftRetention, now time.Time, protected map[string]bool) []draftPurge {
+	var purges []draftPurge
+	kept := make([]draftFile, 0, len(files))
+	for _, file := range files {
+		switch {
+		case protected[file.record.DraftPath]:
+			kept = append(kept, file)
+		case policy.maxAge > 0 && now.Sub(file.record.UpdatedAt) > policy.maxAge:
+			purges = append(purges, draftPurge{file: file, reason: "expired"})
+		case policy.orphanSkips > 0 && file.record.Skips >= policy.orphanSkips && draftSourceMissing(file.record):
+			purges = append(purges, draftPurge{file: file, reason: "orphaned"})
+		default:
+			kept = append(kept, file)
+		}
+	}
+	if policy.maxBytes <= 0 {
+		return purges
+	}
+	var total int64
+	for _, file := range kept {
+		total += file.size
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].record.UpdatedAt.Before(kept[j].record.UpdatedAt)
+	})
+	for _, file := range kept {
+		if total <= policy.maxBytes {
+			break
+		}
+		if protected[file.record.DraftPath] {
+			continue
+		}
+		purges = append(purges, draftPurge{file: file, reason: "over size cap"})
+		total -= file.size
+	}
+	return purges
+}
+
+// protectedDraftPaths returns the draft files that must not be purged: the
+// ones shown or queued in the recovery prompt and the one of the note being
+// edited.
+func (m *Model) protectedDraftPaths() map[string]bool {
+	protected := map[string]bool{}
+	if m.activeDraft != nil {
+		protected[m.activeDraft.DraftPath] = true
+	}
+	for _, record := range m.pendingDrafts {
+		protected[record.DraftPath] = true
+	}
+	if m.mode == modeEditNote && m.currentFile != "" {
+		protected[m.draftPathForSource(m.currentFile)] = true
+	}
+	return protected
+}
+
+// applyDraftRetention purges drafts of the current workspace according to
+// m.draftPolicy and logs each purge.
+func (m *Model) applyDraftRetention() {
+	files, err := listDraftFiles(m.draftsDir())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			appLog.Warn("list draft files", "dir", m.draftsDir(), "error", err)
+		}
+		return
+	}
+	for _, purge := range planDraftPurges(files, m.draftPolicy, time.Now(), m.protectedDraftPaths()) {
+		record := purge.file.record
+		if err := os.Remove(record.DraftPath); err != nil && !os.IsNotExist(err) {
+			appLog.Warn("purge draft", "path", record.DraftPath, "error", err)
+			continue
+		}
+		appLog.Info("purged draft", "path", record.DraftPath, "source", record.SourcePath, "reason", purge.reason, "updated_at", record.UpdatedAt, "bytes", purge.file.size)
+	}
+}
+
+// recordDraftSkips counts a skip in the recovery prompt against each draft
+// in records whose note no longer exists, so orphaned drafts are purged
+// after draft_orphan_skips skips.
+func recordDraftSkips(records []draftRecord) {
+	for _, record := range records {
+		if !draftSourceMissing(record) {
+			continue
+		}
+		record.Skips++
+		data, err := json.Marshal(record)
+		if err == nil {
+			err = os.WriteFile(record.DraftPath, data, 0o600)
+		}
+		if err != nil {
+			appLog.Warn("record draft skip", "path", record.DraftPath, "error", err)
+		}
+	}
+}
+
+// draftUsage summarizes a drafts directory.
+type draftUsage struct {
+	count   int
+	bytes   int64
+	orphans int
+}
+
+// scanDraftUsage counts the drafts under root and their total size.
+func scanDraftUsage(root string) (draftUsage, error) {
+	files, err := listDraftFiles(draftsDirFor(root))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return draftUsage{}, nil
+		}
+		return draftUsage{}, err
+	}
+	var usage draftUsage
+	for _, file := range files {
+		usage.count++
+		usage.bytes += file.size
+		if draftSourceMissing(file.record) {
+			usage.orphans++
+		}
+	}
+	return usage, nil
+}
+
+// String renders the usage for the trash popup and doctor report, e.g.
+// "3 drafts, 12.4K (1 for deleted notes)".
+func (u draftUsage) String() string {
+	noun := "drafts"
+	if u.count == 1 {
+		noun = "draft"
+	}
+	text := fmt.Sprintf("%d %s, %s", u.count, noun, formatCompactSize(u.bytes))
+	if u.orphans > 0 {
+		text += fmt.Sprintf(" (%d for deleted notes)", u.orphans)
+	}
+	return text
+}
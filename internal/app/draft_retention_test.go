@@ -0,0 +1,163 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// writeTestDraft stores a draft for source written at updatedAt and returns
+// the draft file path.
+func writeTestDraft(t *testing.T, m *Model, source, content string, updatedAt time.Time, skips int) string {
+	t.Helper()
+	data, err := json.Marshal(draftRecord{SourcePath: source, Content: content, UpdatedAt: updatedAt, Skips: skips})
+	if err != nil {
+		t.Fatalf("marshal draft: %v", err)
+	}
+	path := m.draftPathForSource(source)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir drafts: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write draft: %v", err)
+	}
+	return path
+}
+
+func assertDraftExists(t *testing.T, path string, want bool) {
+	t.Helper()
+	_, err := os.Stat(path)
+	if got := err == nil; got != want {
+		t.Fatalf("draft %s exists=%v, want %v", filepath.Base(path), got, want)
+	}
+}
+
+func TestDraftRetentionPurgesExpiredDrafts(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "old.md"), "saved\n")
+	mustWriteFile(t, filepath.Join(root, "new.md"), "saved\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.draftPolicy = draftRetention{maxAge: 14 * 24 * time.Hour}
+	old := writeTestDraft(t, m, filepath.Join(root, "old.md"), "edit\n", time.Now().Add(-15*24*time.Hour), 0)
+	recent := writeTestDraft(t, m, filepath.Join(root, "new.md"), "edit\n", time.Now().Add(-time.Hour), 0)
+
+	m.loadPendingDrafts()
+	assertDraftExists(t, old, false)
+	assertDraftExists(t, recent, true)
+	if m.activeDraft == nil || m.activeDraft.DraftPath != recent || len(m.pendingDrafts) != 0 {
+		t.Fatalf("expected only the recent draft offered, got %+v %+v", m.activeDraft, m.pendingDrafts)
+	}
+}
+
+func TestDraftRetentionPurgesOrphansAfterSkips(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "kept.md"), "saved\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.draftPolicy = draftRetention{orphanSkips: 2}
+	orphan := writeTestDraft(t, m, filepath.Join(root, "gone.md"), "lost\n", time.Now(), 0)
+	kept := writeTestDraft(t, m, filepath.Join(root, "kept.md"), "edit\n", time.Now().Add(-time.Minute), 5)
+
+	for skip := 1; skip <= 2; skip++ {
+		m.loadPendingDrafts()
+		if m.mode != modeDraftRecovery || m.activeDraft.DraftPath != orphan {
+			t.Fatalf("skip %d: expected orphan surfaced first, got %+v", skip, m.activeDraft)
+		}
+		_, _ = m.handleDraftRecoveryKey(tea.KeyMsg{Type: tea.KeyEsc})
+		assertDraftExists(t, orphan, true)
+	}
+	m.loadPendingDrafts()
+	assertDraftExists(t, orphan, false)
+	assertDraftExists(t, kept, true)
+	if m.activeDraft == nil || m.activeDraft.DraftPath != kept || m.activeDraft.Skips != 5 {
+		t.Fatalf("expected skips on an existing note not to count, got %+v", m.activeDraft)
+	}
+}
+
+func TestDraftRetentionCapsTotalSizeOldestFirst(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	big := strings.Repeat("x", 400)
+	now := time.Now()
+	oldest := writeTestDraft(t, m, filepath.Join(root, "a.md"), big, now.Add(-3*time.Hour), 0)
+	middle := writeTestDraft(t, m, filepath.Join(root, "b.md"), big, now.Add(-2*time.Hour), 0)
+	newest := writeTestDraft(t, m, filepath.Join(root, "c.md"), big, now.Add(-time.Hour), 0)
+	files, err := listDraftFiles(m.draftsDir())
+	if err != nil || len(files) != 3 {
+		t.Fatalf("list drafts: %v %d", err, len(files))
+	}
+	size := files[0].size
+
+	purges := planDraftPurges(files, draftRetention{maxBytes: 2 * size}, now, nil)
+	if len(purges) != 1 || purges[0].file.record.DraftPath != oldest || purges[0].reason != "over size cap" {
+		t.Fatalf("expected only the oldest purged, got %+v", purges)
+	}
+	purges = planDraftPurges(files, draftRetention{maxBytes: size}, now, map[string]bool{oldest: true})
+	if len(purges) != 2 || purges[0].file.record.DraftPath != middle || purges[1].file.record.DraftPath != newest {
+		t.Fatalf("expected protected oldest kept and the others purged, got %+v", purges)
+	}
+}
+
+func TestDraftRetentionNeverPurgesPendingPrompt(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.draftPolicy = draftRetention{maxAge: time.Hour}
+	path := writeTestDraft(t, m, filepath.Join(root, "a.md"), "edit\n", time.Now().Add(-2*time.Hour), 0)
+	m.activeDraft = &draftRecord{SourcePath: filepath.Join(root, "a.md"), DraftPath: path}
+	m.mode = modeDraftRecovery
+
+	m.applyDraftRetention()
+	assertDraftExists(t, path, true)
+}
+
+func TestSaveDraftSkipsContentIdenticalToSavedFile(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.md")
+	mustWriteFile(t, path, "same\n")
+	m := newTestCRUDModel(root)
+	m.currentFile = path
+	m.editor.SetValue("same\n")
+	if err := m.saveDraftForCurrentFile(); err != nil {
+		t.Fatalf("save draft: %v", err)
+	}
+	assertDraftExists(t, m.draftPathForSource(path), false)
+
+	m.editor.SetValue("sane\n")
+	if err := m.saveDraftForCurrentFile(); err != nil {
+		t.Fatalf("save draft: %v", err)
+	}
+	assertDraftExists(t, m.draftPathForSource(path), true)
+}
+
+func TestDraftUsageReportedByDoctorAndTrashPopup(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "saved\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	writeTestDraft(t, m, filepath.Join(root, "a.md"), "edit\n", time.Now(), 0)
+	writeTestDraft(t, m, filepath.Join(root, "gone.md"), "lost\n", time.Now(), 0)
+
+	var out bytes.Buffer
+	if _, err := doctorReport(&out, root, ""); err != nil {
+		t.Fatalf("doctor: %v", err)
+	}
+	if !strings.Contains(out.String(), "Drafts: 2 drafts, ") || !strings.Contains(out.String(), "(1 for deleted notes)") {
+		t.Fatalf("expected drafts line in report:\n%s", out.String())
+	}
+
+	m.openTrashPopup()
+	if !m.isOverlay(overlayTrash) || m.trashDraftUsage.count != 2 {
+		t.Fatalf("expected trash popup with drafts usage, overlay=%v usage=%+v", m.overlay, m.trashDraftUsage)
+	}
+	if view := m.renderTrashPopup(80, 16); !strings.Contains(view, "Drafts: 2 drafts") {
+		t.Fatalf("expected drafts line in popup:\n%s", view)
+	}
+}
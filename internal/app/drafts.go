@@ -1,9 +1,11 @@
 package app
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -36,6 +38,10 @@ type draftRecord struct {
 	// recovery candidates (most recent first).
 	UpdatedAt time.Time `json:"updated_at"`
 
+	// Skips counts how often the draft was skipped in the recovery prompt
+	// while its note did not exist (see draft_retention.go).
+	Skips int `json:"skips,omitempty"`
+
 	// DraftPath is the filesystem path of the draft JSON file itself.
 	// This field is not serialized; it is populated at load time so the
 	// recovery logic can delete the draft file after recovery or discard.
@@ -76,23 +82,24 @@ func (m *Model) handleDraftAutoSaveTick(_ draftAutoSaveTickMsg) (tea.Model, tea.
 // saveDraftForCurrentFile writes the current editor buffer to a draft file.
 //
 // The draft is only written when the editor content differs from the on-disk
-// file content. If the content matches the saved file exactly, any existing
-// draft is removed instead (the user has manually synced or the content was
-// reverted).
+// file content, compared by size and SHA-256 hash. If the content matches the
+// saved file, any existing draft is removed instead (the user has manually
+// synced or the content was reverted).
 //
 // Draft files are stored as JSON in <notes_dir>/.cli-notes/.drafts/ using a
 // SHA-256 hash of the source path as the filename. This avoids conflicts
 // with special characters in note names and ensures each note has at most
 // one draft file.
 func (m *Model) saveDraftForCurrentFile() error {
-	if m.currentFile == "" {
+	// Drafts are plain JSON; an encrypted note's text must not land there.
+	if m.currentFile == "" || isEncryptedNotePath(m.currentFile) {
 		return nil
 	}
 	content := m.editor.Value()
 
 	// If the editor content matches the on-disk file, there is nothing
 	// unsaved — clean up any stale draft and return early.
-	if onDisk, err := os.ReadFile(m.currentFile); err == nil && string(onDisk) == content {
+	if fileMatchesContent(m.currentFile, content) {
 		m.clearDraftForPath(m.currentFile)
 		return nil
 	}
@@ -118,10 +125,31 @@ func (m *Model) saveDraftForCurrentFile() error {
 	return nil
 }
 
+// fileMatchesContent reports whether the file at path holds exactly content.
+// Sizes are compared first so the common case of a differing buffer never
+// hashes the file.
+func fileMatchesContent(path, content string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() != int64(len(content)) {
+		return false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	fileHash := sha256.New()
+	if _, err := io.Copy(fileHash, f); err != nil {
+		return false
+	}
+	contentHash := sha256.Sum256([]byte(content))
+	return bytes.Equal(fileHash.Sum(nil), contentHash[:])
+}
+
 // draftsDir returns the absolute path to the directory where draft files
 // are stored: <notes_dir>/.cli-notes/.drafts/
 func (m *Model) draftsDir() string {
-	return filepath.Join(m.notesDir, managedNotesDirName, ".drafts")
+	return draftsDirFor(m.notesDir)
 }
 
 // draftPathForSource computes the draft file path for a given source note.
@@ -155,8 +183,9 @@ func (m *Model) clearDraftForPath(path string) {
 
 // loadPendingDrafts scans the drafts directory for recoverable unsaved work.
 //
-// This is called once during app initialization (in New()). For each draft
-// file found, it:
+// This is called during app initialization (in New()) and after a workspace
+// switch. It first applies the draft retention policy (draft_retention.go),
+// then for each remaining draft file it:
 //  1. Reads and parses the draft JSON.
 //  2. Validates that the source path is within the current notes directory
 //     (drafts from other workspaces or deleted notes are cleaned up).
@@ -168,59 +197,55 @@ func (m *Model) clearDraftForPath(path string) {
 // If any valid drafts are found, the app enters modeDraftRecovery to prompt
 // the user to recover or discard each one before normal use begins.
 func (m *Model) loadPendingDrafts() {
-	entries, err := os.ReadDir(m.draftsDir())
+	m.applyDraftRetention()
+	recoveries := m.scanPendingDrafts()
+	if len(recoveries) == 0 {
+		return
+	}
+	m.pendingDrafts = recoveries
+	m.advanceDraftRecoveryPrompt()
+}
+
+// scanPendingDrafts returns the recoverable drafts of the current notes
+// directory, most recent first, removing stale draft files on the way.
+// Drafts already in the recovery prompt are never removed.
+func (m *Model) scanPendingDrafts() []draftRecord {
+	files, err := listDraftFiles(m.draftsDir())
 	if err != nil {
 		if !os.IsNotExist(err) {
 			appLog.Warn("list draft files", "dir", m.draftsDir(), "error", err)
 		}
-		return
+		return nil
 	}
 
-	recoveries := make([]draftRecord, 0, len(entries))
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		path := filepath.Join(m.draftsDir(), entry.Name())
-		data, readErr := os.ReadFile(path)
-		if readErr != nil {
-			appLog.Warn("read draft", "path", path, "error", readErr)
-			continue
-		}
-		var record draftRecord
-		if err := json.Unmarshal(data, &record); err != nil {
-			appLog.Warn("parse draft", "path", path, "error", err)
-			continue
+	protected := m.protectedDraftPaths()
+	recoveries := make([]draftRecord, 0, len(files))
+	for _, file := range files {
+		record := file.record
+		stale := ""
+		switch {
+		case record.SourcePath == "" || !isWithinRoot(m.notesDir, record.SourcePath):
+			// Leftovers from a different workspace configuration.
+			stale = "outside notes directory"
+		case fileMatchesContent(record.SourcePath, record.Content):
+			// The note was saved normally after the draft was created.
+			stale = "matches saved note"
 		}
-
-		// Discard drafts that reference files outside the current notes root
-		// (e.g. leftovers from a different workspace configuration).
-		if record.SourcePath == "" || !isWithinRoot(m.notesDir, record.SourcePath) {
-			_ = os.Remove(path)
+		if stale != "" && !protected[record.DraftPath] {
+			if err := os.Remove(record.DraftPath); err == nil {
+				appLog.Info("purged draft", "path", record.DraftPath, "source", record.SourcePath, "reason", stale)
+			}
 			continue
 		}
-
-		// Discard drafts whose content already matches the on-disk file
-		// (the note was saved normally after the draft was created).
-		if onDisk, statErr := os.ReadFile(record.SourcePath); statErr == nil && string(onDisk) == record.Content {
-			_ = os.Remove(path)
-			continue
-		}
-		record.DraftPath = path
 		recoveries = append(recoveries, record)
 	}
 
-	if len(recoveries) == 0 {
-		return
-	}
-
 	// Present the most recently modified drafts first so the user sees
 	// the most relevant recovery candidates at the top.
 	sort.Slice(recoveries, func(i, j int) bool {
 		return recoveries[i].UpdatedAt.After(recoveries[j].UpdatedAt)
 	})
-	m.pendingDrafts = recoveries
-	m.advanceDraftRecoveryPrompt()
+	return recoveries
 }
 
 // advanceDraftRecoveryPrompt moves to the next pending draft recovery
@@ -296,6 +321,9 @@ func (m *Model) handleDraftRecoveryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case "esc":
 		// Skip all: abandon remaining recovery prompts and enter browse mode.
+		// Drafts of deleted notes remember the skip so retention can purge
+		// them after draft_orphan_skips skips.
+		recordDraftSkips(append([]draftRecord{*m.activeDraft}, m.pendingDrafts...))
 		m.activeDraft = nil
 		m.pendingDrafts = nil
 		m.mode = modeBrowse
@@ -0,0 +1,147 @@
+// duplicate.go implements the duplicate action (`D`).
+//
+// Duplicating copies the selected note or folder next to the original. The
+// name input is prefilled with "<name> (copy)" (then "(copy 2)", "(copy 3)",
+// ... when taken) and can be edited before saving. Folders are copied as a
+// whole subtree through copyPathForMove, the same copier the cross-device
+// move fallback uses. When frontmatter_timestamps is on, a duplicated note
+// gets a fresh created: timestamp so it does not inherit the original's.
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startDuplicateSelected switches to duplicate mode with a free copy name
+// prefilled for the selected item.
+func (m *Model) startDuplicateSelected() {
+	item := m.selectedItem()
+	if item == nil {
+		m.status = "No item selected"
+		return
+	}
+	if item.path == m.notesDir {
+		m.status = "Cannot duplicate the root notes directory"
+		return
+	}
+	if !isWithinRoot(m.notesDir, item.path) {
+		m.status = "Cannot duplicate item outside notes directory"
+		return
+	}
+
+	m.mode = modeDuplicateItem
+	m.showHelp = false
+	m.actionPath = item.path
+	m.input.Reset()
+	m.input.Placeholder = "Name of the copy"
+	m.input.SetValue(filepath.Base(nextDuplicatePath(item.path, item.isDir)))
+	m.input.CursorEnd()
+	m.input.Focus()
+	m.status = "Duplicate: Enter or Ctrl+S to save, Esc to cancel"
+}
+
+// handleDuplicateItemKey processes keypresses while naming a duplicate.
+func (m *Model) handleDuplicateItemKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	return m.handleInputModeKey(msg, m.saveDuplicateItem, "Duplicate cancelled")
+}
+
+// saveDuplicateItem validates the copy name, copies the file or folder, and
+// selects and opens the copy.
+func (m *Model) saveDuplicateItem() (tea.Model, tea.Cmd) {
+	srcPath := m.actionPath
+	name := strings.TrimSpace(m.input.Value())
+	if name == "" {
+		m.status = "Name is required"
+		return m, nil
+	}
+	if filepath.Base(name) != name {
+		m.status = "Name cannot include path separators"
+		return m, nil
+	}
+	info, err := os.Stat(srcPath)
+	if err != nil || !isWithinRoot(m.notesDir, srcPath) {
+		m.mode = modeBrowse
+		m.status = "Invalid duplicate source"
+		return m, nil
+	}
+	if !info.IsDir() && !hasSuffixCaseInsensitive(name, ".md") {
+		name += ".md"
+	}
+
+	dstPath := filepath.Join(filepath.Dir(srcPath), name)
+	if !isWithinRoot(m.notesDir, dstPath) || dstPath == m.notesDir {
+		m.status = "Destination must be inside notes directory"
+		return m, nil
+	}
+	if _, err := os.Stat(dstPath); err == nil {
+		m.status = "Target already exists"
+		return m, nil
+	}
+	if status, collides := firstCaseCollision("", dstPath); collides {
+		m.status = status
+		return m, nil
+	}
+
+	if err := copyPathForMove(srcPath, dstPath); err != nil {
+		_ = os.RemoveAll(dstPath)
+		m.setStatusError("Error duplicating item", err, "from", srcPath, "to", dstPath)
+		return m, nil
+	}
+	if !info.IsDir() && m.frontmatterTimestamps {
+		if err := restampDuplicateCreated(dstPath, info.Mode()); err != nil {
+			appLog.Warn("stamp duplicated note", "path", dstPath, "error", err)
+		}
+	}
+
+	m.mode = modeBrowse
+	effects := mutationEffects{
+		upsertPaths:     []string{dstPath},
+		refreshGit:      true,
+		refreshTree:     true,
+		rebuildKeepPath: dstPath,
+	}
+	if !info.IsDir() {
+		effects.setCurrentFile = dstPath
+	}
+	cmd := m.applyMutationEffects(effects)
+	m.status = "Duplicated as: " + m.displayRelative(dstPath)
+	return m, cmd
+}
+
+// nextDuplicatePath returns the first free sibling path for a copy of path:
+// "x (copy).md", then "x (copy 2).md", and so on. Folders get the same
+// suffixes without an extension.
+func nextDuplicatePath(path string, isDir bool) string {
+	dir := filepath.Dir(path)
+	base := filepath.Base(path)
+	ext := ""
+	if !isDir {
+		ext = filepath.Ext(base)
+		base = strings.TrimSuffix(base, ext)
+	}
+	for n := 1; ; n++ {
+		suffix := " (copy)"
+		if n > 1 {
+			suffix = fmt.Sprintf(" (copy %d)", n)
+		}
+		candidate := filepath.Join(dir, base+suffix+ext)
+		if _, err := os.Lstat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+// restampDuplicateCreated replaces the created: timestamp of a freshly
+// duplicated note with the current time.
+func restampDuplicateCreated(path string, mode os.FileMode) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(stampFrontmatterTime(string(content), "created")), mode.Perm())
+}
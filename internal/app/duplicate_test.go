@@ -0,0 +1,110 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNextDuplicatePathIncrementsSuffix(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "plan.md")
+	mustWriteFile(t, note, "# Plan\n")
+
+	if got, want := nextDuplicatePath(note, false), filepath.Join(root, "plan (copy).md"); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	mustWriteFile(t, filepath.Join(root, "plan (copy).md"), "")
+	if got, want := nextDuplicatePath(note, false), filepath.Join(root, "plan (copy 2).md"); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	dir := filepath.Join(root, "projects.v2")
+	if got, want := nextDuplicatePath(dir, true), filepath.Join(root, "projects.v2 (copy)"); got != want {
+		t.Fatalf("folder copy: got %q, want %q", got, want)
+	}
+}
+
+func TestDuplicateNoteStampsCreatedAndOpensCopy(t *testing.T) {
+	root := t.TempDir()
+	withFixedNow(t, time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC))
+	note := filepath.Join(root, "plan.md")
+	mustWriteFile(t, note, "---\ntitle: Plan\ncreated: 2025-01-01T00:00:00Z\n---\n# Plan\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.frontmatterTimestamps = true
+	selectTreePath(t, m, note)
+
+	m.startDuplicateSelected()
+	if m.mode != modeDuplicateItem || m.input.Value() != "plan (copy).md" {
+		t.Fatalf("expected prefilled copy name, got mode %v value %q", m.mode, m.input.Value())
+	}
+	m.input.SetValue("plan v2")
+	m.saveDuplicateItem()
+
+	copyPath := filepath.Join(root, "plan v2.md")
+	data, err := os.ReadFile(copyPath)
+	if err != nil {
+		t.Fatalf("read copy: %v", err)
+	}
+	if want := "---\ntitle: Plan\ncreated: 2026-03-01T08:00:00Z\n---\n# Plan\n"; string(data) != want {
+		t.Fatalf("unexpected copy.\nwant: %q\ngot:  %q", want, string(data))
+	}
+	if m.mode != modeBrowse || m.selectedPath() != copyPath || m.currentFile != copyPath {
+		t.Fatalf("expected copy selected and open, selected %q current %q", m.selectedPath(), m.currentFile)
+	}
+	if m.status != "Duplicated as: plan v2.md" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	if results := m.searchIndex.search("v2"); len(results) == 0 {
+		t.Fatalf("expected copy in search index")
+	}
+}
+
+func TestDuplicateFolderCopiesSubtree(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "projects")
+	mustWriteFile(t, filepath.Join(dir, "a.md"), "# A\n")
+	mustWriteFile(t, filepath.Join(dir, "sub", "b.md"), "# B\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	selectTreePath(t, m, dir)
+
+	m.startDuplicateSelected()
+	m.saveDuplicateItem()
+
+	copyDir := filepath.Join(root, "projects (copy)")
+	for _, rel := range []string{"a.md", filepath.Join("sub", "b.md")} {
+		if _, err := os.Stat(filepath.Join(copyDir, rel)); err != nil {
+			t.Fatalf("expected %s copied: %v", rel, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.md")); err != nil {
+		t.Fatalf("expected original kept: %v", err)
+	}
+	if m.selectedPath() != copyDir {
+		t.Fatalf("expected copied folder selected, got %q", m.selectedPath())
+	}
+}
+
+func TestDuplicateRejectsPathsAndExistingTargets(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "plan.md")
+	mustWriteFile(t, note, "# Plan\n")
+	mustWriteFile(t, filepath.Join(root, "taken.md"), "")
+	m := newTestCRUDModel(root)
+	m.mode = modeDuplicateItem
+	m.actionPath = note
+
+	for value, status := range map[string]string{
+		"../escape.md": "Name cannot include path separators",
+		"taken":        "Target already exists",
+		"  ":           "Name is required",
+	} {
+		m.input.SetValue(value)
+		m.saveDuplicateItem()
+		if m.status != status || m.mode != modeDuplicateItem {
+			t.Fatalf("value %q: expected %q in duplicate mode, got %q (mode %v)", value, status, m.status, m.mode)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// edit_conflict.go detects when the note being edited changes on disk (a git
+// pull, another editor) and asks before Ctrl+S overwrites that change.
+//
+// startEditNote records a hash of the content it loaded (editBaseHash). On
+// save, the file is hashed again; if it differs, nothing is written and the
+// app enters modeEditConflict with three choices:
+//   - o: overwrite the file with the editor buffer anyway
+//   - r: reload the file into the editor, discarding the unsaved edits
+//   - c: keep the file and save the editor buffer to <name>.conflict.md
+//
+// Esc returns to the editor with the buffer untouched. Draft autosave only
+// writes to .cli-notes/.drafts and never to the note, so it needs no check.
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// contentHash identifies a version of a note's bytes.
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// editChangedOnDisk reports whether the note being edited differs from the
+// content startEditNote loaded. A missing baseline or a deleted file is not
+// a conflict: saving simply (re)creates the note.
+func (m *Model) editChangedOnDisk() bool {
+	if m.editBaseHash == "" || m.currentFile == "" {
+		return false
+	}
+	data, err := os.ReadFile(m.currentFile)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			appLog.Warn("check note for external changes", "path", m.currentFile, "error", err)
+		}
+		return false
+	}
+	return contentHash(data) != m.editBaseHash
+}
+
+// enterEditConflict switches to the conflict prompt, keeping the editor
+// buffer as-is.
+func (m *Model) enterEditConflict() {
+	if m.isOverlay(overlayWikiAutocomplete) {
+		m.closeOverlay()
+	}
+	m.mode = modeEditConflict
+	m.status = filepath.Base(m.currentFile) + " changed on disk since editing started"
+}
+
+// handleEditConflictKey resolves the conflict prompt.
+func (m *Model) handleEditConflictKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	switch msg.String() {
+	case "o", "O":
+		return m.writeEditedNote()
+	case "r", "R":
+		m.reloadEditedNote()
+		return m, nil
+	case "c", "C":
+		return m.saveEditConflictCopy()
+	case "esc":
+		m.mode = modeEditNote
+		m.editor.Focus()
+		m.status = "Still editing " + filepath.Base(m.currentFile) + " (not saved)"
+		return m, nil
+	default:
+		return m, nil
+	}
+}
+
+// reloadEditedNote replaces the editor buffer with the note's on-disk
+// content and resumes editing from that new baseline.
+func (m *Model) reloadEditedNote() {
+	content, raw, err := m.readNoteText(m.currentFile)
+	if err != nil {
+		if raw != nil {
+			m.reportNoteDecryptError(m.currentFile, err)
+			return
+		}
+		m.setStatusError("Error reading note", err, "path", m.currentFile)
+		return
+	}
+	m.mode = modeEditNote
+	m.clearEditorSelection()
+	m.resetEditHistory()
+	m.setEditorValue(content)
+	m.editor.Focus()
+	m.currentNoteContent = content
+	m.editBaseHash = contentHash(raw)
+	m.clearDraftForPath(m.currentFile)
+	m.status = "Reloaded " + filepath.Base(m.currentFile) + " from disk; unsaved edits discarded"
+}
+
+// saveEditConflictCopy writes the editor buffer next to the note as
+// <name>.conflict.md (or .conflict-2.md, ...) and leaves the note itself as
+// it is on disk.
+func (m *Model) saveEditConflictCopy() (tea.Model, tea.Cmd) {
+	if isEncryptedNotePath(m.currentFile) {
+		m.status = "Encrypted notes have no plain conflict copy: press o to overwrite or r to reload"
+		return m, nil
+	}
+	path := conflictCopyPath(m.currentFile)
+	content := normalizeNoteContent(m.editor.Value())
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, FilePermission)
+	if err == nil {
+		_, err = f.WriteString(content)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		m.setStatusError("Error saving conflict copy", err, "path", path)
+		return m, nil
+	}
+
+	source := m.currentFile
+	m.mode = modeBrowse
+	m.rememberNotePosition(source)
+	m.clearEditorSelection()
+	m.clearDraftForPath(source)
+	m.resetEditHistory()
+	m.editBaseHash = ""
+	m.invalidateTreeMetadataPath(source)
+	m.status = "Kept disk version; your edits saved to " + filepath.Base(path)
+	renderCmd := m.endSplitSecondaryEdit()
+	cmd := m.applyMutationEffects(mutationEffects{
+		upsertPaths:    []string{source, path},
+		refreshTree:    true,
+		refreshGit:     true,
+		setCurrentFile: m.currentFile,
+	})
+	return m, tea.Batch(renderCmd, cmd)
+}
+
+// conflictCopyPath returns the first free <stem>.conflict[-N].md path next
+// to path.
+func conflictCopyPath(path string) string {
+	dir := filepath.Dir(path)
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	for n := 1; ; n++ {
+		name := stem + ".conflict.md"
+		if n > 1 {
+			name = stem + ".conflict-" + strconv.Itoa(n) + ".md"
+		}
+		candidate := filepath.Join(dir, name)
+		if _, err := os.Lstat(candidate); errors.Is(err, fs.ErrNotExist) {
+			return candidate
+		}
+	}
+}
+
+// renderEditConflict draws the conflict prompt in the right pane.
+func (m *Model) renderEditConflict(width, height int) string {
+	lines := []string{
+		titleStyle.Render("Note Changed On Disk"),
+		"",
+		truncate(m.displayRelative(m.currentFile), width),
+		"was modified outside cli-notes after you started editing.",
+		"",
+		mutedStyle.Render("o: overwrite it with your version"),
+		mutedStyle.Render("r: reload it, discarding your edits"),
+		mutedStyle.Render("c: keep it and save your version to " + filepath.Base(conflictCopyPath(m.currentFile))),
+		mutedStyle.Render("Esc: keep editing without saving"),
+	}
+	visible := min(height, len(lines))
+	return strings.Join(lines[:visible], "\n")
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newEditConflictModel starts editing root/note.md, then changes the file on
+// disk and the editor buffer so that saving conflicts.
+func newEditConflictModel(t *testing.T) (*Model, string) {
+	t.Helper()
+	root := t.TempDir()
+	path := filepath.Join(root, "note.md")
+	mustWriteFile(t, path, "original\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.currentFile = path
+	_, _ = m.startEditNote()
+	mustWriteFile(t, path, "pulled\n")
+	m.editor.SetValue("mine\n")
+
+	_, _ = m.saveEdit()
+	if m.mode != modeEditConflict {
+		t.Fatalf("expected conflict mode, got %v (status %q)", m.mode, m.status)
+	}
+	return m, path
+}
+
+func readFileString(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestSaveEditWithoutExternalChangeWrites(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "note.md")
+	mustWriteFile(t, path, "original\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.currentFile = path
+	_, _ = m.startEditNote()
+	m.editor.SetValue("mine\n")
+	_, _ = m.saveEdit()
+
+	if m.mode != modeBrowse || readFileString(t, path) != "mine\n" {
+		t.Fatalf("expected normal save, mode %v content %q", m.mode, readFileString(t, path))
+	}
+}
+
+func TestSaveEditExternalChangeEntersConflictWithoutWriting(t *testing.T) {
+	m, path := newEditConflictModel(t)
+	if got := readFileString(t, path); got != "pulled\n" {
+		t.Fatalf("expected external change preserved, got %q", got)
+	}
+
+	_, _ = m.handleEditConflictKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeEditNote || m.editor.Value() != "mine\n" {
+		t.Fatalf("expected Esc to resume editing with buffer intact, mode %v buffer %q", m.mode, m.editor.Value())
+	}
+}
+
+func TestEditConflictOverwrite(t *testing.T) {
+	m, path := newEditConflictModel(t)
+	_, _ = m.handleEditConflictKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if m.mode != modeBrowse || readFileString(t, path) != "mine\n" {
+		t.Fatalf("expected overwrite, mode %v content %q", m.mode, readFileString(t, path))
+	}
+}
+
+func TestEditConflictReload(t *testing.T) {
+	m, path := newEditConflictModel(t)
+	_, _ = m.handleEditConflictKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if m.mode != modeEditNote || m.editor.Value() != "pulled\n" {
+		t.Fatalf("expected reloaded buffer, mode %v buffer %q", m.mode, m.editor.Value())
+	}
+
+	m.editor.SetValue("pulled\nmore\n")
+	_, _ = m.saveEdit()
+	if m.mode != modeBrowse || readFileString(t, path) != "pulled\nmore\n" {
+		t.Fatalf("expected save after reload to succeed, mode %v content %q", m.mode, readFileString(t, path))
+	}
+}
+
+func TestEditConflictSaveCopy(t *testing.T) {
+	m, path := newEditConflictModel(t)
+	mustWriteFile(t, filepath.Join(filepath.Dir(path), "note.conflict.md"), "older copy\n")
+
+	_, _ = m.handleEditConflictKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if m.mode != modeBrowse {
+		t.Fatalf("expected browse mode, got %v (status %q)", m.mode, m.status)
+	}
+	if got := readFileString(t, path); got != "pulled\n" {
+		t.Fatalf("expected disk version kept, got %q", got)
+	}
+	if got := readFileString(t, filepath.Join(filepath.Dir(path), "note.conflict-2.md")); got != "mine\n" {
+		t.Fatalf("expected edits in note.conflict-2.md, got %q", got)
+	}
+}
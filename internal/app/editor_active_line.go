@@ -0,0 +1,101 @@
+// editor_active_line.go tints the editor rows of the line holding the cursor
+// (the editor_active_line config option, on by default).
+//
+// The tint is layered onto the finished textarea view by
+// editorViewWithSelectionHighlight, after fenced-code and selection styling.
+// Those styles end in an SGR reset, so tintEditorRow re-opens the background
+// after every reset: code colors keep their foreground and selected text keeps
+// its own background on top of the tint.
+//
+// The view is scrolled, but the textarea does not expose its offset.
+// editorScrollTop mirrors it: syncEditorScroll applies the textarea's own
+// rule (scroll just far enough to keep the cursor row visible) on each render,
+// and setEditorValue resets it wherever SetValue scrolls the textarea back to
+// the top.
+package app
+
+import "strings"
+
+// sgrReset ends every style Lip Gloss renders.
+const sgrReset = "\x1b[0m"
+
+// setEditorValue replaces the editor content and resets the mirrored scroll
+// offset, since SetValue scrolls the textarea back to the top.
+func (m *Model) setEditorValue(value string) {
+	m.editor.SetValue(value)
+	m.editorScrollTop = 0
+}
+
+// syncEditorScroll brings editorScrollTop in line with the textarea's view
+// for the current size. The textarea scrolls on key updates against the rows
+// of its previous View, so it can leave the cursor off-screen after SetValue,
+// CursorEnd, or a newline on the last row. When the cursor leaves the
+// mirrored window, the textarea is re-rendered and nudged with a nil update so
+// it scrolls the same way now. A blurred textarea neither scrolls nor shows
+// the tint.
+func (m *Model) syncEditorScroll() {
+	if !m.editor.Focused() {
+		return
+	}
+	_, _, row := m.editorCursorRows()
+	height := max(1, m.editor.Height())
+	if row >= m.editorScrollTop && row < m.editorScrollTop+height {
+		return
+	}
+	_ = m.editor.View()
+	m.editor, _ = m.editor.Update(nil)
+	if row < m.editorScrollTop {
+		m.editorScrollTop = row
+	} else {
+		m.editorScrollTop = row - height + 1
+	}
+}
+
+// editorCursorRows returns the wrapped rows [first, last) of the cursor's
+// line and the row of the cursor itself, counted from the top of the note.
+func (m *Model) editorCursorRows() (first, last, cursor int) {
+	lines := splitEditorLines(m.editor.Value())
+	wrapWidth := max(1, m.editor.Width())
+	line := clamp(m.editor.Line(), 0, max(0, len(lines)-1))
+	for _, l := range lines[:line] {
+		first += len(wrapEditorLineWithSources(l, wrapWidth))
+	}
+	last = first + max(1, len(wrapEditorLineWithSources(lines[line], wrapWidth)))
+	cursor = clamp(first+m.editor.LineInfo().RowOffset, first, last-1)
+	return first, last, cursor
+}
+
+// editorActiveLineViewRows returns the view rows [first, last) to tint, or an
+// empty range when the tint is off or the editor is blurred.
+func (m *Model) editorActiveLineViewRows() (first, last int) {
+	if !m.editorActiveLine || !m.editor.Focused() {
+		return 0, 0
+	}
+	first, last, _ = m.editorCursorRows()
+	return first - m.editorScrollTop, last - m.editorScrollTop
+}
+
+// highlightEditorActiveLine tints the cursor line's rows in view.
+func (m *Model) highlightEditorActiveLine(view string) string {
+	first, last := m.editorActiveLineViewRows()
+	if first >= last {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	for row := max(0, first); row < min(last, len(lines)); row++ {
+		lines[row] = tintEditorRow(lines[row])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tintEditorRow paints the active-line background under row, re-opening it
+// after each reset inside the row. Without color support the row is returned
+// unchanged.
+func tintEditorRow(row string) string {
+	sample := editorActiveLine.Render(" ")
+	open, _, ok := strings.Cut(sample, " ")
+	if !ok || open == "" {
+		return row
+	}
+	return open + strings.ReplaceAll(row, sgrReset, sgrReset+open) + sgrReset
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// useColorProfile renders styles with colors for the rest of the test; the
+// default profile in tests is plain ASCII, which drops every background.
+func useColorProfile(t *testing.T) {
+	t.Helper()
+	old := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() { lipgloss.SetColorProfile(old) })
+}
+
+func newActiveLineModel(value string) *Model {
+	m := &Model{
+		editor:                textarea.New(),
+		editorSelectionAnchor: noEditorSelectionAnchor,
+		editorActiveLine:      true,
+	}
+	applyEditorTheme(&m.editor)
+	m.editor.Prompt = ""
+	m.editor.ShowLineNumbers = false
+	m.setEditorValue(value)
+	m.editor.Focus()
+	return m
+}
+
+func (m *Model) pressEditorKey(keyType tea.KeyType, times int) {
+	for i := 0; i < times; i++ {
+		m.editor, _ = m.editor.Update(tea.KeyMsg{Type: keyType})
+	}
+}
+
+func activeLineOpen() string {
+	open, _, _ := strings.Cut(editorActiveLine.Render(" "), " ")
+	return open
+}
+
+// tintedRows returns the indexes of view rows carrying the active-line tint.
+func tintedRows(view string) []int {
+	var rows []int
+	for i, line := range strings.Split(view, "\n") {
+		if strings.HasPrefix(line, activeLineOpen()) {
+			rows = append(rows, i)
+		}
+	}
+	return rows
+}
+
+func TestEditorActiveLineTintsCursorRow(t *testing.T) {
+	useColorProfile(t)
+	m := newActiveLineModel("one\ntwo\nthree")
+	m.pressEditorKey(tea.KeyUp, 1)
+
+	view := m.renderEditor(20, 5)
+	if got := fmt.Sprint(tintedRows(view)); got != "[1]" {
+		t.Fatalf("expected only row 1 tinted, got %s", got)
+	}
+	if row := strings.Split(view, "\n")[1]; !strings.Contains(ansi.Strip(row), "two") {
+		t.Fatalf("expected the tinted row to hold the cursor line, got %q", ansi.Strip(row))
+	}
+
+	m.editorActiveLine = false
+	if rows := tintedRows(m.renderEditor(20, 5)); len(rows) != 0 {
+		t.Fatalf("expected no tint when disabled, got rows %v", rows)
+	}
+}
+
+func TestEditorActiveLineTintsEveryWrappedRow(t *testing.T) {
+	useColorProfile(t)
+	m := newActiveLineModel("short\n" + strings.TrimSpace(strings.Repeat("word ", 8)) + "\nafter")
+	m.pressEditorKey(tea.KeyUp, 1)
+
+	if got := fmt.Sprint(tintedRows(m.renderEditor(20, 6))); got != "[1 2]" {
+		t.Fatalf("expected both wrapped rows of the cursor line tinted, got %s", got)
+	}
+}
+
+func TestEditorActiveLineFollowsScroll(t *testing.T) {
+	useColorProfile(t)
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %02d", i+1)
+	}
+	// SetValue leaves the cursor on the last line.
+	m := newActiveLineModel(strings.Join(lines, "\n"))
+
+	view := m.renderEditor(20, 5)
+	rows := strings.Split(view, "\n")
+	if got := fmt.Sprint(tintedRows(view)); got != "[4]" {
+		t.Fatalf("expected the bottom row tinted, got %s", got)
+	}
+	if !strings.Contains(ansi.Strip(rows[4]), "line 20") {
+		t.Fatalf("expected the view scrolled to the cursor, bottom row is %q", ansi.Strip(rows[4]))
+	}
+
+	m.pressEditorKey(tea.KeyUp, 6)
+	view = m.renderEditor(20, 5)
+	rows = strings.Split(view, "\n")
+	if got := fmt.Sprint(tintedRows(view)); got != "[0]" {
+		t.Fatalf("expected the top row tinted after scrolling up, got %s", got)
+	}
+	if !strings.Contains(ansi.Strip(rows[0]), "line 14") {
+		t.Fatalf("expected line 14 at the top, got %q", ansi.Strip(rows[0]))
+	}
+}
+
+func TestEditorActiveLineCoexistsWithSelectionAndCode(t *testing.T) {
+	useColorProfile(t)
+	m := newActiveLineModel("```\ncode here\n```")
+	m.pressEditorKey(tea.KeyUp, 1)
+	m.pressEditorKey(tea.KeyHome, 1)
+	m.editorSelectionAnchor = m.currentEditorCursorOffset()
+	m.editorSelectionActive = true
+	m.pressEditorKey(tea.KeyRight, 4)
+
+	view := m.renderEditor(20, 5)
+	if got := fmt.Sprint(tintedRows(view)); got != "[1]" {
+		t.Fatalf("expected the code row tinted, got %s", got)
+	}
+	row := strings.Split(view, "\n")[1]
+	selected := selectionText.Render("code")
+	if !strings.Contains(row, selected) {
+		t.Fatalf("expected the selection kept inside the tinted row, got %q", row)
+	}
+	after := row[strings.Index(row, selected)+len(selected):]
+	if !strings.HasPrefix(after, activeLineOpen()) {
+		t.Fatalf("expected the tint re-opened after the selection, got %q", after)
+	}
+}
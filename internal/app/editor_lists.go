@@ -0,0 +1,162 @@
+// editor_lists.go implements markdown list editing in edit mode.
+//
+// Enter at the end of a list item ("- a", "* a", "+ a", "1. a", "1) a", or a
+// task "- [ ] a") starts the next item with the same indentation and marker:
+// ordered numbers are incremented and new tasks start unchecked. Enter on an
+// item with no text after its marker clears the marker instead, ending the
+// list. Enter anywhere else, inside a fenced code block, or with a selection
+// behaves as a plain newline.
+//
+// Ctrl+Space (reported as ctrl+@) or Alt+D flips the task checkbox on the
+// cursor line between "[ ]" and "[x]", keeping indentation and cursor.
+package app
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// markdownListItem is the parsed prefix of a list line.
+type markdownListItem struct {
+	indent  string
+	marker  string // "-", "*", "+", or the number of an ordered item
+	delim   string // "." or ")" for ordered items
+	ordered bool
+	task    bool
+	checked bool
+	// prefixLen is the rune length of indent, marker, checkbox, and the
+	// spaces before the item text.
+	prefixLen int
+	// checkboxAt is the rune offset of the "[" of a task checkbox.
+	checkboxAt int
+}
+
+// parseMarkdownListItem recognizes a list item prefix at the start of line.
+func parseMarkdownListItem(line string) (markdownListItem, bool) {
+	runes := []rune(line)
+	i := 0
+	for i < len(runes) && (runes[i] == ' ' || runes[i] == '\t') {
+		i++
+	}
+	item := markdownListItem{indent: string(runes[:i])}
+	switch {
+	case i < len(runes) && strings.ContainsRune("-*+", runes[i]):
+		item.marker = string(runes[i])
+		i++
+	case i < len(runes) && unicode.IsDigit(runes[i]):
+		start := i
+		for i < len(runes) && unicode.IsDigit(runes[i]) {
+			i++
+		}
+		if i-start > 9 || i >= len(runes) || (runes[i] != '.' && runes[i] != ')') {
+			return markdownListItem{}, false
+		}
+		item.marker, item.delim, item.ordered = string(runes[start:i]), string(runes[i]), true
+		i++
+	default:
+		return markdownListItem{}, false
+	}
+	// A marker must be followed by a space, or end the line (an empty item).
+	if i < len(runes) && runes[i] != ' ' {
+		return markdownListItem{}, false
+	}
+	for i < len(runes) && runes[i] == ' ' {
+		i++
+	}
+	if i+2 < len(runes) && runes[i] == '[' && runes[i+2] == ']' && strings.ContainsRune(" xX", runes[i+1]) &&
+		(i+3 == len(runes) || runes[i+3] == ' ') {
+		item.task, item.checked, item.checkboxAt = true, runes[i+1] != ' ', i
+		i += 3
+		for i < len(runes) && runes[i] == ' ' {
+			i++
+		}
+	}
+	item.prefixLen = i
+	return item, true
+}
+
+// next returns the prefix that starts the following item.
+func (item markdownListItem) next() string {
+	marker := item.marker
+	if item.ordered {
+		n, _ := strconv.Atoi(item.marker)
+		marker = strconv.Itoa(n+1) + item.delim
+	}
+	prefix := item.indent + marker + " "
+	if item.task {
+		prefix += "[ ] "
+	}
+	return prefix
+}
+
+// continueMarkdownList handles Enter in the editor. It reports false when
+// the key should insert a plain newline.
+func (m *Model) continueMarkdownList() bool {
+	if _, _, ok := m.editorSelectionRange(); ok {
+		return false
+	}
+	runes := []rune(m.editor.Value())
+	value, cursor, ok := continueListAt(runes, m.currentEditorCursorOffset())
+	if !ok {
+		return false
+	}
+	m.setEditorValueAndCursorOffset(value, cursor)
+	return true
+}
+
+// continueListAt applies list continuation at cursor: it returns the new
+// value and cursor, or false when the cursor is not at the end of a list
+// item outside a code fence.
+func continueListAt(runes []rune, cursor int) (string, int, bool) {
+	lineStart, lineEnd := lineBoundsAtOffset(runes, cursor)
+	line := string(runes[lineStart:lineEnd])
+	if strings.TrimSpace(string(runes[cursor:lineEnd])) != "" || offsetInCodeFence(runes, lineStart) {
+		return "", 0, false
+	}
+	item, ok := parseMarkdownListItem(line)
+	if !ok || cursor-lineStart < item.prefixLen {
+		return "", 0, false
+	}
+	if strings.TrimSpace(string(runes[lineStart+item.prefixLen:lineEnd])) == "" {
+		// An empty item ends the list: drop its marker, keep the blank line.
+		return string(runes[:lineStart]) + string(runes[lineEnd:]), lineStart, true
+	}
+	insert := "\n" + item.next()
+	value := string(runes[:cursor]) + insert + string(runes[cursor:])
+	return value, cursor + len([]rune(insert)), true
+}
+
+// toggleEditorCheckbox flips the task checkbox on the cursor line.
+func (m *Model) toggleEditorCheckbox() {
+	runes := []rune(m.editor.Value())
+	cursor := m.currentEditorCursorOffset()
+	value, checked, ok := toggleCheckboxAt(runes, cursor)
+	if !ok {
+		m.status = "No task checkbox on this line"
+		return
+	}
+	m.setEditorValueAndCursorOffset(value, cursor)
+	if checked {
+		m.status = "Task checked"
+	} else {
+		m.status = "Task unchecked"
+	}
+}
+
+// toggleCheckboxAt flips "[ ]" and "[x]" on the line holding cursor and
+// reports whether the task is now checked.
+func toggleCheckboxAt(runes []rune, cursor int) (string, bool, bool) {
+	lineStart, lineEnd := lineBoundsAtOffset(runes, cursor)
+	item, ok := parseMarkdownListItem(string(runes[lineStart:lineEnd]))
+	if !ok || !item.task {
+		return "", false, false
+	}
+	mark := 'x'
+	if item.checked {
+		mark = ' '
+	}
+	updated := append([]rune(nil), runes...)
+	updated[lineStart+item.checkboxAt+1] = mark
+	return string(updated), !item.checked, true
+}
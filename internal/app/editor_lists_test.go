@@ -0,0 +1,85 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestContinueListAt(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"bullet", "- one", "- one\n- "},
+		{"star indented", "  * one", "  * one\n  * "},
+		{"ordered", "9. nine", "9. nine\n10. "},
+		{"ordered paren", "1) one", "1) one\n2) "},
+		{"task starts unchecked", "- [x] done", "- [x] done\n- [ ] "},
+		{"empty item ends list", "- one\n- ", "- one\n"},
+		{"empty task ends list", "- [ ] a\n  - [ ]", "- [ ] a\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runes := []rune(tt.value)
+			got, cursor, ok := continueListAt(runes, len(runes))
+			if !ok || got != tt.want {
+				t.Fatalf("continueListAt(%q) = %q, %v; want %q", tt.value, got, ok, tt.want)
+			}
+			if want := len([]rune(tt.want)); cursor != want {
+				t.Fatalf("cursor = %d, want %d", cursor, want)
+			}
+		})
+	}
+
+	for _, value := range []string{"plain text", "---", "*emphasis*", "```\n- code"} {
+		runes := []rune(value)
+		if _, _, ok := continueListAt(runes, len(runes)); ok {
+			t.Fatalf("expected no continuation for %q", value)
+		}
+	}
+	if _, _, ok := continueListAt([]rune("- one two"), 4); ok {
+		t.Fatal("expected no continuation with the cursor mid-line")
+	}
+}
+
+func TestHandleEditNoteKeyEnterContinuesList(t *testing.T) {
+	m := newFocusedEditModel("- [ ] buy milk")
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyEnter})
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("eggs")})
+	if got := m.editor.Value(); got != "- [ ] buy milk\n- [ ] eggs" {
+		t.Fatalf("unexpected value %q", got)
+	}
+
+	m = newFocusedEditModel("prose")
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.editor.Value(); got != "prose\n" {
+		t.Fatalf("expected a plain newline, got %q", got)
+	}
+}
+
+func TestToggleEditorCheckbox(t *testing.T) {
+	m := newFocusedEditModel("intro\n    - [ ] task\nafter")
+	m.setEditorValueAndCursorOffset(m.editor.Value(), strings.Index(m.editor.Value(), "task"))
+	cursor := m.currentEditorCursorOffset()
+
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyCtrlAt})
+	if got := m.editor.Value(); got != "intro\n    - [x] task\nafter" || m.status != "Task checked" {
+		t.Fatalf("unexpected value %q status %q", got, m.status)
+	}
+	if m.currentEditorCursorOffset() != cursor {
+		t.Fatalf("cursor moved to %d, want %d", m.currentEditorCursorOffset(), cursor)
+	}
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d"), Alt: true})
+	if got := m.editor.Value(); got != "intro\n    - [ ] task\nafter" || m.status != "Task unchecked" {
+		t.Fatalf("unexpected value %q status %q", got, m.status)
+	}
+
+	m = newFocusedEditModel("- plain item")
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyCtrlAt})
+	if m.editor.Value() != "- plain item" || m.status != "No task checkbox on this line" {
+		t.Fatalf("unexpected value %q status %q", m.editor.Value(), m.status)
+	}
+}
@@ -68,6 +68,9 @@ func (m *Model) handleEditMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 
 func (m *Model) editPaneContentOrigin(layout LayoutDimensions) (x, y int) {
 	x = layout.LeftWidth + editPane.GetBorderLeftSize() + editPane.GetPaddingLeft()
+	if m.splitMode && m.splitEditSecondary {
+		x += layout.RightWidth / 2
+	}
 	y = editPane.GetBorderTopSize() + editPane.GetPaddingTop() + 1 // +1 for header line
 	return x, y
 }
@@ -76,10 +79,15 @@ func (m *Model) editorOffsetFromMouse(msg tea.MouseMsg) (int, bool) {
 	layout := m.calculateLayout()
 	contentOriginX, contentOriginY := m.editPaneContentOrigin(layout)
 	paneWidth := layout.RightWidth
+	paneStartX := layout.LeftWidth
 	if m.splitMode {
 		paneWidth = paneWidth / 2
+		if m.splitEditSecondary {
+			paneStartX += paneWidth
+			paneWidth = layout.RightWidth - paneWidth
+		}
 	}
-	paneEndX := layout.LeftWidth + paneWidth
+	paneEndX := paneStartX + paneWidth
 	if msg.X < contentOriginX || msg.X >= paneEndX {
 		return 0, false
 	}
@@ -91,7 +99,7 @@ func (m *Model) editorOffsetFromMouse(msg tea.MouseMsg) (int, bool) {
 	if m.editor.ShowLineNumbers {
 		gutterWidth += len(fmt.Sprintf("%3v ", max(1, m.editor.LineCount())))
 	}
-	col := msg.X - contentOriginX - gutterWidth
+	col := msg.X - contentOriginX - gutterWidth + m.editorHScroll
 	if col < 0 {
 		col = 0
 	}
@@ -522,7 +522,7 @@ func (m *Model) setEditorValueAndCursorOffset(value string, cursorOffset int) {
 	total := utf8.RuneCountInString(value)
 	cursorOffset = clamp(cursorOffset, 0, total)
 
-	m.editor.SetValue(value)
+	m.setEditorValue(value)
 	m.editor.Focus()
 
 	movesLeft := total - cursorOffset
@@ -0,0 +1,346 @@
+package app
+
+import (
+	"strings"
+
+	rw "github.com/mattn/go-runewidth"
+)
+
+// markdownTableStarter is the 2x2 GitHub-flavored markdown table inserted by
+// Ctrl+T when the cursor is not already inside a table.
+const markdownTableStarter = "| Header | Header |\n" +
+	"| ------ | ------ |\n" +
+	"| Cell   | Cell   |\n" +
+	"| Cell   | Cell   |"
+
+// insertOrReflowMarkdownTable implements the edit-mode Ctrl+T command.
+//
+// When the cursor sits on a table line (a line beginning with "|"), the
+// surrounding table block is re-aligned with reflowMarkdownTable. Otherwise a
+// starter table is inserted on its own lines at the cursor and the cursor is
+// placed at the start of the first header cell.
+func (m *Model) insertOrReflowMarkdownTable() {
+	if m.reflowMarkdownTableAtCursor() {
+		m.status = "Aligned markdown table"
+		return
+	}
+
+	value := m.editor.Value()
+	runes := []rune(value)
+	cursor := m.currentEditorCursorOffset()
+	lineStart, lineEnd := lineBoundsAtOffset(runes, cursor)
+	prefix := ""
+	if cursor > lineStart {
+		prefix = "\n"
+	}
+	suffix := ""
+	if cursor < lineEnd {
+		suffix = "\n"
+	}
+	insert := prefix + markdownTableStarter + suffix
+
+	updated := make([]rune, 0, len(runes)+len([]rune(insert)))
+	updated = append(updated, runes[:cursor]...)
+	updated = append(updated, []rune(insert)...)
+	updated = append(updated, runes[cursor:]...)
+	// Land on the first header cell, just after the leading "| ".
+	m.setEditorValueAndCursorOffset(string(updated), cursor+len([]rune(prefix))+2)
+	m.clearEditorSelection()
+	m.status = "Inserted markdown table (Ctrl+T again to align)"
+}
+
+// formatMarkdownTableAtCursor implements the edit-mode Alt+T command: it
+// re-aligns the table under the cursor and never inserts a new one.
+func (m *Model) formatMarkdownTableAtCursor() {
+	if !m.reflowMarkdownTableAtCursor() {
+		m.status = "Cursor is not in a markdown table"
+		return
+	}
+	m.status = "Formatted markdown table"
+}
+
+// reflowMarkdownTableAtCursor re-aligns the table block containing the
+// cursor line and reports whether there was one. The cursor stays in the
+// same cell, at the same position within the cell's text.
+func (m *Model) reflowMarkdownTableAtCursor() bool {
+	lines := strings.Split(m.editor.Value(), "\n")
+	row := clamp(m.editor.Line(), 0, max(0, len(lines)-1))
+	start, end, ok := markdownTableBlockAt(lines, row)
+	if !ok {
+		return false
+	}
+	col := m.editor.LineInfo().CharOffset
+	cell := markdownTableCellAt(lines[row], col)
+	inCell := 0
+	if cell >= 0 {
+		inCell = max(0, col-markdownTableCells(lines[row])[cell].start)
+	}
+
+	reflowed := reflowMarkdownTable(lines[start:end])
+	updated := make([]string, 0, len(lines))
+	updated = append(updated, lines[:start]...)
+	updated = append(updated, reflowed...)
+	updated = append(updated, lines[end:]...)
+
+	if cells := markdownTableCells(updated[row]); cell >= 0 && cell < len(cells) {
+		text := []rune(splitMarkdownTableRow(updated[row])[cell])
+		col = cells[cell].start + min(inCell, len(text))
+	}
+	m.setEditorValueAndCursorOffset(strings.Join(updated, "\n"), lineStartOffset(updated, row)+clamp(col, 0, len([]rune(updated[row]))))
+	m.clearEditorSelection()
+	return true
+}
+
+// moveMarkdownTableCell implements Tab (delta 1) and Shift+Tab (delta -1)
+// inside a table: the cursor moves to the start of the next or previous
+// cell, wrapping across rows and skipping separator rows. It reports false
+// when the cursor line is not part of a table so the key keeps its usual
+// meaning.
+func (m *Model) moveMarkdownTableCell(delta int) bool {
+	value := m.editor.Value()
+	lines := strings.Split(value, "\n")
+	row := clamp(m.editor.Line(), 0, max(0, len(lines)-1))
+	start, end, ok := markdownTableBlockAt(lines, row)
+	if !ok {
+		return false
+	}
+	col := m.editor.LineInfo().CharOffset
+
+	type position struct{ row, col int }
+	var cells []position
+	cur, onCell := -1, false
+	for r := start; r < end; r++ {
+		if isMarkdownTableSeparator(splitMarkdownTableRow(lines[r])) {
+			continue
+		}
+		for _, cell := range markdownTableCells(lines[r]) {
+			if r < row || (r == row && cell.open <= col) {
+				cur, onCell = len(cells), r == row
+			}
+			cells = append(cells, position{r, cell.start})
+		}
+	}
+
+	target := cur + delta
+	if delta < 0 && !onCell {
+		// On a separator row or before the first pipe, the previous cell is
+		// the last one before the cursor.
+		target = cur
+	}
+	switch {
+	case target < 0:
+		m.status = "First table cell"
+		return true
+	case target >= len(cells):
+		m.status = "Last table cell"
+		return true
+	}
+	m.setEditorValueAndCursorOffset(value, lineStartOffset(lines, cells[target].row)+cells[target].col)
+	m.clearEditorSelection()
+	return true
+}
+
+// lineStartOffset returns the rune offset of the start of lines[row] in the
+// text formed by joining lines with newlines.
+func lineStartOffset(lines []string, row int) int {
+	offset := 0
+	for i := 0; i < row; i++ {
+		offset += len([]rune(lines[i])) + 1
+	}
+	return offset
+}
+
+// markdownTableBlockAt finds the contiguous block of table lines around row.
+// A table line is any line whose first non-blank character is "|". The
+// returned range is [start, end) in line indexes.
+func markdownTableBlockAt(lines []string, row int) (start, end int, ok bool) {
+	if row < 0 || row >= len(lines) || !isMarkdownTableLine(lines[row]) {
+		return 0, 0, false
+	}
+	start = row
+	for start > 0 && isMarkdownTableLine(lines[start-1]) {
+		start--
+	}
+	end = row + 1
+	for end < len(lines) && isMarkdownTableLine(lines[end]) {
+		end++
+	}
+	return start, end, true
+}
+
+func isMarkdownTableLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+// reflowMarkdownTable pads every cell of a markdown table to its column's
+// maximum display width so the pipes line up.
+//
+// Rows with fewer cells than the widest row are padded with empty cells.
+// Separator rows (cells made of dashes with optional leading/trailing colons)
+// are rebuilt with dashes filling the column width while keeping their
+// alignment colons. Escaped pipes ("\|") inside cells are preserved. The
+// indentation of the first line is applied to every line.
+func reflowMarkdownTable(lines []string) []string {
+	if len(lines) == 0 {
+		return nil
+	}
+	indent := lines[0][:len(lines[0])-len(strings.TrimLeft(lines[0], " \t"))]
+
+	rows := make([][]string, len(lines))
+	separator := make([]bool, len(lines))
+	columns := 0
+	for i, line := range lines {
+		rows[i] = splitMarkdownTableRow(line)
+		separator[i] = isMarkdownTableSeparator(rows[i])
+		columns = max(columns, len(rows[i]))
+	}
+
+	widths := make([]int, columns)
+	for i := range widths {
+		widths[i] = 3
+	}
+	for i, cells := range rows {
+		if separator[i] {
+			continue
+		}
+		for c, cell := range cells {
+			widths[c] = max(widths[c], rw.StringWidth(cell))
+		}
+	}
+
+	out := make([]string, len(lines))
+	for i, cells := range rows {
+		padded := make([]string, columns)
+		for c := 0; c < columns; c++ {
+			cell := ""
+			if c < len(cells) {
+				cell = cells[c]
+			}
+			if separator[i] {
+				padded[c] = markdownTableSeparatorCell(cell, widths[c])
+				continue
+			}
+			padded[c] = cell + strings.Repeat(" ", widths[c]-rw.StringWidth(cell))
+		}
+		out[i] = indent + "| " + strings.Join(padded, " | ") + " |"
+	}
+	return out
+}
+
+// splitMarkdownTableRow splits a table line into trimmed cell values,
+// dropping the outer pipes and honoring "\|" escapes.
+func splitMarkdownTableRow(line string) []string {
+	trimmed := strings.TrimSpace(line)
+	trimmed = strings.TrimPrefix(trimmed, "|")
+	if strings.HasSuffix(trimmed, "|") && !strings.HasSuffix(trimmed, `\|`) {
+		trimmed = strings.TrimSuffix(trimmed, "|")
+	}
+
+	cells := []string{}
+	var cell strings.Builder
+	escaped := false
+	for _, r := range trimmed {
+		switch {
+		case escaped:
+			cell.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			cell.WriteRune(r)
+			escaped = true
+		case r == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteRune(r)
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// markdownTableCell locates a cell on a table line, in rune columns: open
+// is just after the cell's opening pipe, and start is where its text begins
+// (one space in for an empty cell).
+type markdownTableCell struct {
+	open  int
+	start int
+}
+
+// markdownTableCells lists the cells of a table line, honoring "\|"
+// escapes. The closing pipe of the row does not open a cell.
+func markdownTableCells(line string) []markdownTableCell {
+	runes := []rune(line)
+	last := len(runes) - 1
+	for last >= 0 && (runes[last] == ' ' || runes[last] == '\t') {
+		last--
+	}
+	var cells []markdownTableCell
+	escaped := false
+	for i, r := range runes {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '|' && i != last:
+			j := i + 1
+			for j < len(runes) && runes[j] == ' ' {
+				j++
+			}
+			start := j
+			if j >= len(runes) || runes[j] == '|' {
+				start = min(i+2, j)
+			}
+			cells = append(cells, markdownTableCell{open: i + 1, start: start})
+		}
+	}
+	return cells
+}
+
+// markdownTableCellAt returns the index of the cell containing rune column
+// col of a table line, or -1 when col is before the first pipe.
+func markdownTableCellAt(line string, col int) int {
+	index := -1
+	for i, cell := range markdownTableCells(line) {
+		if cell.open <= col {
+			index = i
+		}
+	}
+	return index
+}
+
+// isMarkdownTableSeparator reports whether every cell is a header separator
+// such as "---", ":--", "--:", or ":-:".
+func isMarkdownTableSeparator(cells []string) bool {
+	if len(cells) == 0 {
+		return false
+	}
+	for _, cell := range cells {
+		core := strings.TrimSuffix(strings.TrimPrefix(cell, ":"), ":")
+		if core == "" || strings.Trim(core, "-") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// markdownTableSeparatorCell rebuilds a separator cell at the given width,
+// keeping its alignment colons.
+func markdownTableSeparatorCell(cell string, width int) string {
+	left := strings.HasPrefix(cell, ":")
+	right := strings.HasSuffix(cell, ":") && len(cell) > 1
+	dashes := width
+	if left {
+		dashes--
+	}
+	if right {
+		dashes--
+	}
+	out := strings.Repeat("-", max(1, dashes))
+	if left {
+		out = ":" + out
+	}
+	if right {
+		out += ":"
+	}
+	return out
+}
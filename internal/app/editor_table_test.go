@@ -0,0 +1,137 @@
+package app
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReflowMarkdownTablePadsColumns(t *testing.T) {
+	lines := []string{
+		"| Name | Qty |",
+		"|:--|--:|",
+		"| apples | 3 |",
+		"| kiwi |",
+	}
+	got := reflowMarkdownTable(lines)
+	want := []string{
+		"| Name   | Qty |",
+		"| :----- | --: |",
+		"| apples | 3   |",
+		"| kiwi   |     |",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("unexpected reflow.\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+func TestReflowMarkdownTableKeepsEscapedPipesAndIndent(t *testing.T) {
+	lines := []string{
+		"  | a \\| b | c |",
+		"  | --- | --- |",
+	}
+	got := reflowMarkdownTable(lines)
+	want := []string{
+		"  | a \\| b | c   |",
+		"  | ------ | --- |",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("unexpected reflow.\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+func TestMarkdownTableBlockAtScansAdjacentLines(t *testing.T) {
+	lines := []string{"intro", "| a | b |", "| - | - |", "| 1 | 2 |", "", "outro"}
+	start, end, ok := markdownTableBlockAt(lines, 2)
+	if !ok || start != 1 || end != 4 {
+		t.Fatalf("expected block [1,4), got [%d,%d) ok=%v", start, end, ok)
+	}
+	if _, _, ok := markdownTableBlockAt(lines, 0); ok {
+		t.Fatal("expected no table block on a prose line")
+	}
+}
+
+func TestMarkdownTableCells(t *testing.T) {
+	line := "| ab | | c \\| d |"
+	var starts []int
+	for _, cell := range markdownTableCells(line) {
+		starts = append(starts, cell.start)
+	}
+	if want := []int{2, 7, 9}; !slices.Equal(starts, want) {
+		t.Fatalf("cell starts = %v, want %v", starts, want)
+	}
+	if got := markdownTableCellAt(line, 6); got != 1 {
+		t.Fatalf("cell at column 6 = %d, want 1", got)
+	}
+	if got := markdownTableCellAt("  | a |", 1); got != -1 {
+		t.Fatalf("cell before the first pipe = %d, want -1", got)
+	}
+}
+
+func TestHandleEditNoteKeyAltTFormatsTableKeepingCell(t *testing.T) {
+	m := newFocusedEditModel("intro\n|Name|Qty|\n|:-:|--:|\n|apples|3|\n|kiwi|\nafter")
+	m.setEditorValueAndCursorOffset(m.editor.Value(), strings.Index(m.editor.Value(), "wi|"))
+
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t"), Alt: true})
+	want := "intro\n" +
+		"| Name   | Qty |\n" +
+		"| :----: | --: |\n" +
+		"| apples | 3   |\n" +
+		"| kiwi   |     |\n" +
+		"after"
+	if got := m.editor.Value(); got != want || m.status != "Formatted markdown table" {
+		t.Fatalf("unexpected format.\nwant: %q\ngot:  %q (status %q)", want, got, m.status)
+	}
+	if got, want := m.currentEditorCursorOffset(), strings.Index(want, "wi "); got != want {
+		t.Fatalf("cursor = %d, want %d (same place in the cell)", got, want)
+	}
+
+	m = newFocusedEditModel("plain text")
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t"), Alt: true})
+	if m.editor.Value() != "plain text" || m.status != "Cursor is not in a markdown table" {
+		t.Fatalf("expected no table change, got %q status %q", m.editor.Value(), m.status)
+	}
+}
+
+func TestHandleEditNoteKeyTabMovesBetweenTableCells(t *testing.T) {
+	value := "| a | b |\n| - | - |\n| c |   |"
+	m := newFocusedEditModel(value)
+	m.setEditorValueAndCursorOffset(value, 0)
+
+	var offsets []int
+	for i := 0; i < 4; i++ {
+		_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyTab})
+		offsets = append(offsets, m.currentEditorCursorOffset())
+	}
+	// a, b, then c and the empty cell on the last row (the separator row is
+	// skipped).
+	if want := []int{2, 6, 22, 26}; !slices.Equal(offsets, want) {
+		t.Fatalf("tab offsets = %v, want %v", offsets, want)
+	}
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyTab})
+	if m.currentEditorCursorOffset() != 26 || m.status != "Last table cell" {
+		t.Fatalf("expected to stay on the last cell, offset %d status %q", m.currentEditorCursorOffset(), m.status)
+	}
+	if m.editor.Value() != value {
+		t.Fatalf("tab inside a table must not edit, got %q", m.editor.Value())
+	}
+
+	m.setEditorValueAndCursorOffset(value, strings.Index(value, "- |"))
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if got := m.currentEditorCursorOffset(); got != 6 {
+		t.Fatalf("shift+tab from the separator row = %d, want 6", got)
+	}
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyShiftTab})
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.currentEditorCursorOffset() != 2 || m.status != "First table cell" {
+		t.Fatalf("expected to stop on the first cell, offset %d status %q", m.currentEditorCursorOffset(), m.status)
+	}
+
+	m = newFocusedEditModel("text")
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyTab})
+	if got := m.editor.Value(); got != "text"+strings.Repeat(" ", EditorSoftTabWidth) {
+		t.Fatalf("expected a soft tab outside tables, got %q", got)
+	}
+}
@@ -0,0 +1,111 @@
+// editor_wrap.go implements the per-note `editor_wrap: false` frontmatter
+// option, which opens a note in the editor with soft-wrap off.
+//
+// The textarea has no wrap switch, so no-wrap mode widens it until its
+// longest line fits and crops the rendered rows to the pane, keeping the
+// cursor column in view (editorHScroll). The gutter (prompt and line numbers)
+// stays fixed while the text scrolls sideways.
+package app
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	rw "github.com/mattn/go-runewidth"
+)
+
+// renderEditor sizes the editor for a width×height pane and returns its view
+// with selection highlighting, cropped horizontally in no-wrap mode.
+func (m *Model) renderEditor(width, height int) string {
+	m.editor.SetHeight(height)
+	if !m.editorNoWrap {
+		m.editor.SetWidth(width)
+		m.syncEditorScroll()
+		return m.editorViewWithSelectionHighlight(m.editor.View())
+	}
+
+	gutter := m.editorContentStartColumn()
+	// One spare column keeps the cursor on the same row at the end of the
+	// longest line.
+	m.editor.MaxWidth = 0
+	m.editor.SetWidth(max(width, gutter+longestLineWidth(m.editor.Value())+1))
+	m.syncEditorScroll()
+
+	visible := max(1, width-gutter)
+	col := m.editor.LineInfo().CharOffset
+	if col < m.editorHScroll {
+		m.editorHScroll = col
+	} else if col >= m.editorHScroll+visible {
+		m.editorHScroll = col - visible + 1
+	}
+
+	view := m.editorViewWithSelectionHighlight(m.editor.View())
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		lines[i] = ansiSliceColumns(line, 0, gutter) + ansiSliceColumns(line, gutter+m.editorHScroll, visible)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// longestLineWidth returns the display width of the widest line in value.
+func longestLineWidth(value string) int {
+	widest := 0
+	for _, line := range strings.Split(value, "\n") {
+		widest = max(widest, rw.StringWidth(line))
+	}
+	return widest
+}
+
+// ansiSliceColumns returns the cells of s in display columns
+// [start, start+width). Escape sequences are kept wherever they occur so
+// the visible cells keep their styling.
+func ansiSliceColumns(s string, start, width int) string {
+	var b strings.Builder
+	col := 0
+	end := start + width
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			n := ansiEscapeLen(s[i:])
+			b.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := rw.RuneWidth(r)
+		if col >= start && col+w <= end {
+			b.WriteRune(r)
+		}
+		col += w
+		i += size
+	}
+	return b.String()
+}
+
+// ansiEscapeLen returns the byte length of the escape sequence at the start
+// of s: CSI (ESC [ ... final byte), OSC (ESC ] ... BEL or ESC \), or a
+// two-byte escape.
+func ansiEscapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+	return len(s)
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	rw "github.com/mattn/go-runewidth"
+)
+
+func TestParseFrontmatterEditorWrap(t *testing.T) {
+	meta, _ := parseFrontmatterAndBody("---\neditor_wrap: false\n---\nbody\n")
+	if !meta.EditorNoWrap {
+		t.Fatal("expected editor_wrap: false to disable wrapping")
+	}
+	for _, value := range []string{"true", "maybe"} {
+		meta, _ = parseFrontmatterAndBody("---\neditor_wrap: " + value + "\n---\nbody\n")
+		if meta.EditorNoWrap {
+			t.Fatalf("expected editor_wrap: %s to keep wrapping", value)
+		}
+	}
+}
+
+func TestStartEditNoteWithEditorWrapFalseDisablesSoftWrap(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "data.md")
+	longRow := "| " + strings.Repeat("cell | ", 30)
+	mustWriteFile(t, path, "---\neditor_wrap: false\n---\n"+longRow+"\nshort\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.currentFile = path
+	_, _ = m.startEditNote()
+	if !m.editorNoWrap || !strings.Contains(m.status, "wrap off") {
+		t.Fatalf("expected no-wrap editor, got %v (status %q)", m.editorNoWrap, m.status)
+	}
+
+	view := m.renderEditor(40, 10)
+	if m.editor.Width() < rw.StringWidth(longRow) {
+		t.Fatalf("expected textarea wide enough for the long row, width %d", m.editor.Width())
+	}
+	lines := strings.Split(view, "\n")
+	for _, line := range lines {
+		if w := ansi.StringWidth(line); w > 40 {
+			t.Fatalf("expected rows cropped to 40 columns, got %d: %q", w, ansi.Strip(line))
+		}
+	}
+	if !strings.Contains(ansi.Strip(lines[4]), "short") {
+		t.Fatalf("expected the long row not to wrap, row 5 is %q", ansi.Strip(lines[4]))
+	}
+}
+
+func TestStartEditNoteWrapsByDefault(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "prose.md")
+	mustWriteFile(t, path, strings.Repeat("word ", 40)+"\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.currentFile = path
+	_, _ = m.startEditNote()
+	_ = m.renderEditor(40, 10)
+	if m.editorNoWrap || m.editor.Width() > 40 {
+		t.Fatalf("expected wrapping editor at pane width, got width %d", m.editor.Width())
+	}
+}
+
+func TestAnsiSliceColumnsKeepsStyles(t *testing.T) {
+	styled := "\x1b[1mabcdef\x1b[0m"
+	got := ansiSliceColumns(styled, 2, 3)
+	if ansi.Strip(got) != "cde" || !strings.HasPrefix(got, "\x1b[1m") {
+		t.Fatalf("unexpected slice %q", got)
+	}
+}
@@ -0,0 +1,180 @@
+// empty_state.go implements the getting-started panel shown in the right
+// pane of sparse workspaces.
+//
+// While the workspace holds fewer notes than empty_state_threshold and no
+// note is selected, the preview area lists concrete next steps — new note,
+// today's daily note, import, git init, and the tutorial — each with the key
+// currently bound to it. Rows are built at render time from the live
+// keybindings, so remapping (or unbinding) an action updates the panel
+// immediately, and the git row disappears once the notes directory is a
+// repository. The panel never replaces an explicitly opened note, and it goes
+// away for good once the workspace reaches the threshold.
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// errNoteCountLimit stops countNotesUpTo's walk once the limit is reached.
+var errNoteCountLimit = errors.New("note count limit reached")
+
+// emptyStateAction is one row of the getting-started panel.
+type emptyStateAction struct {
+	action string
+	label  string
+}
+
+// countNotesUpTo counts markdown notes under root, skipping the managed
+// .cli-notes directory, and stops early once limit notes have been seen.
+func countNotesUpTo(root string, limit int) int {
+	count := 0
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+		if shouldSkipManagedPath(d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && hasSuffixCaseInsensitive(d.Name(), ".md") {
+			count++
+			if count >= limit {
+				return errNoteCountLimit
+			}
+		}
+		return nil
+	})
+	return count
+}
+
+// refreshWorkspaceNoteCount recounts notes for the empty-state check. It is
+// called whenever the tree is refreshed, so creating, importing, or deleting
+// notes shows or hides the panel right away.
+func (m *Model) refreshWorkspaceNoteCount() {
+	if !m.showEmptyState || m.notesDir == "" {
+		return
+	}
+	m.workspaceNoteCount = countNotesUpTo(m.notesDir, m.emptyStateThreshold)
+}
+
+// emptyStateVisible reports whether the getting-started panel replaces the
+// "Select a note to view" placeholder.
+func (m *Model) emptyStateVisible() bool {
+	return m.showEmptyState && m.currentFile == "" && m.workspaceNoteCount < m.emptyStateThreshold
+}
+
+// emptyStateActions lists the panel rows that currently apply.
+func (m *Model) emptyStateActions() []emptyStateAction {
+	journalDir := m.journalDir
+	if journalDir == "" {
+		journalDir = DefaultJournalDir
+	}
+	actions := []emptyStateAction{
+		{actionNewNote, "Create your first note"},
+		{actionDailyNote, "Open today's daily note (" + journalDir + "/)"},
+		{actionImport, "Import notes from a folder"},
+	}
+	if !m.git.isRepo {
+		actions = append(actions, emptyStateAction{actionGitInit, "Initialize git for sync"})
+	}
+	return append(actions, emptyStateAction{actionTutorial, "Open the tutorial"})
+}
+
+// renderEmptyState draws the getting-started panel. Actions without a bound
+// key are left out.
+func (m *Model) renderEmptyState(width, height int) string {
+	noun := "notes"
+	if m.workspaceNoteCount == 1 {
+		noun = "note"
+	}
+	lines := []string{
+		titleStyle.Render("Getting started"),
+		mutedStyle.Render(fmt.Sprintf("This workspace has %d %s. Try one of these:", m.workspaceNoteCount, noun)),
+		"",
+	}
+	for _, row := range m.emptyStateActions() {
+		keys := m.actionKeyLabels(row.action)
+		if len(keys) == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  %-10s %s", keys[0], row.label))
+	}
+	lines = append(lines,
+		"",
+		mutedStyle.Render("Select a note in the tree to preview it."),
+		mutedStyle.Render(fmt.Sprintf("This panel hides once the workspace has %d notes.", m.emptyStateThreshold)),
+	)
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	return strings.Join(lines[:min(len(lines), height)], "\n")
+}
+
+// initGitRepo runs "git init" in the notes directory and ignores the managed
+// .cli-notes folder (drafts, trash, app state) unless a .gitignore exists.
+func (m *Model) initGitRepo() (tea.Model, tea.Cmd) {
+	if m.git.isRepo {
+		m.status = "Notes directory is already in a git repository"
+		return m, nil
+	}
+	if out, err := m.runGit("init"); err != nil {
+		m.setStatusError("Git init failed: "+firstLine(out), err, "path", m.notesDir)
+		return m, nil
+	}
+	gitignore := filepath.Join(m.notesDir, ".gitignore")
+	if _, err := os.Stat(gitignore); os.IsNotExist(err) {
+		if err := os.WriteFile(gitignore, []byte(managedNotesDirName+"/\n"), FilePermission); err != nil {
+			appLog.Warn("write .gitignore", "path", gitignore, "error", err)
+		}
+	}
+	m.refreshGitStatus()
+	m.status = "Initialized git repository in " + m.notesDir
+	return m, nil
+}
+
+// applyEmptyWorkspaceAction opens a workspace with no notes straight into
+// creating one, per empty_workspace_action: the new-note name prompt, or the
+// template picker (the name prompt when there are no templates). Pending
+// draft recovery takes precedence.
+func (m *Model) applyEmptyWorkspaceAction(action string) {
+	if m.mode != modeBrowse || countNotesUpTo(m.notesDir, 1) > 0 {
+		return
+	}
+	switch action {
+	case config.EmptyWorkspaceActionNewNote:
+		m.configureInputForMode(modeNewNote, "Note name (without .md extension)")
+		m.status = "Empty workspace: name your first note"
+	case config.EmptyWorkspaceActionTemplatePicker:
+		m.startNewNote()
+	}
+}
+
+// openTutorial opens the welcome note, recreating it from welcomeNote when it
+// has been deleted or renamed.
+func (m *Model) openTutorial() (tea.Model, tea.Cmd) {
+	path := filepath.Join(m.notesDir, welcomeNoteName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.WriteFile(path, []byte(normalizeNoteContent(welcomeNote)), FilePermission); err != nil {
+			m.setStatusError("Error creating tutorial note", err, "path", path)
+			return m, nil
+		}
+		_ = m.applyMutationEffects(mutationEffects{
+			upsertPaths: []string{path},
+			refreshGit:  true,
+			refreshTree: true,
+		})
+	}
+	m.rebuildTreeKeep(path)
+	m.status = "Opened tutorial: " + welcomeNoteName
+	return m, m.setFocusedFile(path)
+}
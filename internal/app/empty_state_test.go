@@ -0,0 +1,133 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func newEmptyStateModel(t *testing.T, notes int) *Model {
+	t.Helper()
+	root := t.TempDir()
+	for i := 0; i < notes; i++ {
+		mustWriteFile(t, filepath.Join(root, "n"+string(rune('a'+i))+".md"), "x\n")
+	}
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.showEmptyState = true
+	m.emptyStateThreshold = 3
+	m.loadKeybindings(config.Config{})
+	m.refreshWorkspaceNoteCount()
+	return m
+}
+
+func TestCountNotesUpToStopsAtLimitAndSkipsManagedDir(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "a\n")
+	mustWriteFile(t, filepath.Join(root, "sub", "b.md"), "b\n")
+	mustWriteFile(t, filepath.Join(root, "sub", "c.txt"), "c\n")
+	mustWriteFile(t, filepath.Join(root, managedNotesDirName, "trash", "d.md"), "d\n")
+
+	if got := countNotesUpTo(root, 10); got != 2 {
+		t.Fatalf("expected 2 notes, got %d", got)
+	}
+	if got := countNotesUpTo(root, 1); got != 1 {
+		t.Fatalf("expected count to stop at limit 1, got %d", got)
+	}
+}
+
+func TestEmptyStateVisibility(t *testing.T) {
+	m := newEmptyStateModel(t, 1)
+	if !m.emptyStateVisible() {
+		t.Fatal("expected panel in sparse workspace with nothing selected")
+	}
+
+	m.currentFile = filepath.Join(m.notesDir, "na.md")
+	if m.emptyStateVisible() {
+		t.Fatal("expected panel to stay hidden while a note is selected")
+	}
+	m.currentFile = ""
+
+	m.showEmptyState = false
+	if m.emptyStateVisible() {
+		t.Fatal("expected panel hidden when disabled")
+	}
+
+	m = newEmptyStateModel(t, 3)
+	if m.emptyStateVisible() {
+		t.Fatal("expected panel hidden once the threshold is reached")
+	}
+}
+
+func TestEmptyStateFollowsKeybindings(t *testing.T) {
+	m := newEmptyStateModel(t, 0)
+	m.loadKeybindings(config.Config{Keybindings: map[string]config.KeyList{actionImport: {"alt+o"}}})
+
+	panel := m.renderEmptyState(80, 20)
+	if !strings.Contains(panel, "Alt+O") || !strings.Contains(panel, "Import notes") {
+		t.Fatalf("expected rebound import key in panel:\n%s", panel)
+	}
+
+	delete(m.keyForAction, actionTutorial)
+	if panel := m.renderEmptyState(80, 20); strings.Contains(panel, "tutorial") {
+		t.Fatalf("expected unbound tutorial row to be hidden:\n%s", panel)
+	}
+
+	m.git.isRepo = true
+	if panel := m.renderEmptyState(80, 20); strings.Contains(panel, "Initialize git") {
+		t.Fatalf("expected git row hidden inside a repository:\n%s", panel)
+	}
+}
+
+func TestOpenTutorialRecreatesWelcomeNote(t *testing.T) {
+	m := newEmptyStateModel(t, 0)
+	_, _ = m.openTutorial()
+
+	path := filepath.Join(m.notesDir, welcomeNoteName)
+	if m.currentFile != path {
+		t.Fatalf("expected tutorial to open %q, got %q", path, m.currentFile)
+	}
+	assertTreeHasPath(t, m.items, path)
+}
+
+func TestEmptyWorkspaceOpensInNewNoteModeWithSeedingOff(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	notes := filepath.Join(home, "notes")
+	off := false
+	if err := config.Save(config.Config{NotesDir: notes, SeedWelcomeNote: &off, EmptyWorkspaceAction: "new_note"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	m, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if m.mode != modeNewNote || m.newParent != notes {
+		t.Fatalf("expected new-note mode in %q, got mode %v parent %q", notes, m.mode, m.newParent)
+	}
+	if countNotesUpTo(notes, 1) != 0 {
+		t.Fatal("expected no welcome note with seeding off")
+	}
+}
+
+func TestEmptyWorkspaceActionNeedsAnEmptyWorkspace(t *testing.T) {
+	m := newEmptyStateModel(t, 1)
+	m.applyEmptyWorkspaceAction(config.EmptyWorkspaceActionNewNote)
+	if m.mode != modeBrowse {
+		t.Fatalf("expected browse mode with notes present, got %v", m.mode)
+	}
+
+	m = newEmptyStateModel(t, 0)
+	m.applyEmptyWorkspaceAction(config.EmptyWorkspaceActionNone)
+	if m.mode != modeBrowse {
+		t.Fatalf("expected browse mode for action none, got %v", m.mode)
+	}
+	m.templatesDir = t.TempDir()
+	m.applyEmptyWorkspaceAction(config.EmptyWorkspaceActionTemplatePicker)
+	if m.mode != modeNewNote {
+		t.Fatalf("expected the name prompt without templates, got %v", m.mode)
+	}
+}
@@ -0,0 +1,485 @@
+// encryption.go implements per-note encryption at rest (Alt+E by default).
+//
+// Toggling encryption on the selected note asks for confirmation, then
+// replaces notes/secret.md with notes/secret.md.enc (and back). The plain
+// file is removed outright rather than trashed, since the trash would keep a
+// readable copy. An encrypted file is
+//
+//	EncryptedNoteMagic | 16-byte salt | 12-byte nonce | AES-256-GCM ciphertext
+//
+// with the key derived from a passphrase by scrypt (N=2^15, r=8, p=1). Every
+// write picks a fresh salt and nonce.
+//
+// The passphrase is asked once per session, when an encrypted note is first
+// opened (Enter or e on it) or encrypted, and is kept only in memory
+// (noteKeyring) along with the keys derived from it. While unlocked:
+//
+//   - the preview decrypts the note into memory and renders it directly;
+//     the result never enters renderCache,
+//   - editing loads the plain text and saving re-encrypts it; draft autosave
+//     is skipped so no plain copy lands in .cli-notes/.drafts.
+//
+// The tree marks encrypted notes with a LOCK badge. Only their file names are
+// indexed for search, and export refuses them. A wrong passphrase fails GCM
+// authentication and is reported in the status bar instead of rendering
+// garbage.
+package app
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters for note keys: the recommended interactive-login cost.
+const (
+	noteKeySize  = 32
+	noteSaltSize = 16
+	noteScryptN  = 1 << 15
+	noteScryptR  = 8
+	noteScryptP  = 1
+)
+
+var (
+	// errNoteLocked means no passphrase has been entered this session.
+	errNoteLocked = errors.New("encrypted note is locked")
+	// errWrongPassphrase means the note failed authentication: the
+	// passphrase is wrong or the file was altered.
+	errWrongPassphrase = errors.New("wrong passphrase or damaged file")
+	// errNotEncryptedNote means the file does not start with the container
+	// header.
+	errNotEncryptedNote = errors.New("not an encrypted note")
+)
+
+// isEncryptedNotePath reports whether path names an encrypted note
+// (<name>.md.enc).
+func isEncryptedNotePath(path string) bool {
+	return hasSuffixCaseInsensitive(path, ".md"+EncryptedNoteExt)
+}
+
+// noteKeyring holds the session passphrase and the keys derived from it,
+// keyed by salt so re-rendering a note does not re-run scrypt. It is never
+// persisted.
+type noteKeyring struct {
+	passphrase string
+	keys       map[string][]byte
+}
+
+func (k *noteKeyring) unlocked() bool {
+	return k.passphrase != ""
+}
+
+// set replaces the passphrase and drops keys derived from the old one.
+func (k *noteKeyring) set(passphrase string) {
+	k.passphrase = passphrase
+	k.keys = nil
+}
+
+func (k *noteKeyring) forget() {
+	k.set("")
+}
+
+func (k *noteKeyring) gcm(salt []byte) (cipher.AEAD, error) {
+	key, ok := k.keys[string(salt)]
+	if !ok {
+		var err error
+		key, err = scrypt.Key([]byte(k.passphrase), salt, noteScryptN, noteScryptR, noteScryptP, noteKeySize)
+		if err != nil {
+			return nil, err
+		}
+		if k.keys == nil {
+			k.keys = map[string][]byte{}
+		}
+		k.keys[string(salt)] = key
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plain into a new container.
+func (k *noteKeyring) seal(plain []byte) ([]byte, error) {
+	if !k.unlocked() {
+		return nil, errNoteLocked
+	}
+	salt := make([]byte, noteSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := k.gcm(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(EncryptedNoteMagic), salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plain, []byte(EncryptedNoteMagic)), nil
+}
+
+// open decrypts a container written by seal.
+func (k *noteKeyring) open(data []byte) ([]byte, error) {
+	if !k.unlocked() {
+		return nil, errNoteLocked
+	}
+	rest, ok := bytes.CutPrefix(data, []byte(EncryptedNoteMagic))
+	if !ok || len(rest) < noteSaltSize {
+		return nil, errNotEncryptedNote
+	}
+	salt, rest := rest[:noteSaltSize], rest[noteSaltSize:]
+	gcm, err := k.gcm(salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < gcm.NonceSize() {
+		return nil, errNotEncryptedNote
+	}
+	nonce, sealed := rest[:gcm.NonceSize()], rest[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, sealed, []byte(EncryptedNoteMagic))
+	if err != nil {
+		return nil, errWrongPassphrase
+	}
+	return plain, nil
+}
+
+// readNoteText returns a note's text and its bytes on disk, decrypting
+// encrypted notes with the session passphrase.
+func (m *Model) readNoteText(path string) (string, []byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	if !isEncryptedNotePath(path) {
+		return string(raw), raw, nil
+	}
+	plain, err := m.noteKeys.open(raw)
+	if err != nil {
+		return "", raw, err
+	}
+	return string(plain), raw, nil
+}
+
+// noteFileBytes returns what is written to disk for a note's text: the text
+// itself, or its encryption for encrypted notes.
+func (m *Model) noteFileBytes(path, text string) ([]byte, error) {
+	if !isEncryptedNotePath(path) {
+		return []byte(text), nil
+	}
+	return m.noteKeys.seal([]byte(text))
+}
+
+// renderEncryptedNote shows an encrypted note in the preview, decrypted in
+// memory, or a locked placeholder when it cannot be decrypted.
+func (m *Model) renderEncryptedNote(path string) {
+	m.clearRenderingState()
+	text, _, err := m.readNoteText(path)
+	if err != nil {
+		m.currentNoteContent = ""
+		m.refreshMetadataStrip()
+		m.viewport.SetContent(lockedNotePlaceholder(path))
+		m.reportNoteDecryptError(path, err)
+		return
+	}
+	m.currentNoteContent = text
+	m.refreshMetadataStrip()
+	m.viewport.SetContent(renderMarkdown(text, roundWidthToNearestBucket(m.viewport.Width)))
+	m.restorePreviewOffset(path)
+}
+
+// renderedEncryptedForPath renders an encrypted note for a split pane,
+// bypassing renderCache like renderEncryptedNote.
+func (m *Model) renderedEncryptedForPath(path string, width int) string {
+	text, _, err := m.readNoteText(path)
+	if err != nil {
+		return lockedNotePlaceholder(path)
+	}
+	return renderMarkdown(text, roundWidthToNearestBucket(width))
+}
+
+// lockedNotePlaceholder is shown instead of an encrypted note's content.
+func lockedNotePlaceholder(path string) string {
+	return "LOCKED " + filepath.Base(path) + " is encrypted.\n\n" +
+		"Press Enter (or e to edit) and type the passphrase to unlock\n" +
+		"encrypted notes for this session."
+}
+
+// reportNoteDecryptError puts a decryption failure in the status bar. A
+// wrong passphrase is forgotten so the next Enter asks again.
+func (m *Model) reportNoteDecryptError(path string, err error) {
+	switch {
+	case errors.Is(err, errNoteLocked):
+	case errors.Is(err, errWrongPassphrase):
+		m.noteKeys.forget()
+		m.status = "Wrong passphrase for " + filepath.Base(path) + "; press Enter to try again"
+	default:
+		m.setStatusError("Error decrypting note", err, "path", path)
+	}
+}
+
+// passphraseRequest is the pending passphrase prompt (modeNotePassphrase).
+type passphraseRequest struct {
+	// path is the note the passphrase is for.
+	path string
+	// verify checks the passphrase against path (an encrypted note) before
+	// accepting it. Without it, the passphrase is new and asked twice.
+	verify bool
+	// then runs once the passphrase is accepted.
+	then func() (tea.Model, tea.Cmd)
+}
+
+// withNotePassphrase runs then at once when the session is unlocked, or
+// after asking for the passphrase.
+func (m *Model) withNotePassphrase(path string, verify bool, then func() (tea.Model, tea.Cmd)) (tea.Model, tea.Cmd) {
+	if m.noteKeys.unlocked() {
+		return then()
+	}
+	m.mode = modeNotePassphrase
+	m.showHelp = false
+	m.passphrase = &passphraseRequest{path: path, verify: verify, then: then}
+	m.passphraseFirst = ""
+	m.input.Reset()
+	m.input.Placeholder = "Passphrase"
+	m.input.EchoMode = textinput.EchoPassword
+	m.input.Focus()
+	m.status = "Enter the passphrase for encrypted notes"
+	if !verify {
+		m.status = "Choose a passphrase for encrypted notes"
+	}
+	return m, nil
+}
+
+// unlockSelectedNote asks for the passphrase when the selected item is an
+// encrypted note and the session is locked, then shows the note. It reports
+// whether it took over the key.
+func (m *Model) unlockSelectedNote() bool {
+	item := m.selectedItem()
+	if item == nil || item.isDir || !isEncryptedNotePath(item.path) || m.noteKeys.unlocked() {
+		return false
+	}
+	path := item.path
+	m.withNotePassphrase(path, true, func() (tea.Model, tea.Cmd) {
+		m.status = "Unlocked encrypted notes for this session"
+		return m, m.setFocusedFile(path)
+	})
+	return true
+}
+
+// notePassphraseModeMeta returns the prompt, location, and helper lines of
+// the passphrase input.
+func (m *Model) notePassphraseModeMeta() (string, string, string) {
+	location := ""
+	if m.passphrase != nil {
+		location = "Note: " + m.displayRelative(m.passphrase.path)
+	}
+	switch {
+	case m.passphrase != nil && m.passphrase.verify:
+		return "Unlock encrypted notes", location, "Asked once per session and kept only in memory. Enter to unlock. Esc to cancel."
+	case m.passphraseFirst != "":
+		return "Repeat the new passphrase", location, "Enter to confirm. Esc to cancel."
+	default:
+		return "Choose a passphrase", location, "There is no way to recover a note if the passphrase is lost. Enter to continue. Esc to cancel."
+	}
+}
+
+// handleNotePassphraseKey routes key presses while the passphrase prompt is
+// shown.
+func (m *Model) handleNotePassphraseKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "esc" {
+		m.endNotePassphrase()
+	}
+	return m.handleInputModeKey(msg, m.submitNotePassphrase, "Passphrase entry cancelled")
+}
+
+// endNotePassphrase clears the prompt and restores the shared input.
+func (m *Model) endNotePassphrase() {
+	m.passphrase = nil
+	m.passphraseFirst = ""
+	m.input.Reset()
+	m.input.EchoMode = textinput.EchoNormal
+}
+
+// submitNotePassphrase accepts the typed passphrase: a new one must be typed
+// twice, an existing one must open the note it was asked for.
+func (m *Model) submitNotePassphrase() (tea.Model, tea.Cmd) {
+	req := m.passphrase
+	pass := m.input.Value()
+	if req == nil {
+		m.endNotePassphrase()
+		m.mode = modeBrowse
+		return m, nil
+	}
+	if pass == "" {
+		m.status = "Passphrase cannot be empty"
+		return m, nil
+	}
+	if !req.verify {
+		if m.passphraseFirst == "" {
+			m.passphraseFirst = pass
+			m.input.Reset()
+			m.status = "Repeat the passphrase to confirm"
+			return m, nil
+		}
+		if pass != m.passphraseFirst {
+			m.passphraseFirst = ""
+			m.input.Reset()
+			m.status = "Passphrases did not match; choose one again"
+			return m, nil
+		}
+	}
+	m.endNotePassphrase()
+	m.mode = modeBrowse
+
+	keys := noteKeyring{}
+	keys.set(pass)
+	if req.verify {
+		data, err := os.ReadFile(req.path)
+		if err != nil {
+			m.setStatusError("Error reading note", err, "path", req.path)
+			return m, nil
+		}
+		if _, err := keys.open(data); err != nil {
+			m.reportNoteDecryptError(req.path, err)
+			return m, nil
+		}
+	}
+	m.noteKeys = keys
+	return req.then()
+}
+
+// startToggleNoteEncryption asks to encrypt the selected note, or to decrypt
+// it when it is already encrypted.
+func (m *Model) startToggleNoteEncryption() (tea.Model, tea.Cmd) {
+	item := m.selectedItem()
+	if item == nil || item.isDir {
+		m.status = "Select a note to encrypt or decrypt"
+		return m, nil
+	}
+	if !isWithinRoot(m.notesDir, item.path) {
+		m.status = "Cannot encrypt items outside notes directory"
+		return m, nil
+	}
+	path := item.path
+	name := filepath.Base(path)
+	if isEncryptedNotePath(path) {
+		m.askConfirm(confirmPrompt{
+			question:     "Decrypt " + name + " back to a plain note on disk? (y/n)",
+			yesHint:      "decrypt",
+			noHint:       "keep encrypted",
+			cancelStatus: "Decrypt cancelled",
+			returnMode:   modeBrowse,
+			onConfirm: func() (tea.Model, tea.Cmd) {
+				return m.withNotePassphrase(path, true, func() (tea.Model, tea.Cmd) { return m.decryptNoteFile(path) })
+			},
+		})
+		return m, nil
+	}
+	if !hasSuffixCaseInsensitive(path, ".md") {
+		m.status = "Only markdown notes can be encrypted"
+		return m, nil
+	}
+	m.askConfirm(confirmPrompt{
+		question:     "Encrypt " + name + "? The plain file is deleted, not trashed (y/n)",
+		yesHint:      "encrypt",
+		noHint:       "cancel",
+		cancelStatus: "Encrypt cancelled",
+		returnMode:   modeBrowse,
+		onConfirm: func() (tea.Model, tea.Cmd) {
+			return m.withNotePassphrase(path, false, func() (tea.Model, tea.Cmd) { return m.encryptNoteFile(path) })
+		},
+	})
+	return m, nil
+}
+
+// encryptNoteFile replaces the plain note at path with <path>.enc.
+func (m *Model) encryptNoteFile(path string) (tea.Model, tea.Cmd) {
+	plain, err := os.ReadFile(path)
+	if err != nil {
+		m.setStatusError("Error reading note", err, "path", path)
+		return m, nil
+	}
+	sealed, err := m.noteKeys.seal(plain)
+	if err != nil {
+		m.setStatusError("Error encrypting note", err, "path", path)
+		return m, nil
+	}
+	return m.replaceNoteFile(path, path+EncryptedNoteExt, sealed, "Encrypted "+filepath.Base(path))
+}
+
+// decryptNoteFile replaces the encrypted note at path with the plain note.
+func (m *Model) decryptNoteFile(path string) (tea.Model, tea.Cmd) {
+	text, _, err := m.readNoteText(path)
+	if err != nil {
+		m.reportNoteDecryptError(path, err)
+		return m, nil
+	}
+	target := path[:len(path)-len(EncryptedNoteExt)]
+	return m.replaceNoteFile(path, target, []byte(text), "Decrypted "+filepath.Base(target))
+}
+
+// replaceNoteFile writes data to a new file at target, removes oldPath, and
+// moves every reference to the note over to target.
+func (m *Model) replaceNoteFile(oldPath, target string, data []byte, status string) (tea.Model, tea.Cmd) {
+	if err := createNoteFile(target, data); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			m.status = filepath.Base(target) + " already exists"
+			return m, nil
+		}
+		m.setStatusError("Error writing note", err, "path", target)
+		return m, nil
+	}
+	if err := os.Remove(oldPath); err != nil {
+		_ = os.Remove(target)
+		m.setStatusError("Error removing note", err, "path", oldPath)
+		return m, nil
+	}
+
+	delete(m.renderCache, oldPath)
+	m.clearDraftForPath(oldPath)
+	m.remapStatePaths(oldPath, target)
+	m.remapTreeMetadataPath(oldPath, target)
+	if m.secondaryFile == oldPath {
+		m.secondaryFile = target
+	}
+	cmd := m.applyMutationEffects(mutationEffects{
+		removePaths:     []string{oldPath},
+		upsertPaths:     []string{target},
+		refreshGit:      true,
+		refreshTree:     true,
+		saveState:       true,
+		rebuildKeepPath: target,
+	})
+	m.status = status
+	if m.currentFile == oldPath {
+		m.currentFile = target
+		return m, tea.Batch(cmd, m.setCurrentFile(target))
+	}
+	return m, cmd
+}
+
+// createNoteFile writes data to path, failing if path already exists.
+func createNoteFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, FilePermission)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNoteKeyringRoundTrip(t *testing.T) {
+	keys := noteKeyring{}
+	keys.set("correct horse")
+
+	sealed, err := keys.seal([]byte("# Secret\n"))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if !bytes.HasPrefix(sealed, []byte(EncryptedNoteMagic)) || bytes.Contains(sealed, []byte("Secret")) {
+		t.Fatalf("unexpected container %q", sealed)
+	}
+	plain, err := keys.open(sealed)
+	if err != nil || string(plain) != "# Secret\n" {
+		t.Fatalf("open = %q, %v", plain, err)
+	}
+
+	wrong := noteKeyring{}
+	wrong.set("battery staple")
+	if _, err := wrong.open(sealed); !errors.Is(err, errWrongPassphrase) {
+		t.Fatalf("expected errWrongPassphrase, got %v", err)
+	}
+	if _, err := (&noteKeyring{}).open(sealed); !errors.Is(err, errNoteLocked) {
+		t.Fatalf("expected errNoteLocked, got %v", err)
+	}
+}
+
+func TestToggleEncryptionReplacesPlainNote(t *testing.T) {
+	root := t.TempDir()
+	plainPath := filepath.Join(root, "secret.md")
+	mustWriteFile(t, plainPath, "# Secret\n\nlaunch codes\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	selectTreePath(t, m, plainPath)
+
+	_, _ = m.startToggleNoteEncryption()
+	_, _ = m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m.mode != modeNotePassphrase {
+		t.Fatalf("expected passphrase prompt, got mode %v", m.mode)
+	}
+	m.input.SetValue("pw")
+	_, _ = m.submitNotePassphrase()
+	m.input.SetValue("pw")
+	_, _ = m.submitNotePassphrase()
+
+	encPath := plainPath + EncryptedNoteExt
+	if _, err := os.Stat(plainPath); !os.IsNotExist(err) {
+		t.Fatalf("expected plain note to be removed, stat err = %v", err)
+	}
+	data, err := os.ReadFile(encPath)
+	if err != nil {
+		t.Fatalf("read encrypted note: %v", err)
+	}
+	if bytes.Contains(data, []byte("launch codes")) {
+		t.Fatal("encrypted note contains plain text")
+	}
+	if m.mode != modeBrowse || m.input.EchoMode != 0 {
+		t.Fatalf("expected browse mode with normal echo, got mode %v echo %v", m.mode, m.input.EchoMode)
+	}
+	assertTreeHasPath(t, m.items, encPath)
+	if rows := m.searchIndex.search("launch"); len(rows) != 0 {
+		t.Fatalf("expected encrypted body to stay out of search, got %d rows", len(rows))
+	}
+}
+
+func TestLockedEncryptedNotePreviewShowsPlaceholder(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "secret.md.enc")
+	writeEncryptedNote(t, path, "pw", "# Secret\n")
+	m := newTestCRUDModel(root)
+	m.viewport = viewport.New(60, 10)
+
+	m.renderEncryptedNote(path)
+	if !strings.Contains(m.viewport.View(), "LOCKED") || m.currentNoteContent != "" {
+		t.Fatalf("expected locked placeholder, got %q", m.viewport.View())
+	}
+
+	m.noteKeys.set("pw")
+	m.renderEncryptedNote(path)
+	if m.currentNoteContent != "# Secret\n" {
+		t.Fatalf("currentNoteContent = %q", m.currentNoteContent)
+	}
+	if len(m.renderCache) != 0 {
+		t.Fatal("expected decrypted render to bypass the render cache")
+	}
+
+	m.noteKeys.set("nope")
+	m.renderEncryptedNote(path)
+	if m.noteKeys.unlocked() || !strings.HasPrefix(m.status, "Wrong passphrase") {
+		t.Fatalf("expected wrong passphrase to be forgotten, status %q", m.status)
+	}
+}
+
+func TestSavingEncryptedNoteReencrypts(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "secret.md.enc")
+	writeEncryptedNote(t, path, "pw", "# Secret\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.currentFile = path
+	m.noteKeys.set("pw")
+
+	_, _ = m.startEditNote()
+	if m.mode != modeEditNote || m.editor.Value() != "# Secret\n" {
+		t.Fatalf("expected decrypted editor, got mode %v value %q", m.mode, m.editor.Value())
+	}
+	m.setEditorValue("# Secret\n\nupdated\n")
+	_, _ = m.saveEdit()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read note: %v", err)
+	}
+	if bytes.Contains(data, []byte("updated")) {
+		t.Fatal("saved note contains plain text")
+	}
+	plain, err := m.noteKeys.open(data)
+	if err != nil || string(plain) != "# Secret\n\nupdated\n" {
+		t.Fatalf("open saved note = %q, %v", plain, err)
+	}
+	if err := m.saveDraftForCurrentFile(); err != nil {
+		t.Fatalf("saveDraftForCurrentFile: %v", err)
+	}
+	if _, err := os.Stat(m.draftsDir()); !os.IsNotExist(err) {
+		t.Fatalf("expected no draft for encrypted note, stat err = %v", err)
+	}
+}
+
+func writeEncryptedNote(t *testing.T, path, passphrase, text string) {
+	t.Helper()
+	keys := noteKeyring{}
+	keys.set(passphrase)
+	data, err := keys.seal([]byte(text))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write encrypted note: %v", err)
+	}
+}
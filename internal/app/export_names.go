@@ -0,0 +1,178 @@
+// export_names.go derives the file names of exported notes. Every exporter
+// goes through it: the single-note exports written beside the note (HTML,
+// PDF, plain text, markdown copy), "Folder to HTML", and the single-document
+// folder exports.
+//
+// A name is built from the note's path relative to the exported folder. With
+// export_layout "mirror" (the default) the folders are kept; with "flatten"
+// they are joined into the file name with "—" (a/b/Plan.md →
+// a—b—Plan.html). Each part is made safe on every platform: characters
+// Windows rejects and control characters become "_", trailing dots and
+// spaces are dropped, reserved device names get a "_" (CON.md →
+// CON_.html), and names are cut, at a character boundary, to the 255-byte
+// limit common to filesystems. Characters that are legal in file names but
+// special in URLs ("#", "%", spaces) are kept; links to exported pages are
+// percent-encoded when they are written (escapeExportHref in
+// folder_export.go).
+//
+// exportNamer hands out the names of one folder export. Names are compared
+// case-insensitively, so Plan.md and plan.md cannot overwrite each other on
+// macOS or Windows either; a clash gets a numeric suffix (Plan-2.html) in
+// the order the notes are exported, and is recorded so the export summary
+// can report it.
+package app
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// exportFlattenSeparator joins folder names in flattened export names.
+const exportFlattenSeparator = "—"
+
+// maxExportNameBytes is the longest file or folder name written by an
+// export, the per-name limit of common filesystems.
+const maxExportNameBytes = 255
+
+// windowsReservedNames are the device names Windows refuses as file names,
+// with or without an extension.
+var windowsReservedNames = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true, "com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true, "lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// exportRename records a note whose export name had to change to avoid
+// overwriting another.
+type exportRename struct {
+	rel  string // source path relative to the exported folder
+	want string // name derived from the path
+	got  string // name written
+}
+
+// exportNamer assigns unique export names within one output directory.
+type exportNamer struct {
+	layout  string
+	used    map[string]bool // case-folded names already taken
+	renamed []exportRename
+}
+
+// newExportNamer returns a namer for layout (config.ExportLayoutMirror or
+// config.ExportLayoutFlatten) with the given slash-separated names already
+// taken, such as a generated index.html.
+func newExportNamer(layout string, reserved ...string) *exportNamer {
+	n := &exportNamer{layout: layout, used: map[string]bool{}}
+	for _, name := range reserved {
+		n.used[strings.ToLower(name)] = true
+	}
+	return n
+}
+
+// name returns the slash-separated output name, ending in ext, for the note
+// at rel (relative to the exported folder), adding a numeric suffix when the
+// name is already taken.
+func (n *exportNamer) name(rel, ext string) string {
+	want := exportFileName(rel, n.layout, ext)
+	dir, file := path.Split(want)
+	stem := strings.TrimSuffix(file, ext)
+	got := want
+	for k := 2; n.used[strings.ToLower(got)]; k++ {
+		suffix := fmt.Sprintf("-%d", k)
+		got = dir + truncateExportName(stem, maxExportNameBytes-len(suffix)-len(ext)) + suffix + ext
+	}
+	n.used[strings.ToLower(got)] = true
+	if got != want {
+		n.renamed = append(n.renamed, exportRename{rel: filepath.ToSlash(rel), want: want, got: got})
+	}
+	return got
+}
+
+// exportFileName derives the slash-separated output name, ending in ext,
+// for the note at rel (relative to the exported folder) without checking
+// for clashes.
+func exportFileName(rel, layout, ext string) string {
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(rel)), "/")
+	last := len(parts) - 1
+	parts[last] = strings.TrimSuffix(parts[last], path.Ext(parts[last]))
+	for i, part := range parts {
+		parts[i] = sanitizeExportName(part)
+	}
+	if layout == config.ExportLayoutFlatten {
+		return truncateExportName(strings.Join(parts, exportFlattenSeparator), maxExportNameBytes-len(ext)) + ext
+	}
+	for i := range parts[:last] {
+		parts[i] = truncateExportName(parts[i], maxExportNameBytes)
+	}
+	parts[last] = truncateExportName(parts[last], maxExportNameBytes-len(ext)) + ext
+	return strings.Join(parts, "/")
+}
+
+// noteExportPath returns where a single-note export with the given suffix
+// (".html", ".export.md", ...) is written: beside the note, named after it.
+func noteExportPath(notePath, suffix string) string {
+	return filepath.Join(filepath.Dir(notePath), exportFileName(filepath.Base(notePath), config.ExportLayoutMirror, suffix))
+}
+
+// folderExportPath returns where a single-document export of folder dir
+// with the given suffix (".html", ".pdf") is written: beside the folder,
+// named after it.
+func folderExportPath(dir, suffix string) string {
+	name := truncateExportName(sanitizeExportName(filepath.Base(dir)), maxExportNameBytes-len(suffix))
+	return filepath.Join(filepath.Dir(dir), name+suffix)
+}
+
+// sanitizeExportName makes one file or folder name safe on every platform.
+func sanitizeExportName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	name = strings.TrimRight(name, ". ")
+	if name == "" {
+		return "note"
+	}
+	stem, rest, dotted := strings.Cut(name, ".")
+	if windowsReservedNames[strings.ToLower(strings.TrimSpace(stem))] {
+		name = stem + "_"
+		if dotted {
+			name += "." + rest
+		}
+	}
+	return name
+}
+
+// truncateExportName cuts name to at most limit bytes without splitting a
+// character, dropping any trailing dots or spaces the cut exposes.
+func truncateExportName(name string, limit int) string {
+	if len(name) <= limit {
+		return name
+	}
+	cut := max(0, limit)
+	for cut > 0 && !utf8.RuneStart(name[cut]) {
+		cut--
+	}
+	if out := strings.TrimRight(name[:cut], ". "); out != "" {
+		return out
+	}
+	return "note"
+}
+
+// exportRenameSummary describes the renames of a folder export for the
+// status bar, or "" when there were none.
+func exportRenameSummary(renamed []exportRename) string {
+	switch len(renamed) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("; renamed %s to %s to avoid a name clash", renamed[0].rel, renamed[0].got)
+	}
+	return fmt.Sprintf("; renamed %d notes to avoid name clashes (first: %s to %s)", len(renamed), renamed[0].rel, renamed[0].got)
+}
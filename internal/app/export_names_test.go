@@ -0,0 +1,154 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func TestExportNamerPinsNamesForTrickyPaths(t *testing.T) {
+	long126 := strings.Repeat("é", 126) // 252 bytes
+	cases := []struct {
+		rel, mirror, flatten string
+	}{
+		{"Plan.md", "Plan.html", "Plan.html"},
+		{"plan.md", "plan-2.html", "plan-2.html"},
+		{"index.md", "index-2.html", "index-2.html"},
+		{"a/Plan.md", "a/Plan.html", "a—Plan.html"},
+		{"b/Plan.md", "b/Plan.html", "b—Plan.html"},
+		{"a/b/Plan.md", "a/b/Plan.html", "a—b—Plan.html"},
+		{"a—b/Plan.md", "a—b/Plan.html", "a—b—Plan-2.html"},
+		{"Café ☕.md", "Café ☕.html", "Café ☕.html"},
+		{"What? *now*.md", "What_ _now_.html", "What_ _now_.html"},
+		{"C# notes.md", "C# notes.html", "C# notes.html"},
+		{"a/100% done.md", "a/100% done.html", "a—100% done.html"},
+		{"tab\there.md", "tab_here.html", "tab_here.html"},
+		{"dots...md", "dots.html", "dots.html"},
+		{"CON.md", "CON_.html", "CON_.html"},
+		{"aux.notes.md", "aux_.notes.html", "aux_.notes.html"},
+		{" trailing. /n.md", "trailing/n.html", "trailing—n.html"},
+		{long126 + ".md", strings.Repeat("é", 125) + ".html", strings.Repeat("é", 125) + ".html"},
+		{strings.Repeat("é", 125) + "x.md", strings.Repeat("é", 124) + "-2.html", strings.Repeat("é", 124) + "-2.html"},
+		{"deep/" + long126 + ".md", "deep/" + strings.Repeat("é", 125) + ".html", "deep—" + strings.Repeat("é", 121) + ".html"},
+	}
+
+	for _, layout := range []string{config.ExportLayoutMirror, config.ExportLayoutFlatten} {
+		namer := newExportNamer(layout, "index.html")
+		for _, tc := range cases {
+			want := tc.mirror
+			if layout == config.ExportLayoutFlatten {
+				want = tc.flatten
+			}
+			got := namer.name(filepath.FromSlash(tc.rel), ".html")
+			if got != want {
+				t.Fatalf("%s: name(%q) = %q, want %q", layout, tc.rel, got, want)
+			}
+			for _, part := range strings.Split(got, "/") {
+				if len(part) > maxExportNameBytes {
+					t.Fatalf("%s: %q exceeds the name limit", layout, part)
+				}
+			}
+		}
+		wantRenamed := 3
+		if layout == config.ExportLayoutFlatten {
+			wantRenamed = 4
+		}
+		if len(namer.renamed) != wantRenamed || namer.renamed[0].rel != "plan.md" || namer.renamed[0].want != "plan.html" {
+			t.Fatalf("%s: unexpected renames %+v", layout, namer.renamed)
+		}
+	}
+}
+
+func TestExportHrefsEscapeNamesKeptByTheNamer(t *testing.T) {
+	for _, layout := range []string{config.ExportLayoutMirror, config.ExportLayoutFlatten} {
+		namer := newExportNamer(layout, "index.html")
+		cases := map[string]string{
+			"C# notes.md":     "C%23%20notes.html",
+			"why?.md":         "why_.html",
+			"odd/100% id.md":  "odd/100%25%20id.html",
+			"time: 10:30.md":  "time_%2010_30.html",
+			"plain/simple.md": "plain/simple.html",
+		}
+		if layout == config.ExportLayoutFlatten {
+			cases["odd/100% id.md"] = "odd%E2%80%94100%25%20id.html"
+			cases["plain/simple.md"] = "plain%E2%80%94simple.html"
+		}
+		for rel, want := range cases {
+			got := escapeExportHref(namer.name(filepath.FromSlash(rel), ".html"))
+			if got != want {
+				t.Fatalf("%s: href for %q = %q, want %q", layout, rel, got, want)
+			}
+		}
+	}
+	if got := escapeExportHref("a:b/c.html"); got != "./a:b/c.html" {
+		t.Fatalf("expected a scheme-like first segment to be prefixed, got %q", got)
+	}
+	if got := relativeExportHref("odd dir/x.html", "C# notes.html"); got != "../C%23%20notes.html" {
+		t.Fatalf("unexpected relative href %q", got)
+	}
+}
+
+func TestSingleExportPathsShareSanitizedNames(t *testing.T) {
+	dir := filepath.Join("notes", "work")
+	for suffix, want := range map[string]string{
+		".html":      "What_ now.html",
+		".pdf":       "What_ now.pdf",
+		".txt":       "What_ now.txt",
+		".export.md": "What_ now.export.md",
+	} {
+		if got := noteExportPath(filepath.Join(dir, "What? now.md"), suffix); got != filepath.Join(dir, want) {
+			t.Fatalf("noteExportPath(%s) = %q, want %q", suffix, got, want)
+		}
+	}
+	if got := folderExportPath(filepath.Join("notes", "notes.v2"), ".pdf"); got != filepath.Join("notes", "notes.v2.pdf") {
+		t.Fatalf("expected the folder name kept whole, got %q", got)
+	}
+	if got := folderExportPath(filepath.Join("notes", "nul"), ".html"); got != filepath.Join("notes", "nul_.html") {
+		t.Fatalf("expected a reserved folder name escaped, got %q", got)
+	}
+}
+
+func TestExportFolderHTMLFlattensAndReportsClashes(t *testing.T) {
+	root := t.TempDir()
+	out := filepath.Join(t.TempDir(), "site")
+	mustWriteFile(t, filepath.Join(root, "a", "Plan.md"), "# A plan\n\nSee [[B plan]].\n")
+	mustWriteFile(t, filepath.Join(root, "b", "Plan.md"), "---\ntitle: B plan\n---\nBody\n")
+	mustWriteFile(t, filepath.Join(root, "index.md"), "# Start\n\n[a](a/Plan.md)\n")
+	mustWriteFile(t, filepath.Join(root, "plan.md"), "# Top\n")
+	mustWriteFile(t, filepath.Join(root, "Plan.md"), "# Top again\n")
+
+	count, renamed, err := exportFolderHTML(root, out, exportStyleBlock(""), config.ExportLayoutFlatten, nil)
+	if err != nil || count != 5 {
+		t.Fatalf("export: %d %v", count, err)
+	}
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	if got := strings.Join(names, ","); got != "Plan.html,a—Plan.html,b—Plan.html,index-2.html,index.html,plan-2.html" {
+		t.Fatalf("unexpected output files %s", got)
+	}
+	if page := readExported(t, filepath.Join(out, "a—Plan.html")); !strings.Contains(page, `<a href="b%E2%80%94Plan.html">B plan</a>`) || !strings.Contains(page, `<a href="index.html">Index</a>`) {
+		t.Fatalf("expected links between flattened pages:\n%s", page)
+	}
+	if page := readExported(t, filepath.Join(out, "index-2.html")); !strings.Contains(page, `<a href="a%E2%80%94Plan.html">a</a>`) {
+		t.Fatalf("expected the renamed index note to link to flattened pages:\n%s", page)
+	}
+
+	m := newTestCRUDModel(root)
+	job := &folderExportJob{}
+	m.folderExport = job
+	m.handleFolderExportDone(folderExportDoneMsg{job: job, count: count, outDir: out, renamed: renamed})
+	if want := "Exported 5 notes to " + displayHomePath(out) + "; renamed 2 notes to avoid name clashes (first: index.md to index-2.html)"; m.status != want {
+		t.Fatalf("expected the clashes in the summary, got %q", m.status)
+	}
+}
@@ -0,0 +1,67 @@
+// export_style.go holds the stylesheet of exported HTML pages (single-note
+// HTML, "Folder to HTML", and "Folder to single HTML"). The contents of
+// export_css_file are inlined into each page's <style> block so the output
+// stays self-contained; when it is unset, or cannot be read, the built-in
+// defaultExportCSS is used instead and the failure is logged.
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"os"
+	"strings"
+)
+
+// defaultExportCSS is the built-in stylesheet of exported pages: a readable
+// single column with styled code, quotes, and tables, following the reader's
+// light or dark preference.
+const defaultExportCSS = `:root { color-scheme: light dark; --fg: #1f2328; --bg: #ffffff; --muted: #59636e; --code: #f3f4f6; --border: #d1d9e0; --link: #0969da; }
+@media (prefers-color-scheme: dark) { :root { --fg: #e6edf3; --bg: #0d1117; --muted: #9198a1; --code: #161b22; --border: #3d444d; --link: #4493f8; } }
+body { max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: var(--fg); background: var(--bg); font-family: system-ui, -apple-system, "Segoe UI", sans-serif; line-height: 1.6; }
+h1, h2, h3, h4, h5, h6 { line-height: 1.25; margin: 1.5em 0 .5em; }
+h1, h2 { border-bottom: 1px solid var(--border); padding-bottom: .3em; }
+a { color: var(--link); }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: .9em; background: var(--code); border-radius: 4px; }
+code { padding: .1em .3em; }
+pre { overflow-x: auto; padding: .75rem 1rem; }
+pre code { padding: 0; background: none; }
+blockquote { margin: 1em 0; padding: 0 1em; color: var(--muted); border-left: 4px solid var(--border); }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid var(--border); padding: .4em .8em; }
+img { max-width: 100%; }
+hr { border: 0; border-top: 1px solid var(--border); }
+nav { margin-bottom: 1rem; }
+`
+
+// exportStyleBlock returns the <style> element for exported pages with the
+// contents of cssFile, or defaultExportCSS when cssFile is empty or cannot
+// be read.
+func exportStyleBlock(cssFile string) string {
+	css := defaultExportCSS
+	if cssFile != "" {
+		data, err := os.ReadFile(cssFile)
+		if err != nil {
+			appLog.Warn("read export css; using the built-in stylesheet", "path", cssFile, "error", err)
+		} else {
+			css = string(data)
+		}
+	}
+	return "<style>\n" + strings.TrimRight(css, "\n") + "\n</style>\n"
+}
+
+// writeExportHead writes the start of an exported page through <body>: the
+// title, the note's frontmatter as <meta> tags, and the style block.
+func writeExportHead(b *bytes.Buffer, title string, meta NoteMetadata, style string) {
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	fmt.Fprintf(b, "<title>%s</title>\n", html.EscapeString(title))
+	writeExportMeta(b, "date", meta.Date)
+	writeExportMeta(b, "category", meta.Category)
+	writeExportMeta(b, "keywords", strings.Join(meta.Tags, ", "))
+	for _, field := range meta.Extra {
+		writeExportMeta(b, field.Key, field.Value)
+	}
+	b.WriteString(style)
+	b.WriteString("</head>\n<body>\n")
+}
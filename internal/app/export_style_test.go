@@ -0,0 +1,49 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportNoteHTMLWrapsBodyInStyledDocument(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "plan.md")
+	css := filepath.Join(t.TempDir(), "brand.css")
+	mustWriteFile(t, note, "---\ntitle: Launch Plan\ntags: [work]\n---\nShip **it**.\n")
+	mustWriteFile(t, css, "body { color: rebeccapurple; }\n")
+	m := newTestCRUDModel(root)
+	m.currentFile = note
+	m.exportCSSFile = css
+
+	m.Update(m.exportCurrentNoteHTML()())
+	page := readExported(t, filepath.Join(root, "plan.html"))
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>Launch Plan</title>",
+		`<meta name="keywords" content="work">`,
+		"<style>\nbody { color: rebeccapurple; }\n</style>\n</head>\n<body>\n<h1>Launch Plan</h1>\n<p>Ship <strong>it</strong>.</p>",
+		"</body>\n</html>\n",
+	} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("expected %q in page:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "tags:") || strings.Contains(page, "--link") {
+		t.Fatalf("expected no frontmatter in the body and only the custom css:\n%s", page)
+	}
+}
+
+func TestExportStyleFallsBackToDefaultWhenCSSMissing(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.css")
+	var style string
+	logs := captureLogOutput(t, func() {
+		style = exportStyleBlock(missing)
+	})
+	if style != exportStyleBlock("") || !strings.Contains(style, "max-width: 48rem") {
+		t.Fatalf("expected the built-in stylesheet, got:\n%s", style)
+	}
+	if !strings.Contains(string(logs), "level=WARN") || !strings.Contains(string(logs), "missing.css") {
+		t.Fatalf("expected a warning naming the css file, got:\n%s", logs)
+	}
+}
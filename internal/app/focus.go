@@ -0,0 +1,278 @@
+// focus.go implements time-boxed focus sessions (`F`), a pomodoro-style
+// timer shown in the footer.
+//
+// Starting a session binds it to the current note, if any, and records that
+// note's word count. The footer shows the remaining minutes; a focusTickMsg
+// fires at most every FocusTickInterval (and exactly at the end), so the
+// timer never forces per-second redraws. `F` pauses and resumes, Alt+F
+// cancels. When a focus session ends the app reports it (optionally ringing
+// the terminal bell), appends it to the workspace's focus history in
+// state.json, and offers a break: the next `F` starts a break_minutes timer.
+//
+// Quitting during a focus session asks before abandoning it. Only completed
+// focus sessions are recorded; the stats popup sums this week's.
+package app
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// focusKind distinguishes focus sessions from the breaks between them.
+type focusKind int
+
+const (
+	focusWork focusKind = iota
+	focusBreak
+)
+
+// focusSession is the running or paused timer.
+type focusSession struct {
+	kind       focusKind
+	notePath   string // bound note, "" when none was selected
+	startedAt  time.Time
+	length     time.Duration
+	endsAt     time.Time     // valid while running
+	remaining  time.Duration // valid while paused
+	paused     bool
+	startWords int
+}
+
+// focusRecord is one completed focus session in the workspace history.
+type focusRecord struct {
+	NotePath   string
+	StartedAt  time.Time
+	Duration   time.Duration
+	WordsAdded int
+}
+
+// focusTickMsg re-checks the timer. Ticks from cancelled or paused timers
+// carry a stale seq and are ignored.
+type focusTickMsg struct {
+	seq int
+}
+
+// timeLeft returns the remaining time at now.
+func (s *focusSession) timeLeft(now time.Time) time.Duration {
+	if s.paused {
+		return s.remaining
+	}
+	return max(0, s.endsAt.Sub(now))
+}
+
+// formatFocusDuration renders a duration in whole minutes, rounding up so
+// a timer never shows 0m while running.
+func formatFocusDuration(d time.Duration) string {
+	if d <= 0 {
+		return "0m"
+	}
+	minutes := int((d + time.Minute - 1) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+// toggleFocusSession starts a session (a break if one was offered), or
+// pauses/resumes the current one.
+func (m *Model) toggleFocusSession() tea.Cmd {
+	now := time.Now()
+	if m.focus == nil {
+		if m.focusBreakOffered {
+			return m.startFocusTimer(focusBreak, now)
+		}
+		return m.startFocusTimer(focusWork, now)
+	}
+	m.focusSeq++
+	if m.focus.paused {
+		m.focus.paused = false
+		m.focus.endsAt = now.Add(m.focus.remaining)
+		m.status = focusKindLabel(m.focus.kind) + " resumed: " + formatFocusDuration(m.focus.remaining) + " left"
+		return m.scheduleFocusTick(now)
+	}
+	m.focus.remaining = m.focus.timeLeft(now)
+	m.focus.paused = true
+	m.status = focusKindLabel(m.focus.kind) + " paused: " + formatFocusDuration(m.focus.remaining) + " left"
+	return nil
+}
+
+func (m *Model) startFocusTimer(kind focusKind, now time.Time) tea.Cmd {
+	length := m.focusLength
+	if kind == focusBreak {
+		length = m.breakLength
+	}
+	session := &focusSession{kind: kind, startedAt: now, length: length, endsAt: now.Add(length)}
+	if kind == focusWork && m.currentFile != "" {
+		session.notePath = m.currentFile
+		session.startWords = m.focusNoteWords(m.currentFile)
+	}
+	m.focus = session
+	m.focusBreakOffered = false
+	m.focusSeq++
+	m.status = fmt.Sprintf("%s started: %s", focusKindLabel(kind), formatFocusDuration(length))
+	if session.notePath != "" {
+		m.status += " on " + m.displayRelative(session.notePath)
+	}
+	return m.scheduleFocusTick(now)
+}
+
+// cancelFocusSession stops the current timer without recording it, or
+// declines an offered break.
+func (m *Model) cancelFocusSession() {
+	if m.focus == nil {
+		if m.focusBreakOffered {
+			m.focusBreakOffered = false
+			m.status = "Break skipped"
+			return
+		}
+		m.status = "No focus session running"
+		return
+	}
+	kind := m.focus.kind
+	m.focus = nil
+	m.focusSeq++
+	m.status = focusKindLabel(kind) + " cancelled"
+}
+
+// scheduleFocusTick wakes up after FocusTickInterval or when the timer
+// ends, whichever comes first.
+func (m *Model) scheduleFocusTick(now time.Time) tea.Cmd {
+	if m.focus == nil || m.focus.paused {
+		return nil
+	}
+	wait := min(FocusTickInterval, m.focus.timeLeft(now))
+	seq := m.focusSeq
+	return tea.Tick(max(wait, time.Millisecond), func(time.Time) tea.Msg {
+		return focusTickMsg{seq: seq}
+	})
+}
+
+// handleFocusTick ends the timer once it has run out and otherwise
+// schedules the next check.
+func (m *Model) handleFocusTick(msg focusTickMsg) (tea.Model, tea.Cmd) {
+	if msg.seq != m.focusSeq || m.focus == nil || m.focus.paused {
+		return m, nil
+	}
+	now := time.Now()
+	if m.focus.timeLeft(now) > 0 {
+		return m, m.scheduleFocusTick(now)
+	}
+	return m, m.finishFocusSession(now)
+}
+
+// finishFocusSession records a completed focus session and offers a break,
+// or ends a break. It returns the bell command when focus_bell is set.
+func (m *Model) finishFocusSession(now time.Time) tea.Cmd {
+	session := m.focus
+	m.focus = nil
+	m.focusSeq++
+	if session.kind == focusBreak {
+		m.status = "Break over: press " + m.primaryActionKey(actionFocus, "F") + " to start another focus session"
+	} else {
+		record := focusRecord{NotePath: session.notePath, StartedAt: session.startedAt, Duration: session.length}
+		if session.notePath != "" {
+			record.WordsAdded = m.focusNoteWords(session.notePath) - session.startWords
+		}
+		m.focusHistory = appendFocusRecord(m.focusHistory, record)
+		m.saveAppState()
+		m.focusBreakOffered = true
+		m.status = fmt.Sprintf("Focus session complete (%s", formatFocusDuration(session.length))
+		if session.notePath != "" {
+			m.status += fmt.Sprintf(", %+d words", record.WordsAdded)
+		}
+		m.status += fmt.Sprintf("): press %s for a %s break, %s to skip",
+			m.primaryActionKey(actionFocus, "F"), formatFocusDuration(m.breakLength), m.primaryActionKey(actionFocusCancel, "Alt+F"))
+	}
+	if !m.focusBell {
+		return nil
+	}
+	return func() tea.Msg {
+		_, _ = os.Stdout.WriteString("\a")
+		return nil
+	}
+}
+
+// appendFocusRecord appends record, keeping at most FocusHistoryLimit of the
+// newest entries.
+func appendFocusRecord(history []focusRecord, record focusRecord) []focusRecord {
+	history = append(history, record)
+	if extra := len(history) - FocusHistoryLimit; extra > 0 {
+		history = append([]focusRecord(nil), history[extra:]...)
+	}
+	return history
+}
+
+// focusNoteWords counts the words in path, using the editor buffer when the
+// note is being edited so unsaved writing counts too.
+func (m *Model) focusNoteWords(path string) int {
+	if m.mode == modeEditNote && path == m.currentFile {
+		return computeNoteMetrics(m.editor.Value()).words
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	return computeNoteMetrics(string(data)).words
+}
+
+// startOfWeek returns Monday 00:00 of t's week in t's location.
+func startOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	year, month, day := t.AddDate(0, 0, -daysSinceMonday).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// focusTimeSince sums the completed focus sessions started at or after since.
+func (m *Model) focusTimeSince(since time.Time) time.Duration {
+	var total time.Duration
+	for _, record := range m.focusHistory {
+		if !record.StartedAt.Before(since) {
+			total += record.Duration
+		}
+	}
+	return total
+}
+
+func focusKindLabel(kind focusKind) string {
+	if kind == focusBreak {
+		return "Break"
+	}
+	return "Focus session"
+}
+
+// focusFooterSegment is the footer's timer, e.g. "focus: 24m left".
+func (m *Model) focusFooterSegment() string {
+	if m.focus == nil {
+		return ""
+	}
+	label := "focus"
+	if m.focus.kind == focusBreak {
+		label = "break"
+	}
+	left := formatFocusDuration(m.focus.timeLeft(time.Now()))
+	if m.focus.paused {
+		return label + ": paused, " + left + " left"
+	}
+	return label + ": " + left + " left"
+}
+
+// requestQuit quits immediately unless a focus session is in progress, in
+// which case it asks before abandoning the session.
+func (m *Model) requestQuit() (tea.Model, tea.Cmd) {
+	if m.focus == nil || m.focus.kind != focusWork {
+		return m, tea.Quit
+	}
+	m.askConfirm(confirmPrompt{
+		question: fmt.Sprintf("Focus session in progress (%s left). Quit and abandon it? (y/n)",
+			formatFocusDuration(m.focus.timeLeft(time.Now()))),
+		yesHint:      "quit and abandon session",
+		noHint:       "keep working",
+		cancelStatus: "Quit cancelled; focus session continues",
+		returnMode:   m.mode,
+		onConfirm:    func() (tea.Model, tea.Cmd) { return m, tea.Quit },
+	})
+	return m, nil
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFormatFocusDuration(t *testing.T) {
+	for _, tc := range []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{10 * time.Second, "1m"},
+		{25 * time.Minute, "25m"},
+		{24*time.Minute + time.Second, "25m"},
+		{85 * time.Minute, "1h 25m"},
+	} {
+		if got := formatFocusDuration(tc.in); got != tc.want {
+			t.Fatalf("formatFocusDuration(%v) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestStartOfWeekIsMonday(t *testing.T) {
+	sunday := time.Date(2026, 10, 18, 15, 4, 0, 0, time.UTC)
+	if got, want := startOfWeek(sunday), time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("startOfWeek(%v) = %v, want %v", sunday, got, want)
+	}
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	if got := startOfWeek(monday); !got.Equal(monday) {
+		t.Fatalf("startOfWeek(Monday) = %v", got)
+	}
+}
+
+func TestFocusSessionPauseResumeAndCancel(t *testing.T) {
+	m := &Model{focusLength: 25 * time.Minute}
+	if cmd := m.toggleFocusSession(); cmd == nil || m.focus == nil || m.focus.kind != focusWork {
+		t.Fatalf("expected running focus session with a tick, got %+v", m.focus)
+	}
+	if got := m.focusFooterSegment(); got != "focus: 25m left" {
+		t.Fatalf("unexpected footer segment %q", got)
+	}
+
+	seq := m.focusSeq
+	if cmd := m.toggleFocusSession(); cmd != nil || !m.focus.paused {
+		t.Fatal("expected pause without a tick")
+	}
+	if !strings.Contains(m.focusFooterSegment(), "paused") {
+		t.Fatalf("expected paused footer, got %q", m.focusFooterSegment())
+	}
+	// A tick scheduled before the pause must not end or reschedule the timer.
+	if _, cmd := m.handleFocusTick(focusTickMsg{seq: seq}); cmd != nil || m.focus == nil {
+		t.Fatal("expected stale tick to be ignored")
+	}
+
+	if cmd := m.toggleFocusSession(); cmd == nil || m.focus.paused {
+		t.Fatal("expected resume to schedule a tick")
+	}
+	m.cancelFocusSession()
+	if m.focus != nil || m.focusFooterSegment() != "" || len(m.focusHistory) != 0 {
+		t.Fatalf("expected cancelled session to leave no timer or history, got %+v", m.focusHistory)
+	}
+}
+
+func TestFocusSessionCompletionRecordsWordsAndOffersBreak(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "draft.md")
+	mustWriteFile(t, note, "one two three\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.currentFile = note
+	m.focusLength = 25 * time.Minute
+	m.breakLength = 5 * time.Minute
+	_ = m.toggleFocusSession()
+	if m.focus.notePath != note || m.focus.startWords != 3 {
+		t.Fatalf("expected session bound to note with 3 words, got %+v", m.focus)
+	}
+
+	mustWriteFile(t, note, "one two three four five\n")
+	m.focus.endsAt = time.Now().Add(-time.Second)
+	_, _ = m.handleFocusTick(focusTickMsg{seq: m.focusSeq})
+
+	if m.focus != nil || !m.focusBreakOffered {
+		t.Fatalf("expected session finished with a break offered, got %+v", m.focus)
+	}
+	if len(m.focusHistory) != 1 || m.focusHistory[0].WordsAdded != 2 || m.focusHistory[0].NotePath != note {
+		t.Fatalf("unexpected history %+v", m.focusHistory)
+	}
+	if !strings.Contains(m.status, "+2 words") {
+		t.Fatalf("expected completion status with words added, got %q", m.status)
+	}
+
+	state, err := loadAppState(root)
+	if err != nil {
+		t.Fatalf("load app state: %v", err)
+	}
+	if len(state.FocusSessions) != 1 || state.FocusSessions[0].Duration != 25*time.Minute || state.FocusSessions[0].NotePath != note {
+		t.Fatalf("expected persisted session, got %+v", state.FocusSessions)
+	}
+	if got := m.focusTimeSince(startOfWeek(time.Now())); got != 25*time.Minute {
+		t.Fatalf("expected 25m focus this week, got %v", got)
+	}
+
+	_ = m.toggleFocusSession()
+	if m.focus == nil || m.focus.kind != focusBreak || m.focus.length != 5*time.Minute {
+		t.Fatalf("expected break timer, got %+v", m.focus)
+	}
+}
+
+func TestQuitDuringFocusSessionAsksFirst(t *testing.T) {
+	m := &Model{focusLength: 25 * time.Minute}
+	if _, cmd := m.requestQuit(); cmd == nil {
+		t.Fatal("expected immediate quit without a session")
+	}
+
+	_ = m.toggleFocusSession()
+	if _, cmd := m.requestQuit(); cmd != nil || m.mode != modeConfirm {
+		t.Fatalf("expected abandon prompt, mode %v", m.mode)
+	}
+	_, _ = m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeBrowse || m.focus == nil {
+		t.Fatal("expected Esc to keep the session running")
+	}
+
+	_, _ = m.requestQuit()
+	if _, cmd := m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd == nil {
+		t.Fatal("expected y to quit")
+	}
+}
@@ -0,0 +1,197 @@
+// folder_document.go implements "Folder to single HTML" and "Folder to single
+// PDF (pandoc)" in the export popup (x): every markdown note under the
+// selected folder is joined into one document with a generated table of
+// contents, written next to the folder as <folder>.html or <folder>.pdf.
+//
+// Notes are collected and ordered as in "Folder to HTML" (see
+// folder_export.go), so the managed and hidden folders are skipped. Each note
+// becomes a section headed by its title; the note's own headings move one
+// level down and a leading heading that repeats the title is dropped. Wiki
+// links and relative .md links between exported notes point at the target's
+// section. The PDF is produced by handing the combined HTML to pandoc, which
+// builds its own table of contents. The HTML is styled like the other
+// exports, with export_css_file or the built-in stylesheet.
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuin/goldmark/ast"
+)
+
+// Folder document formats accepted by exportFolder.
+const (
+	folderDocumentHTML = "html"
+	folderDocumentPDF  = "pdf"
+)
+
+// exportFolderDocument returns an async Cmd that joins the notes under the
+// selected folder into one document and reports the output path.
+func (m *Model) exportFolderDocument(format string) tea.Cmd {
+	dir := m.selectedParentDir()
+	if dir == "" || !isWithinRoot(m.notesDir, dir) {
+		dir = m.notesDir
+	}
+	cssFile := m.exportCSSFile
+	m.status = "Exporting " + m.displayRelative(dir) + " to a single " + strings.ToUpper(format) + "..."
+	return func() tea.Msg {
+		out, err := exportFolder(dir, format, exportStyleBlock(cssFile))
+		if err != nil {
+			return statusMsg{Text: "Folder export failed: " + err.Error()}
+		}
+		return statusMsg{Text: "Exported " + strings.ToUpper(format) + ": " + displayHomePath(out)}
+	}
+}
+
+// exportFolder joins every markdown note under dir into one document in the
+// given format (folderDocumentHTML or folderDocumentPDF), styled with the
+// <style> block style, and returns the path written.
+func exportFolder(dir, format, style string) (string, error) {
+	if format != folderDocumentHTML && format != folderDocumentPDF {
+		return "", fmt.Errorf("unsupported format %q", format)
+	}
+	pandoc := ""
+	if format == folderDocumentPDF {
+		path, err := exec.LookPath("pandoc")
+		if err != nil {
+			return "", errors.New("install pandoc to enable PDF export")
+		}
+		pandoc = path
+	}
+	notes, err := collectExportNotes(dir)
+	if err != nil {
+		return "", err
+	}
+	out := folderDocumentPath(dir, format)
+	page, err := renderFolderDocument(filepath.Base(dir), notes, format == folderDocumentHTML, style)
+	if err != nil {
+		return "", err
+	}
+	if format == folderDocumentHTML {
+		if err := os.WriteFile(out, page, FilePermission); err != nil {
+			return "", fmt.Errorf("write %s: %w", out, err)
+		}
+		return out, nil
+	}
+
+	cmd := exec.Command(pandoc, "-f", "html", "--toc", "-o", out)
+	cmd.Stdin = bytes.NewReader(page)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if line := strings.TrimSpace(stderr.String()); line != "" {
+			return "", errors.New(line)
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// folderDocumentPath returns where the document for dir is written: beside
+// the folder, like the default output directory of "Folder to HTML".
+func folderDocumentPath(dir, format string) string {
+	return folderExportPath(dir, "."+format)
+}
+
+// renderFolderDocument joins notes into one HTML page. withTOC adds a
+// table of contents grouped by folder.
+func renderFolderDocument(name string, notes []exportedNote, withTOC bool, style string) ([]byte, error) {
+	anchors := folderDocumentAnchors(notes)
+	resolveWiki, bySrc := indexExportNotes(notes)
+	href := func(target *exportedNote) string { return "#" + anchors[target.src] }
+
+	var b bytes.Buffer
+	writeExportHead(&b, name, NoteMetadata{}, style)
+	if withTOC {
+		writeFolderDocumentTOC(&b, name, notes, anchors)
+	}
+	for i := range notes {
+		note := &notes[i]
+		body, err := renderExportBody(note, replaceWikiLinksForExport(note.body, resolveWiki, href), bySrc, href, func(doc ast.Node, source []byte) {
+			demoteNoteHeadings(doc, source, note.title)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("convert %s: %w", note.src, err)
+		}
+		fmt.Fprintf(&b, "<section id=\"%s\">\n<h1>%s</h1>\n", anchors[note.src], html.EscapeString(note.title))
+		b.Write(body)
+		b.WriteString("</section>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.Bytes(), nil
+}
+
+// writeFolderDocumentTOC writes the table of contents: one list per folder,
+// top-level notes first, each entry linking to its section.
+func writeFolderDocumentTOC(b *bytes.Buffer, name string, notes []exportedNote, anchors map[string]string) {
+	fmt.Fprintf(b, "<nav id=\"contents\">\n<h1>%s</h1>\n", html.EscapeString(name))
+	group := ""
+	open := false
+	for _, note := range notes {
+		dir := exportNoteDir(note.rel)
+		if !open || dir != group {
+			if open {
+				b.WriteString("</ul>\n")
+			}
+			if dir != "." {
+				fmt.Fprintf(b, "<h2>%s</h2>\n", html.EscapeString(dir))
+			}
+			b.WriteString("<ul>\n")
+			group, open = dir, true
+		}
+		fmt.Fprintf(b, "<li><a href=\"#%s\">%s</a></li>\n", anchors[note.src], html.EscapeString(note.title))
+	}
+	if open {
+		b.WriteString("</ul>\n")
+	}
+	b.WriteString("</nav>\n")
+}
+
+// folderDocumentAnchors assigns each note a unique section id derived from
+// its path, keyed by source path.
+func folderDocumentAnchors(notes []exportedNote) map[string]string {
+	anchors := make(map[string]string, len(notes))
+	used := map[string]bool{"contents": true}
+	for _, note := range notes {
+		base := strings.Trim(strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return '-'
+		}, strings.TrimSuffix(note.rel, ".html")), "-")
+		if base == "" {
+			base = "note"
+		}
+		anchor := base
+		for n := 2; used[anchor]; n++ {
+			anchor = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[anchor] = true
+		anchors[note.src] = anchor
+	}
+	return anchors
+}
+
+// demoteNoteHeadings moves every heading in a note one level down (to at
+// most h6) so it nests under the note's section title, and drops a leading
+// heading whose text repeats that title.
+func demoteNoteHeadings(doc ast.Node, source []byte, title string) {
+	if first, ok := doc.FirstChild().(*ast.Heading); ok && strings.TrimSpace(string(first.Text(source))) == title {
+		doc.RemoveChild(doc, first)
+	}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if heading, ok := n.(*ast.Heading); ok && entering {
+			heading.Level = min(heading.Level+1, 6)
+		}
+		return ast.WalkContinue, nil
+	})
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestExportFolderJoinsNotesIntoOneHTMLDocument(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "notes")
+	mustWriteFile(t, filepath.Join(root, "home.md"), "---\ntitle: Start Here\n---\n# Start Here\n\nSee [[Plan]] and [setup](guides/setup.md#install) and [[Missing]].\n\n## Next\n")
+	mustWriteFile(t, filepath.Join(root, "guides", "setup.md"), "Back to [[Start Here]].\n")
+	mustWriteFile(t, filepath.Join(root, "guides", "plan.md"), "# Plan\n\n###### Deep\n")
+	mustWriteFile(t, filepath.Join(root, ".cli-notes", "state.md"), "managed\n")
+
+	out, err := exportFolder(root, folderDocumentHTML, exportStyleBlock(""))
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if want := filepath.Join(filepath.Dir(root), "notes.html"); out != want {
+		t.Fatalf("expected output %s, got %s", want, out)
+	}
+	doc := readExported(t, out)
+	for _, want := range []string{
+		"<title>notes</title>",
+		`<li><a href="#home">Start Here</a></li>`,
+		"<h2>guides</h2>",
+		`<li><a href="#guides-plan">Plan</a></li>`,
+		"<section id=\"home\">\n<h1>Start Here</h1>\n<p>See",
+		`<a href="#guides-plan">Plan</a>`,
+		`<a href="#guides-setup">setup</a>`,
+		"and Missing.",
+		"<h3>Next</h3>",
+		"<h6>Deep</h6>",
+		`Back to <a href="#home">Start Here</a>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Fatalf("expected %q in document:\n%s", want, doc)
+		}
+	}
+	if strings.Contains(doc, "managed") || strings.Count(doc, "<h1>Plan</h1>") != 1 || strings.Contains(doc, "<h2>Plan</h2>") {
+		t.Fatalf("expected the managed folder skipped and the repeated title dropped:\n%s", doc)
+	}
+	if strings.Index(doc, `id="home"`) > strings.Index(doc, `id="guides-plan"`) {
+		t.Fatalf("expected top-level notes first:\n%s", doc)
+	}
+
+	if _, err := exportFolder(root, "docx", exportStyleBlock("")); err == nil {
+		t.Fatal("expected an unsupported format to fail")
+	}
+}
+
+func TestFolderDocumentAnchorsAreUnique(t *testing.T) {
+	anchors := folderDocumentAnchors([]exportedNote{
+		{src: "1", rel: "a b.html"},
+		{src: "2", rel: "a-b.html"},
+		{src: "3", rel: "Contents.html"},
+		{src: "4", rel: "日記.html"},
+	})
+	for src, want := range map[string]string{"1": "a-b", "2": "a-b-2", "3": "contents-2", "4": "日記"} {
+		if anchors[src] != want {
+			t.Errorf("anchor for %s = %q, want %q", src, anchors[src], want)
+		}
+	}
+}
+
+func TestExportPopupFolderDocumentReportsPath(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "notes")
+	mustWriteFile(t, filepath.Join(root, "a.md"), "# A\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.openExportPopup()
+	m.exportCursor = exportRowFolderHTML
+	_, cmd := m.handleExportPopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected an export command")
+	}
+	m.Update(cmd())
+	if want := "Exported HTML: " + displayHomePath(filepath.Join(filepath.Dir(root), "notes.html")); m.status != want {
+		t.Fatalf("expected %q, got %q", want, m.status)
+	}
+}
@@ -0,0 +1,502 @@
+// folder_export.go implements "Folder to HTML", an option of the export
+// popup (x): every markdown note under the selected folder (the whole
+// workspace from the root item) becomes a standalone HTML page under an
+// output directory, keeping the folder layout (or flattened into one folder
+// with export_layout), plus an index.html listing the pages grouped by
+// folder. Page names come from export_names.go, which keeps notes with the
+// same name from overwriting each other.
+//
+// Frontmatter is not rendered into the body: the title becomes <title> and
+// an <h1> only when the body has no heading of its own, and date, category,
+// tags, and any other keys become <meta> tags. Pages are styled with
+// export_css_file or the built-in stylesheet (export_style.go). [[Wiki links]] are resolved
+// among the exported notes the same way the app resolves them (title first,
+// then filename stem) and relative .md links are pointed at the generated
+// .html files. Links to notes outside the export stay as plain text.
+//
+// The export runs in a goroutine; folderExportJob relays progress and the
+// final result to the Update loop through the same wait-command pattern as
+// the filesystem event watcher.
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// folderExportJob is a running folder export.
+type folderExportJob struct {
+	updates chan tea.Msg
+}
+
+// folderExportProgressMsg reports how many notes have been written.
+type folderExportProgressMsg struct {
+	job         *folderExportJob
+	done, total int
+}
+
+// folderExportDoneMsg ends a folder export.
+type folderExportDoneMsg struct {
+	job     *folderExportJob
+	count   int
+	outDir  string
+	renamed []exportRename
+	err     error
+}
+
+// exportedNote is one note in a folder export.
+type exportedNote struct {
+	src   string // absolute source path
+	rel   string // slash-separated path relative to the exported folder, ".md" replaced by ".html"
+	out   string // slash-separated page name in a "Folder to HTML" output directory (exportNamer)
+	title string
+	stem  string
+	meta  NoteMetadata
+	body  string
+}
+
+// startFolderExportPrompt asks for the output directory of a folder export.
+func (m *Model) startFolderExportPrompt() {
+	if m.folderExport != nil {
+		m.status = "A folder export is already running"
+		return
+	}
+	folder := m.selectedParentDir()
+	m.mode = modeExportFolder
+	m.showHelp = false
+	m.actionPath = folder
+	m.input.Reset()
+	m.input.Placeholder = "Output directory (~ allowed)"
+	m.input.SetValue(filepath.Join(filepath.Dir(m.notesDir), filepath.Base(folder)+"-html"))
+	m.input.CursorEnd()
+	m.input.Focus()
+	m.status = "Export folder: Enter or Ctrl+S to export, Esc to cancel"
+}
+
+// handleExportFolderKey processes keypresses in the output directory prompt.
+func (m *Model) handleExportFolderKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	return m.handleInputModeKey(msg, m.saveExportFolder, "Folder export cancelled")
+}
+
+// saveExportFolder validates the output directory and starts the export.
+func (m *Model) saveExportFolder() (tea.Model, tea.Cmd) {
+	outDir, err := config.NormalizeNotesDir(m.input.Value())
+	if err != nil {
+		m.status = "Output directory is required"
+		return m, nil
+	}
+	folder := m.actionPath
+	cssFile := m.exportCSSFile
+	layout := m.exportLayout
+	if folder == "" || !isWithinRoot(m.notesDir, folder) {
+		folder = m.notesDir
+	}
+	if isWithinRoot(folder, outDir) {
+		m.status = "Output directory must be outside the exported folder"
+		return m, nil
+	}
+
+	m.mode = modeBrowse
+	job := &folderExportJob{updates: make(chan tea.Msg, 1)}
+	m.folderExport = job
+	m.status = "Exporting " + m.displayRelative(folder) + " to HTML..."
+	go func() {
+		count, renamed, err := exportFolderHTML(folder, outDir, exportStyleBlock(cssFile), layout, func(done, total int) {
+			// Progress is best-effort; a pending update is simply replaced by
+			// the next one.
+			select {
+			case job.updates <- folderExportProgressMsg{job: job, done: done, total: total}:
+			default:
+			}
+		})
+		job.updates <- folderExportDoneMsg{job: job, count: count, outDir: outDir, renamed: renamed, err: err}
+		close(job.updates)
+	}()
+	return m, job.wait()
+}
+
+// wait returns a command that blocks until the job's next update.
+func (j *folderExportJob) wait() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-j.updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// handleFolderExportProgress shows progress and waits for the next update.
+func (m *Model) handleFolderExportProgress(msg folderExportProgressMsg) (tea.Model, tea.Cmd) {
+	if msg.job != m.folderExport {
+		return m, nil
+	}
+	m.status = fmt.Sprintf("Exporting to HTML... %d/%d notes", msg.done, msg.total)
+	return m, msg.job.wait()
+}
+
+// handleFolderExportDone reports the result of a folder export.
+func (m *Model) handleFolderExportDone(msg folderExportDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.job != m.folderExport {
+		return m, nil
+	}
+	m.folderExport = nil
+	if msg.err != nil {
+		m.setStatusError("Folder export failed", msg.err, "out", msg.outDir)
+		return m, nil
+	}
+	noun := "notes"
+	if msg.count == 1 {
+		noun = "note"
+	}
+	m.status = fmt.Sprintf("Exported %d %s to %s", msg.count, noun, displayHomePath(msg.outDir)) + exportRenameSummary(msg.renamed)
+	return m, nil
+}
+
+// exportFolderHTML writes an HTML page for every markdown note under folder
+// into outDir, laid out per layout (config.ExportLayoutMirror or
+// config.ExportLayoutFlatten), plus outDir/index.html. It returns the number
+// of notes written and the notes renamed to avoid a name clash. style is the
+// <style> block of every page (exportStyleBlock). progress is called after
+// each note.
+func exportFolderHTML(folder, outDir, style, layout string, progress func(done, total int)) (int, []exportRename, error) {
+	notes, err := collectExportNotes(folder)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := os.MkdirAll(outDir, DirPermission); err != nil {
+		return 0, nil, fmt.Errorf("create output directory: %w", err)
+	}
+
+	namer := newExportNamer(layout, "index.html")
+	for i := range notes {
+		rel, err := filepath.Rel(folder, notes[i].src)
+		if err != nil {
+			return 0, nil, err
+		}
+		notes[i].out = namer.name(rel, ".html")
+	}
+	resolveWiki, bySrc := indexExportNotes(notes)
+	for i := range notes {
+		note := &notes[i]
+		page, err := renderExportPage(note, resolveWiki, bySrc, style)
+		if err != nil {
+			return i, namer.renamed, fmt.Errorf("convert %s: %w", note.src, err)
+		}
+		target := filepath.Join(outDir, filepath.FromSlash(note.out))
+		if err := os.MkdirAll(filepath.Dir(target), DirPermission); err != nil {
+			return i, namer.renamed, fmt.Errorf("create output directory: %w", err)
+		}
+		if err := os.WriteFile(target, page, FilePermission); err != nil {
+			return i, namer.renamed, fmt.Errorf("write %s: %w", target, err)
+		}
+		if progress != nil {
+			progress(i+1, len(notes))
+		}
+	}
+
+	index := renderExportIndex(filepath.Base(folder), notes, style)
+	if err := os.WriteFile(filepath.Join(outDir, "index.html"), index, FilePermission); err != nil {
+		return len(notes), namer.renamed, fmt.Errorf("write index: %w", err)
+	}
+	return len(notes), namer.renamed, nil
+}
+
+// indexExportNotes returns the wiki-link resolver for notes (title first,
+// then filename stem) and the notes keyed by source path.
+func indexExportNotes(notes []exportedNote) (func(string) *exportedNote, map[string]*exportedNote) {
+	byTitle := map[string]*exportedNote{}
+	byStem := map[string]*exportedNote{}
+	bySrc := map[string]*exportedNote{}
+	for i := range notes {
+		note := &notes[i]
+		bySrc[note.src] = note
+		if key := strings.ToLower(strings.TrimSpace(note.meta.Title)); key != "" && byTitle[key] == nil {
+			byTitle[key] = note
+		}
+		if key := strings.ToLower(note.stem); byStem[key] == nil {
+			byStem[key] = note
+		}
+	}
+	resolveWiki := func(label string) *exportedNote {
+		key := strings.ToLower(strings.TrimSpace(label))
+		if note := byTitle[key]; note != nil {
+			return note
+		}
+		return byStem[key]
+	}
+	return resolveWiki, bySrc
+}
+
+// collectExportNotes reads every markdown note under folder, skipping the
+// managed folder and hidden folders such as .git.
+func collectExportNotes(folder string) ([]exportedNote, error) {
+	var notes []exportedNote
+	err := filepath.WalkDir(folder, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			if path != folder && (shouldSkipManagedPath(d.Name()) || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !hasSuffixCaseInsensitive(d.Name(), ".md") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(folder, path)
+		if err != nil {
+			return err
+		}
+		meta, body := parseFrontmatterAndBody(string(content))
+		stem := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+		notes = append(notes, exportedNote{
+			src:   path,
+			rel:   strings.TrimSuffix(filepath.ToSlash(rel), filepath.Ext(rel)) + ".html",
+			title: exportNoteTitle(meta, body, stem),
+			stem:  stem,
+			meta:  meta,
+			body:  body,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(notes) == 0 {
+		return nil, errors.New("no markdown notes in folder")
+	}
+	// Group by folder (top-level notes first), then by name within a folder.
+	sort.Slice(notes, func(i, j int) bool {
+		di, dj := exportNoteDir(notes[i].rel), exportNoteDir(notes[j].rel)
+		if di != dj {
+			return di == "." || (dj != "." && strings.ToLower(di) < strings.ToLower(dj))
+		}
+		if li, lj := strings.ToLower(notes[i].rel), strings.ToLower(notes[j].rel); li != lj {
+			return li < lj
+		}
+		// Names differing only in case keep a fixed order, so clash
+		// suffixes (export_names.go) are assigned deterministically.
+		return notes[i].src < notes[j].src
+	})
+	return notes, nil
+}
+
+// exportNoteTitle returns the title of an exported note: its frontmatter
+// title, else its first heading, else its filename stem.
+func exportNoteTitle(meta NoteMetadata, body, stem string) string {
+	if title := strings.TrimSpace(meta.Title); title != "" {
+		return title
+	}
+	if headings := parseMarkdownHeadings(body); len(headings) > 0 {
+		return headings[0].Title
+	}
+	return stem
+}
+
+// renderExportPage converts one note to a standalone HTML page.
+func renderExportPage(note *exportedNote, resolveWiki func(string) *exportedNote, bySrc map[string]*exportedNote, style string) ([]byte, error) {
+	href := func(target *exportedNote) string { return relativeExportHref(note.out, target.out) }
+	body, err := renderExportBody(note, replaceWikiLinksForExport(note.body, resolveWiki, href), bySrc, href, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := strings.Count(note.out, "/")
+	var b bytes.Buffer
+	writeExportHead(&b, note.title, note.meta, style)
+	fmt.Fprintf(&b, "<nav><a href=\"%sindex.html\">Index</a></nav>\n", strings.Repeat("../", depth))
+	if len(parseMarkdownHeadings(note.body)) == 0 {
+		fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(note.title))
+	}
+	b.Write(body)
+	b.WriteString("</body>\n</html>\n")
+	return b.Bytes(), nil
+}
+
+// renderExportBody converts the markdown source of note to HTML, pointing
+// relative links to exported notes at href of their target. adjust, when
+// set, may change the parsed document before it is rendered.
+func renderExportBody(note *exportedNote, source string, bySrc map[string]*exportedNote, href func(*exportedNote) string, adjust func(doc ast.Node, source []byte)) ([]byte, error) {
+	md := goldmark.New()
+	doc := md.Parser().Parse(text.NewReader([]byte(source)))
+	if adjust != nil {
+		adjust(doc, []byte(source))
+	}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if link, ok := n.(*ast.Link); ok && entering {
+			link.Destination = []byte(rewriteExportLink(note, string(link.Destination), bySrc, href))
+		}
+		return ast.WalkContinue, nil
+	})
+	var body bytes.Buffer
+	if err := md.Renderer().Render(&body, []byte(source), doc); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// replaceWikiLinksForExport turns resolvable [[wiki links]] outside fenced
+// code blocks into markdown links to href of the target; others become their
+// plain label.
+func replaceWikiLinksForExport(body string, resolveWiki func(string) *exportedNote, href func(*exportedNote) string) string {
+	lines := strings.Split(body, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		lines[i] = wikiLinkPattern.ReplaceAllStringFunc(line, func(match string) string {
+			label := strings.TrimSpace(match[2 : len(match)-2])
+			target := resolveWiki(label)
+			if target == nil {
+				return label
+			}
+			return "[" + label + "](<" + href(target) + ">)"
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// rewriteExportLink points a relative link to an exported .md note at href
+// of the target, keeping any #fragment. Destinations may be percent-encoded
+// ("my%20note.md"); a literal "#" that is part of a note's name ("C# notes.md")
+// is tried as a whole path before it is read as a fragment. Other links are
+// returned unchanged.
+func rewriteExportLink(note *exportedNote, dest string, bySrc map[string]*exportedNote, href func(*exportedNote) string) string {
+	if dest == "" || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "/") || strings.Contains(dest, "://") || strings.HasPrefix(dest, "mailto:") {
+		return dest
+	}
+	lookup := func(path string) *exportedNote {
+		if unescaped, err := url.PathUnescape(path); err == nil {
+			path = unescaped
+		}
+		if !hasSuffixCaseInsensitive(path, ".md") {
+			return nil
+		}
+		return bySrc[filepath.Join(filepath.Dir(note.src), filepath.FromSlash(path))]
+	}
+	fragment := ""
+	target := lookup(dest)
+	if target == nil {
+		var path string
+		path, fragment, _ = strings.Cut(dest, "#")
+		target = lookup(path)
+	}
+	if target == nil {
+		return dest
+	}
+	link := href(target)
+	if fragment != "" && !strings.Contains(link, "#") {
+		link += "#" + fragment
+	}
+	return link
+}
+
+// relativeExportHref returns the link from page fromRel to page toRel (both
+// slash-separated, relative to the export root).
+func relativeExportHref(fromRel, toRel string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(fromRel)), filepath.FromSlash(toRel))
+	if err != nil {
+		return escapeExportHref(toRel)
+	}
+	return escapeExportHref(filepath.ToSlash(rel))
+}
+
+// escapeExportHref percent-encodes each segment of the slash-separated
+// relative path rel, so names with spaces, "#", "?" or "%" stay one path in
+// the browser. A first segment containing ":" gets a "./" prefix so it is
+// not read as a URL scheme.
+func escapeExportHref(rel string) string {
+	segments := strings.Split(rel, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	href := strings.Join(segments, "/")
+	if strings.Contains(segments[0], ":") {
+		href = "./" + href
+	}
+	return href
+}
+
+// renderExportIndex lists all exported pages grouped by folder.
+func renderExportIndex(name string, notes []exportedNote, style string) []byte {
+	var b bytes.Buffer
+	writeExportHead(&b, name, NoteMetadata{}, style)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(name))
+	group := ""
+	open := false
+	for _, note := range notes {
+		dir := exportNoteDir(note.rel)
+		if !open || dir != group {
+			if open {
+				b.WriteString("</ul>\n")
+			}
+			label := dir
+			if dir == "." {
+				label = name
+			}
+			fmt.Fprintf(&b, "<h2>%s</h2>\n<ul>\n", html.EscapeString(label))
+			group, open = dir, true
+		}
+		fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a></li>\n", html.EscapeString(escapeExportHref(note.out)), html.EscapeString(note.title))
+	}
+	if open {
+		b.WriteString("</ul>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.Bytes()
+}
+
+// exportNoteDir returns the slash-separated folder of an exported page, "."
+// for top-level pages.
+func exportNoteDir(rel string) string {
+	return filepath.ToSlash(filepath.Dir(filepath.FromSlash(rel)))
+}
+
+// writeExportMeta writes a <meta> tag when value is set.
+func writeExportMeta(b *bytes.Buffer, name, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	fmt.Fprintf(b, "<meta name=\"%s\" content=\"%s\">\n", html.EscapeString(name), html.EscapeString(value))
+}
+
+// displayHomePath shortens a path under the home directory to ~/...
+func displayHomePath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" || !isWithinRoot(home, path) {
+		return path
+	}
+	rel, err := filepath.Rel(home, path)
+	if err != nil {
+		return path
+	}
+	if rel == "." {
+		return "~"
+	}
+	return "~" + string(os.PathSeparator) + rel
+}
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func readExported(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestExportFolderHTMLRewritesLinksAndWritesIndex(t *testing.T) {
+	root := t.TempDir()
+	out := filepath.Join(t.TempDir(), "site")
+	mustWriteFile(t, filepath.Join(root, "home.md"), "---\ntitle: Start Here\ndate: 2026-01-02\ntags: [a, b]\n---\n# Home\n\nSee [[Plan]] and [setup](guides/setup.md#install) and [[Missing]].\n\n```\n[[Plan]]\n```\n")
+	mustWriteFile(t, filepath.Join(root, "guides", "setup.md"), "Back to [[Start Here]].\n")
+	mustWriteFile(t, filepath.Join(root, "guides", "plan.md"), "# Plan\n")
+	mustWriteFile(t, filepath.Join(root, ".cli-notes", "state.md"), "managed\n")
+	mustWriteFile(t, filepath.Join(root, ".git", "HEAD.md"), "hidden\n")
+
+	var progress []int
+	count, _, err := exportFolderHTML(root, out, exportStyleBlock(""), config.ExportLayoutMirror, func(done, total int) {
+		progress = append(progress, done)
+		if total != 3 {
+			t.Errorf("expected total 3, got %d", total)
+		}
+	})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if count != 3 || len(progress) != 3 || progress[2] != 3 {
+		t.Fatalf("expected 3 notes with progress, got %d %v", count, progress)
+	}
+
+	home := readExported(t, filepath.Join(out, "home.html"))
+	for _, want := range []string{
+		"<title>Start Here</title>",
+		`<meta name="date" content="2026-01-02">`,
+		`<meta name="keywords" content="a, b">`,
+		`<a href="guides/plan.html">Plan</a>`,
+		`<a href="guides/setup.html#install">setup</a>`,
+		"and Missing.",
+		"[[Plan]]\n</code></pre>",
+		`<a href="index.html">Index</a>`,
+	} {
+		if !strings.Contains(home, want) {
+			t.Fatalf("expected %q in home page:\n%s", want, home)
+		}
+	}
+	if strings.Contains(home, "title: Start Here") {
+		t.Fatalf("expected frontmatter to stay out of the body:\n%s", home)
+	}
+
+	setup := readExported(t, filepath.Join(out, "guides", "setup.html"))
+	for _, want := range []string{`<a href="../home.html">Start Here</a>`, `<a href="../index.html">Index</a>`, "<h1>setup</h1>"} {
+		if !strings.Contains(setup, want) {
+			t.Fatalf("expected %q in setup page:\n%s", want, setup)
+		}
+	}
+
+	index := readExported(t, filepath.Join(out, "index.html"))
+	rootGroup := strings.Index(index, "<h2>"+filepath.Base(root)+"</h2>")
+	guidesGroup := strings.Index(index, "<h2>guides</h2>")
+	if rootGroup < 0 || guidesGroup < rootGroup {
+		t.Fatalf("expected root then guides groups in index:\n%s", index)
+	}
+	if !strings.Contains(index, `<a href="guides/plan.html">Plan</a>`) || !strings.Contains(index, `<a href="home.html">Start Here</a>`) {
+		t.Fatalf("expected note links in index:\n%s", index)
+	}
+	if _, err := os.Stat(filepath.Join(out, ".cli-notes")); !os.IsNotExist(err) {
+		t.Fatalf("expected managed folder to be skipped, got %v", err)
+	}
+}
+
+func TestExportFolderHTMLEscapesHrefsForAwkwardNames(t *testing.T) {
+	root := t.TempDir()
+	out := filepath.Join(t.TempDir(), "site")
+	mustWriteFile(t, filepath.Join(root, "home.md"), "# Home\n\n"+
+		"[[C# notes]] [a](C%23%20notes.md) [b](my%20note.md#sec) [c](<my note.md>) [d](why%3F.md) [e](<odd dir/100% done.md>)\n")
+	mustWriteFile(t, filepath.Join(root, "C# notes.md"), "# C# notes\n")
+	mustWriteFile(t, filepath.Join(root, "my note.md"), "# My note\n")
+	mustWriteFile(t, filepath.Join(root, "why?.md"), "# Why\n")
+	mustWriteFile(t, filepath.Join(root, "odd dir", "100% done.md"), "# Done\n\nBack [[Home]].\n")
+
+	if _, _, err := exportFolderHTML(root, out, exportStyleBlock(""), config.ExportLayoutMirror, nil); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	home := readExported(t, filepath.Join(out, "home.html"))
+	for _, want := range []string{
+		`<a href="C%23%20notes.html">C# notes</a>`,
+		`<a href="C%23%20notes.html">a</a>`,
+		`<a href="my%20note.html#sec">b</a>`,
+		`<a href="my%20note.html">c</a>`,
+		`<a href="why_.html">d</a>`,
+		`<a href="odd%20dir/100%25%20done.html">e</a>`,
+	} {
+		if !strings.Contains(home, want) {
+			t.Fatalf("expected %q in home page:\n%s", want, home)
+		}
+	}
+	done := readExported(t, filepath.Join(out, "odd dir", "100% done.html"))
+	if !strings.Contains(done, `<a href="../home.html">Home</a>`) {
+		t.Fatalf("expected a link back to home:\n%s", done)
+	}
+	index := readExported(t, filepath.Join(out, "index.html"))
+	for _, want := range []string{`href="C%23%20notes.html"`, `href="my%20note.html"`, `href="odd%20dir/100%25%20done.html"`} {
+		if !strings.Contains(index, want) {
+			t.Fatalf("expected %q in index:\n%s", want, index)
+		}
+	}
+}
+
+func TestExportFolderHTMLRequiresNotes(t *testing.T) {
+	root := t.TempDir()
+	if _, _, err := exportFolderHTML(root, filepath.Join(t.TempDir(), "out"), exportStyleBlock(""), config.ExportLayoutMirror, nil); err == nil {
+		t.Fatal("expected error for a folder without notes")
+	}
+}
+
+func TestFolderExportPromptRunsAsyncExport(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "# A\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.openExportPopup()
+	m.exportCursor = exportRowFolder
+	_, _ = m.handleExportPopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeExportFolder {
+		t.Fatalf("expected export folder prompt, got mode %v", m.mode)
+	}
+
+	m.input.SetValue(filepath.Join(root, "inside"))
+	_, _ = m.saveExportFolder()
+	if m.mode != modeExportFolder || !strings.Contains(m.status, "outside") {
+		t.Fatalf("expected output inside the folder to be rejected, got %q", m.status)
+	}
+
+	out := filepath.Join(t.TempDir(), "out")
+	m.input.SetValue(out)
+	_, cmd := m.saveExportFolder()
+	if m.mode != modeBrowse || m.folderExport == nil || cmd == nil {
+		t.Fatalf("expected export to start, mode=%v job=%v", m.mode, m.folderExport)
+	}
+	for cmd != nil {
+		_, cmd = m.Update(cmd())
+	}
+	if m.folderExport != nil || m.status != "Exported 1 note to "+out {
+		t.Fatalf("unexpected final status %q", m.status)
+	}
+	if _, err := os.Stat(filepath.Join(out, "a.html")); err != nil {
+		t.Fatalf("expected exported page: %v", err)
+	}
+}
@@ -47,6 +47,42 @@ func TestBuildStatusRowsTruncatesWithEllipsisWhenOverCapacity(t *testing.T) {
 	}
 }
 
+func TestBuildStatusRowsKeepsPrioritizedGitSegment(t *testing.T) {
+	m := &Model{
+		mode: modeBrowse,
+		git:  gitRepoStatus{isRepo: true, branch: "main", dirty: true},
+	}
+	const width = 60
+	gitSegment := "git main no-upstream dirty"
+
+	rows, fit := m.buildStatusRows(width, FooterMinRows)
+	if fit || strings.Contains(strings.Join(rows, "\n"), gitSegment) {
+		t.Fatalf("expected the git segment to be cut without a priority, got %q", rows)
+	}
+
+	m.footerPriority = []string{config.FooterSegmentGit}
+	rows, fit = m.buildStatusRows(width, FooterMinRows)
+	joined := strings.Join(rows, "\n")
+	if fit || !strings.Contains(joined, "Context: ") || !strings.Contains(joined, gitSegment) {
+		t.Fatalf("expected the prioritized git segment to be kept, got %q", rows)
+	}
+	if !strings.Contains(joined, "Keys: ") || strings.Contains(joined, "notes --configure") || strings.Contains(joined, "…") {
+		t.Fatalf("expected trailing help hints dropped instead of truncation, got %q", rows)
+	}
+	for _, row := range rows {
+		if lipgloss.Width(row) > width {
+			t.Fatalf("row wider than %d: %q", width, row)
+		}
+	}
+
+	m.status = "Saved"
+	m.footerPriority = []string{config.FooterSegmentStatus, config.FooterSegmentGit}
+	rows, _ = m.buildStatusRows(width, FooterMinRows)
+	if joined := strings.Join(rows, "\n"); !strings.Contains(joined, "Status: Saved") || !strings.Contains(joined, gitSegment) {
+		t.Fatalf("expected status and git kept, got %q", rows)
+	}
+}
+
 func TestStatusHelpSegmentsByMode(t *testing.T) {
 	t.Run("browse", func(t *testing.T) {
 		m := &Model{mode: modeBrowse}
@@ -126,8 +162,8 @@ func TestViewPadsToTerminalSizeWithAdaptiveFooter(t *testing.T) {
 func TestStatusHelpSegmentsUsesConfiguredKeybindingLegends(t *testing.T) {
 	m := &Model{mode: modeBrowse}
 	m.loadKeybindings(config.Config{
-		Keybindings: map[string]string{
-			actionSearch: "alt+s",
+		Keybindings: map[string]config.KeyList{
+			actionSearch: {"alt+s"},
 		},
 	})
 
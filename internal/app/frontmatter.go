@@ -1,7 +1,9 @@
 package app
 
 import (
+	"strconv"
 	"strings"
+	"time"
 )
 
 // NoteMetadata holds structured metadata extracted from the YAML frontmatter
@@ -43,6 +45,34 @@ type NoteMetadata struct {
 	//   - Filtering in the Ctrl+P search popup via "tag:<name>" syntax.
 	//   - Metadata-aware search matching.
 	Tags []string
+
+	// WordGoal is the optional per-note word-count target from the
+	// "word_goal" key. Zero means no goal; non-numeric or non-positive
+	// values are ignored. The footer shows progress toward it.
+	WordGoal int
+
+	// EditorNoWrap is set by "editor_wrap: false" and opens the note in the
+	// editor with soft-wrap off, scrolling long lines horizontally instead
+	// (useful for tables and data). Unparseable values are ignored.
+	EditorNoWrap bool
+
+	// Extra holds every other top-level frontmatter key in file order, so
+	// the metadata popup can show fields the app does not interpret.
+	Extra []MetadataField
+
+	// When is the calendar date the note is about, parsed in local time
+	// from an "event" key or, failing that, Date. It is zero when neither
+	// parses; WhenHasTime is set when the value included a time of day.
+	// The agenda popup lists notes by this date.
+	When        time.Time
+	WhenHasTime bool
+}
+
+// MetadataField is a single unrecognized frontmatter key and its raw value.
+// Quotes are stripped and bullet-list values are joined with ", ".
+type MetadataField struct {
+	Key   string
+	Value string
 }
 
 // parseFrontmatterAndBody splits a markdown file's content into its YAML
@@ -115,8 +145,10 @@ func parseFrontmatterAndBody(content string) (NoteMetadata, string) {
 //   - Quoted values (single or double quotes are stripped).
 //   - Comment lines (starting with #) and blank lines are skipped.
 //
-// Recognized keys (case-insensitive): title, date, category, tags.
-// Unrecognized keys are silently ignored.
+// Recognized keys (case-insensitive): title, date, category, tags, word_goal,
+// editor_wrap.
+// Unrecognized keys are collected into Extra with their original spelling;
+// an "event" key is kept there too but also feeds When.
 func parseSimpleFrontmatter(yamlText string) NoteMetadata {
 	meta := NoteMetadata{}
 	lines := strings.Split(yamlText, "\n")
@@ -145,6 +177,14 @@ func parseSimpleFrontmatter(yamlText string) NoteMetadata {
 			meta.Date = trimQuoted(value)
 		case "category":
 			meta.Category = trimQuoted(value)
+		case "word_goal":
+			if goal, err := strconv.Atoi(trimQuoted(value)); err == nil && goal > 0 {
+				meta.WordGoal = goal
+			}
+		case "editor_wrap":
+			if wrap, err := strconv.ParseBool(trimQuoted(value)); err == nil {
+				meta.EditorNoWrap = !wrap
+			}
 		case "tags":
 			// Tags support three syntax variants:
 			//
@@ -180,11 +220,111 @@ func parseSimpleFrontmatter(yamlText string) NoteMetadata {
 				break
 			}
 			meta.Tags = normalizeTagList(bullets)
+		default:
+			field := MetadataField{Key: strings.TrimSpace(parts[0]), Value: trimQuoted(value)}
+			if value == "" {
+				// Collect a bullet-list value the same way tags do.
+				items := make([]string, 0, 4)
+				for i < len(lines) {
+					next := strings.TrimSpace(lines[i])
+					if !strings.HasPrefix(next, "-") {
+						break
+					}
+					items = append(items, trimQuoted(strings.TrimPrefix(next, "-")))
+					i++
+				}
+				field.Value = strings.Join(items, ", ")
+			}
+			meta.Extra = append(meta.Extra, field)
 		}
 	}
+	meta.When, meta.WhenHasTime = noteWhen(meta, time.Local)
 	return meta
 }
 
+// noteWhen picks the agenda date of a note: an "event" key wins over date.
+func noteWhen(meta NoteMetadata, loc *time.Location) (time.Time, bool) {
+	for _, field := range meta.Extra {
+		if strings.EqualFold(field.Key, "event") {
+			if when, hasTime, ok := parseNoteDate(field.Value, loc); ok {
+				return when, hasTime
+			}
+		}
+	}
+	when, hasTime, _ := parseNoteDate(meta.Date, loc)
+	return when, hasTime
+}
+
+// noteDateLayouts are the accepted date/time spellings without a zone,
+// interpreted in the caller's location. The bool reports a time of day.
+var noteDateLayouts = []struct {
+	layout  string
+	hasTime bool
+}{
+	{"2006-01-02", false},
+	{"2006-01-02 15:04", true},
+	{"2006-01-02 15:04:05", true},
+	{"2006-01-02T15:04", true},
+	{"2006-01-02T15:04:05", true},
+	{"2006/01/02", false},
+	{"2006/01/02 15:04", true},
+	{"Jan 2, 2006", false},
+	{"January 2, 2006", false},
+	{"2 Jan 2006", false},
+	{"2 January 2006", false},
+}
+
+// noteDateZonedLayouts carry their own UTC offset and are converted into the
+// caller's location, which can move them to a different calendar day.
+var noteDateZonedLayouts = []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02 15:04 -0700", "2006-01-02 15:04:05Z07:00", "2006-01-02 15:04:05 -0700"}
+
+// parseNoteDate parses a frontmatter date such as "2025-02-07",
+// "2025-02-07 09:30", or "Feb 7, 2025" in loc. Date-only values are midnight
+// in loc. A nil loc keeps every value on the wall clock it was written with:
+// zoned values keep their offset and the rest are read as UTC.
+func parseNoteDate(value string, loc *time.Location) (time.Time, bool, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false, false
+	}
+	for _, layout := range noteDateZonedLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			if loc != nil {
+				t = t.In(loc)
+			}
+			return t, true, true
+		}
+	}
+	in := loc
+	if in == nil {
+		in = time.UTC
+	}
+	for _, candidate := range noteDateLayouts {
+		if t, err := time.ParseInLocation(candidate.layout, value, in); err == nil {
+			return t, candidate.hasTime, true
+		}
+	}
+	return time.Time{}, false, false
+}
+
+// defaultDateDisplayFormat is the layout frontmatter dates are shown with
+// when date_display_format is unset.
+const defaultDateDisplayFormat = "Jan 2, 2006"
+
+// formatNoteDate renders a frontmatter date value with layout (the default
+// when empty) for display, on the calendar day it was written with. Values
+// parseNoteDate does not recognize are returned unchanged.
+func formatNoteDate(value, layout string) string {
+	t, _, ok := parseNoteDate(value, nil)
+	if !ok {
+		return value
+	}
+	if layout == "" {
+		layout = defaultDateDisplayFormat
+	}
+	return t.Format(layout)
+}
+
 // trimQuoted removes surrounding single or double quotes from a value string,
 // plus any leading/trailing whitespace.
 //
@@ -229,6 +369,23 @@ func normalizeTagList(values []string) []string {
 	return out
 }
 
+// setFrontmatterTags rewrites the tags key of a note's frontmatter block and
+// returns the updated content. An empty tags slice removes the key. Only the
+// tags lines change; see frontmatterDoc for the preservation rules.
+func setFrontmatterTags(content string, tags []string) string {
+	doc := parseFrontmatterDoc(content)
+	doc.setTags(tags)
+	return doc.String()
+}
+
+// stampFrontmatterTime sets key (created or updated) to the current local
+// time in ISO 8601 form with offset, e.g. "updated: 2026-02-07T09:30:00-05:00".
+func stampFrontmatterTime(content, key string) string {
+	doc := parseFrontmatterDoc(content)
+	doc.setTime(key, appNow())
+	return doc.String()
+}
+
 // compactTagLabel formats a slice of tags into a short display string for
 // use in tree view row badges.
 //
@@ -255,13 +412,21 @@ func compactTagLabel(tags []string, maxTags int) string {
 // text search terms and tag filter terms.
 //
 // The search popup supports a special "tag:<name>" prefix syntax for
-// filtering results by tag. All other words are treated as text search
-// terms that match against note names, titles, categories, and content.
+// filtering results by tag, "-tag:<name>" for excluding a tag, and an
+// "in:archive" token that includes notes
+// under the archive folder (excluded by default). All other words are treated
+// as text search terms that match against note names, titles, categories, and
+// content.
 //
 // Example query: "meeting notes tag:work tag:important"
 //
 //	textTerms: ["meeting", "notes"]
 //	tagTerms:  ["work", "important"]
+//
+// Example query: "tag:todo -tag:done"
+//
+//	tagTerms:     ["todo"]
+//	excludedTags: ["done"]
 type searchQuery struct {
 	// textTerms contains lowercase words that are matched against note
 	// filenames, frontmatter titles/categories, and body content.
@@ -271,14 +436,22 @@ type searchQuery struct {
 	// that must all be present in a note's frontmatter tags for the note
 	// to match the query.
 	tagTerms []string
+
+	// excludedTags contains lowercase tag names (without the "-tag:"
+	// prefix) that a note must not have to match the query.
+	excludedTags []string
+
+	// inArchive includes notes under the archive folder in the results.
+	inArchive bool
 }
 
 // parseSearchQuery splits a raw search input string into text terms and
 // tag filter terms.
 //
 // The input is lowercased and split on whitespace. Tokens that start with
-// "tag:" are extracted as tag filter terms (with the prefix stripped); all
-// other tokens become text search terms.
+// "tag:" are extracted as tag filter terms (with the prefix stripped), tokens
+// that start with "-tag:" as excluded tags, the "in:archive" token sets inArchive, and all other tokens become text search
+// terms.
 //
 // Both term lists are pre-allocated with reasonable initial capacities to
 // minimize allocations during interactive search.
@@ -289,6 +462,17 @@ func parseSearchQuery(query string) searchQuery {
 		tagTerms:  make([]string, 0, 4),
 	}
 	for _, token := range fields {
+		if token == "in:"+ArchiveDirName {
+			parsed.inArchive = true
+			continue
+		}
+		if strings.HasPrefix(token, "-tag:") {
+			tag := strings.TrimSpace(strings.TrimPrefix(token, "-tag:"))
+			if tag != "" {
+				parsed.excludedTags = append(parsed.excludedTags, tag)
+			}
+			continue
+		}
 		if strings.HasPrefix(token, "tag:") {
 			tag := strings.TrimSpace(strings.TrimPrefix(token, "tag:"))
 			if tag != "" {
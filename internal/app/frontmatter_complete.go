@@ -0,0 +1,394 @@
+// frontmatter_complete.go completes tags and frontmatter keys while the
+// frontmatter block is edited by hand in the editor.
+//
+// After each edit, frontmatterCompletionAt classifies the cursor position.
+// It only ever matches on the lines between the opening "---" (the first
+// line of the note) and its closing "---"; an unclosed block is not a block.
+// Inside it:
+//
+//   - a bare word at the start of a line completes keys: the common keys in
+//     frontmatterCompletionKeys plus keys seen in other notes, minus keys the
+//     block already has. Accepting writes "key: ".
+//   - a value on the tags: line (plain "a, b" or flow "[a, b]") completes
+//     known workspace tags, minus tags already on the line. Accepting writes
+//     the tag with ", " after a previous item and ": " after the key.
+//   - a word on an item line under a block-style "tags:" completes tags
+//     too. Accepting writes "- tag", adding the dash when it was left out.
+//
+// Outside the block, a #hashtag being typed in the body (currentTagPrefix)
+// completes known tags too, most used first. Accepting replaces the typed
+// part after the "#".
+//
+// Candidates are shown in the wiki-link autocomplete popup
+// (overlayWikiAutocomplete) and use its keys, except that j and k are
+// typed rather than moving the selection.
+package app
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// frontmatterCompletionKeys are offered at the start of a frontmatter line
+// in every workspace.
+var frontmatterCompletionKeys = []string{
+	frontmatterKeyTitle,
+	frontmatterKeyTags,
+	frontmatterKeyCategory,
+	frontmatterKeyAliases,
+	frontmatterKeyCreated,
+	frontmatterKeyUpdated,
+	"date",
+	"event",
+	frontmatterKeyType,
+	frontmatterKeyColor,
+	frontmatterKeyPrivate,
+	"word_goal",
+	"editor_wrap",
+}
+
+// frontmatterCompletionKind is what the cursor position completes.
+type frontmatterCompletionKind int
+
+const (
+	fmCompleteKey frontmatterCompletionKind = iota + 1
+	fmCompleteTagInline
+	fmCompleteTagItem
+	fmCompleteHashtag
+)
+
+// frontmatterCompletionContext describes a completion site in the editor.
+type frontmatterCompletionContext struct {
+	kind frontmatterCompletionKind
+	// prefix is the text typed so far.
+	prefix string
+	// start and end are the rune offsets of the text an accepted completion
+	// replaces; end is the cursor.
+	start, end int
+	// used holds the keys already in the block (fmCompleteKey) or the tags
+	// already in the list, lowercased; they are not offered again.
+	used []string
+}
+
+var (
+	fmKeyPrefixPattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	fmTagsLinePattern   = regexp.MustCompile(`(?i)^tags\s*:(.*)$`)
+	fmItemLinePattern   = regexp.MustCompile(`^(\s*)-\s*([^\s,\[\]]+)$`)
+	fmBareIndentPattern = regexp.MustCompile(`^(\s+)([^\s,\[\]:-][^\s,\[\]:]*)$`)
+)
+
+// editorLine is one line of the editor value; start is its rune offset and
+// text excludes the newline.
+type editorLine struct {
+	start int
+	text  []rune
+}
+
+func editorLinesWithOffsets(value string) []editorLine {
+	split := splitEditorLines(value)
+	lines := make([]editorLine, len(split))
+	offset := 0
+	for i, text := range split {
+		lines[i] = editorLine{start: offset, text: text}
+		offset += len(text) + 1
+	}
+	return lines
+}
+
+// frontmatterBlockLines returns the indexes of the opening and closing
+// delimiter lines, or ok=false when the value has no closed block.
+func frontmatterBlockLines(lines []editorLine) (int, int, bool) {
+	if len(lines) < 2 {
+		return 0, 0, false
+	}
+	first := strings.TrimSuffix(strings.TrimPrefix(string(lines[0].text), "\ufeff"), "\r")
+	if first != "---" {
+		return 0, 0, false
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(string(lines[i].text)) == "---" {
+			return 0, i, true
+		}
+	}
+	return 0, 0, false
+}
+
+// frontmatterCompletionAt classifies the cursor position in value. It
+// returns ok=false outside the frontmatter block or where nothing
+// completes.
+func frontmatterCompletionAt(value string, cursor int) (frontmatterCompletionContext, bool) {
+	if cursor < 0 || cursor > utf8.RuneCountInString(value) {
+		return frontmatterCompletionContext{}, false
+	}
+	lines := editorLinesWithOffsets(value)
+	open, closing, ok := frontmatterBlockLines(lines)
+	if !ok {
+		return frontmatterCompletionContext{}, false
+	}
+	idx := -1
+	for i := open + 1; i < closing; i++ {
+		if cursor >= lines[i].start && cursor <= lines[i].start+len(lines[i].text) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return frontmatterCompletionContext{}, false
+	}
+	line := lines[idx]
+	col := cursor - line.start
+	before := string(line.text[:col])
+	after := string(line.text[col:])
+	block := lines[open+1 : closing]
+
+	if fmKeyPrefixPattern.MatchString(before) && !strings.Contains(after, ":") {
+		return frontmatterCompletionContext{
+			kind:   fmCompleteKey,
+			prefix: before,
+			start:  line.start,
+			end:    cursor,
+			used:   frontmatterBlockKeys(block, idx-open-1),
+		}, true
+	}
+
+	if match := fmTagsLinePattern.FindStringSubmatch(before); match != nil {
+		prefix := match[1]
+		if cut := strings.LastIndexAny(prefix, ",["); cut >= 0 {
+			prefix = prefix[cut+1:]
+		}
+		prefix = strings.TrimLeft(prefix, " \t")
+		if prefix == "" || strings.ContainsAny(prefix, "]\"'") {
+			return frontmatterCompletionContext{}, false
+		}
+		value := strings.Trim(strings.TrimSpace(fmTagsLinePattern.FindStringSubmatch(string(line.text))[1]), "[]")
+		return frontmatterCompletionContext{
+			kind:   fmCompleteTagInline,
+			prefix: prefix,
+			start:  cursor - len([]rune(prefix)),
+			end:    cursor,
+			used:   normalizeTagList(strings.Split(value, ",")),
+		}, true
+	}
+
+	if strings.TrimSpace(after) != "" {
+		return frontmatterCompletionContext{}, false
+	}
+	match := fmItemLinePattern.FindStringSubmatch(before)
+	if match == nil {
+		match = fmBareIndentPattern.FindStringSubmatch(before)
+	}
+	if match == nil {
+		return frontmatterCompletionContext{}, false
+	}
+	items, ok := blockTagItems(block, idx-open-1)
+	if !ok {
+		return frontmatterCompletionContext{}, false
+	}
+	return frontmatterCompletionContext{
+		kind:   fmCompleteTagItem,
+		prefix: match[2],
+		start:  line.start + len([]rune(match[1])),
+		end:    cursor,
+		used:   items,
+	}, true
+}
+
+// hashtagCompletionAt returns the completion site of a #hashtag being typed
+// at cursor outside the frontmatter block and code fences.
+func hashtagCompletionAt(value string, cursor int) (frontmatterCompletionContext, bool) {
+	prefix, ok := currentTagPrefix(value, cursor)
+	if !ok {
+		return frontmatterCompletionContext{}, false
+	}
+	runes := []rune(value)
+	lineStart, _ := lineBoundsAtOffset(runes, cursor)
+	if offsetInCodeFence(runes, lineStart) {
+		return frontmatterCompletionContext{}, false
+	}
+	lines := editorLinesWithOffsets(value)
+	if _, closing, ok := frontmatterBlockLines(lines); ok && lineStart <= lines[closing].start {
+		return frontmatterCompletionContext{}, false
+	}
+	return frontmatterCompletionContext{
+		kind:   fmCompleteHashtag,
+		prefix: prefix,
+		start:  cursor - len([]rune(prefix)),
+		end:    cursor,
+	}, true
+}
+
+// currentTagPrefix returns the tag typed so far when the cursor ends a
+// #hashtag token: a "#" at the start of a line or after whitespace, followed
+// by a letter and then letters, digits, _, /, or - (see inlineTagPattern).
+// A bare "#" does not count, so headings do not open the popup.
+func currentTagPrefix(value string, cursor int) (string, bool) {
+	runes := []rune(value)
+	if cursor < 0 || cursor > len(runes) {
+		return "", false
+	}
+	i := cursor
+	for i > 0 && isTagRune(runes[i-1]) {
+		i--
+	}
+	if i == cursor || i == 0 || runes[i-1] != '#' || !unicode.IsLetter(runes[i]) {
+		return "", false
+	}
+	if hash := i - 1; hash > 0 && !unicode.IsSpace(runes[hash-1]) {
+		return "", false
+	}
+	return string(runes[i:cursor]), true
+}
+
+// isTagRune reports whether r may appear in a #hashtag after its first letter.
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '/' || r == '-'
+}
+
+// frontmatterBlockKeys returns the lowercased top-level keys of the block
+// lines, skipping the line at skip (the one being typed).
+func frontmatterBlockKeys(block []editorLine, skip int) []string {
+	var keys []string
+	for i, line := range block {
+		text := string(line.text)
+		if i == skip || text == "" || text[0] == ' ' || text[0] == '\t' || text[0] == '-' || text[0] == '#' {
+			continue
+		}
+		if key, _, ok := strings.Cut(text, ":"); ok {
+			keys = append(keys, strings.ToLower(strings.TrimSpace(key)))
+		}
+	}
+	return keys
+}
+
+// blockTagItems reports whether the line at idx belongs to a block-style
+// "tags:" entry and returns the tags on its other item lines.
+func blockTagItems(block []editorLine, idx int) ([]string, bool) {
+	owner := -1
+	for i := idx - 1; i >= 0; i-- {
+		text := string(block[i].text)
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || strings.HasPrefix(trimmed, "-") || text[0] == ' ' || text[0] == '\t' {
+			continue
+		}
+		owner = i
+		break
+	}
+	if owner < 0 {
+		return nil, false
+	}
+	key, value, ok := strings.Cut(string(block[owner].text), ":")
+	if !ok || !strings.EqualFold(strings.TrimSpace(key), frontmatterKeyTags) || strings.TrimSpace(value) != "" {
+		return nil, false
+	}
+	var items []string
+	for i := owner + 1; i < len(block); i++ {
+		text := string(block[i].text)
+		trimmed := strings.TrimSpace(text)
+		if trimmed != "" && text[0] != ' ' && text[0] != '\t' && text[0] != '-' {
+			break
+		}
+		if i != idx && strings.HasPrefix(trimmed, "-") {
+			items = append(items, strings.TrimSpace(strings.TrimPrefix(trimmed, "-")))
+		}
+	}
+	return normalizeTagList(items), true
+}
+
+// frontmatterCompletionCandidates filters source by the context prefix
+// (case-insensitive prefix match), dropping used entries and an exact match
+// of what is already typed.
+func frontmatterCompletionCandidates(ctx frontmatterCompletionContext, source []string) []string {
+	prefix := strings.ToLower(ctx.prefix)
+	seen := map[string]bool{}
+	for _, used := range ctx.used {
+		seen[used] = true
+	}
+	var out []string
+	for _, candidate := range source {
+		lower := strings.ToLower(candidate)
+		if seen[lower] || lower == prefix || !strings.HasPrefix(lower, prefix) {
+			continue
+		}
+		seen[lower] = true
+		out = append(out, candidate)
+	}
+	return out
+}
+
+// maybeTriggerEditorAutocomplete opens frontmatter completion when the
+// cursor is at a completion site in the frontmatter block or ends a
+// #hashtag, and otherwise falls back to wiki-link autocomplete.
+func (m *Model) maybeTriggerEditorAutocomplete() {
+	value, cursor := m.editor.Value(), m.currentEditorCursorOffset()
+	ctx, ok := frontmatterCompletionAt(value, cursor)
+	if !ok {
+		ctx, ok = hashtagCompletionAt(value, cursor)
+	}
+	if !ok {
+		m.fmCompletion = nil
+		m.maybeTriggerWikiAutocomplete()
+		return
+	}
+	if m.searchIndex == nil {
+		m.searchIndex = newSearchIndex(m.notesDir)
+	}
+	if err := m.searchIndex.ensureBuilt(); err != nil {
+		return
+	}
+	var source []string
+	switch ctx.kind {
+	case fmCompleteKey:
+		source = append(append([]string(nil), frontmatterCompletionKeys...), m.searchIndex.extraFrontmatterKeys()...)
+	case fmCompleteHashtag:
+		for _, tag := range m.searchIndex.allTags() {
+			source = append(source, tag.name)
+		}
+	default:
+		source = m.searchIndex.knownTags()
+	}
+	items := frontmatterCompletionCandidates(ctx, source)
+	if len(items) == 0 {
+		m.closeOverlay()
+		return
+	}
+	m.openOverlay(overlayWikiAutocomplete)
+	m.wikiAutocomplete = nil
+	m.fmCompletion = &ctx
+	m.fmCompletionItems = items
+	m.wikiAutocompleteCursor = clamp(m.wikiAutocompleteCursor, 0, len(items)-1)
+}
+
+// acceptFrontmatterCompletion replaces the typed prefix with item, adding
+// the YAML punctuation the context needs.
+func (m *Model) acceptFrontmatterCompletion(item string) {
+	ctx := m.fmCompletion
+	if ctx == nil {
+		return
+	}
+	runes := []rune(m.editor.Value())
+	if ctx.start < 0 || ctx.end > len(runes) || ctx.start > ctx.end {
+		return
+	}
+	start := ctx.start
+	repl := item
+	switch ctx.kind {
+	case fmCompleteKey:
+		repl = item + ": "
+	case fmCompleteTagItem:
+		repl = "- " + item
+	case fmCompleteTagInline:
+		for start > 0 && runes[start-1] == ' ' {
+			start--
+		}
+		if start > 0 && (runes[start-1] == ',' || runes[start-1] == ':') {
+			repl = " " + item
+		}
+	}
+	updated := make([]rune, 0, len(runes)+len(repl))
+	updated = append(updated, runes[:start]...)
+	updated = append(updated, []rune(repl)...)
+	updated = append(updated, runes[ctx.end:]...)
+	m.setEditorValueAndCursorOffset(string(updated), start+len([]rune(repl)))
+}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// cursorAt returns value with the "|" marker removed and the rune offset
+// where it was.
+func cursorAt(t *testing.T, marked string) (string, int) {
+	t.Helper()
+	idx := strings.Index(marked, "|")
+	if idx < 0 {
+		t.Fatalf("no cursor marker in %q", marked)
+	}
+	return marked[:idx] + marked[idx+1:], len([]rune(marked[:idx]))
+}
+
+func TestFrontmatterCompletionAtOnlyInsideClosedBlock(t *testing.T) {
+	for name, marked := range map[string]string{
+		"unclosed block":      "---\nta|\n",
+		"body after block":    "---\ntitle: A\n---\nta|",
+		"closing delimiter":   "---\ntitle: A\n--|-\n",
+		"opening delimiter":   "--|-\ntitle: A\n---\n",
+		"no block":            "ta|\n",
+		"block not at top":    "\n---\nta|\n---\n",
+		"body tags line":      "---\ntitle: A\n---\ntags: g|",
+		"scalar value":        "---\ntitle: Go|\n---\n",
+		"block list of other": "---\naliases:\n  - fo|\n---\n",
+	} {
+		value, cursor := cursorAt(t, marked)
+		if ctx, ok := frontmatterCompletionAt(value, cursor); ok {
+			t.Errorf("%s: expected no completion, got %+v", name, ctx)
+		}
+	}
+}
+
+func TestFrontmatterCompletionAtClassifiesContext(t *testing.T) {
+	cases := []struct {
+		name   string
+		marked string
+		kind   frontmatterCompletionKind
+		prefix string
+		used   []string
+	}{
+		{"key", "---\ntitle: A\nTags: x\nca|\n---\n", fmCompleteKey, "ca", []string{"title", "tags"}},
+		{"inline first tag", "---\ntags: g|\n---\n", fmCompleteTagInline, "g", []string{"g"}},
+		{"inline after comma", "---\ntags: go, Pr|, rust\n---\n", fmCompleteTagInline, "Pr", []string{"go", "pr", "rust"}},
+		{"flow list", "---\ntags: [go,wo|]\n---\n", fmCompleteTagInline, "wo", []string{"go", "wo"}},
+		{"block item", "---\ntags:\n  - go\n  - pr|\n---\n", fmCompleteTagItem, "pr", []string{"go"}},
+		{"bare indented item", "---\ntags:\n  - go\n  pr|\n---\n", fmCompleteTagItem, "pr", []string{"go"}},
+	}
+	for _, tc := range cases {
+		value, cursor := cursorAt(t, tc.marked)
+		ctx, ok := frontmatterCompletionAt(value, cursor)
+		if !ok {
+			t.Errorf("%s: expected completion", tc.name)
+			continue
+		}
+		if ctx.kind != tc.kind || ctx.prefix != tc.prefix || ctx.end != cursor {
+			t.Errorf("%s: got kind %v prefix %q end %d", tc.name, ctx.kind, ctx.prefix, ctx.end)
+		}
+		if !reflect.DeepEqual(ctx.used, tc.used) {
+			t.Errorf("%s: used = %v, want %v", tc.name, ctx.used, tc.used)
+		}
+	}
+}
+
+func TestFrontmatterCompletionCandidatesSkipUsedAndTyped(t *testing.T) {
+	ctx := frontmatterCompletionContext{prefix: "Pr", used: []string{"projects"}}
+	got := frontmatterCompletionCandidates(ctx, []string{"pr", "project", "projects", "rust", "Project"})
+	if want := []string{"project"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("candidates = %v, want %v", got, want)
+	}
+}
+
+func TestAcceptFrontmatterCompletionAddsPunctuation(t *testing.T) {
+	cases := []struct {
+		marked string
+		item   string
+		want   string
+	}{
+		{"---\nca|\n---\n", "category", "---\ncategory: |\n---\n"},
+		{"---\ntags: go,pr|\n---\n", "project", "---\ntags: go, project|\n---\n"},
+		{"---\ntags:pr|\n---\n", "project", "---\ntags: project|\n---\n"},
+		{"---\ntags: [go, pr|]\n---\n", "project", "---\ntags: [go, project|]\n---\n"},
+		{"---\ntags:\n  pr|\n---\n", "project", "---\ntags:\n  - project|\n---\n"},
+		{"---\ntags:\n  -pr|\n---\n", "project", "---\ntags:\n  - project|\n---\n"},
+	}
+	for _, tc := range cases {
+		value, cursor := cursorAt(t, tc.marked)
+		m := newFocusedEditModel("")
+		m.setEditorValueAndCursorOffset(value, cursor)
+		ctx, ok := frontmatterCompletionAt(value, cursor)
+		if !ok {
+			t.Fatalf("%q: expected completion", tc.marked)
+		}
+		m.fmCompletion = &ctx
+		m.acceptFrontmatterCompletion(tc.item)
+
+		want, wantCursor := cursorAt(t, tc.want)
+		if got := m.editor.Value(); got != want {
+			t.Errorf("%q: value = %q, want %q", tc.marked, got, want)
+		}
+		if got := m.currentEditorCursorOffset(); got != wantCursor {
+			t.Errorf("%q: cursor = %d, want %d", tc.marked, got, wantCursor)
+		}
+	}
+}
+
+func TestEditorAutocompleteCompletesWorkspaceTags(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "---\ntags: [golang, project]\nsource: web\n---\n# A\n")
+	m := newFocusedEditModel("")
+	m.notesDir = root
+
+	value, cursor := cursorAt(t, "---\ntags: go|\n---\n")
+	m.setEditorValueAndCursorOffset(value, cursor)
+	m.maybeTriggerEditorAutocomplete()
+	if !m.isOverlay(overlayWikiAutocomplete) || !reflect.DeepEqual(m.fmCompletionItems, []string{"golang"}) {
+		t.Fatalf("expected tag popup, overlay %v items %v", m.overlay, m.fmCompletionItems)
+	}
+	// j is part of a tag, not a selection key.
+	if _, _, handled := m.handleWikiAutocompleteKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}); handled {
+		t.Fatal("expected j to be typed while completing tags")
+	}
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyTab})
+	if got := m.editor.Value(); got != "---\ntags: golang\n---\n" {
+		t.Fatalf("value = %q", got)
+	}
+	if m.overlay != overlayNone || m.fmCompletion != nil {
+		t.Fatalf("expected popup closed, overlay %v", m.overlay)
+	}
+
+	value, cursor = cursorAt(t, "---\ntags: golang\nso|\n---\n")
+	m.setEditorValueAndCursorOffset(value, cursor)
+	m.maybeTriggerEditorAutocomplete()
+	if !reflect.DeepEqual(m.fmCompletionItems, []string{"source"}) {
+		t.Fatalf("expected observed key, got %v", m.fmCompletionItems)
+	}
+
+	value, cursor = cursorAt(t, "---\ntags: golang\n---\nso|")
+	m.setEditorValueAndCursorOffset(value, cursor)
+	m.maybeTriggerEditorAutocomplete()
+	if m.isOverlay(overlayWikiAutocomplete) || m.fmCompletion != nil {
+		t.Fatal("expected no completion in the note body")
+	}
+}
+
+func TestCurrentTagPrefix(t *testing.T) {
+	tests := []struct {
+		marked string
+		want   string
+		ok     bool
+	}{
+		{"#go|", "go", true},
+		{"see #pro| later", "pro", true},
+		{"x\n#work/pla|", "work/pla", true},
+		{"# |", "", false},
+		{"#|", "", false},
+		{"## He|", "", false},
+		{"issue#12|", "", false},
+		{"a#bc|", "", false},
+		{"#1abc|", "", false},
+		{"#go |", "", false},
+	}
+	for _, tt := range tests {
+		value, cursor := cursorAt(t, tt.marked)
+		got, ok := currentTagPrefix(value, cursor)
+		if got != tt.want || ok != tt.ok {
+			t.Fatalf("currentTagPrefix(%q) = %q, %v; want %q, %v", tt.marked, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestEditorAutocompleteCompletesHashtags(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "---\ntags: [golang, project]\n---\n")
+	mustWriteFile(t, filepath.Join(root, "b.md"), "---\ntags: [project, planning]\n---\n")
+	m := newFocusedEditModel("")
+	m.notesDir = root
+
+	value, cursor := cursorAt(t, "---\ntitle: x\n---\nToday #p| work")
+	m.setEditorValueAndCursorOffset(value, cursor)
+	m.maybeTriggerEditorAutocomplete()
+	if !m.isOverlay(overlayWikiAutocomplete) || !reflect.DeepEqual(m.fmCompletionItems, []string{"project", "planning"}) {
+		t.Fatalf("expected most used tags first, overlay %v items %v", m.overlay, m.fmCompletionItems)
+	}
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.editor.Value(); got != "---\ntitle: x\n---\nToday #project work" {
+		t.Fatalf("value = %q", got)
+	}
+	if got := m.currentEditorCursorOffset(); got != len([]rune("---\ntitle: x\n---\nToday #project")) {
+		t.Fatalf("cursor = %d", got)
+	}
+
+	value, cursor = cursorAt(t, "```\n#p|\n```")
+	m.setEditorValueAndCursorOffset(value, cursor)
+	m.maybeTriggerEditorAutocomplete()
+	if m.isOverlay(overlayWikiAutocomplete) {
+		t.Fatal("expected no hashtag completion inside a code block")
+	}
+}
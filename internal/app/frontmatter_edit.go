@@ -0,0 +1,526 @@
+// frontmatter_edit.go provides round-trip-safe editing of frontmatter blocks.
+//
+// parseFrontmatterAndBody is read-oriented: it normalizes values and forgets
+// layout. Anything that rewrites metadata goes through frontmatterDoc instead,
+// which keeps every line of the note as written and only regenerates the
+// lines of keys that are explicitly changed:
+//
+//   - key order, key spelling, and unknown keys are preserved verbatim;
+//   - untouched values keep their scalar style (plain, 'single', "double",
+//     block scalars) and list style (inline [a, b] or "- item" bullets);
+//   - a touched scalar reuses its previous quote style, and plain values that
+//     YAML would read as another type (numbers, booleans, text containing
+//     ": ") are quoted;
+//   - a touched list keeps its previous style and bullet indentation;
+//   - CRLF line endings, a leading BOM, and the body are left alone.
+//
+// The editor understands the same top-level subset as parseSimpleFrontmatter:
+// a line that starts at column 0 with "key:" begins an entry, and indented or
+// "- " lines that follow belong to it. Nested mappings therefore survive as
+// opaque values. Comments survive as standalone lines but are not otherwise
+// supported: a comment inside a touched entry is dropped with it, and trailing
+// "# ..." on a value line is treated as part of the value.
+package app
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frontmatter keys the app reads or writes.
+const (
+	frontmatterKeyTitle    = "title"
+	frontmatterKeyTags     = "tags"
+	frontmatterKeyCategory = "category"
+	frontmatterKeyDate     = "date"
+	frontmatterKeyAliases  = "aliases"
+	frontmatterKeyCreated  = "created"
+	frontmatterKeyUpdated  = "updated"
+	frontmatterKeyPrivate  = "private"
+	frontmatterKeyColor    = "color"
+	frontmatterKeyType     = "type"
+)
+
+// frontmatterDoc is an editable view of a note's frontmatter. Build one with
+// parseFrontmatterDoc, apply setters, and write back String().
+type frontmatterDoc struct {
+	// content is the original input, returned unchanged when nothing edits a
+	// note that has no frontmatter block.
+	content string
+	bom     string
+	// hasBlock reports whether the note already had a frontmatter block.
+	hasBlock bool
+	// open is the opening delimiter line; tail holds the closing delimiter
+	// and every body line. Lines are split on "\n" and keep any "\r".
+	open    string
+	entries []frontmatterEntry
+	tail    []string
+	// rest is the note without BOM, used as the body when a block is added.
+	rest string
+	// eol is "\r" for CRLF blocks so generated lines match their neighbours.
+	eol     string
+	changed bool
+}
+
+// frontmatterEntry is one top-level key with its continuation lines, or a
+// standalone blank, comment, or unparseable line (key == "").
+type frontmatterEntry struct {
+	key   string
+	lines []string
+}
+
+// parseFrontmatterDoc splits content into editable frontmatter entries. The
+// block detection matches parseFrontmatterAndBody.
+func parseFrontmatterDoc(content string) *frontmatterDoc {
+	const delim = "---"
+
+	doc := &frontmatterDoc{content: content}
+	rest := content
+	if strings.HasPrefix(rest, "\ufeff") {
+		doc.bom = "\ufeff"
+		rest = strings.TrimPrefix(rest, "\ufeff")
+	}
+	doc.rest = rest
+	if !strings.HasPrefix(rest, delim+"\n") && !strings.HasPrefix(rest, delim+"\r\n") {
+		return doc
+	}
+
+	lines := strings.Split(rest, "\n")
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == delim {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return doc
+	}
+
+	doc.hasBlock = true
+	doc.open = lines[0]
+	doc.tail = lines[end:]
+	if strings.HasSuffix(lines[0], "\r") {
+		doc.eol = "\r"
+	}
+	block := lines[1:end]
+	for i := 0; i < len(block); i++ {
+		line := block[i]
+		key, ok := frontmatterLineKey(line)
+		if !ok {
+			doc.entries = append(doc.entries, frontmatterEntry{lines: []string{line}})
+			continue
+		}
+		entry := frontmatterEntry{key: key, lines: []string{line}}
+		for i+1 < len(block) && frontmatterContinues(block, i+1) {
+			i++
+			entry.lines = append(entry.lines, block[i])
+		}
+		doc.entries = append(doc.entries, entry)
+	}
+	return doc
+}
+
+// frontmatterLineKey returns the key of a top-level "key: value" line.
+func frontmatterLineKey(line string) (string, bool) {
+	if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' || line[0] == '-' {
+		return "", false
+	}
+	name, _, ok := strings.Cut(line, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+// frontmatterContinues reports whether block[i] belongs to the entry above
+// it: an indented or bullet line, or a blank line followed by one.
+func frontmatterContinues(block []string, i int) bool {
+	for ; i < len(block); i++ {
+		line := strings.TrimRight(block[i], "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		return line[0] == ' ' || line[0] == '\t' || line[0] == '-'
+	}
+	return false
+}
+
+// String serializes the document. Untouched lines are emitted byte-for-byte.
+func (d *frontmatterDoc) String() string {
+	if !d.changed {
+		return d.content
+	}
+	if !d.hasBlock {
+		if len(d.entries) == 0 {
+			return d.content
+		}
+		out := make([]string, 0, len(d.entries)+2)
+		out = append(out, "---")
+		for _, entry := range d.entries {
+			out = append(out, entry.lines...)
+		}
+		out = append(out, "---")
+		return d.bom + strings.Join(out, "\n") + "\n" + d.rest
+	}
+	out := make([]string, 0, len(d.entries)+len(d.tail)+1)
+	out = append(out, d.open)
+	for _, entry := range d.entries {
+		out = append(out, entry.lines...)
+	}
+	out = append(out, d.tail...)
+	return d.bom + strings.Join(out, "\n")
+}
+
+// find returns the index of the first entry for key (case-insensitive).
+func (d *frontmatterDoc) find(key string) int {
+	for i, entry := range d.entries {
+		if entry.key != "" && strings.EqualFold(entry.key, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// has reports whether key is present.
+func (d *frontmatterDoc) has(key string) bool {
+	return d.find(key) >= 0
+}
+
+// scalar returns the unquoted value of key. Block scalars (| and >) and
+// plain multi-line values are folded the way YAML reads them.
+func (d *frontmatterDoc) scalar(key string) string {
+	i := d.find(key)
+	if i < 0 {
+		return ""
+	}
+	entry := d.entries[i]
+	inline := entry.inlineValue()
+	if len(entry.lines) == 1 {
+		return unquoteFrontmatterScalar(inline)
+	}
+	parts := make([]string, 0, len(entry.lines)-1)
+	for _, line := range entry.lines[1:] {
+		parts = append(parts, strings.TrimSpace(line))
+	}
+	switch {
+	case strings.HasPrefix(inline, "|"):
+		return strings.TrimRight(strings.Join(parts, "\n"), "\n")
+	case strings.HasPrefix(inline, ">"):
+		return strings.TrimSpace(strings.Join(parts, " "))
+	case inline == "":
+		return strings.Join(nonEmpty(parts), " ")
+	default:
+		return unquoteFrontmatterScalar(strings.Join(append([]string{inline}, nonEmpty(parts)...), " "))
+	}
+}
+
+// list returns the items of key in either inline ([a, b] or a, b) or bullet
+// form, unquoted and in file order.
+func (d *frontmatterDoc) list(key string) []string {
+	i := d.find(key)
+	if i < 0 {
+		return nil
+	}
+	entry := d.entries[i]
+	inline := entry.inlineValue()
+	if inline != "" {
+		inline = strings.TrimSuffix(strings.TrimPrefix(inline, "["), "]")
+		var items []string
+		for _, item := range splitInlineList(inline) {
+			if item = unquoteFrontmatterScalar(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	var items []string
+	for _, line := range entry.lines[1:] {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "-") {
+			continue
+		}
+		if item := unquoteFrontmatterScalar(strings.TrimPrefix(line, "-")); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setScalar sets key to value, reusing the previous quote style. An empty
+// value removes the key.
+func (d *frontmatterDoc) setScalar(key, value string) {
+	if value == "" {
+		d.remove(key)
+		return
+	}
+	style := byte(0)
+	if i := d.find(key); i >= 0 {
+		key = d.entries[i].key
+		if inline := d.entries[i].inlineValue(); inline != "" && (inline[0] == '"' || inline[0] == '\'') {
+			style = inline[0]
+		}
+	}
+	if strings.Contains(value, "\n") {
+		lines := []string{key + ": |" + d.eol}
+		for _, line := range strings.Split(value, "\n") {
+			lines = append(lines, "  "+line+d.eol)
+		}
+		d.put(key, lines)
+		return
+	}
+	d.put(key, []string{key + ": " + quoteFrontmatterScalar(value, style) + d.eol})
+}
+
+// setList sets key to items, keeping the previous list style (inline by
+// default) and bullet indentation. Items whose value is unchanged keep their
+// original quoting. An empty list removes the key.
+func (d *frontmatterDoc) setList(key string, items []string) {
+	if len(items) == 0 {
+		d.remove(key)
+		return
+	}
+	bulletPrefix := ""
+	raw := map[string]string{}
+	if i := d.find(key); i >= 0 {
+		entry := d.entries[i]
+		key = entry.key
+		if inline := entry.inlineValue(); inline != "" {
+			inline = strings.TrimSuffix(strings.TrimPrefix(inline, "["), "]")
+			for _, item := range splitInlineList(inline) {
+				raw[unquoteFrontmatterScalar(item)] = item
+			}
+		} else {
+			for _, line := range entry.lines[1:] {
+				trimmed := strings.TrimLeft(line, " \t")
+				if !strings.HasPrefix(trimmed, "-") {
+					continue
+				}
+				if bulletPrefix == "" {
+					bulletPrefix = line[:len(line)-len(trimmed)] + "- "
+				}
+				item := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+				raw[unquoteFrontmatterScalar(item)] = item
+			}
+			if bulletPrefix == "" {
+				bulletPrefix = "  - "
+			}
+		}
+	}
+	format := func(item string, quote func(string) string) string {
+		if existing, ok := raw[item]; ok {
+			return existing
+		}
+		return quote(item)
+	}
+	if bulletPrefix != "" {
+		lines := []string{key + ":" + d.eol}
+		for _, item := range items {
+			lines = append(lines, bulletPrefix+format(item, func(v string) string { return quoteFrontmatterScalar(v, 0) })+d.eol)
+		}
+		d.put(key, lines)
+		return
+	}
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, format(item, quoteFrontmatterListItem))
+	}
+	d.put(key, []string{key + ": [" + strings.Join(quoted, ", ") + "]" + d.eol})
+}
+
+// setBool writes key: true, or removes the key for false.
+func (d *frontmatterDoc) setBool(key string, value bool) {
+	if !value {
+		d.remove(key)
+		return
+	}
+	d.put(key, []string{d.spelling(key) + ": true" + d.eol})
+}
+
+// boolValue reports whether key holds a YAML true value.
+func (d *frontmatterDoc) boolValue(key string) bool {
+	switch strings.ToLower(d.scalar(key)) {
+	case "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// setTime writes key as an RFC 3339 timestamp with offset.
+func (d *frontmatterDoc) setTime(key string, t time.Time) {
+	d.setScalar(key, t.Format(time.RFC3339))
+}
+
+// timeValue parses key with parseNoteDate, keeping the value's own offset.
+func (d *frontmatterDoc) timeValue(key string) (time.Time, bool) {
+	t, _, ok := parseNoteDate(d.scalar(key), nil)
+	return t, ok
+}
+
+// remove deletes every entry for key.
+func (d *frontmatterDoc) remove(key string) {
+	out := d.entries[:0]
+	for _, entry := range d.entries {
+		if entry.key != "" && strings.EqualFold(entry.key, key) {
+			d.changed = true
+			continue
+		}
+		out = append(out, entry)
+	}
+	d.entries = out
+}
+
+// put replaces the first entry for key with lines (dropping duplicates), or
+// appends a new entry at the end of the block.
+func (d *frontmatterDoc) put(key string, lines []string) {
+	i := d.find(key)
+	if i < 0 {
+		d.entries = append(d.entries, frontmatterEntry{key: key, lines: lines})
+		d.changed = true
+		return
+	}
+	if !slices.Equal(d.entries[i].lines, lines) {
+		d.changed = true
+	}
+	d.entries[i].lines = lines
+	for j := len(d.entries) - 1; j > i; j-- {
+		if d.entries[j].key != "" && strings.EqualFold(d.entries[j].key, key) {
+			d.entries = append(d.entries[:j], d.entries[j+1:]...)
+			d.changed = true
+		}
+	}
+}
+
+// spelling returns key as written in the document, or key itself.
+func (d *frontmatterDoc) spelling(key string) string {
+	if i := d.find(key); i >= 0 {
+		return d.entries[i].key
+	}
+	return key
+}
+
+// Typed accessors for the keys the app knows. Setters follow setScalar,
+// setList, setBool, and setTime: empty values and false remove the key.
+
+func (d *frontmatterDoc) title() string              { return d.scalar(frontmatterKeyTitle) }
+func (d *frontmatterDoc) setTitle(v string)          { d.setScalar(frontmatterKeyTitle, v) }
+func (d *frontmatterDoc) category() string           { return d.scalar(frontmatterKeyCategory) }
+func (d *frontmatterDoc) setCategory(v string)       { d.setScalar(frontmatterKeyCategory, v) }
+func (d *frontmatterDoc) color() string              { return d.scalar(frontmatterKeyColor) }
+func (d *frontmatterDoc) setColor(v string)          { d.setScalar(frontmatterKeyColor, v) }
+func (d *frontmatterDoc) noteType() string           { return d.scalar(frontmatterKeyType) }
+func (d *frontmatterDoc) setNoteType(v string)       { d.setScalar(frontmatterKeyType, v) }
+func (d *frontmatterDoc) tags() []string             { return normalizeTagList(d.list(frontmatterKeyTags)) }
+func (d *frontmatterDoc) setTags(tags []string)      { d.setList(frontmatterKeyTags, tags) }
+func (d *frontmatterDoc) aliases() []string          { return d.list(frontmatterKeyAliases) }
+func (d *frontmatterDoc) setAliases(v []string)      { d.setList(frontmatterKeyAliases, v) }
+func (d *frontmatterDoc) private() bool              { return d.boolValue(frontmatterKeyPrivate) }
+func (d *frontmatterDoc) setPrivate(v bool)          { d.setBool(frontmatterKeyPrivate, v) }
+func (d *frontmatterDoc) created() (time.Time, bool) { return d.timeValue(frontmatterKeyCreated) }
+func (d *frontmatterDoc) setCreated(t time.Time)     { d.setTime(frontmatterKeyCreated, t) }
+func (d *frontmatterDoc) updated() (time.Time, bool) { return d.timeValue(frontmatterKeyUpdated) }
+func (d *frontmatterDoc) setUpdated(t time.Time)     { d.setTime(frontmatterKeyUpdated, t) }
+
+// inlineValue returns the trimmed text after "key:" on the entry's first line.
+func (e frontmatterEntry) inlineValue() string {
+	_, value, _ := strings.Cut(e.lines[0], ":")
+	return strings.TrimSpace(value)
+}
+
+// unquoteFrontmatterScalar strips YAML quoting from a single-line value.
+func unquoteFrontmatterScalar(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 {
+		return value
+	}
+	switch {
+	case value[0] == '"' && value[len(value)-1] == '"':
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		return value[1 : len(value)-1]
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return value
+}
+
+// quoteFrontmatterScalar renders value in style ('"', '\”, or 0 for plain).
+// Plain values that YAML would misread are double-quoted.
+func quoteFrontmatterScalar(value string, style byte) string {
+	switch style {
+	case '"':
+		return strconv.Quote(value)
+	case '\'':
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	if frontmatterNeedsQuotes(value) {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// quoteFrontmatterListItem renders an inline-list item, quoting values that
+// would split or close the list.
+func quoteFrontmatterListItem(item string) string {
+	if strings.ContainsAny(item, ",[]") {
+		return strconv.Quote(item)
+	}
+	return quoteFrontmatterScalar(item, 0)
+}
+
+// frontmatterNeedsQuotes reports whether a plain scalar would be read back
+// as something else: a number, boolean, or null, a value with YAML
+// indicators, or one with significant surrounding whitespace.
+func frontmatterNeedsQuotes(value string) bool {
+	if value == "" || strings.TrimSpace(value) != value {
+		return true
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return true
+	}
+	switch strings.ToLower(value) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return true
+	}
+	if strings.ContainsRune("-?:,[]{}#&*!|>'\"%@`", rune(value[0])) {
+		return true
+	}
+	return strings.Contains(value, ": ") || strings.Contains(value, " #")
+}
+
+// splitInlineList splits "a, 'b, c', d" on commas outside quotes.
+func splitInlineList(value string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, strings.TrimSpace(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, strings.TrimSpace(value[start:]))
+}
+
+// nonEmpty drops blank strings.
+func nonEmpty(values []string) []string {
+	out := values[:0:0]
+	for _, value := range values {
+		if value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
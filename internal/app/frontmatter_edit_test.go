@@ -0,0 +1,224 @@
+package app
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFixture exercises the layouts the editor must preserve: unusual
+// key order, quoted numbers, both list styles, block and plain multi-line
+// values, a nested mapping, unknown keys, and blank lines.
+const roundTripFixture = "---\n" +
+	"zeta: keep\n" +
+	"Title: 'Quarterly plan'\n" +
+	"version: \"1.10\"\n" +
+	"zip: '02134'\n" +
+	"tags: [go, 'cli, tools']\n" +
+	"aliases:\n" +
+	"    - plan\n" +
+	"    - \"Q3 plan\"\n" +
+	"\n" +
+	"summary: |\n" +
+	"  first line\n" +
+	"\n" +
+	"  second line\n" +
+	"folded: >\n" +
+	"  one\n" +
+	"  two\n" +
+	"plain: starts here\n" +
+	"  and continues\n" +
+	"nested:\n" +
+	"  child: value\n" +
+	"  other: 2\n" +
+	"unknown_key:   spaced   value\n" +
+	"---\n" +
+	"# Body\n\nkept  \n"
+
+func TestFrontmatterDocRoundTripsUnchangedContent(t *testing.T) {
+	for _, content := range []string{
+		roundTripFixture,
+		"# No frontmatter\n",
+		"---\nunterminated: true\n",
+		"\ufeff---\r\ntitle: T\r\n---\r\nbody\r\n",
+		"",
+	} {
+		doc := parseFrontmatterDoc(content)
+		if got := doc.String(); got != content {
+			t.Fatalf("expected untouched round trip.\nwant: %q\ngot:  %q", content, got)
+		}
+	}
+}
+
+func TestFrontmatterDocReadsKnownFields(t *testing.T) {
+	doc := parseFrontmatterDoc(roundTripFixture)
+	if got := doc.title(); got != "Quarterly plan" {
+		t.Fatalf("title: got %q", got)
+	}
+	if got := doc.scalar("version"); got != "1.10" {
+		t.Fatalf("version: got %q", got)
+	}
+	if got := doc.tags(); !slices.Equal(got, []string{"go", "cli, tools"}) {
+		t.Fatalf("tags: got %q", got)
+	}
+	if got := doc.aliases(); !slices.Equal(got, []string{"plan", "Q3 plan"}) {
+		t.Fatalf("aliases: got %q", got)
+	}
+	if got := doc.scalar("summary"); got != "first line\n\nsecond line" {
+		t.Fatalf("summary: got %q", got)
+	}
+	if got := doc.scalar("folded"); got != "one two" {
+		t.Fatalf("folded: got %q", got)
+	}
+	if got := doc.scalar("plain"); got != "starts here and continues" {
+		t.Fatalf("plain: got %q", got)
+	}
+	if doc.private() || doc.has("private") {
+		t.Fatal("expected private to be absent")
+	}
+}
+
+func TestFrontmatterDocEditsOnlyTouchedLines(t *testing.T) {
+	cases := []struct {
+		name string
+		edit func(*frontmatterDoc)
+		from string
+		to   string
+	}{
+		{
+			name: "title keeps single quotes and key spelling",
+			edit: func(d *frontmatterDoc) { d.setTitle("It's done") },
+			from: "Title: 'Quarterly plan'\n",
+			to:   "Title: 'It''s done'\n",
+		},
+		{
+			name: "inline tags stay inline",
+			edit: func(d *frontmatterDoc) { d.setTags([]string{"go", "a,b"}) },
+			from: "tags: [go, 'cli, tools']\n",
+			to:   "tags: [go, \"a,b\"]\n",
+		},
+		{
+			name: "bullet aliases keep indentation",
+			edit: func(d *frontmatterDoc) { d.setAliases([]string{"plan", "2024"}) },
+			from: "aliases:\n    - plan\n    - \"Q3 plan\"\n",
+			to:   "aliases:\n    - plan\n    - \"2024\"\n",
+		},
+		{
+			name: "multi-line value replaced as a whole",
+			edit: func(d *frontmatterDoc) { d.setScalar("summary", "short") },
+			from: "summary: |\n  first line\n\n  second line\n",
+			to:   "summary: short\n",
+		},
+		{
+			name: "nested mapping removed as a whole",
+			edit: func(d *frontmatterDoc) { d.remove("nested") },
+			from: "nested:\n  child: value\n  other: 2\n",
+			to:   "",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := parseFrontmatterDoc(roundTripFixture)
+			tc.edit(doc)
+			want := replaceOnce(t, roundTripFixture, tc.from, tc.to)
+			if got := doc.String(); got != want {
+				t.Fatalf("unexpected rewrite.\nwant: %q\ngot:  %q", want, got)
+			}
+		})
+	}
+}
+
+func TestFrontmatterDocAppendsNewKeysBeforeClosingDelimiter(t *testing.T) {
+	doc := parseFrontmatterDoc(roundTripFixture)
+	doc.setCategory("2024")
+	doc.setPrivate(true)
+	doc.setColor("#ff0000")
+	doc.setNoteType("meeting: weekly")
+	stamp := time.Date(2026, 2, 7, 9, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+	doc.setCreated(stamp)
+
+	added := "category: \"2024\"\n" +
+		"private: true\n" +
+		"color: \"#ff0000\"\n" +
+		"type: \"meeting: weekly\"\n" +
+		"created: 2026-02-07T09:30:00-05:00\n"
+	want := replaceOnce(t, roundTripFixture, "---\n# Body", added+"---\n# Body")
+	got := doc.String()
+	if got != want {
+		t.Fatalf("unexpected rewrite.\nwant: %q\ngot:  %q", want, got)
+	}
+
+	reread := parseFrontmatterDoc(got)
+	if reread.category() != "2024" || !reread.private() || reread.color() != "#ff0000" || reread.noteType() != "meeting: weekly" {
+		t.Fatalf("expected typed values to read back, got category=%q private=%v color=%q type=%q",
+			reread.category(), reread.private(), reread.color(), reread.noteType())
+	}
+	if created, ok := reread.created(); !ok || !created.Equal(stamp) {
+		t.Fatalf("expected created to read back, got %v %v", created, ok)
+	}
+	meta, body := parseFrontmatterAndBody(got)
+	if meta.Category != "2024" || body != "# Body\n\nkept  \n" {
+		t.Fatalf("expected read-side parser to agree, got %q / %q", meta.Category, body)
+	}
+}
+
+func TestFrontmatterDocRemovesAndDedupesKeys(t *testing.T) {
+	content := "---\ntitle: A\ntags: [x]\ntitle: B\n---\nbody\n"
+	doc := parseFrontmatterDoc(content)
+	doc.setTitle("C")
+	if want := "---\ntitle: C\ntags: [x]\n---\nbody\n"; doc.String() != want {
+		t.Fatalf("expected duplicate key collapsed into the first, got %q", doc.String())
+	}
+
+	doc = parseFrontmatterDoc(content)
+	doc.setPrivate(false)
+	doc.setAliases(nil)
+	if doc.String() != content {
+		t.Fatalf("expected removing absent keys to leave content unchanged, got %q", doc.String())
+	}
+}
+
+func TestFrontmatterDocIdempotentSetKeepsContent(t *testing.T) {
+	doc := parseFrontmatterDoc(roundTripFixture)
+	doc.setTitle(doc.title())
+	doc.setScalar("version", doc.scalar("version"))
+	doc.setAliases(doc.aliases())
+	if got := doc.String(); got != roundTripFixture {
+		t.Fatalf("expected re-setting current values to be a no-op.\nwant: %q\ngot:  %q", roundTripFixture, got)
+	}
+}
+
+func TestFrontmatterDocPreservesCRLFAndBOM(t *testing.T) {
+	content := "\ufeff---\r\ntitle: T\r\ntags:\r\n  - a\r\n---\r\nbody\r\n"
+	doc := parseFrontmatterDoc(content)
+	doc.setTags([]string{"a", "b"})
+	doc.setUpdated(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	want := "\ufeff---\r\ntitle: T\r\ntags:\r\n  - a\r\n  - b\r\nupdated: 2026-01-02T03:04:05Z\r\n---\r\nbody\r\n"
+	if got := doc.String(); got != want {
+		t.Fatalf("unexpected rewrite.\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+func TestFrontmatterNeedsQuotes(t *testing.T) {
+	quoted := []string{"1.10", "42", "true", "No", "null", "- item", "a: b", "x #y", " padded", "#tag", "[x]"}
+	for _, value := range quoted {
+		if !frontmatterNeedsQuotes(value) {
+			t.Fatalf("expected %q to need quotes", value)
+		}
+	}
+	plain := []string{"plan", "2026-02-07T09:30:00-05:00", "a:b", "C#", "two words"}
+	for _, value := range plain {
+		if frontmatterNeedsQuotes(value) {
+			t.Fatalf("expected %q to stay plain", value)
+		}
+	}
+}
+
+func replaceOnce(t *testing.T, s, old, new string) string {
+	t.Helper()
+	if !strings.Contains(s, old) {
+		t.Fatalf("fixture does not contain %q", old)
+	}
+	return strings.Replace(s, old, new, 1)
+}
@@ -0,0 +1,565 @@
+// frontmatter_form.go implements the structured frontmatter editor (Shift+E).
+//
+// The selected note's frontmatter is parsed into one row per key: title,
+// tags, category, and date are always listed, followed by every other
+// top-level key in file order. The focused row is edited in the shared input
+// widget; other keys are typed as "key: value" so they can be renamed, and
+// Ctrl+N adds one. Nested mappings are shown read-only.
+//
+// A note whose frontmatter the form cannot represent faithfully (lines that
+// are not "key: value", duplicate keys, unterminated quotes or lists, or a
+// block that is never closed) does not open: the first problem is shown in
+// the status bar so nothing is silently dropped. Saving rewrites only the
+// changed keys through frontmatterDoc, so the body, key order, and untouched
+// values stay as written; a note without frontmatter gets the block rendered
+// by NoteMetadata.toFrontmatter.
+package app
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// frontmatterField is one row of the frontmatter form.
+type frontmatterField struct {
+	// key is the fixed key of a known row, or the key the row had when the
+	// form opened ("" for rows added in the form).
+	key string
+	// text is the editable value: the value alone for fixed rows, or
+	// "key: value" for the others.
+	text string
+	// original is text as it was when the form opened.
+	original string
+	fixed    bool
+	// list marks keys stored as a YAML list; the form shows them comma
+	// separated and writes them back as a list.
+	list bool
+	// readOnly marks nested values the form cannot edit.
+	readOnly bool
+}
+
+// frontmatterForm is the state of modeFrontmatterForm.
+type frontmatterForm struct {
+	path   string
+	fields []frontmatterField
+	cursor int
+	// customKeys are the keys of the non-fixed rows when the form opened,
+	// so deleted and renamed rows can be removed from the note.
+	customKeys []string
+}
+
+// frontmatterFormKeys are the rows every form starts with.
+var frontmatterFormKeys = []string{frontmatterKeyTitle, frontmatterKeyTags, frontmatterKeyCategory, frontmatterKeyDate}
+
+// toFrontmatter renders meta as a frontmatter block, including the
+// delimiters and a trailing newline, or "" when meta has no fields. Known
+// keys come first in a fixed order, then Extra in order; values that YAML
+// would misread are quoted.
+func (meta NoteMetadata) toFrontmatter() string {
+	var lines []string
+	add := func(key, value string) {
+		if value != "" {
+			lines = append(lines, key+": "+quoteFrontmatterScalar(value, 0))
+		}
+	}
+	add(frontmatterKeyTitle, meta.Title)
+	if len(meta.Tags) > 0 {
+		items := make([]string, 0, len(meta.Tags))
+		for _, tag := range meta.Tags {
+			items = append(items, quoteFrontmatterListItem(tag))
+		}
+		lines = append(lines, frontmatterKeyTags+": ["+strings.Join(items, ", ")+"]")
+	}
+	add(frontmatterKeyCategory, meta.Category)
+	add(frontmatterKeyDate, meta.Date)
+	if meta.WordGoal > 0 {
+		lines = append(lines, "word_goal: "+strconv.Itoa(meta.WordGoal))
+	}
+	if meta.EditorNoWrap {
+		lines = append(lines, "editor_wrap: false")
+	}
+	for _, field := range meta.Extra {
+		add(field.Key, field.Value)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "---\n" + strings.Join(lines, "\n") + "\n---\n"
+}
+
+// frontmatterProblems lists what in content's frontmatter the form cannot
+// round-trip, with 1-based file line numbers.
+func frontmatterProblems(content string) []string {
+	doc := parseFrontmatterDoc(content)
+	if !doc.hasBlock {
+		rest := doc.rest
+		if strings.HasPrefix(rest, "---\n") || strings.HasPrefix(rest, "---\r\n") {
+			return []string{"line 1: frontmatter block is never closed with ---"}
+		}
+		return nil
+	}
+	var problems []string
+	seen := map[string]bool{}
+	lineNo := 2
+	for _, entry := range doc.entries {
+		first := strings.TrimSpace(entry.lines[0])
+		switch {
+		case entry.key == "":
+			if first != "" && !strings.HasPrefix(first, "#") {
+				problems = append(problems, fmt.Sprintf("line %d: expected key: value, found %q", lineNo, first))
+			}
+		case seen[strings.ToLower(entry.key)]:
+			problems = append(problems, fmt.Sprintf("line %d: duplicate key %q", lineNo, entry.key))
+		default:
+			seen[strings.ToLower(entry.key)] = true
+			if problem := frontmatterValueProblem(entry.inlineValue()); problem != "" && len(entry.lines) == 1 {
+				problems = append(problems, fmt.Sprintf("line %d: %s in %q", lineNo, problem, entry.key))
+			}
+		}
+		lineNo += len(entry.lines)
+	}
+	return problems
+}
+
+// frontmatterValueProblem reports an unterminated quote or inline list.
+func frontmatterValueProblem(value string) string {
+	if value == "" {
+		return ""
+	}
+	switch value[0] {
+	case '"', '\'':
+		if len(value) < 2 || value[len(value)-1] != value[0] {
+			return "unterminated quote"
+		}
+	case '[':
+		if !strings.HasSuffix(value, "]") {
+			return "unclosed list"
+		}
+	case '{':
+		if !strings.HasSuffix(value, "}") {
+			return "unclosed mapping"
+		}
+	}
+	return ""
+}
+
+// newFrontmatterForm builds the form rows for content.
+func newFrontmatterForm(path, content string) *frontmatterForm {
+	doc := parseFrontmatterDoc(content)
+	form := &frontmatterForm{path: path}
+	for _, key := range frontmatterFormKeys {
+		field := frontmatterField{key: key, fixed: true}
+		if key == frontmatterKeyTags {
+			field.list = true
+			field.text = strings.Join(doc.tags(), ", ")
+		} else {
+			field.text = doc.scalar(key)
+		}
+		field.original = field.text
+		form.fields = append(form.fields, field)
+	}
+	for _, entry := range doc.entries {
+		if entry.key == "" || isFrontmatterFormKey(entry.key) {
+			continue
+		}
+		field := frontmatterField{key: entry.key}
+		value := doc.scalar(entry.key)
+		switch {
+		case frontmatterEntryIsNested(entry):
+			field.readOnly = true
+			value = "(nested value, edit it in the note)"
+		case frontmatterEntryIsList(entry):
+			field.list = true
+			value = strings.Join(doc.list(entry.key), ", ")
+		}
+		field.text = entry.key + ": " + value
+		field.original = field.text
+		form.fields = append(form.fields, field)
+		form.customKeys = append(form.customKeys, entry.key)
+	}
+	return form
+}
+
+// isFrontmatterFormKey reports whether key has a fixed row.
+func isFrontmatterFormKey(key string) bool {
+	for _, fixed := range frontmatterFormKeys {
+		if strings.EqualFold(key, fixed) {
+			return true
+		}
+	}
+	return false
+}
+
+// frontmatterEntryIsList reports whether entry holds an inline [a, b] or
+// bullet list.
+func frontmatterEntryIsList(entry frontmatterEntry) bool {
+	inline := entry.inlineValue()
+	if inline != "" {
+		return strings.HasPrefix(inline, "[")
+	}
+	for _, line := range entry.lines[1:] {
+		if strings.HasPrefix(strings.TrimSpace(line), "-") {
+			return true
+		}
+	}
+	return false
+}
+
+// frontmatterEntryIsNested reports whether entry is a nested mapping: an
+// empty inline value followed by indented lines that are not list items.
+func frontmatterEntryIsNested(entry frontmatterEntry) bool {
+	if entry.inlineValue() != "" {
+		return false
+	}
+	for _, line := range entry.lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "-") {
+			return true
+		}
+	}
+	return false
+}
+
+// startFrontmatterFormSelected opens the form for the selected note.
+func (m *Model) startFrontmatterFormSelected() {
+	item := m.selectedItem()
+	if item == nil || item.isDir || !hasSuffixCaseInsensitive(item.path, ".md") {
+		m.status = "Select a markdown note to edit frontmatter"
+		return
+	}
+	if !isWithinRoot(m.notesDir, item.path) {
+		m.status = "Cannot edit frontmatter outside notes directory"
+		return
+	}
+	content, err := os.ReadFile(item.path)
+	if err != nil {
+		m.setStatusError("Error reading note", err, "path", item.path)
+		return
+	}
+	if problems := frontmatterProblems(string(content)); len(problems) > 0 {
+		m.status = frontmatterProblemStatus(problems)
+		return
+	}
+	m.fmForm = newFrontmatterForm(item.path, string(content))
+	m.mode = modeFrontmatterForm
+	m.showHelp = false
+	m.focusFrontmatterField(0)
+	m.status = "Frontmatter: ↑/↓ move, Ctrl+N add key, Ctrl+S save, Esc cancel"
+}
+
+// frontmatterProblemStatus summarizes problems for the status bar.
+func frontmatterProblemStatus(problems []string) string {
+	status := "Fix the frontmatter in the editor first: " + problems[0]
+	if len(problems) > 1 {
+		status += fmt.Sprintf(" (+%d more)", len(problems)-1)
+	}
+	return status
+}
+
+// focusFrontmatterField stores the input into the focused row and moves the
+// input to row i.
+func (m *Model) focusFrontmatterField(i int) {
+	form := m.fmForm
+	if i < 0 || i >= len(form.fields) {
+		return
+	}
+	form.cursor = i
+	field := form.fields[i]
+	m.input.Reset()
+	m.input.Placeholder = "Value"
+	if !field.fixed {
+		m.input.Placeholder = "key: value"
+	}
+	m.input.SetValue(field.text)
+	m.input.CursorEnd()
+	m.input.Focus()
+}
+
+// storeFrontmatterInput copies the input into the focused row.
+func (m *Model) storeFrontmatterInput() {
+	form := m.fmForm
+	if field := &form.fields[form.cursor]; !field.readOnly {
+		field.text = m.input.Value()
+	}
+}
+
+// handleFrontmatterFormKey processes keypresses in the form.
+func (m *Model) handleFrontmatterFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	form := m.fmForm
+	switch msg.String() {
+	case "esc":
+		m.fmForm = nil
+		m.mode = modeBrowse
+		m.status = "Frontmatter edit cancelled"
+		return m, nil
+	case "ctrl+s":
+		m.storeFrontmatterInput()
+		return m.saveFrontmatterForm()
+	case "up", "shift+tab":
+		m.storeFrontmatterInput()
+		m.focusFrontmatterField(form.cursor - 1)
+	case "down", "tab", "enter":
+		m.storeFrontmatterInput()
+		m.focusFrontmatterField(form.cursor + 1)
+	case "ctrl+n":
+		m.storeFrontmatterInput()
+		form.fields = append(form.fields, frontmatterField{})
+		m.focusFrontmatterField(len(form.fields) - 1)
+	case "ctrl+d":
+		field := form.fields[form.cursor]
+		switch {
+		case field.fixed:
+			m.input.SetValue("")
+		case field.readOnly:
+			m.status = "Nested values can only be removed in the note"
+		default:
+			form.fields = append(form.fields[:form.cursor], form.fields[form.cursor+1:]...)
+			m.focusFrontmatterField(min(form.cursor, len(form.fields)-1))
+		}
+	default:
+		if form.fields[form.cursor].readOnly {
+			m.status = "Nested values can only be edited in the note"
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// frontmatterChange is one key the form writes back; an empty value removes
+// the key.
+type frontmatterChange struct {
+	key   string
+	value string
+	list  bool
+}
+
+// changes validates the rows and returns the keys to remove, then the keys
+// to set, in row order.
+func (form *frontmatterForm) changes() (removed []string, set []frontmatterChange, err error) {
+	seen := map[string]bool{}
+	for _, key := range frontmatterFormKeys {
+		seen[key] = true
+	}
+	kept := map[string]bool{}
+	for _, field := range form.fields {
+		if field.readOnly {
+			kept[strings.ToLower(field.key)] = true
+			continue
+		}
+		if field.fixed {
+			if field.text != field.original {
+				set = append(set, frontmatterChange{key: field.key, value: strings.TrimSpace(field.text), list: field.list})
+			}
+			continue
+		}
+		if strings.TrimSpace(field.text) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field.text, ":")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" {
+			return nil, nil, fmt.Errorf("Enter %q as key: value", field.text)
+		}
+		if _, valid := frontmatterLineKey(key + ":"); !valid {
+			return nil, nil, fmt.Errorf("Invalid frontmatter key %q", key)
+		}
+		if seen[strings.ToLower(key)] {
+			return nil, nil, fmt.Errorf("Duplicate frontmatter key %q", key)
+		}
+		seen[strings.ToLower(key)] = true
+		renamed := !strings.EqualFold(key, field.key)
+		if !renamed {
+			kept[strings.ToLower(key)] = true
+		}
+		if renamed || field.text != field.original {
+			set = append(set, frontmatterChange{key: key, value: value, list: field.list})
+		}
+	}
+	for _, key := range form.customKeys {
+		if !kept[strings.ToLower(key)] {
+			removed = append(removed, key)
+		}
+	}
+	return removed, set, nil
+}
+
+// applyFrontmatterChanges rewrites content with the form's changes. A note
+// without frontmatter gets a new block from toFrontmatter.
+func applyFrontmatterChanges(content string, removed []string, set []frontmatterChange) (string, error) {
+	doc := parseFrontmatterDoc(content)
+	meta := NoteMetadata{}
+	for _, key := range removed {
+		doc.remove(key)
+	}
+	for _, change := range set {
+		var items []string
+		if change.list {
+			if strings.EqualFold(change.key, frontmatterKeyTags) {
+				tags, err := parseTagInput(change.value)
+				if err != nil {
+					return "", err
+				}
+				items = tags
+			} else {
+				for _, item := range splitInlineList(change.value) {
+					if item = unquoteFrontmatterScalar(item); item != "" {
+						items = append(items, item)
+					}
+				}
+			}
+		}
+		switch {
+		case change.list:
+			doc.setList(change.key, items)
+		default:
+			doc.setScalar(change.key, change.value)
+		}
+		if doc.hasBlock {
+			continue
+		}
+		switch strings.ToLower(change.key) {
+		case frontmatterKeyTitle:
+			meta.Title = change.value
+		case frontmatterKeyTags:
+			meta.Tags = items
+		case frontmatterKeyCategory:
+			meta.Category = change.value
+		case frontmatterKeyDate:
+			meta.Date = change.value
+		default:
+			meta.Extra = append(meta.Extra, MetadataField{Key: change.key, Value: change.value})
+		}
+	}
+	if !doc.hasBlock {
+		block := meta.toFrontmatter()
+		if block == "" {
+			return content, nil
+		}
+		return doc.bom + block + doc.rest, nil
+	}
+	return doc.String(), nil
+}
+
+// saveFrontmatterForm writes the form back to the note. While a git
+// operation holds the interlock the form stays open so nothing is lost.
+func (m *Model) saveFrontmatterForm() (tea.Model, tea.Cmd) {
+	if m.rejectWhileLocked("saving frontmatter") {
+		return m, nil
+	}
+	form := m.fmForm
+	path := form.path
+	if !isWithinRoot(m.notesDir, path) {
+		m.fmForm = nil
+		m.mode = modeBrowse
+		m.status = "Invalid frontmatter target"
+		return m, nil
+	}
+	removed, set, err := form.changes()
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		m.setStatusError("Error reading note", err, "path", path)
+		return m, nil
+	}
+	if problems := frontmatterProblems(string(content)); len(problems) > 0 {
+		m.status = frontmatterProblemStatus(problems)
+		return m, nil
+	}
+	updated, err := applyFrontmatterChanges(string(content), removed, set)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	m.fmForm = nil
+	m.mode = modeBrowse
+	if updated == string(content) {
+		m.status = "Frontmatter unchanged"
+		return m, nil
+	}
+	if err := os.WriteFile(path, []byte(updated), FilePermission); err != nil {
+		m.setStatusError("Error saving frontmatter", err, "path", path)
+		return m, nil
+	}
+	m.invalidateTreeMetadataPath(path)
+	delete(m.renderCache, path)
+	effects := mutationEffects{
+		upsertPaths:     []string{path},
+		refreshGit:      true,
+		rebuildKeepPath: path,
+	}
+	if m.currentFile == path {
+		effects.setCurrentFile = path
+	}
+	cmd := m.applyMutationEffects(effects)
+	n := len(removed) + len(set)
+	noun := "keys"
+	if n == 1 {
+		noun = "key"
+	}
+	m.status = fmt.Sprintf("Frontmatter saved (%d %s changed)", n, noun)
+	return m, cmd
+}
+
+// renderFrontmatterForm draws the form in the right pane, scrolled so the
+// focused row stays visible.
+func (m *Model) renderFrontmatterForm(width, height int) string {
+	form := m.fmForm
+	header := []string{
+		titleStyle.Render("Edit frontmatter"),
+		"Note: " + m.displayRelative(form.path),
+		"",
+	}
+	footer := []string{
+		"",
+		mutedStyle.Render("↑/↓ move  Ctrl+N add key  Ctrl+D remove  Ctrl+S save  Esc cancel"),
+	}
+	labelWidth := 0
+	for _, key := range frontmatterFormKeys {
+		labelWidth = max(labelWidth, len(key))
+	}
+	rows := make([]string, 0, len(form.fields))
+	for i, field := range form.fields {
+		prefix := "  "
+		if i == form.cursor {
+			prefix = "> "
+		}
+		label := ""
+		if field.fixed {
+			label = fmt.Sprintf("%-*s  ", labelWidth, field.key)
+		}
+		if i == form.cursor && !field.readOnly {
+			m.input.Width = max(1, width-len(prefix)-len(label)-2)
+			rows = append(rows, prefix+label+m.input.View())
+			continue
+		}
+		line := prefix + label + field.text
+		if field.readOnly {
+			line = mutedStyle.Render(truncate(line, width))
+		} else if i == form.cursor {
+			line = selectedStyle.Render(truncate(line, width))
+		}
+		rows = append(rows, truncate(line, width))
+	}
+	visible := max(1, height-len(header)-len(footer))
+	start := 0
+	if form.cursor >= visible {
+		start = form.cursor - visible + 1
+	}
+	end := min(len(rows), start+visible)
+	lines := append(header, rows[start:end]...)
+	lines = append(lines, footer...)
+	return strings.Join(lines[:min(height, len(lines))], "\n")
+}
@@ -0,0 +1,212 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNoteMetadataToFrontmatter(t *testing.T) {
+	meta := NoteMetadata{
+		Title:        "Plan: Q3",
+		Tags:         []string{"go", "cli"},
+		Category:     "work",
+		Date:         "2026-03-04",
+		WordGoal:     500,
+		EditorNoWrap: true,
+		Extra:        []MetadataField{{Key: "status", Value: "draft"}, {Key: "priority", Value: "3"}},
+	}
+	want := "---\n" +
+		"title: \"Plan: Q3\"\n" +
+		"tags: [go, cli]\n" +
+		"category: work\n" +
+		"date: 2026-03-04\n" +
+		"word_goal: 500\n" +
+		"editor_wrap: false\n" +
+		"status: draft\n" +
+		"priority: \"3\"\n" +
+		"---\n"
+	got := meta.toFrontmatter()
+	if got != want {
+		t.Fatalf("unexpected frontmatter.\nwant: %q\ngot:  %q", want, got)
+	}
+	back, _ := parseFrontmatterAndBody(got + "body\n")
+	if back.Title != meta.Title || back.Category != meta.Category || back.Date != meta.Date || back.WordGoal != 500 || !back.EditorNoWrap {
+		t.Fatalf("frontmatter did not round-trip: %+v", back)
+	}
+	if (NoteMetadata{}).toFrontmatter() != "" {
+		t.Fatal("expected empty metadata to render no block")
+	}
+}
+
+func TestFrontmatterProblems(t *testing.T) {
+	cases := map[string]string{
+		"---\ntitle: ok\njust text\n---\n":        "line 3: expected key: value",
+		"---\ntitle: a\nTitle: b\n---\n":          "line 3: duplicate key",
+		"---\ntitle: \"open\n---\n":               "line 2: unterminated quote",
+		"---\ntags: [a, b\n---\n":                 "line 2: unclosed list",
+		"---\ntitle: never closed\n\nbody text\n": "line 1: frontmatter block is never closed",
+	}
+	for content, want := range cases {
+		problems := frontmatterProblems(content)
+		if len(problems) == 0 || !strings.HasPrefix(problems[0], want) {
+			t.Errorf("frontmatterProblems(%q) = %v, want prefix %q", content, problems, want)
+		}
+	}
+	for _, content := range []string{"no frontmatter\n", "---\n# comment\ntitle: ok\nmeta:\n  nested: 1\n---\n"} {
+		if problems := frontmatterProblems(content); len(problems) != 0 {
+			t.Errorf("frontmatterProblems(%q) = %v, want none", content, problems)
+		}
+	}
+}
+
+func TestFrontmatterFormSavesChangedKeysOnly(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "plan.md")
+	mustWriteFile(t, path, "---\n"+
+		"title: 'Plan'\n"+
+		"tags:\n"+
+		"  - inbox\n"+
+		"status: draft\n"+
+		"aliases: [p, plan]\n"+
+		"old: remove me\n"+
+		"meta:\n"+
+		"  nested: 1\n"+
+		"---\n"+
+		"# Body\n\nkept  \n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	reselectTreeItem(t, m, path)
+	m.startFrontmatterFormSelected()
+	if m.mode != modeFrontmatterForm {
+		t.Fatalf("expected frontmatter form mode, got %v (status %q)", m.mode, m.status)
+	}
+	var texts []string
+	for _, field := range m.fmForm.fields {
+		texts = append(texts, field.text)
+	}
+	wantTexts := "Plan|inbox|||status: draft|aliases: p, plan|old: remove me|meta: (nested value, edit it in the note)"
+	if got := strings.Join(texts, "|"); got != wantTexts {
+		t.Fatalf("unexpected rows.\nwant: %q\ngot:  %q", wantTexts, got)
+	}
+
+	m.input.SetValue("Plan B")
+	m.dispatchKey(keyPress("down"))
+	m.input.SetValue("inbox, projects")
+	m.dispatchKey(keyPress("down"))
+	m.input.SetValue("work")
+	for m.fmForm.cursor < 5 {
+		m.dispatchKey(keyPress("down"))
+	}
+	m.input.SetValue("aliases: p, plan, the plan")
+	m.dispatchKey(keyPress("down"))
+	m.dispatchKey(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m.dispatchKey(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m.input.SetValue("priority: high")
+	m.dispatchKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+
+	if m.mode != modeBrowse {
+		t.Fatalf("expected browse mode after save, got %v (status %q)", m.mode, m.status)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read note: %v", err)
+	}
+	want := "---\n" +
+		"title: 'Plan B'\n" +
+		"tags:\n" +
+		"  - inbox\n" +
+		"  - projects\n" +
+		"status: draft\n" +
+		"aliases: [p, plan, the plan]\n" +
+		"meta:\n" +
+		"  nested: 1\n" +
+		"category: work\n" +
+		"priority: high\n" +
+		"---\n" +
+		"# Body\n\nkept  \n"
+	if string(data) != want {
+		t.Fatalf("unexpected note content.\nwant: %q\ngot:  %q", want, string(data))
+	}
+	assertSearchHasQuery(t, m.searchIndex, "tag:projects", true)
+}
+
+func TestFrontmatterFormAddsBlockToPlainNote(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "plain.md")
+	mustWriteFile(t, path, "# Plain\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	reselectTreeItem(t, m, path)
+	m.startFrontmatterFormSelected()
+	m.input.SetValue("Plain note")
+	m.dispatchKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read note: %v", err)
+	}
+	if want := "---\ntitle: Plain note\n---\n# Plain\n"; string(data) != want {
+		t.Fatalf("unexpected note content.\nwant: %q\ngot:  %q", want, string(data))
+	}
+}
+
+func TestFrontmatterFormSaveWaitsForGitOperation(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "plain.md")
+	mustWriteFile(t, path, "# Plain\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	reselectTreeItem(t, m, path)
+	m.startFrontmatterFormSelected()
+	m.input.SetValue("Plain note")
+	m.acquireOpLock("git pull")
+	m.dispatchKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+
+	if m.mode != modeFrontmatterForm || m.fmForm == nil || !strings.Contains(m.status, "Git pull in progress: saving frontmatter") {
+		t.Fatalf("expected the save to wait with the form open, mode %v status %q", m.mode, m.status)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "# Plain\n" {
+		t.Fatalf("note changed on disk: %q", data)
+	}
+
+	m.releaseOpLock(m.opLock.token)
+	m.dispatchKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "title: Plain note") {
+		t.Fatalf("expected the form saved once the pull finished, got %q", data)
+	}
+}
+
+func TestFrontmatterFormRefusesInvalidFrontmatter(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "broken.md")
+	content := "---\ntitle: ok\nnot a pair\n---\nbody\n"
+	mustWriteFile(t, path, content)
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	reselectTreeItem(t, m, path)
+	m.startFrontmatterFormSelected()
+	if m.mode != modeBrowse || m.fmForm != nil {
+		t.Fatalf("expected the form to stay closed, got mode %v", m.mode)
+	}
+	if !strings.Contains(m.status, "line 3: expected key: value") {
+		t.Fatalf("expected the problem in the status, got %q", m.status)
+	}
+}
+
+func TestFrontmatterFormRejectsDuplicateCustomKey(t *testing.T) {
+	form := &frontmatterForm{fields: []frontmatterField{
+		{key: frontmatterKeyTitle, fixed: true},
+		{text: "Title: again"},
+	}}
+	if _, _, err := form.changes(); err == nil || !strings.Contains(err.Error(), "Duplicate") {
+		t.Fatalf("expected duplicate key error, got %v", err)
+	}
+}
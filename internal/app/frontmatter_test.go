@@ -36,4 +36,61 @@ func TestParseSearchQueryTagTokens(t *testing.T) {
 	if len(q.tagTerms) != 2 || q.tagTerms[0] != "go" || q.tagTerms[1] != "cli" {
 		t.Fatalf("unexpected tag terms: %#v", q.tagTerms)
 	}
+
+	q = parseSearchQuery("tag:todo -tag:Done")
+	if len(q.tagTerms) != 1 || len(q.excludedTags) != 1 || q.excludedTags[0] != "done" {
+		t.Fatalf("unexpected tags: %#v excluded %#v", q.tagTerms, q.excludedTags)
+	}
+}
+
+func TestParseFrontmatterCollectsExtraFieldsInOrder(t *testing.T) {
+	content := "---\n" +
+		"title: Plan\n" +
+		"Author: \"Ada\"\n" +
+		"status: draft\n" +
+		"aliases:\n" +
+		"  - roadmap\n" +
+		"  - 'q3 plan'\n" +
+		"---\n" +
+		"body\n"
+
+	meta, _ := parseFrontmatterAndBody(content)
+	want := []MetadataField{
+		{Key: "Author", Value: "Ada"},
+		{Key: "status", Value: "draft"},
+		{Key: "aliases", Value: "roadmap, q3 plan"},
+	}
+	if len(meta.Extra) != len(want) {
+		t.Fatalf("expected %d extra fields, got %#v", len(want), meta.Extra)
+	}
+	for i := range want {
+		if meta.Extra[i] != want[i] {
+			t.Fatalf("extra field %d: expected %#v, got %#v", i, want[i], meta.Extra[i])
+		}
+	}
+}
+
+func TestFormatNoteDateFriendly(t *testing.T) {
+	for _, value := range []string{"2025-02-07", "2025-02-07T14:30:00Z", "2025-02-07T23:30:00-05:00", "2025/02/07", "February 7, 2025"} {
+		if got := formatNoteDate(value, ""); got != "Feb 7, 2025" {
+			t.Fatalf("formatNoteDate(%q) = %q, want %q", value, got, "Feb 7, 2025")
+		}
+	}
+	if got := formatNoteDate("2025-02-07", "02.01.2006"); got != "07.02.2025" {
+		t.Fatalf("expected the configured layout, got %q", got)
+	}
+	if got := formatNoteDate("sometime soon", ""); got != "sometime soon" {
+		t.Fatalf("expected unparseable values unchanged, got %q", got)
+	}
+}
+
+func TestNoteMetadataFieldsFormatsDates(t *testing.T) {
+	meta, _ := parseFrontmatterAndBody("---\ndate: 2025-02-07\ncreated: 2025-02-07T14:30:00Z\nstatus: draft\n---\nbody\n")
+	got := map[string]string{}
+	for _, field := range noteMetadataFields(meta, "") {
+		got[field.Key] = field.Value
+	}
+	if got["date"] != "Feb 7, 2025" || got["created"] != "Feb 7, 2025" || got["status"] != "draft" {
+		t.Fatalf("unexpected metadata fields %v", got)
+	}
 }
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStampFrontmatterTimeRoundTrips(t *testing.T) {
+	zone := time.FixedZone("EST", -5*60*60)
+	withFixedNow(t, time.Date(2026, 2, 7, 9, 30, 0, 0, zone))
+
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "no frontmatter",
+			content: "# Body\n",
+			want:    "---\nupdated: 2026-02-07T09:30:00-05:00\n---\n# Body\n",
+		},
+		{
+			name:    "unusual key order is preserved",
+			content: "---\nzeta: 1\ntags:\n  - b\n  - a\ntitle: T\nalpha: 2\n---\nbody\n",
+			want:    "---\nzeta: 1\ntags:\n  - b\n  - a\ntitle: T\nalpha: 2\nupdated: 2026-02-07T09:30:00-05:00\n---\nbody\n",
+		},
+		{
+			name:    "existing key replaced in place",
+			content: "---\nupdated: 2020-01-01T00:00:00Z\ntitle: T\n---\nbody\n",
+			want:    "---\nupdated: 2026-02-07T09:30:00-05:00\ntitle: T\n---\nbody\n",
+		},
+		{
+			name:    "crlf endings",
+			content: "---\r\ntitle: T\r\n---\r\nbody\r\n",
+			want:    "---\r\ntitle: T\r\nupdated: 2026-02-07T09:30:00-05:00\r\n---\r\nbody\r\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := stampFrontmatterTime(tc.content, "updated")
+			if got != tc.want {
+				t.Fatalf("unexpected content.\nwant: %q\ngot:  %q", tc.want, got)
+			}
+			// Stamping again with the same clock is idempotent.
+			if again := stampFrontmatterTime(got, "updated"); again != got {
+				t.Fatalf("expected idempotent stamp, got %q", again)
+			}
+			meta, _ := parseFrontmatterAndBody(got)
+			found := false
+			for _, field := range meta.Extra {
+				if field.Key == "updated" && field.Value == "2026-02-07T09:30:00-05:00" {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("expected parsed updated field, got %#v", meta.Extra)
+			}
+		})
+	}
+}
+
+func TestFrontmatterTimestampsOnCreateAndSave(t *testing.T) {
+	root := t.TempDir()
+	created := time.Date(2026, 2, 7, 9, 0, 0, 0, time.UTC)
+	withFixedNow(t, created)
+
+	m := newTestCRUDModel(root)
+	m.frontmatterTimestamps = true
+	m.newParent = root
+	m.input.SetValue("journal")
+	m.selectedTemplate = &noteTemplate{content: "---\ntitle: Journal\n---\n# Journal\n"}
+	model, _ := settlePendingOp(m.saveNewNote())
+	m = model.(*Model)
+
+	path := filepath.Join(root, "journal.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read created note: %v", err)
+	}
+	if want := "---\ntitle: Journal\ncreated: 2026-02-07T09:00:00Z\n---\n# Journal\n"; string(data) != want {
+		t.Fatalf("unexpected created note.\nwant: %q\ngot:  %q", want, string(data))
+	}
+
+	withFixedNow(t, created.Add(time.Hour))
+	m.currentFile = path
+	m.mode = modeEditNote
+	m.editor.SetValue(string(data) + "more\n")
+	model, _ = m.saveEdit()
+	m = model.(*Model)
+
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read saved note: %v", err)
+	}
+	want := "---\ntitle: Journal\ncreated: 2026-02-07T09:00:00Z\nupdated: 2026-02-07T10:00:00Z\n---\n# Journal\nmore\n"
+	if string(data) != want {
+		t.Fatalf("unexpected saved note.\nwant: %q\ngot:  %q", want, string(data))
+	}
+	if m.currentNoteContent != want {
+		t.Fatalf("expected current content to match disk, got %q", m.currentNoteContent)
+	}
+
+	// A rename must not touch the timestamps.
+	withFixedNow(t, created.Add(2*time.Hour))
+	reselectTreeItem(t, m, path)
+	m.startRenameSelected()
+	m.input.SetValue("journal-renamed.md")
+	model, _ = settlePendingOp(m.saveRenameItem())
+	m = model.(*Model)
+	renamed, err := os.ReadFile(filepath.Join(root, "journal-renamed.md"))
+	if err != nil {
+		t.Fatalf("read renamed note: %v", err)
+	}
+	if string(renamed) != want {
+		t.Fatalf("rename should not touch timestamps, got %q", string(renamed))
+	}
+}
+
+func TestFrontmatterTimestampsDisabledLeavesContentAlone(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.newParent = root
+	m.input.SetValue("plain")
+	model, _ := settlePendingOp(m.saveNewNote())
+	m = model.(*Model)
+
+	data, err := os.ReadFile(filepath.Join(root, "plain.md"))
+	if err != nil {
+		t.Fatalf("read note: %v", err)
+	}
+	if strings.Contains(string(data), "created:") {
+		t.Fatalf("expected no timestamp without config, got %q", string(data))
+	}
+}
+
+func TestFrontmatterOnCreateSeedsNewNotes(t *testing.T) {
+	root := t.TempDir()
+	withFixedNow(t, time.Date(2026, 2, 7, 9, 0, 0, 0, time.UTC))
+
+	m := newTestCRUDModel(root)
+	m.frontmatterOnCreate = true
+	m.newParent = root
+	m.input.SetValue("Plan: Q3")
+	_, _ = settlePendingOp(m.saveNewNote())
+
+	data, err := os.ReadFile(filepath.Join(root, "Plan: Q3.md"))
+	if err != nil {
+		t.Fatalf("read created note: %v", err)
+	}
+	want := "---\ntitle: \"Plan: Q3\"\ncreated: 2026-02-07\ntags: []\n---\n# Plan: Q3\n\nYour note content here...\n"
+	if string(data) != want {
+		t.Fatalf("unexpected created note.\nwant: %q\ngot:  %q", want, string(data))
+	}
+	meta, _ := parseFrontmatterAndBody(string(data))
+	if meta.Title != "Plan: Q3" || len(meta.Tags) != 0 {
+		t.Fatalf("expected the block to parse, got %+v", meta)
+	}
+
+	// A template with its own block is left alone.
+	m = newTestCRUDModel(root)
+	m.frontmatterOnCreate = true
+	m.newParent = root
+	m.input.SetValue("journal")
+	m.selectedTemplate = &noteTemplate{content: "---\ntitle: Journal\n---\n# Journal\n"}
+	_, _ = settlePendingOp(m.saveNewNote())
+	data, err = os.ReadFile(filepath.Join(root, "journal.md"))
+	if err != nil {
+		t.Fatalf("read created note: %v", err)
+	}
+	if want := "---\ntitle: Journal\n---\n# Journal\n"; string(data) != want {
+		t.Fatalf("expected the template frontmatter only.\nwant: %q\ngot:  %q", want, string(data))
+	}
+}
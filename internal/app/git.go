@@ -2,9 +2,11 @@ package app
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -21,16 +23,29 @@ import (
 // the app.
 //
 // If the notes directory is not inside a git repository, isRepo will be false
-// and all other fields are zero-valued.
+// and all other fields are zero-valued, except missing when the reason is
+// that git itself is not installed.
 type gitRepoStatus struct {
 	// isRepo is true when the notes directory is inside a git work tree.
 	// All other fields are only meaningful when this is true.
 	isRepo bool
 
+	// missing is true when the git executable could not be found on PATH,
+	// so whether the notes directory is a repository is unknown.
+	missing bool
+
+	// root is the absolute path of the repository's top-level directory.
+	// Porcelain paths in entries are relative to it.
+	root string
+
 	// branch is the current branch name (e.g. "main", "feature/xyz"), or
 	// "(detached)" when HEAD is not on a named branch.
 	branch string
 
+	// upstream is the remote tracking branch name (e.g. "origin/main"), or
+	// empty when hasUpstream is false.
+	upstream string
+
 	// hasUpstream is true when the current branch has a configured remote
 	// tracking branch. When false, ahead/behind counts are not available.
 	hasUpstream bool
@@ -47,34 +62,63 @@ type gitRepoStatus struct {
 	// modifications, staged changes, or untracked files).
 	dirty bool
 
+	// entries lists the uncommitted changes reported by git status, in the
+	// order git printed them. The git panel renders these rows.
+	entries []gitStatusEntry
+
 	// lastError holds the most recent error message from a git status
 	// command, if any. It is displayed as a "status-error" indicator in
 	// the footer.
 	lastError string
 }
 
+// gitStatusEntry is one changed path from "git status --porcelain=1".
+type gitStatusEntry struct {
+	// code is the two-letter XY status (e.g. " M", "A ", "??", "R ").
+	code string
+
+	// path is the repository-relative path of the changed file. For renames
+	// and copies this is the destination.
+	path string
+
+	// origPath is the source path of a rename or copy, empty otherwise.
+	origPath string
+}
+
 // refreshGitStatus queries the git repository state for the current notes
 // directory and updates m.git with the results.
 //
-// The function performs two git commands:
+// The function performs these git commands:
 //  1. "git rev-parse --is-inside-work-tree" — to determine if the notes dir
 //     is inside a git repo at all. If not, m.git is reset and the function
 //     returns early.
-//  2. "git status --porcelain=1 --branch" — to extract the branch name,
-//     upstream tracking info (ahead/behind counts), and dirty state.
+//  2. "git rev-parse --show-toplevel" and "--abbrev-ref HEAD" — to record
+//     the repository root and current branch.
+//  3. "git status --porcelain=1 --branch" — to extract upstream tracking
+//     info (ahead/behind counts) and the list of changed paths.
 //
 // Any errors from the status command are stored in m.git.lastError rather
 // than surfaced to the user, since git integration is optional and
-// non-critical.
+// non-critical. The exception is a missing git executable: it is recorded in
+// m.git.missing and, unless git_missing_notice is off, reported once per
+// session so git features do not vanish silently inside a real repository.
 func (m *Model) refreshGitStatus() {
 	m.git = gitRepoStatus{}
 
 	out, err := m.runGit("rev-parse", "--is-inside-work-tree")
+	if errors.Is(err, exec.ErrNotFound) {
+		m.git.missing = true
+		m.noticeGitMissing(err)
+		return
+	}
 	if err != nil || strings.TrimSpace(out) != "true" {
 		return
 	}
 
 	m.git.isRepo = true
+	if root, rootErr := m.runGit("rev-parse", "--show-toplevel"); rootErr == nil {
+		m.git.root = filepath.Clean(strings.TrimSpace(root))
+	}
 	branch, branchErr := m.runGit("rev-parse", "--abbrev-ref", "HEAD")
 	if branchErr == nil {
 		m.git.branch = strings.TrimSpace(branch)
@@ -93,14 +137,37 @@ func (m *Model) refreshGitStatus() {
 	lines := strings.Split(strings.TrimSpace(statusOut), "\n")
 	if len(lines) > 0 {
 		m.git.hasUpstream, m.git.ahead, m.git.behind = parseGitPorcelainBranchLine(lines[0])
+		m.git.upstream = parseGitUpstreamName(lines[0])
+	}
+	m.git.entries = parseGitPorcelainEntries(lines)
+	m.git.dirty = len(m.git.entries) > 0
+}
+
+// gitMissingStatus is shown when the git executable cannot be found.
+const gitMissingStatus = "git not found; install git for repo features"
+
+// noticeGitMissing reports a missing git executable in the status bar the
+// first time it is detected, when the notice is enabled. It is always logged.
+func (m *Model) noticeGitMissing(err error) {
+	if m.gitMissingNoticed {
+		return
 	}
-	// If there are any lines beyond the branch header, the working tree has
-	// uncommitted changes (dirty state).
-	if len(lines) > 1 {
-		m.git.dirty = true
+	m.gitMissingNoticed = true
+	appLog.Warn("git executable not found", "error", err)
+	if m.gitMissingNotice {
+		m.status = gitMissingStatus
 	}
 }
 
+// gitUnavailableStatus explains why a git action cannot run: git is not
+// installed, or the notes directory is not in a repository.
+func (m *Model) gitUnavailableStatus() string {
+	if m.git.missing {
+		return gitMissingStatus
+	}
+	return "Git is unavailable for this notes directory"
+}
+
 // parseGitPorcelainBranchLine extracts upstream tracking information from
 // the first line of "git status --porcelain=1 --branch" output.
 //
@@ -124,6 +191,80 @@ func parseGitPorcelainBranchLine(line string) (bool, int, int) {
 	return hasUpstream, ahead, behind
 }
 
+// parseGitUpstreamName returns the tracking branch named in a porcelain
+// branch line ("origin/main" for "## main...origin/main [ahead 2]"), or ""
+// when the branch has no upstream.
+func parseGitUpstreamName(line string) string {
+	_, upstream, ok := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "##")), "...")
+	if !ok {
+		return ""
+	}
+	upstream, _, _ = strings.Cut(upstream, " ")
+	return upstream
+}
+
+// parseGitPorcelainEntries converts "git status --porcelain=1" lines into
+// structured entries. The "##" branch header and blank lines are skipped.
+//
+// Each entry line has the form "XY path". Renames and copies (R or C in
+// either status column) carry two paths, "XY orig -> path". Paths containing
+// spaces or unusual characters are C-quoted by git and are unquoted here.
+func parseGitPorcelainEntries(lines []string) []gitStatusEntry {
+	var entries []gitStatusEntry
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "##") || len(line) < 4 {
+			continue
+		}
+		entry := gitStatusEntry{code: line[:2]}
+		rest := line[3:]
+		if strings.ContainsAny(entry.code, "RC") {
+			entry.origPath, entry.path = splitGitPorcelainPaths(rest)
+		} else {
+			entry.path = unquoteGitPath(rest)
+		}
+		if entry.path == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// splitGitPorcelainPaths splits the "orig -> path" field of a rename or copy
+// entry. A quoted source path is scanned to its closing quote so an arrow
+// inside the file name is not mistaken for the separator.
+func splitGitPorcelainPaths(field string) (string, string) {
+	if strings.HasPrefix(field, `"`) {
+		for i := 1; i < len(field); i++ {
+			switch field[i] {
+			case '\\':
+				i++
+			case '"':
+				if to, ok := strings.CutPrefix(field[i+1:], " -> "); ok {
+					return unquoteGitPath(field[:i+1]), unquoteGitPath(to)
+				}
+				return "", unquoteGitPath(field)
+			}
+		}
+	}
+	if from, to, ok := strings.Cut(field, " -> "); ok {
+		return unquoteGitPath(from), unquoteGitPath(to)
+	}
+	return "", unquoteGitPath(field)
+}
+
+// unquoteGitPath decodes a C-quoted porcelain path ("\"a b.md\"") and returns
+// unquoted paths unchanged.
+func unquoteGitPath(path string) string {
+	if len(path) >= 2 && strings.HasPrefix(path, `"`) && strings.HasSuffix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			return unquoted
+		}
+	}
+	return path
+}
+
 // parseGitCount extracts a numeric count that follows the given token string
 // within a line. For example, parseGitCount("ahead 3, behind 1", "ahead ")
 // returns 3.
@@ -201,12 +342,23 @@ func (m *Model) gitFooterSummary() string {
 // can accept the default by pressing Enter/Ctrl+S or type a custom message.
 //
 // If the notes directory is not a git repository, a status message is shown
-// and no mode change occurs.
+// and no mode change occurs. When no notes were changed in the app but the
+// working tree is dirty, the user is asked before the commit stages
+// everything (see git_stage.go).
 func (m *Model) handleGitCommitStart() (tea.Model, tea.Cmd) {
 	if !m.git.isRepo {
-		m.status = "Git is unavailable for this notes directory"
+		m.status = m.gitUnavailableStatus()
+		return m, nil
+	}
+	if !m.gitStageAll && len(m.gitTouched) == 0 && m.git.dirty {
+		m.confirmBroadGitCommit()
 		return m, nil
 	}
+	return m.startGitCommitMessage()
+}
+
+// startGitCommitMessage opens the commit message input.
+func (m *Model) startGitCommitMessage() (tea.Model, tea.Cmd) {
 	m.mode = modeGitCommit
 	m.showHelp = false
 	m.input.Reset()
@@ -218,34 +370,98 @@ func (m *Model) handleGitCommitStart() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// handleGitPull runs "git pull --ff-only" in the notes directory.
+// gitOpResultMsg carries the outcome of a git pull, push, or commit that ran
+// off the UI goroutine under the operation interlock (see op_lock.go).
+type gitOpResultMsg struct {
+	// token is the interlock holder token of the operation.
+	token int
+	// op is "pull", "push", or "commit".
+	op string
+	// message is the commit message (commit only).
+	message string
+	// step is the git subcommand that produced out/err: "add" or "commit"
+	// for a commit, op otherwise.
+	step string
+	out  string
+	err  error
+	// paths are the paths a commit staged, and stagedAll is set when it
+	// staged the whole repository (see git_stage.go).
+	paths     []string
+	stagedAll bool
+}
+
+// startGitOp takes the interlock for "git <op>" and runs run in the
+// background. Its result is applied by handleGitOpResult.
+func (m *Model) startGitOp(op string, run func(dir string) gitOpResultMsg) (tea.Model, tea.Cmd) {
+	token, timeout := m.acquireOpLock("git " + op)
+	m.status = "Git " + op + " running..."
+	dir := m.notesDir
+	return m, tea.Batch(timeout, func() tea.Msg {
+		res := run(dir)
+		res.token = token
+		return res
+	})
+}
+
+// handleGitOpResult releases the interlock and applies a finished git
+// operation.
+func (m *Model) handleGitOpResult(msg gitOpResultMsg) (tea.Model, tea.Cmd) {
+	m.releaseOpLock(msg.token)
+	defer m.runPendingAutoCommit()
+	var cmd tea.Cmd
+	switch msg.op {
+	case "pull":
+		cmd = m.finishGitPull(msg)
+	case "push":
+		m.finishGitPush(msg)
+	case "commit":
+		m.finishGitCommit(msg)
+	}
+	if m.overlay == overlayGitPanel {
+		m.clampGitPanelCursor()
+	}
+	return m, cmd
+}
+
+// handleGitPull runs "git pull --ff-only" in the notes directory in the
+// background.
 //
 // The --ff-only flag ensures that only fast-forward merges are performed,
 // which avoids creating merge commits or triggering conflict resolution.
-// If the pull introduces changes, the tree view, search index, render cache,
-// and git status are all refreshed to reflect the new state.
-//
-// If the pull fails (e.g. due to divergent histories, network errors, or
-// authentication failures), the error is shown in the status bar and logged.
+// Changes to notes are rejected until the pull finishes (see op_lock.go).
 func (m *Model) handleGitPull() (tea.Model, tea.Cmd) {
 	if !m.git.isRepo {
-		m.status = "Git is unavailable for this notes directory"
+		m.status = m.gitUnavailableStatus()
 		return m, nil
 	}
+	if m.rejectWhileLocked("git pull") {
+		return m, nil
+	}
+	return m.startGitOp("pull", func(dir string) gitOpResultMsg {
+		out, err := gitRun(dir, "pull", "--ff-only")
+		return gitOpResultMsg{op: "pull", step: "pull", out: out, err: err}
+	})
+}
 
-	out, err := m.runGit("pull", "--ff-only")
-	if err != nil {
-		m.status = "Git pull failed: " + firstLine(out)
-		if strings.TrimSpace(firstLine(out)) == "" {
-			m.status = "Git pull failed: " + err.Error()
+// finishGitPull reports a pull result. If the pull introduced changes, the
+// tree view, search index, render cache, and git status are all refreshed
+// to reflect the new state.
+//
+// If the pull failed (e.g. due to divergent histories, network errors, or
+// authentication failures), the error is shown in the status bar and logged.
+func (m *Model) finishGitPull(res gitOpResultMsg) tea.Cmd {
+	if res.err != nil {
+		m.status = "Git pull failed: " + firstLine(res.out)
+		if strings.TrimSpace(firstLine(res.out)) == "" {
+			m.status = "Git pull failed: " + res.err.Error()
 		}
-		appLog.Warn("git pull failed", "error", err, "output", out)
+		appLog.Warn("git pull failed", "error", res.err, "output", res.out)
 		m.refreshGitStatus()
-		return m, nil
+		return nil
 	}
 
 	m.status = "Git pull complete"
-	if line := firstLine(out); line != "" {
+	if line := firstLine(res.out); line != "" {
 		m.status = "Git pull: " + line
 	}
 
@@ -257,62 +473,62 @@ func (m *Model) handleGitPull() (tea.Model, tea.Cmd) {
 	m.reconcileCurrentFileAfterFilesystemChange()
 	m.refreshGitStatus()
 	if m.currentFile != "" {
-		return m, m.setCurrentFile(m.currentFile)
+		return m.setCurrentFile(m.currentFile)
 	}
-	return m, nil
+	return nil
 }
 
-// handleGitPush runs "git push" in the notes directory to push local commits
-// to the configured remote.
-//
-// This is a straightforward push with no flags. If the push fails (e.g. due
-// to rejected updates, network errors, or authentication issues), the error
-// is shown in the status bar. On success, the git status is refreshed to
-// update the ahead/behind counts.
+// handleGitPush runs "git push" in the notes directory in the background to
+// push local commits to the configured remote.
 func (m *Model) handleGitPush() (tea.Model, tea.Cmd) {
 	if !m.git.isRepo {
-		m.status = "Git is unavailable for this notes directory"
+		m.status = m.gitUnavailableStatus()
+		return m, nil
+	}
+	if m.rejectWhileLocked("git push") {
 		return m, nil
 	}
+	return m.startGitOp("push", func(dir string) gitOpResultMsg {
+		out, err := gitRun(dir, "push")
+		return gitOpResultMsg{op: "push", step: "push", out: out, err: err}
+	})
+}
 
-	out, err := m.runGit("push")
-	if err != nil {
-		m.status = "Git push failed: " + firstLine(out)
-		if strings.TrimSpace(firstLine(out)) == "" {
-			m.status = "Git push failed: " + err.Error()
+// finishGitPush reports a push result. If the push failed (e.g. due to
+// rejected updates, network errors, or authentication issues), the error is
+// shown in the status bar. On success, the git status is refreshed to update
+// the ahead/behind counts.
+func (m *Model) finishGitPush(res gitOpResultMsg) {
+	if res.err != nil {
+		m.status = "Git push failed: " + firstLine(res.out)
+		if strings.TrimSpace(firstLine(res.out)) == "" {
+			m.status = "Git push failed: " + res.err.Error()
 		}
-		appLog.Warn("git push failed", "error", err, "output", out)
+		appLog.Warn("git push failed", "error", res.err, "output", res.out)
 		m.refreshGitStatus()
-		return m, nil
+		return
 	}
 
 	m.status = "Git push complete"
-	if line := firstLine(out); line != "" {
+	if line := firstLine(res.out); line != "" {
 		m.status = "Git push: " + line
 	}
 	m.refreshGitStatus()
-	return m, nil
 }
 
-// runGitCommit executes a two-step commit: "git add -A" (stage everything)
-// followed by "git commit -m <message>".
+// runGitCommit returns to browse mode and commits in the background: the
+// notes changed in the app (gitCommitPaths), or everything (gitCommitAll)
+// with git_stage_all or after the user confirmed a broad commit.
 //
 // If the provided message is empty or whitespace-only, a default commit
 // message with the current timestamp is used.
-//
-// The function handles common outcomes:
-//   - Successful commit: status bar shows the commit message.
-//   - "nothing to commit": recognized as a non-error condition and reported
-//     calmly in the status bar.
-//   - Add or commit failure: error details are shown in the status bar and
-//     logged.
-//
-// After the commit (whether successful or not), the app returns to browse
-// mode and the git status is refreshed.
 func (m *Model) runGitCommit(message string) (tea.Model, tea.Cmd) {
+	m.mode = modeBrowse
 	if !m.git.isRepo {
-		m.mode = modeBrowse
-		m.status = "Git is unavailable for this notes directory"
+		m.status = m.gitUnavailableStatus()
+		return m, nil
+	}
+	if m.rejectWhileLocked("git commit") || m.rejectWhileAnyPending("git commit") {
 		return m, nil
 	}
 
@@ -320,41 +536,63 @@ func (m *Model) runGitCommit(message string) (tea.Model, tea.Cmd) {
 	if msg == "" {
 		msg = m.defaultCommitMessage()
 	}
-
-	// Stage all changes (new files, modifications, deletions).
-	if out, err := m.runGit("add", "-A"); err != nil {
-		m.status = "Git add failed: " + firstLine(out)
-		if strings.TrimSpace(firstLine(out)) == "" {
-			m.status = "Git add failed: " + err.Error()
-		}
-		appLog.Warn("git add failed", "error", err, "output", out)
-		m.refreshGitStatus()
+	stageAll := m.gitStageAllNext
+	m.gitStageAllNext = false
+	run, ok := m.gitCommitFunc(msg, stageAll)
+	if !ok {
+		m.status = "Nothing to commit: no notes changed in the app"
 		return m, nil
 	}
+	return m.startGitOp("commit", run)
+}
+
+// gitCommitAll executes a two-step commit in dir: "git add -A" (stage
+// everything) followed by "git commit -m <message>".
+func gitCommitAll(dir, message string) gitOpResultMsg {
+	res := gitOpResultMsg{op: "commit", message: message, step: "add", stagedAll: true}
+	if res.out, res.err = gitRun(dir, "add", "-A"); res.err != nil {
+		return res
+	}
+	res.step = "commit"
+	res.out, res.err = gitRun(dir, "commit", "-m", message)
+	return res
+}
 
-	// Create the commit with the user's (or default) message.
-	out, err := m.runGit("commit", "-m", msg)
-	m.mode = modeBrowse
-	if err != nil {
-		line := strings.ToLower(firstLine(out))
-		switch {
-		case strings.Contains(line, "nothing to commit"):
-			// Not a real error — just nothing staged to commit.
-			m.status = "Nothing to commit"
-		default:
-			m.status = "Git commit failed: " + firstLine(out)
-			if strings.TrimSpace(firstLine(out)) == "" {
-				m.status = "Git commit failed: " + err.Error()
-			}
-			appLog.Warn("git commit failed", "error", err, "output", out)
+// finishGitCommit reports a commit result. It handles the common outcomes:
+//   - Successful commit: status bar shows the commit message and the
+//     committed paths are no longer tracked for the next commit.
+//   - "nothing to commit": recognized as a non-error condition and reported
+//     calmly in the status bar; the staged paths are dropped as well.
+//   - Add or commit failure: error details are shown in the status bar and
+//     logged.
+//
+// The git status is refreshed in every case.
+func (m *Model) finishGitCommit(res gitOpResultMsg) {
+	defer m.refreshGitStatus()
+	if res.err == nil {
+		m.status = "Committed: " + res.message
+		m.clearGitTouched(res)
+		return
+	}
+	if res.step == "add" {
+		m.status = "Git add failed: " + firstLine(res.out)
+		if strings.TrimSpace(firstLine(res.out)) == "" {
+			m.status = "Git add failed: " + res.err.Error()
 		}
-		m.refreshGitStatus()
-		return m, nil
+		appLog.Warn("git add failed", "error", res.err, "output", res.out)
+		return
 	}
-
-	m.status = "Committed: " + msg
-	m.refreshGitStatus()
-	return m, nil
+	if strings.Contains(strings.ToLower(firstLine(res.out)), "nothing to commit") {
+		// Not a real error — just nothing staged to commit.
+		m.status = "Nothing to commit"
+		m.clearGitTouched(res)
+		return
+	}
+	m.status = "Git commit failed: " + firstLine(res.out)
+	if strings.TrimSpace(firstLine(res.out)) == "" {
+		m.status = "Git commit failed: " + res.err.Error()
+	}
+	appLog.Warn("git commit failed", "error", res.err, "output", res.out)
 }
 
 // defaultCommitMessage generates a timestamped commit message used as the
@@ -382,7 +620,17 @@ func (m *Model) defaultCommitMessage() string {
 // inspect both — a non-nil error with informative output is common for git
 // commands that fail with explanatory messages.
 func (m *Model) runGit(args ...string) (string, error) {
-	cmd := exec.Command("git", append([]string{"-C", m.notesDir}, args...)...)
+	return gitRun(m.notesDir, args...)
+}
+
+// gitRun runs git in a directory; tests replace it to script slow or failing
+// git operations.
+var gitRun = runGitIn
+
+// runGitIn executes "git -C <dir> <args...>" and merges its output like
+// runGit.
+func runGitIn(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -407,24 +655,33 @@ func (m *Model) runGit(args ...string) (string, error) {
 
 // reconcileCurrentFileAfterFilesystemChange checks whether the currently
 // displayed note file still exists on disk after an external change (e.g.
-// git pull, manual file deletion).
+// git pull, manual file deletion), and reports whether it does.
 //
 // If the file has been deleted or is no longer a regular file, the viewport
 // is reset to the "Select a note to view" placeholder and the current file
-// reference is cleared. If the file still exists, no action is taken.
+// reference is cleared. In edit mode the path and editor buffer are kept
+// instead and a warning is shown, so saving recreates the note rather than
+// losing the unsaved edits. If the file still exists, no action is taken.
 //
 // This prevents the app from showing stale content or crashing when the
 // underlying file disappears.
-func (m *Model) reconcileCurrentFileAfterFilesystemChange() {
+func (m *Model) reconcileCurrentFileAfterFilesystemChange() bool {
 	if m.currentFile == "" {
-		return
+		return false
 	}
 	info, err := os.Stat(m.currentFile)
 	if err == nil && !info.IsDir() {
-		return
+		return true
+	}
+	if m.mode == modeEditNote {
+		m.status = "Warning: " + filepath.Base(m.currentFile) + " was removed on disk; saving will recreate it"
+		return false
 	}
 	m.currentFile = ""
+	m.currentNoteContent = ""
+	m.metadataStripSegments = nil
 	m.viewport.SetContent("Select a note to view")
+	return false
 }
 
 // firstLine extracts the first non-empty line from a (possibly multi-line)
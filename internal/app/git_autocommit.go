@@ -0,0 +1,83 @@
+// git_autocommit.go implements optional interval auto-commit
+// (git_autocommit_minutes).
+//
+// When the interval is positive, an autoCommitTickMsg fires every interval
+// for the life of the app. If the notes directory is a git repository with a
+// dirty working tree, the tick commits the notes changed in the app (or
+// everything with git_stage_all, see git_stage.go) with an "Auto-commit
+// notes (<time>)" message. A clean tree, no changed notes, or "nothing to
+// commit" leaves the status bar untouched; failures are reported there.
+//
+// The commit runs synchronously, so it never overlaps another git operation;
+// a tick that arrives while editing, in an input mode, with a popup open, or
+// while a background git operation holds the interlock or a create or rename
+// is still writing only marks the commit as pending; it runs on the first
+// key press that leaves the app in plain browse mode, or when the background
+// operation or last pending write finishes.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// autoCommitTickMsg is emitted every autoCommitInterval.
+type autoCommitTickMsg struct{}
+
+// scheduleAutoCommit returns the next auto-commit tick, or nil when
+// auto-commit is disabled.
+func (m *Model) scheduleAutoCommit() tea.Cmd {
+	if m.autoCommitInterval <= 0 {
+		return nil
+	}
+	return tea.Tick(m.autoCommitInterval, func(time.Time) tea.Msg {
+		return autoCommitTickMsg{}
+	})
+}
+
+// handleAutoCommitTick commits now or defers until browse mode, then
+// schedules the next tick.
+func (m *Model) handleAutoCommitTick(_ autoCommitTickMsg) (tea.Model, tea.Cmd) {
+	if m.git.isRepo {
+		m.autoCommitPending = true
+		m.runPendingAutoCommit()
+	}
+	return m, m.scheduleAutoCommit()
+}
+
+// autoCommitBlocked reports whether committing now would interrupt the user.
+func (m *Model) autoCommitBlocked() bool {
+	return m.mode != modeBrowse || m.overlay != overlayNone || m.opLocked() || len(m.pendingOps) > 0
+}
+
+// runPendingAutoCommit performs a pending auto-commit unless the user is
+// busy. It is called on each tick and after each key press.
+func (m *Model) runPendingAutoCommit() {
+	if !m.autoCommitPending || m.autoCommitBlocked() {
+		return
+	}
+	m.autoCommitPending = false
+	if !m.git.isRepo {
+		return
+	}
+	m.refreshGitStatus()
+	if !m.git.dirty {
+		return
+	}
+
+	msg := fmt.Sprintf("Auto-commit notes (%s)", time.Now().Format("2006-01-02 15:04"))
+	run, ok := m.gitCommitFunc(msg, false)
+	if !ok {
+		return
+	}
+	previous := m.status
+	m.finishGitCommit(run(m.notesDir))
+	switch m.status {
+	case "Nothing to commit":
+		m.status = previous
+	case "Committed: " + msg:
+		m.status = "Auto-committed: " + msg
+	}
+}
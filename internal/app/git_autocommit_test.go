@@ -0,0 +1,43 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleAutoCommitDisabledAtZero(t *testing.T) {
+	m := &Model{}
+	if cmd := m.scheduleAutoCommit(); cmd != nil {
+		t.Fatal("expected no auto-commit tick when the interval is 0")
+	}
+	m.autoCommitInterval = time.Minute
+	if cmd := m.scheduleAutoCommit(); cmd == nil {
+		t.Fatal("expected an auto-commit tick for a positive interval")
+	}
+}
+
+func TestAutoCommitTickOutsideRepoDoesNothing(t *testing.T) {
+	m := &Model{autoCommitInterval: time.Minute, status: "Ready"}
+	if _, cmd := m.handleAutoCommitTick(autoCommitTickMsg{}); cmd == nil {
+		t.Fatal("expected the next tick to be scheduled")
+	}
+	if m.autoCommitPending || m.status != "Ready" {
+		t.Fatalf("expected no pending commit or status change, got pending=%v status %q", m.autoCommitPending, m.status)
+	}
+}
+
+func TestAutoCommitDefersWhileEditing(t *testing.T) {
+	m := &Model{autoCommitInterval: time.Minute, mode: modeEditNote, status: "Editing"}
+	m.git.isRepo = true
+	_, _ = m.handleAutoCommitTick(autoCommitTickMsg{})
+	if !m.autoCommitPending || m.status != "Editing" {
+		t.Fatalf("expected commit deferred while editing, got pending=%v status %q", m.autoCommitPending, m.status)
+	}
+
+	m.mode = modeBrowse
+	m.overlay = overlayGitLog
+	m.runPendingAutoCommit()
+	if !m.autoCommitPending {
+		t.Fatal("expected commit still deferred while a popup is open")
+	}
+}
@@ -0,0 +1,157 @@
+// git_diff.go implements the git diff popup (`v`) for the current note.
+//
+// The popup shows the unified diff of the current note against the index
+// ("git diff") or, after pressing Tab, the staged changes against HEAD
+// ("git diff --cached"), so edits can be reviewed before committing. Added,
+// removed, and hunk-header lines are colored; the diff scrolls in its own
+// viewport. A clean note shows "(no changes)".
+package app
+
+import (
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// gitDiffArgs returns the git arguments that diff one notes-relative path,
+// either the working tree against the index or the index against HEAD.
+func gitDiffArgs(relPath string, staged bool) []string {
+	args := []string{"diff", "--no-color", "--no-ext-diff"}
+	if staged {
+		args = append(args, "--cached")
+	}
+	return append(args, "--", filepath.ToSlash(relPath))
+}
+
+// gitDiff returns the unified diff for path, honoring the popup's staged
+// toggle. An empty result means the note has no changes of that kind.
+func (m *Model) gitDiff(path string) (string, error) {
+	rel, err := filepath.Rel(m.notesDir, path)
+	if err != nil {
+		return "", err
+	}
+	return m.runGit(gitDiffArgs(rel, m.gitDiffStaged)...)
+}
+
+// gitDiffLineStyle colors one line of unified diff output.
+func gitDiffLineStyle(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return titleStyle.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return diffHunkLine.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return diffAddedLine.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return diffRemovedLine.Render(line)
+	case strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "):
+		return mutedStyle.Render(line)
+	default:
+		return line
+	}
+}
+
+// openGitDiffPopup shows the unstaged diff of the current note.
+func (m *Model) openGitDiffPopup() {
+	if m.mode != modeBrowse || m.currentFile == "" {
+		m.status = "Select a note first"
+		return
+	}
+	if !m.git.isRepo {
+		m.status = m.gitUnavailableStatus()
+		return
+	}
+	m.gitDiffStaged = false
+	if !m.loadGitDiff() {
+		return
+	}
+	m.openOverlay(overlayGitDiff)
+	m.showHelp = false
+}
+
+// loadGitDiff runs git diff for the current note and resets the popup
+// scroll position. It reports false (with the error in the status bar) when
+// git fails.
+func (m *Model) loadGitDiff() bool {
+	out, err := m.gitDiff(m.currentFile)
+	if err != nil {
+		m.setStatusError("Git diff failed: "+firstLine(out), err, "path", m.currentFile)
+		return false
+	}
+	m.gitDiffText = out
+	m.gitDiffViewport.YOffset = 0
+	m.status = "Git diff (" + m.gitDiffModeLabel() + "): Tab toggles staged, Esc to close"
+	return true
+}
+
+// gitDiffModeLabel names the diff currently shown.
+func (m *Model) gitDiffModeLabel() string {
+	if m.gitDiffStaged {
+		return "staged"
+	}
+	return "unstaged"
+}
+
+// handleGitDiffPopupKey scrolls the diff, toggles staged/unstaged with Tab
+// or `s`, and closes on Esc or `q`.
+func (m *Model) handleGitDiffPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	switch m.popupAction(msg) {
+	case actionPopupClose:
+		m.closeOverlay()
+		m.status = "Git diff closed"
+	case popupActionDiffStaged:
+		m.gitDiffStaged = !m.gitDiffStaged
+		if !m.loadGitDiff() {
+			m.closeOverlay()
+		}
+	case actionPopupUp:
+		m.scrollGitDiffBy(-1)
+	case actionPopupDown:
+		m.scrollGitDiffBy(1)
+	case actionPopupPageUp:
+		m.scrollGitDiffBy(-max(1, m.gitDiffViewport.Height))
+	case actionPopupPageDown:
+		m.scrollGitDiffBy(max(1, m.gitDiffViewport.Height))
+	case actionPopupTop:
+		m.gitDiffViewport.YOffset = 0
+	case actionPopupBottom:
+		m.scrollGitDiffBy(m.gitDiffViewport.TotalLineCount())
+	}
+	return m, nil
+}
+
+func (m *Model) scrollGitDiffBy(delta int) {
+	maxOffset := max(0, m.gitDiffViewport.TotalLineCount()-m.gitDiffViewport.Height)
+	m.gitDiffViewport.YOffset = clamp(m.gitDiffViewport.YOffset+delta, 0, maxOffset)
+}
+
+// renderGitDiffPopup draws the colored diff in a scrollable viewport under a
+// title line naming the note and the diff mode.
+func (m *Model) renderGitDiffPopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	header := []string{
+		titleStyle.Render("Git Diff (" + m.gitDiffModeLabel() + ")"),
+		mutedStyle.Render(truncate(m.displayRelative(m.currentFile), innerWidth)),
+		"",
+	}
+	footer := mutedStyle.Render("↑/↓ scroll  Tab: staged/unstaged  Esc: close")
+
+	body := []string{mutedStyle.Render("(no changes)")}
+	if strings.TrimSpace(m.gitDiffText) != "" {
+		body = body[:0]
+		for _, line := range strings.Split(m.gitDiffText, "\n") {
+			body = append(body, gitDiffLineStyle(truncate(line, innerWidth)))
+		}
+	}
+	m.gitDiffViewport.Width = innerWidth
+	m.gitDiffViewport.Height = max(1, innerHeight-len(header)-2)
+	m.gitDiffViewport.SetContent(strings.Join(body, "\n"))
+
+	content := strings.Join(header, "\n") + "\n" + m.gitDiffViewport.View() + "\n\n" + footer
+	return popupStyle.Width(width).Height(height).Render(padBlock(content, innerWidth, innerHeight))
+}
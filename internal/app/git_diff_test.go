@@ -0,0 +1,52 @@
+package app
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGitDiffArgs(t *testing.T) {
+	rel := filepath.Join("work", "a.md")
+	if got, want := gitDiffArgs(rel, false), []string{"diff", "--no-color", "--no-ext-diff", "--", "work/a.md"}; !slices.Equal(got, want) {
+		t.Fatalf("unstaged args = %v, want %v", got, want)
+	}
+	if got := gitDiffArgs(rel, true); !slices.Contains(got, "--cached") {
+		t.Fatalf("expected --cached for staged diff, got %v", got)
+	}
+}
+
+func TestGitDiffLineStyleKeepsText(t *testing.T) {
+	for _, line := range []string{"+++ b/a.md", "@@ -1 +1 @@", "+added", "-removed", " context"} {
+		if got := gitDiffLineStyle(line); !strings.Contains(got, line) {
+			t.Fatalf("styled line %q lost its text: %q", line, got)
+		}
+	}
+}
+
+func TestGitDiffPopupShowsNoChanges(t *testing.T) {
+	m := &Model{currentFile: "/notes/a.md", notesDir: "/notes"}
+	m.openOverlay(overlayGitDiff)
+	if got := m.renderGitDiffPopup(60, 14); !strings.Contains(got, "(no changes)") {
+		t.Fatalf("expected clean diff placeholder, got:\n%s", got)
+	}
+}
+
+func TestGitDiffPopupScrollsAndCloses(t *testing.T) {
+	m := &Model{currentFile: "/notes/a.md", notesDir: "/notes"}
+	m.gitDiffText = strings.Repeat("+line\n", 40)
+	m.openOverlay(overlayGitDiff)
+	_ = m.renderGitDiffPopup(60, 14)
+
+	_, _ = m.handleGitDiffPopupKey(tea.KeyMsg{Type: tea.KeyDown})
+	if m.gitDiffViewport.YOffset != 1 {
+		t.Fatalf("expected scroll offset 1, got %d", m.gitDiffViewport.YOffset)
+	}
+	_, _ = m.handleGitDiffPopupKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.overlay != overlayNone {
+		t.Fatalf("expected popup closed, got %v", m.overlay)
+	}
+}
@@ -0,0 +1,233 @@
+// git_log.go implements the git history popup (`V`) for the current note.
+//
+// The popup lists the commits that touched the note, following renames, with
+// their short hash, date, and subject. Enter shows the note as it was at the
+// selected commit, rendered as markdown in a read-only viewport; Esc returns
+// to the list and closes the popup from there.
+package app
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// gitCommit is one row of a note's history. relPath is the note's
+// notes-relative path at that commit, which differs from the current path
+// for commits before a rename.
+type gitCommit struct {
+	hash    string
+	date    string
+	subject string
+	relPath string
+}
+
+// gitLogRecordSep starts each commit record in gitFileLog output, so that
+// subjects and the --name-only path lines can be split unambiguously.
+const gitLogRecordSep = "\x1e"
+
+// gitFileLog returns the commits that touched path, newest first.
+func (m *Model) gitFileLog(path string) ([]gitCommit, error) {
+	rel, err := filepath.Rel(m.notesDir, path)
+	if err != nil {
+		return nil, err
+	}
+	out, err := m.runGit(
+		"log", "--follow", "--date=short", "--name-only",
+		"--format="+gitLogRecordSep+"%h%x09%ad%x09%s",
+		"--", filepath.ToSlash(rel),
+	)
+	if err != nil {
+		return nil, errors.New(firstLine(out))
+	}
+	return parseGitFileLog(out, filepath.ToSlash(rel)), nil
+}
+
+// parseGitFileLog parses gitFileLog output. Records without a path line fall
+// back to fallbackRel.
+func parseGitFileLog(out, fallbackRel string) []gitCommit {
+	var commits []gitCommit
+	for _, record := range strings.Split(out, gitLogRecordSep) {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.SplitN(lines[0], "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		commit := gitCommit{hash: fields[0], date: fields[1], subject: fields[2], relPath: fallbackRel}
+		for _, line := range lines[1:] {
+			if line = strings.TrimSpace(line); line != "" {
+				commit.relPath = line
+				break
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// gitFileAtRevision returns the content of path at commit hash. path is
+// resolved relative to the notes directory.
+func (m *Model) gitFileAtRevision(path, hash string) (string, error) {
+	rel, err := filepath.Rel(m.notesDir, path)
+	if err != nil {
+		return "", err
+	}
+	out, err := m.runGit("show", hash+":"+filepath.ToSlash(rel))
+	if err != nil {
+		return "", errors.New(firstLine(out))
+	}
+	return out, nil
+}
+
+// openGitLogPopup lists the history of the current note.
+func (m *Model) openGitLogPopup() {
+	if m.mode != modeBrowse || m.currentFile == "" {
+		m.status = "Select a note first"
+		return
+	}
+	if !m.git.isRepo {
+		m.status = m.gitUnavailableStatus()
+		return
+	}
+	commits, err := m.gitFileLog(m.currentFile)
+	if err != nil {
+		m.setStatusError("Git log failed: "+err.Error(), err, "path", m.currentFile)
+		return
+	}
+	if len(commits) == 0 {
+		m.status = "No commits for this note yet"
+		return
+	}
+	m.gitLogEntries = commits
+	m.gitLogCursor = 0
+	m.gitLogRevision = ""
+	m.gitLogRendered = ""
+	m.openOverlay(overlayGitLog)
+	m.showHelp = false
+	m.status = "Git history: Enter to view revision, Esc to close"
+}
+
+// handleGitLogPopupKey moves through the commit list and opens a revision
+// on Enter. While a revision is shown, keys scroll it and Esc/q returns to
+// the list.
+func (m *Model) handleGitLogPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	if m.gitLogRevision != "" {
+		m.handleGitLogRevisionKey(msg)
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.gitLogCursor, len(m.gitLogEntries))
+	if !handled {
+		return m, nil
+	}
+	if closePressed {
+		m.closeOverlay()
+		m.status = "Git history closed"
+		return m, nil
+	}
+	m.gitLogCursor = next
+	if selectPressed {
+		m.showGitLogRevision(m.gitLogEntries[m.gitLogCursor])
+	}
+	return m, nil
+}
+
+// showGitLogRevision loads the note's content at commit into the viewport.
+func (m *Model) showGitLogRevision(commit gitCommit) {
+	content, err := m.gitFileAtRevision(filepath.Join(m.notesDir, filepath.FromSlash(commit.relPath)), commit.hash)
+	if err != nil {
+		m.setStatusError("Git show failed: "+err.Error(), err, "commit", commit.hash, "path", commit.relPath)
+		return
+	}
+	if strings.TrimSpace(content) == "" {
+		content = "_(empty note)_"
+	}
+	m.gitLogRevision = content
+	m.gitLogRendered = ""
+	m.gitLogViewport.YOffset = 0
+	m.status = "Revision " + commit.hash + " (read-only): Esc to go back"
+}
+
+func (m *Model) handleGitLogRevisionKey(msg tea.KeyMsg) {
+	switch m.popupAction(msg) {
+	case actionPopupClose:
+		m.gitLogRevision = ""
+		m.gitLogRendered = ""
+		m.status = "Git history: Enter to view revision, Esc to close"
+	case actionPopupUp:
+		m.scrollGitLogBy(-1)
+	case actionPopupDown:
+		m.scrollGitLogBy(1)
+	case actionPopupPageUp:
+		m.scrollGitLogBy(-max(1, m.gitLogViewport.Height))
+	case actionPopupPageDown:
+		m.scrollGitLogBy(max(1, m.gitLogViewport.Height))
+	case actionPopupTop:
+		m.gitLogViewport.YOffset = 0
+	case actionPopupBottom:
+		m.scrollGitLogBy(m.gitLogViewport.TotalLineCount())
+	}
+}
+
+func (m *Model) scrollGitLogBy(delta int) {
+	maxOffset := max(0, m.gitLogViewport.TotalLineCount()-m.gitLogViewport.Height)
+	m.gitLogViewport.YOffset = clamp(m.gitLogViewport.YOffset+delta, 0, maxOffset)
+}
+
+// renderGitLogPopup draws either the commit list or the selected revision.
+func (m *Model) renderGitLogPopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	if m.gitLogRevision != "" {
+		return m.renderGitLogRevision(width, height, innerWidth, innerHeight)
+	}
+
+	lines := []string{
+		titleStyle.Render("Git History"),
+		mutedStyle.Render(truncate(m.displayRelative(m.currentFile), innerWidth)),
+		"",
+	}
+	limit := max(0, innerHeight-len(lines)-1)
+	start := 0
+	if limit > 0 {
+		start = max(0, m.gitLogCursor-limit+1)
+	}
+	for i := start; i < min(start+limit, len(m.gitLogEntries)); i++ {
+		commit := m.gitLogEntries[i]
+		line := truncate(commit.hash+"  "+commit.date+"  "+commit.subject, innerWidth)
+		if i == m.gitLogCursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, mutedStyle.Render("Enter: view revision  Esc: close"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
+
+// renderGitLogRevision draws the selected revision as rendered markdown. The
+// rendering is cached until the popup width changes.
+func (m *Model) renderGitLogRevision(width, height, innerWidth, innerHeight int) string {
+	commit := m.gitLogEntries[m.gitLogCursor]
+	header := []string{
+		titleStyle.Render(truncate("Revision "+commit.hash+"  "+commit.date, innerWidth)),
+		mutedStyle.Render(truncate(commit.subject, innerWidth)),
+		"",
+	}
+	footer := mutedStyle.Render("↑/↓ scroll  Esc: back to history")
+
+	if m.gitLogRendered == "" || m.gitLogRenderedWidth != innerWidth {
+		m.gitLogRendered = strings.TrimRight(renderMarkdown(m.gitLogRevision, innerWidth), "\n")
+		m.gitLogRenderedWidth = innerWidth
+	}
+	m.gitLogViewport.Width = innerWidth
+	m.gitLogViewport.Height = max(1, innerHeight-len(header)-2)
+	m.gitLogViewport.SetContent(m.gitLogRendered)
+
+	content := strings.Join(header, "\n") + "\n" + m.gitLogViewport.View() + "\n\n" + footer
+	return popupStyle.Width(width).Height(height).Render(padBlock(content, innerWidth, innerHeight))
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseGitFileLog(t *testing.T) {
+	out := gitLogRecordSep + "abc1234\t2026-10-15\tRename plan\n\nwork/plan.md\n" +
+		gitLogRecordSep + "def5678\t2026-10-01\tFirst draft\twith tab\n\nplan.md"
+	got := parseGitFileLog(out, "work/plan.md")
+	want := []gitCommit{
+		{hash: "abc1234", date: "2026-10-15", subject: "Rename plan", relPath: "work/plan.md"},
+		{hash: "def5678", date: "2026-10-01", subject: "First draft\twith tab", relPath: "plan.md"},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("parseGitFileLog = %+v, want %+v", got, want)
+	}
+	if got := parseGitFileLog("", "a.md"); len(got) != 0 {
+		t.Fatalf("expected no commits for empty output, got %+v", got)
+	}
+}
+
+func TestOpenGitLogPopupOutsideRepo(t *testing.T) {
+	m := &Model{currentFile: "/notes/a.md", notesDir: "/notes"}
+	m.openGitLogPopup()
+	if m.overlay != overlayNone || m.status != "Git is unavailable for this notes directory" {
+		t.Fatalf("expected non-repo status, got overlay %v status %q", m.overlay, m.status)
+	}
+}
+
+func TestGitLogPopupRevisionEscReturnsToList(t *testing.T) {
+	m := &Model{currentFile: "/notes/a.md", notesDir: "/notes"}
+	m.gitLogEntries = []gitCommit{
+		{hash: "abc1234", date: "2026-10-15", subject: "Second", relPath: "a.md"},
+		{hash: "def5678", date: "2026-10-01", subject: "First", relPath: "a.md"},
+	}
+	m.openOverlay(overlayGitLog)
+
+	_, _ = m.handleGitLogPopupKey(tea.KeyMsg{Type: tea.KeyDown})
+	if m.gitLogCursor != 1 {
+		t.Fatalf("expected cursor on second commit, got %d", m.gitLogCursor)
+	}
+	if got := m.renderGitLogPopup(70, 14); !strings.Contains(got, "def5678  2026-10-01  First") {
+		t.Fatalf("expected commit row in list:\n%s", got)
+	}
+
+	m.gitLogRevision = "# Old title\n"
+	if got := m.renderGitLogPopup(70, 14); !strings.Contains(got, "Revision def5678") || !strings.Contains(got, "title") {
+		t.Fatalf("expected rendered revision:\n%s", got)
+	}
+	_, _ = m.handleGitLogPopupKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.overlay != overlayGitLog || m.gitLogRevision != "" {
+		t.Fatalf("expected Esc to return to the list, overlay %v revision %q", m.overlay, m.gitLogRevision)
+	}
+	_, _ = m.handleGitLogPopupKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.overlay != overlayNone {
+		t.Fatalf("expected second Esc to close, got %v", m.overlay)
+	}
+}
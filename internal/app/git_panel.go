@@ -0,0 +1,227 @@
+// git_panel.go implements the git panel popup (Ctrl+G): a single view of the
+// branch, upstream, ahead/behind counts, and changed files, with action rows
+// that dispatch to the existing commit / pull / push / refresh handlers.
+//
+// The panel reads m.git directly, so it reflects the latest refreshGitStatus
+// result after every action without needing to be reopened. Committing
+// leaves the panel for the message input and returns to it afterwards.
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// gitPanelAction identifies one of the action rows at the top of the panel.
+type gitPanelAction int
+
+const (
+	gitPanelCommit gitPanelAction = iota
+	gitPanelPull
+	gitPanelPush
+	gitPanelRefresh
+)
+
+// gitPanelActions lists the action rows in display order. File rows follow
+// them, so a cursor below len(gitPanelActions) selects an action.
+var gitPanelActions = []gitPanelAction{gitPanelCommit, gitPanelPull, gitPanelPush, gitPanelRefresh}
+
+// gitPanelActionLabel returns the row text for an action, including its
+// browse-mode key.
+func (m *Model) gitPanelActionLabel(action gitPanelAction) string {
+	switch action {
+	case gitPanelCommit:
+		return "Commit all changes… (" + m.primaryActionKey(actionGitCommit, "c") + ")"
+	case gitPanelPull:
+		return "Pull --ff-only (" + m.primaryActionKey(actionGitPull, "p") + ")"
+	case gitPanelPush:
+		return "Push (" + m.primaryActionKey(actionGitPush, "P") + ")"
+	default:
+		return "Refresh status"
+	}
+}
+
+// gitPanelRowCount returns the number of selectable rows in the panel.
+func (m *Model) gitPanelRowCount() int {
+	return len(gitPanelActions) + len(m.git.entries)
+}
+
+// openGitPanel refreshes git status and shows the git panel.
+func (m *Model) openGitPanel() {
+	m.refreshGitStatus()
+	if !m.git.isRepo {
+		m.status = m.gitUnavailableStatus()
+		return
+	}
+	m.openOverlay(overlayGitPanel)
+	m.gitPanelCursor = 0
+	m.status = "Git panel: Enter to run or open, Esc to close"
+}
+
+// resumeGitPanel reopens the panel after a commit started from it has been
+// submitted or cancelled. The commit status message is left in place.
+func (m *Model) resumeGitPanel() {
+	if !m.gitPanelResume || m.mode != modeBrowse {
+		return
+	}
+	m.gitPanelResume = false
+	if !m.git.isRepo {
+		return
+	}
+	m.openOverlay(overlayGitPanel)
+	m.clampGitPanelCursor()
+}
+
+// clampGitPanelCursor keeps the cursor on a valid row after the file list
+// shrinks (e.g. after a commit or pull).
+func (m *Model) clampGitPanelCursor() {
+	m.gitPanelCursor = max(0, min(m.gitPanelCursor, m.gitPanelRowCount()-1))
+}
+
+// handleGitPanelKey routes key presses while the git panel is visible.
+func (m *Model) handleGitPanelKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.gitPanelCursor, m.gitPanelRowCount())
+	if !handled {
+		return m, nil
+	}
+	if closePressed {
+		m.closeOverlay()
+		m.status = "Git panel closed"
+		return m, nil
+	}
+	m.gitPanelCursor = next
+	if !selectPressed {
+		return m, nil
+	}
+	if m.gitPanelCursor < len(gitPanelActions) {
+		return m.runGitPanelAction(gitPanelActions[m.gitPanelCursor])
+	}
+	return m.openGitPanelEntry(m.git.entries[m.gitPanelCursor-len(gitPanelActions)])
+}
+
+// runGitPanelAction dispatches an action row to the matching git handler.
+// Pull, push, and refresh keep the panel open on the refreshed status;
+// commit hands over to the message input and resumes the panel afterwards.
+func (m *Model) runGitPanelAction(action gitPanelAction) (tea.Model, tea.Cmd) {
+	var (
+		model tea.Model = m
+		cmd   tea.Cmd
+	)
+	switch action {
+	case gitPanelCommit:
+		m.closeOverlay()
+		m.gitPanelResume = true
+		return m.handleGitCommitStart()
+	case gitPanelPull:
+		model, cmd = m.handleGitPull()
+	case gitPanelPush:
+		model, cmd = m.handleGitPush()
+	case gitPanelRefresh:
+		m.refreshGitStatus()
+		m.status = "Git status refreshed"
+	}
+	if !m.git.isRepo {
+		m.closeOverlay()
+		return model, cmd
+	}
+	m.clampGitPanelCursor()
+	return model, cmd
+}
+
+// openGitPanelEntry closes the panel and jumps to a changed markdown note.
+// Deleted files, non-markdown files, and paths outside the notes directory
+// are reported in the status bar instead.
+func (m *Model) openGitPanelEntry(entry gitStatusEntry) (tea.Model, tea.Cmd) {
+	if !hasSuffixCaseInsensitive(entry.path, ".md") {
+		m.status = "Not a markdown note: " + entry.path
+		return m, nil
+	}
+	path := filepath.Join(m.git.root, filepath.FromSlash(entry.path))
+	if m.git.root == "" || !isWithinRoot(m.notesDir, path) {
+		m.status = "Outside the notes directory: " + entry.path
+		return m, nil
+	}
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		m.status = "File no longer exists: " + entry.path
+		return m, nil
+	}
+	m.closeOverlay()
+	m.expandParentDirs(path)
+	m.rebuildTreeKeep(path)
+	m.status = "Jumped to changed note: " + m.displayRelative(path)
+	return m, m.setFocusedFile(path)
+}
+
+// gitPanelHeader summarizes the branch, upstream, and ahead/behind counts.
+func (m *Model) gitPanelHeader() string {
+	branch := m.git.branch
+	if branch == "" {
+		branch = "(detached)"
+	}
+	if !m.git.hasUpstream {
+		return "Branch " + branch + "  (no upstream)"
+	}
+	return fmt.Sprintf("Branch %s → %s  ↑%d ↓%d", branch, m.git.upstream, m.git.ahead, m.git.behind)
+}
+
+// gitStatusEntryLabel formats a file row: the XY status letters followed by
+// the path, with "orig → path" for renames and copies.
+func gitStatusEntryLabel(entry gitStatusEntry) string {
+	path := entry.path
+	if entry.origPath != "" {
+		path = entry.origPath + " → " + entry.path
+	}
+	return strings.ReplaceAll(entry.code, " ", "·") + " " + path
+}
+
+// renderGitPanel draws the git panel. Action rows are always visible; the
+// file list below them scrolls to keep the cursor in view.
+func (m *Model) renderGitPanel(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	lines := []string{
+		titleStyle.Render("Git"),
+		mutedStyle.Render(truncate(m.gitPanelHeader(), innerWidth)),
+		"",
+	}
+	for i, action := range gitPanelActions {
+		line := truncate(m.gitPanelActionLabel(action), innerWidth)
+		if i == m.gitPanelCursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "")
+	if m.git.lastError != "" {
+		lines = append(lines, mutedStyle.Render(truncate("Status error: "+m.git.lastError, innerWidth)))
+	}
+	if len(m.git.entries) == 0 {
+		lines = append(lines, mutedStyle.Render("Working tree clean"))
+	} else {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("Changes (%d)", len(m.git.entries))))
+	}
+
+	limit := max(0, innerHeight-len(lines)-1)
+	selected := m.gitPanelCursor - len(gitPanelActions)
+	start := 0
+	if limit > 0 {
+		start = max(0, selected-limit+1)
+	}
+	for i := start; i < min(start+limit, len(m.git.entries)); i++ {
+		line := truncate(gitStatusEntryLabel(m.git.entries[i]), innerWidth)
+		if i == selected {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, mutedStyle.Render("Enter: run/open  Esc: close"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
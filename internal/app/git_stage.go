@@ -0,0 +1,193 @@
+// git_stage.go limits commits to the notes this app instance changed.
+//
+// `git add -A` stages the whole repository, which is wrong when the notes
+// directory is a subfolder of a bigger repository or when other tools drop
+// files into the workspace. Instead, every filesystem mutation that goes
+// through applyMutationEffects records its paths in m.gitTouched: saved and
+// created notes, both sides of a rename or move, and deleted items. Folder
+// paths are only recorded for renames and moves (a mutation that also
+// removed a path), so creating a note does not sweep in its whole folder.
+// Changes the watcher reports (edits in another editor, sync tools, a git
+// pull) are marked external and never recorded.
+//
+// A commit stages exactly those paths (gitStagePaths): existing ones with
+// `git add -A -- <paths>`, which also records deletions inside a renamed
+// folder, and vanished ones with `git rm -r --cached --ignore-unmatch`, which
+// stages deletions and ignores notes created and deleted again before they
+// were ever committed. The commit itself is limited to the staged changes
+// under those paths (`git commit -- <paths>`), so files staged outside the
+// app, possibly outside the notes directory, are not swept into it. Paths
+// are passed with --literal-pathspecs so names with glob characters are not
+// expanded. A successful commit clears the paths it staged; a failed one
+// keeps them for the next attempt.
+//
+// git_stage_all restores the old `git add -A` behavior. When nothing was
+// tracked but the working tree is dirty, the commit asks before staging
+// everything for that one commit; auto-commit skips instead.
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recordGitTouched adds the paths of a filesystem mutation to the set
+// staged by the next commit.
+func (m *Model) recordGitTouched(opts mutationEffects) {
+	for _, path := range opts.removePaths {
+		m.touchGitPath(path)
+	}
+	for _, path := range opts.upsertPaths {
+		if info, err := os.Stat(path); err == nil && info.IsDir() && len(opts.removePaths) == 0 {
+			continue
+		}
+		m.touchGitPath(path)
+	}
+}
+
+// touchGitPath records one path inside the notes directory, skipping the
+// root itself and the managed .cli-notes directory.
+func (m *Model) touchGitPath(path string) {
+	if path == "" || m.notesDir == "" || path == m.notesDir || !isWithinRoot(m.notesDir, path) {
+		return
+	}
+	if isWithinRoot(filepath.Join(m.notesDir, managedNotesDirName), path) {
+		return
+	}
+	if m.gitTouched == nil {
+		m.gitTouched = map[string]bool{}
+	}
+	m.gitTouched[path] = true
+}
+
+// gitTouchedPaths returns the tracked paths, sorted.
+func (m *Model) gitTouchedPaths() []string {
+	paths := make([]string, 0, len(m.gitTouched))
+	for path := range m.gitTouched {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// clearGitTouched forgets the paths a finished commit staged.
+func (m *Model) clearGitTouched(res gitOpResultMsg) {
+	if res.stagedAll {
+		m.gitTouched = nil
+		return
+	}
+	for _, path := range res.paths {
+		delete(m.gitTouched, path)
+	}
+}
+
+// confirmBroadGitCommit asks before a commit that has no tracked paths
+// stages every change in the repository, then continues to the message
+// input.
+func (m *Model) confirmBroadGitCommit() {
+	m.gitPanelResume = false
+	m.askConfirm(confirmPrompt{
+		question:     fmt.Sprintf("No notes changed in the app since the last commit. Stage all %d changed paths in the repository? (y/n)", len(m.git.entries)),
+		yesHint:      "stage all",
+		noHint:       "cancel",
+		cancelStatus: "Git commit cancelled",
+		returnMode:   modeBrowse,
+		onConfirm: func() (tea.Model, tea.Cmd) {
+			m.gitStageAllNext = true
+			return m.startGitCommitMessage()
+		},
+	})
+}
+
+// gitStagePaths stages paths (absolute, inside dir) in dir's repository and
+// returns them relative to dir.
+func gitStagePaths(dir string, paths []string) ([]string, string, error) {
+	var present, missing []string
+	for _, path := range paths {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Lstat(path); err == nil {
+			present = append(present, rel)
+		} else {
+			missing = append(missing, rel)
+		}
+	}
+	if len(present) > 0 {
+		args := append([]string{"--literal-pathspecs", "add", "-A", "--"}, present...)
+		if out, err := gitRun(dir, args...); err != nil {
+			return nil, out, err
+		}
+	}
+	if len(missing) > 0 {
+		args := append([]string{"--literal-pathspecs", "rm", "-r", "-q", "--cached", "--ignore-unmatch", "--"}, missing...)
+		if out, err := gitRun(dir, args...); err != nil {
+			return nil, out, err
+		}
+	}
+	return append(present, missing...), "", nil
+}
+
+// gitStagedUnder lists the staged changes under rels (relative to dir), as
+// paths relative to dir. Notes created and deleted again before a commit
+// have no staged change and are left out.
+func gitStagedUnder(dir string, rels []string) ([]string, string, error) {
+	args := append([]string{"--literal-pathspecs", "diff", "--cached", "--no-renames", "--name-only", "--relative", "-z", "--"}, rels...)
+	out, err := gitRun(dir, args...)
+	if err != nil {
+		return nil, out, err
+	}
+	var names []string
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, "", nil
+}
+
+// gitCommitPaths stages paths (see gitStagePaths) and commits only their
+// staged changes, so anything else staged in the repository (by another tool
+// or by hand) stays staged and out of the commit. The paths are carried on
+// the result so a successful commit can clear them.
+func gitCommitPaths(dir, message string, paths []string) gitOpResultMsg {
+	res := gitOpResultMsg{op: "commit", message: message, step: "add", paths: paths}
+	rels, out, err := gitStagePaths(dir, paths)
+	if err != nil {
+		res.out, res.err = out, err
+		return res
+	}
+	res.step = "commit"
+	names, out, err := gitStagedUnder(dir, rels)
+	if err != nil {
+		res.out, res.err = out, err
+		return res
+	}
+	if len(names) == 0 {
+		res.out, res.err = "nothing to commit", errors.New("nothing to commit")
+		return res
+	}
+	res.out, res.err = gitRun(dir, append([]string{"--literal-pathspecs", "commit", "-m", message, "--"}, names...)...)
+	return res
+}
+
+// gitCommitFunc returns how a commit stages its changes: everything when
+// git_stage_all or stageAll is set, otherwise the tracked paths. ok is false
+// when nothing is tracked.
+func (m *Model) gitCommitFunc(message string, stageAll bool) (run func(dir string) gitOpResultMsg, ok bool) {
+	if m.gitStageAll || stageAll {
+		return func(dir string) gitOpResultMsg { return gitCommitAll(dir, message) }, true
+	}
+	paths := m.gitTouchedPaths()
+	if len(paths) == 0 {
+		return nil, false
+	}
+	return func(dir string) gitOpResultMsg { return gitCommitPaths(dir, message, paths) }, true
+}
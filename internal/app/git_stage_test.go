@@ -0,0 +1,226 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newGitStageRepo initialises a repository with the notes directory as a
+// subfolder next to an unrelated file, and commits both.
+func newGitStageRepo(t *testing.T) (repo, notes string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "Test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+	repo = t.TempDir()
+	notes = filepath.Join(repo, "notes")
+	mustWriteFile(t, filepath.Join(notes, "keep.md"), "# Keep\n")
+	mustWriteFile(t, filepath.Join(notes, "old.md"), "# Old\n")
+	mustWriteFile(t, filepath.Join(notes, "gone.md"), "# Gone\n")
+	mustWriteFile(t, filepath.Join(repo, "other.txt"), "one\n")
+	for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}, {"commit", "-q", "-m", "init"}} {
+		if out, err := runGitIn(repo, args...); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return repo, notes
+}
+
+func TestGitCommitPathsStagesOnlyTouchedNotes(t *testing.T) {
+	repo, notes := newGitStageRepo(t)
+	mustWriteFile(t, filepath.Join(repo, "other.txt"), "two\n")
+	m := newTestCRUDModel(notes)
+
+	oldPath, newPath := filepath.Join(notes, "old.md"), filepath.Join(notes, "new.md")
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+	m.applyMutationEffects(mutationEffects{upsertPaths: []string{newPath}, removePaths: []string{oldPath}})
+	gone := filepath.Join(notes, "gone.md")
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+	m.applyMutationEffects(mutationEffects{removePaths: []string{gone}})
+	m.applyMutationEffects(mutationEffects{upsertPaths: []string{notes, filepath.Join(notes, managedNotesDirName, "state.json")}})
+
+	var got []string
+	for _, path := range m.gitTouchedPaths() {
+		got = append(got, strings.TrimPrefix(path, notes+string(filepath.Separator)))
+	}
+	if strings.Join(got, ",") != "gone.md,new.md,old.md" {
+		t.Fatalf("unexpected touched paths %v", got)
+	}
+	run, ok := m.gitCommitFunc("Update notes", false)
+	if !ok {
+		t.Fatal("expected a commit of the touched paths")
+	}
+	m.finishGitCommit(run(notes))
+	if m.status != "Committed: Update notes" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	if len(m.gitTouched) != 0 {
+		t.Fatalf("expected touched paths cleared, got %v", m.gitTouchedPaths())
+	}
+
+	out, err := runGitIn(repo, "show", "--name-status", "--format=", "HEAD")
+	if err != nil {
+		t.Fatalf("git show: %v\n%s", err, out)
+	}
+	for _, want := range []string{"notes/gone.md", "notes/new.md", "notes/old.md"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %s in the commit, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "other.txt") {
+		t.Fatalf("unrelated file was committed:\n%s", out)
+	}
+	status, _ := runGitIn(repo, "status", "--porcelain")
+	if strings.TrimSpace(status) != "M other.txt" {
+		t.Fatalf("expected only other.txt left unstaged, got %q", status)
+	}
+}
+
+func TestGitCommitNeverStagesExternalChanges(t *testing.T) {
+	repo, notes := newGitStageRepo(t)
+	m := newTestCRUDModel(notes)
+	m.fsWatcher = &fsEventWatcher{root: notes, batches: make(chan fsEventsMsg)}
+
+	keep := filepath.Join(notes, "keep.md")
+	mustWriteFile(t, keep, "# Keep\nedited in the app\n")
+	m.applyMutationEffects(mutationEffects{upsertPaths: []string{keep}})
+	// Another editor, a sync tool, or a pull changes these; the watcher
+	// reports them.
+	external := []string{filepath.Join(notes, "old.md"), filepath.Join(notes, "gone.md")}
+	for _, path := range external {
+		mustWriteFile(t, path, "# Changed elsewhere\n")
+	}
+	_, _ = m.handleFSEvents(fsEventsMsg{watcher: m.fsWatcher, paths: external})
+
+	if got := m.gitTouchedPaths(); len(got) != 1 || got[0] != keep {
+		t.Fatalf("expected only the in-app edit tracked, got %v", got)
+	}
+	run, ok := m.gitCommitFunc("Update notes", false)
+	if !ok {
+		t.Fatal("expected a commit of the touched path")
+	}
+	m.finishGitCommit(run(notes))
+	out, err := runGitIn(repo, "show", "--name-status", "--format=", "HEAD")
+	if err != nil {
+		t.Fatalf("git show: %v\n%s", err, out)
+	}
+	if strings.TrimSpace(out) != "M\tnotes/keep.md" {
+		t.Fatalf("expected only keep.md committed, got:\n%s", out)
+	}
+}
+
+func TestGitCommitPathsLeavesOtherStagedFilesOut(t *testing.T) {
+	repo, notes := newGitStageRepo(t)
+	mustWriteFile(t, filepath.Join(repo, "other", "x.txt"), "staged by hand\n")
+	mustWriteFile(t, filepath.Join(repo, "other.txt"), "two\n")
+	if out, err := runGitIn(repo, "add", "--", "other/x.txt", "other.txt"); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+	m := newTestCRUDModel(notes)
+	note := filepath.Join(notes, "a.md")
+	mustWriteFile(t, note, "# A\n")
+	m.applyMutationEffects(mutationEffects{upsertPaths: []string{note}})
+
+	run, ok := m.gitCommitFunc("Add a", false)
+	if !ok {
+		t.Fatal("expected a commit of the touched path")
+	}
+	m.finishGitCommit(run(notes))
+	if m.status != "Committed: Add a" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	out, err := runGitIn(repo, "show", "--name-status", "--format=", "HEAD")
+	if err != nil {
+		t.Fatalf("git show: %v\n%s", err, out)
+	}
+	if strings.TrimSpace(out) != "A\tnotes/a.md" {
+		t.Fatalf("expected only notes/a.md committed, got:\n%s", out)
+	}
+	status, _ := runGitIn(repo, "status", "--porcelain")
+	for _, want := range []string{"M  other.txt", "A  other/x.txt"} {
+		if !strings.Contains(status, want) {
+			t.Fatalf("expected %q still staged, got:\n%s", want, status)
+		}
+	}
+}
+
+func TestGitCommitPathsReportsNothingToCommit(t *testing.T) {
+	_, notes := newGitStageRepo(t)
+	m := newTestCRUDModel(notes)
+	// Created and deleted again before any commit: nothing to stage.
+	note := filepath.Join(notes, "brief.md")
+	m.applyMutationEffects(mutationEffects{removePaths: []string{note}})
+
+	run, ok := m.gitCommitFunc("Update notes", false)
+	if !ok {
+		t.Fatal("expected a commit of the touched path")
+	}
+	m.finishGitCommit(run(notes))
+	if m.status != "Nothing to commit" || len(m.gitTouched) != 0 {
+		t.Fatalf("unexpected status %q, touched %v", m.status, m.gitTouchedPaths())
+	}
+}
+
+func TestGitTouchedPathsSurviveFailedCommit(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	path := filepath.Join(root, "a.md")
+	mustWriteFile(t, path, "# A\n")
+	m.applyMutationEffects(mutationEffects{upsertPaths: []string{path}})
+
+	withGitRun(t, func(dir string, args ...string) (string, error) {
+		if len(args) > 1 && args[1] == "add" {
+			return "fatal: index.lock exists", errors.New("exit status 128")
+		}
+		return "", nil
+	})
+	run, ok := m.gitCommitFunc("Update notes", false)
+	if !ok {
+		t.Fatal("expected a commit of the touched path")
+	}
+	m.finishGitCommit(run(root))
+	if !strings.HasPrefix(m.status, "Git add failed") || !m.gitTouched[path] {
+		t.Fatalf("expected the path kept after a failed add, status %q touched %v", m.status, m.gitTouched)
+	}
+}
+
+func TestGitCommitStartConfirmsBroadStaging(t *testing.T) {
+	m := newTestCRUDModel(t.TempDir())
+	m.mode = modeBrowse
+	m.git.isRepo = true
+	m.git.dirty = true
+	m.gitPanelResume = true
+
+	_, _ = m.handleGitCommitStart()
+	if m.mode != modeConfirm || !strings.Contains(m.status, "Stage all") {
+		t.Fatalf("expected a stage-all confirmation, got mode %v status %q", m.mode, m.status)
+	}
+	_, _ = m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m.mode != modeGitCommit || !m.gitStageAllNext {
+		t.Fatalf("expected the commit input with stage-all armed, got mode %v next %v", m.mode, m.gitStageAllNext)
+	}
+
+	m.mode = modeBrowse
+	m.gitStageAllNext = false
+	m.gitStageAll = true
+	_, _ = m.handleGitCommitStart()
+	if m.mode != modeGitCommit {
+		t.Fatalf("expected git_stage_all to skip the confirmation, got mode %v", m.mode)
+	}
+}
@@ -1,6 +1,11 @@
 package app
 
-import "testing"
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
 
 func TestParseGitPorcelainBranchLine(t *testing.T) {
 	tests := []struct {
@@ -24,3 +29,160 @@ func TestParseGitPorcelainBranchLine(t *testing.T) {
 		}
 	}
 }
+
+func TestParseGitUpstreamName(t *testing.T) {
+	tests := map[string]string{
+		"## main":               "",
+		"## main...origin/main": "origin/main",
+		"## feature/x...upstream/feature/x [ahead 1]": "upstream/feature/x",
+		"## No commits yet on main":                   "",
+	}
+	for line, want := range tests {
+		if got := parseGitUpstreamName(line); got != want {
+			t.Fatalf("line %q: got upstream %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestParseGitPorcelainEntries(t *testing.T) {
+	lines := []string{
+		"## main...origin/main [ahead 1]",
+		" M notes/a.md",
+		"A  new.md",
+		"?? scratch/",
+		"R  old name.md -> new name.md",
+		"C  src.md -> copy.md",
+		`R  "we\"ird -> x.md" -> "caf\303\251.md"`,
+		`?? "with space.md"`,
+		"",
+	}
+	want := []gitStatusEntry{
+		{code: " M", path: "notes/a.md"},
+		{code: "A ", path: "new.md"},
+		{code: "??", path: "scratch/"},
+		{code: "R ", path: "new name.md", origPath: "old name.md"},
+		{code: "C ", path: "copy.md", origPath: "src.md"},
+		{code: "R ", path: "café.md", origPath: `we"ird -> x.md`},
+		{code: "??", path: "with space.md"},
+	}
+	got := parseGitPorcelainEntries(lines)
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %#v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("entry %d: got %#v, want %#v", i, got[i], want[i])
+		}
+	}
+	if entries := parseGitPorcelainEntries([]string{"## main"}); len(entries) != 0 {
+		t.Fatalf("expected clean tree to have no entries, got %#v", entries)
+	}
+}
+
+func TestGitStatusEntryLabel(t *testing.T) {
+	if got := gitStatusEntryLabel(gitStatusEntry{code: " M", path: "a.md"}); got != "·M a.md" {
+		t.Fatalf("unexpected label %q", got)
+	}
+	if got := gitStatusEntryLabel(gitStatusEntry{code: "R ", path: "b.md", origPath: "a.md"}); got != "R· a.md → b.md" {
+		t.Fatalf("unexpected rename label %q", got)
+	}
+}
+
+func TestGitPanelEntryOpensChangedNote(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "projects", "x.md")
+	mustWriteFile(t, note, "# X\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.git = gitRepoStatus{isRepo: true, root: root, entries: []gitStatusEntry{
+		{code: "??", path: "image.png"},
+		{code: " D", path: "gone.md"},
+		{code: " M", path: "projects/x.md"},
+	}}
+	m.openOverlay(overlayGitPanel)
+
+	m.openGitPanelEntry(m.git.entries[0])
+	if m.overlay != overlayGitPanel || m.status != "Not a markdown note: image.png" {
+		t.Fatalf("expected non-markdown entry to be refused, status %q", m.status)
+	}
+	m.openGitPanelEntry(m.git.entries[1])
+	if m.overlay != overlayGitPanel || m.status != "File no longer exists: gone.md" {
+		t.Fatalf("expected deleted entry to be refused, status %q", m.status)
+	}
+	m.openGitPanelEntry(m.git.entries[2])
+	if m.overlay != overlayNone {
+		t.Fatalf("expected panel to close after jumping")
+	}
+	if m.selectedPath() != note {
+		t.Fatalf("expected %q selected in tree, got %q", note, m.selectedPath())
+	}
+}
+
+func TestGitPanelCursorClampsWhenEntriesShrink(t *testing.T) {
+	m := &Model{git: gitRepoStatus{isRepo: true, entries: []gitStatusEntry{{code: " M", path: "a.md"}}}}
+	m.gitPanelCursor = len(gitPanelActions)
+	m.git.entries = nil
+	m.clampGitPanelCursor()
+	if m.gitPanelCursor != len(gitPanelActions)-1 {
+		t.Fatalf("expected cursor on last action row, got %d", m.gitPanelCursor)
+	}
+}
+
+func TestRefreshGitStatusReportsMissingGitOnce(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.gitMissingNotice = true
+	t.Setenv("PATH", t.TempDir())
+
+	m.refreshGitStatus()
+	if !m.git.missing || m.git.isRepo {
+		t.Fatalf("expected git to be reported missing, got %+v", m.git)
+	}
+	if m.status != gitMissingStatus {
+		t.Fatalf("expected the missing-git notice, got status %q", m.status)
+	}
+
+	m.status = ""
+	m.refreshGitStatus()
+	if m.status != "" {
+		t.Fatalf("expected the notice only once, got status %q", m.status)
+	}
+	_, _ = m.handleGitCommitStart()
+	if m.status != gitMissingStatus {
+		t.Fatalf("expected git actions to name the missing binary, got %q", m.status)
+	}
+}
+
+func TestRefreshGitStatusNotARepoStaysQuiet(t *testing.T) {
+	m := newTestCRUDModel(t.TempDir())
+	m.gitMissingNotice = true
+	gitRun = func(string, ...string) (string, error) {
+		return "fatal: not a git repository", errors.New("exit status 128")
+	}
+	t.Cleanup(func() { gitRun = runGitIn })
+
+	m.status = ""
+	m.refreshGitStatus()
+	if m.git.missing || m.git.isRepo || m.status != "" {
+		t.Fatalf("expected a silent not-a-repo, got %+v status %q", m.git, m.status)
+	}
+	_, _ = m.handleGitCommitStart()
+	if m.status != "Git is unavailable for this notes directory" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
+func TestRefreshGitStatusMissingGitNoticeCanBeDisabled(t *testing.T) {
+	m := newTestCRUDModel(t.TempDir())
+	gitRun = func(string, ...string) (string, error) {
+		return "", &exec.Error{Name: "git", Err: exec.ErrNotFound}
+	}
+	t.Cleanup(func() { gitRun = runGitIn })
+
+	m.status = ""
+	m.refreshGitStatus()
+	if !m.git.missing || m.status != "" {
+		t.Fatalf("expected a silent missing git with the notice off, got %+v status %q", m.git, m.status)
+	}
+}
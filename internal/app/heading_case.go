@@ -0,0 +1,279 @@
+// heading_case.go implements the heading case popup (`H`), which rewrites
+// every ATX heading in the current note to title case or sentence case.
+//
+// Only the heading text changes: the `#` markers, any closing `#` sequence,
+// frontmatter, fenced code blocks, and body lines are left as they are.
+// Words that already carry inner capitals (acronyms like "API", names like
+// "iPhone"), inline code spans, wiki links, and URLs are kept verbatim so the
+// rewrite never breaks a reference.
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// headingCase selects the target case for convertHeadingCase.
+type headingCase int
+
+const (
+	headingTitleCase headingCase = iota
+	headingSentenceCase
+)
+
+// headingCaseOptions are the popup rows, in headingCase order.
+var headingCaseOptions = []string{"Title Case", "Sentence case"}
+
+// titleCaseMinorWords stay lowercase in title case unless they start or end
+// the heading.
+var titleCaseMinorWords = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "at": true, "but": true,
+	"by": true, "for": true, "in": true, "nor": true, "of": true, "on": true,
+	"or": true, "the": true, "to": true, "vs": true, "via": true,
+}
+
+// openHeadingCasePopup opens the case chooser for the current note.
+func (m *Model) openHeadingCasePopup() {
+	if m.currentFile == "" {
+		m.status = "Select a note first"
+		return
+	}
+	if !hasSuffixCaseInsensitive(m.currentFile, ".md") {
+		m.status = "Heading case supports markdown notes only"
+		return
+	}
+	m.openOverlay(overlayHeadingCase)
+	m.headingCaseCursor = 0
+	m.status = "Heading case: choose title or sentence case"
+}
+
+// handleHeadingCasePopupKey routes key presses while the popup is visible.
+func (m *Model) handleHeadingCasePopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.headingCaseCursor, len(headingCaseOptions))
+	if !handled {
+		return m, nil
+	}
+	if closePressed {
+		m.closeOverlay()
+		m.status = "Heading case cancelled"
+		return m, nil
+	}
+	m.headingCaseCursor = next
+	if selectPressed {
+		m.closeOverlay()
+		return m, m.applyHeadingCase(headingCase(m.headingCaseCursor))
+	}
+	return m, nil
+}
+
+// applyHeadingCase rewrites the current note's headings and refreshes it.
+func (m *Model) applyHeadingCase(target headingCase) tea.Cmd {
+	path := m.currentFile
+	content, err := os.ReadFile(path)
+	if err != nil {
+		m.setStatusError("Error reading note", err, "path", path)
+		return nil
+	}
+	updated, changed := convertHeadingCase(string(content), target)
+	if changed == 0 {
+		m.status = "Headings already in " + headingCaseOptions[target]
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(updated), FilePermission); err != nil {
+		m.setStatusError("Error saving note", err, "path", path)
+		return nil
+	}
+
+	m.invalidateTreeMetadataPath(path)
+	delete(m.renderCache, path)
+	cmd := m.applyMutationEffects(mutationEffects{
+		upsertPaths:     []string{path},
+		refreshGit:      true,
+		rebuildKeepPath: path,
+		setCurrentFile:  path,
+	})
+	noun := "headings"
+	if changed == 1 {
+		noun = "heading"
+	}
+	m.status = fmt.Sprintf("Converted %d %s to %s", changed, noun, headingCaseOptions[target])
+	return cmd
+}
+
+// convertHeadingCase rewrites the text of every ATX heading outside
+// frontmatter and fenced code blocks, returning the new content and the
+// number of headings that changed.
+func convertHeadingCase(content string, target headingCase) (string, int) {
+	lines := strings.Split(content, "\n")
+	start := 0
+	if len(lines) > 0 && strings.TrimRight(lines[0], "\r") == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimRight(lines[i], "\r") == "---" {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	changed := 0
+	inFence := false
+	for i := start; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if rewritten, ok := convertHeadingLine(lines[i], target); ok && rewritten != lines[i] {
+			lines[i] = rewritten
+			changed++
+		}
+	}
+	return strings.Join(lines, "\n"), changed
+}
+
+// convertHeadingLine converts one line if it is an ATX heading, keeping its
+// indentation, markers, closing sequence, and line ending.
+func convertHeadingLine(line string, target headingCase) (string, bool) {
+	body := strings.TrimRight(line, "\r")
+	ending := line[len(body):]
+	indent := len(body) - len(strings.TrimLeft(body, " "))
+	if indent > 3 {
+		return line, false
+	}
+	level := 0
+	for indent+level < len(body) && body[indent+level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return line, false
+	}
+	rest := body[indent+level:]
+	if rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+		return line, false
+	}
+
+	// Split the text from its surrounding spaces and optional closing #s.
+	text := strings.TrimLeft(rest, " \t")
+	lead := rest[:len(rest)-len(text)]
+	trail := ""
+	if trimmed := strings.TrimRight(text, " \t"); trimmed != text {
+		trail = text[len(trimmed):]
+		text = trimmed
+	}
+	if closing := strings.TrimRight(text, "#"); closing != text && (closing == "" || strings.HasSuffix(closing, " ") || strings.HasSuffix(closing, "\t")) {
+		trimmed := strings.TrimRight(closing, " \t")
+		trail = text[len(trimmed):] + trail
+		text = trimmed
+	}
+	if text == "" {
+		return line, false
+	}
+	return body[:indent+level] + lead + convertHeadingText(text, target) + trail + ending, true
+}
+
+// convertHeadingText applies the target case word by word.
+func convertHeadingText(text string, target headingCase) string {
+	words := strings.Split(text, " ")
+	first, last := -1, -1
+	for i, word := range words {
+		if headingWordCore(word) != "" {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+
+	inCode, inWiki := false, false
+	for i, word := range words {
+		protected := inCode || inWiki || strings.Contains(word, "`") || strings.Contains(word, "[[") ||
+			strings.Contains(word, "://") || strings.Contains(word, "](")
+		inCode = inCode != (strings.Count(word, "`")%2 == 1)
+		if strings.Contains(word, "[[") {
+			inWiki = true
+		}
+		if strings.Contains(word, "]]") {
+			inWiki = false
+		}
+		if protected {
+			continue
+		}
+		words[i] = convertHeadingWord(word, target, i == first, i == last)
+	}
+	return strings.Join(words, " ")
+}
+
+// convertHeadingWord cases one word. Leading punctuation such as quotes or
+// brackets is skipped when finding the first letter.
+func convertHeadingWord(word string, target headingCase, isFirst, isLast bool) string {
+	core := headingWordCore(word)
+	if core == "" || hasInnerCapital(core) {
+		return word
+	}
+	idx := strings.Index(word, core)
+	prefix, suffix := word[:idx], word[idx+len(core):]
+
+	lower := strings.ToLower(core)
+	switch {
+	case isFirst:
+		core = capitalizeFirst(lower)
+	case target == headingSentenceCase:
+		core = lower
+	case titleCaseMinorWords[lower] && !isLast:
+		core = lower
+	default:
+		core = capitalizeFirst(lower)
+	}
+	// Hyphenated compounds are title-cased part by part ("Follow-Up").
+	if target == headingTitleCase && strings.Contains(core, "-") {
+		parts := strings.Split(core, "-")
+		for j := range parts {
+			parts[j] = capitalizeFirst(parts[j])
+		}
+		core = strings.Join(parts, "-")
+	}
+	return prefix + core + suffix
+}
+
+// headingWordCore returns word without leading and trailing non-letter,
+// non-digit characters.
+func headingWordCore(word string) string {
+	return strings.TrimFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// hasInnerCapital reports whether any letter after the first of a
+// hyphen-separated part is uppercase, which marks acronyms and brand names
+// that must keep their casing.
+func hasInnerCapital(word string) bool {
+	for _, part := range strings.Split(word, "-") {
+		_, size := utf8.DecodeRuneInString(part)
+		for _, r := range part[size:] {
+			if unicode.IsUpper(r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// capitalizeFirst uppercases the first rune of s.
+func capitalizeFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConvertHeadingCaseTitleAndSentence(t *testing.T) {
+	if got, n := convertHeadingCase("## hello world", headingTitleCase); got != "## Hello World" || n != 1 {
+		t.Fatalf("title case = %q (%d changed)", got, n)
+	}
+	if got, n := convertHeadingCase("## Hello World", headingSentenceCase); got != "## Hello world" || n != 1 {
+		t.Fatalf("sentence case = %q (%d changed)", got, n)
+	}
+}
+
+func TestConvertHeadingCaseWordRules(t *testing.T) {
+	for _, tc := range []struct {
+		in     string
+		target headingCase
+		want   string
+	}{
+		{"# the state of the art", headingTitleCase, "# The State of the Art"},
+		{"# what to look for", headingTitleCase, "# What to Look For"},
+		{"### follow-up with the API team ###", headingTitleCase, "### Follow-Up With the API Team ###"},
+		{"## Using `Go Build` With GitHub", headingSentenceCase, "## Using `Go Build` with GitHub"},
+		{"## See [[Project Plan]] Notes", headingSentenceCase, "## See [[Project Plan]] notes"},
+		{"#  \"quoted\" title  ", headingTitleCase, "#  \"Quoted\" Title  "},
+	} {
+		if got, _ := convertHeadingCase(tc.in, tc.target); got != tc.want {
+			t.Fatalf("convertHeadingCase(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestConvertHeadingCaseLeavesBodyAlone(t *testing.T) {
+	in := "---\n# yaml comment\ntitle: x\n---\n# my note\nbody text here\n```\n# not a heading\n```\n#hashtag line\n"
+	want := "---\n# yaml comment\ntitle: x\n---\n# My Note\nbody text here\n```\n# not a heading\n```\n#hashtag line\n"
+	got, n := convertHeadingCase(in, headingTitleCase)
+	if got != want || n != 1 {
+		t.Fatalf("convertHeadingCase =\n%q (%d changed), want\n%q", got, n, want)
+	}
+}
+
+func TestHeadingCasePopupRewritesCurrentNote(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "imported.md")
+	mustWriteFile(t, path, "# Meeting Notes\n\n## Action Items\ntext\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.currentFile = path
+	m.openHeadingCasePopup()
+	_, _ = m.handleHeadingCasePopupKey(tea.KeyMsg{Type: tea.KeyDown})
+	_, _ = m.handleHeadingCasePopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.overlay != overlayNone {
+		t.Fatalf("expected popup closed, got %v", m.overlay)
+	}
+	if got := readFileString(t, path); got != "# Meeting notes\n\n## Action items\ntext\n" {
+		t.Fatalf("unexpected note content %q", got)
+	}
+	if m.status != "Converted 2 headings to Sentence case" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
@@ -0,0 +1,130 @@
+// heading_promote.go turns the editor selection into a section heading.
+//
+// With a selection active, Ctrl+1..3 in edit mode promote the selected text
+// instead of toggling a heading on the whole line: the line is split around
+// the selection, the selected text becomes a heading of that level on its
+// own line, and what was before and after it stays as body text above and
+// below. Stray whitespace and trailing sentence punctuation are trimmed from
+// the heading, and leading punctuation from the remainder, so promoting
+// "Budget is due." out of "We met. Budget is due. Next week..." yields
+//
+//	We met.
+//	## Budget is due
+//	Next week...
+//
+// The cursor lands on the remainder line, or at the end of the heading when
+// nothing followed the selection. Selections spanning several lines, or
+// inside a fenced code block, are rejected.
+package app
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// headingTrimPunctuation is trimmed from both ends of a promoted heading and
+// from the start of the text left after it.
+const headingTrimPunctuation = ".,;:"
+
+// headingShortcut handles Ctrl+1..3: promote the selection when there is one,
+// otherwise toggle the heading on the current line.
+func (m *Model) headingShortcut(level int) {
+	if _, _, ok := m.editorSelectionRange(); ok {
+		m.promoteSelectionToHeading(level)
+		return
+	}
+	m.toggleHeading(level)
+}
+
+// promoteSelectionToHeading replaces the selected text with a heading of the
+// given level (1-6) on its own line.
+func (m *Model) promoteSelectionToHeading(level int) {
+	if level < 1 || level > 6 {
+		return
+	}
+	start, end, ok := m.editorSelectionRange()
+	if !ok {
+		m.status = "Select text to promote to a heading"
+		return
+	}
+	runes := []rune(m.editor.Value())
+	edit, ok := promoteHeading(runes, start, end, level)
+	if !ok {
+		m.status = edit.status
+		return
+	}
+
+	m.clearEditorSelection()
+	m.setEditorValueAndCursorOffset(edit.value, edit.cursor)
+	// Outline rows are indexes into the old heading list.
+	m.outlineHeadings = nil
+	m.outlineCursor = 0
+	m.status = fmt.Sprintf("Promoted selection to H%d: %s", level, edit.title)
+}
+
+// headingPromotion is the result of promoteHeading: the new editor value and
+// cursor, or a status explaining why the selection cannot be promoted.
+type headingPromotion struct {
+	value  string
+	cursor int
+	title  string
+	status string
+}
+
+// promoteHeading splits the line holding runes[start:end] around it and
+// turns the selected text into a heading.
+func promoteHeading(runes []rune, start, end, level int) (headingPromotion, bool) {
+	selected := string(runes[start:end])
+	if strings.Contains(selected, "\n") {
+		return headingPromotion{status: "Select text on a single line to make a heading"}, false
+	}
+	lineStart, lineEnd := lineBoundsAtOffset(runes, start)
+	if offsetInCodeFence(runes, lineStart) {
+		return headingPromotion{status: "Cannot make a heading inside a code block"}, false
+	}
+	title := strings.TrimFunc(selected, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(headingTrimPunctuation, r)
+	})
+	if title == "" {
+		return headingPromotion{status: "Selection has no text for a heading"}, false
+	}
+
+	before := strings.TrimRightFunc(string(runes[lineStart:start]), unicode.IsSpace)
+	after := strings.TrimLeftFunc(string(runes[end:lineEnd]), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(headingTrimPunctuation, r)
+	})
+	if strings.TrimSpace(before) == "" {
+		before = ""
+	}
+
+	var b strings.Builder
+	b.WriteString(string(runes[:lineStart]))
+	if before != "" {
+		b.WriteString(before + "\n")
+	}
+	b.WriteString(strings.Repeat("#", level) + " " + title)
+	cursor := len([]rune(b.String()))
+	if after != "" {
+		b.WriteString("\n" + after)
+		cursor++
+	}
+	b.WriteString(string(runes[lineEnd:]))
+	return headingPromotion{value: b.String(), cursor: cursor, title: title}, true
+}
+
+// offsetInCodeFence reports whether the line starting at lineStart is a
+// ``` fence or lies inside a fenced code block.
+func offsetInCodeFence(runes []rune, lineStart int) bool {
+	inFence := false
+	for _, line := range strings.Split(string(runes[:lineStart]), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+	}
+	_, lineEnd := lineBoundsAtOffset(runes, lineStart)
+	if strings.HasPrefix(strings.TrimSpace(string(runes[lineStart:lineEnd])), "```") {
+		return true
+	}
+	return inFence
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"strings"
+	"testing"
+)
+
+// selectEditorRange selects value[start:end] (rune offsets) in m's editor.
+func selectEditorRange(m *Model, value string, start, end int) {
+	m.setEditorValueAndCursorOffset(value, end)
+	m.editorSelectionAnchor = start
+	m.editorSelectionActive = true
+}
+
+func TestPromoteSelectionToHeading(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		selected   string
+		level      int
+		want       string
+		wantCursor int
+	}{
+		{
+			name:       "mid line",
+			value:      "We met. Budget is due. Next week we ship.",
+			selected:   "Budget is due. ",
+			level:      2,
+			want:       "We met.\n## Budget is due\nNext week we ship.",
+			wantCursor: 25,
+		},
+		{
+			name:       "line start",
+			value:      "Intro, then the rest",
+			selected:   "Intro,",
+			level:      1,
+			want:       "# Intro\nthen the rest",
+			wantCursor: 8,
+		},
+		{
+			name:       "line end",
+			value:      "first\nBody text. Closing thought.\nlast",
+			selected:   " Closing thought.",
+			level:      3,
+			want:       "first\nBody text.\n### Closing thought\nlast",
+			wantCursor: 36,
+		},
+		{
+			name:       "whole line",
+			value:      "Summary",
+			selected:   "Summary",
+			level:      2,
+			want:       "## Summary",
+			wantCursor: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newFocusedEditModel("")
+			start := strings.Index(tt.value, tt.selected)
+			selectEditorRange(m, tt.value, start, start+len(tt.selected))
+			m.outlineCursor = 3
+
+			m.headingShortcut(tt.level)
+			if got := m.editor.Value(); got != tt.want {
+				t.Fatalf("value = %q, want %q", got, tt.want)
+			}
+			if m.hasEditorSelectionAnchor() {
+				t.Fatal("expected selection to be cleared")
+			}
+			if m.outlineCursor != 0 {
+				t.Fatalf("outline cursor = %d, want reset", m.outlineCursor)
+			}
+			if got := m.currentEditorCursorOffset(); got != tt.wantCursor {
+				t.Fatalf("cursor = %d, want %d", got, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestPromoteSelectionToHeadingRejectsMultiLineAndCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      string
+		selected   string
+		wantStatus string
+	}{
+		{"multi line", "one two\nthree four", "two\nthree", "Select text on a single line to make a heading"},
+		{"inside fence", "```\ncode line\n```", "code", "Cannot make a heading inside a code block"},
+		{"fence line", "```go\nx := 1\n```", "go", "Cannot make a heading inside a code block"},
+		{"punctuation only", "a, b", ", ", "Selection has no text for a heading"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newFocusedEditModel("")
+			start := strings.Index(tt.value, tt.selected)
+			selectEditorRange(m, tt.value, start, start+len(tt.selected))
+
+			m.headingShortcut(2)
+			if got := m.editor.Value(); got != tt.value {
+				t.Fatalf("value changed to %q", got)
+			}
+			if m.status != tt.wantStatus {
+				t.Fatalf("status = %q, want %q", m.status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHeadingShortcutWithoutSelectionTogglesLine(t *testing.T) {
+	m := newFocusedEditModel("")
+	m.setEditorValueAndCursorOffset("hello world", 3)
+
+	m.headingShortcut(1)
+	if got := m.editor.Value(); got != "# hello world" {
+		t.Fatalf("value = %q, want toggled heading", got)
+	}
+}
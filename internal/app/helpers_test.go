@@ -0,0 +1,21 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+// stubNow replaces the package clock (appNow) with now for the rest of the
+// test.
+func stubNow(t *testing.T, now func() time.Time) {
+	t.Helper()
+	previous := appNow
+	appNow = now
+	t.Cleanup(func() { appNow = previous })
+}
+
+// withFixedNow pins the package clock to ts for the rest of the test.
+func withFixedNow(t *testing.T, ts time.Time) {
+	t.Helper()
+	stubNow(t, func() time.Time { return ts })
+}
@@ -0,0 +1,362 @@
+// import.go implements importing existing notes from outside the notes
+// directory (Alt+I, also offered by the getting-started panel).
+//
+// The user types a path (~ is expanded like notes_dir in config). A file is
+// copied into the selected folder; a directory is copied as a new folder of
+// the same name, keeping its subfolder layout. Only .md files are copied
+// unless Tab in the prompt switches to importing all files. Hidden folders
+// (.git, .obsidian, ...) are not descended. Importing from inside the notes
+// directory, or from a folder that contains it, is rejected so the copy can
+// never recurse into itself.
+//
+// The import is planned before anything is copied. Each target that already
+// exists, or whose name differs from an existing file only by case, is then
+// resolved in modeImportConflict: o overwrites it, r imports under a free
+// "(imported)" name, s skips it, and the shifted keys apply the choice to
+// every remaining conflict. Esc cancels the whole import.
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// importResult tallies the outcome of an import.
+type importResult struct {
+	imported    []string // created or overwritten file paths
+	createdDirs []string // folders created to hold them
+	skipped     int      // filtered files, skipped conflicts
+	overwrote   bool     // an existing file was replaced
+}
+
+// importResolution is the user's choice for a conflicting import target.
+type importResolution int
+
+const (
+	importUnresolved importResolution = iota
+	importOverwrite
+	importRename
+	importSkip
+)
+
+// importCopy is one file to copy. For conflicts, resolution records how the
+// existing target is handled.
+type importCopy struct {
+	src        string
+	target     string
+	resolution importResolution
+}
+
+// importPlan is a walked import waiting for its conflicts to be resolved.
+type importPlan struct {
+	src       string
+	dst       string
+	isDir     bool
+	copies    []importCopy // targets that do not exist yet
+	conflicts []importCopy // targets that exist, resolved in order
+	next      int          // index of the conflict being prompted
+	skipped   int          // files filtered out while walking
+}
+
+// startImport switches to import mode with the selected folder as the
+// destination.
+func (m *Model) startImport() {
+	m.mode = modeImport
+	m.showHelp = false
+	m.actionPath = m.selectedParentDir()
+	m.importAllFiles = false
+	m.importPlan = nil
+	m.input.Reset()
+	m.input.Placeholder = "Path to a folder or file (~ allowed)"
+	m.input.Focus()
+	m.status = "Import: Enter or Ctrl+S to import, Tab to toggle all files, Esc to cancel"
+}
+
+// handleImportKey processes keypresses while entering an import path. Tab
+// toggles between importing markdown files only and all files.
+func (m *Model) handleImportKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "tab" && !m.shouldIgnoreInput(msg) {
+		m.importAllFiles = !m.importAllFiles
+		m.status = "Import: " + m.importFilterLabel()
+		return m, nil
+	}
+	return m.handleInputModeKey(msg, m.saveImport, "Import cancelled")
+}
+
+// importFilterLabel describes which files the import copies.
+func (m *Model) importFilterLabel() string {
+	if m.importAllFiles {
+		return "all files"
+	}
+	return "markdown files only"
+}
+
+// saveImport validates the source path and plans the import. Without
+// conflicts the files are copied right away; otherwise the first conflict
+// is prompted.
+func (m *Model) saveImport() (tea.Model, tea.Cmd) {
+	src, err := config.NormalizeNotesDir(m.input.Value())
+	if err != nil {
+		m.status = "Import path is required"
+		return m, nil
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		m.status = "Import path does not exist: " + src
+		return m, nil
+	}
+	if isWithinRoot(m.notesDir, src) || isWithinRoot(src, m.notesDir) {
+		m.status = "Cannot import from inside the notes directory"
+		return m, nil
+	}
+	destDir := m.actionPath
+	if destDir == "" || !isWithinRoot(m.notesDir, destDir) {
+		destDir = m.notesDir
+	}
+	if !info.IsDir() && !m.importAllFiles && !hasSuffixCaseInsensitive(src, ".md") {
+		m.status = "Only markdown files can be imported (Tab to import all files)"
+		return m, nil
+	}
+
+	plan, err := planImport(src, filepath.Join(destDir, filepath.Base(src)), m.importAllFiles)
+	if err != nil {
+		m.setStatusError("Error importing notes", err, "from", src, "to", destDir)
+		m.mode = modeBrowse
+		return m, nil
+	}
+	plan.isDir = info.IsDir()
+	m.importPlan = &plan
+	return m.advanceImport()
+}
+
+// planImport walks src and sorts the files to copy to dst into new targets
+// and conflicts. A target clashing by case with an existing sibling is a
+// conflict on that sibling. Directory walks skip hidden folders and, unless allFiles is
+// set, non-markdown files.
+func planImport(src, dst string, allFiles bool) (importPlan, error) {
+	plan := importPlan{src: src, dst: dst}
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			if path != src && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || (!allFiles && !hasSuffixCaseInsensitive(d.Name(), ".md")) {
+			plan.skipped++
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := dst
+		if rel != "." {
+			target = filepath.Join(dst, rel)
+		}
+		op := importCopy{src: path, target: target}
+		if _, err := os.Lstat(target); err == nil {
+			plan.conflicts = append(plan.conflicts, op)
+		} else if name := caseCollision(target, ""); name != "" {
+			// A sibling differing only by case is the same file on
+			// case-insensitive volumes; resolve against its real name.
+			op.target = filepath.Join(filepath.Dir(target), name)
+			plan.conflicts = append(plan.conflicts, op)
+		} else {
+			plan.copies = append(plan.copies, op)
+		}
+		return nil
+	})
+	return plan, err
+}
+
+// advanceImport prompts the next unresolved conflict of the pending import,
+// or runs the import once every conflict is resolved.
+func (m *Model) advanceImport() (tea.Model, tea.Cmd) {
+	plan := m.importPlan
+	if plan == nil {
+		m.mode = modeBrowse
+		return m, nil
+	}
+	for plan.next < len(plan.conflicts) && plan.conflicts[plan.next].resolution != importUnresolved {
+		plan.next++
+	}
+	if plan.next < len(plan.conflicts) {
+		m.mode = modeImportConflict
+		m.status = fmt.Sprintf("Import conflict %d of %d: %s exists", plan.next+1, len(plan.conflicts), m.displayRelative(plan.conflicts[plan.next].target))
+		return m, nil
+	}
+	m.importPlan = nil
+	m.mode = modeBrowse
+	result, err := plan.run()
+	if err != nil {
+		m.setStatusError("Error importing notes", err, "from", plan.src, "to", plan.dst)
+	}
+	if len(result.imported) > 0 {
+		m.expandParentDirs(result.imported[0])
+		keep := plan.dst
+		if !plan.isDir {
+			keep = result.imported[0]
+		}
+		_ = m.applyMutationEffects(mutationEffects{
+			upsertPaths:      append(result.createdDirs, result.imported...),
+			clearRenderCache: result.overwrote,
+			refreshGit:       true,
+			refreshTree:      true,
+			rebuildKeepPath:  keep,
+		})
+	}
+	if err == nil {
+		m.status = importSummary(result)
+	}
+	return m, nil
+}
+
+// handleImportConflictKey resolves the prompted conflict: o overwrite, r
+// rename, s skip; O, R and S apply the choice to all remaining conflicts.
+// Esc cancels the import without copying anything.
+func (m *Model) handleImportConflictKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	plan := m.importPlan
+	if plan == nil {
+		m.mode = modeBrowse
+		return m, nil
+	}
+	key := msg.String()
+	var resolution importResolution
+	switch strings.ToLower(strings.TrimPrefix(key, "shift+")) {
+	case "o":
+		resolution = importOverwrite
+	case "r":
+		resolution = importRename
+	case "s":
+		resolution = importSkip
+	case "esc":
+		m.importPlan = nil
+		m.mode = modeBrowse
+		m.status = "Import cancelled"
+		return m, nil
+	default:
+		return m, nil
+	}
+	last := plan.next
+	if key != strings.ToLower(key) || strings.HasPrefix(key, "shift+") {
+		last = len(plan.conflicts) - 1
+	}
+	for i := plan.next; i <= last; i++ {
+		plan.conflicts[i].resolution = resolution
+	}
+	return m.advanceImport()
+}
+
+// run copies the planned files: new targets first, then the resolved
+// conflicts, so renamed copies pick names that are free after the rest of
+// the import. On error, the files copied so far are still reported.
+func (p *importPlan) run() (importResult, error) {
+	result := importResult{skipped: p.skipped}
+	for _, op := range append(append([]importCopy{}, p.copies...), p.conflicts...) {
+		target := op.target
+		switch op.resolution {
+		case importSkip:
+			result.skipped++
+			continue
+		case importRename:
+			target = nextImportPath(op.target)
+		case importOverwrite:
+			if info, err := os.Lstat(target); err == nil && info.IsDir() {
+				result.skipped++
+				continue
+			}
+		}
+		created := missingDirs(filepath.Dir(p.dst), filepath.Dir(target))
+		if err := os.MkdirAll(filepath.Dir(target), DirPermission); err != nil {
+			return result, err
+		}
+		result.createdDirs = append(result.createdDirs, created...)
+		if op.resolution == importOverwrite {
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return result, err
+			}
+			result.overwrote = true
+		}
+		if err := copyFileWithMode(op.src, target, FilePermission); err != nil {
+			return result, err
+		}
+		result.imported = append(result.imported, target)
+	}
+	return result, nil
+}
+
+// importMarkdownTree copies the .md files of src (a markdown file or a
+// directory) to dst, skipping targets that already exist.
+func importMarkdownTree(src, dst string) (importResult, error) {
+	plan, err := planImport(src, dst, false)
+	if err != nil {
+		return importResult{}, err
+	}
+	for i := range plan.conflicts {
+		plan.conflicts[i].resolution = importSkip
+	}
+	return plan.run()
+}
+
+// nextImportPath returns the first free sibling path for an imported file
+// whose name is taken: "x (imported).md", then "x (imported 2).md", and so on.
+func nextImportPath(path string) string {
+	dir := filepath.Dir(path)
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(filepath.Base(path), ext)
+	for n := 1; ; n++ {
+		suffix := " (imported)"
+		if n > 1 {
+			suffix = fmt.Sprintf(" (imported %d)", n)
+		}
+		candidate := filepath.Join(dir, base+suffix+ext)
+		if _, err := os.Lstat(candidate); os.IsNotExist(err) && caseCollision(candidate, "") == "" {
+			return candidate
+		}
+	}
+}
+
+// renderImportConflict draws the conflict prompt in the right pane.
+func (m *Model) renderImportConflict(width, height int) string {
+	plan := m.importPlan
+	if plan == nil || plan.next >= len(plan.conflicts) {
+		return ""
+	}
+	op := plan.conflicts[plan.next]
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("Import Conflict (%d of %d)", plan.next+1, len(plan.conflicts))),
+		"",
+		truncate(m.displayRelative(op.target), width),
+		"already exists.",
+		"",
+		mutedStyle.Render("o: overwrite it with " + truncate(filepath.Base(op.src), max(0, width-20))),
+		mutedStyle.Render("r: import as " + filepath.Base(nextImportPath(op.target))),
+		mutedStyle.Render("s: skip this file"),
+		mutedStyle.Render("O / R / S: same for all remaining conflicts"),
+		mutedStyle.Render("Esc: cancel the import"),
+	}
+	visible := min(height, len(lines))
+	return strings.Join(lines[:visible], "\n")
+}
+
+// importSummary formats the status line for a finished import.
+func importSummary(result importResult) string {
+	noun := "notes"
+	if len(result.imported) == 1 {
+		noun = "note"
+	}
+	return fmt.Sprintf("Imported %d %s, skipped %d", len(result.imported), noun, result.skipped)
+}
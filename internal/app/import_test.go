@@ -0,0 +1,212 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestImportMarkdownTreeCopiesNotesOnly(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "vault")
+	mustWriteFile(t, filepath.Join(src, "a.md"), "a\n")
+	mustWriteFile(t, filepath.Join(src, "sub", "b.md"), "b\n")
+	mustWriteFile(t, filepath.Join(src, "sub", "image.png"), "png")
+	mustWriteFile(t, filepath.Join(src, ".obsidian", "c.md"), "c\n")
+	dst := filepath.Join(t.TempDir(), "vault")
+	mustWriteFile(t, filepath.Join(dst, "a.md"), "existing\n")
+
+	result, err := importMarkdownTree(src, dst)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(result.imported) != 1 || result.imported[0] != filepath.Join(dst, "sub", "b.md") {
+		t.Fatalf("expected only sub/b.md imported, got %v", result.imported)
+	}
+	if result.skipped != 2 {
+		t.Fatalf("expected existing note and image skipped, got %d", result.skipped)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "a.md")); string(data) != "existing\n" {
+		t.Fatalf("expected existing note untouched, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".obsidian")); !os.IsNotExist(err) {
+		t.Fatalf("expected hidden folder not imported, stat err %v", err)
+	}
+}
+
+func TestSaveImportAddsNotesToTree(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(t.TempDir(), "old-notes")
+	mustWriteFile(t, filepath.Join(src, "idea.md"), "idea\n")
+
+	m := newTestCRUDModel(root)
+	m.startImport()
+	m.input.SetValue(src)
+	_, _ = m.saveImport()
+
+	imported := filepath.Join(root, "old-notes", "idea.md")
+	if m.mode != modeBrowse {
+		t.Fatalf("expected browse mode after import, got %v", m.mode)
+	}
+	if _, err := os.Stat(imported); err != nil {
+		t.Fatalf("expected imported note: %v", err)
+	}
+	assertTreeHasPath(t, m.items, imported)
+	if m.status != "Imported 1 note, skipped 0" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
+func TestSaveImportRejectsNotesDirectory(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "sub", "a.md"), "a\n")
+
+	m := newTestCRUDModel(root)
+	m.startImport()
+	m.input.SetValue(filepath.Join(root, "sub"))
+	_, _ = m.saveImport()
+
+	if m.mode != modeImport || !strings.Contains(m.status, "inside the notes directory") {
+		t.Fatalf("expected import rejected, got mode %v status %q", m.mode, m.status)
+	}
+}
+
+func TestImportConflictsPromptPerFile(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(t.TempDir(), "vault")
+	for _, name := range []string{"a.md", "b.md", "c.md", "d.md", "new.md"} {
+		mustWriteFile(t, filepath.Join(src, name), "imported "+name+"\n")
+	}
+	for _, name := range []string{"a.md", "b.md", "c.md", "d.md"} {
+		mustWriteFile(t, filepath.Join(root, "vault", name), "existing "+name+"\n")
+	}
+
+	m := newTestCRUDModel(root)
+	m.startImport()
+	m.actionPath = root
+	m.input.SetValue(src)
+	_, _ = m.saveImport()
+	if m.mode != modeImportConflict || !strings.Contains(m.status, "1 of 4") {
+		t.Fatalf("expected first conflict prompt, mode=%v status=%q", m.mode, m.status)
+	}
+	if _, err := os.Stat(filepath.Join(root, "vault", "new.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing copied before conflicts are resolved, err=%v", err)
+	}
+
+	press := func(key string) {
+		_, _ = m.handleImportConflictKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	press("o")
+	press("r")
+	press("S")
+	if m.mode != modeBrowse || m.status != "Imported 3 notes, skipped 2" {
+		t.Fatalf("unexpected result mode=%v status=%q", m.mode, m.status)
+	}
+	want := map[string]string{
+		"a.md":            "imported a.md\n",
+		"b.md":            "existing b.md\n",
+		"b (imported).md": "imported b.md\n",
+		"c.md":            "existing c.md\n",
+		"d.md":            "existing d.md\n",
+		"new.md":          "imported new.md\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(root, "vault", name))
+		if err != nil || string(data) != content {
+			t.Fatalf("%s: got %q (%v), want %q", name, data, err, content)
+		}
+	}
+	assertTreeHasPath(t, m.items, filepath.Join(root, "vault", "b (imported).md"))
+	if results := m.searchIndex.search("imported b"); len(results) == 0 {
+		t.Fatal("expected renamed import in the search index")
+	}
+}
+
+func TestImportTreatsCaseOnlyClashAsConflict(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(t.TempDir(), "vault")
+	mustWriteFile(t, filepath.Join(src, "notes.md"), "imported notes\n")
+	mustWriteFile(t, filepath.Join(src, "todo.md"), "imported todo\n")
+	mustWriteFile(t, filepath.Join(root, "vault", "Notes.md"), "existing notes\n")
+	mustWriteFile(t, filepath.Join(root, "vault", "TODO.md"), "existing todo\n")
+
+	m := newTestCRUDModel(root)
+	m.startImport()
+	m.actionPath = root
+	m.input.SetValue(src)
+	_, _ = m.saveImport()
+	if m.mode != modeImportConflict || !strings.Contains(m.status, "1 of 2") || !strings.Contains(m.status, "Notes.md") {
+		t.Fatalf("expected a conflict on the existing spelling, mode=%v status=%q", m.mode, m.status)
+	}
+
+	press := func(key string) {
+		_, _ = m.handleImportConflictKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	press("o")
+	press("r")
+	if m.mode != modeBrowse || m.status != "Imported 2 notes, skipped 0" {
+		t.Fatalf("unexpected result mode=%v status=%q", m.mode, m.status)
+	}
+	entries, err := os.ReadDir(filepath.Join(root, "vault"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if strings.Join(names, ",") != "Notes.md,TODO (imported).md,TODO.md" {
+		t.Fatalf("expected no case-only pairs, got %v", names)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "vault", "Notes.md")); string(data) != "imported notes\n" {
+		t.Fatalf("expected Notes.md overwritten, got %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "vault", "TODO (imported).md")); string(data) != "imported todo\n" {
+		t.Fatalf("expected renamed import, got %q", data)
+	}
+}
+
+func TestImportConflictEscCancelsWholeImport(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(t.TempDir(), "vault")
+	mustWriteFile(t, filepath.Join(src, "a.md"), "new\n")
+	mustWriteFile(t, filepath.Join(src, "b.md"), "b\n")
+	mustWriteFile(t, filepath.Join(root, "vault", "a.md"), "old\n")
+
+	m := newTestCRUDModel(root)
+	m.startImport()
+	m.actionPath = root
+	m.input.SetValue(src)
+	_, _ = m.saveImport()
+	_, _ = m.handleImportConflictKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeBrowse || m.status != "Import cancelled" || m.importPlan != nil {
+		t.Fatalf("expected import cancelled, mode=%v status=%q", m.mode, m.status)
+	}
+	if _, err := os.Stat(filepath.Join(root, "vault", "b.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected no files copied, err=%v", err)
+	}
+}
+
+func TestImportTabTogglesAllFiles(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(t.TempDir(), "vault")
+	mustWriteFile(t, filepath.Join(src, "a.md"), "a\n")
+	mustWriteFile(t, filepath.Join(src, "img", "pic.png"), "png")
+
+	m := newTestCRUDModel(root)
+	m.startImport()
+	_, _ = m.handleImportKey(tea.KeyMsg{Type: tea.KeyTab})
+	if !m.importAllFiles || !strings.Contains(m.status, "all files") {
+		t.Fatalf("expected all-files toggle, got %v %q", m.importAllFiles, m.status)
+	}
+	m.input.SetValue(src)
+	_, _ = m.saveImport()
+	if m.status != "Imported 2 notes, skipped 0" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	if _, err := os.Stat(filepath.Join(root, "vault", "img", "pic.png")); err != nil {
+		t.Fatalf("expected non-markdown file imported: %v", err)
+	}
+}
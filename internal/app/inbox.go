@@ -0,0 +1,400 @@
+// inbox.go implements the GTD-style inbox processing workflow (Shift+I).
+//
+// The inbox is a folder (inbox_dir, default "inbox") holding two kinds of
+// unprocessed items:
+//
+//   - notes directly inside the folder, processed by moving them to a
+//     destination folder;
+//   - bullet lines ("- text", "* text", "- [ ] text") in the capture file
+//     inbox/inbox.md, processed by converting each into its own note. The
+//     line is then marked "- [x]" so it is not offered again.
+//
+// Processing walks the items one at a time in modeInbox: the input is
+// prefilled with the last destination folder used, Enter applies the move or
+// conversion and advances, Tab skips, and Esc stops. Converted notes are
+// created like new notes (create_missing_dirs, frontmatter_timestamps). Moves
+// reuse the archive relocation bookkeeping, so expansion, pins, positions,
+// and the search index follow the note.
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// inboxItem is one unprocessed inbox entry.
+type inboxItem struct {
+	// path is the inbox note, or the capture file for line items.
+	path string
+	// line is the 0-based line index in the capture file, or -1 for notes.
+	line int
+	// text is the captured line without its bullet (line items only).
+	text string
+}
+
+func (item inboxItem) isLine() bool {
+	return item.line >= 0
+}
+
+// inboxResults counts what happened during one inbox walk.
+type inboxResults struct {
+	moved     int
+	converted int
+	skipped   int
+}
+
+// inboxPath returns the absolute inbox folder.
+func inboxPath(notesDir, inboxDir string) string {
+	dir := strings.TrimSpace(inboxDir)
+	if dir == "" {
+		dir = DefaultInboxDir
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(notesDir, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// collectInboxItems lists the inbox notes (by name) followed by the
+// unprocessed lines of the capture file (in file order).
+func collectInboxItems(dir string) ([]inboxItem, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var items []inboxItem
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.EqualFold(name, InboxFileName) || !hasSuffixCaseInsensitive(name, ".md") {
+			continue
+		}
+		items = append(items, inboxItem{path: filepath.Join(dir, name), line: -1})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return strings.ToLower(items[i].path) < strings.ToLower(items[j].path)
+	})
+
+	capture := filepath.Join(dir, InboxFileName)
+	content, err := os.ReadFile(capture)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return items, nil
+		}
+		return items, err
+	}
+	for i, line := range strings.Split(string(content), "\n") {
+		if text, ok := unprocessedInboxLine(line); ok {
+			items = append(items, inboxItem{path: capture, line: i, text: text})
+		}
+	}
+	return items, nil
+}
+
+// unprocessedInboxLine returns the text of a bullet or open task line.
+// Checked tasks ("- [x]") and anything that is not a bullet are skipped.
+func unprocessedInboxLine(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	var rest string
+	switch {
+	case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "):
+		rest = strings.TrimSpace(trimmed[2:])
+	default:
+		return "", false
+	}
+	lower := strings.ToLower(rest)
+	if strings.HasPrefix(lower, "[x]") {
+		return "", false
+	}
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, "[ ]"))
+	return rest, rest != ""
+}
+
+// markInboxLineProcessed rewrites line index as a checked task, keeping its
+// indentation, bullet, and line ending.
+func markInboxLineProcessed(content string, index int) (string, bool) {
+	lines := strings.Split(content, "\n")
+	if index < 0 || index >= len(lines) {
+		return content, false
+	}
+	line := lines[index]
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(trimmed)]
+	if len(trimmed) < 2 || (trimmed[0] != '-' && trimmed[0] != '*') {
+		return content, false
+	}
+	rest := strings.TrimLeft(trimmed[1:], " ")
+	rest = strings.TrimPrefix(rest, "[ ] ")
+	lines[index] = indent + trimmed[:1] + " [x] " + rest
+	return strings.Join(lines, "\n"), true
+}
+
+// inboxNoteName turns a captured line into a default note file name.
+func inboxNoteName(text string) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(strings.TrimRight(text, "\r")))
+	name = strings.Join(strings.Fields(name), " ")
+	if runes := []rune(name); len(runes) > 60 {
+		name = strings.TrimSpace(string(runes[:60]))
+	}
+	if name == "" {
+		name = "Inbox note"
+	}
+	return name
+}
+
+// startInbox collects inbox items and begins walking them.
+func (m *Model) startInbox() {
+	dir := inboxPath(m.notesDir, m.inboxDir)
+	if !isWithinRoot(m.notesDir, dir) {
+		m.status = "inbox_dir must be inside the notes directory"
+		return
+	}
+	items, err := collectInboxItems(dir)
+	if err != nil && !os.IsNotExist(err) {
+		m.setStatusError("Error reading inbox", err, "path", dir)
+		return
+	}
+	if len(items) == 0 {
+		m.status = "Inbox is empty"
+		return
+	}
+	m.inboxItems = items
+	m.inboxIndex = 0
+	m.inboxResults = inboxResults{}
+	m.mode = modeInbox
+	m.showHelp = false
+	m.input.Reset()
+	m.input.SetValue("")
+	m.showInboxItem()
+}
+
+// currentInboxItem returns the item being processed, if any.
+func (m *Model) currentInboxItem() (inboxItem, bool) {
+	if m.inboxIndex < 0 || m.inboxIndex >= len(m.inboxItems) {
+		return inboxItem{}, false
+	}
+	return m.inboxItems[m.inboxIndex], true
+}
+
+// showInboxItem prepares the input for the current item, keeping the
+// destination folder used for the previous item.
+func (m *Model) showInboxItem() {
+	item, ok := m.currentInboxItem()
+	if !ok {
+		m.finishInbox()
+		return
+	}
+	folder := m.inboxDestinationFolder()
+	m.input.Reset()
+	if item.isLine() {
+		m.input.Placeholder = "Note path (relative to notes root)"
+		m.input.SetValue(filepath.Join(folder, inboxNoteName(item.text)))
+	} else {
+		m.input.Placeholder = "Destination folder (relative to notes root)"
+		m.input.SetValue(folder)
+		m.expandParentDirs(item.path)
+		m.rebuildTreeKeep(item.path)
+	}
+	m.input.CursorEnd()
+	m.input.Focus()
+	m.status = fmt.Sprintf("Inbox %d/%d: Enter to apply, Tab to skip, Esc to stop", m.inboxIndex+1, len(m.inboxItems))
+}
+
+// inboxDestinationFolder returns the folder part of the current input, or
+// "" before the first item.
+func (m *Model) inboxDestinationFolder() string {
+	value := strings.TrimSpace(m.input.Value())
+	if value == "" {
+		return ""
+	}
+	if m.inboxIndex > 0 && m.inboxItems[m.inboxIndex-1].isLine() {
+		value = filepath.Dir(value)
+		if value == "." {
+			return ""
+		}
+	}
+	return value
+}
+
+// handleInboxKey processes keypresses while walking the inbox.
+func (m *Model) handleInboxKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	switch msg.String() {
+	case "ctrl+s", "enter":
+		m.applyInboxItem()
+		return m, nil
+	case "tab":
+		m.inboxResults.skipped++
+		m.advanceInbox()
+		return m, nil
+	case "esc":
+		m.finishInbox()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// applyInboxItem moves or converts the current item and advances. On
+// validation errors the walk stays on the item so the input can be fixed.
+func (m *Model) applyInboxItem() {
+	item, ok := m.currentInboxItem()
+	if !ok {
+		m.finishInbox()
+		return
+	}
+	var done bool
+	if item.isLine() {
+		done = m.convertInboxLine(item)
+	} else {
+		done = m.moveInboxNote(item)
+	}
+	if done {
+		m.advanceInbox()
+	}
+}
+
+// advanceInbox moves to the next item or ends the walk.
+func (m *Model) advanceInbox() {
+	m.inboxIndex++
+	m.showInboxItem()
+}
+
+// moveInboxNote moves an inbox note into the destination folder.
+func (m *Model) moveInboxNote(item inboxItem) bool {
+	destDir, err := m.resolveMoveDestination(m.input.Value())
+	if err != nil {
+		m.status = err.Error()
+		return false
+	}
+	newPath := filepath.Join(destDir, filepath.Base(item.path))
+	if newPath == item.path {
+		m.status = "Choose a folder outside the inbox"
+		return false
+	}
+	if _, err := os.Stat(newPath); err == nil {
+		m.status = "Destination already exists: " + m.displayRelative(newPath)
+		return false
+	}
+	if !m.relocatePath(item.path, newPath, "Error moving inbox note") {
+		return false
+	}
+	m.expanded[destDir] = true
+	m.rebuildTreeKeep(newPath)
+	m.inboxResults.moved++
+	return true
+}
+
+// convertInboxLine writes the captured line as a new note and marks it
+// processed in the capture file.
+func (m *Model) convertInboxLine(item inboxItem) bool {
+	name := strings.TrimSpace(m.input.Value())
+	if name == "" {
+		m.status = "Note name is required"
+		return false
+	}
+	if !hasSuffixCaseInsensitive(name, ".md") {
+		name += ".md"
+	}
+	path := filepath.Join(m.notesDir, strings.TrimPrefix(filepath.Clean("/"+name), "/"))
+	if !isWithinRoot(m.notesDir, path) {
+		m.status = "Invalid note name"
+		return false
+	}
+	if _, err := os.Stat(path); err == nil {
+		m.status = "Note already exists: " + m.displayRelative(path)
+		return false
+	}
+	if status, collides := firstCaseCollision("", append(missingDirs(m.notesDir, filepath.Dir(path)), path)...); collides {
+		m.status = status
+		return false
+	}
+
+	captured, err := os.ReadFile(item.path)
+	if err != nil {
+		m.setStatusError("Error reading inbox", err, "path", item.path)
+		return false
+	}
+	marked, ok := markInboxLineProcessed(string(captured), item.line)
+	if !ok {
+		m.status = "Inbox line changed on disk; skipping"
+		m.inboxResults.skipped++
+		return true
+	}
+
+	var createdDirs []string
+	if m.createMissingDirs {
+		createdDirs = missingDirs(m.notesDir, filepath.Dir(path))
+		if err := os.MkdirAll(filepath.Dir(path), DirPermission); err != nil {
+			m.setStatusError("Error creating folder", err, "path", filepath.Dir(path))
+			return false
+		}
+	}
+	title := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	content := "# " + title + "\n\n" + strings.TrimRight(item.text, "\r") + "\n"
+	if m.frontmatterTimestamps {
+		content = stampFrontmatterTime(content, "created")
+	}
+	if err := os.WriteFile(path, []byte(normalizeNoteContent(content)), FilePermission); err != nil {
+		m.setStatusError("Error creating note", err, "path", path)
+		return false
+	}
+	if err := os.WriteFile(item.path, []byte(marked), FilePermission); err != nil {
+		m.setStatusError("Error updating inbox", err, "path", item.path)
+		return false
+	}
+
+	for _, dir := range createdDirs {
+		m.expanded[dir] = true
+	}
+	m.expanded[filepath.Dir(path)] = true
+	m.invalidateTreeMetadataPath(item.path)
+	delete(m.renderCache, item.path)
+	m.applyMutationEffects(mutationEffects{
+		upsertPaths:     append(createdDirs, path, item.path),
+		refreshGit:      true,
+		rebuildKeepPath: path,
+	})
+	m.inboxResults.converted++
+	return true
+}
+
+// finishInbox ends the walk and reports what was done.
+func (m *Model) finishInbox() {
+	results := m.inboxResults
+	total := len(m.inboxItems)
+	m.mode = modeBrowse
+	m.input.Blur()
+	m.inboxItems = nil
+	m.inboxIndex = 0
+	m.inboxResults = inboxResults{}
+	processed := results.moved + results.converted + results.skipped
+	m.status = fmt.Sprintf("Inbox: %d moved, %d converted, %d skipped", results.moved, results.converted, results.skipped)
+	if processed < total {
+		m.status += fmt.Sprintf(", %d left", total-processed)
+	}
+}
+
+// inboxModeMeta returns the prompt, location, and helper lines for the
+// right pane while walking the inbox.
+func (m *Model) inboxModeMeta() (string, string, string) {
+	item, _ := m.currentInboxItem()
+	counter := fmt.Sprintf("Inbox %d/%d", m.inboxIndex+1, len(m.inboxItems))
+	if item.isLine() {
+		return counter + ": convert line to note", "Line: " + item.text, "Enter note path to create it and mark the line done. Tab to skip, Esc to stop."
+	}
+	return counter + ": move note", "Note: " + m.displayRelative(item.path), "Enter destination folder to move it. Tab to skip, Esc to stop."
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCollectInboxItemsListsNotesThenOpenLines(t *testing.T) {
+	root := t.TempDir()
+	inbox := filepath.Join(root, DefaultInboxDir)
+	mustWriteFile(t, filepath.Join(inbox, "b.md"), "b\n")
+	mustWriteFile(t, filepath.Join(inbox, "A.md"), "a\n")
+	mustWriteFile(t, filepath.Join(inbox, "image.png"), "")
+	mustWriteFile(t, filepath.Join(inbox, "sub", "nested.md"), "n\n")
+	mustWriteFile(t, filepath.Join(inbox, InboxFileName), "# Inbox\n- call bob\n- [x] done\n* [ ] buy milk\nplain text\n  - indented idea\n-\n")
+
+	items, err := collectInboxItems(inbox)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	want := []inboxItem{
+		{path: filepath.Join(inbox, "A.md"), line: -1},
+		{path: filepath.Join(inbox, "b.md"), line: -1},
+		{path: filepath.Join(inbox, InboxFileName), line: 1, text: "call bob"},
+		{path: filepath.Join(inbox, InboxFileName), line: 3, text: "buy milk"},
+		{path: filepath.Join(inbox, InboxFileName), line: 5, text: "indented idea"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("expected %d items, got %#v", len(want), items)
+	}
+	for i := range want {
+		if items[i] != want[i] {
+			t.Fatalf("item %d: want %#v, got %#v", i, want[i], items[i])
+		}
+	}
+}
+
+func TestMarkInboxLineProcessed(t *testing.T) {
+	content := "- a\r\n  * [ ] b\r\nplain\r\n"
+	got, ok := markInboxLineProcessed(content, 1)
+	if !ok || got != "- a\r\n  * [x] b\r\nplain\r\n" {
+		t.Fatalf("unexpected mark: %q %v", got, ok)
+	}
+	if _, ok := markInboxLineProcessed(content, 2); ok {
+		t.Fatal("expected non-bullet line to be refused")
+	}
+	if text, ok := unprocessedInboxLine("* [x] b"); ok {
+		t.Fatalf("expected marked line to be processed, got %q", text)
+	}
+}
+
+func TestInboxWalkMovesConvertsAndSkips(t *testing.T) {
+	root := t.TempDir()
+	inbox := filepath.Join(root, DefaultInboxDir)
+	projects := filepath.Join(root, "projects")
+	note := filepath.Join(inbox, "idea.md")
+	capture := filepath.Join(inbox, InboxFileName)
+	mustWriteFile(t, note, "# Idea\n")
+	mustWriteFile(t, filepath.Join(inbox, "later.md"), "# Later\n")
+	mustWriteFile(t, capture, "- Call Bob: re budget\n- skip me\n")
+	if err := os.MkdirAll(projects, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.createMissingDirs = true
+	m.pinnedPaths[note] = true
+	m.startInbox()
+	if m.mode != modeInbox || len(m.inboxItems) != 4 {
+		t.Fatalf("expected inbox walk over 4 items, got mode %v items %d", m.mode, len(m.inboxItems))
+	}
+
+	// Item 1: move idea.md into projects.
+	m.input.SetValue("projects")
+	m.handleInboxKey(tea.KeyMsg{Type: tea.KeyEnter})
+	moved := filepath.Join(projects, "idea.md")
+	if _, err := os.Stat(moved); err != nil {
+		t.Fatalf("expected note moved to %q: %v", moved, err)
+	}
+	if !m.pinnedPaths[moved] || m.pinnedPaths[note] {
+		t.Fatal("expected pin to follow the moved note")
+	}
+	got := relPathSet(root, m.searchIndex.search("idea"))
+	expectContains(t, got, filepath.Join("projects", "idea.md"))
+	expectNotContains(t, got, filepath.Join(DefaultInboxDir, "idea.md"))
+
+	// Item 2: skip later.md; the destination carries over.
+	if m.input.Value() != "projects" {
+		t.Fatalf("expected destination to carry over, got %q", m.input.Value())
+	}
+	m.handleInboxKey(tea.KeyMsg{Type: tea.KeyTab})
+
+	// Item 3: convert the first captured line, name prefilled from the text.
+	if want := filepath.Join("projects", "Call Bob re budget"); m.input.Value() != want {
+		t.Fatalf("expected prefilled note path %q, got %q", want, m.input.Value())
+	}
+	m.handleInboxKey(tea.KeyMsg{Type: tea.KeyEnter})
+	converted := filepath.Join(projects, "Call Bob re budget.md")
+	body, err := os.ReadFile(converted)
+	if err != nil {
+		t.Fatalf("expected converted note: %v", err)
+	}
+	if string(body) != "# Call Bob re budget\n\nCall Bob: re budget\n" {
+		t.Fatalf("unexpected converted content %q", body)
+	}
+	captured, _ := os.ReadFile(capture)
+	if string(captured) != "- [x] Call Bob: re budget\n- skip me\n" {
+		t.Fatalf("expected line marked processed, got %q", captured)
+	}
+
+	// Item 4: stop early.
+	m.handleInboxKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeBrowse {
+		t.Fatalf("expected browse mode after Esc, got %v", m.mode)
+	}
+	if want := "Inbox: 1 moved, 1 converted, 1 skipped, 1 left"; m.status != want {
+		t.Fatalf("expected %q, got %q", want, m.status)
+	}
+	if _, err := os.Stat(filepath.Join(inbox, "later.md")); err != nil {
+		t.Fatalf("expected skipped note to stay in inbox: %v", err)
+	}
+}
+
+func TestInboxMoveErrorKeepsItem(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, DefaultInboxDir, "idea.md"), "# Idea\n")
+	m := newTestCRUDModel(root)
+	m.startInbox()
+
+	m.input.SetValue("missing")
+	m.handleInboxKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeInbox || m.inboxIndex != 0 {
+		t.Fatalf("expected to stay on the item, got mode %v index %d", m.mode, m.inboxIndex)
+	}
+	if m.status != "Destination folder not found" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
+func TestStartInboxEmpty(t *testing.T) {
+	m := newTestCRUDModel(t.TempDir())
+	m.mode = modeBrowse
+	m.startInbox()
+	if m.mode != modeBrowse || m.status != "Inbox is empty" {
+		t.Fatalf("expected empty inbox status, got mode %v status %q", m.mode, m.status)
+	}
+}
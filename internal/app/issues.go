@@ -0,0 +1,402 @@
+// issues.go implements the current-note issue registry.
+//
+// Several features can find problems in the note being viewed or edited
+// (unresolved wiki links, merge-conflict hunks, and in the future lint
+// findings or dangling footnotes). Rather than each feature growing its own
+// navigation, they register a noteIssueProducer in noteIssueProducers. The
+// registry merges every producer's findings in document order so F8 /
+// Shift+F8 can cycle through them and the issues popup (`!`) can list them
+// grouped by source.
+//
+// Adding a producer:
+//
+//  1. Implement noteIssueProducer. Issues receives the note path, its raw
+//     markdown (the live editor buffer in edit mode), and helpers such as
+//     wiki-link resolution. It must be side-effect free and cheap enough to
+//     run on every edit, because results are recomputed whenever the content
+//     changes.
+//  2. Report 1-based Line and 0-based rune Column for each issue; the
+//     registry fills in the absolute Offset used for editor cursor jumps.
+//  3. Optionally set Anchor to text that appears in the rendered preview so
+//     preview scrolling can land on the right line after Glamour rendering.
+//  4. Append the producer to noteIssueProducers. Its position there is the
+//     group order in the issues popup.
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// noteIssueSeverity ranks how serious an issue is.
+type noteIssueSeverity int
+
+const (
+	issueSeverityInfo noteIssueSeverity = iota
+	issueSeverityWarning
+	issueSeverityError
+)
+
+// String returns the lowercase label shown in the status line and popup.
+func (s noteIssueSeverity) String() string {
+	switch s {
+	case issueSeverityError:
+		return "error"
+	case issueSeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// noteIssue is a single problem found in the current note.
+type noteIssue struct {
+	// Line is the 1-based source line of the issue.
+	Line int
+	// Column is the 0-based rune column within Line.
+	Column int
+	// Offset is the absolute rune offset of Line/Column in the note content.
+	// It is filled in by collectNoteIssues.
+	Offset int
+	// Severity ranks the issue.
+	Severity noteIssueSeverity
+	// Message describes the issue for the status line and popup.
+	Message string
+	// Source is the producer name, used to group issues in the popup.
+	Source string
+	// Anchor is optional rendered-preview text used to locate the issue
+	// when scrolling the preview.
+	Anchor string
+}
+
+// issueContext is the input handed to every noteIssueProducer.
+type issueContext struct {
+	path    string
+	content string
+	// resolveWikiLink reports whether a wiki-link label resolves to a note.
+	// It is nil when no index is available, in which case producers should
+	// treat links as resolved rather than report false positives.
+	resolveWikiLink func(label string) bool
+}
+
+// noteIssueProducer is implemented by every feature that reports problems in
+// the current note. See the file comment for the registration contract.
+type noteIssueProducer interface {
+	// Source returns a short, stable name used to group issues.
+	Source() string
+	// Issues returns the producer's findings for the given note.
+	Issues(ctx issueContext) []noteIssue
+}
+
+// noteIssueProducers is the issue registry, in popup group order.
+var noteIssueProducers = []noteIssueProducer{
+	conflictMarkerIssueProducer{},
+	wikiLinkIssueProducer{},
+}
+
+// collectNoteIssues runs every producer and merges their findings in document
+// order. Issues at the same position keep registry order.
+func collectNoteIssues(ctx issueContext, producers []noteIssueProducer) []noteIssue {
+	lineStarts := []int{0}
+	offset := 0
+	for _, line := range strings.Split(ctx.content, "\n") {
+		offset += len([]rune(line)) + 1
+		lineStarts = append(lineStarts, offset)
+	}
+
+	var issues []noteIssue
+	for _, producer := range producers {
+		for _, issue := range producer.Issues(ctx) {
+			issue.Source = producer.Source()
+			line := clamp(issue.Line, 1, len(lineStarts)-1)
+			issue.Offset = lineStarts[line-1] + max(0, issue.Column)
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Offset < issues[j].Offset
+	})
+	return issues
+}
+
+// groupNoteIssues returns issues ordered by producer (registry order) and then
+// by document position, as listed in the issues popup.
+func groupNoteIssues(issues []noteIssue, producers []noteIssueProducer) []noteIssue {
+	rank := make(map[string]int, len(producers))
+	for i, producer := range producers {
+		rank[producer.Source()] = i
+	}
+	grouped := append([]noteIssue(nil), issues...)
+	sort.SliceStable(grouped, func(i, j int) bool {
+		return rank[grouped[i].Source] < rank[grouped[j].Source]
+	})
+	return grouped
+}
+
+// wikiLinkIssueProducer reports [[links]] that do not resolve to any note.
+type wikiLinkIssueProducer struct{}
+
+func (wikiLinkIssueProducer) Source() string { return "wiki-links" }
+
+func (wikiLinkIssueProducer) Issues(ctx issueContext) []noteIssue {
+	if ctx.resolveWikiLink == nil {
+		return nil
+	}
+	var issues []noteIssue
+	inFence := false
+	for i, line := range strings.Split(ctx.content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, match := range wikiLinkPattern.FindAllStringSubmatchIndex(line, -1) {
+			label := strings.TrimSpace(line[match[2]:match[3]])
+			if label == "" || ctx.resolveWikiLink(label) {
+				continue
+			}
+			issues = append(issues, noteIssue{
+				Line:     i + 1,
+				Column:   len([]rune(line[:match[0]])),
+				Severity: issueSeverityWarning,
+				Message:  "Unresolved wiki link [[" + label + "]]",
+				Anchor:   label,
+			})
+		}
+	}
+	return issues
+}
+
+// conflictMarkerIssueProducer reports git merge-conflict hunks left in a note.
+type conflictMarkerIssueProducer struct{}
+
+func (conflictMarkerIssueProducer) Source() string { return "conflicts" }
+
+func (conflictMarkerIssueProducer) Issues(ctx issueContext) []noteIssue {
+	var issues []noteIssue
+	start := 0
+	for i, line := range strings.Split(ctx.content, "\n") {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<<"):
+			start = i + 1
+		case strings.HasPrefix(line, ">>>>>>>") && start > 0:
+			issues = append(issues, noteIssue{
+				Line:     start,
+				Severity: issueSeverityError,
+				Message:  fmt.Sprintf("Merge conflict (lines %d-%d)", start, i+1),
+				Anchor:   "<<<<<<<",
+			})
+			start = 0
+		}
+	}
+	if start > 0 {
+		issues = append(issues, noteIssue{
+			Line:     start,
+			Severity: issueSeverityError,
+			Message:  "Unterminated merge conflict marker",
+			Anchor:   "<<<<<<<",
+		})
+	}
+	return issues
+}
+
+// issueSubject returns the note path and content issues are computed for: the
+// live editor buffer in edit mode, otherwise the previewed note.
+func (m *Model) issueSubject() (string, string) {
+	if m.mode == modeEditNote {
+		return m.currentFile, m.editor.Value()
+	}
+	return m.currentFile, m.currentNoteContent
+}
+
+// currentNoteIssues returns the cached issues for the current note,
+// recomputing them when the note or its content has changed since the last
+// call (after an edit, a save, or a re-render of a different note).
+func (m *Model) currentNoteIssues() []noteIssue {
+	path, content := m.issueSubject()
+	if path == m.issuesPath && content == m.issuesContent && m.issuesValid {
+		return m.issues
+	}
+	m.issues = collectNoteIssues(issueContext{
+		path:            path,
+		content:         content,
+		resolveWikiLink: m.wikiLinkResolver(),
+	}, noteIssueProducers)
+	m.issuesPath = path
+	m.issuesContent = content
+	m.issuesValid = true
+	m.issueCursor = -1
+	return m.issues
+}
+
+// invalidateNoteIssues forces the next currentNoteIssues call to recompute,
+// e.g. after the set of notes changed and wiki links may now resolve.
+func (m *Model) invalidateNoteIssues() {
+	m.issuesValid = false
+}
+
+// wikiLinkResolver returns a resolver backed by the search index, or nil when
+// the index cannot be built.
+func (m *Model) wikiLinkResolver() func(string) bool {
+	if m.searchIndex == nil {
+		m.searchIndex = newSearchIndex(m.notesDir)
+	}
+	if err := m.searchIndex.ensureBuilt(); err != nil {
+		return nil
+	}
+	return func(label string) bool {
+		_, ok := m.searchIndex.resolveWikiTarget(label)
+		return ok
+	}
+}
+
+// jumpToNoteIssue moves to the next (delta > 0) or previous (delta < 0)
+// issue in document order, wrapping around at either end. In edit mode the
+// step is relative to the editor cursor; in browse mode it is relative to the
+// last visited issue.
+func (m *Model) jumpToNoteIssue(delta int) {
+	if m.currentFile == "" {
+		m.status = "Select a note first"
+		return
+	}
+	issues := m.currentNoteIssues()
+	if len(issues) == 0 {
+		m.status = "No issues in current note"
+		return
+	}
+
+	next := 0
+	if m.mode == modeEditNote {
+		cursor := m.currentEditorCursorOffset()
+		if delta > 0 {
+			next = 0
+			for next < len(issues) && issues[next].Offset <= cursor {
+				next++
+			}
+			if next == len(issues) {
+				next = 0
+			}
+		} else {
+			next = len(issues) - 1
+			for next >= 0 && issues[next].Offset >= cursor {
+				next--
+			}
+			if next < 0 {
+				next = len(issues) - 1
+			}
+		}
+	} else if m.issueCursor < 0 {
+		if delta < 0 {
+			next = len(issues) - 1
+		}
+	} else {
+		next = (m.issueCursor + delta + len(issues)) % len(issues)
+	}
+	m.showNoteIssue(next)
+}
+
+// showNoteIssue moves the editor cursor or preview to issues[index] and
+// describes it in the status line.
+func (m *Model) showNoteIssue(index int) {
+	issue := m.issues[index]
+	m.issueCursor = index
+	if m.mode == modeEditNote {
+		m.setEditorValueAndCursorOffset(m.editor.Value(), issue.Offset)
+		m.clearEditorSelection()
+	} else {
+		m.scrollPreviewToSource(issue.Anchor, issue.Line)
+	}
+	m.status = fmt.Sprintf("Issue %d/%d [%s] line %d: %s", index+1, len(m.issues), issue.Source, issue.Line, issue.Message)
+}
+
+// openIssuesPopup lists the current note's issues grouped by source.
+func (m *Model) openIssuesPopup() {
+	if m.currentFile == "" {
+		m.status = "Select a note first"
+		return
+	}
+	issues := m.currentNoteIssues()
+	if len(issues) == 0 {
+		m.status = "No issues in current note"
+		return
+	}
+	m.openOverlay(overlayIssues)
+	m.issuesPopup = groupNoteIssues(issues, noteIssueProducers)
+	m.issuesPopupCursor = 0
+	m.status = "Issues: Enter to jump, Esc to close"
+}
+
+// handleIssuesPopupKey routes key presses while the issues popup is visible.
+func (m *Model) handleIssuesPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.issuesPopupCursor, len(m.issuesPopup))
+	if !handled {
+		return m, nil
+	}
+	if closePressed {
+		m.closeOverlay()
+		m.status = "Issues closed"
+		return m, nil
+	}
+	if len(m.issuesPopup) == 0 {
+		return m, nil
+	}
+	m.issuesPopupCursor = next
+	if selectPressed {
+		selected := m.issuesPopup[m.issuesPopupCursor]
+		m.closeOverlay()
+		for i, issue := range m.currentNoteIssues() {
+			if issue == selected {
+				m.showNoteIssue(i)
+				break
+			}
+		}
+	}
+	return m, nil
+}
+
+// renderIssuesPopup draws the issues popup with a heading per source.
+func (m *Model) renderIssuesPopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	lines := []string{
+		titleStyle.Render("Issues"),
+		"",
+	}
+	limit := max(0, innerHeight-len(lines)-1)
+	// Headings and issues share one row list so the window below scrolls
+	// them together and keeps the highlighted issue on screen.
+	var rows []string
+	cursorRow := 0
+	source := ""
+	for i, issue := range m.issuesPopup {
+		if issue.Source != source {
+			source = issue.Source
+			rows = append(rows, mutedStyle.Render(truncate(source, innerWidth)))
+		}
+		line := truncate(fmt.Sprintf("  %d: %s (%s)", issue.Line, issue.Message, issue.Severity), innerWidth)
+		if i == m.issuesPopupCursor {
+			line = selectedStyle.Render(line)
+			cursorRow = len(rows)
+		}
+		rows = append(rows, line)
+	}
+	start := 0
+	if limit > 0 {
+		start = max(0, cursorRow-limit+1)
+	}
+	lines = append(lines, rows[min(start, len(rows)):min(start+limit, len(rows))]...)
+	if len(m.issuesPopup) == 0 {
+		lines = append(lines, mutedStyle.Render("No issues"))
+	}
+	lines = append(lines, mutedStyle.Render("Enter: jump  Esc: close"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCollectNoteIssuesMergesProducersInDocumentOrder(t *testing.T) {
+	content := "# Note\n" +
+		"See [[Missing]] and [[Known]].\n" +
+		"```\n" +
+		"[[InFence]]\n" +
+		"```\n" +
+		"<<<<<<< HEAD\n" +
+		"mine\n" +
+		"=======\n" +
+		"theirs\n" +
+		">>>>>>> branch\n" +
+		"tail [[Other]]"
+	ctx := issueContext{
+		content: content,
+		resolveWikiLink: func(label string) bool {
+			return label == "Known"
+		},
+	}
+
+	issues := collectNoteIssues(ctx, noteIssueProducers)
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %d: %+v", len(issues), issues)
+	}
+	want := []struct {
+		line   int
+		source string
+		msg    string
+	}{
+		{2, "wiki-links", "Unresolved wiki link [[Missing]]"},
+		{6, "conflicts", "Merge conflict (lines 6-10)"},
+		{11, "wiki-links", "Unresolved wiki link [[Other]]"},
+	}
+	for i, w := range want {
+		if issues[i].Line != w.line || issues[i].Source != w.source || issues[i].Message != w.msg {
+			t.Fatalf("issue %d: expected %+v, got %+v", i, w, issues[i])
+		}
+	}
+	if got := []rune(content)[issues[0].Offset:]; !strings.HasPrefix(string(got), "[[Missing]]") {
+		t.Fatalf("expected offset to point at the link, got %q", string(got[:10]))
+	}
+
+	grouped := groupNoteIssues(issues, noteIssueProducers)
+	if grouped[0].Source != "conflicts" || grouped[1].Line != 2 || grouped[2].Line != 11 {
+		t.Fatalf("unexpected grouping: %+v", grouped)
+	}
+}
+
+func TestConflictMarkerIssueProducerReportsUnterminatedHunk(t *testing.T) {
+	issues := conflictMarkerIssueProducer{}.Issues(issueContext{content: "a\n<<<<<<< HEAD\nb"})
+	if len(issues) != 1 || issues[0].Line != 2 || issues[0].Severity != issueSeverityError {
+		t.Fatalf("unexpected issues: %+v", issues)
+	}
+	if issues[0].Message != "Unterminated merge conflict marker" {
+		t.Fatalf("unexpected message %q", issues[0].Message)
+	}
+}
+
+func TestJumpToNoteIssueInEditModeMovesCursorAndWraps(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "note.md")
+	mustWriteFile(t, path, "")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeEditNote
+	m.currentFile = path
+	m.editor.SetValue("[[One]]\nplain\n[[Two]]")
+	m.setEditorValueAndCursorOffset(m.editor.Value(), 0)
+
+	m.jumpToNoteIssue(1)
+	if got := m.currentEditorCursorOffset(); got != 14 {
+		t.Fatalf("expected cursor at second link (14), got %d", got)
+	}
+	if !strings.HasPrefix(m.status, "Issue 2/2 [wiki-links] line 3:") {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+
+	m.jumpToNoteIssue(1)
+	if got := m.currentEditorCursorOffset(); got != 0 {
+		t.Fatalf("expected wrap to first link, got %d", got)
+	}
+
+	m.jumpToNoteIssue(-1)
+	if got := m.currentEditorCursorOffset(); got != 14 {
+		t.Fatalf("expected wrap back to second link, got %d", got)
+	}
+
+	// Editing the buffer invalidates the cached issues.
+	m.editor.SetValue("no links")
+	m.jumpToNoteIssue(1)
+	if m.status != "No issues in current note" {
+		t.Fatalf("expected issues to be recomputed after edit, got %q", m.status)
+	}
+}
+
+func TestIssuesPopupScrollsToCursor(t *testing.T) {
+	m := newTestCRUDModel(t.TempDir())
+	for i := 1; i <= 30; i++ {
+		m.issuesPopup = append(m.issuesPopup, noteIssue{Line: i, Source: "wiki-links", Message: fmt.Sprintf("issue-%02d", i)})
+	}
+	m.issuesPopupCursor = 29
+
+	view := m.renderIssuesPopup(60, 12)
+	if !strings.Contains(view, "issue-30") {
+		t.Fatalf("expected the highlighted issue on screen:\n%s", view)
+	}
+	if strings.Contains(view, "issue-01") || strings.Contains(view, "wiki-links") {
+		t.Fatalf("expected the top of the list scrolled away:\n%s", view)
+	}
+
+	m.issuesPopupCursor = 0
+	view = m.renderIssuesPopup(60, 12)
+	if !strings.Contains(view, "wiki-links") || !strings.Contains(view, "issue-01") || strings.Contains(view, "issue-30") {
+		t.Fatalf("expected the list from the top:\n%s", view)
+	}
+}
@@ -0,0 +1,198 @@
+// journal.go implements the daily-note commands.
+//
+// Shift+J resolves today's entry at <notes_dir>/<journal_dir>/YYYY-MM-DD.md,
+// creating it when missing, then selects it in the tree and opens it in the
+// editor. journal_dir is relative to the notes directory (an absolute path is
+// allowed if it lies inside it) and defaults to "journal". A new entry is
+// seeded from daily.md in the templates directory when that file exists,
+// otherwise from journal_template. Both support {{date}} (YYYY-MM-DD) and
+// {{weekday}} (e.g. Monday) placeholders.
+//
+// { and } step to the previous and next existing entry in the journal folder,
+// starting from the open entry (or today when another note is open), and
+// show it in the preview for review.
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openDailyNote jumps to today's journal entry, creating it first if needed,
+// and starts editing it.
+func (m *Model) openDailyNote() (tea.Model, tea.Cmd) {
+	now := appNow()
+	path := dailyNotePath(m.notesDir, m.journalDir, now)
+	if !isWithinRoot(m.notesDir, path) {
+		m.status = "journal_dir must be inside the notes directory"
+		return m, nil
+	}
+
+	created := false
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(path), DirPermission); err != nil {
+			m.setStatusError("Error creating journal folder", err, "path", filepath.Dir(path))
+			return m, nil
+		}
+		content := expandJournalTemplate(m.dailyTemplate(), now)
+		if m.frontmatterTimestamps {
+			content = stampFrontmatterTime(content, "created")
+		}
+		if err := os.WriteFile(path, []byte(normalizeNoteContent(content)), FilePermission); err != nil {
+			m.setStatusError("Error creating journal entry", err, "path", path)
+			return m, nil
+		}
+		created = true
+	} else if err != nil {
+		m.setStatusError("Error reading journal entry", err, "path", path)
+		return m, nil
+	}
+
+	cmd := m.revealJournalEntry(path, created)
+	m.startEditNote()
+	if created {
+		m.status = "Created journal entry: " + m.displayRelative(path)
+	} else {
+		m.status = "Opened journal entry: " + m.displayRelative(path)
+	}
+	return m, cmd
+}
+
+// openAdjacentDailyNote shows the nearest existing journal entry before
+// (step < 0) or after (step > 0) the open entry, or today when the open note
+// is not a journal entry.
+func (m *Model) openAdjacentDailyNote(step int) (tea.Model, tea.Cmd) {
+	today := dailyNotePath(m.notesDir, m.journalDir, appNow())
+	if !isWithinRoot(m.notesDir, today) {
+		m.status = "journal_dir must be inside the notes directory"
+		return m, nil
+	}
+	dir := filepath.Dir(today)
+	anchor := filepath.Base(today)
+	if filepath.Dir(m.currentFile) == dir && isDailyNoteName(filepath.Base(m.currentFile)) {
+		anchor = filepath.Base(m.currentFile)
+	}
+
+	entries, err := journalEntries(dir)
+	if err != nil {
+		m.setStatusError("Error reading journal folder", err, "path", dir)
+		return m, nil
+	}
+	i := sort.SearchStrings(entries, anchor)
+	if step > 0 && i < len(entries) && entries[i] == anchor {
+		i++
+	} else if step < 0 {
+		i--
+	}
+	if i < 0 || i >= len(entries) {
+		if step < 0 {
+			m.status = "No earlier journal entry"
+		} else {
+			m.status = "No later journal entry"
+		}
+		return m, nil
+	}
+
+	path := filepath.Join(dir, entries[i])
+	cmd := m.revealJournalEntry(path, false)
+	m.status = "Journal entry: " + m.displayRelative(path)
+	if day, err := time.Parse("2006-01-02", strings.TrimSuffix(entries[i], ".md")); err == nil {
+		m.status += " (" + day.Weekday().String() + ")"
+	}
+	return m, cmd
+}
+
+// revealJournalEntry selects a journal entry in the tree, clearing any tree
+// filter so it is visible, and makes it the current file. created adds a
+// just-written entry to the tree and search index.
+func (m *Model) revealJournalEntry(path string, created bool) tea.Cmd {
+	m.treeFilterQuery = ""
+	m.treeFilterRestorePath = ""
+	m.tagFilter = ""
+	m.expandParentDirs(path)
+	effects := mutationEffects{rebuildKeepPath: path}
+	if created {
+		m.invalidateTreeMetadataPath(path)
+		effects.upsertPaths = []string{filepath.Dir(path), path}
+		effects.refreshGit = true
+	}
+	m.applyMutationEffects(effects)
+	return m.setCurrentFile(path)
+}
+
+// dailyTemplate returns the template for new journal entries: daily.md from
+// the templates directory when it can be read, else journal_template.
+func (m *Model) dailyTemplate() string {
+	if m.templatesDir != "" {
+		path := filepath.Join(m.templatesDir, DailyTemplateFileName)
+		content, err := os.ReadFile(path)
+		if err == nil {
+			return string(content)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			appLog.Warn("read daily template", "path", path, "error", err)
+		}
+	}
+	return m.journalTemplate
+}
+
+// journalEntries returns the names of the dated entries in dir, oldest
+// first. A missing folder has no entries.
+func journalEntries(dir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range dirEntries {
+		if !entry.IsDir() && isDailyNoteName(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	// YYYY-MM-DD names sort chronologically.
+	sort.Strings(names)
+	return names, nil
+}
+
+// isDailyNoteName reports whether name is a YYYY-MM-DD.md journal entry.
+func isDailyNoteName(name string) bool {
+	date, ok := strings.CutSuffix(name, ".md")
+	if !ok {
+		return false
+	}
+	_, err := time.Parse("2006-01-02", date)
+	return err == nil
+}
+
+// dailyNotePath returns the journal entry path for the given day.
+func dailyNotePath(notesDir, journalDir string, day time.Time) string {
+	dir := strings.TrimSpace(journalDir)
+	if dir == "" {
+		dir = DefaultJournalDir
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(notesDir, dir)
+	}
+	return filepath.Join(filepath.Clean(dir), day.Format("2006-01-02")+".md")
+}
+
+// expandJournalTemplate fills the {{date}} and {{weekday}} placeholders of a
+// journal template, falling back to DefaultJournalTemplate when empty.
+func expandJournalTemplate(template string, day time.Time) string {
+	if strings.TrimSpace(template) == "" {
+		template = DefaultJournalTemplate
+	}
+	return strings.NewReplacer(
+		"{{date}}", day.Format("2006-01-02"),
+		"{{weekday}}", day.Weekday().String(),
+	).Replace(template)
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDailyNotePathAndTemplate(t *testing.T) {
+	day := time.Date(2026, 2, 9, 8, 0, 0, 0, time.UTC)
+	if got, want := dailyNotePath("/notes", "", day), filepath.Join("/notes", "journal", "2026-02-09.md"); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got, want := dailyNotePath("/notes", "log/daily", day), filepath.Join("/notes", "log", "daily", "2026-02-09.md"); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got := expandJournalTemplate("", day); got != "# 2026-02-09\n" {
+		t.Fatalf("unexpected default template expansion %q", got)
+	}
+	if got := expandJournalTemplate("# {{weekday}} {{date}}\n\n## Tasks\n", day); got != "# Monday 2026-02-09\n\n## Tasks\n" {
+		t.Fatalf("unexpected template expansion %q", got)
+	}
+}
+
+func TestOpenDailyNoteCreatesThenReopens(t *testing.T) {
+	root := t.TempDir()
+	withFixedNow(t, time.Date(2026, 2, 9, 8, 0, 0, 0, time.UTC))
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.journalTemplate = "# {{date}}\n\nmood:\n"
+	m.openDailyNote()
+
+	path := filepath.Join(root, "journal", "2026-02-09.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read journal entry: %v", err)
+	}
+	if want := "# 2026-02-09\n\nmood:\n"; string(data) != want {
+		t.Fatalf("unexpected seed content.\nwant: %q\ngot:  %q", want, string(data))
+	}
+	if m.mode != modeEditNote || m.currentFile != path {
+		t.Fatalf("expected editing %q, got mode %v file %q", path, m.mode, m.currentFile)
+	}
+	if m.selectedPath() != path {
+		t.Fatalf("expected tree selection on journal entry, got %q", m.selectedPath())
+	}
+	if m.status != "Created journal entry: "+filepath.Join("journal", "2026-02-09.md") {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	assertSearchHasQuery(t, m.searchIndex, "2026-02-09", true)
+
+	mustWriteFile(t, path, "# 2026-02-09\n\nalready written\n")
+	m.mode = modeBrowse
+	m.openDailyNote()
+	if got := m.editor.Value(); got != "# 2026-02-09\n\nalready written\n" {
+		t.Fatalf("expected existing entry to be opened untouched, got %q", got)
+	}
+	if m.status != "Opened journal entry: "+filepath.Join("journal", "2026-02-09.md") {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
+func TestOpenDailyNoteRejectsJournalDirOutsideRoot(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.journalDir = "../elsewhere"
+	m.openDailyNote()
+	if m.status != "journal_dir must be inside the notes directory" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
+func TestOpenDailyNotePrefersDailyTemplateFile(t *testing.T) {
+	root := t.TempDir()
+	withFixedNow(t, time.Date(2026, 2, 9, 8, 0, 0, 0, time.UTC))
+	templates := filepath.Join(t.TempDir(), "templates")
+	mustWriteFile(t, filepath.Join(templates, DailyTemplateFileName), "# {{weekday}}, {{date}}\n\n## Log\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.templatesDir = templates
+	m.journalTemplate = "# ignored\n"
+	m.openDailyNote()
+
+	data, err := os.ReadFile(filepath.Join(root, "journal", "2026-02-09.md"))
+	if err != nil {
+		t.Fatalf("read journal entry: %v", err)
+	}
+	if want := "# Monday, 2026-02-09\n\n## Log\n"; string(data) != want {
+		t.Fatalf("expected daily.md seed.\nwant: %q\ngot:  %q", want, string(data))
+	}
+}
+
+func TestOpenAdjacentDailyNoteStepsBetweenEntries(t *testing.T) {
+	root := t.TempDir()
+	withFixedNow(t, time.Date(2026, 2, 9, 8, 0, 0, 0, time.UTC))
+	journal := filepath.Join(root, "journal")
+	for _, name := range []string{"2026-02-02.md", "2026-02-06.md", "2026-02-12.md", "notes.md"} {
+		mustWriteFile(t, filepath.Join(journal, name), "# "+name+"\n")
+	}
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.currentFile = filepath.Join(root, "Welcome.md")
+
+	// Today (2026-02-09) has no entry; stepping back finds the nearest one.
+	m.openAdjacentDailyNote(-1)
+	if want := filepath.Join(journal, "2026-02-06.md"); m.currentFile != want || m.selectedPath() != want {
+		t.Fatalf("expected %q selected, got current %q selected %q", want, m.currentFile, m.selectedPath())
+	}
+	if m.status != "Journal entry: "+filepath.Join("journal", "2026-02-06.md")+" (Friday)" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	m.openAdjacentDailyNote(-1)
+	m.openAdjacentDailyNote(-1)
+	if m.currentFile != filepath.Join(journal, "2026-02-02.md") || m.status != "No earlier journal entry" {
+		t.Fatalf("expected to stop at the oldest entry, got %q (%q)", m.currentFile, m.status)
+	}
+
+	m.openAdjacentDailyNote(1)
+	m.openAdjacentDailyNote(1)
+	if m.currentFile != filepath.Join(journal, "2026-02-12.md") {
+		t.Fatalf("expected the newest entry, got %q", m.currentFile)
+	}
+	m.openAdjacentDailyNote(1)
+	if m.status != "No later journal entry" || m.mode != modeBrowse {
+		t.Fatalf("expected to stay in browse at the newest entry, got %q mode %v", m.status, m.mode)
+	}
+}
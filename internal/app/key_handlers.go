@@ -2,20 +2,51 @@ package app
 
 import (
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // handleBrowseKey routes key presses in browse mode (not searching).
 func (m *Model) handleBrowseKey(key string) (tea.Model, tea.Cmd) {
+	if m.describeKeyPending {
+		m.describeKey(key)
+		return m, nil
+	}
 	if m.showHelp {
 		return m.handleHelpKey(key)
 	}
 
-	action := m.actionForKey(key)
+	if key == "esc" && m.treeFilterQuery != "" {
+		m.clearTreeFilter()
+		return m, nil
+	}
+	if key == "esc" && m.tagFilter != "" {
+		m.clearTagFilter()
+		return m, nil
+	}
+
+	return m.runBrowseAction(m.actionForKey(key))
+}
+
+// runBrowseAction runs a browse action. Key presses reach it through
+// handleBrowseKey and the command palette calls it directly.
+func (m *Model) runBrowseAction(action string) (tea.Model, tea.Cmd) {
+	if what, ok := interlockedActions[action]; ok && m.rejectWhileLocked(what) {
+		return m, nil
+	}
+	if what, ok := pendingConflictActions[action]; ok && m.rejectWhilePending(m.selectedPath(), what) {
+		return m, nil
+	}
+	if what, ok := pendingGitActions[action]; ok && m.rejectWhileAnyPending(what) {
+		return m, nil
+	}
 	switch action {
-	case actionSearchHint:
-		m.status = "Use Ctrl+P for search popup"
+	case actionTreeFilter:
+		m.startTreeFilter()
+		return m, nil
+	case actionTagFilter:
+		m.openTagFilterPopup()
 		return m, nil
 	case actionCursorUp:
 		return m.handleCursorUp()
@@ -25,14 +56,32 @@ func (m *Model) handleBrowseKey(key string) (tea.Model, tea.Cmd) {
 		return m.handleJumpTop()
 	case actionJumpBottom:
 		return m.handleJumpBottom()
+	case actionJumpFirstChild:
+		return m.handleJumpChild(true)
+	case actionJumpLastChild:
+		return m.handleJumpChild(false)
 	case actionExpandToggle:
+		if m.unlockSelectedNote() {
+			return m, nil
+		}
 		m.toggleExpand(true)
 		return m, nil
 	case actionCollapse:
 		m.toggleExpand(false)
 		return m, nil
+	case actionExpandAll:
+		m.setAllExpanded(true)
+		return m, nil
+	case actionCollapseAll:
+		m.setAllExpanded(false)
+		return m, nil
 	case actionQuit:
-		return m, tea.Quit
+		return m.requestQuit()
+	case actionFocus:
+		return m, m.toggleFocusSession()
+	case actionFocusCancel:
+		m.cancelFocusSession()
+		return m, nil
 	case actionHelp:
 		return m.toggleHelp()
 	case actionSearch:
@@ -55,9 +104,21 @@ func (m *Model) handleBrowseKey(key string) (tea.Model, tea.Cmd) {
 		return m, nil
 	case actionEditNote:
 		return m.startEditNote()
+	case actionDailyNote:
+		return m.openDailyNote()
+	case actionDailyPrev:
+		return m.openAdjacentDailyNote(-1)
+	case actionDailyNext:
+		return m.openAdjacentDailyNote(1)
 	case actionSort:
 		m.cycleSortMode()
 		return m, nil
+	case actionSortReverse:
+		m.reverseSortDirection()
+		return m, nil
+	case actionSortFolder:
+		m.toggleFolderSortOverride()
+		return m, nil
 	case actionPreviewScrollPageUp:
 		return m.scrollActivePreviewBy(-m.previewPageStep())
 	case actionPreviewScrollPageDown:
@@ -69,9 +130,54 @@ func (m *Model) handleBrowseKey(key string) (tea.Model, tea.Cmd) {
 	case actionPin:
 		m.togglePinnedSelection()
 		return m, nil
+	case actionTreeSizes:
+		m.toggleFileSizes()
+		return m, nil
+	case actionNoteAge:
+		m.toggleNoteAgeColors()
+		return m, nil
+	case actionArchive:
+		m.toggleArchiveSelected()
+		return m, nil
+	case actionToggleFolder:
+		m.toggleSelectedFolder()
+		return m, nil
+	case actionShowArchived:
+		m.toggleShowArchived()
+		return m, nil
+	case actionNoteStats:
+		m.openNoteStatsPopup()
+		return m, nil
+	case actionMetadataStrip:
+		m.toggleMetadataStrip()
+		return m, nil
+	case actionImport:
+		m.startImport()
+		return m, nil
+	case actionGitInit:
+		return m.initGitRepo()
+	case actionGitDiff:
+		m.openGitDiffPopup()
+		return m, nil
+	case actionGitLog:
+		m.openGitLogPopup()
+		return m, nil
+	case actionTutorial:
+		return m.openTutorial()
+	case actionInbox:
+		m.startInbox()
+		return m, nil
+	case actionEditTags:
+		m.startEditTagsSelected()
+	case actionEditFrontmatter:
+		m.startFrontmatterFormSelected()
+		return m, nil
 	case actionDelete:
 		m.deleteSelected()
 		return m, nil
+	case actionTrash:
+		m.openTrashPopup()
+		return m, nil
 	case actionCopyContent:
 		m.copyCurrentNoteContentToClipboard()
 		return m, nil
@@ -86,24 +192,68 @@ func (m *Model) handleBrowseKey(key string) (tea.Model, tea.Cmd) {
 	case actionMove:
 		m.startMoveSelected()
 		return m, nil
+	case actionDuplicate:
+		m.startDuplicateSelected()
+		return m, nil
 	case actionGitCommit:
 		return m.handleGitCommitStart()
 	case actionGitPull:
 		return m.handleGitPull()
 	case actionGitPush:
 		return m.handleGitPush()
+	case actionGitPanel:
+		m.openGitPanel()
+		return m, nil
 	case actionExport:
 		m.openExportPopup()
 		return m, nil
+	case actionHeadingCase:
+		m.openHeadingCasePopup()
+		return m, nil
+	case actionAgenda:
+		m.openAgendaPopup()
+		return m, nil
+	case actionKeymap:
+		m.openKeymapPopup()
+		return m, nil
+	case actionSavedViews:
+		m.openSavedViewsPopup()
+		return m, nil
+	case actionMaintenance:
+		m.openMaintenancePopup()
+		return m, nil
+	case actionTagBrowser:
+		m.openTagBrowserPopup()
+		return m, nil
+	case actionEncryptToggle:
+		return m.startToggleNoteEncryption()
+	case actionDescribeKey:
+		m.startDescribeKey()
+		return m, nil
 	case actionWikiLinks:
 		m.openWikiLinksPopup()
 		return m, nil
+	case actionMetadata:
+		m.openMetadataPopup()
+		return m, nil
+	case actionIssueNext:
+		m.jumpToNoteIssue(1)
+		return m, nil
+	case actionIssuePrev:
+		m.jumpToNoteIssue(-1)
+		return m, nil
+	case actionIssues:
+		m.openIssuesPopup()
+		return m, nil
 	case actionSplitToggle:
 		m.toggleSplitMode()
 		return m, nil
 	case actionSplitFocus:
 		m.toggleSplitFocus()
 		return m, nil
+	case actionCommandPalette:
+		m.openCommandPalette()
+		return m, nil
 	}
 	return m, nil
 }
@@ -144,23 +294,31 @@ func (m *Model) maxHelpViewportOffset() int {
 
 // handleSearchKey routes key presses while the search popup is active.
 func (m *Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	key := msg.String()
-
 	if m.shouldIgnoreInput(msg) {
 		return m, nil
 	}
 
-	switch key {
-	case "esc":
+	switch m.popupAction(msg) {
+	case actionPopupClose:
 		m.closeSearchPopup()
 		m.status = "Search cancelled"
 		return m, nil
-	case "up", "k", "ctrl+p":
+	case actionPopupUp:
 		return m.moveSearchCursor(-1)
-	case "down", "j", "ctrl+n":
+	case actionPopupDown:
 		return m.moveSearchCursor(1)
-	case "enter":
+	case actionPopupPageUp:
+		return m.moveSearchCursor(-PopupPageRows)
+	case actionPopupPageDown:
+		return m.moveSearchCursor(PopupPageRows)
+	case actionPopupSelect:
 		return m.selectSearchResult()
+	case popupActionSaveView:
+		m.startSaveSearchView()
+		return m, nil
+	case popupActionToggleLastTag:
+		m.toggleSearchTag()
+		return m, nil
 	}
 
 	// Handle text input for search query
@@ -207,8 +365,66 @@ func (m *Model) handleJumpBottom() (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// handleJumpChild jumps to the first or last direct child of the selected
+// folder when it is expanded. For a file or collapsed folder it jumps within
+// the folder that contains the selection instead.
+func (m *Model) handleJumpChild(first bool) (tea.Model, tea.Cmd) {
+	if m.cursor < 0 || m.cursor >= len(m.items) {
+		return m, nil
+	}
+	parent := m.cursor
+	if item := m.items[m.cursor]; !item.isDir || !m.expanded[item.path] {
+		parent = treeParentIndex(m.items, m.cursor)
+	}
+	start, end, ok := treeChildRange(m.items, parent)
+	if !ok {
+		m.status = "Folder is empty"
+		return m, nil
+	}
+	if first {
+		m.cursor = start
+	} else {
+		m.cursor = end
+	}
+	m.adjustTreeOffset()
+	cmd := m.maybeShowSelectedFile()
+	return m, cmd
+}
+
+// treeParentIndex returns the index of the folder row containing items[i],
+// or -1 for top-level items.
+func treeParentIndex(items []treeItem, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if items[j].depth < items[i].depth {
+			return j
+		}
+	}
+	return -1
+}
+
+// treeChildRange returns the indexes of the first and last direct children
+// of the folder row at parent, or of the top-level items when parent is -1.
+// ok is false when the folder has no visible children.
+func treeChildRange(items []treeItem, parent int) (start, end int, ok bool) {
+	depth := 0
+	if parent >= 0 {
+		depth = items[parent].depth + 1
+	}
+	start, end = -1, -1
+	for j := parent + 1; j < len(items) && items[j].depth >= depth; j++ {
+		if items[j].depth == depth {
+			if start < 0 {
+				start = j
+			}
+			end = j
+		}
+	}
+	return start, end, start >= 0
+}
+
 // handleRefresh rebuilds the tree and search index.
 func (m *Model) handleRefresh() (tea.Model, tea.Cmd) {
+	start := time.Now()
 	m.rememberCurrentNotePosition()
 	cmd := m.applyMutationEffects(mutationEffects{
 		saveState:        true,
@@ -219,6 +435,9 @@ func (m *Model) handleRefresh() (tea.Model, tea.Cmd) {
 	})
 	m.invalidateTreeMetadataCache()
 	m.status = "Refreshed"
+	if elapsed := time.Since(start); m.isSlowOp(elapsed) {
+		m.reportSlowOp(slowOpReport{op: slowOpRefresh, elapsed: elapsed, items: len(m.items), itemsNoun: "tree rows"})
+	}
 	return m, cmd
 }
 
@@ -25,8 +25,12 @@ import (
 // ---------------------------------------------------------------------------
 
 const (
-	// actionSearchHint shows a browse-mode hint to use Ctrl+P search.
-	actionSearchHint = "search.hint"
+	// actionTreeFilter starts narrowing the tree by name/title as you type.
+	actionTreeFilter = "tree.filter"
+
+	// actionTagFilter opens the tag picker that limits the tree to notes
+	// carrying one tag.
+	actionTagFilter = "tree.filter.tag"
 
 	// actionCursorUp moves the tree selection up by one item.
 	actionCursorUp = "tree.cursor.up"
@@ -40,15 +44,38 @@ const (
 	// actionJumpBottom moves selection to the last visible tree item.
 	actionJumpBottom = "tree.jump.bottom"
 
+	// actionJumpFirstChild moves selection to the first direct child of the
+	// selected expanded folder (or the first sibling of any other item).
+	actionJumpFirstChild = "tree.jump.first_child"
+
+	// actionJumpLastChild moves selection to the last direct child of the
+	// selected expanded folder (or the last sibling of any other item).
+	actionJumpLastChild = "tree.jump.last_child"
+
 	// actionExpandToggle toggles expansion for the selected directory.
 	actionExpandToggle = "tree.expand.toggle"
 
 	// actionCollapse collapses the selected directory.
 	actionCollapse = "tree.collapse"
 
+	// actionExpandAll expands every directory in the tree.
+	actionExpandAll = "tree.expand.all"
+
+	// actionCollapseAll collapses every directory except the root.
+	actionCollapseAll = "tree.collapse.all"
+
 	// actionSearch opens the Ctrl+P full-text search popup.
 	actionSearch = "search.open"
 
+	// actionSavedViews opens the saved search views popup.
+	actionSavedViews = "search.views"
+	// actionMaintenance opens the workspace maintenance popup (state
+	// export/import).
+	actionMaintenance = "workspace.maintenance"
+
+	// actionTagBrowser opens the popup listing every tag with its note count.
+	actionTagBrowser = "search.tags"
+
 	// actionRecent opens the recent-files quick-jump popup (Ctrl+O).
 	actionRecent = "recent.open"
 
@@ -65,6 +92,19 @@ const (
 	// actionNewFolder starts the new-folder creation flow.
 	actionNewFolder = "folder.new"
 
+	// actionDailyNote opens (creating if needed) today's journal entry.
+	actionDailyNote = "journal.today"
+
+	// actionDailyPrev shows the previous existing journal entry.
+	actionDailyPrev = "journal.prev"
+
+	// actionDailyNext shows the next existing journal entry.
+	actionDailyNext = "journal.next"
+
+	// actionAgenda opens the agenda of notes dated today, this week, or
+	// next week.
+	actionAgenda = "journal.agenda"
+
 	// actionEditNote enters edit mode for the currently selected note.
 	actionEditNote = "note.edit"
 
@@ -72,6 +112,13 @@ const (
 	// size → created → name …).
 	actionSort = "tree.sort.cycle"
 
+	// actionSortReverse flips the direction of the current tree sort.
+	actionSortReverse = "tree.sort.reverse"
+
+	// actionSortFolder toggles a per-folder sort override on the selected
+	// folder (or the folder containing the selected note).
+	actionSortFolder = "tree.sort.folder"
+
 	// actionPreviewScrollPageUp scrolls the active preview pane up by one
 	// viewport page.
 	actionPreviewScrollPageUp = "preview.scroll.page_up"
@@ -92,6 +139,72 @@ const (
 	// Pinned items float to the top of their parent folder regardless of sort.
 	actionPin = "tree.pin.toggle"
 
+	// actionTreeSizes toggles the right-aligned file-size column for
+	// markdown files in the tree.
+	actionTreeSizes = "tree.sizes.toggle"
+
+	// actionNoteAge toggles coloring note names in the tree by how long
+	// ago they were modified.
+	actionNoteAge = "tree.age.toggle"
+
+	// actionArchive moves the selected item into the archive folder, or
+	// restores it to where it came from when it is already archived.
+	actionArchive = "tree.archive.toggle"
+
+	// actionToggleFolder moves the selected note between the two
+	// toggle_folders (e.g. active and done).
+	actionToggleFolder = "tree.folder.toggle"
+
+	// actionShowArchived shows or hides the archive folder in the tree.
+	actionShowArchived = "tree.archive.show"
+
+	// actionNoteStats opens the detailed word/character stats popup for
+	// the current note.
+	actionNoteStats = "note.stats"
+
+	// actionMetadataStrip toggles the frontmatter summary strip under the
+	// preview header.
+	actionMetadataStrip = "preview.metadata.strip"
+
+	// actionImport copies markdown notes from a folder or file outside the
+	// workspace into the selected folder.
+	actionImport = "workspace.import"
+
+	// actionGitDiff shows the git diff of the current note.
+	actionGitDiff = "git.diff"
+
+	// actionGitLog shows the commit history of the current note.
+	actionGitLog = "git.log"
+
+	// actionFocus starts a focus session (or the offered break), or pauses
+	// and resumes the running one.
+	actionFocus = "focus.toggle"
+
+	// actionFocusCancel cancels the running focus session or skips the
+	// offered break.
+	actionFocusCancel = "focus.cancel"
+
+	// actionGitInit initializes a git repository in the notes directory.
+	actionGitInit = "git.init"
+
+	// actionTutorial opens the welcome note, recreating it if needed.
+	actionTutorial = "help.tutorial"
+
+	// actionInbox walks the inbox folder, moving notes and converting
+	// captured lines into notes one at a time.
+	actionInbox = "inbox.process"
+
+	// actionEditTags opens the tag editor for the selected note, rewriting
+	// only the frontmatter tags key on save.
+	actionEditTags = "note.tags.edit"
+
+	// actionEditFrontmatter opens the structured frontmatter form for the
+	// selected note.
+	actionEditFrontmatter = "note.frontmatter.edit"
+
+	// actionTrash opens the trash popup for restoring deleted items.
+	actionTrash = "trash.open"
+
 	// actionDelete initiates deletion of the selected item (prompts for
 	// confirmation before actually removing).
 	actionDelete = "item.delete"
@@ -115,6 +228,10 @@ const (
 	// a destination folder path.
 	actionMove = "item.move"
 
+	// actionDuplicate copies the selected note or folder next to the
+	// original, prompting for the copy's name.
+	actionDuplicate = "item.duplicate"
+
 	// actionGitCommit starts the git commit flow (git add -A && git commit).
 	// Only available when the notes directory is inside a git repository.
 	actionGitCommit = "git.commit"
@@ -125,13 +242,35 @@ const (
 	// actionGitPush runs git push in the notes directory.
 	actionGitPush = "git.push"
 
+	// actionGitPanel opens the git panel popup listing branch state and
+	// changed files, with commit / pull / push / refresh action rows.
+	actionGitPanel = "git.panel"
+
 	// actionExport opens the export popup for the current note (HTML / PDF).
 	actionExport = "note.export"
 
+	// actionHeadingCase opens the popup that converts all headings in the
+	// current note to title case or sentence case.
+	actionHeadingCase = "note.headings.case"
+
 	// actionWikiLinks opens the wiki-links popup showing all [[...]] links
 	// found in the current note and their resolution status.
 	actionWikiLinks = "wiki.links.open"
 
+	// actionMetadata opens a popup listing the current note's parsed
+	// frontmatter fields.
+	actionMetadata = "note.metadata.open"
+
+	// actionIssueNext jumps to the next issue (unresolved wiki link, merge
+	// conflict, ...) in the current note.
+	actionIssueNext = "issues.next"
+
+	// actionIssuePrev jumps to the previous issue in the current note.
+	actionIssuePrev = "issues.prev"
+
+	// actionIssues opens the popup listing all current-note issues.
+	actionIssues = "issues.open"
+
 	// actionSplitToggle enables or disables split-pane mode, which shows two
 	// notes side by side.
 	actionSplitToggle = "split.toggle"
@@ -140,6 +279,21 @@ const (
 	// secondary split panes.
 	actionSplitFocus = "split.focus.toggle"
 
+	// actionEncryptToggle encrypts the selected note, or decrypts it when it
+	// is already encrypted.
+	actionEncryptToggle = "note.encrypt.toggle"
+
+	// actionKeymap opens the keybindings popup for remapping actions.
+	actionKeymap = "keys.remap"
+
+	// actionDescribeKey reports the action bound to the next key pressed
+	// instead of running it.
+	actionDescribeKey = "keys.describe"
+
+	// actionCommandPalette opens the popup listing every action with its
+	// keys, filterable by typing (command_palette.go).
+	actionCommandPalette = "command.palette"
+
 	// actionHelp toggles the in-app keyboard shortcut reference panel.
 	actionHelp = "help.toggle"
 
@@ -162,13 +316,18 @@ const (
 //   - Special keys: "enter", "esc", "tab", "up", "down", "left", "right"
 //   - Single characters: "n", "f", "e", "?", etc.
 var defaultActionKeys = map[string][]string{
-	actionSearchHint:            {"/"},
+	actionTreeFilter:            {"/"},
+	actionTagFilter:             {"alt+t"},
 	actionCursorUp:              {"up", "k"},
 	actionCursorDown:            {"down", "j", "ctrl+n"},
 	actionJumpTop:               {"g"},
 	actionJumpBottom:            {"shift+g"},
+	actionJumpFirstChild:        {"["},
+	actionJumpLastChild:         {"]"},
 	actionExpandToggle:          {"enter", "right", "l"},
 	actionCollapse:              {"left", "h"},
+	actionExpandAll:             {"="},
+	actionCollapseAll:           {"-"},
 	actionSearch:                {"ctrl+p"},
 	actionRecent:                {"ctrl+o"},
 	actionOutline:               {"o"},
@@ -176,25 +335,63 @@ var defaultActionKeys = map[string][]string{
 	actionNewNote:               {"n"},
 	actionNewFolder:             {"f"},
 	actionEditNote:              {"e"},
+	actionDailyNote:             {"shift+j"},
+	actionDailyPrev:             {"{"},
+	actionDailyNext:             {"}"},
+	actionAgenda:                {"shift+c"},
 	actionSort:                  {"s"},
+	actionSortReverse:           {"shift+s"},
+	actionSortFolder:            {"alt+s"},
 	actionPreviewScrollPageUp:   {"pgup"},
 	actionPreviewScrollPageDown: {"pgdown"},
 	actionPreviewScrollHalfUp:   {"ctrl+u"},
 	actionPreviewScrollHalfDown: {"ctrl+d"},
 	actionPin:                   {"t"},
+	actionTreeSizes:             {"b"},
+	actionNoteAge:               {"shift+b"},
+	actionArchive:               {"shift+a"},
+	actionShowArchived:          {"a"},
+	actionToggleFolder:          {"alt+m"},
+	actionInbox:                 {"shift+i"},
+	actionEditTags:              {"#"},
+	actionEditFrontmatter:       {"shift+e"},
 	actionDelete:                {"d"},
+	actionTrash:                 {"ctrl+t"},
 	actionCopyContent:           {"y"},
 	actionCopyPath:              {"shift+y"},
 	actionRename:                {"r"},
 	actionRefresh:               {"ctrl+r", "shift+r"},
 	actionMove:                  {"m"},
+	actionDuplicate:             {"shift+d"},
 	actionGitCommit:             {"c"},
 	actionGitPull:               {"p"},
 	actionGitPush:               {"shift+p"},
+	actionGitPanel:              {"ctrl+g"},
+	actionGitDiff:               {"v"},
+	actionGitLog:                {"shift+v"},
 	actionExport:                {"x"},
+	actionHeadingCase:           {"shift+h"},
 	actionWikiLinks:             {"shift+l"},
+	actionMetadata:              {"i"},
+	actionMetadataStrip:         {"shift+m"},
+	actionNoteStats:             {"w"},
+	actionImport:                {"alt+i"},
+	actionGitInit:               {"alt+g"},
+	actionTutorial:              {"f1"},
+	actionFocus:                 {"shift+f"},
+	actionFocusCancel:           {"alt+f"},
+	actionIssueNext:             {"f8"},
+	actionIssuePrev:             {"f20"},
+	actionIssues:                {"!"},
 	actionSplitToggle:           {"z"},
 	actionSplitFocus:            {"tab"},
+	actionEncryptToggle:         {"alt+e"},
+	actionKeymap:                {"alt+k"},
+	actionSavedViews:            {"alt+v"},
+	actionMaintenance:           {"alt+w"},
+	actionTagBrowser:            {"alt+b"},
+	actionDescribeKey:           {"alt+d"},
+	actionCommandPalette:        {":"},
 	actionHelp:                  {"?"},
 	actionQuit:                  {"q", "ctrl+c"},
 }
@@ -217,7 +414,7 @@ var defaultActionKeys = map[string][]string{
 //
 // Any unknown action names in user overrides are logged as warnings and
 // ignored. Overrides replace an action's full default key set with the
-// configured key. Key conflicts (two actions mapped to the same key) are also
+// configured key or keys. Key conflicts (two actions mapped to the same key) are also
 // logged as warnings; the first action to claim a key wins.
 func (m *Model) loadKeybindings(cfg config.Config) {
 	// Start with a fresh copy of the factory defaults.
@@ -225,16 +422,19 @@ func (m *Model) loadKeybindings(cfg config.Config) {
 	for action, keys := range defaultActionKeys {
 		m.keyForAction[action] = append([]string(nil), keys...)
 	}
+	for action, keys := range defaultPopupKeys {
+		m.keyForAction[action] = append([]string(nil), keys...)
+	}
 
 	// Layer on inline config overrides (lower priority than keymap file).
-	for action, key := range cfg.Keybindings {
-		m.applyKeybindingOverride(action, key)
+	for action, keys := range cfg.Keybindings {
+		m.applyKeybindingOverride(action, keys...)
 	}
 
 	// Layer on external keymap file overrides (highest priority).
 	fileOverrides := loadKeymapFile(cfg.KeymapFile)
-	for action, key := range fileOverrides {
-		m.applyKeybindingOverride(action, key)
+	for action, keys := range fileOverrides {
+		m.applyKeybindingOverride(action, keys...)
 	}
 
 	// Build the reverse index for runtime key → action lookups.
@@ -244,17 +444,17 @@ func (m *Model) loadKeybindings(cfg config.Config) {
 // loadKeymapFile reads and parses an external JSON keymap file.
 //
 // The file is expected to contain a flat JSON object mapping action strings
-// to key strings, for example:
+// to a key string or an array of key strings, for example:
 //
 //	{
-//	    "note.new": "ctrl+n",
+//	    "note.new": ["N", "ctrl+n"],
 //	    "tree.sort.cycle": "S"
 //	}
 //
 // If the file does not exist, nil is returned silently (the keymap file is
 // entirely optional). Parse errors or read errors for existing files are
 // logged as warnings.
-func loadKeymapFile(path string) map[string]string {
+func loadKeymapFile(path string) map[string]config.KeyList {
 	if strings.TrimSpace(path) == "" {
 		return nil
 	}
@@ -265,7 +465,7 @@ func loadKeymapFile(path string) map[string]string {
 		}
 		return nil
 	}
-	overrides := map[string]string{}
+	overrides := map[string]config.KeyList{}
 	if err := json.Unmarshal(data, &overrides); err != nil {
 		appLog.Warn("parse keymap file", "path", path, "error", err)
 		return nil
@@ -274,23 +474,30 @@ func loadKeymapFile(path string) map[string]string {
 }
 
 // applyKeybindingOverride updates a single action's key binding, replacing the
-// action's full default key set.
+// action's full default key set with keys.
 //
-// Both the action and key are trimmed and normalized. If the action string
+// The action is trimmed and each key normalized; empty and repeated keys are
+// dropped, and an override with no keys left is ignored. If the action string
 // is not recognized (i.e. it does not exist in defaultActionKeys), the
 // override is ignored and a warning is logged. This prevents typos in
 // config files from silently failing.
-func (m *Model) applyKeybindingOverride(action, key string) {
+func (m *Model) applyKeybindingOverride(action string, keys ...string) {
 	action = strings.TrimSpace(action)
-	key = normalizeKeyString(key)
-	if action == "" || key == "" {
+	normalized := make([]string, 0, len(keys))
+	for _, key := range keys {
+		key = normalizeKeyString(key)
+		if key != "" && !slices.Contains(normalized, key) {
+			normalized = append(normalized, key)
+		}
+	}
+	if action == "" || len(normalized) == 0 {
 		return
 	}
-	if _, ok := defaultActionKeys[action]; !ok {
+	if _, ok := defaultActionKeys[action]; !ok && !isPopupAction(action) {
 		appLog.Warn("ignore unknown keybinding action", "action", action)
 		return
 	}
-	m.keyForAction[action] = []string{key}
+	m.keyForAction[action] = normalized
 }
 
 // rebuildActionKeyIndex constructs the reverse lookup map (keyToAction) from
@@ -307,6 +514,10 @@ func (m *Model) applyKeybindingOverride(action, key string) {
 func (m *Model) rebuildActionKeyIndex() {
 	m.keyToAction = map[string]string{}
 	for action, keys := range m.keyForAction {
+		if isPopupAction(action) {
+			// Popup keys overlap browse keys by design; see popup_keys.go.
+			continue
+		}
 		for _, key := range keys {
 			if key == "" {
 				continue
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/treykane/cli-notes/internal/config"
@@ -22,6 +24,8 @@ func TestActionForKeySupportsDefaultBrowseAliases(t *testing.T) {
 		"h":       actionCollapse,
 		"g":       actionJumpTop,
 		"G":       actionJumpBottom,
+		"[":       actionJumpFirstChild,
+		"]":       actionJumpLastChild,
 		"shift+r": actionRefresh,
 		"ctrl+c":  actionQuit,
 	}
@@ -35,8 +39,8 @@ func TestActionForKeySupportsDefaultBrowseAliases(t *testing.T) {
 func TestLoadKeybindingsOverrideReplacesDefaultAliases(t *testing.T) {
 	m := &Model{}
 	m.loadKeybindings(config.Config{
-		Keybindings: map[string]string{
-			actionCursorDown: "alt+j",
+		Keybindings: map[string]config.KeyList{
+			actionCursorDown: {"alt+j"},
 		},
 	})
 
@@ -53,3 +57,26 @@ func TestLoadKeybindingsOverrideReplacesDefaultAliases(t *testing.T) {
 		t.Fatalf("expected default alias 'ctrl+n' to be replaced, got %q", got)
 	}
 }
+
+func TestLoadKeybindingsAcceptsSeveralKeysPerAction(t *testing.T) {
+	keymap := filepath.Join(t.TempDir(), "keymap.json")
+	if err := os.WriteFile(keymap, []byte(`{"tree.sort.cycle": ["S", "alt+z", "shift+s"]}`), 0o644); err != nil {
+		t.Fatalf("write keymap: %v", err)
+	}
+	m := &Model{}
+	m.loadKeybindings(config.Config{
+		Keybindings: map[string]config.KeyList{
+			actionNewNote: {"N", " Alt+N ", ""},
+		},
+		KeymapFile: keymap,
+	})
+
+	for _, key := range []string{"shift+n", "alt+n"} {
+		if got := m.actionForKey(key); got != actionNewNote {
+			t.Fatalf("actionForKey(%q) = %q, want %q", key, got, actionNewNote)
+		}
+	}
+	if got := m.keyForAction[actionSort]; len(got) != 2 || got[0] != "shift+s" || got[1] != "alt+z" {
+		t.Fatalf("expected normalized, deduplicated sort keys, got %v", got)
+	}
+}
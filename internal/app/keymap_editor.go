@@ -0,0 +1,221 @@
+// keymap_editor.go implements the keybindings popup (Alt+K by default), an
+// in-app alternative to editing "keybindings" in config.json by hand.
+//
+// The popup lists a "Reset to defaults" row followed by every action in
+// defaultActionKeys, sorted by name, with its current keys. Enter on an
+// action waits for the next key press and binds it:
+//
+//   - A key already bound to another action is refused with a warning (the
+//     same conflict rebuildActionKeyIndex would log), and the popup keeps
+//     waiting so another key can be tried. Esc cancels the capture.
+//   - Otherwise the key replaces the action's keys, is written to
+//     cfg.Keybindings with config.Save, and takes effect at once through
+//     applyKeybindingOverride and rebuildActionKeyIndex.
+//
+// Resetting clears cfg.Keybindings and reloads the bindings. Overrides from
+// keymap_file still apply on top and win over the popup at startup, so the
+// status line says so when the file sets the action being changed.
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// keymapResetRow is the popup row that clears every config override.
+const keymapResetRow = 0
+
+// keymapActions returns every remappable action, sorted by name.
+func keymapActions() []string {
+	actions := make([]string, 0, len(defaultActionKeys))
+	for action := range defaultActionKeys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// openKeymapPopup shows the keybindings popup.
+func (m *Model) openKeymapPopup() {
+	m.openOverlay(overlayKeymap)
+	m.keymapActions = keymapActions()
+	m.keymapCursor = 0
+	m.keymapCapture = ""
+	m.status = "Keybindings: Enter to remap, Esc to close"
+}
+
+// keymapRowCount is the number of popup rows, including the reset row.
+func (m *Model) keymapRowCount() int {
+	return len(m.keymapActions) + 1
+}
+
+// handleKeymapPopupKey routes key presses while the keybindings popup is
+// visible, including the key being captured for an action.
+func (m *Model) handleKeymapPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	if m.keymapCapture != "" {
+		m.captureKeymapKey(msg.String())
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.keymapCursor, m.keymapRowCount())
+	if !handled {
+		return m, nil
+	}
+	if closePressed {
+		m.closeOverlay()
+		m.status = "Keybindings closed"
+		return m, nil
+	}
+	m.keymapCursor = next
+	if !selectPressed {
+		return m, nil
+	}
+	if m.keymapCursor == keymapResetRow {
+		m.resetKeybindings()
+		return m, nil
+	}
+	m.keymapCapture = m.keymapActions[m.keymapCursor-1]
+	m.status = "Press a key for " + m.keymapCapture + " (Esc cancels)"
+	return m, nil
+}
+
+// captureKeymapKey binds key to the action being captured, unless it is Esc
+// or already bound to another action.
+func (m *Model) captureKeymapKey(key string) {
+	action := m.keymapCapture
+	key = normalizeKeyString(key)
+	if key == "" {
+		return
+	}
+	if key == "esc" {
+		m.keymapCapture = ""
+		m.status = "Remap cancelled"
+		return
+	}
+	if other := m.keymapConflict(action, key); other != "" {
+		m.status = fmt.Sprintf("%s is already bound to %s; press another key or Esc", humanizeKeyLabel(key), other)
+		return
+	}
+	m.keymapCapture = ""
+	if err := saveKeybindingOverride(action, key); err != nil {
+		m.setStatusError("Error saving keybinding", err, "action", action)
+		return
+	}
+	m.applyKeybindingOverride(action, key)
+	m.rebuildActionKeyIndex()
+	m.status = fmt.Sprintf("Bound %s to %s", action, humanizeKeyLabel(key)) + m.keymapFileNote(action)
+}
+
+// keymapConflict returns the other action currently bound to key, if any.
+func (m *Model) keymapConflict(action, key string) string {
+	for _, other := range keymapActions() {
+		if other != action && keymapHasKey(m.keyForAction[other], key) {
+			return other
+		}
+	}
+	return ""
+}
+
+func keymapHasKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if normalizeKeyString(k) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// resetKeybindings clears every config override and reloads the bindings.
+func (m *Model) resetKeybindings() {
+	cfg, err := config.Load()
+	if err != nil {
+		m.setStatusError("Error loading config", err)
+		return
+	}
+	cfg.Keybindings = nil
+	if err := config.Save(cfg); err != nil {
+		m.setStatusError("Error saving keybindings", err)
+		return
+	}
+	m.loadKeybindings(cfg)
+	m.status = "Keybindings reset to defaults"
+	if len(loadKeymapFile(cfg.KeymapFile)) > 0 {
+		m.status += " (keymap_file overrides still apply)"
+	}
+}
+
+// keymapFileNote returns a status suffix when keymap_file also sets action,
+// since the file wins over config.json the next time the app starts.
+func (m *Model) keymapFileNote(action string) string {
+	cfg, err := config.Load()
+	if err != nil {
+		return ""
+	}
+	if _, ok := loadKeymapFile(cfg.KeymapFile)[action]; ok {
+		return " (keymap_file also sets it and wins at startup)"
+	}
+	return ""
+}
+
+// saveKeybindingOverride writes one action→key override to config.json.
+func saveKeybindingOverride(action, key string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.Keybindings == nil {
+		cfg.Keybindings = map[string]config.KeyList{}
+	}
+	cfg.Keybindings[action] = config.KeyList{key}
+	return config.Save(cfg)
+}
+
+// renderKeymapPopup draws the keybindings popup, scrolled to keep the
+// selected row visible.
+func (m *Model) renderKeymapPopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	lines := []string{
+		titleStyle.Render("Keybindings"),
+		"",
+	}
+	rows := make([]string, 0, m.keymapRowCount())
+	rows = append(rows, "Reset to defaults")
+	nameWidth := 0
+	for _, action := range m.keymapActions {
+		nameWidth = max(nameWidth, len(action))
+	}
+	for _, action := range m.keymapActions {
+		keys := strings.Join(m.actionKeyLabels(action), ", ")
+		if action == m.keymapCapture {
+			keys = "press a key..."
+		}
+		rows = append(rows, fmt.Sprintf("%-*s  %s", nameWidth, action, keys))
+	}
+
+	limit := max(0, innerHeight-len(lines)-1)
+	start := 0
+	if limit > 0 {
+		start = max(0, m.keymapCursor-limit+1)
+	}
+	for i := start; i < min(start+limit, len(rows)); i++ {
+		line := truncate(rows[i], innerWidth)
+		if i == m.keymapCursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	footer := "Enter: remap  Esc: close"
+	if m.keymapCapture != "" {
+		footer = "Press the new key  Esc: cancel"
+	}
+	lines = append(lines, mutedStyle.Render(footer))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func newKeymapTestModel(t *testing.T) *Model {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := config.Save(config.Config{NotesDir: filepath.Join(home, "notes")}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	m := &Model{}
+	m.loadKeybindings(config.Config{})
+	m.openKeymapPopup()
+	return m
+}
+
+func (m *Model) selectKeymapAction(t *testing.T, action string) {
+	t.Helper()
+	for i, a := range m.keymapActions {
+		if a == action {
+			m.keymapCursor = i + 1
+			m.handleKeymapPopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+			return
+		}
+	}
+	t.Fatalf("action %q not listed", action)
+}
+
+func TestKeymapPopupRemapsAndPersists(t *testing.T) {
+	m := newKeymapTestModel(t)
+
+	m.selectKeymapAction(t, actionNewNote)
+	if m.keymapCapture != actionNewNote {
+		t.Fatalf("expected capture for %s, got %q", actionNewNote, m.keymapCapture)
+	}
+	m.handleKeymapPopupKey(tea.KeyMsg{Type: tea.KeyCtrlE})
+
+	if got := m.actionForKey("ctrl+e"); got != actionNewNote {
+		t.Fatalf("expected ctrl+e to create notes at once, got %q", got)
+	}
+	if got := m.actionForKey("n"); got != "" {
+		t.Fatalf("expected the old key unbound, got %q", got)
+	}
+	if m.keymapCapture != "" || m.status != "Bound note.new to Ctrl+E" {
+		t.Fatalf("unexpected capture %q status %q", m.keymapCapture, m.status)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if keys := cfg.Keybindings[actionNewNote]; len(keys) != 1 || keys[0] != "ctrl+e" {
+		t.Fatalf("expected override saved, got %v", cfg.Keybindings)
+	}
+}
+
+func TestKeymapPopupRefusesConflictingKey(t *testing.T) {
+	m := newKeymapTestModel(t)
+
+	m.selectKeymapAction(t, actionNewNote)
+	m.handleKeymapPopupKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if m.status != "E is already bound to note.edit; press another key or Esc" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	if m.keymapCapture != actionNewNote || m.actionForKey("e") != actionEditNote {
+		t.Fatalf("expected capture kept and bindings unchanged, capture %q", m.keymapCapture)
+	}
+	cfg, _ := config.Load()
+	if len(cfg.Keybindings) != 0 {
+		t.Fatalf("expected nothing saved, got %v", cfg.Keybindings)
+	}
+
+	m.handleKeymapPopupKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.keymapCapture != "" || !m.isOverlay(overlayKeymap) || m.status != "Remap cancelled" {
+		t.Fatalf("expected Esc to cancel only the capture, status %q", m.status)
+	}
+}
+
+func TestKeymapPopupResetClearsOverrides(t *testing.T) {
+	m := newKeymapTestModel(t)
+	m.selectKeymapAction(t, actionNewNote)
+	m.handleKeymapPopupKey(tea.KeyMsg{Type: tea.KeyCtrlE})
+
+	m.keymapCursor = keymapResetRow
+	m.handleKeymapPopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.actionForKey("n"); got != actionNewNote {
+		t.Fatalf("expected default key restored, got %q", got)
+	}
+	cfg, _ := config.Load()
+	if len(cfg.Keybindings) != 0 {
+		t.Fatalf("expected overrides cleared, got %v", cfg.Keybindings)
+	}
+
+	keymap, err := config.DefaultKeymapPath()
+	if err != nil {
+		t.Fatalf("keymap path: %v", err)
+	}
+	if err := os.WriteFile(keymap, []byte(`{"note.new": "ctrl+b"}`), 0o644); err != nil {
+		t.Fatalf("write keymap: %v", err)
+	}
+	m.handleKeymapPopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.HasSuffix(m.status, "(keymap_file overrides still apply)") || m.actionForKey("ctrl+b") != actionNewNote {
+		t.Fatalf("expected keymap_file kept after reset, status %q", m.status)
+	}
+}
@@ -7,7 +7,7 @@
 // The right pane's usable area depends on the active mode because preview and
 // edit modes use different Lipgloss border styles with different frame sizes.
 // An additional row is subtracted for the right-pane header bar that shows the
-// current file path.
+// current file path, plus the metadata strip rows when that strip is visible.
 //
 // All dimension calculations are gathered into a single LayoutDimensions struct
 // so they can be computed once per resize and reused by View, updateLayout, and
@@ -33,20 +33,22 @@ type LayoutDimensions struct {
 // terminal_width / TreeWidthDivider, so narrow terminals still get a usable
 // tree. The right pane fills the remaining space. The viewport dimensions
 // account for the active pane style's border and padding (which differ between
-// preview and edit modes), subtract one row for the right-pane header bar, and
-// reserve adaptive footer rows at the bottom.
+// preview and edit modes), subtract one row for the right-pane header bar and
+// any visible metadata strip rows, and reserve adaptive footer rows at the
+// bottom.
 func (m *Model) calculateLayout() LayoutDimensions {
 	leftWidth := min(DefaultTreeWidth, m.width/TreeWidthDivider)
 	rightWidth := max(0, m.width-leftWidth)
 	contentHeight := max(0, m.height-m.footerHeightForWidth(m.width))
 
 	rightPaneStyle := previewPane
-	if m.mode == modeEditNote {
+	if m.mode == modeEditNote || m.mode == modeEditTemplate {
 		rightPaneStyle = editPane
 	}
 
 	viewportWidth := max(0, rightWidth-rightPaneStyle.GetHorizontalFrameSize())
 	viewportHeight := max(0, contentHeight-rightPaneStyle.GetVerticalFrameSize()-1)
+	viewportHeight = max(0, viewportHeight-m.metadataStripHeight(viewportWidth))
 
 	return LayoutDimensions{
 		LeftWidth:      leftWidth,
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -11,7 +12,8 @@ import (
 func (m *Model) handleSpinnerTick(msg spinner.TickMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.spinner, cmd = m.spinner.Update(msg)
-	if m.rendering {
+	if m.renderPlaceholderDue() {
+		m.renderPlaceholder = true
 		m.viewport.SetContent(m.spinner.View() + " Rendering...")
 	}
 	return m, cmd
@@ -33,7 +35,7 @@ func (m *Model) handleRenderRequest(msg renderRequestMsg) (tea.Model, tea.Cmd) {
 	if msg.seq != m.renderSeq || msg.path != m.pendingPath || msg.width != m.pendingWidth {
 		return m, nil
 	}
-	return m, renderMarkdownCmd(msg.path, msg.width, msg.seq)
+	return m, renderMarkdownCmd(m.renderLimiter, msg.path, msg.width, msg.seq)
 }
 
 // handleRenderResult processes the completed markdown render.
@@ -64,6 +66,8 @@ func (m *Model) handleRenderResult(msg renderResultMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	m.renderCosts.record(msg.width, int64(len(msg.raw)), msg.elapsed)
+
 	// Update cache if this is newer than what we have
 	if entry, ok := m.renderCache[msg.path]; !ok || !entry.mtime.After(msg.mtime) {
 		m.renderCache[msg.path] = renderCacheEntry{
@@ -83,8 +87,19 @@ func (m *Model) handleRenderResult(msg renderResultMsg) (tea.Model, tea.Cmd) {
 	if msg.width == roundWidthToNearestBucket(m.viewport.Width) {
 		m.viewport.SetContent(msg.content)
 		m.currentNoteContent = msg.raw
+		m.refreshMetadataStrip()
 		m.restorePreviewOffset(msg.path)
 		m.clearRenderingState()
+		if m.isSlowOp(msg.elapsed) {
+			m.reportSlowOp(slowOpReport{
+				op:      slowOpOpenNote,
+				phase:   slowPhaseRender,
+				elapsed: msg.elapsed,
+				path:    msg.path,
+				bytes:   int64(len(msg.raw)),
+				width:   msg.width,
+			})
+		}
 	}
 	return m, nil
 }
@@ -144,24 +159,88 @@ func (m *Model) handleEditNoteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case "ctrl+1":
 		before := m.captureEditorSnapshot()
-		m.toggleHeading(1)
+		m.headingShortcut(1)
 		m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
 		return m, nil
 	case "ctrl+2":
 		before := m.captureEditorSnapshot()
-		m.toggleHeading(2)
+		m.headingShortcut(2)
 		m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
 		return m, nil
 	case "ctrl+3":
 		before := m.captureEditorSnapshot()
-		m.toggleHeading(3)
+		m.headingShortcut(3)
+		m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
+		return m, nil
+	case "ctrl+t":
+		before := m.captureEditorSnapshot()
+		m.insertOrReflowMarkdownTable()
+		m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
+		return m, nil
+	case "alt+t":
+		before := m.captureEditorSnapshot()
+		m.formatMarkdownTableAtCursor()
+		m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
+		return m, nil
+	case "alt+=":
+		before := m.captureEditorSnapshot()
+		m.evaluateCalcAtCursor()
+		m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
+		return m, nil
+	case "alt++":
+		before := m.captureEditorSnapshot()
+		m.sumCalcSelection()
+		m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
+		return m, nil
+	case "enter":
+		before := m.captureEditorSnapshot()
+		if m.continueMarkdownList() {
+			m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
+			return m, nil
+		}
+		return m.updateEditorWithKey(msg)
+	case "ctrl+@", "alt+d":
+		before := m.captureEditorSnapshot()
+		m.toggleEditorCheckbox()
+		m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
+		return m, nil
+	case "tab":
+		// Reached only when the wiki autocomplete popup is closed; while it
+		// is open handleWikiAutocompleteKey consumes Tab to accept. Inside a
+		// table it moves to the next cell instead of indenting.
+		if m.moveMarkdownTableCell(1) {
+			return m, nil
+		}
+		before := m.captureEditorSnapshot()
+		m.insertEditorSoftTab()
 		m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
 		return m, nil
+	case "shift+tab":
+		if m.moveMarkdownTableCell(-1) {
+			return m, nil
+		}
+		return m.updateEditorWithKey(msg)
+	case "f8":
+		m.jumpToNoteIssue(1)
+		return m, nil
+	case "f20":
+		// Terminals report Shift+F8 as F20.
+		m.jumpToNoteIssue(-1)
+		return m, nil
 	case "ctrl+v":
 		before := m.captureEditorSnapshot()
 		m.pasteFromClipboardIntoEditor()
 		m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
 		return m, nil
+	case "ctrl+c", "alt+c":
+		m.copyEditorSelection(false)
+		return m, nil
+	case "ctrl+x":
+		before := m.captureEditorSnapshot()
+		if m.copyEditorSelection(true) {
+			m.recordDiscreteEditMutation(before, m.captureEditorSnapshot())
+		}
+		return m, nil
 	case "esc":
 		m.rememberNotePosition(m.currentFile)
 		m.saveAppState()
@@ -173,26 +252,40 @@ func (m *Model) handleEditNoteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.clearDraftForPath(m.currentFile)
 		m.status = "Edit cancelled"
-		return m, nil
+		return m, m.endSplitSecondaryEdit()
 	default:
-		beforeSnapshot := m.captureEditorSnapshot()
-		before := m.editor.Value()
-		var cmd tea.Cmd
-		m.editor, cmd = m.editor.Update(msg)
-		if before != m.editor.Value() {
-			m.recordTypingMutation(beforeSnapshot, m.captureEditorSnapshot(), time.Now())
+		return m.updateEditorWithKey(msg)
+	}
+}
+
+// updateEditorWithKey passes a key to the textarea, recording typing for
+// undo and keeping the selection state in step.
+func (m *Model) updateEditorWithKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	beforeSnapshot := m.captureEditorSnapshot()
+	before := m.editor.Value()
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	if before != m.editor.Value() {
+		m.recordTypingMutation(beforeSnapshot, m.captureEditorSnapshot(), time.Now())
+		m.clearEditorSelection()
+		m.maybeTriggerEditorAutocomplete()
+	} else if m.hasEditorSelectionAnchor() {
+		if isUnshiftedSelectionCollapseKey(msg) {
 			m.clearEditorSelection()
-			m.maybeTriggerWikiAutocomplete()
-		} else if m.hasEditorSelectionAnchor() {
-			if isUnshiftedSelectionCollapseKey(msg) {
-				m.clearEditorSelection()
-				m.status = "Selection cleared"
-			} else {
-				m.updateEditorSelectionStatus()
-			}
+			m.status = "Selection cleared"
+		} else {
+			m.updateEditorSelectionStatus()
 		}
-		return m, cmd
 	}
+	return m, cmd
+}
+
+// insertEditorSoftTab inserts EditorSoftTabWidth spaces at the cursor. The
+// textarea widget ignores Tab on its own, and spaces keep list nesting stable
+// across markdown renderers.
+func (m *Model) insertEditorSoftTab() {
+	m.clearEditorSelection()
+	m.editor.InsertString(strings.Repeat(" ", EditorSoftTabWidth))
 }
 
 // insertEditorWrapper inserts open+close markers and positions the cursor between them.
@@ -226,6 +319,10 @@ func (m *Model) handleInputModeKey(msg tea.KeyMsg, save func() (tea.Model, tea.C
 
 // handleNewNoteKey processes keypresses while creating a new note.
 func (m *Model) handleNewNoteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "tab" && !m.templateChosen {
+		m.openTemplatePickerOverFolderDefault()
+		return m, nil
+	}
 	return m.handleInputModeKey(msg, m.saveNewNote, "New note cancelled")
 }
 
@@ -244,6 +341,11 @@ func (m *Model) handleMoveItemKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m.handleInputModeKey(msg, m.saveMoveItem, "Move cancelled")
 }
 
+// handleEditTagsKey processes keypresses while editing a note's tags.
+func (m *Model) handleEditTagsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	return m.handleInputModeKey(msg, m.saveEditTags, "Tag edit cancelled")
+}
+
 // handleConfirmDeleteKey processes yes/no confirmation for deletions.
 func (m *Model) handleConfirmDeleteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.shouldIgnoreInput(msg) {
@@ -268,14 +370,21 @@ func (m *Model) handleConfirmDeleteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleGitCommitKey processes keypresses while entering a git commit message.
 func (m *Model) handleGitCommitKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	return m.handleInputModeKey(msg, func() (tea.Model, tea.Cmd) {
+	model, cmd := m.handleInputModeKey(msg, func() (tea.Model, tea.Cmd) {
 		return m.runGitCommit(m.input.Value())
 	}, "Git commit cancelled")
+	if m.mode != modeGitCommit {
+		// A confirmed stage-all applies to this commit only.
+		m.gitStageAllNext = false
+	}
+	m.resumeGitPanel()
+	return model, cmd
 }
 
 // clearRenderingState resets rendering flags after completion or error.
 func (m *Model) clearRenderingState() {
 	m.rendering = false
+	m.renderPlaceholder = false
 	m.renderingPath = ""
 	m.renderingSeq = 0
 }
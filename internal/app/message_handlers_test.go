@@ -433,3 +433,34 @@ func TestFormattingEmptySelectionFallsBack(t *testing.T) {
 		}
 	})
 }
+
+func TestHandleEditNoteKeyTabAcceptsOpenAutocomplete(t *testing.T) {
+	m := newFocusedEditModel("See [[Pro")
+	m.openOverlay(overlayWikiAutocomplete)
+	m.wikiAutocomplete = []noteTarget{{Name: "Project"}}
+
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyTab})
+
+	if got := m.editor.Value(); got != "See [[Project]]" {
+		t.Fatalf("expected candidate accepted, got %q", got)
+	}
+	if m.overlay != overlayNone {
+		t.Fatalf("expected autocomplete closed, got %v", m.overlay)
+	}
+}
+
+func TestHandleEditNoteKeyTabIndentsWithoutAutocomplete(t *testing.T) {
+	m := newFocusedEditModel("- item")
+	m.editor.CursorStart()
+
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyTab})
+
+	want := strings.Repeat(" ", EditorSoftTabWidth) + "- item"
+	if got := m.editor.Value(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	m.undoEditorChange()
+	if got := m.editor.Value(); got != "- item" {
+		t.Fatalf("expected undo to remove the indent, got %q", got)
+	}
+}
@@ -0,0 +1,136 @@
+// metadata_strip.go implements the frontmatter summary strip shown between
+// the preview header and the rendered note (toggled with `M`).
+//
+// The strip summarizes Title · Category · #tags · modified date on one or two
+// lines. It only appears in preview when the current note has a frontmatter
+// block; its height is subtracted from the viewport in both renderRight and
+// calculateLayout so the preview never overflows the pane. The segments are
+// rebuilt whenever currentNoteContent changes (open, render result, save), so
+// the View layer only has to wrap them to the current width.
+package app
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// metadataStripSeparator joins summary segments on a strip line.
+const metadataStripSeparator = " · "
+
+// refreshMetadataStrip rebuilds the strip segments from currentNoteContent.
+// The modified date prefers the frontmatter updated: value and falls back to
+// the file's mtime.
+func (m *Model) refreshMetadataStrip() {
+	m.metadataStripSegments = nil
+	if m.currentFile == "" {
+		return
+	}
+	doc := parseFrontmatterDoc(m.currentNoteContent)
+	if !doc.hasBlock {
+		return
+	}
+
+	var segments []string
+	if title := doc.title(); title != "" {
+		segments = append(segments, title)
+	}
+	if category := doc.category(); category != "" {
+		segments = append(segments, category)
+	}
+	if tags := doc.tags(); len(tags) > 0 {
+		segments = append(segments, "#"+strings.Join(tags, " #"))
+	}
+	layout := m.dateDisplayFormat
+	if layout == "" {
+		layout = defaultDateDisplayFormat
+	}
+	if updated, ok := doc.timeValue(frontmatterKeyUpdated); ok {
+		segments = append(segments, "modified "+updated.Format(layout))
+	} else if info, err := os.Stat(m.currentFile); err == nil {
+		segments = append(segments, "modified "+info.ModTime().Format(layout))
+	}
+	m.metadataStripSegments = segments
+}
+
+// metadataStripVisible reports whether the strip is drawn: it must be turned
+// on, the note must have frontmatter, and the right pane must be showing the
+// single-pane preview.
+func (m *Model) metadataStripVisible() bool {
+	if !m.showMetadataStrip || len(m.metadataStripSegments) == 0 {
+		return false
+	}
+	if m.splitMode || m.showHelp {
+		return false
+	}
+	switch m.mode {
+	case modeEditNote, modeTemplatePicker, modeDraftRecovery, modeEditConflict, modeImportConflict,
+		modeNewNote, modeNewFolder, modeRenameItem, modeMoveItem, modeDuplicateItem, modeImport, modeAddWorkspace, modeExportFolder, modeGitCommit, modeEditTags, modeInbox, modeNotePassphrase, modeSaveView, modeRenameTag, modeStateTransfer, modeFrontmatterForm, modeEditTemplate:
+		return false
+	}
+	return true
+}
+
+// metadataStripHeight returns how many rows the strip takes at width, or 0
+// when it is hidden.
+func (m *Model) metadataStripHeight(width int) int {
+	if !m.metadataStripVisible() {
+		return 0
+	}
+	return len(metadataStripLines(m.metadataStripSegments, width))
+}
+
+// metadataStripLines packs segments into at most MetadataStripMaxLines lines
+// of the given width. Segments that do not fit on the last line are appended
+// to it and truncated.
+func metadataStripLines(segments []string, width int) []string {
+	if len(segments) == 0 || width <= 1 {
+		return nil
+	}
+	avail := width - 1 // leading space, matching renderRightHeader
+	var lines []string
+	current := ""
+	for _, segment := range segments {
+		candidate := segment
+		if current != "" {
+			candidate = current + metadataStripSeparator + segment
+		}
+		if current == "" || lipgloss.Width(candidate) <= avail || len(lines) == MetadataStripMaxLines-1 {
+			current = candidate
+			continue
+		}
+		lines = append(lines, current)
+		current = segment
+	}
+	lines = append(lines, current)
+	for i, line := range lines {
+		lines[i] = " " + truncate(line, avail)
+	}
+	return lines
+}
+
+// renderMetadataStrip draws the strip lines for the current note.
+func (m *Model) renderMetadataStrip(width int) string {
+	lines := metadataStripLines(m.metadataStripSegments, width)
+	for i, line := range lines {
+		lines[i] = mutedStyle.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// toggleMetadataStrip shows or hides the strip and persists the choice.
+func (m *Model) toggleMetadataStrip() {
+	m.showMetadataStrip = !m.showMetadataStrip
+	m.saveAppState()
+	m.updateLayout()
+	if !m.showMetadataStrip {
+		m.status = "Metadata strip hidden"
+		return
+	}
+	if len(m.metadataStripSegments) == 0 {
+		m.status = "Metadata strip shown (current note has no frontmatter)"
+		return
+	}
+	m.status = "Metadata strip shown"
+}
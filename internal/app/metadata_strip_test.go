@@ -0,0 +1,91 @@
+package app
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRefreshMetadataStripSummarizesFrontmatter(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "plan.md")
+	content := "---\ntitle: Plan\ncategory: work\ntags: [go, cli]\nupdated: 2026-03-04T10:00:00Z\n---\nbody\n"
+	mustWriteFile(t, note, content)
+
+	m := &Model{notesDir: root, currentFile: note, currentNoteContent: content}
+	m.refreshMetadataStrip()
+	want := []string{"Plan", "work", "#go #cli", "modified Mar 4, 2026"}
+	if !reflect.DeepEqual(m.metadataStripSegments, want) {
+		t.Fatalf("expected segments %q, got %q", want, m.metadataStripSegments)
+	}
+
+	m.currentNoteContent = "no frontmatter\n"
+	m.refreshMetadataStrip()
+	if len(m.metadataStripSegments) != 0 {
+		t.Fatalf("expected no segments without frontmatter, got %q", m.metadataStripSegments)
+	}
+}
+
+func TestMetadataStripLinesWrapsToTwoLines(t *testing.T) {
+	segments := []string{"A long title", "category", "#one #two", "modified Mar 4, 2026"}
+
+	if got := metadataStripLines(segments, 80); len(got) != 1 {
+		t.Fatalf("expected one line at wide width, got %q", got)
+	}
+	got := metadataStripLines(segments, 30)
+	if len(got) != MetadataStripMaxLines {
+		t.Fatalf("expected %d lines at narrow width, got %q", MetadataStripMaxLines, got)
+	}
+	for _, line := range got {
+		if width := len([]rune(line)); width > 30 {
+			t.Fatalf("line %q exceeds width 30", line)
+		}
+	}
+	if !strings.HasPrefix(got[0], " A long title · category") {
+		t.Fatalf("unexpected first line %q", got[0])
+	}
+}
+
+func TestCalculateLayoutShrinksViewportForMetadataStrip(t *testing.T) {
+	m := &Model{
+		mode:                  modeBrowse,
+		width:                 240,
+		height:                24,
+		metadataStripSegments: []string{"Plan", "work"},
+	}
+	base := m.calculateLayout().ViewportHeight
+
+	m.showMetadataStrip = true
+	if got := m.calculateLayout().ViewportHeight; got != base-1 {
+		t.Fatalf("expected viewport height %d with strip, got %d", base-1, got)
+	}
+
+	m.mode = modeEditNote
+	if got := m.metadataStripHeight(80); got != 0 {
+		t.Fatalf("expected strip hidden in edit mode, got height %d", got)
+	}
+}
+
+func TestToggleMetadataStripPersistsInAppState(t *testing.T) {
+	root := t.TempDir()
+	m := &Model{notesDir: root, mode: modeBrowse, width: 120, height: 30}
+
+	m.toggleMetadataStrip()
+	state, err := loadAppState(root)
+	if err != nil {
+		t.Fatalf("load app state: %v", err)
+	}
+	if !state.ShowMetadataStrip {
+		t.Fatal("expected metadata strip to be persisted as shown")
+	}
+
+	m.toggleMetadataStrip()
+	state, err = loadAppState(root)
+	if err != nil {
+		t.Fatalf("load app state: %v", err)
+	}
+	if state.ShowMetadataStrip {
+		t.Fatal("expected metadata strip to be persisted as hidden")
+	}
+}
@@ -3,16 +3,19 @@ package app
 import (
 	"fmt"
 	"strings"
+	"time"
 	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // noteMetrics holds computed statistics about a note's text content.
 //
 // These metrics are displayed in the footer status bar (in both preview and
-// edit modes) to give the user a quick overview of the note's size. The
-// metrics are recomputed on every View call from the current note content,
-// so they always reflect the latest state — including unsaved edits in
-// progress.
+// edit modes) to give the user a quick overview of the note's size. They are
+// cached per note content (see currentNoteMetrics) and follow unsaved edits in
+// progress — immediately for normal notes, after a short pause in typing for
+// very large buffers.
 type noteMetrics struct {
 	// words is the number of whitespace-separated tokens in the content,
 	// as determined by strings.Fields (which splits on any Unicode
@@ -30,6 +33,34 @@ type noteMetrics struct {
 	// newline does NOT add an extra line (i.e. "hello\n" is 1 line, not 2),
 	// matching the behavior most text editors display in their status bars.
 	lines int
+
+	// headings is the number of ATX headings outside fenced code blocks,
+	// counted with the same rules as the outline popup.
+	headings int
+
+	// readingMinutes is the estimated reading time at ReadingWordsPerMinute,
+	// rounded to the nearest minute (0 for very short notes).
+	readingMinutes int
+}
+
+// noteMetricsCache holds the footer metrics for the content they were last
+// computed from, so View does not rescan the note on every frame.
+type noteMetricsCache struct {
+	valid    bool
+	path     string
+	content  string
+	metrics  noteMetrics
+	wordGoal int
+	// goalWords counts the words of the body below the frontmatter, which
+	// is what a word goal measures.
+	goalWords int
+}
+
+// liveMetricsMsg is emitted LiveMetricsDebounce after an edit to a large
+// buffer. Only the message matching the latest liveMetricsSeq recomputes
+// metrics, so a burst of typing triggers a single update.
+type liveMetricsMsg struct {
+	seq int
 }
 
 // currentNoteTextForMetrics returns the raw text content that should be used
@@ -51,8 +82,8 @@ func (m *Model) currentNoteTextForMetrics() string {
 	return m.currentNoteContent
 }
 
-// computeNoteMetrics calculates word count, character count, and line count
-// for the given content string.
+// computeNoteMetrics calculates word count, character count, line count,
+// heading count, and estimated reading time for the given content string.
 //
 // The function handles edge cases:
 //   - Empty content returns all-zero metrics.
@@ -75,11 +106,76 @@ func computeNoteMetrics(content string) noteMetrics {
 	if !strings.HasSuffix(content, "\n") {
 		lines++
 	}
+	words := len(strings.Fields(content))
 	return noteMetrics{
-		words: len(strings.Fields(content)),
-		chars: utf8.RuneCountInString(content),
-		lines: lines,
+		words:          words,
+		chars:          utf8.RuneCountInString(content),
+		lines:          lines,
+		headings:       len(parseMarkdownHeadings(content)),
+		readingMinutes: (words + ReadingWordsPerMinute/2) / ReadingWordsPerMinute,
+	}
+}
+
+// currentNoteMetrics returns the cached footer metrics, frontmatter word
+// goal, and body word count for the current note text, recomputing them only
+// when the text changed.
+//
+// While a large buffer (LiveMetricsDebounceBytes or more) is being edited, the
+// cached values are kept until typing pauses and handleLiveMetrics refreshes
+// them; smaller buffers are recomputed right away.
+func (m *Model) currentNoteMetrics() noteMetricsCache {
+	content := m.currentNoteTextForMetrics()
+	cache := m.metricsCache
+	if cache.valid && cache.path == m.currentFile {
+		if cache.content == content {
+			return cache
+		}
+		if m.mode == modeEditNote && len(content) >= LiveMetricsDebounceBytes {
+			return cache
+		}
+	}
+	m.updateMetricsCache(content)
+	return m.metricsCache
+}
+
+// updateMetricsCache recomputes the cached footer metrics from content.
+func (m *Model) updateMetricsCache(content string) {
+	meta, body := parseFrontmatterAndBody(content)
+	m.metricsCache = noteMetricsCache{
+		valid:     true,
+		path:      m.currentFile,
+		content:   content,
+		metrics:   computeNoteMetrics(content),
+		wordGoal:  meta.WordGoal,
+		goalWords: len(strings.Fields(body)),
+	}
+}
+
+// scheduleLiveMetrics starts the debounce timer after a key press changed a
+// large editor buffer. It returns nil outside edit mode, for small buffers
+// (which currentNoteMetrics recomputes directly), and when the cached metrics
+// already match the buffer.
+func (m *Model) scheduleLiveMetrics() tea.Cmd {
+	if m.mode != modeEditNote {
+		return nil
+	}
+	content := m.editor.Value()
+	if len(content) < LiveMetricsDebounceBytes || content == m.metricsCache.content {
+		return nil
+	}
+	m.liveMetricsSeq++
+	seq := m.liveMetricsSeq
+	return tea.Tick(LiveMetricsDebounce, func(time.Time) tea.Msg {
+		return liveMetricsMsg{seq: seq}
+	})
+}
+
+// handleLiveMetrics refreshes the cached metrics once typing has paused.
+func (m *Model) handleLiveMetrics(msg liveMetricsMsg) (tea.Model, tea.Cmd) {
+	if msg.seq == m.liveMetricsSeq && m.mode == modeEditNote {
+		m.updateMetricsCache(m.editor.Value())
 	}
+	return m, nil
 }
 
 // noteMetricsSummary produces a compact summary string of the current note's
@@ -90,14 +186,52 @@ func computeNoteMetrics(content string) noteMetrics {
 // For example, a note with 150 words, 823 characters, and 42 lines would
 // produce: "W:150 C:823 L:42"
 //
+// When the note's frontmatter sets word_goal, progress toward it is appended
+// (e.g. "W:150 C:823 L:42 Goal:150/500 (30%)"). Once the goal is met the
+// progress part is rendered in the success color.
+//
 // Returns an empty string if the note content is empty or whitespace-only,
 // which causes the footer rendering to omit the metrics section entirely
 // (avoiding a distracting "W:0 C:0 L:0" display when no note is loaded).
 func (m *Model) noteMetricsSummary() string {
-	content := m.currentNoteTextForMetrics()
-	if strings.TrimSpace(content) == "" {
+	if strings.TrimSpace(m.currentNoteTextForMetrics()) == "" {
 		return ""
 	}
-	metrics := computeNoteMetrics(content)
-	return fmt.Sprintf("W:%d C:%d L:%d", metrics.words, metrics.chars, metrics.lines)
+	cache := m.currentNoteMetrics()
+	metrics := cache.metrics
+	summary := fmt.Sprintf("W:%d C:%d L:%d", metrics.words, metrics.chars, metrics.lines)
+	if cache.wordGoal <= 0 {
+		return summary
+	}
+	progress := wordGoalProgress(cache.goalWords, cache.wordGoal)
+	if cache.goalWords >= cache.wordGoal {
+		background := accentBrowse
+		if m.mode == modeEditNote {
+			background = accentEdit
+		}
+		// Keep the footer background behind the highlighted segment.
+		progress = wordGoalMetStyle.Copy().Background(background).Render(progress)
+	}
+	return summary + " " + progress
+}
+
+// wordGoalProgress formats word-goal progress as "W <words>/<goal> (<pct>%)".
+// The percentage is rounded down and may exceed 100 once the goal is passed.
+func wordGoalProgress(words, goal int) string {
+	return fmt.Sprintf("W %d/%d (%d%%)", words, goal, words*100/goal)
+}
+
+// noteReadingSegments returns the reading-time and heading-count footer
+// segments ("~3 min read", "H:4"). They are separate from the W/C/L summary so
+// the footer can wrap them onto the next row at narrow widths.
+func (m *Model) noteReadingSegments() []string {
+	if strings.TrimSpace(m.currentNoteTextForMetrics()) == "" {
+		return nil
+	}
+	metrics := m.currentNoteMetrics().metrics
+	reading := fmt.Sprintf("~%d min read", metrics.readingMinutes)
+	if metrics.readingMinutes == 0 {
+		reading = "<1 min read"
+	}
+	return []string{reading, fmt.Sprintf("H:%d", metrics.headings)}
 }
@@ -1,6 +1,9 @@
 package app
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestComputeNoteMetrics(t *testing.T) {
 	metrics := computeNoteMetrics("one two\nthree\n")
@@ -21,3 +24,99 @@ func TestNoteMetricsSummaryEmpty(t *testing.T) {
 		t.Fatalf("expected empty summary, got %q", got)
 	}
 }
+
+func TestNoteMetricsSummaryAppendsWordGoalProgress(t *testing.T) {
+	m := &Model{currentNoteContent: "---\nword_goal: 8\n---\none two three\n"}
+	// W: counts the whole note; the goal counts only the body.
+	if got, want := m.noteMetricsSummary(), "W:7 C:35 L:4 W 3/8 (37%)"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	m.currentNoteContent = "---\nword_goal: 3\n---\none two three\n"
+	got := m.noteMetricsSummary()
+	if !strings.HasPrefix(got, "W:7 C:35 L:4 ") || !strings.Contains(got, "W 3/3 (100%)") {
+		t.Fatalf("unexpected summary for met goal: %q", got)
+	}
+
+	m.currentNoteContent = "---\nword_goal: 500\n---\n"
+	if got := m.noteMetricsSummary(); !strings.HasSuffix(got, "W 0/500 (0%)") {
+		t.Fatalf("expected an empty body to start at zero, got %q", got)
+	}
+}
+
+func TestNoteMetricsSummaryIgnoresInvalidWordGoal(t *testing.T) {
+	for _, goal := range []string{"abc", "0", "-5"} {
+		m := &Model{currentNoteContent: "---\nword_goal: " + goal + "\n---\nbody\n"}
+		if got := m.noteMetricsSummary(); strings.Contains(got, "%)") {
+			t.Fatalf("word_goal %q: expected no goal segment, got %q", goal, got)
+		}
+	}
+}
+
+func TestComputeNoteMetricsHeadingsAndReadingTime(t *testing.T) {
+	content := "# Title\n\n" + strings.Repeat("word ", 450) + "\n## Section\n```\n# not a heading\n```\n"
+	metrics := computeNoteMetrics(content)
+	if metrics.headings != 2 {
+		t.Fatalf("expected 2 headings, got %d", metrics.headings)
+	}
+	// 460 words (fenced lines included) at 200 wpm round to 2 minutes.
+	if metrics.readingMinutes != 2 {
+		t.Fatalf("expected 2 minute reading time, got %d", metrics.readingMinutes)
+	}
+}
+
+func TestNoteReadingSegments(t *testing.T) {
+	m := &Model{currentNoteContent: "# One\n\nshort note\n"}
+	got := m.noteReadingSegments()
+	if len(got) != 2 || got[0] != "<1 min read" || got[1] != "H:1" {
+		t.Fatalf("unexpected reading segments %q", got)
+	}
+
+	m.currentNoteContent = strings.Repeat("word ", 700)
+	if got := m.noteReadingSegments(); got[0] != "~4 min read" || got[1] != "H:0" {
+		t.Fatalf("unexpected reading segments %q", got)
+	}
+}
+
+func TestNoteMetricsFollowEditorBuffer(t *testing.T) {
+	m := newFocusedEditModel("one two")
+	if got := m.noteMetricsSummary(); !strings.HasPrefix(got, "W:2 ") {
+		t.Fatalf("expected live word count, got %q", got)
+	}
+	m.editor.InsertString(" three")
+	if got := m.noteMetricsSummary(); !strings.HasPrefix(got, "W:3 ") {
+		t.Fatalf("expected small buffer to update immediately, got %q", got)
+	}
+	if cmd := m.scheduleLiveMetrics(); cmd != nil {
+		t.Fatal("expected no debounce for a small buffer")
+	}
+}
+
+func TestNoteMetricsDebounceLargeEditorBuffer(t *testing.T) {
+	m := newFocusedEditModel("")
+	m.editor.CharLimit = 0
+	m.editor.MaxHeight = 0
+	words := LiveMetricsDebounceBytes/5 + 10
+	m.editor.SetValue(strings.Repeat("word ", words))
+	if got := m.currentNoteMetrics().metrics; got.words != words {
+		t.Fatalf("expected %d words on first view, got %d", words, got.words)
+	}
+
+	m.editor.InsertString(" extra")
+	cmd := m.scheduleLiveMetrics()
+	if cmd == nil {
+		t.Fatal("expected debounce command for large buffer")
+	}
+	if got := m.currentNoteMetrics().metrics; got.words != words {
+		t.Fatalf("expected cached metrics while typing, got %d words", got.words)
+	}
+
+	_, _ = m.handleLiveMetrics(liveMetricsMsg{seq: m.liveMetricsSeq - 1})
+	if got := m.currentNoteMetrics().metrics; got.words != words {
+		t.Fatalf("expected stale tick to be ignored, got %d words", got.words)
+	}
+	_, _ = m.handleLiveMetrics(liveMetricsMsg{seq: m.liveMetricsSeq})
+	if got := m.currentNoteMetrics().metrics; got.words != words+1 {
+		t.Fatalf("expected %d words after debounce, got %d", words+1, got.words)
+	}
+}
@@ -40,9 +40,25 @@
 //   - modeMoveItem: Input widget is active for move destination path
 //   - modeConfirmDelete: Yes/No confirmation before deleting
 //   - modeGitCommit: Input widget is active for commit message
+//   - modeEditTags: Input widget is active for the selected note's tags
+//   - modeTreeFilter: Input widget is narrowing the tree as the user types
+//   - modeInbox: Input widget takes the destination for the current inbox item
+//   - modeDuplicateItem: Input widget is active for naming a duplicated item
+//   - modeImport: Input widget takes the path of notes to import
+//   - modeEditConflict: Overwrite/reload/save-copy prompt when the edited note changed on disk
+//   - modeConfirm: Yes/No confirmation before an action that would drop work (confirm.go)
+//   - modeAddWorkspace: Input widget takes a new workspace's name, then its notes dir
+//   - modeExportFolder: Input widget takes the output directory of a folder HTML export
+//   - modeImportConflict: Overwrite/rename/skip prompt for an import target that already exists
+//   - modeNotePassphrase: Masked input takes the passphrase for encrypted notes (encryption.go)
+//   - modeSaveView: Input widget takes the name of a saved search view (saved_views.go)
+//   - modeRenameTag: Input widget takes the new name of a tag renamed in every note (tag_rename.go)
+//   - modeStateTransfer: Input widget takes the file a workspace state export or import uses (state_transfer.go)
+//   - modeFrontmatterForm: Structured form edits the selected note's frontmatter keys (frontmatter_form.go)
+//   - modeEditTemplate: Textarea widget edits a template file opened from the template picker (template_edit.go)
 //
 // Rendering: Markdown rendering is debounced and cached to prevent lag.
-// When a file is selected, we wait 500ms before rendering to avoid
+// When a file is selected, we wait briefly before rendering to avoid
 // excessive work during rapid navigation. Renders are cached by file
 // path, modification time, and terminal width bucket.
 //
@@ -79,6 +95,22 @@ const (
 	modeGitCommit
 	modeTemplatePicker
 	modeDraftRecovery
+	modeEditTags
+	modeTreeFilter
+	modeInbox
+	modeDuplicateItem
+	modeImport
+	modeEditConflict
+	modeConfirm
+	modeAddWorkspace
+	modeExportFolder
+	modeImportConflict
+	modeNotePassphrase
+	modeSaveView
+	modeRenameTag
+	modeStateTransfer
+	modeFrontmatterForm
+	modeEditTemplate
 )
 
 // overlayMode represents the single active popup/overlay surface.
@@ -93,6 +125,21 @@ const (
 	overlayExport
 	overlayWikiLinks
 	overlayWikiAutocomplete
+	overlayIssues
+	overlayMetadata
+	overlayGitPanel
+	overlayTrash
+	overlayNoteStats
+	overlayGitDiff
+	overlayGitLog
+	overlayHeadingCase
+	overlayAgenda
+	overlayKeymap
+	overlaySavedViews
+	overlayTagFilter
+	overlayTagBrowser
+	overlayMaintenance
+	overlayCommandPalette
 )
 
 // treeItem represents a single row in the left-hand tree pane.
@@ -103,6 +150,10 @@ type treeItem struct {
 	isDir  bool
 	pinned bool
 	tags   []string
+	size   int64 // file size from the tree walk's stat (files only)
+	// modTime is the modification time from the tree walk's stat (files
+	// only), used for note age colors.
+	modTime time.Time
 }
 
 // Model holds the Bubble Tea state for the entire UI.
@@ -118,8 +169,53 @@ type Model struct {
 	currentFile string
 	// Full-text search index for quick lookup
 	searchIndex *searchIndex
-	// Current tree sorting mode
-	sortMode sortMode
+	// Current tree sorting mode and direction ("" = mode's natural direction)
+	sortMode      sortMode
+	sortDirection sortDirection
+	// Per-folder sort overrides keyed by absolute directory path.
+	folderSorts map[string]folderSort
+	// Where each archived item was archived from (archived path -> origin).
+	archiveOrigins map[string]string
+	// Show the archive folder in the tree (hidden by default).
+	showArchived bool
+	// Ordering applied when the primary sort key is equal
+	sortTiebreak sortTiebreak
+	// Maintain created/updated frontmatter timestamps on save.
+	frontmatterTimestamps bool
+	// Seed new notes without frontmatter with title/created/tags.
+	frontmatterOnCreate bool
+	// Editor calculator result placement (config.CalcResult*) and whether
+	// its numbers use a decimal comma (calc.go).
+	calcResult       string
+	calcDecimalComma bool
+	// Footer segment kinds kept when the footer overflows, most important
+	// first (config.FooterSegment*); empty truncates at the end.
+	footerPriority []string
+	// Operations slower than this are reported (0 disables reporting).
+	slowOpThreshold time.Duration
+	// Daily-note folder (relative to notesDir) and seed template.
+	journalDir      string
+	journalTemplate string
+	// Go layouts for the {{date}} and {{time}} template placeholders.
+	templateDateFormat string
+	templateTimeFormat string
+	// Go layout for frontmatter dates in the metadata popup and strip.
+	dateDisplayFormat string
+	// Create missing intermediate folders for nested new-note names.
+	createMissingDirs bool
+	// Ask before switching workspaces with unsaved edits or drafts.
+	confirmWorkspaceSwitch bool
+	// Workspace popup order: config order or most recently used first.
+	workspaceOrder string
+	// Remove deleted items immediately instead of moving them to the trash.
+	hardDelete bool
+	// Inbox folder (relative to notesDir) and the in-progress inbox walk.
+	inboxDir     string
+	inboxItems   []inboxItem
+	inboxIndex   int
+	inboxResults inboxResults
+	// Folders the folder toggle moves notes between (see toggle_folder.go).
+	toggleFolders []string
 	// Pinned note/folder paths.
 	pinnedPaths map[string]bool
 	// Recently viewed/edited note paths (most recent first).
@@ -130,6 +226,28 @@ type Model struct {
 	noteOpenCounts map[string]int
 	// Frontmatter metadata cache used by tree rendering.
 	treeMetadataCache map[string]treeMetadataCacheEntry
+	// Active tree filter query ("" when the tree is unfiltered).
+	treeFilterQuery string
+	// Row selected before filtering began, restored when the filter clears.
+	treeFilterRestorePath string
+	// Active tree tag filter (tag_filter.go), "" when off, plus the tags
+	// listed in its picker popup and the selected row.
+	tagFilter       string
+	tagFilterTags   []string
+	tagFilterCursor int
+	// Tag browser rows (tag_browser.go) and the selected row.
+	tagBrowserTags   []tagCount
+	tagBrowserCursor int
+	// Creates and renames whose write has not finished, by id
+	// (optimistic_ops.go).
+	pendingOps   map[int]*pendingOp
+	pendingOpSeq int
+	// Tag being renamed in modeRenameTag (tag_rename.go).
+	renameTagFrom string
+	// Rows of the frontmatter form in modeFrontmatterForm (frontmatter_form.go).
+	fmForm *frontmatterForm
+	// Template file open in modeEditTemplate (template_edit.go).
+	templateEditPath string
 
 	// Tree Navigation
 	// Index of the currently selected item in items slice
@@ -166,12 +284,59 @@ type Model struct {
 	status string
 	// Whether the help screen is displayed
 	showHelp bool
+	// Show a right-aligned size column for markdown files in the tree.
+	showFileSizes bool
+	// Color note names in the tree by age (note_age_colors, Shift+B).
+	noteAgeColors bool
+	// Fresh and stale age thresholds in days (note_age_days); nil uses the
+	// defaults.
+	noteAgeDays []int
+	// Stylesheet inlined into exported HTML (export_css_file); empty uses
+	// the built-in one.
+	exportCSSFile string
+	// Page layout of "Folder to HTML" (export_layout: mirror or flatten).
+	exportLayout string
+	// Show the frontmatter summary strip under the preview header (persisted).
+	showMetadataStrip bool
+	// Summary segments for the strip, rebuilt by refreshMetadataStrip.
+	metadataStripSegments []string
+	// Show the getting-started panel in sparse workspaces (show_empty_state).
+	showEmptyState bool
+	// Note count below which the getting-started panel is shown.
+	emptyStateThreshold int
+	// Draft retention policy applied when a workspace is opened.
+	draftPolicy draftRetention
+	// Notes in the workspace, counted up to emptyStateThreshold.
+	workspaceNoteCount int
+	// Whether the notes directory's filesystem ignores name case (probed per
+	// workspace, see case_collision.go).
+	caseInsensitiveFS bool
 	// Debug mode for input sequence logging
 	debugInput bool
+	// Keys arriving before this time were buffered before the last mode or
+	// overlay change and are dropped (see transition_guard.go).
+	transitionGuardUntil time.Time
 	// Last loaded raw note content for counts and clipboard copy
 	currentNoteContent string
+	// Hash of the note content loaded by startEditNote; saving compares it
+	// with the file on disk to detect external changes (edit_conflict.go).
+	editBaseHash string
+	// Soft-wrap off for the note being edited (editor_wrap: false), and the
+	// horizontal scroll of the cropped editor view (editor_wrap.go).
+	editorNoWrap  bool
+	editorHScroll int
+	// Active-line tint (editor_active_line) and the first visible editor
+	// row, mirrored from the textarea's scrolling (editor_active_line.go).
+	editorActiveLine bool
+	editorScrollTop  int
 	// Poll interval for external filesystem watcher ticks.
 	fileWatchInterval time.Duration
+	// Interval auto-commit (git_autocommit_minutes, git_autocommit.go);
+	// pending is set when a tick arrived while the user was busy.
+	autoCommitInterval time.Duration
+	autoCommitPending  bool
+	// Interlock held by a background git operation (op_lock.go).
+	opLock opLock
 
 	// Layout Dimensions
 	// Terminal width and height
@@ -183,6 +348,10 @@ type Model struct {
 	newParent string
 	// Path for rename/move actions
 	actionPath string
+	// Import copies all files instead of markdown only (Tab in the prompt).
+	importAllFiles bool
+	// Planned import waiting on conflict prompts (modeImportConflict).
+	importPlan *importPlan
 	// Snapshot of the item pending delete confirmation
 	pendingDelete treeItem
 	// Anchor offset (in runes) for editor range selection
@@ -193,6 +362,9 @@ type Model struct {
 	editorMouseSelecting bool
 	// Offset where the current mouse drag selection started.
 	editorMouseSelectionOrigin int
+	// Recently copied or cut editor text, newest last; Ctrl+V falls back to
+	// it when the system clipboard is unavailable.
+	killRing []string
 	// Undo history stack for edit mode.
 	editorUndo []editorSnapshot
 	// Redo history stack for edit mode.
@@ -209,21 +381,51 @@ type Model struct {
 	templateCursor int
 	// Template chosen for current new-note flow.
 	selectedTemplate *noteTemplate
+	// Whether the picker was confirmed in the current new-note flow, so a
+	// nil selectedTemplate ("Default") overrides the folder template.
+	templateChosen bool
 	// Pending draft recoveries discovered at startup.
 	pendingDrafts []draftRecord
 	// Current startup recovery candidate.
 	activeDraft *draftRecord
 	// Last successful auto-save timestamp for edit-mode drafts.
 	lastDraftAutosaveAt time.Time
+	// Focus session timer (nil when none), its tick sequence, and whether a
+	// break is offered for the next start (see focus.go).
+	focus             *focusSession
+	focusSeq          int
+	focusBreakOffered bool
+	// Configured focus/break lengths and whether expiry rings the bell.
+	focusLength time.Duration
+	breakLength time.Duration
+	focusBell   bool
+	// Completed focus sessions of this workspace, oldest first.
+	focusHistory []focusRecord
 
 	// Git State
 	git gitRepoStatus
+	// Selected row in the git panel popup.
+	gitPanelCursor int
+	// Reopen the git panel once a commit started from it finishes.
+	gitPanelResume bool
+	// Paths changed through the app since the last commit (git_stage.go),
+	// the git_stage_all setting, and a one-off "stage everything" for the
+	// next commit confirmed when nothing was tracked.
+	gitTouched      map[string]bool
+	gitStageAll     bool
+	gitStageAllNext bool
+	// The git_missing_notice setting, and whether a missing git executable
+	// was already reported this session.
+	gitMissingNotice  bool
+	gitMissingNoticed bool
 
 	// Rendering State
 	// Whether a markdown render is in progress
 	rendering bool
 	// Sequence number for the current render request (prevents stale renders)
 	renderSeq int
+	// Bounds concurrent renders and drops superseded queued ones.
+	renderLimiter *renderLimiter
 	// Path that is pending render
 	pendingPath string
 	// Width for which we're rendering (bucketed for caching)
@@ -234,8 +436,25 @@ type Model struct {
 	renderingPath string
 	// Sequence number of the in-flight render
 	renderingSeq int
+	// Rolling render cost per width bucket (render_debounce.go)
+	renderCosts renderCostModel
+	// When the previous render was requested (fast-navigation detection)
+	lastRenderRequest time.Time
+	// When the pending render was requested, and whether its placeholder shows
+	renderRequestedAt time.Time
+	renderPlaceholder bool
 	// Last observed filesystem snapshot for external-change detection.
 	fileWatchSnapshot fileWatchSnapshot
+	// Event-based watcher for the notes root (watcher_events.go); nil when
+	// fsnotify is unavailable and the poller is in charge.
+	fsWatcher *fsEventWatcher
+	// Terminal focus (terminal_focus.go): watcher changes that arrived while
+	// the terminal was blurred, applied when it regains focus.
+	terminalBlurred bool
+	blurredFSPaths  map[string]bool
+	blurredFSFull   bool
+	// Running folder HTML export (folder_export.go), nil when idle.
+	folderExport *folderExportJob
 
 	// Overlay State
 	// Current active overlay; at most one overlay is visible at a time.
@@ -250,6 +469,8 @@ type Model struct {
 	outlineCursor int
 	// Selected row in workspace popup.
 	workspaceCursor int
+	// Name entered in the first step of the add-workspace prompt.
+	workspaceDraftName string
 	// Selected row in export popup.
 	exportCursor int
 	// Parsed wiki links for current note.
@@ -259,6 +480,85 @@ type Model struct {
 	// Edit-mode wiki link autocomplete popup.
 	wikiAutocomplete       []noteTarget
 	wikiAutocompleteCursor int
+	// Frontmatter completion (frontmatter_complete.go) reuses the wiki
+	// autocomplete popup; fmCompletion is set while it lists tags or keys.
+	fmCompletion      *frontmatterCompletionContext
+	fmCompletionItems []string
+	// Frontmatter rows shown in the metadata popup and selected row.
+	metadataFields []MetadataField
+	metadataCursor int
+	// Cached current-note issues, keyed by path and content (see issues.go).
+	issues        []noteIssue
+	issuesPath    string
+	issuesContent string
+	issuesValid   bool
+	// Index into issues of the last issue jumped to, or -1.
+	issueCursor int
+	// Issues popup rows (grouped by source) and selected row.
+	issuesPopup       []noteIssue
+	issuesPopupCursor int
+	// Footer metrics for the current note text (see currentNoteMetrics).
+	metricsCache noteMetricsCache
+	// Debounce sequence for recomputing metrics of large edit buffers.
+	liveMetricsSeq int
+	// Stats shown in the note stats popup, computed when it opens.
+	noteStats noteStats
+	// Git diff popup: raw diff output, staged toggle, and scroll viewport.
+	gitDiffText     string
+	gitDiffStaged   bool
+	gitDiffViewport viewport.Model
+	// Git history popup: commits of the current note, selected row, and the
+	// revision being viewed (raw and rendered for gitLogRenderedWidth).
+	gitLogEntries       []gitCommit
+	gitLogCursor        int
+	gitLogRevision      string
+	gitLogRendered      string
+	gitLogRenderedWidth int
+	gitLogViewport      viewport.Model
+	// Heading case popup: selected target case row.
+	headingCaseCursor int
+	// Keybindings popup (keymap_editor.go): listed actions, selected row
+	// (0 is the reset row), and the action waiting for a key, if any.
+	keymapActions []string
+	keymapCursor  int
+	keymapCapture string
+	// Command palette (command_palette.go): filter input, the matching
+	// actions, and the selected row.
+	paletteInput   textinput.Model
+	paletteEntries []paletteEntry
+	paletteCursor  int
+	// The next browse key is described instead of run (describe_key.go).
+	describeKeyPending bool
+	// Encrypted notes (encryption.go): the session passphrase and derived
+	// keys, the pending passphrase prompt, and the first entry of a new
+	// passphrase awaiting confirmation.
+	noteKeys        noteKeyring
+	passphrase      *passphraseRequest
+	passphraseFirst string
+	// Pending yes/no question while in modeConfirm (confirm.go).
+	confirm *confirmPrompt
+	// Agenda popup (agenda.go): range shown, its sorted entries, selected
+	// entry, and the cached "today: N" footer count.
+	agendaRange   agendaRange
+	agendaEntries []agendaEntry
+	agendaCursor  int
+	agendaBadge   agendaBadgeState
+	// Saved search views (saved_views.go), the selected popup row, and the
+	// query awaiting a name in modeSaveView.
+	savedViews          []savedView
+	savedViewCursor     int
+	savedViewDraftQuery string
+	// Tag filter toggled by Ctrl+T in the search popup (search_tag_toggle.go).
+	lastSearchTag string
+	// Maintenance popup row, and whether modeStateTransfer imports
+	// (state_transfer.go).
+	maintenanceCursor int
+	stateImport       bool
+	// Trash popup rows (newest first) and selected row.
+	trashEntries []trashEntry
+	trashCursor  int
+	// Drafts count and size shown in the trash popup footer.
+	trashDraftUsage draftUsage
 
 	// Workspace State
 	workspaces      []config.WorkspaceConfig
@@ -267,11 +567,17 @@ type Model struct {
 	// Keybinding State
 	keyForAction map[string][]string
 	keyToAction  map[string]string
+	// Shared popup layer and per-popup key indexes (popup_keys.go).
+	popupKeyToAction map[string]string
+	popupLocalKeys   map[string]map[string]string
 
 	// Split-pane state
 	splitMode           bool
 	splitFocusSecondary bool
 	secondaryFile       string
+	// splitEditSecondary is set while the editor is bound to the secondary
+	// pane (split_edit.go); currentFile and secondaryFile are then swapped.
+	splitEditSecondary bool
 }
 
 // New prepares the initial UI model and ensures the configured notes directory exists.
@@ -280,10 +586,11 @@ func New() (*Model, error) {
 	if err != nil {
 		return nil, err
 	}
-	applyThemePreset(cfg.ThemePreset)
+	applyTheme(loadWorkspaceTheme(cfg, cfg.NotesDir))
 	notesDir := cfg.NotesDir
 	sortMode := loadWorkspaceSortMode(cfg, notesDir)
-	if err := ensureNotesDir(notesDir); err != nil {
+	sortDirection := loadWorkspaceSortDirection(cfg, notesDir)
+	if err := ensureNotesDir(notesDir, cfg.WelcomeNoteEnabled()); err != nil {
 		return nil, err
 	}
 	state, err := loadAppState(notesDir)
@@ -318,6 +625,43 @@ func New() (*Model, error) {
 		items:                      nil,
 		expanded:                   expanded,
 		sortMode:                   sortMode,
+		sortDirection:              sortDirection,
+		folderSorts:                state.FolderSorts,
+		archiveOrigins:             state.ArchivedFrom,
+		sortTiebreak:               parseSortTiebreak(cfg.TreeSortTiebreak),
+		frontmatterTimestamps:      cfg.FrontmatterTimestamps,
+		frontmatterOnCreate:        cfg.FrontmatterOnCreate,
+		calcResult:                 cfg.CalcResult,
+		calcDecimalComma:           cfg.CalcDecimalComma,
+		footerPriority:             cfg.FooterPriority,
+		journalDir:                 cfg.JournalDir,
+		journalTemplate:            cfg.JournalTemplate,
+		templateDateFormat:         cfg.TemplateDateFormat,
+		templateTimeFormat:         cfg.TemplateTimeFormat,
+		dateDisplayFormat:          cfg.DateDisplayFormat,
+		createMissingDirs:          cfg.CreateMissingDirsEnabled(),
+		confirmWorkspaceSwitch:     cfg.ConfirmWorkspaceSwitchEnabled(),
+		editorActiveLine:           cfg.EditorActiveLineEnabled(),
+		workspaceOrder:             cfg.WorkspaceOrder,
+		inboxDir:                   cfg.InboxDir,
+		toggleFolders:              cfg.ToggleFolders,
+		noteAgeColors:              cfg.NoteAgeColors,
+		noteAgeDays:                cfg.NoteAgeDays,
+		exportCSSFile:              cfg.ExportCSSFile,
+		exportLayout:               cfg.ExportLayout,
+		hardDelete:                 cfg.HardDelete,
+		showMetadataStrip:          state.ShowMetadataStrip,
+		showEmptyState:             cfg.EmptyStateEnabled(),
+		emptyStateThreshold:        cfg.EmptyStateThreshold,
+		draftPolicy:                draftRetentionFromConfig(cfg),
+		focusLength:                time.Duration(cfg.FocusMinutes) * time.Minute,
+		breakLength:                time.Duration(cfg.BreakMinutes) * time.Minute,
+		focusBell:                  cfg.FocusBell,
+		focusHistory:               state.FocusSessions,
+		savedViews:                 state.SavedViews,
+		lastSearchTag:              state.LastSearchTag,
+		renderLimiter:              newRenderLimiter(cfg.MaxConcurrentRenders),
+		slowOpThreshold:            time.Duration(cfg.SlowOperationThresholdMs) * time.Millisecond,
 		pinnedPaths:                state.PinnedPaths,
 		recentFiles:                state.RecentFiles,
 		notePositions:              state.Positions,
@@ -329,6 +673,8 @@ func New() (*Model, error) {
 		search:                     search,
 		editor:                     editor,
 		helpViewport:               viewport.New(0, 0),
+		gitDiffViewport:            viewport.New(0, 0),
+		gitLogViewport:             viewport.New(0, 0),
 		mode:                       modeBrowse,
 		status:                     "Ready",
 		spinner:                    spin,
@@ -343,12 +689,19 @@ func New() (*Model, error) {
 		workspaces:                 cfg.Workspaces,
 		activeWorkspace:            cfg.ActiveWorkspace,
 		fileWatchInterval:          time.Duration(cfg.FileWatchIntervalSeconds) * time.Second,
+		autoCommitInterval:         time.Duration(cfg.GitAutocommitMinutes) * time.Minute,
+		gitStageAll:                cfg.GitStageAll,
+		gitMissingNotice:           cfg.GitMissingNoticeEnabled(),
 	}
 	m.loadKeybindings(cfg)
-	m.items = buildTreeWithMetadataCache(m.notesDir, m.expanded, m.sortMode, m.pinnedPaths, m.cachedTagsForPath)
+	m.applyRenderStyle(cfg.RenderStyle)
+	m.detectFilesystemCase()
+	m.items = m.buildTreeItems()
+	m.refreshWorkspaceNoteCount()
 	m.rebuildRecentEntries()
 	m.refreshGitStatus()
 	m.loadPendingDrafts()
+	m.applyEmptyWorkspaceAction(cfg.EmptyWorkspaceAction)
 	return m, nil
 }
 
@@ -358,6 +711,9 @@ func (m *Model) Init() tea.Cmd {
 		m.spinner.Tick,
 		m.scheduleDraftAutosave(),
 		m.scheduleFileWatchTick(),
+		m.scheduleAutoCommit(),
+		m.startFileEvents(),
+		m.scanAgendaBadge(),
 	)
 }
 
@@ -389,32 +745,47 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.MouseMsg:
 		return m.handleMouse(msg)
 	case tea.KeyMsg:
-		switch m.mode {
-		case modeEditNote:
-			return m.handleEditNoteKey(msg)
-		case modeNewNote:
-			return m.handleNewNoteKey(msg)
-		case modeNewFolder:
-			return m.handleNewFolderKey(msg)
-		case modeRenameItem:
-			return m.handleRenameItemKey(msg)
-		case modeMoveItem:
-			return m.handleMoveItemKey(msg)
-		case modeConfirmDelete:
-			return m.handleConfirmDeleteKey(msg)
-		case modeGitCommit:
-			return m.handleGitCommitKey(msg)
-		case modeTemplatePicker:
-			return m.handleTemplatePickerKey(msg)
-		case modeDraftRecovery:
-			return m.handleDraftRecoveryKey(msg)
-		default:
-			return m.handleKey(msg)
+		if m.swallowTransitionKey(msg) {
+			return m, nil
+		}
+		prevMode, prevOverlay := m.mode, m.overlay
+		model, cmd := m.dispatchKey(msg)
+		m.armTransitionGuard(prevMode, prevOverlay)
+		m.runPendingAutoCommit()
+		if live := m.scheduleLiveMetrics(); live != nil {
+			cmd = tea.Batch(cmd, live)
 		}
+		return model, cmd
+	case liveMetricsMsg:
+		return m.handleLiveMetrics(msg)
 	case draftAutoSaveTickMsg:
 		return m.handleDraftAutoSaveTick(msg)
+	case focusTickMsg:
+		return m.handleFocusTick(msg)
+	case autoCommitTickMsg:
+		return m.handleAutoCommitTick(msg)
 	case fileWatchTickMsg:
 		return m.handleFileWatchTick(msg)
+	case fsEventsMsg:
+		return m.handleFSEvents(msg)
+	case fsWatcherStoppedMsg:
+		return m.handleFSWatcherStopped(msg)
+	case tea.BlurMsg:
+		return m.handleTerminalBlur(msg)
+	case tea.FocusMsg:
+		return m.handleTerminalFocus(msg)
+	case gitOpResultMsg:
+		return m.handleGitOpResult(msg)
+	case opLockTimeoutMsg:
+		return m.handleOpLockTimeout(msg)
+	case pendingOpDoneMsg:
+		return m.handlePendingOpDone(msg)
+	case agendaBadgeMsg:
+		return m.handleAgendaBadge(msg)
+	case folderExportProgressMsg:
+		return m.handleFolderExportProgress(msg)
+	case folderExportDoneMsg:
+		return m.handleFolderExportDone(msg)
 	case statusMsg:
 		if strings.TrimSpace(msg.Text) != "" {
 			m.status = msg.Text
@@ -424,6 +795,64 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// dispatchKey routes a key press to the handler for the active mode.
+func (m *Model) dispatchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.mode {
+	case modeEditNote:
+		return m.handleEditNoteKey(msg)
+	case modeNewNote:
+		return m.handleNewNoteKey(msg)
+	case modeNewFolder:
+		return m.handleNewFolderKey(msg)
+	case modeRenameItem:
+		return m.handleRenameItemKey(msg)
+	case modeMoveItem:
+		return m.handleMoveItemKey(msg)
+	case modeDuplicateItem:
+		return m.handleDuplicateItemKey(msg)
+	case modeImport:
+		return m.handleImportKey(msg)
+	case modeImportConflict:
+		return m.handleImportConflictKey(msg)
+	case modeConfirmDelete:
+		return m.handleConfirmDeleteKey(msg)
+	case modeGitCommit:
+		return m.handleGitCommitKey(msg)
+	case modeTemplatePicker:
+		return m.handleTemplatePickerKey(msg)
+	case modeEditTemplate:
+		return m.handleEditTemplateKey(msg)
+	case modeDraftRecovery:
+		return m.handleDraftRecoveryKey(msg)
+	case modeEditConflict:
+		return m.handleEditConflictKey(msg)
+	case modeConfirm:
+		return m.handleConfirmKey(msg)
+	case modeAddWorkspace:
+		return m.handleAddWorkspaceKey(msg)
+	case modeExportFolder:
+		return m.handleExportFolderKey(msg)
+	case modeEditTags:
+		return m.handleEditTagsKey(msg)
+	case modeTreeFilter:
+		return m.handleTreeFilterKey(msg)
+	case modeInbox:
+		return m.handleInboxKey(msg)
+	case modeNotePassphrase:
+		return m.handleNotePassphraseKey(msg)
+	case modeSaveView:
+		return m.handleSaveViewKey(msg)
+	case modeRenameTag:
+		return m.handleRenameTagKey(msg)
+	case modeStateTransfer:
+		return m.handleStateTransferKey(msg)
+	case modeFrontmatterForm:
+		return m.handleFrontmatterFormKey(msg)
+	default:
+		return m.handleKey(msg)
+	}
+}
+
 // handleKey routes key presses in browse mode.
 func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch m.overlay {
@@ -433,6 +862,36 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleExportPopupKey(msg)
 	case overlayWikiLinks:
 		return m.handleWikiLinksPopupKey(msg)
+	case overlayIssues:
+		return m.handleIssuesPopupKey(msg)
+	case overlayMetadata:
+		return m.handleMetadataPopupKey(msg)
+	case overlayGitPanel:
+		return m.handleGitPanelKey(msg)
+	case overlayTrash:
+		return m.handleTrashPopupKey(msg)
+	case overlayNoteStats:
+		return m.handleNoteStatsPopupKey(msg)
+	case overlayGitDiff:
+		return m.handleGitDiffPopupKey(msg)
+	case overlayGitLog:
+		return m.handleGitLogPopupKey(msg)
+	case overlayHeadingCase:
+		return m.handleHeadingCasePopupKey(msg)
+	case overlayAgenda:
+		return m.handleAgendaPopupKey(msg)
+	case overlayKeymap:
+		return m.handleKeymapPopupKey(msg)
+	case overlaySavedViews:
+		return m.handleSavedViewsPopupKey(msg)
+	case overlayTagFilter:
+		return m.handleTagFilterPopupKey(msg)
+	case overlayTagBrowser:
+		return m.handleTagBrowserPopupKey(msg)
+	case overlayMaintenance:
+		return m.handleMaintenancePopupKey(msg)
+	case overlayCommandPalette:
+		return m.handleCommandPaletteKey(msg)
 	case overlayRecent:
 		return m.handleRecentPopupKey(msg)
 	case overlayOutline:
@@ -453,12 +912,16 @@ func (m *Model) openSearchPopup() {
 	m.searchResults = nil
 	m.searchResultCursor = 0
 	m.showHelp = false
+	m.status = "Search popup: type to filter, Enter to jump, Esc to cancel"
 	if m.searchIndex != nil {
+		start := time.Now()
 		if err := m.searchIndex.ensureBuilt(); err != nil {
 			appLog.Error("build search index", "root", m.notesDir, "error", err)
 		}
+		if elapsed := time.Since(start); m.isSlowOp(elapsed) {
+			m.reportSlowOp(slowOpReport{op: slowOpSearch, phase: slowPhaseIndexBuild, elapsed: elapsed, items: len(m.searchIndex.docs), itemsNoun: "indexed entries"})
+		}
 	}
-	m.status = "Search popup: type to filter, Enter to jump, Esc to cancel"
 }
 
 func (m *Model) closeSearchPopup() {
@@ -476,6 +939,11 @@ func (m *Model) updateSearchRows() {
 	if m.searchIndex == nil {
 		m.searchIndex = newSearchIndex(m.notesDir)
 	}
+	phase := slowPhaseQuery
+	if !m.searchIndex.ready {
+		phase = slowPhaseIndexBuild
+	}
+	start := time.Now()
 	if err := m.searchIndex.ensureBuilt(); err != nil {
 		m.searchResults = nil
 		m.searchResultCursor = 0
@@ -484,13 +952,17 @@ func (m *Model) updateSearchRows() {
 		return
 	}
 	m.searchResults = m.searchIndex.search(query)
+	elapsed := time.Since(start)
 	if len(m.searchResults) == 0 {
 		m.searchResultCursor = 0
 		m.status = fmt.Sprintf("Search \"%s\" (0 matches)", query)
-		return
+	} else {
+		m.searchResultCursor = clamp(m.searchResultCursor, 0, len(m.searchResults)-1)
+		m.status = fmt.Sprintf("Search \"%s\" (%d matches)", query, len(m.searchResults))
+	}
+	if m.isSlowOp(elapsed) {
+		m.reportSlowOp(slowOpReport{op: slowOpSearch, phase: phase, elapsed: elapsed, items: len(m.searchIndex.docs), itemsNoun: "indexed entries"})
 	}
-	m.searchResultCursor = clamp(m.searchResultCursor, 0, len(m.searchResults)-1)
-	m.status = fmt.Sprintf("Search \"%s\" (%d matches)", query, len(m.searchResults))
 }
 
 func (m *Model) selectSearchResult() (tea.Model, tea.Cmd) {
@@ -500,6 +972,7 @@ func (m *Model) selectSearchResult() (tea.Model, tea.Cmd) {
 	}
 
 	item := m.searchResults[m.searchResultCursor]
+	m.rememberSearchTag(m.search.Value())
 	m.closeSearchPopup()
 	m.expandParentDirs(item.path)
 	if item.isDir {
@@ -12,15 +12,31 @@ type mutationEffects struct {
 	saveState        bool
 	clearRenderCache bool
 	setCurrentFile   string
+	// external marks changes made outside the app (reported by the
+	// watcher): the paths are re-read, but saved positions keep the content
+	// they were measured on so they can be re-anchored, and the paths are
+	// not staged by the next commit.
+	external bool
 }
 
 // applyMutationEffects centralizes post-filesystem-mutation side effects to keep update flows consistent.
 func (m *Model) applyMutationEffects(opts mutationEffects) tea.Cmd {
+	if !opts.external {
+		for _, path := range opts.upsertPaths {
+			m.rebaseNotePosition(path)
+		}
+		m.recordGitTouched(opts)
+	}
 	if opts.saveState {
 		m.saveAppState()
 	}
 
+	if opts.invalidateSearch || len(opts.removePaths) > 0 || len(opts.upsertPaths) > 0 {
+		// Wiki links in the current note may resolve differently now.
+		m.invalidateNoteIssues()
+	}
 	if m.searchIndex != nil {
+		m.searchIndex.caseInsensitive = m.caseInsensitiveFS
 		if opts.invalidateSearch {
 			m.searchIndex.invalidate()
 		}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -36,6 +37,60 @@ func TestParseMarkdownHeadingsIgnoresFencedBlocks(t *testing.T) {
 	}
 }
 
+func TestOpenMetadataPopupListsFrontmatterFields(t *testing.T) {
+	m := &Model{
+		mode:        modeBrowse,
+		currentFile: "/tmp/notes/plan.md",
+		currentNoteContent: "---\n" +
+			"title: \"Project Plan\"\n" +
+			"date: 2026-02-07\n" +
+			"tags: [go, cli]\n" +
+			"owner: ada\n" +
+			"priority: high\n" +
+			"---\n" +
+			"# Body\n",
+	}
+
+	m.openMetadataPopup()
+	if m.overlay != overlayMetadata {
+		t.Fatalf("expected metadata overlay, got %v (status %q)", m.overlay, m.status)
+	}
+	want := []MetadataField{
+		{Key: "title", Value: "Project Plan"},
+		{Key: "tags", Value: "go, cli"},
+		{Key: "category", Value: "(not set)"},
+		{Key: "date", Value: "Feb 7, 2026"},
+		{Key: "owner", Value: "ada"},
+		{Key: "priority", Value: "high"},
+	}
+	if len(m.metadataFields) != len(want) {
+		t.Fatalf("expected %d fields, got %#v", len(want), m.metadataFields)
+	}
+	for i := range want {
+		if m.metadataFields[i] != want[i] {
+			t.Fatalf("field %d: expected %#v, got %#v", i, want[i], m.metadataFields[i])
+		}
+	}
+
+	rendered := m.renderMetadataPopup(60, MetadataPopupHeight)
+	for _, text := range []string{"Note Metadata", "Project Plan", "owner", "high"} {
+		if !strings.Contains(rendered, text) {
+			t.Fatalf("expected popup to contain %q, got:\n%s", text, rendered)
+		}
+	}
+}
+
+func TestOpenMetadataPopupWithoutFrontmatter(t *testing.T) {
+	m := &Model{mode: modeBrowse, currentFile: "/tmp/notes/plain.md", currentNoteContent: "# Plain\n"}
+	m.openMetadataPopup()
+	if m.overlay != overlayNone {
+		t.Fatalf("expected no overlay, got %v", m.overlay)
+	}
+	if m.status != "No frontmatter in current note" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
 func TestAppStateRoundTrip(t *testing.T) {
 	root := t.TempDir()
 	note := filepath.Join(root, "note.md")
@@ -204,3 +259,44 @@ func TestOpenOverlayReplacesSearchOverlay(t *testing.T) {
 		t.Fatalf("expected search cursor reset, got %d", m.searchResultCursor)
 	}
 }
+
+func TestJumpToFirstAndLastChildOfExpandedFolder(t *testing.T) {
+	root := t.TempDir()
+	docs := filepath.Join(root, "docs")
+	mustWriteFile(t, filepath.Join(docs, "a.md"), "a\n")
+	mustWriteFile(t, filepath.Join(docs, "sub", "deep.md"), "deep\n")
+	mustWriteFile(t, filepath.Join(docs, "z.md"), "z\n")
+	mustWriteFile(t, filepath.Join(root, "top.md"), "top\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.expanded = map[string]bool{root: true, docs: true, filepath.Join(docs, "sub"): true}
+	m.items = buildTree(root, m.expanded, sortModeName, nil)
+	selected := func() string {
+		rel, _ := filepath.Rel(root, m.items[m.cursor].path)
+		return filepath.ToSlash(rel)
+	}
+	for i, item := range m.items {
+		if item.path == docs {
+			m.cursor = i
+		}
+	}
+
+	_, _ = m.handleJumpChild(false)
+	if got := selected(); got != "docs/z.md" {
+		t.Fatalf("expected last child docs/z.md, got %s (tree %v)", got, relPaths(root, m.items))
+	}
+	_, _ = m.handleJumpChild(true)
+	if got := selected(); got != "docs/sub" {
+		t.Fatalf("expected first sibling docs/sub from a file, got %s", got)
+	}
+	_, _ = m.handleJumpChild(true)
+	if got := selected(); got != "docs/sub/deep.md" {
+		t.Fatalf("expected first child of expanded docs/sub, got %s", got)
+	}
+	m.cursor = len(m.items) - 1
+	_, _ = m.handleJumpChild(true)
+	if got := selected(); got != "docs" {
+		t.Fatalf("expected first top-level item, got %s", got)
+	}
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func TestNoteAgeColorsStyleFreshAndOldNotesDifferently(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	withFixedNow(t, now)
+
+	root := t.TempDir()
+	stamps := map[string]time.Time{
+		"fresh.md":  now.Add(-24 * time.Hour),
+		"middle.md": now.AddDate(0, 0, -10),
+		"old.md":    now.AddDate(0, 0, -45),
+	}
+	for name, stamp := range stamps {
+		path := filepath.Join(root, name)
+		mustWriteFile(t, path, "# "+name+"\n")
+		if err := os.Chtimes(path, stamp, stamp); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	m := newTestCRUDModel(root)
+	m.noteAgeDays = []int{3, 30}
+	for _, item := range m.buildTreeItems() {
+		if !item.modTime.Equal(stamps[item.name]) {
+			t.Fatalf("expected the walk to record the mtime of %s, got %v", item.name, item.modTime)
+		}
+		if style := m.treeFileNameStyle(item); style.GetBold() || style.GetForeground() != treeFileName.GetForeground() {
+			t.Fatalf("expected the plain style with age colors off for %s", item.name)
+		}
+	}
+
+	m.noteAgeColors = true
+	got := map[string]noteAge{}
+	for _, item := range m.buildTreeItems() {
+		fresh, stale := m.noteAgeThresholds()
+		got[item.name] = classifyNoteAge(item.modTime, appNow(), fresh, stale)
+		style := m.treeFileNameStyle(item)
+		switch got[item.name] {
+		case noteAgeFresh:
+			if !style.GetBold() || style.GetForeground() != treeFileName.GetForeground() {
+				t.Fatalf("expected a bright style for the fresh note")
+			}
+		case noteAgeStale:
+			if style.GetBold() || style.GetForeground() != mutedStyle.GetForeground() {
+				t.Fatalf("expected a dim style for the old note")
+			}
+		default:
+			if style.GetBold() || style.GetForeground() != treeFileName.GetForeground() {
+				t.Fatalf("expected the plain style between the thresholds")
+			}
+		}
+	}
+	if got["fresh.md"] != noteAgeFresh || got["middle.md"] != noteAgeNormal || got["old.md"] != noteAgeStale {
+		t.Fatalf("unexpected age classes %v", got)
+	}
+	if classifyNoteAge(time.Time{}, now, time.Hour, 2*time.Hour) != noteAgeNormal {
+		t.Fatal("expected an unknown mtime to keep the plain style")
+	}
+}
+
+func TestNoteAgeToggleKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := newTestCRUDModel(t.TempDir())
+	m.mode = modeBrowse
+	m.loadKeybindings(config.Config{})
+
+	m.handleBrowseKey("shift+b")
+	if !m.noteAgeColors || m.status != "Note age colors: on (fresh ≤ 7d, stale > 90d)" {
+		t.Fatalf("expected Shift+B to turn age colors on, got %v / %q", m.noteAgeColors, m.status)
+	}
+	m.handleBrowseKey("shift+b")
+	if m.noteAgeColors || m.status != "Note age colors: off" {
+		t.Fatalf("expected Shift+B to turn age colors off, got %v / %q", m.noteAgeColors, m.status)
+	}
+}
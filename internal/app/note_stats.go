@@ -0,0 +1,192 @@
+// note_stats.go implements the note statistics popup (`w`).
+//
+// The footer shows a compact W/C/L summary; the popup breaks the current
+// note down further: words, characters with and without whitespace, lines,
+// paragraphs, headings, links, and tags, plus the workspace's focus time this
+// week (see focus.go). Everything except tags is computed from the body with
+// the frontmatter block removed, and fenced code blocks are skipped for
+// heading, link, and inline-tag detection the same way the outline and
+// wiki-link parsers skip them. Enter or `y` copies the stats as plain text.
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// markdownLinkPattern matches inline markdown links and images; images are
+// filtered out by their leading "!".
+var markdownLinkPattern = regexp.MustCompile(`!?\[[^\[\]]*\]\([^()\s]*\)`)
+
+// inlineTagPattern matches #tag tokens in prose. Tags must start with a
+// letter so issue numbers like #12 are not counted.
+var inlineTagPattern = regexp.MustCompile(`(?:^|\s)#(\pL[\pL\pN_/-]*)`)
+
+// noteStats is the detailed breakdown shown in the stats popup.
+type noteStats struct {
+	words         int
+	chars         int
+	charsNoSpaces int
+	lines         int
+	paragraphs    int
+	headings      int
+	links         int // markdown links plus [[wiki links]], images excluded
+	tags          int // distinct frontmatter and inline #tags
+	// focusThisWeek is the workspace's completed focus time since Monday;
+	// it is not specific to the note.
+	focusThisWeek time.Duration
+}
+
+// computeNoteStats computes noteStats for raw note content (frontmatter
+// included; it is stripped here).
+func computeNoteStats(content string) noteStats {
+	meta, body := parseFrontmatterAndBody(content)
+	basic := computeNoteMetrics(body)
+	stats := noteStats{
+		words:    basic.words,
+		chars:    basic.chars,
+		lines:    basic.lines,
+		headings: basic.headings,
+	}
+	for _, r := range body {
+		if !unicode.IsSpace(r) {
+			stats.charsNoSpaces++
+		}
+	}
+
+	tags := append([]string(nil), meta.Tags...)
+	inFence := false
+	inParagraph := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if trimmed == "" && !inFence {
+			inParagraph = false
+			continue
+		}
+		if !inFence && isMarkdownHeadingLine(trimmed) {
+			inParagraph = false
+			continue
+		}
+		if !inParagraph {
+			stats.paragraphs++
+			inParagraph = true
+		}
+		if inFence || strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+		for _, match := range markdownLinkPattern.FindAllString(line, -1) {
+			if !strings.HasPrefix(match, "!") {
+				stats.links++
+			}
+		}
+		stats.links += len(wikiLinkPattern.FindAllString(line, -1))
+		for _, match := range inlineTagPattern.FindAllStringSubmatch(line, -1) {
+			tags = append(tags, match[1])
+		}
+	}
+	stats.tags = len(normalizeTagList(tags))
+	return stats
+}
+
+// isMarkdownHeadingLine reports whether a trimmed line is an ATX heading,
+// using the same rules as parseMarkdownHeadings.
+func isMarkdownHeadingLine(trimmed string) bool {
+	return len(parseMarkdownHeadings(trimmed)) == 1
+}
+
+// noteStatsRows returns the label/value rows shown in the popup and copied
+// to the clipboard.
+func noteStatsRows(stats noteStats) []MetadataField {
+	return []MetadataField{
+		{Key: "Words", Value: fmt.Sprint(stats.words)},
+		{Key: "Characters", Value: fmt.Sprint(stats.chars)},
+		{Key: "Characters (no spaces)", Value: fmt.Sprint(stats.charsNoSpaces)},
+		{Key: "Lines", Value: fmt.Sprint(stats.lines)},
+		{Key: "Paragraphs", Value: fmt.Sprint(stats.paragraphs)},
+		{Key: "Headings", Value: fmt.Sprint(stats.headings)},
+		{Key: "Links", Value: fmt.Sprint(stats.links)},
+		{Key: "Tags", Value: fmt.Sprint(stats.tags)},
+		{Key: "Focus time this week", Value: formatFocusDuration(stats.focusThisWeek)},
+	}
+}
+
+// noteStatsText formats the stats as plain "Label: value" lines, headed by
+// the note's path.
+func noteStatsText(title string, stats noteStats) string {
+	lines := []string{title}
+	for _, row := range noteStatsRows(stats) {
+		lines = append(lines, row.Key+": "+row.Value)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// openNoteStatsPopup computes stats for the current note and shows them.
+func (m *Model) openNoteStatsPopup() {
+	if m.mode != modeBrowse || m.currentFile == "" {
+		m.status = "Select a note first"
+		return
+	}
+	m.noteStats = computeNoteStats(m.currentNoteTextForMetrics())
+	m.noteStats.focusThisWeek = m.focusTimeSince(startOfWeek(time.Now()))
+	m.openOverlay(overlayNoteStats)
+	m.showHelp = false
+	m.status = "Note stats: Enter or y to copy, Esc to close"
+}
+
+// handleNoteStatsPopupKey routes key presses while the stats popup is
+// visible. Enter or `y` copies the stats and closes the popup.
+func (m *Model) handleNoteStatsPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	switch m.popupAction(msg) {
+	case actionPopupClose:
+		m.closeOverlay()
+		m.status = "Note stats closed"
+	case actionPopupSelect:
+		m.closeOverlay()
+		m.copyNoteStatsToClipboard()
+	}
+	return m, nil
+}
+
+// copyNoteStatsToClipboard copies the popup's stats as plain text.
+func (m *Model) copyNoteStatsToClipboard() {
+	if err := writeClipboard(noteStatsText(m.displayRelative(m.currentFile), m.noteStats)); err != nil {
+		m.setStatusError("Clipboard copy failed", err)
+		return
+	}
+	m.status = "Copied note stats"
+}
+
+// renderNoteStatsPopup draws the stats as aligned label/value rows.
+func (m *Model) renderNoteStatsPopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	lines := []string{
+		titleStyle.Render("Note Stats"),
+		mutedStyle.Render(truncate(m.displayRelative(m.currentFile), innerWidth)),
+		"",
+	}
+	rows := noteStatsRows(m.noteStats)
+	keyWidth := 0
+	for _, row := range rows {
+		keyWidth = max(keyWidth, utf8.RuneCountInString(row.Key))
+	}
+	for _, row := range rows {
+		key := row.Key + strings.Repeat(" ", keyWidth-utf8.RuneCountInString(row.Key))
+		lines = append(lines, truncate(key+"  "+row.Value, innerWidth))
+	}
+	lines = append(lines, "", mutedStyle.Render("Enter/y: copy stats  Esc: close"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const noteStatsFixture = `---
+title: Fixture
+tags: [writing, Drafts]
+---
+# Chapter one
+
+It was a dark night. See [docs](https://example.com) and [[Other Note]].
+Second line of the same paragraph #writing #plot.
+
+![cover](cover.png) is not a link; #12 is not a tag.
+
+## Notes
+
+` + "```" + `
+# not a heading [x](y) #code
+
+still code
+` + "```" + `
+`
+
+func TestComputeNoteStatsFixture(t *testing.T) {
+	stats := computeNoteStats(noteStatsFixture)
+	_, body := parseFrontmatterAndBody(noteStatsFixture)
+
+	checks := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"words", stats.words, len(strings.Fields(body))},
+		{"chars", stats.chars, len([]rune(body))},
+		{"chars without spaces", stats.charsNoSpaces, len([]rune(strings.Join(strings.Fields(body), "")))},
+		{"lines", stats.lines, 14},
+		{"paragraphs", stats.paragraphs, 3},
+		{"headings", stats.headings, 2},
+		{"links", stats.links, 2},
+		{"tags", stats.tags, 3},
+	}
+	for _, check := range checks {
+		if check.got != check.want {
+			t.Errorf("%s: expected %d, got %d", check.name, check.want, check.got)
+		}
+	}
+}
+
+func TestComputeNoteStatsEmpty(t *testing.T) {
+	if stats := computeNoteStats(""); stats != (noteStats{}) {
+		t.Fatalf("expected zero stats for empty note, got %+v", stats)
+	}
+}
+
+func TestComputeNoteStatsWordsAndCharacters(t *testing.T) {
+	stats := computeNoteStats("héllo  wörld\n")
+	if stats.words != 2 {
+		t.Fatalf("expected 2 words, got %d", stats.words)
+	}
+	if stats.chars != 13 {
+		t.Fatalf("expected 13 characters, got %d", stats.chars)
+	}
+	if stats.charsNoSpaces != 10 {
+		t.Fatalf("expected 10 characters without spaces, got %d", stats.charsNoSpaces)
+	}
+	if stats.lines != 1 || stats.paragraphs != 1 {
+		t.Fatalf("expected 1 line and 1 paragraph, got %+v", stats)
+	}
+}
+
+func TestNoteStatsTextListsEveryMetric(t *testing.T) {
+	text := noteStatsText("notes/a.md", noteStats{words: 3, chars: 10, charsNoSpaces: 8, lines: 2, paragraphs: 1, headings: 1, links: 4, tags: 2, focusThisWeek: 90 * time.Minute})
+	for _, want := range []string{
+		"notes/a.md\n",
+		"Words: 3\n",
+		"Characters: 10\n",
+		"Characters (no spaces): 8\n",
+		"Lines: 2\n",
+		"Paragraphs: 1\n",
+		"Headings: 1\n",
+		"Links: 4\n",
+		"Tags: 2\n",
+		"Focus time this week: 1h 30m\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in stats text:\n%s", want, text)
+		}
+	}
+}
+
+func TestOpenNoteStatsPopupRequiresNote(t *testing.T) {
+	m := &Model{mode: modeBrowse}
+	m.openNoteStatsPopup()
+	if m.overlay != overlayNone {
+		t.Fatalf("expected no popup without a note, got overlay %v", m.overlay)
+	}
+
+	m.currentFile = "/notes/a.md"
+	m.currentNoteContent = "one two three\n"
+	m.openNoteStatsPopup()
+	if m.overlay != overlayNoteStats || m.noteStats.words != 3 {
+		t.Fatalf("expected stats popup with 3 words, got overlay %v stats %+v", m.overlay, m.noteStats)
+	}
+}
@@ -31,6 +31,10 @@ var renameForMove = os.Rename
 var copyPathForMove = copyPathRecursive
 var removeSourceForMove = os.RemoveAll
 
+// welcomeNoteName is the file the welcome note is seeded into; the tutorial
+// action reopens (or recreates) it.
+const welcomeNoteName = "Welcome.md"
+
 // welcomeNote is the markdown content seeded into a new notes directory on
 // first run. It serves as both a quick-start guide and a smoke test that the
 // directory was created successfully.
@@ -46,20 +50,36 @@ const welcomeNote = "# Welcome to CLI Notes!\n\n" +
 	"- Enter/Right/l: Expand or collapse folder\n" +
 	"- Left/h: Collapse folder\n" +
 	"- g / G: Jump to top / bottom\n" +
+	"- [ / ]: Jump to first / last child of the selected folder\n" +
 	"- PgUp / PgDn: Scroll preview up / down one page\n" +
 	"- Ctrl+U / Ctrl+D: Scroll preview up / down half page\n" +
 	"- Ctrl+P: Open search popup\n" +
+	"- /: Filter the tree as you type (Enter keeps, Esc clears)\n" +
 	"- Ctrl+O: Open recent files popup\n" +
-	"- Ctrl+W: Open workspace popup\n" +
+	"- Ctrl+W: Open workspace popup (a adds, d removes a workspace)\n" +
 	"- o: Open heading outline popup\n" +
 	"- x: Open export popup\n" +
+	"- H: Convert headings to title or sentence case\n" +
 	"- Shift+L: Open wiki links popup\n" +
+	"- i: Show the note's frontmatter metadata\n" +
+	"- Shift+M: Toggle the metadata strip under the preview header\n" +
+	"- w: Show detailed note stats (copy with Enter)\n" +
+	"- F: Start a focus session (F again pauses, Alt+F cancels)\n" +
+	"- F8 / Shift+F8: Jump to next / previous issue in the note (also when editing)\n" +
+	"- !: Open issues popup (unresolved wiki links, merge conflicts)\n" +
 	"- n: Create a new note\n" +
 	"- f: Create a new folder\n" +
 	"- e: Edit the selected note\n" +
+	"- J: Open (or create) today's journal entry\n" +
+	"- C: Open the agenda of notes dated today / this week / next week\n" +
+	"- Alt+I: Import markdown notes from another folder\n" +
+	"- Alt+G: Initialize git in the notes directory\n" +
+	"- F1: Reopen this tutorial\n" +
 	"- r: Rename the selected item\n" +
 	"- m: Move the selected item\n" +
-	"- d: Delete the selected note/folder (with confirmation)\n" +
+	"- D: Duplicate the selected note or folder\n" +
+	"- d: Move the selected note/folder to the trash (with confirmation)\n" +
+	"- Ctrl+T: Restore items from the trash\n" +
 	"- Shift+R or Ctrl+R: Refresh the directory tree\n" +
 	"- z: Toggle split mode (two notes)\n" +
 	"- Tab: Toggle split focus\n" +
@@ -73,11 +93,28 @@ const welcomeNote = "# Welcome to CLI Notes!\n\n" +
 	"- Ctrl+B / Alt+I / Ctrl+U / Alt+X: Toggle bold/italic/underline/strikethrough on selection/word (when editing)\n" +
 	"- Ctrl+K: Insert [text](url) link template (when editing)\n" +
 	"- Ctrl+1/2/3: Toggle heading level on current line (when editing)\n" +
+	"- Ctrl+T: Insert a table, or align the table under the cursor (when editing)\n" +
+	"- Alt+T: Format the table under the cursor; Tab/Shift+Tab move between its cells (when editing)\n" +
+	"- Alt+=: Evaluate the selected expression or the one left of the cursor (when editing)\n" +
+	"- Alt++: Sum the last number on each selected line (when editing)\n" +
+	"- Enter: Continue a markdown list; on an empty item, end the list (when editing)\n" +
+	"- Ctrl+Space / Alt+D: Toggle the task checkbox on the current line (when editing)\n" +
+	"- Tab: Accept wiki autocomplete when open, next table cell in a table, otherwise indent 4 spaces (when editing)\n" +
 	"- Ctrl+V: Paste from clipboard (when editing)\n" +
 	"- Type [[ in edit mode for wiki note-name autocomplete\n" +
 	"- y / Y: Copy current note content / path to clipboard\n" +
 	"- s: Cycle tree sort mode (name/modified/size/created)\n" +
+	"- S: Reverse tree sort direction\n" +
+	"- Alt+S: Toggle a sort override for the selected folder\n" +
 	"- t: Pin/unpin selected item\n" +
+	"- b: Toggle file sizes in the tree\n" +
+	"- A: Archive the selected item (again inside archive/ to restore)\n" +
+	"- a: Show/hide the archive folder\n" +
+	"- I: Process the inbox (move notes, turn inbox.md bullets into notes)\n" +
+	"- #: Edit tags of the selected note\n" +
+	"- Ctrl+G: Git panel (changed files, commit/pull/push) when notes are in a git repo\n" +
+	"- v: Show the git diff of the current note (Tab toggles staged)\n" +
+	"- V: Browse the git history of the current note\n" +
 	"- Esc: Cancel (when naming or editing)\n" +
 	"- q or Ctrl+C: Quit the application\n\n" +
 	"## Getting Started\n\n" +
@@ -86,16 +123,17 @@ const welcomeNote = "# Welcome to CLI Notes!\n\n" +
 	"3. Press f to create folders and organize your notes\n\n" +
 	"Happy note-taking!\n"
 
-// ensureNotesDir creates the notes directory if it does not exist and seeds
-// it with a Welcome.md note when the directory is empty. This is called
-// during app initialization to guarantee the filesystem is ready.
-func ensureNotesDir(notesDir string) error {
+// ensureNotesDir creates the notes directory if it does not exist and, when
+// seedWelcome is set (seed_welcome_note), seeds it with a Welcome.md note
+// when the directory is empty. This is called during app initialization to
+// guarantee the filesystem is ready.
+func ensureNotesDir(notesDir string, seedWelcome bool) error {
 	if err := os.MkdirAll(notesDir, DirPermission); err != nil {
 		return fmt.Errorf("create notes directory %q: %w", notesDir, err)
 	}
 
-	if isDirEmpty(notesDir) {
-		welcomePath := filepath.Join(notesDir, "Welcome.md")
+	if seedWelcome && isDirEmpty(notesDir) {
+		welcomePath := filepath.Join(notesDir, welcomeNoteName)
 		if err := os.WriteFile(welcomePath, []byte(normalizeNoteContent(welcomeNote)), FilePermission); err != nil {
 			return fmt.Errorf("seed welcome note %q: %w", welcomePath, err)
 		}
@@ -148,8 +186,14 @@ func (m *Model) configureInputForMode(mode mode, placeholder string) {
 // startNewNote switches to new-note mode and configures the input.
 func (m *Model) startNewNote() {
 	m.selectedTemplate = nil
-	m.templates = m.loadTemplates()
+	m.templateChosen = false
 	m.templateCursor = 0
+	if folderTemplate, ok := m.folderDefaultTemplate(m.selectedParentDir()); ok {
+		m.configureInputForMode(modeNewNote, "Note name (without .md extension)")
+		m.status = "Using folder template: " + folderTemplate.name + " (Tab to choose another)"
+		return
+	}
+	m.templates = m.loadTemplates()
 	if len(m.templates) > 0 {
 		m.mode = modeTemplatePicker
 		m.status = "Choose a template for the new note"
@@ -220,28 +264,46 @@ func (m *Model) startMoveSelected() {
 	m.status = "Move: Enter or Ctrl+S to save, Esc to cancel"
 }
 
-// startEditNote loads the current file and opens the editor.
+// startEditNote loads the focused pane's note and opens the editor. In split
+// mode with the secondary pane focused, the editor is bound to that pane.
 func (m *Model) startEditNote() (tea.Model, tea.Cmd) {
-	if m.currentFile == "" {
+	path, _ := m.activePreviewTarget()
+	if path == "" {
 		m.status = "No note selected"
 		return m, nil
 	}
 
-	content, err := os.ReadFile(m.currentFile)
+	if isEncryptedNotePath(path) && !m.noteKeys.unlocked() {
+		return m.withNotePassphrase(path, true, m.startEditNote)
+	}
+
+	content, raw, err := m.readNoteText(path)
 	if err != nil {
-		m.setStatusError("Error reading note", err, "path", m.currentFile)
+		if raw != nil {
+			m.reportNoteDecryptError(path, err)
+			return m, nil
+		}
+		m.setStatusError("Error reading note", err, "path", path)
 		return m, nil
 	}
 
+	meta, _ := parseFrontmatterAndBody(content)
+	m.bindEditorToFocusedPane()
 	m.mode = modeEditNote
 	m.showHelp = false
 	m.clearEditorSelection()
 	m.resetEditHistory()
-	m.editor.SetValue(string(content))
-	m.currentNoteContent = string(content)
+	m.editorNoWrap = meta.EditorNoWrap
+	m.editorHScroll = 0
+	m.setEditorValue(content)
+	m.currentNoteContent = content
+	m.editBaseHash = contentHash(raw)
 	m.restoreEditorCursor(m.currentFile)
 	m.editor.Focus()
 	m.status = "Editing " + filepath.Base(m.currentFile)
+	if m.editorNoWrap {
+		m.status += " (wrap off)"
+	}
 	return m, nil
 }
 
@@ -255,6 +317,12 @@ func (m *Model) saveNewNote() (tea.Model, tea.Cmd) {
 	if !strings.HasSuffix(strings.ToLower(name), ".md") {
 		name += ".md"
 	}
+	if slash := strings.LastIndex(filepath.ToSlash(name), "/"); slash >= 0 {
+		if status := validateFolderLevels(filepath.ToSlash(name)[:slash]); status != "" {
+			m.status = status
+			return m, nil
+		}
+	}
 
 	path := filepath.Join(m.newParent, name)
 	if !isWithinRoot(m.notesDir, path) {
@@ -263,40 +331,126 @@ func (m *Model) saveNewNote() (tea.Model, tea.Cmd) {
 	}
 	content := m.defaultNewNoteContent(name)
 	if m.selectedTemplate != nil {
-		content = m.selectedTemplate.content
+		content = mergeTemplateDefaults(m.selectedTemplate.content, content)
+	} else if folderTemplate, ok := m.folderDefaultTemplate(filepath.Dir(path)); ok && !m.templateChosen {
+		resolved, ok := m.resolveNoteTemplate(*folderTemplate)
+		if !ok {
+			return m, nil
+		}
+		content = mergeTemplateDefaults(resolved, content)
+	}
+	content = expandTemplateVariables(content, m.newNoteTemplateVars(name))
+	if m.frontmatterOnCreate {
+		if _, body := parseFrontmatterAndBody(content); body == content {
+			content = defaultFrontmatter(name) + content
+		}
+	}
+	content, autoTagStatus := m.autoTagNewNote(content, path)
+	if m.frontmatterTimestamps {
+		content = stampFrontmatterTime(content, "created")
 	}
-	if err := os.WriteFile(path, []byte(normalizeNoteContent(content)), FilePermission); err != nil {
-		m.setStatusError("Error creating note", err, "path", path)
+	content, cursor := extractTemplateCursor(content)
+	if m.rejectWhilePending(path, "creating it again") {
+		return m, nil
+	}
+	if _, err := os.Lstat(path); err == nil {
+		m.status = "Note already exists: " + m.displayRelative(path)
+		return m, nil
+	}
+	var createdDirs []string
+	if m.createMissingDirs {
+		createdDirs = missingDirs(m.notesDir, filepath.Dir(path))
+	}
+	if status, collides := firstCaseCollision("", append(createdDirs, path)...); collides {
+		m.status = status
 		return m, nil
 	}
 
+	// The note shows up in the tree now; the write and the index update
+	// finish in handlePendingOpDone (optimistic_ops.go).
+	op := &pendingOp{
+		kind:           pendingCreate,
+		path:           path,
+		input:          m.input.Value(),
+		doneStatus:     "Created note: " + name + autoTagStatus,
+		parent:         m.newParent,
+		createdDirs:    createdDirs,
+		editorCursor:   cursor,
+		template:       m.selectedTemplate,
+		templateChosen: m.templateChosen,
+	}
+	mkdir := m.createMissingDirs
+	data := []byte(normalizeNoteContent(content))
+	cmd := m.startPendingOp(op, func() (string, error) {
+		if mkdir {
+			if err := mkdirAllForCreate(filepath.Dir(path), DirPermission); err != nil {
+				return "Error creating folder", err
+			}
+		}
+		if err := writeFileForCreate(path, data, FilePermission); err != nil {
+			return "Error creating note", err
+		}
+		return "", nil
+	})
+
 	m.mode = modeBrowse
-	m.status = "Created note: " + name
-	m.expanded[m.newParent] = true
+	m.status = "Creating note: " + name + "…"
+	m.expandForPendingOp(op, append([]string{m.newParent}, createdDirs...)...)
 	m.selectedTemplate = nil
-	m.invalidateTreeMetadataPath(path)
-	cmd := m.applyMutationEffects(mutationEffects{
-		upsertPaths:    []string{path},
-		refreshTree:    true,
-		refreshGit:     true,
-		setCurrentFile: path,
-	})
+	m.templateChosen = false
+	m.insertPendingRows(op)
 	return m, cmd
 }
 
+// missingDirs returns the folders between root and dir (inclusive of dir) that
+// do not exist yet, outermost first. Paths outside root yield nil.
+func missingDirs(root, dir string) []string {
+	var missing []string
+	for dir != root && isWithinRoot(root, dir) {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		missing = append([]string{dir}, missing...)
+		dir = filepath.Dir(dir)
+	}
+	return missing
+}
+
 // saveNewFolder creates a directory and refreshes the tree.
+//
+// A nested name such as "a/b/c" (a trailing slash is ignored) creates every
+// missing level when create_missing_dirs is on; with it off only the last
+// level may be new. Each created level is expanded and indexed, and the
+// deepest one is selected.
 func (m *Model) saveNewFolder() (tea.Model, tea.Cmd) {
-	name := strings.TrimSpace(m.input.Value())
+	name := strings.Trim(filepath.ToSlash(strings.TrimSpace(m.input.Value())), "/")
 	if name == "" {
 		m.status = "Folder name is required"
 		return m, nil
 	}
+	if status := validateFolderLevels(name); status != "" {
+		m.status = status
+		return m, nil
+	}
 
-	path := filepath.Join(m.newParent, name)
+	path := filepath.Join(m.newParent, filepath.FromSlash(name))
 	if !isWithinRoot(m.notesDir, path) {
 		m.status = "Invalid folder name"
 		return m, nil
 	}
+	created := missingDirs(m.notesDir, path)
+	if len(created) == 0 {
+		m.status = "Folder already exists: " + m.displayRelative(path)
+		return m, nil
+	}
+	if len(created) > 1 && !m.createMissingDirs {
+		m.status = "Parent folder does not exist: " + m.displayRelative(created[0]) + " (create_missing_dirs is off)"
+		return m, nil
+	}
+	if status, collides := firstCaseCollision("", created...); collides {
+		m.status = status
+		return m, nil
+	}
 	if err := os.MkdirAll(path, DirPermission); err != nil {
 		m.setStatusError("Error creating folder", err, "path", path)
 		return m, nil
@@ -304,16 +458,43 @@ func (m *Model) saveNewFolder() (tea.Model, tea.Cmd) {
 
 	m.mode = modeBrowse
 	m.status = "Created folder: " + name
+	if len(created) > 1 {
+		m.status = fmt.Sprintf("Created folders: %s (%d new levels)", name, len(created))
+	}
 	m.expanded[m.newParent] = true
-	m.invalidateTreeMetadataPath(path)
+	for _, dir := range created {
+		m.expanded[dir] = true
+		m.invalidateTreeMetadataPath(dir)
+	}
 	cmd := m.applyMutationEffects(mutationEffects{
-		upsertPaths: []string{path},
-		refreshTree: true,
-		refreshGit:  true,
+		upsertPaths:     created,
+		refreshGit:      true,
+		rebuildKeepPath: path,
 	})
 	return m, cmd
 }
 
+// validateFolderLevels checks each slash-separated level of a new folder
+// name (or of the folder part of a new note's name) and returns a status
+// message for the first bad one, or "".
+func validateFolderLevels(name string) string {
+	for _, level := range strings.Split(name, "/") {
+		switch {
+		case strings.TrimSpace(level) == "":
+			return "Folder name has an empty level: " + name
+		case level == "." || level == "..":
+			return "Folder name cannot contain . or .. levels"
+		case level != strings.TrimSpace(level):
+			return "Folder levels cannot start or end with spaces: " + name
+		case shouldSkipManagedPath(level):
+			return level + " is reserved for app data"
+		case strings.EqualFold(level, ".git"):
+			return level + " is reserved for git"
+		}
+	}
+	return ""
+}
+
 // saveRenameItem validates the new name, performs the filesystem rename, and
 // updates all in-memory state (expanded paths, pinned paths, recent files,
 // note positions, search index, and git status) to reflect the new path.
@@ -345,32 +526,42 @@ func (m *Model) saveRenameItem() (tea.Model, tea.Cmd) {
 		m.status = "Invalid target name"
 		return m, nil
 	}
-	if _, err := os.Stat(newPath); err == nil {
+	if m.rejectWhilePending(oldPath, "rename") || m.rejectWhilePending(newPath, "rename") {
+		return m, nil
+	}
+	// On a case-insensitive filesystem a case-only rename finds the item
+	// itself here; that is not a collision.
+	caseOnly := m.caseInsensitiveFS && strings.EqualFold(oldPath, newPath)
+	if _, err := os.Stat(newPath); err == nil && !caseOnly {
 		m.status = "Target already exists"
 		return m, nil
 	}
-
-	if err := os.Rename(oldPath, newPath); err != nil {
-		m.setStatusError("Error renaming item", err, "from", oldPath, "to", newPath)
+	if status, collides := firstCaseCollision(oldPath, newPath); collides {
+		m.status = status
 		return m, nil
 	}
 
+	// The tree shows the new name now; the rename and the index update
+	// finish in handlePendingOpDone (optimistic_ops.go).
+	op := &pendingOp{
+		kind:       pendingRename,
+		path:       newPath,
+		oldPath:    oldPath,
+		input:      m.input.Value(),
+		doneStatus: "Renamed to: " + name,
+	}
+	cmd := m.startPendingOp(op, func() (string, error) {
+		if err := renameForRename(oldPath, newPath); err != nil {
+			return "Error renaming item", err
+		}
+		return "", nil
+	})
+
 	m.mode = modeBrowse
+	m.status = "Renaming to: " + name + "…"
 	m.remapExpandedPaths(oldPath, newPath)
-	m.remapStatePaths(oldPath, newPath)
-	m.remapTreeMetadataPath(oldPath, newPath)
+	m.renamePendingRows(oldPath, newPath)
 	m.currentFile = replacePathPrefix(m.currentFile, oldPath, newPath)
-	cmd := m.applyMutationEffects(mutationEffects{
-		removePaths:     []string{oldPath},
-		upsertPaths:     []string{newPath},
-		refreshGit:      true,
-		refreshTree:     true,
-		rebuildKeepPath: newPath,
-	})
-	m.status = "Renamed to: " + name
-	if m.currentFile != "" {
-		return m, m.setCurrentFile(m.currentFile)
-	}
 	return m, cmd
 }
 
@@ -414,6 +605,10 @@ func (m *Model) saveMoveItem() (tea.Model, tea.Cmd) {
 		m.status = "Destination already exists"
 		return m, nil
 	}
+	if status, collides := firstCaseCollision(oldPath, newPath); collides {
+		m.status = status
+		return m, nil
+	}
 
 	if err := movePathWithFallback(oldPath, newPath); err != nil {
 		m.setStatusError("Error moving item", err, "from", oldPath, "to", newPath)
@@ -447,27 +642,54 @@ func (m *Model) saveEdit() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 	m.finalizeTypingBurstBoundary()
-	content := normalizeNoteContent(m.editor.Value())
-	if err := os.WriteFile(m.currentFile, []byte(content), FilePermission); err != nil {
+	if m.rejectWhileLocked("saving") {
+		return m, nil
+	}
+	if m.editChangedOnDisk() {
+		m.enterEditConflict()
+		return m, nil
+	}
+	return m.writeEditedNote()
+}
+
+// writeEditedNote writes the editor buffer to the current note and returns
+// to browse mode.
+func (m *Model) writeEditedNote() (tea.Model, tea.Cmd) {
+	content := m.editor.Value()
+	if m.frontmatterTimestamps {
+		content = stampFrontmatterTime(content, "updated")
+	}
+	content = normalizeNoteContent(content)
+	data, err := m.noteFileBytes(m.currentFile, content)
+	if err != nil {
+		m.setStatusError("Error encrypting note", err, "path", m.currentFile)
+		return m, nil
+	}
+	if err := os.WriteFile(m.currentFile, data, FilePermission); err != nil {
 		m.setStatusError("Error saving note", err, "path", m.currentFile)
 		return m, nil
 	}
 
-	m.mode = modeBrowse
+	// Remembered while still editing so the cursor and its context are kept.
 	m.rememberNotePosition(m.currentFile)
+	m.mode = modeBrowse
 	m.clearEditorSelection()
 	m.currentNoteContent = content
+	m.refreshMetadataStrip()
 	m.clearDraftForPath(m.currentFile)
 	m.invalidateTreeMetadataPath(m.currentFile)
 	m.resetEditHistory()
+	m.editBaseHash = ""
 	m.status = "Saved: " + filepath.Base(m.currentFile)
+	saved := m.currentFile
+	renderCmd := m.endSplitSecondaryEdit()
 	cmd := m.applyMutationEffects(mutationEffects{
-		upsertPaths:    []string{m.currentFile},
+		upsertPaths:    []string{saved},
 		refreshGit:     true,
 		saveState:      true,
 		setCurrentFile: m.currentFile,
 	})
-	return m, cmd
+	return m, tea.Batch(renderCmd, cmd)
 }
 
 // normalizeNoteContent ensures notes always end with exactly one newline.
@@ -479,6 +701,18 @@ func (m *Model) defaultNewNoteContent(name string) string {
 	return fmt.Sprintf("# %s\n\nYour note content here...\n", strings.TrimSuffix(name, ".md"))
 }
 
+// defaultFrontmatter is the block frontmatter_on_create puts ahead of a new
+// note without one: its title, today's date as created, and an empty tags
+// list to fill in.
+func defaultFrontmatter(name string) string {
+	title := strings.TrimSuffix(filepath.Base(name), ".md")
+	return "---\n" +
+		frontmatterKeyTitle + ": " + quoteFrontmatterScalar(title, 0) + "\n" +
+		frontmatterKeyCreated + ": " + appNow().Format("2006-01-02") + "\n" +
+		frontmatterKeyTags + ": []\n" +
+		"---\n"
+}
+
 // validateDeleteTarget checks if the item can be deleted and returns an error message if not.
 func (m *Model) validateDeleteTarget(item *treeItem) string {
 	if item == nil {
@@ -490,15 +724,20 @@ func (m *Model) validateDeleteTarget(item *treeItem) string {
 	if !isWithinRoot(m.notesDir, item.path) {
 		return "Cannot delete item outside notes directory"
 	}
-	if item.isDir && !isDirEmpty(item.path) {
+	if m.hardDelete && item.isDir && !isDirEmpty(item.path) {
 		return "Folder is not empty. Delete contents first."
 	}
 	return ""
 }
 
-// performDelete executes the deletion and updates state.
+// performDelete executes the deletion and updates state. Unless hard_delete
+// is configured, the item is moved to the trash instead of being removed.
 func (m *Model) performDelete(item *treeItem) {
-	if err := os.Remove(item.path); err != nil {
+	remove := m.moveToTrash
+	if m.hardDelete {
+		remove = os.Remove
+	}
+	if err := remove(item.path); err != nil {
 		itemType := "file"
 		if item.isDir {
 			itemType = "folder"
@@ -508,14 +747,17 @@ func (m *Model) performDelete(item *treeItem) {
 	}
 
 	// Update status message
-	if item.isDir {
+	switch {
+	case !m.hardDelete:
+		m.status = "Moved to trash: " + item.name + " (" + m.primaryActionKey(actionTrash, "Ctrl+T") + " to restore)"
+	case item.isDir:
 		m.status = "Deleted folder: " + item.name
-	} else {
+	default:
 		m.status = "Deleted: " + item.name
 	}
 
-	// Clear viewport if we deleted the current file
-	if item.path == m.currentFile {
+	// Clear viewport if we deleted the current file (or the folder holding it)
+	if m.currentFile != "" && isWithinRoot(item.path, m.currentFile) {
 		m.clearStateForPath(item.path)
 		m.currentFile = ""
 		m.viewport.SetContent("Select a note to view")
@@ -545,7 +787,11 @@ func (m *Model) deleteSelected() {
 	if item.isDir {
 		targetType = "folder"
 	}
-	m.status = fmt.Sprintf("Delete %s \"%s\"? (y/N)", targetType, item.name)
+	if m.hardDelete {
+		m.status = fmt.Sprintf("Delete %s \"%s\"? (y/N)", targetType, item.name)
+		return
+	}
+	m.status = fmt.Sprintf("Move %s \"%s\" to trash? (y/N)", targetType, item.name)
 }
 
 // displayRelative shows paths relative to the notes root for UI display.
@@ -38,7 +38,7 @@ func TestEnsureNotesDirPermissionError(t *testing.T) {
 	defer os.Chmod(readOnlyDir, 0o755) // cleanup
 
 	subdir := filepath.Join(readOnlyDir, "notes")
-	err := ensureNotesDir(subdir)
+	err := ensureNotesDir(subdir, true)
 	if err == nil {
 		t.Fatal("expected error when creating directory in read-only parent")
 	}
@@ -61,7 +61,7 @@ func TestEnsureNotesDirWelcomeFileWriteError(t *testing.T) {
 	defer os.Chmod(root, 0o755) // cleanup
 
 	logs := captureLogOutput(t, func() {
-		err := ensureNotesDir(root)
+		err := ensureNotesDir(root, true)
 		if err == nil {
 			t.Error("expected error when writing welcome file to read-only directory")
 		}
@@ -140,7 +140,7 @@ func TestSaveNewNoteWriteError(t *testing.T) {
 	}
 
 	logs := captureLogOutput(t, func() {
-		result, _ := m.saveNewNote()
+		result, _ := settlePendingOp(m.saveNewNote())
 		resultModel := result.(*Model)
 
 		if resultModel.status != "Error creating note" {
@@ -440,7 +440,7 @@ func TestSaveNewNoteSuccessLogsNoErrors(t *testing.T) {
 	}
 
 	logs := captureLogOutput(t, func() {
-		result, _ := m.saveNewNote()
+		result, _ := settlePendingOp(m.saveNewNote())
 		resultModel := result.(*Model)
 
 		if resultModel.mode != modeBrowse {
@@ -457,6 +457,141 @@ func TestSaveNewNoteSuccessLogsNoErrors(t *testing.T) {
 	}
 }
 
+func TestSaveNewNoteCreatesMissingIntermediateFolders(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.createMissingDirs = true
+	m.newParent = root
+	m.input.SetValue("a/b/note")
+
+	model, _ := settlePendingOp(m.saveNewNote())
+	m = model.(*Model)
+
+	notePath := filepath.Join(root, "a", "b", "note.md")
+	if _, err := os.Stat(notePath); err != nil {
+		t.Fatalf("expected note to be created: %v", err)
+	}
+	for _, dir := range []string{filepath.Join(root, "a"), filepath.Join(root, "a", "b")} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			t.Fatalf("expected folder %q to be created: %v", dir, err)
+		}
+		if !m.expanded[dir] {
+			t.Fatalf("expected created folder %q to be expanded", dir)
+		}
+		assertTreeHasPath(t, m.items, dir)
+	}
+	assertTreeHasPath(t, m.items, notePath)
+	if m.currentFile != notePath {
+		t.Fatalf("expected current file %q, got %q", notePath, m.currentFile)
+	}
+}
+
+func TestSaveNewFolderCreatesExpandsAndSelectsNestedLevels(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.mode = modeNewFolder
+	m.createMissingDirs = true
+	m.newParent = root
+	m.input.SetValue("a/b/c/")
+
+	_, _ = m.saveNewFolder()
+
+	levels := []string{filepath.Join(root, "a"), filepath.Join(root, "a", "b"), filepath.Join(root, "a", "b", "c")}
+	for _, dir := range levels {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			t.Fatalf("expected folder %q to be created: %v", dir, err)
+		}
+		if !m.expanded[dir] {
+			t.Fatalf("expected created folder %q to be expanded", dir)
+		}
+		assertTreeHasPath(t, m.items, dir)
+		if _, ok := m.searchIndex.docs[dir]; !ok {
+			t.Fatalf("expected folder %q to be indexed", dir)
+		}
+	}
+	if got := m.selectedPath(); got != levels[2] {
+		t.Fatalf("expected deepest folder selected, got %q", got)
+	}
+	if m.mode != modeBrowse || m.status != "Created folders: a/b/c (3 new levels)" {
+		t.Fatalf("unexpected mode %v status %q", m.mode, m.status)
+	}
+
+	m.mode = modeNewFolder
+	m.input.SetValue("a/b")
+	_, _ = m.saveNewFolder()
+	if m.mode != modeNewFolder || m.status != "Folder already exists: a/b" {
+		t.Fatalf("expected existing folder to be refused, got %q", m.status)
+	}
+}
+
+func TestSaveNewFolderValidatesEachLevel(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.mode = modeNewFolder
+	m.newParent = root
+
+	for input, want := range map[string]string{
+		"a//b":       "Folder name has an empty level: a//b",
+		"a/../b":     "Folder name cannot contain . or .. levels",
+		"a/ b":       "Folder levels cannot start or end with spaces: a/ b",
+		".cli-notes": ".cli-notes is reserved for app data",
+		"a/.git":     ".git is reserved for git",
+		"x/y":        "Parent folder does not exist: x (create_missing_dirs is off)",
+	} {
+		m.input.SetValue(input)
+		_, _ = m.saveNewFolder()
+		if m.status != want {
+			t.Fatalf("%q: status = %q, want %q", input, m.status, want)
+		}
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 0 {
+		t.Fatalf("expected nothing created, got %d entries", len(entries))
+	}
+}
+
+func TestSaveNewNoteValidatesFolderLevels(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.createMissingDirs = true
+
+	for input, want := range map[string]string{
+		"x/.git/y":          ".git is reserved for git",
+		".cli-notes/sneaky": ".cli-notes is reserved for app data",
+		"a//b":              "Folder name has an empty level: a/",
+		"a/../b":            "Folder name cannot contain . or .. levels",
+	} {
+		m.mode = modeNewNote
+		m.newParent = root
+		m.input.SetValue(input)
+		_, cmd := m.saveNewNote()
+		if cmd != nil || m.status != want {
+			t.Fatalf("%q: status = %q, want %q", input, m.status, want)
+		}
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 0 {
+		t.Fatalf("expected nothing created, got %d entries", len(entries))
+	}
+}
+
+func TestSaveNewNoteWithoutCreateMissingDirsFails(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.newParent = root
+	m.input.SetValue("a/b/note")
+
+	captureLogOutput(t, func() {
+		model, _ := settlePendingOp(m.saveNewNote())
+		m = model.(*Model)
+	})
+
+	if m.status != "Error creating note" {
+		t.Fatalf("expected write error status, got %q", m.status)
+	}
+	if _, err := os.Stat(filepath.Join(root, "a")); !os.IsNotExist(err) {
+		t.Fatalf("expected no folders to be created, got err=%v", err)
+	}
+}
+
 func TestSetStatusErrorLogsWithAttributes(t *testing.T) {
 	m := &Model{}
 
@@ -528,7 +663,8 @@ func TestDeleteSelectedNonEmptyFolder(t *testing.T) {
 	}
 
 	m := &Model{
-		notesDir: root,
+		notesDir:   root,
+		hardDelete: true,
 		items: []treeItem{
 			{path: folder, name: "nonempty", isDir: true},
 		},
@@ -562,7 +698,7 @@ func TestSaveRenameItemUpdatesCurrentFile(t *testing.T) {
 		input:       input,
 	}
 
-	result, _ := m.saveRenameItem()
+	result, _ := settlePendingOp(m.saveRenameItem())
 	got := result.(*Model)
 
 	if got.mode != modeBrowse {
@@ -688,7 +824,7 @@ func TestNoteCRUDLifecycleIntegration(t *testing.T) {
 			m.input.SetValue(tc.noteName)
 			m.selectedTemplate = &noteTemplate{content: tc.initial}
 
-			model, _ := m.saveNewNote()
+			model, _ := settlePendingOp(m.saveNewNote())
 			m = model.(*Model)
 			notePath := filepath.Join(parent, tc.noteName)
 			if got := m.currentFile; got != notePath {
@@ -0,0 +1,132 @@
+// op_lock.go implements the operation interlock that keeps the notes tree
+// still while an exclusive background job (git pull, push, or commit) runs.
+//
+// Git operations run off the UI goroutine. While one is in flight, actions
+// that change notes on disk (create, save, rename, move, delete, archive,
+// folder toggles, tag and frontmatter edits, workspace switches, and further
+// git operations) are rejected with a status naming the job, so a delete cannot
+// race a pull's checkout and the search index never receives upserts for
+// paths git is about to rewrite. Navigation, preview, search, and other read-only actions stay
+// available. The footer shows a LOCK segment while the interlock is held.
+//
+// The interlock is released when the job's result message arrives, or by a
+// timeout tick after GitOperationTimeout so a hung remote cannot lock the
+// app for good. A result arriving after its timeout is still applied, but
+// never releases a newer holder.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// opLock is held by at most one exclusive background job.
+type opLock struct {
+	// reason names the job in the footer and in rejection statuses
+	// (e.g. "git pull"); empty when the lock is free.
+	reason string
+	// token identifies the holder so stale results and timeouts from an
+	// earlier job cannot release a newer one.
+	token int
+	// deadline is when the lock is released even without a result.
+	deadline time.Time
+}
+
+// opLockTimeoutMsg releases the interlock held with token if it is still
+// held when the timeout fires.
+type opLockTimeoutMsg struct {
+	token int
+}
+
+// interlockedActions maps browse actions that modify notes on disk to the
+// word used for them in rejection statuses.
+var interlockedActions = map[string]string{
+	actionNewNote:         "create",
+	actionNewFolder:       "create",
+	actionDailyNote:       "create",
+	actionTutorial:        "create",
+	actionImport:          "import",
+	actionInbox:           "inbox processing",
+	actionRename:          "rename",
+	actionMove:            "move",
+	actionDuplicate:       "duplicate",
+	actionDelete:          "delete",
+	actionTrash:           "trash",
+	actionArchive:         "archive",
+	actionToggleFolder:    "folder toggle",
+	actionEditTags:        "tag edits",
+	actionEditFrontmatter: "frontmatter edits",
+	actionHeadingCase:     "heading rewrites",
+	actionEncryptToggle:   "encryption",
+	actionWorkspace:       "workspace switch",
+	actionGitInit:         "git init",
+	actionGitCommit:       "git commit",
+	actionGitPull:         "git pull",
+	actionGitPush:         "git push",
+}
+
+// opLocked reports whether an exclusive job holds the interlock, releasing
+// it first when its deadline has passed.
+func (m *Model) opLocked() bool {
+	if m.opLock.reason == "" {
+		return false
+	}
+	if !appNow().Before(m.opLock.deadline) {
+		m.expireOpLock()
+		return false
+	}
+	return true
+}
+
+// acquireOpLock takes the interlock for reason and returns the holder token
+// and the command that enforces the timeout.
+func (m *Model) acquireOpLock(reason string) (int, tea.Cmd) {
+	m.opLock.token++
+	m.opLock.reason = reason
+	m.opLock.deadline = appNow().Add(GitOperationTimeout)
+	token := m.opLock.token
+	return token, tea.Tick(GitOperationTimeout, func(time.Time) tea.Msg {
+		return opLockTimeoutMsg{token: token}
+	})
+}
+
+// releaseOpLock frees the interlock if token still holds it.
+func (m *Model) releaseOpLock(token int) {
+	if m.opLock.token == token {
+		m.opLock.reason = ""
+	}
+}
+
+// handleOpLockTimeout releases a lock whose job has not reported back.
+func (m *Model) handleOpLockTimeout(msg opLockTimeoutMsg) (tea.Model, tea.Cmd) {
+	if m.opLock.token == msg.token && m.opLock.reason != "" {
+		m.expireOpLock()
+	}
+	return m, nil
+}
+
+func (m *Model) expireOpLock() {
+	appLog.Warn("operation interlock timed out", "operation", m.opLock.reason, "timeout", GitOperationTimeout)
+	m.status = fmt.Sprintf("%s is still running after %s; changes are allowed again", capitalizeFirst(m.opLock.reason), GitOperationTimeout)
+	m.opLock.reason = ""
+}
+
+// rejectWhileLocked reports whether what must wait for the in-flight job,
+// and if so says so in the status bar.
+func (m *Model) rejectWhileLocked(what string) bool {
+	if !m.opLocked() {
+		return false
+	}
+	m.status = fmt.Sprintf("%s in progress: %s is unavailable until it finishes", capitalizeFirst(m.opLock.reason), what)
+	return true
+}
+
+// opLockFooterSegment shows the held interlock in the footer.
+func (m *Model) opLockFooterSegment() string {
+	if m.opLock.reason == "" {
+		return ""
+	}
+	return "LOCK " + m.opLock.reason
+}
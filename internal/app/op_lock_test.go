@@ -0,0 +1,153 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func TestDeleteDuringSlowPullIsRejectedUntilPullFinishes(t *testing.T) {
+	root := t.TempDir()
+	a := filepath.Join(root, "a.md")
+	b := filepath.Join(root, "b.md")
+	mustWriteFile(t, a, "# A\n")
+	mustWriteFile(t, b, "# B\n")
+	release := make(chan struct{})
+	withGitRun(t, func(_ string, args ...string) (string, error) {
+		switch args[0] {
+		case "rev-parse":
+			return "true", nil
+		case "pull":
+			<-release
+			return "Already up to date.", nil
+		}
+		return "", nil
+	})
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.loadKeybindings(config.Config{})
+	m.git.isRepo = true
+	selectTreePath(t, m, a)
+
+	_, cmd := m.handleBrowseKey("p")
+	results := runCmdsInBackground(cmd)
+	if got := m.opLockFooterSegment(); got != "LOCK git pull" {
+		t.Fatalf("footer segment = %q", got)
+	}
+
+	_, _ = m.handleBrowseKey("d")
+	if m.mode != modeBrowse || !strings.Contains(m.status, "Git pull in progress: delete") {
+		t.Fatalf("expected delete to be rejected, mode %v status %q", m.mode, m.status)
+	}
+	_, _ = m.handleBrowseKey("p")
+	if !strings.Contains(m.status, "Git pull in progress: git pull") {
+		t.Fatalf("expected a second pull to be rejected, status %q", m.status)
+	}
+	_, _ = m.handleBrowseKey("j")
+	if m.selectedPath() != b {
+		t.Fatalf("expected navigation to stay available, selected %q", m.selectedPath())
+	}
+	if _, err := os.Stat(a); err != nil {
+		t.Fatalf("expected note to survive, stat err = %v", err)
+	}
+
+	close(release)
+	select {
+	case msg := <-results:
+		_, _ = m.Update(msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("pull never finished")
+	}
+	if m.opLocked() || m.opLockFooterSegment() != "" {
+		t.Fatal("expected the interlock to be released")
+	}
+	if m.status != "Git pull: Already up to date." {
+		t.Fatalf("status = %q", m.status)
+	}
+	_, _ = m.handleBrowseKey("d")
+	if m.mode != modeConfirmDelete {
+		t.Fatalf("expected delete confirmation after the pull, got mode %v", m.mode)
+	}
+}
+
+func TestSaveDuringGitOperationKeepsEditor(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "a.md")
+	mustWriteFile(t, note, "# A\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.currentFile = note
+	_, _ = m.startEditNote()
+	m.setEditorValue("# A\n\nchanged\n")
+
+	m.acquireOpLock("git commit")
+	_, _ = m.saveEdit()
+	if m.mode != modeEditNote || !strings.Contains(m.status, "Git commit in progress: saving") {
+		t.Fatalf("expected save to be rejected, mode %v status %q", m.mode, m.status)
+	}
+	if data, _ := os.ReadFile(note); string(data) != "# A\n" {
+		t.Fatalf("note changed on disk: %q", data)
+	}
+}
+
+func TestOpLockTimeoutReleasesOnlyItsHolder(t *testing.T) {
+	m := &Model{}
+	first, _ := m.acquireOpLock("git push")
+	second, _ := m.acquireOpLock("git pull")
+
+	_, _ = m.handleOpLockTimeout(opLockTimeoutMsg{token: first})
+	m.releaseOpLock(first)
+	if !m.opLocked() {
+		t.Fatal("expected a stale timeout and result not to release the newer lock")
+	}
+
+	_, _ = m.handleOpLockTimeout(opLockTimeoutMsg{token: second})
+	if m.opLocked() || !strings.HasPrefix(m.status, "Git pull is still running") {
+		t.Fatalf("expected timeout release, status %q", m.status)
+	}
+
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	stubNow(t, func() time.Time { return now })
+	m.acquireOpLock("git pull")
+	now = now.Add(GitOperationTimeout)
+	if m.opLocked() {
+		t.Fatal("expected the lock to expire at its deadline")
+	}
+}
+
+func withGitRun(t *testing.T, run func(dir string, args ...string) (string, error)) {
+	t.Helper()
+	prev := gitRun
+	gitRun = run
+	t.Cleanup(func() { gitRun = prev })
+}
+
+// runCmdsInBackground runs cmd and every command of a batch in goroutines
+// and delivers their messages; timers that never fire are left running.
+func runCmdsInBackground(cmd tea.Cmd) <-chan tea.Msg {
+	out := make(chan tea.Msg, 8)
+	var run func(tea.Cmd)
+	run = func(c tea.Cmd) {
+		if c == nil {
+			return
+		}
+		go func() {
+			msg := c()
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				for _, sub := range batch {
+					run(sub)
+				}
+				return
+			}
+			out <- msg
+		}()
+	}
+	run(cmd)
+	return out
+}
@@ -0,0 +1,384 @@
+// optimistic_ops.go makes creating and renaming notes feel instant on slow
+// filesystems.
+//
+// saveNewNote and saveRenameItem validate synchronously and then apply their
+// result to the in-memory tree right away: a new note is inserted under its
+// folder and selected, and a renamed item (with any visible descendants)
+// takes its new path. The row carries a muted "…" marker while the
+// filesystem write runs in a tea.Cmd. When its pendingOpDoneMsg arrives the
+// marker clears and the usual mutation effects run on the UI goroutine
+// (search index upsert, git status, tree rebuild, state remaps).
+//
+// If the write fails, only that operation's own change is rolled back, so
+// other operations started since keep theirs: its tree rows are reverted,
+// folders it expanded are collapsed again unless another pending item sits
+// inside them, and the selection and currentFile are moved back only while
+// they still point at its item. The error is shown and the name input
+// reopens with the typed name so the user can retry without retyping.
+//
+// While an operation is pending, actions on the same item or inside a
+// pending folder (rename, move, delete, edit, ...) are rejected, and no
+// second create or rename may target it. Git pull, push, and commit wait for
+// every pending write, so a pull cannot race it and a commit cannot miss its
+// path; a due auto-commit runs once the last one finishes.
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Filesystem hooks used by the pending operations. Tests replace them to
+// inject failures.
+var (
+	mkdirAllForCreate  = os.MkdirAll
+	writeFileForCreate = os.WriteFile
+	renameForRename    = os.Rename
+)
+
+// pendingOpKind is the kind of optimistic operation.
+type pendingOpKind int
+
+const (
+	pendingCreate pendingOpKind = iota
+	pendingRename
+)
+
+// pendingOp is an optimistic create or rename whose filesystem write has
+// not reported back yet.
+type pendingOp struct {
+	id   int
+	kind pendingOpKind
+	// path is the created note or the rename target; oldPath is the rename
+	// source.
+	path    string
+	oldPath string
+	// input is the typed name, restored into the input on failure.
+	input string
+	// doneStatus is shown when the write succeeds.
+	doneStatus string
+
+	// Create details: the folder the note goes in, folders created on the
+	// way, the editor cursor from the template (-1 for none), and the
+	// template choice to restore on failure.
+	parent         string
+	createdDirs    []string
+	editorCursor   int
+	template       *noteTemplate
+	templateChosen bool
+
+	// The selection and scroll offset before the optimistic change, and the
+	// folders it expanded.
+	selected      string
+	treeOffset    int
+	expandedAdded []string
+}
+
+// pendingOpDoneMsg reports the result of a pending operation's write.
+// errStatus names the failed step ("Error creating note").
+type pendingOpDoneMsg struct {
+	id        int
+	errStatus string
+	err       error
+}
+
+// pendingConflictActions maps browse actions that act on the selected item
+// to the word used when they are rejected for a pending item.
+var pendingConflictActions = map[string]string{
+	actionEditNote:        "editing",
+	actionRename:          "rename",
+	actionMove:            "move",
+	actionDuplicate:       "duplicate",
+	actionDelete:          "delete",
+	actionArchive:         "archive",
+	actionToggleFolder:    "move",
+	actionEditTags:        "tag edits",
+	actionEditFrontmatter: "frontmatter edits",
+	actionHeadingCase:     "heading rewrites",
+	actionEncryptToggle:   "encryption",
+}
+
+// pendingGitActions maps the git actions that wait for every pending write
+// to the word used when they are rejected.
+var pendingGitActions = map[string]string{
+	actionGitCommit: "git commit",
+	actionGitPull:   "git pull",
+	actionGitPush:   "git push",
+}
+
+// pendingOpFor returns the pending operation that path belongs to: the
+// operation's own item, a folder created for it, a path inside a pending
+// folder, or a rename source.
+func (m *Model) pendingOpFor(path string) *pendingOp {
+	for _, op := range m.pendingOps {
+		paths := []string{op.path, op.oldPath}
+		if len(op.createdDirs) > 0 {
+			paths = append(paths, op.createdDirs[0])
+		}
+		for _, p := range paths {
+			if p != "" && isWithinRoot(p, path) {
+				return op
+			}
+		}
+	}
+	return nil
+}
+
+// rejectWhilePending reports whether path is still being written, and if so
+// says so in the status bar.
+func (m *Model) rejectWhilePending(path, what string) bool {
+	op := m.pendingOpFor(path)
+	if op == nil {
+		return false
+	}
+	m.status = "Still saving " + filepath.Base(op.path) + ": " + what + " is unavailable until it finishes"
+	return true
+}
+
+// rejectWhileAnyPending reports whether any write is still pending, and if
+// so says so in the status bar.
+func (m *Model) rejectWhileAnyPending(what string) bool {
+	var first *pendingOp
+	for _, op := range m.pendingOps {
+		if first == nil || op.id < first.id {
+			first = op
+		}
+	}
+	if first == nil {
+		return false
+	}
+	m.status = "Still saving " + filepath.Base(first.path) + ": " + what + " is unavailable until it finishes"
+	return true
+}
+
+// startPendingOp records the selection in op, registers it, and returns the
+// command that runs write off the UI goroutine. The caller applies the
+// optimistic change after this call.
+func (m *Model) startPendingOp(op *pendingOp, write func() (string, error)) tea.Cmd {
+	op.selected = m.selectedPath()
+	op.treeOffset = m.treeOffset
+	if m.pendingOps == nil {
+		m.pendingOps = map[int]*pendingOp{}
+	}
+	m.pendingOpSeq++
+	op.id = m.pendingOpSeq
+	m.pendingOps[op.id] = op
+	id := op.id
+	return func() tea.Msg {
+		status, err := write()
+		return pendingOpDoneMsg{id: id, errStatus: status, err: err}
+	}
+}
+
+// expandForPendingOp expands dirs for op, remembering the folders that were
+// collapsed so a rollback can collapse them again.
+func (m *Model) expandForPendingOp(op *pendingOp, dirs ...string) {
+	for _, dir := range dirs {
+		if !m.expanded[dir] {
+			op.expandedAdded = append(op.expandedAdded, dir)
+		}
+		m.expanded[dir] = true
+	}
+}
+
+// pendingInside reports whether another pending operation's item lies in
+// dir.
+func (m *Model) pendingInside(dir string) bool {
+	for _, op := range m.pendingOps {
+		if isWithinRoot(dir, op.path) {
+			return true
+		}
+	}
+	return false
+}
+
+// insertPendingRows adds tree rows for a note being created (and folders
+// created for it) right below its parent folder, and selects the note.
+func (m *Model) insertPendingRows(op *pendingOp) {
+	rows := make([]treeItem, 0, len(op.createdDirs)+1)
+	for _, dir := range op.createdDirs {
+		rows = append(rows, m.pendingTreeItem(dir, true))
+	}
+	rows = append(rows, m.pendingTreeItem(op.path, false))
+
+	at := len(m.items)
+	if op.parent == m.notesDir {
+		at = 0
+	}
+	for i, item := range m.items {
+		if item.path == op.parent {
+			at = i + 1
+			break
+		}
+	}
+	items := make([]treeItem, 0, len(m.items)+len(rows))
+	items = append(items, m.items[:at]...)
+	items = append(items, rows...)
+	m.items = append(items, m.items[at:]...)
+	m.cursor = at + len(rows) - 1
+	m.adjustTreeOffset()
+}
+
+// pendingTreeItem builds the tree row of a path that is not on disk yet.
+func (m *Model) pendingTreeItem(path string, isDir bool) treeItem {
+	depth := 0
+	if rel, err := filepath.Rel(m.notesDir, path); err == nil {
+		depth = strings.Count(rel, string(os.PathSeparator))
+	}
+	return treeItem{path: path, name: filepath.Base(path), depth: depth, isDir: isDir}
+}
+
+// renamePendingRows moves the rows under from to to, renaming the row of
+// from itself.
+func (m *Model) renamePendingRows(from, to string) {
+	for i := range m.items {
+		path := replacePathPrefix(m.items[i].path, from, to)
+		if path == m.items[i].path {
+			continue
+		}
+		m.items[i].path = path
+		if path == to {
+			m.items[i].name = filepath.Base(to)
+		}
+	}
+}
+
+// handlePendingOpDone finishes or rolls back a pending operation.
+func (m *Model) handlePendingOpDone(msg pendingOpDoneMsg) (tea.Model, tea.Cmd) {
+	op := m.pendingOps[msg.id]
+	if op == nil {
+		return m, nil
+	}
+	delete(m.pendingOps, msg.id)
+	defer m.runPendingAutoCommit()
+	if msg.err != nil {
+		m.rollbackPendingOp(op)
+		m.setStatusError(msg.errStatus, msg.err, "path", op.path)
+		m.reopenPendingInput(op)
+		return m, nil
+	}
+	if op.kind == pendingRename {
+		return m, m.finishPendingRename(op)
+	}
+	return m, m.finishPendingCreate(op)
+}
+
+// finishPendingCreate runs the effects of a created note.
+func (m *Model) finishPendingCreate(op *pendingOp) tea.Cmd {
+	if op.editorCursor >= 0 {
+		m.setNoteEditorCursor(op.path, op.editorCursor)
+	}
+	for _, dir := range op.createdDirs {
+		m.invalidateTreeMetadataPath(dir)
+	}
+	m.invalidateTreeMetadataPath(op.path)
+	effects := mutationEffects{
+		upsertPaths: append(op.createdDirs, op.path),
+		refreshTree: true,
+		refreshGit:  true,
+	}
+	if m.selectedPath() == op.path {
+		effects.setCurrentFile = op.path
+	}
+	m.status = op.doneStatus
+	return m.applyMutationEffects(effects)
+}
+
+// finishPendingRename runs the effects of a renamed item.
+func (m *Model) finishPendingRename(op *pendingOp) tea.Cmd {
+	m.remapStatePaths(op.oldPath, op.path)
+	m.remapTreeMetadataPath(op.oldPath, op.path)
+	cmd := m.applyMutationEffects(mutationEffects{
+		removePaths: []string{op.oldPath},
+		upsertPaths: []string{op.path},
+		refreshGit:  true,
+		refreshTree: true,
+	})
+	m.status = op.doneStatus
+	if m.currentFile != "" {
+		return m.setCurrentFile(m.currentFile)
+	}
+	return cmd
+}
+
+// rollbackPendingOp reverts the optimistic change of op. op must already be
+// removed from m.pendingOps; changes made since by other operations or by
+// the user are kept.
+func (m *Model) rollbackPendingOp(op *pendingOp) {
+	selected := m.selectedPath()
+	ownSelection := selected != "" && isWithinRoot(op.path, selected)
+	switch op.kind {
+	case pendingCreate:
+		added := []string{op.path}
+		for _, dir := range op.createdDirs {
+			if !m.pendingInside(dir) {
+				added = append(added, dir)
+			}
+		}
+		items := m.items[:0]
+		for _, item := range m.items {
+			if !slices.Contains(added, item.path) {
+				items = append(items, item)
+			}
+		}
+		m.items = items
+		if m.currentFile == op.path {
+			m.currentFile = ""
+		}
+	case pendingRename:
+		m.renamePendingRows(op.path, op.oldPath)
+		m.remapExpandedPaths(op.path, op.oldPath)
+		m.currentFile = replacePathPrefix(m.currentFile, op.path, op.oldPath)
+		selected = replacePathPrefix(selected, op.path, op.oldPath)
+	}
+	for _, dir := range op.expandedAdded {
+		if !m.pendingInside(dir) {
+			delete(m.expanded, dir)
+		}
+	}
+
+	target := selected
+	if ownSelection && op.kind == pendingCreate {
+		target = op.selected
+	}
+	m.cursor = clamp(m.cursor, 0, max(0, len(m.items)-1))
+	for i, item := range m.items {
+		if item.path == target {
+			m.cursor = i
+			break
+		}
+	}
+	if ownSelection {
+		m.treeOffset = op.treeOffset
+	}
+	m.adjustTreeOffset()
+}
+
+// reopenPendingInput reopens the name input of a failed operation with the
+// typed name, unless the user has moved on to another mode.
+func (m *Model) reopenPendingInput(op *pendingOp) {
+	if m.mode != modeBrowse {
+		return
+	}
+	m.showHelp = false
+	m.input.Reset()
+	switch op.kind {
+	case pendingCreate:
+		m.mode = modeNewNote
+		m.newParent = op.parent
+		m.selectedTemplate = op.template
+		m.templateChosen = op.templateChosen
+		m.input.Placeholder = "Note name (without .md extension)"
+	case pendingRename:
+		m.mode = modeRenameItem
+		m.actionPath = op.oldPath
+		m.input.Placeholder = "New name"
+	}
+	m.input.SetValue(op.input)
+	m.input.CursorEnd()
+	m.input.Focus()
+}
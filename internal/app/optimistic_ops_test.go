@@ -0,0 +1,289 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// settlePendingOp runs the filesystem write of a create or rename returned
+// by saveNewNote or saveRenameItem and delivers its result, as the Bubble
+// Tea runtime would.
+func settlePendingOp(model tea.Model, cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	m := model.(*Model)
+	if cmd == nil {
+		return m, nil
+	}
+	if done, ok := cmd().(pendingOpDoneMsg); ok {
+		return m.handlePendingOpDone(done)
+	}
+	return m, nil
+}
+
+func treePaths(items []treeItem) []string {
+	paths := make([]string, 0, len(items))
+	for _, item := range items {
+		paths = append(paths, item.path)
+	}
+	return paths
+}
+
+func TestSaveNewNoteShowsNoteBeforeWriteFinishes(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "work")
+	mustWriteFile(t, filepath.Join(dir, "plan.md"), "# Plan\n")
+	mustWriteFile(t, filepath.Join(root, "z.md"), "# Z\n")
+	m := newTestCRUDModel(root)
+	m.expanded[dir] = true
+	m.items = buildTree(root, m.expanded, sortModeName, nil)
+	m.newParent = dir
+	m.input.SetValue("idea")
+	path := filepath.Join(dir, "idea.md")
+
+	model, cmd := m.saveNewNote()
+	m = model.(*Model)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the write to wait for the command, err=%v", err)
+	}
+	if m.mode != modeBrowse || m.selectedPath() != path || m.status != "Creating note: idea.md…" {
+		t.Fatalf("expected the note selected right away, mode %v selected %q status %q", m.mode, m.selectedPath(), m.status)
+	}
+	if item := m.selectedItem(); item.depth != 1 || item.isDir {
+		t.Fatalf("unexpected pending row %+v", *item)
+	}
+	if row := m.renderTreeRow(*m.selectedItem(), true, 40); !strings.Contains(row, "idea.md") || !strings.Contains(row, "…") {
+		t.Fatalf("expected a pending marker on the row, got %q", row)
+	}
+
+	settlePendingOp(m, cmd)
+	if len(m.pendingOps) != 0 {
+		t.Fatal("expected the pending operation to clear")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected the note on disk: %v", err)
+	}
+	if m.currentFile != path || m.selectedPath() != path || m.status != "Created note: idea.md" {
+		t.Fatalf("current %q selected %q status %q", m.currentFile, m.selectedPath(), m.status)
+	}
+	if row := m.renderTreeRow(*m.selectedItem(), true, 40); strings.Contains(row, "…") {
+		t.Fatalf("expected the pending marker to clear, got %q", row)
+	}
+	assertSearchHasQuery(t, m.searchIndex, "idea", true)
+}
+
+func TestSaveNewNoteRollsBackWhenWriteFails(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "work")
+	other := filepath.Join(root, "z.md")
+	mustWriteFile(t, filepath.Join(dir, "plan.md"), "# Plan\n")
+	mustWriteFile(t, other, "# Z\n")
+	m := newTestCRUDModel(root)
+	reselectTreeItem(t, m, other)
+	m.currentFile = other
+	m.newParent = dir
+	m.input.SetValue("idea")
+	tpl := &noteTemplate{name: "Meeting", content: "# {{title}}\n"}
+	m.selectedTemplate, m.templateChosen = tpl, true
+	wantItems, wantCursor := treePaths(m.items), m.cursor
+
+	orig := writeFileForCreate
+	writeFileForCreate = func(string, []byte, os.FileMode) error { return errors.New("disk full") }
+	defer func() { writeFileForCreate = orig }()
+
+	model, cmd := m.saveNewNote()
+	m = model.(*Model)
+	if !m.expanded[dir] || m.selectedPath() != filepath.Join(dir, "idea.md") {
+		t.Fatalf("expected the optimistic row, selected %q", m.selectedPath())
+	}
+	captureLogOutput(t, func() { settlePendingOp(m, cmd) })
+
+	if got := treePaths(m.items); !slices.Equal(got, wantItems) || m.cursor != wantCursor {
+		t.Fatalf("tree not restored: %v cursor %d, want %v cursor %d", got, m.cursor, wantItems, wantCursor)
+	}
+	if m.expanded[dir] || m.currentFile != other {
+		t.Fatalf("expected expansion and current file restored, expanded %v current %q", m.expanded, m.currentFile)
+	}
+	if m.status != "Error creating note" || m.mode != modeNewNote || m.input.Value() != "idea" || m.newParent != dir {
+		t.Fatalf("expected the input reopened, status %q mode %v input %q parent %q", m.status, m.mode, m.input.Value(), m.newParent)
+	}
+	if m.selectedTemplate != tpl || !m.templateChosen {
+		t.Fatal("expected the template choice restored")
+	}
+	if len(m.pendingOps) != 0 {
+		t.Fatal("expected the pending operation to clear")
+	}
+}
+
+func TestSaveRenameItemRollsBackWhenRenameFails(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "proj")
+	note := filepath.Join(dir, "note.md")
+	mustWriteFile(t, note, "# Note\n")
+	m := newTestCRUDModel(root)
+	m.expanded[dir] = true
+	m.items = buildTree(root, m.expanded, sortModeName, nil)
+	reselectTreeItem(t, m, dir)
+	m.currentFile = note
+	m.mode = modeRenameItem
+	m.actionPath = dir
+	m.input.SetValue("work")
+	wantItems, wantCursor := treePaths(m.items), m.cursor
+
+	orig := renameForRename
+	renameForRename = func(string, string) error { return errors.New("network share went away") }
+	defer func() { renameForRename = orig }()
+
+	model, cmd := m.saveRenameItem()
+	m = model.(*Model)
+	renamed := filepath.Join(root, "work", "note.md")
+	if m.currentFile != renamed || !m.expanded[filepath.Join(root, "work")] {
+		t.Fatalf("expected the rename applied optimistically, current %q", m.currentFile)
+	}
+	assertTreeHasPath(t, m.items, renamed)
+	captureLogOutput(t, func() { settlePendingOp(m, cmd) })
+
+	if got := treePaths(m.items); !slices.Equal(got, wantItems) || m.cursor != wantCursor {
+		t.Fatalf("tree not restored: %v cursor %d, want %v cursor %d", got, m.cursor, wantItems, wantCursor)
+	}
+	if m.items[wantCursor].name != "proj" {
+		t.Fatalf("expected the row name restored, got %q", m.items[wantCursor].name)
+	}
+	if !m.expanded[dir] || m.expanded[filepath.Join(root, "work")] || m.currentFile != note {
+		t.Fatalf("expected expansion and current file restored, expanded %v current %q", m.expanded, m.currentFile)
+	}
+	if m.status != "Error renaming item" || m.mode != modeRenameItem || m.input.Value() != "work" || m.actionPath != dir {
+		t.Fatalf("expected the input reopened, status %q mode %v input %q target %q", m.status, m.mode, m.input.Value(), m.actionPath)
+	}
+}
+
+func TestPendingItemBlocksConflictingOperations(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.loadKeybindings(config.Config{})
+	m.newParent = root
+	m.input.SetValue("idea")
+	model, cmd := m.saveNewNote()
+	m = model.(*Model)
+
+	m.handleBrowseKey("r")
+	if m.mode != modeBrowse || m.status != "Still saving idea.md: rename is unavailable until it finishes" {
+		t.Fatalf("expected rename to be rejected, mode %v status %q", m.mode, m.status)
+	}
+	m.mode = modeNewNote
+	m.newParent = root
+	m.input.SetValue("idea")
+	m.saveNewNote()
+	if len(m.pendingOps) != 1 || m.status != "Still saving idea.md: creating it again is unavailable until it finishes" {
+		t.Fatalf("expected a second create to be rejected, status %q", m.status)
+	}
+
+	settlePendingOp(m, cmd)
+	m.handleBrowseKey("r")
+	if m.mode != modeRenameItem {
+		t.Fatalf("expected rename to open once the write finished, status %q", m.status)
+	}
+}
+
+func TestRollbackKeepsLaterPendingOperation(t *testing.T) {
+	root := t.TempDir()
+	dirA, dirB := filepath.Join(root, "a"), filepath.Join(root, "b")
+	mustWriteFile(t, filepath.Join(dirA, "x.md"), "# X\n")
+	mustWriteFile(t, filepath.Join(dirB, "y.md"), "# Y\n")
+	m := newTestCRUDModel(root)
+	m.items = buildTree(root, m.expanded, sortModeName, nil)
+
+	orig := writeFileForCreate
+	writeFileForCreate = func(path string, data []byte, perm os.FileMode) error {
+		if filepath.Dir(path) == dirA {
+			return errors.New("disk full")
+		}
+		return orig(path, data, perm)
+	}
+	defer func() { writeFileForCreate = orig }()
+
+	m.mode, m.newParent = modeNewNote, dirA
+	m.input.SetValue("one")
+	model, cmdOne := m.saveNewNote()
+	m = model.(*Model)
+	m.mode, m.newParent = modeNewNote, dirB
+	m.input.SetValue("two")
+	model, cmdTwo := m.saveNewNote()
+	m = model.(*Model)
+	two := filepath.Join(dirB, "two.md")
+	if m.selectedPath() != two || len(m.pendingOps) != 2 {
+		t.Fatalf("expected both operations pending with two selected, selected %q", m.selectedPath())
+	}
+
+	captureLogOutput(t, func() { settlePendingOp(m, cmdOne) })
+	if m.expanded[dirA] || !m.expanded[dirB] {
+		t.Fatalf("expected only a collapsed again, expanded %v", m.expanded)
+	}
+	assertTreeHasPath(t, m.items, two)
+	if slices.Contains(treePaths(m.items), filepath.Join(dirA, "one.md")) {
+		t.Fatal("expected the failed row removed")
+	}
+	if m.selectedPath() != two {
+		t.Fatalf("expected the selection to stay on two, got %q", m.selectedPath())
+	}
+
+	m.mode = modeBrowse
+	settlePendingOp(m, cmdTwo)
+	if m.currentFile != two || m.selectedPath() != two {
+		t.Fatalf("expected two to become the current file, current %q selected %q", m.currentFile, m.selectedPath())
+	}
+	if _, err := os.Stat(two); err != nil {
+		t.Fatalf("expected two on disk: %v", err)
+	}
+}
+
+func TestGitActionsWaitForPendingWrites(t *testing.T) {
+	root := t.TempDir()
+	withGitRun(t, func(_ string, args ...string) (string, error) {
+		t.Fatalf("git ran while a write was pending: %v", args)
+		return "", nil
+	})
+	m := newTestCRUDModel(root)
+	m.loadKeybindings(config.Config{})
+	m.git.isRepo = true
+	m.newParent = root
+	m.input.SetValue("idea")
+	model, cmd := m.saveNewNote()
+	m = model.(*Model)
+
+	for _, action := range []string{actionGitPull, actionGitPush, actionGitCommit} {
+		m.status = ""
+		_, gitCmd := m.runBrowseAction(action)
+		if gitCmd != nil || m.opLocked() || m.mode != modeBrowse {
+			t.Fatalf("%s: expected the action rejected, mode %v", action, m.mode)
+		}
+		want := "Still saving idea.md: " + pendingGitActions[action] + " is unavailable until it finishes"
+		if m.status != want {
+			t.Fatalf("%s: expected %q, got %q", action, want, m.status)
+		}
+	}
+	m.autoCommitPending = true
+	if !m.autoCommitBlocked() {
+		t.Fatal("expected auto-commit to wait for the pending write")
+	}
+
+	withGitRun(t, func(_ string, args ...string) (string, error) {
+		if args[0] == "rev-parse" {
+			return "true", nil
+		}
+		return "", nil
+	})
+	settlePendingOp(m, cmd)
+	if m.autoCommitPending {
+		t.Fatal("expected the due auto-commit to run once the write finished")
+	}
+	_, gitCmd := m.runBrowseAction(actionGitPull)
+	if gitCmd == nil || !m.opLocked() {
+		t.Fatalf("expected git pull to start after the write finished, status %q", m.status)
+	}
+}
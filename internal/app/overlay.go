@@ -1,7 +1,5 @@
 package app
 
-import tea "github.com/charmbracelet/bubbletea"
-
 var overlayCleanupByMode = map[overlayMode]func(*Model){
 	overlaySearch: func(m *Model) {
 		m.search.Blur()
@@ -12,6 +10,8 @@ var overlayCleanupByMode = map[overlayMode]func(*Model){
 	overlayWikiAutocomplete: func(m *Model) {
 		m.wikiAutocomplete = nil
 		m.wikiAutocompleteCursor = 0
+		m.fmCompletion = nil
+		m.fmCompletionItems = nil
 	},
 }
 
@@ -43,27 +43,3 @@ func (m *Model) closeOverlay() {
 func (m *Model) isOverlay(mode overlayMode) bool {
 	return m.overlay == mode
 }
-
-// handlePopupListNav handles the shared up/down/select/close key patterns used by list popups.
-// It returns (nextCursor, selectPressed, closePressed, handled).
-func handlePopupListNav(msg tea.KeyMsg, cursor, count int) (int, bool, bool, bool) {
-	key := msg.String()
-	switch key {
-	case "esc":
-		return cursor, false, true, true
-	case "up", "k", "ctrl+p":
-		if count <= 0 {
-			return 0, false, false, true
-		}
-		return clamp(cursor-1, 0, count-1), false, false, true
-	case "down", "j", "ctrl+n":
-		if count <= 0 {
-			return 0, false, false, true
-		}
-		return clamp(cursor+1, 0, count-1), false, false, true
-	case "enter":
-		return cursor, true, false, true
-	default:
-		return cursor, false, false, false
-	}
-}
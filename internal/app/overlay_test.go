@@ -16,12 +16,21 @@ func allConcreteOverlayModesForTest() []overlayMode {
 		overlayExport,
 		overlayWikiLinks,
 		overlayWikiAutocomplete,
+		overlayIssues,
+		overlayMetadata,
+		overlayGitPanel,
+		overlayTrash,
+		overlayNoteStats,
+		overlayGitDiff,
+		overlayGitLog,
+		overlayHeadingCase,
+		overlayAgenda,
 	}
 }
 
 func TestOverlayModeCoverageGuard(t *testing.T) {
 	modes := allConcreteOverlayModesForTest()
-	if want := int(overlayWikiAutocomplete); len(modes) != want {
+	if want := int(overlayAgenda); len(modes) != want {
 		t.Fatalf("overlay coverage list out of date: got %d overlays, expected %d", len(modes), want)
 	}
 }
@@ -167,6 +176,24 @@ func (m overlayMode) String() string {
 		return "wiki_links"
 	case overlayWikiAutocomplete:
 		return "wiki_autocomplete"
+	case overlayIssues:
+		return "issues"
+	case overlayMetadata:
+		return "metadata"
+	case overlayGitPanel:
+		return "git_panel"
+	case overlayTrash:
+		return "trash"
+	case overlayNoteStats:
+		return "note_stats"
+	case overlayGitDiff:
+		return "git_diff"
+	case overlayGitLog:
+		return "git_log"
+	case overlayHeadingCase:
+		return "heading_case"
+	case overlayAgenda:
+		return "agenda"
 	default:
 		return "unknown"
 	}
@@ -0,0 +1,257 @@
+// plain_export.go implements the sharing-oriented export popup (x) options:
+// "Plain text (strip markdown)", which writes a .txt file next to the note;
+// "HTML to clipboard", which renders the note the same way as the HTML export
+// and copies the markup instead of writing a file; "Rendered text to
+// clipboard", which copies the note as the preview shows it, without colors;
+// and "Markdown copy (no frontmatter)", which writes <name>.export.md.
+//
+// All of them work on the body with frontmatter stripped (see
+// exportStripFrontmatter). The plain text conversion
+// is line based: heading markers, emphasis, inline code ticks, blockquote
+// markers and link syntax are removed, while list markers and indentation
+// stay so nested lists still read as lists. Fenced code blocks keep their
+// contents verbatim and only lose the fence lines.
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/yuin/goldmark"
+)
+
+var (
+	plainHeadingPattern     = regexp.MustCompile(`^\s{0,3}#{1,6}(\s+|$)`)
+	plainHeadingTailPattern = regexp.MustCompile(`\s+#+\s*$`)
+	plainSetextPattern      = regexp.MustCompile(`^\s{0,3}(=+|-+)\s*$`)
+	plainListMarkerPattern  = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])(\s+)`)
+	plainImagePattern       = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	plainLinkPattern        = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	plainRefLinkPattern     = regexp.MustCompile(`\[([^\]]+)\]\[[^\]]*\]`)
+	plainWikiLinkPattern    = regexp.MustCompile(`\[\[([^\]|]+)(?:\|([^\]]+))?\]\]`)
+	plainAutoLinkPattern    = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	plainStrongPattern      = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	plainStrikePattern      = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	plainStarEmphPattern    = regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*?\S)?)\*($|[^\w*])`)
+	plainUnderEmphPattern   = regexp.MustCompile(`(^|[^\w])_(\S(?:[^_]*?\S)?)_($|[^\w])`)
+	plainEscapePattern      = regexp.MustCompile("\\\\([\\\\`*_{}\\[\\]()#+\\-.!~|>])")
+)
+
+// markdownToPlainText strips markdown syntax from body, keeping list
+// structure and the literal contents of fenced code blocks.
+func markdownToPlainText(body string) string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	fence, prev := "", ""
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+		if marker := codeFenceMarker(trimmed); marker != "" {
+			fence, prev = marker, ""
+			continue
+		}
+		// A line of = or - under a paragraph line is a setext heading
+		// underline; elsewhere a --- line is a thematic break and stays.
+		setext := plainSetextPattern.MatchString(line) && isPlainParagraphLine(prev)
+		prev = line
+		if setext {
+			continue
+		}
+		out = append(out, plainTextLine(line))
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n") + "\n"
+}
+
+// isPlainParagraphLine reports whether line is paragraph text that a
+// following = or - underline would turn into a setext heading.
+func isPlainParagraphLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed != "" &&
+		!plainHeadingPattern.MatchString(line) &&
+		!plainListMarkerPattern.MatchString(line) &&
+		!plainSetextPattern.MatchString(line) &&
+		!strings.HasPrefix(trimmed, ">")
+}
+
+// codeFenceMarker returns the opening fence (``` or ~~~, possibly longer)
+// of a trimmed line, or "" when the line does not open a fenced block.
+func codeFenceMarker(trimmed string) string {
+	for _, ch := range []string{"`", "~"} {
+		if strings.HasPrefix(trimmed, ch+ch+ch) {
+			return strings.Repeat(ch, len(trimmed)-len(strings.TrimLeft(trimmed, ch)))
+		}
+	}
+	return ""
+}
+
+// plainTextLine strips block and inline markdown from one line outside a
+// fenced code block.
+func plainTextLine(line string) string {
+	for {
+		trimmed := strings.TrimLeft(line, " ")
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		line = strings.TrimPrefix(strings.TrimPrefix(trimmed, ">"), " ")
+	}
+	if loc := plainHeadingPattern.FindStringIndex(line); loc != nil {
+		return stripInlineMarkdown(plainHeadingTailPattern.ReplaceAllString(line[loc[1]:], ""))
+	}
+	prefix := ""
+	if loc := plainListMarkerPattern.FindStringIndex(line); loc != nil {
+		prefix, line = line[:loc[1]], line[loc[1]:]
+	}
+	return prefix + stripInlineMarkdown(line)
+}
+
+// stripInlineMarkdown removes inline markdown from s. Code spans keep their
+// contents literally and lose only the backticks.
+func stripInlineMarkdown(s string) string {
+	var b strings.Builder
+	for s != "" {
+		start := strings.IndexByte(s, '`')
+		if start < 0 {
+			b.WriteString(stripInlineSyntax(s))
+			break
+		}
+		ticks := len(s[start:]) - len(strings.TrimLeft(s[start:], "`"))
+		delim := s[start : start+ticks]
+		end := strings.Index(s[start+ticks:], delim)
+		if end < 0 {
+			b.WriteString(stripInlineSyntax(s))
+			break
+		}
+		b.WriteString(stripInlineSyntax(s[:start]))
+		b.WriteString(strings.TrimSpace(s[start+ticks : start+ticks+end]))
+		s = s[start+ticks+end+ticks:]
+	}
+	return b.String()
+}
+
+// stripInlineSyntax removes links, images and emphasis from text that holds
+// no code spans. Links keep their URL in parentheses so it survives pasting.
+func stripInlineSyntax(s string) string {
+	s = plainImagePattern.ReplaceAllString(s, "$1")
+	s = plainLinkPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := plainLinkPattern.FindStringSubmatch(match)
+		if parts[1] == parts[2] {
+			return parts[1]
+		}
+		return parts[1] + " (" + parts[2] + ")"
+	})
+	s = plainRefLinkPattern.ReplaceAllString(s, "$1")
+	s = plainWikiLinkPattern.ReplaceAllStringFunc(s, func(match string) string {
+		parts := plainWikiLinkPattern.FindStringSubmatch(match)
+		if parts[2] != "" {
+			return strings.TrimSpace(parts[2])
+		}
+		return strings.TrimSpace(parts[1])
+	})
+	s = plainAutoLinkPattern.ReplaceAllString(s, "$1")
+	s = plainStrongPattern.ReplaceAllString(s, "$2")
+	s = plainStrikePattern.ReplaceAllString(s, "$1")
+	s = plainStarEmphPattern.ReplaceAllString(s, "$1$2$3")
+	s = plainUnderEmphPattern.ReplaceAllString(s, "$1$2$3")
+	return plainEscapePattern.ReplaceAllString(s, "$1")
+}
+
+// exportCurrentNotePlainText returns an async Cmd that writes the current
+// note as plain text alongside the source file (same name, .txt extension).
+func (m *Model) exportCurrentNotePlainText() tea.Cmd {
+	path := m.currentFile
+	return func() tea.Msg {
+		body, err := exportStripFrontmatter(path)
+		if err != nil {
+			return statusMsg{Text: "Export failed: unable to read note"}
+		}
+		txtPath := noteExportPath(path, ".txt")
+		if err := os.WriteFile(txtPath, []byte(markdownToPlainText(body)), FilePermission); err != nil {
+			return statusMsg{Text: "Export failed: unable to write text file"}
+		}
+		return statusMsg{Text: "Exported plain text: " + m.displayRelative(txtPath)}
+	}
+}
+
+// copyCurrentNoteHTMLToClipboard returns an async Cmd that renders the
+// current note to HTML and copies it to the system clipboard.
+func (m *Model) copyCurrentNoteHTMLToClipboard() tea.Cmd {
+	path := m.currentFile
+	return func() tea.Msg {
+		body, err := exportStripFrontmatter(path)
+		if err != nil {
+			return statusMsg{Text: "Export failed: unable to read note"}
+		}
+		var out bytes.Buffer
+		if err := goldmark.Convert([]byte(body), &out); err != nil {
+			return statusMsg{Text: "Export failed: unable to convert markdown to HTML"}
+		}
+		if err := writeClipboard(out.String()); err != nil {
+			return statusMsg{Text: "Clipboard copy failed: " + err.Error()}
+		}
+		return statusMsg{Text: fmt.Sprintf("Copied HTML to clipboard (%d bytes)", out.Len())}
+	}
+}
+
+// copyCurrentNoteRenderedToClipboard returns an async Cmd that renders the
+// current note as the preview does, at the preview width, and copies the
+// result to the system clipboard with terminal colors and line padding
+// removed.
+func (m *Model) copyCurrentNoteRenderedToClipboard() tea.Cmd {
+	path := m.currentFile
+	width := roundWidthToNearestBucket(max(m.viewport.Width, 20))
+	return func() tea.Msg {
+		body, err := exportStripFrontmatter(path)
+		if err != nil {
+			return statusMsg{Text: "Export failed: unable to read note"}
+		}
+		lines := strings.Split(ansi.Strip(renderMarkdown(body, width)), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " ")
+		}
+		text := strings.Trim(strings.Join(lines, "\n"), "\n") + "\n"
+		if err := writeClipboard(text); err != nil {
+			return statusMsg{Text: "Clipboard copy failed: " + err.Error()}
+		}
+		return statusMsg{Text: fmt.Sprintf("Copied rendered text (%d chars)", len([]rune(text)))}
+	}
+}
+
+// exportCurrentNoteMarkdownCopy returns an async Cmd that writes the current
+// note without its frontmatter alongside the source file, as <name>.export.md.
+func (m *Model) exportCurrentNoteMarkdownCopy() tea.Cmd {
+	path := m.currentFile
+	return func() tea.Msg {
+		body, err := exportStripFrontmatter(path)
+		if err != nil {
+			return statusMsg{Text: "Export failed: unable to read note"}
+		}
+		copyPath := noteExportPath(path, ".export.md")
+		if err := os.WriteFile(copyPath, []byte(normalizeNoteContent(strings.TrimLeft(body, "\n"))), FilePermission); err != nil {
+			return statusMsg{Text: "Export failed: unable to write markdown copy"}
+		}
+		return statusMsg{Text: "Exported markdown copy: " + m.displayRelative(copyPath)}
+	}
+}
+
+// exportStripFrontmatter reads the note at path and returns its body with
+// any frontmatter block removed.
+func exportStripFrontmatter(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	_, body := parseFrontmatterAndBody(string(content))
+	return body, nil
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func withFakeClipboard(t *testing.T, err error) *string {
+	t.Helper()
+	var got string
+	prev := writeClipboard
+	writeClipboard = func(text string) error {
+		got = text
+		return err
+	}
+	t.Cleanup(func() { writeClipboard = prev })
+	return &got
+}
+
+func TestMarkdownToPlainTextStripsSyntaxKeepsStructure(t *testing.T) {
+	body := strings.Join([]string{
+		"# Weekly *update* #",
+		"",
+		"Summary with **bold**, _emphasis_, ~~old~~ and `a_b*c`.",
+		"See [the docs](https://example.com \"Docs\"), [[Roadmap|our roadmap]] and <https://x.dev>.",
+		"![diagram](img.png) keeps snake_case_names and 2*3*4.",
+		"",
+		"Setext",
+		"======",
+		"",
+		"> quoted **line**",
+		"",
+		"- item one",
+		"  - nested [link](https://a.b)",
+		"1. first",
+		"---",
+		"```go",
+		"// **not** stripped",
+		"x := `raw`",
+		"```",
+	}, "\n")
+	want := strings.Join([]string{
+		"Weekly update",
+		"",
+		"Summary with bold, emphasis, old and a_b*c.",
+		"See the docs (https://example.com), our roadmap and https://x.dev.",
+		"diagram keeps snake_case_names and 2*3*4.",
+		"",
+		"Setext",
+		"",
+		"quoted line",
+		"",
+		"- item one",
+		"  - nested link (https://a.b)",
+		"1. first",
+		"---",
+		"// **not** stripped",
+		"x := `raw`",
+	}, "\n") + "\n"
+	if got := markdownToPlainText(body); got != want {
+		t.Fatalf("unexpected plain text:\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExportPopupWritesPlainTextWithoutFrontmatter(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "note.md")
+	mustWriteFile(t, path, "---\ntitle: Note\n---\n## Hello **world**\n")
+	m := newTestCRUDModel(root)
+	m.currentFile = path
+
+	msg := m.exportCurrentNotePlainText()()
+	if status, ok := msg.(statusMsg); !ok || status.Text != "Exported plain text: note.txt" {
+		t.Fatalf("unexpected result %#v", msg)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "note.txt"))
+	if err != nil || string(data) != "Hello world\n" {
+		t.Fatalf("unexpected text file %q (%v)", data, err)
+	}
+}
+
+func TestCopyNoteHTMLToClipboard(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "note.md")
+	mustWriteFile(t, path, "---\ntitle: Note\n---\n# Hi\n")
+	m := newTestCRUDModel(root)
+	m.currentFile = path
+
+	copied := withFakeClipboard(t, nil)
+	msg := m.copyCurrentNoteHTMLToClipboard()()
+	if *copied != "<h1>Hi</h1>\n" {
+		t.Fatalf("unexpected clipboard HTML %q", *copied)
+	}
+	if status := msg.(statusMsg); status.Text != "Copied HTML to clipboard (12 bytes)" {
+		t.Fatalf("unexpected status %q", status.Text)
+	}
+
+	withFakeClipboard(t, errors.New("no clipboard utility"))
+	if status := m.copyCurrentNoteHTMLToClipboard()().(statusMsg); !strings.Contains(status.Text, "no clipboard utility") {
+		t.Fatalf("expected clipboard failure in status, got %q", status.Text)
+	}
+}
+
+func TestCopyNoteRenderedTextToClipboard(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "note.md")
+	mustWriteFile(t, path, "---\ntitle: Note\n---\n# Hi\n\nSome **bold** text.\n")
+	m := newTestCRUDModel(root)
+	m.currentFile = path
+	m.openExportPopup()
+	m.exportCursor = exportRowClipboardRendered
+
+	copied := withFakeClipboard(t, nil)
+	_, cmd := m.handleExportPopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+	status := cmd().(statusMsg)
+	if !strings.Contains(*copied, "Hi") || !strings.Contains(*copied, "text.") || strings.Contains(*copied, "title: Note") || strings.Contains(*copied, "\x1b[") {
+		t.Fatalf("unexpected rendered clipboard text %q", *copied)
+	}
+	for _, line := range strings.Split(*copied, "\n") {
+		if strings.HasSuffix(line, " ") {
+			t.Fatalf("expected line padding trimmed, got %q", line)
+		}
+	}
+	if !strings.HasPrefix(status.Text, "Copied rendered text (") {
+		t.Fatalf("unexpected status %q", status.Text)
+	}
+}
+
+func TestExportMarkdownCopyStripsFrontmatter(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "note.md")
+	mustWriteFile(t, path, "---\ntitle: Note\ntags: [private]\n---\n\n# Hi\n\nBody\n\n")
+	m := newTestCRUDModel(root)
+	m.currentFile = path
+	m.openExportPopup()
+	m.exportCursor = exportRowMarkdownCopy
+
+	_, cmd := m.handleExportPopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+	status := cmd().(statusMsg)
+	if status.Text != "Exported markdown copy: note.export.md" {
+		t.Fatalf("unexpected status %q", status.Text)
+	}
+	if got := readFileString(t, filepath.Join(root, "note.export.md")); got != "# Hi\n\nBody\n" {
+		t.Fatalf("unexpected markdown copy %q", got)
+	}
+	if got := readFileString(t, path); !strings.HasPrefix(got, "---\ntitle: Note") {
+		t.Fatalf("expected the note itself untouched, got %q", got)
+	}
+
+	if _, err := exportStripFrontmatter(filepath.Join(root, "missing.md")); err == nil {
+		t.Fatal("expected a missing note to fail")
+	}
+}
@@ -0,0 +1,413 @@
+// popup_keys.go implements the key layer shared by every popup.
+//
+// Popups used to match literal keys ("j", "enter", "esc", ...) on their own,
+// so keymap overrides never reached them and feature keys could silently
+// shadow navigation. Keys are now resolved in two layers:
+//
+//   - The shared layer (popup.up, popup.down, popup.page_up, popup.page_down,
+//     popup.top, popup.bottom, popup.select, popup.close) is bound like any
+//     browse action: defaultPopupKeys are the defaults, and "keybindings" or
+//     keymap_file remap them once for every popup. The layer has its own
+//     index because its keys (j, k, g, ...) deliberately overlap browse keys.
+//   - Each popup declares its feature keys in popupSpecs, either as a popup
+//     action (e.g. "t" → tag_browser.filter) or as an extra key
+//     for a shared action (e.g. "q" → popup.close in the git diff).
+//
+// rebuildPopupKeyIndex runs with rebuildActionKeyIndex. Shared keys win: a
+// popup key already taken by the shared layer, or declared twice in one
+// popup, is dropped with a warning naming the popup, the key, and both
+// actions. Popups that pick rows by number (quickSelect) do so for 1-9
+// unless a layer binds the digit.
+//
+// The footer hints of a popup are generated from its spec and the current
+// bindings (popupFooterHints), so they follow remaps.
+package app
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Shared popup actions.
+const (
+	actionPopupUp       = "popup.up"
+	actionPopupDown     = "popup.down"
+	actionPopupPageUp   = "popup.page_up"
+	actionPopupPageDown = "popup.page_down"
+	actionPopupTop      = "popup.top"
+	actionPopupBottom   = "popup.bottom"
+	actionPopupSelect   = "popup.select"
+	actionPopupClose    = "popup.close"
+)
+
+// defaultPopupKeys are the shared layer's default keys.
+var defaultPopupKeys = map[string][]string{
+	actionPopupUp:       {"up", "k", "ctrl+p"},
+	actionPopupDown:     {"down", "j", "ctrl+n"},
+	actionPopupPageUp:   {"pgup"},
+	actionPopupPageDown: {"pgdown"},
+	actionPopupTop:      {"home", "g"},
+	actionPopupBottom:   {"end", "shift+g"},
+	actionPopupSelect:   {"enter"},
+	actionPopupClose:    {"esc"},
+}
+
+// isPopupAction reports whether action belongs to the shared popup layer.
+func isPopupAction(action string) bool {
+	_, ok := defaultPopupKeys[action]
+	return ok
+}
+
+// Popups with a spec in popupSpecs. A popup with sub-views (git history and
+// the revision it shows) has one spec per view.
+const (
+	popupSearch           = "search"
+	popupSavedViews       = "saved_views"
+	popupMaintenance      = "maintenance"
+	popupTagBrowser       = "tag_browser"
+	popupTagFilter        = "tag_filter"
+	popupRecent           = "recent"
+	popupOutline          = "outline"
+	popupWorkspace        = "workspace"
+	popupExport           = "export"
+	popupWikiLinks        = "wiki_links"
+	popupWikiAutocomplete = "wiki_autocomplete"
+	popupIssues           = "issues"
+	popupMetadata         = "metadata"
+	popupGitPanel         = "git_panel"
+	popupTrash            = "trash"
+	popupNoteStats        = "note_stats"
+	popupGitDiff          = "git_diff"
+	popupGitLog           = "git_log"
+	popupGitRevision      = "git_revision"
+	popupHeadingCase      = "heading_case"
+	popupAgenda           = "agenda"
+	popupKeymap           = "keymap"
+	popupTemplates        = "templates"
+	popupCommandPalette   = "command_palette"
+)
+
+// Popup actions declared in popupSpecs.
+const (
+	popupActionSaveView        = "search.save_view"
+	popupActionToggleLastTag   = "search.toggle_last_tag"
+	popupActionDeleteView      = "saved_views.delete"
+	popupActionTagFilter       = "tag_browser.filter"
+	popupActionTagRename       = "tag_browser.rename"
+	popupActionClearTagFilter  = "tag_filter.clear"
+	popupActionAddWorkspace    = "workspace.add"
+	popupActionRemoveWorkspace = "workspace.remove"
+	popupActionWorkspaceUp     = "workspace.move_up"
+	popupActionWorkspaceDown   = "workspace.move_down"
+	popupActionDiffStaged      = "git_diff.toggle_staged"
+	popupActionAgendaNext      = "agenda.next_range"
+	popupActionAgendaPrev      = "agenda.previous_range"
+	popupActionTemplateEdit    = "templates.edit"
+)
+
+// popupKey binds key to action inside one popup. action is a popup action
+// or a shared one; hint labels a popup action in the footer (keys without
+// a hint are not shown). For a shared action any non-empty hint adds the
+// key to that action's footer label, as in "Enter/y copy".
+type popupKey struct {
+	key    string
+	action string
+	hint   string
+}
+
+// popupSpec declares a popup's footer and its own keys.
+type popupSpec struct {
+	// title leads the footer hints; extra follows it (e.g. "type").
+	title string
+	extra []string
+	// nav labels the up/down keys ("move" or "scroll"); empty hides them.
+	nav string
+	// selectHint labels popup.select; empty hides it.
+	selectHint string
+	// closeHint labels popup.close (default "close").
+	closeHint string
+	// quickSelect picks rows 1-9 by number.
+	quickSelect bool
+	keys        []popupKey
+}
+
+// popupSpecs is the registry of popup keys and footer hints.
+var popupSpecs = map[string]popupSpec{
+	popupSearch: {title: "Search popup", extra: []string{"type"}, nav: "move", selectHint: "jump", closeHint: "cancel",
+		keys: []popupKey{{"ctrl+s", popupActionSaveView, "save view"}, {"ctrl+t", popupActionToggleLastTag, "last tag"}}},
+	popupSavedViews: {title: "Saved views", nav: "move", selectHint: "search", quickSelect: true,
+		keys: []popupKey{{"d", popupActionDeleteView, "delete"}}},
+	popupTagBrowser: {title: "Tag browser", nav: "move", selectHint: "search",
+		keys: []popupKey{{"t", popupActionTagFilter, "filter tree"}, {"r", popupActionTagRename, "rename"}}},
+	popupTagFilter: {title: "Tag filter", nav: "move", selectHint: "filter", quickSelect: true,
+		keys: []popupKey{{"x", popupActionClearTagFilter, "clear"}}},
+	popupMaintenance: {title: "Maintenance", nav: "move", selectHint: "run", quickSelect: true},
+	popupRecent:      {title: "Recent popup", nav: "move", selectHint: "jump", closeHint: "cancel"},
+	popupOutline:     {title: "Outline popup", nav: "move", selectHint: "jump", closeHint: "cancel"},
+	popupWorkspace: {title: "Workspace popup", nav: "move", selectHint: "switch", closeHint: "cancel", quickSelect: true,
+		keys: []popupKey{
+			{"a", popupActionAddWorkspace, "add"},
+			{"d", popupActionRemoveWorkspace, "remove"},
+			{"alt+up", popupActionWorkspaceUp, ""},
+			{"alt+down", popupActionWorkspaceDown, ""},
+		}},
+	popupExport:    {title: "Export popup", nav: "move", selectHint: "export", closeHint: "cancel"},
+	popupWikiLinks: {title: "Wiki links popup", nav: "move", selectHint: "jump", closeHint: "cancel"},
+	popupWikiAutocomplete: {title: "Wiki autocomplete", nav: "move", selectHint: "insert",
+		keys: []popupKey{{"tab", actionPopupSelect, "insert"}}},
+	popupIssues:   {title: "Issues popup", nav: "move", selectHint: "jump", closeHint: "cancel"},
+	popupMetadata: {title: "Metadata popup", nav: "scroll"},
+	popupGitPanel: {title: "Git panel", nav: "move", selectHint: "run/open"},
+	popupTrash:    {title: "Trash popup", nav: "move", selectHint: "restore"},
+	popupNoteStats: {title: "Note stats", selectHint: "copy",
+		keys: []popupKey{{"y", actionPopupSelect, "copy"}, {"q", actionPopupClose, ""}}},
+	popupGitDiff: {title: "Git diff", nav: "scroll",
+		keys: []popupKey{{"tab", popupActionDiffStaged, "staged/unstaged"}, {"s", popupActionDiffStaged, ""}, {"q", actionPopupClose, ""}}},
+	popupGitLog: {title: "Git history", nav: "move", selectHint: "view"},
+	popupGitRevision: {title: "Git revision", nav: "scroll", closeHint: "back",
+		keys: []popupKey{{"q", actionPopupClose, ""}, {"left", actionPopupClose, ""}, {"backspace", actionPopupClose, ""}}},
+	popupHeadingCase: {title: "Heading case", nav: "move", selectHint: "convert", closeHint: "cancel"},
+	popupAgenda: {title: "Agenda", nav: "move", selectHint: "open",
+		keys: []popupKey{{"tab", popupActionAgendaNext, "range"}, {"shift+tab", popupActionAgendaPrev, ""}}},
+	popupKeymap:         {title: "Keybindings", nav: "move", selectHint: "remap/reset"},
+	popupCommandPalette: {title: "Command palette", extra: []string{"type"}, nav: "move", selectHint: "run", closeHint: "cancel"},
+	popupTemplates: {title: "Template picker", nav: "move", selectHint: "choose", closeHint: "cancel",
+		keys: []popupKey{{"ctrl+s", actionPopupSelect, ""}, {"e", popupActionTemplateEdit, "edit"}}},
+}
+
+// activePopup returns the spec name of the popup receiving keys, or "".
+func (m *Model) activePopup() string {
+	if m.mode == modeTemplatePicker {
+		return popupTemplates
+	}
+	switch m.overlay {
+	case overlaySearch:
+		return popupSearch
+	case overlaySavedViews:
+		return popupSavedViews
+	case overlayMaintenance:
+		return popupMaintenance
+	case overlayTagBrowser:
+		return popupTagBrowser
+	case overlayTagFilter:
+		return popupTagFilter
+	case overlayRecent:
+		return popupRecent
+	case overlayOutline:
+		return popupOutline
+	case overlayWorkspace:
+		return popupWorkspace
+	case overlayExport:
+		return popupExport
+	case overlayWikiLinks:
+		return popupWikiLinks
+	case overlayWikiAutocomplete:
+		return popupWikiAutocomplete
+	case overlayIssues:
+		return popupIssues
+	case overlayMetadata:
+		return popupMetadata
+	case overlayGitPanel:
+		return popupGitPanel
+	case overlayTrash:
+		return popupTrash
+	case overlayNoteStats:
+		return popupNoteStats
+	case overlayGitDiff:
+		return popupGitDiff
+	case overlayGitLog:
+		if m.gitLogRevision != "" {
+			return popupGitRevision
+		}
+		return popupGitLog
+	case overlayHeadingCase:
+		return popupHeadingCase
+	case overlayAgenda:
+		return popupAgenda
+	case overlayKeymap:
+		return popupKeymap
+	case overlayCommandPalette:
+		return popupCommandPalette
+	}
+	return ""
+}
+
+// popupKeys returns the keys bound to a shared popup action.
+func (m *Model) popupKeys(action string) []string {
+	if keys, ok := m.keyForAction[action]; ok {
+		return keys
+	}
+	return defaultPopupKeys[action]
+}
+
+// rebuildPopupKeyIndex rebuilds the shared-layer and per-popup key indexes,
+// warning about conflicts the way rebuildActionKeyIndex does.
+func (m *Model) rebuildPopupKeyIndex() {
+	m.popupKeyToAction = map[string]string{}
+	actions := make([]string, 0, len(defaultPopupKeys))
+	for action := range defaultPopupKeys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		for _, key := range m.popupKeys(action) {
+			key = normalizeKeyString(key)
+			if key == "" {
+				continue
+			}
+			if existing, ok := m.popupKeyToAction[key]; ok && existing != action {
+				appLog.Warn("popup keybinding conflict ignored", "key", key, "action", action, "existing_action", existing)
+				continue
+			}
+			m.popupKeyToAction[key] = action
+		}
+	}
+	m.popupLocalKeys = map[string]map[string]string{}
+	popups := make([]string, 0, len(popupSpecs))
+	for popup := range popupSpecs {
+		popups = append(popups, popup)
+	}
+	sort.Strings(popups)
+	for _, popup := range popups {
+		local := map[string]string{}
+		for _, pk := range popupSpecs[popup].keys {
+			key := normalizeKeyString(pk.key)
+			existing, ok := m.popupKeyToAction[key]
+			if !ok {
+				existing, ok = local[key]
+			}
+			if ok && existing != pk.action {
+				appLog.Warn("popup keybinding conflict ignored", "popup", popup, "key", key, "action", pk.action, "existing_action", existing)
+				continue
+			}
+			if !ok {
+				local[key] = pk.action
+			}
+		}
+		m.popupLocalKeys[popup] = local
+	}
+}
+
+// popupAction resolves a key press in the active popup to a shared or popup
+// action, or "" when the key is unbound there.
+func (m *Model) popupAction(msg tea.KeyMsg) string {
+	if m.popupKeyToAction == nil {
+		m.rebuildPopupKeyIndex()
+	}
+	key := normalizeKeyString(msg.String())
+	if action, ok := m.popupKeyToAction[key]; ok {
+		return action
+	}
+	return m.popupLocalKeys[m.activePopup()][key]
+}
+
+// popupQuickSelect returns the row picked by a digit key in a quickSelect
+// popup.
+func (m *Model) popupQuickSelect(msg tea.KeyMsg) (int, bool) {
+	key := msg.String()
+	if !popupSpecs[m.activePopup()].quickSelect || len(key) != 1 || key[0] < '1' || key[0] > '9' {
+		return 0, false
+	}
+	if m.popupAction(msg) != "" {
+		return 0, false
+	}
+	return int(key[0] - '1'), true
+}
+
+// popupListNav applies a shared action to a list cursor. It returns
+// (nextCursor, selectPressed, closePressed, handled).
+func popupListNav(action string, cursor, count int) (int, bool, bool, bool) {
+	last := max(0, count-1)
+	switch action {
+	case actionPopupClose:
+		return cursor, false, true, true
+	case actionPopupSelect:
+		return cursor, true, false, true
+	case actionPopupUp:
+		return clamp(cursor-1, 0, last), false, false, true
+	case actionPopupDown:
+		return clamp(cursor+1, 0, last), false, false, true
+	case actionPopupPageUp:
+		return clamp(cursor-PopupPageRows, 0, last), false, false, true
+	case actionPopupPageDown:
+		return clamp(cursor+PopupPageRows, 0, last), false, false, true
+	case actionPopupTop:
+		return 0, false, false, true
+	case actionPopupBottom:
+		return last, false, false, true
+	}
+	return cursor, false, false, false
+}
+
+// popupListKey resolves msg and applies it to a list cursor (see
+// popupListNav).
+func (m *Model) popupListKey(msg tea.KeyMsg, cursor, count int) (int, bool, bool, bool) {
+	return popupListNav(m.popupAction(msg), cursor, count)
+}
+
+// popupFooterHints builds the footer hints of popup from its spec and the
+// current bindings.
+func (m *Model) popupFooterHints(popup string) []string {
+	spec := popupSpecs[popup]
+	hints := append([]string{spec.title}, spec.extra...)
+	if spec.nav != "" {
+		hints = append(hints, m.popupSharedLabel(actionPopupUp)+"/"+m.popupSharedLabel(actionPopupDown)+" "+spec.nav)
+	}
+	if spec.selectHint != "" {
+		label := m.popupActionLabel(spec, actionPopupSelect)
+		if spec.quickSelect {
+			label += "/1-9"
+		}
+		hints = append(hints, label+" "+spec.selectHint)
+	}
+	shown := map[string]bool{}
+	for _, pk := range spec.keys {
+		if pk.hint == "" || isPopupAction(pk.action) || shown[pk.action] {
+			continue
+		}
+		shown[pk.action] = true
+		hints = append(hints, popupKeyLabel(pk.key)+" "+pk.hint)
+	}
+	return append(hints, m.popupActionLabel(spec, actionPopupClose)+" "+spec.closeLabel())
+}
+
+// closeLabel returns the footer label of popup.close.
+func (s popupSpec) closeLabel() string {
+	if s.closeHint == "" {
+		return "close"
+	}
+	return s.closeHint
+}
+
+// popupActionLabel labels a shared action with its primary key and the
+// popup's hinted extra keys for it.
+func (m *Model) popupActionLabel(spec popupSpec, action string) string {
+	labels := []string{m.popupSharedLabel(action)}
+	for _, pk := range spec.keys {
+		if pk.action == action && pk.hint != "" {
+			labels = append(labels, popupKeyLabel(pk.key))
+		}
+	}
+	return strings.Join(labels, "/")
+}
+
+// popupSharedLabel labels a shared action with its primary key.
+func (m *Model) popupSharedLabel(action string) string {
+	keys := m.popupKeys(action)
+	if len(keys) == 0 {
+		return "-"
+	}
+	return popupKeyLabel(keys[0])
+}
+
+// popupKeyLabel is humanizeKeyLabel, except that plain letters stay
+// lowercase the way popup footers show them.
+func popupKeyLabel(key string) string {
+	key = normalizeKeyString(key)
+	if runes := []rune(key); len(runes) == 1 {
+		return key
+	}
+	return humanizeKeyLabel(key)
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// showPopup makes popup the active one on m.
+var showPopup = map[string]func(m *Model){
+	popupSearch:           func(m *Model) { m.overlay = overlaySearch },
+	popupSavedViews:       func(m *Model) { m.overlay = overlaySavedViews },
+	popupMaintenance:      func(m *Model) { m.overlay = overlayMaintenance },
+	popupTagBrowser:       func(m *Model) { m.overlay = overlayTagBrowser },
+	popupTagFilter:        func(m *Model) { m.overlay = overlayTagFilter },
+	popupRecent:           func(m *Model) { m.overlay = overlayRecent },
+	popupOutline:          func(m *Model) { m.overlay = overlayOutline },
+	popupWorkspace:        func(m *Model) { m.overlay = overlayWorkspace },
+	popupExport:           func(m *Model) { m.overlay = overlayExport },
+	popupWikiLinks:        func(m *Model) { m.overlay = overlayWikiLinks },
+	popupWikiAutocomplete: func(m *Model) { m.overlay = overlayWikiAutocomplete },
+	popupIssues:           func(m *Model) { m.overlay = overlayIssues },
+	popupMetadata:         func(m *Model) { m.overlay = overlayMetadata },
+	popupGitPanel:         func(m *Model) { m.overlay = overlayGitPanel },
+	popupTrash:            func(m *Model) { m.overlay = overlayTrash },
+	popupNoteStats:        func(m *Model) { m.overlay = overlayNoteStats },
+	popupGitDiff:          func(m *Model) { m.overlay = overlayGitDiff },
+	popupGitLog:           func(m *Model) { m.overlay = overlayGitLog },
+	popupGitRevision:      func(m *Model) { m.overlay = overlayGitLog; m.gitLogRevision = "abc123" },
+	popupHeadingCase:      func(m *Model) { m.overlay = overlayHeadingCase },
+	popupAgenda:           func(m *Model) { m.overlay = overlayAgenda },
+	popupKeymap:           func(m *Model) { m.overlay = overlayKeymap },
+	popupTemplates:        func(m *Model) { m.mode = modeTemplatePicker },
+	popupCommandPalette:   func(m *Model) { m.openCommandPalette() },
+}
+
+func keyPress(key string) tea.KeyMsg {
+	switch key {
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "shift+tab":
+		return tea.KeyMsg{Type: tea.KeyShiftTab}
+	case "ctrl+s":
+		return tea.KeyMsg{Type: tea.KeyCtrlS}
+	case "left":
+		return tea.KeyMsg{Type: tea.KeyLeft}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	case "alt+up":
+		return tea.KeyMsg{Type: tea.KeyUp, Alt: true}
+	case "alt+down":
+		return tea.KeyMsg{Type: tea.KeyDown, Alt: true}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+func newPopupKeysModel(t *testing.T, cfg config.Config) *Model {
+	t.Helper()
+	m := newTestCRUDModel(t.TempDir())
+	m.mode = modeBrowse
+	m.loadKeybindings(cfg)
+	return m
+}
+
+func TestPopupSpecKeysDispatchInTheirPopup(t *testing.T) {
+	m := newPopupKeysModel(t, config.Config{})
+	for popup, spec := range popupSpecs {
+		show, ok := showPopup[popup]
+		if !ok {
+			t.Fatalf("no test setup for popup %q", popup)
+		}
+		m.overlay, m.mode, m.gitLogRevision = overlayNone, modeBrowse, ""
+		show(m)
+		if got := m.activePopup(); got != popup {
+			t.Fatalf("expected %q active, got %q", popup, got)
+		}
+		for _, pk := range spec.keys {
+			if got := m.popupAction(keyPress(pk.key)); got != pk.action {
+				t.Fatalf("%s: %q dispatched %q, want %q", popup, pk.key, got, pk.action)
+			}
+		}
+		if got := m.popupAction(keyPress("esc")); got != actionPopupClose {
+			t.Fatalf("%s: esc dispatched %q", popup, got)
+		}
+	}
+	m.overlay, m.mode = overlayRecent, modeBrowse
+	if got := m.popupAction(keyPress("t")); got != "" {
+		t.Fatalf("expected the tag browser's t to stay out of other popups, got %q", got)
+	}
+}
+
+func TestPopupFeatureKeysRunTheirActions(t *testing.T) {
+	m := newPopupKeysModel(t, config.Config{})
+	m.savedViews = []savedView{{Name: "a", Query: "x"}, {Name: "b", Query: "y"}}
+	m.overlay = overlaySavedViews
+	_, _ = m.handleKey(keyPress("d"))
+	if len(m.savedViews) != 1 || m.savedViews[0].Name != "b" {
+		t.Fatalf("expected d to delete the selected view, got %v", m.savedViews)
+	}
+
+	m.overlay = overlayGitDiff
+	_, _ = m.handleKey(keyPress("q"))
+	if m.overlay != overlayNone || m.status != "Git diff closed" {
+		t.Fatalf("expected q to close the git diff, overlay %v status %q", m.overlay, m.status)
+	}
+
+	m.overlay = overlayTagFilter
+	m.tagFilterTags = []string{"a", "b"}
+	_, _ = m.handleKey(keyPress("2"))
+	if m.tagFilter != "b" {
+		t.Fatalf("expected 2 to apply the second tag, got %q", m.tagFilter)
+	}
+}
+
+func TestPopupCloseRemapAppliesToEveryPopup(t *testing.T) {
+	m := newPopupKeysModel(t, config.Config{Keybindings: map[string]config.KeyList{actionPopupClose: {"x"}}})
+	for popup := range popupSpecs {
+		if popup == popupWikiAutocomplete {
+			continue // opened from the editor only
+		}
+		m.overlay, m.mode, m.gitLogRevision = overlayNone, modeBrowse, ""
+		showPopup[popup](m)
+		_, _ = m.dispatchKey(keyPress("esc"))
+		if m.activePopup() != popup {
+			t.Fatalf("%s: esc still closed the popup after remapping popup.close", popup)
+		}
+		hints := m.statusHelpSegments()
+		if !slices.Contains(hints, "x "+popupSpecs[popup].closeLabel()) {
+			t.Fatalf("%s: footer does not show the remapped close key: %v", popup, hints)
+		}
+		_, _ = m.dispatchKey(keyPress("x"))
+		if m.activePopup() == popup {
+			t.Fatalf("%s: x did not close the popup", popup)
+		}
+	}
+}
+
+func TestPopupKeyConflictKeepsSharedLayer(t *testing.T) {
+	m := newPopupKeysModel(t, config.Config{Keybindings: map[string]config.KeyList{actionPopupSelect: {"t", "enter"}}})
+	m.overlay = overlayTagBrowser
+	if got := m.popupAction(keyPress("t")); got != actionPopupSelect {
+		t.Fatalf("expected the shared layer to keep t, got %q", got)
+	}
+	if got := m.popupAction(keyPress("r")); got != popupActionTagRename {
+		t.Fatalf("expected r to keep renaming, got %q", got)
+	}
+	if m.actionForKey("t") != actionPin {
+		t.Fatalf("expected popup keys to leave browse bindings alone, t runs %q", m.actionForKey("t"))
+	}
+}
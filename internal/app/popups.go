@@ -1,5 +1,5 @@
 // popups.go implements browse-mode popup overlays for recent files, heading
-// outline, and pin/unpin toggling.
+// outline, frontmatter metadata, and pin/unpin toggling.
 //
 // Each popup follows a consistent interaction pattern:
 //
@@ -15,6 +15,9 @@
 // The recent files popup filters the persisted recent-files list to only show
 // entries that still exist on disk and are within the current workspace root.
 //
+// The metadata popup shows the current note's parsed frontmatter (title, tags,
+// category, date, and any other keys) as a read-only key/value list.
+//
 // Pin toggling does not use a popup — it simply toggles the pinned flag for the
 // selected tree item and rebuilds the tree so pinned items float to the top.
 package app
@@ -23,6 +26,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -68,7 +72,7 @@ func (m *Model) handleRecentPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.shouldIgnoreInput(msg) {
 		return m, nil
 	}
-	next, selectPressed, closePressed, handled := handlePopupListNav(msg, m.recentCursor, len(m.recentEntries))
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.recentCursor, len(m.recentEntries))
 	if !handled {
 		return m, nil
 	}
@@ -147,7 +151,7 @@ func (m *Model) handleOutlinePopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.shouldIgnoreInput(msg) {
 		return m, nil
 	}
-	next, selectPressed, closePressed, handled := handlePopupListNav(msg, m.outlineCursor, len(m.outlineHeadings))
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.outlineCursor, len(m.outlineHeadings))
 	if !handled {
 		return m, nil
 	}
@@ -168,11 +172,18 @@ func (m *Model) handleOutlinePopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 // jumpToOutlineHeading scrolls the preview viewport so the selected heading is
-// at the top of the visible area. It first searches the rendered view for the
-// heading text (since Glamour rendering may shift line numbers) and falls back
-// to the raw source line number if no rendered match is found. The viewport
-// offset is saved to per-note position memory so the scroll position persists.
+// at the top of the visible area.
 func (m *Model) jumpToOutlineHeading(heading noteHeading) {
+	m.scrollPreviewToSource(heading.Title, heading.Line)
+	m.status = fmt.Sprintf("Jumped to heading: %s", heading.Title)
+}
+
+// scrollPreviewToSource scrolls the active preview pane to a source location.
+// It first searches the rendered view for anchor (since Glamour rendering may
+// shift line numbers) and falls back to the raw 1-based source line if no
+// rendered match is found. The viewport offset is saved to per-note position
+// memory so the scroll position persists.
+func (m *Model) scrollPreviewToSource(anchor string, line int) {
 	path := m.currentFile
 	secondary := false
 	rendered := m.viewport.View()
@@ -184,23 +195,86 @@ func (m *Model) jumpToOutlineHeading(heading noteHeading) {
 		}
 	}
 	lines := strings.Split(rendered, "\n")
-	target := heading.Title
 	index := -1
-	for i, line := range lines {
-		if strings.Contains(strings.ToLower(line), strings.ToLower(target)) {
-			index = i
-			break
+	if target := strings.ToLower(strings.TrimSpace(anchor)); target != "" {
+		for i, renderedLine := range lines {
+			if strings.Contains(strings.ToLower(renderedLine), target) {
+				index = i
+				break
+			}
 		}
 	}
 	if index < 0 {
-		index = max(0, heading.Line-1)
+		index = max(0, line-1)
 	}
 	m.setPaneOffset(path, secondary, max(0, index))
 	if !secondary {
 		m.viewport.YOffset = max(0, index)
 	}
 	m.saveAppState()
-	m.status = fmt.Sprintf("Jumped to heading: %s", heading.Title)
+}
+
+// openMetadataPopup shows the parsed frontmatter of the current note.
+func (m *Model) openMetadataPopup() {
+	if m.mode != modeBrowse || m.currentFile == "" {
+		m.status = "Select a note first"
+		return
+	}
+	meta, body := parseFrontmatterAndBody(m.currentNoteContent)
+	if body == m.currentNoteContent {
+		m.status = "No frontmatter in current note"
+		return
+	}
+	m.metadataFields = noteMetadataFields(meta, m.dateDisplayFormat)
+	m.metadataCursor = 0
+	m.openOverlay(overlayMetadata)
+	m.showHelp = false
+	m.status = "Metadata: Esc to close"
+}
+
+// handleMetadataPopupKey routes key presses while the metadata popup is
+// visible. Up/Down move through long field lists; Enter or Esc close it.
+func (m *Model) handleMetadataPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.metadataCursor, len(m.metadataFields))
+	if !handled {
+		return m, nil
+	}
+	if closePressed || selectPressed {
+		m.closeOverlay()
+		m.status = "Metadata closed"
+		return m, nil
+	}
+	m.metadataCursor = next
+	return m, nil
+}
+
+// noteMetadataFields flattens NoteMetadata into the rows shown by the
+// metadata popup: the built-in fields first (marked "(not set)" when empty),
+// followed by extra frontmatter keys in file order. Values that parse as
+// dates are shown with dateLayout (see formatNoteDate).
+func noteMetadataFields(meta NoteMetadata, dateLayout string) []MetadataField {
+	orNotSet := func(value string) string {
+		if strings.TrimSpace(value) == "" {
+			return "(not set)"
+		}
+		return value
+	}
+	fields := []MetadataField{
+		{Key: "title", Value: orNotSet(meta.Title)},
+		{Key: "tags", Value: orNotSet(strings.Join(meta.Tags, ", "))},
+		{Key: "category", Value: orNotSet(meta.Category)},
+		{Key: "date", Value: orNotSet(formatNoteDate(meta.Date, dateLayout))},
+	}
+	if meta.WordGoal > 0 {
+		fields = append(fields, MetadataField{Key: "word_goal", Value: strconv.Itoa(meta.WordGoal)})
+	}
+	for _, field := range meta.Extra {
+		fields = append(fields, MetadataField{Key: field.Key, Value: formatNoteDate(field.Value, dateLayout)})
+	}
+	return fields
 }
 
 // parseMarkdownHeadings extracts all ATX-style markdown headings from content.
@@ -0,0 +1,257 @@
+// position_anchor.go recovers saved note positions (state.go) after a note
+// is rewritten outside the app, e.g. by prettier, markdownlint, or a sync
+// tool that reflows paragraphs or converts list markers.
+//
+// Raw offsets stop meaning anything once the text moves, so positions carry
+// anchors next to them:
+//
+//   - The editor cursor keeps up to positionContextRunes of text on each side
+//     (CursorBefore/CursorAfter). On restore, text that still matches at the
+//     saved offset is trusted as-is. Otherwise relocateCursor searches for the
+//     context with whitespace, punctuation, and list markers ignored, and
+//     picks the match nearest the old offset.
+//   - The primary preview offset keeps the heading at or above the top of the
+//     preview (PreviewHeading) and a hash of the content it was measured on
+//     (ContentHash). When the rendered content's hash differs, the preview
+//     scrolls to that heading in the new rendering.
+//
+// Notes the app writes itself go through applyMutationEffects, which moves
+// the hash to the new content (rebaseNotePosition) so in-app edits keep the
+// exact offset as before. Changes reported by the watcher are marked
+// external and keep the old hash, so the preview re-anchors.
+//
+// Encrypted notes get no anchors: the context, heading, and hash all come
+// from the plaintext, and state.json is not encrypted. Their positions are
+// restored from the raw offsets.
+//
+// When no anchor can be found (too little context survives, or the heading is
+// gone), the position falls back to the top of the note with a status of
+// positionResetStatus. The recovered position replaces the saved one, so the
+// status is shown once. Positions saved before anchors existed have no
+// context or hash and are restored as before.
+package app
+
+import (
+	"os"
+	"strings"
+	"unicode"
+)
+
+const (
+	// positionContextRunes is how much text on each side of the editor
+	// cursor is kept to re-locate it.
+	positionContextRunes = 24
+	// minAnchorRunes is the fewest letters and digits a context match needs
+	// before a relocated cursor is trusted.
+	minAnchorRunes = 8
+	// positionResetStatus is shown when a saved position cannot be recovered.
+	positionResetStatus = "Position reset — note changed externally"
+)
+
+// cursorContext returns the text around offset in value that re-locates the
+// cursor later.
+func cursorContext(value string, offset int) (string, string) {
+	runes := []rune(value)
+	offset = clamp(offset, 0, len(runes))
+	return string(runes[max(0, offset-positionContextRunes):offset]),
+		string(runes[offset:min(len(runes), offset+positionContextRunes)])
+}
+
+// cursorContextMatches reports whether value still has before and after
+// around offset.
+func cursorContextMatches(value string, offset int, before, after string) bool {
+	runes := []rune(value)
+	b, a := []rune(before), []rune(after)
+	if offset < len(b) || offset+len(a) > len(runes) {
+		return false
+	}
+	return string(runes[offset-len(b):offset]) == before && string(runes[offset:offset+len(a)]) == after
+}
+
+// anchorText is text reduced to lowercase letters and digits, with the rune
+// offset in the original text of each kept rune.
+type anchorText struct {
+	runes   []rune
+	offsets []int
+}
+
+func newAnchorText(text string) anchorText {
+	var out anchorText
+	for i, r := range []rune(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			out.runes = append(out.runes, unicode.ToLower(r))
+			out.offsets = append(out.offsets, i)
+		}
+	}
+	return out
+}
+
+// nearestMatch returns the index in t of the occurrence of needle whose
+// original offset is closest to near, or -1.
+func (t anchorText) nearestMatch(needle []rune, near int) int {
+	best, bestDist := -1, 0
+	for i := 0; i+len(needle) <= len(t.runes); i++ {
+		if string(t.runes[i:i+len(needle)]) != string(needle) {
+			continue
+		}
+		dist := t.offsets[i] - near
+		if dist < 0 {
+			dist = -dist
+		}
+		if best < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
+
+// relocateCursor finds where a cursor saved with context before|after and
+// offset old belongs in value. It tries the whole context first, then each
+// side alone, and reports false when no match of at least minAnchorRunes
+// letters and digits is found.
+func relocateCursor(value string, old int, before, after string) (int, bool) {
+	text := newAnchorText(value)
+	b, a := newAnchorText(before).runes, newAnchorText(after).runes
+	// Right after a word the cursor sticks to that word; otherwise it goes
+	// in front of the text that followed it.
+	beforeRunes := []rune(before)
+	stickToBefore := len(beforeRunes) > 0 && (unicode.IsLetter(beforeRunes[len(beforeRunes)-1]) || unicode.IsDigit(beforeRunes[len(beforeRunes)-1]))
+
+	if len(b)+len(a) >= minAnchorRunes {
+		needle := append(append([]rune(nil), b...), a...)
+		if i := text.nearestMatch(needle, old); i >= 0 {
+			if len(b) > 0 && (stickToBefore || len(a) == 0) {
+				return text.offsets[i+len(b)-1] + 1, true
+			}
+			return text.offsets[i+len(b)], true
+		}
+	}
+	if len(a) >= minAnchorRunes {
+		if i := text.nearestMatch(a, old); i >= 0 {
+			return text.offsets[i], true
+		}
+	}
+	if len(b) >= minAnchorRunes {
+		if i := text.nearestMatch(b, old); i >= 0 {
+			return text.offsets[i+len(b)-1] + 1, true
+		}
+	}
+	return 0, false
+}
+
+// previewHeadingAt returns the title of the last heading of raw whose
+// rendered line is at or above offset in rendered, or "" when the preview
+// is above the first heading.
+func previewHeadingAt(raw, rendered string, offset int) string {
+	_, body := parseFrontmatterAndBody(raw)
+	lines := strings.Split(strings.ToLower(rendered), "\n")
+	title, from := "", 0
+	for _, heading := range parseMarkdownHeadings(body) {
+		line := renderedLineContaining(lines, heading.Title, from)
+		if line < 0 {
+			continue
+		}
+		if line > offset {
+			break
+		}
+		title, from = heading.Title, line+1
+	}
+	return title
+}
+
+// renderedLineContaining returns the first line at or after from that
+// contains title, ignoring case, or -1. lines must be lowercased.
+func renderedLineContaining(lines []string, title string, from int) int {
+	target := strings.ToLower(strings.TrimSpace(title))
+	if target == "" {
+		return -1
+	}
+	for i := from; i < len(lines); i++ {
+		if strings.Contains(lines[i], target) {
+			return i
+		}
+	}
+	return -1
+}
+
+// anchorPreviewPosition records the heading and content hash for a primary
+// preview offset, using the cached rendering of path. Without one the
+// anchor is cleared and the offset is trusted as-is on restore.
+func (m *Model) anchorPreviewPosition(path string, pos *notePosition) {
+	pos.PreviewHeading, pos.ContentHash = "", ""
+	entry, ok := m.renderCache[path]
+	if !ok || entry.raw == "" || isEncryptedNotePath(path) {
+		return
+	}
+	pos.PreviewHeading = previewHeadingAt(entry.raw, entry.content, pos.PrimaryPreviewOffset)
+	pos.ContentHash = contentHash([]byte(entry.raw))
+}
+
+// recoverPreviewOffset re-anchors the saved primary preview offset of path
+// when the cached rendering is of different content than the offset was
+// measured on. It reports the offset to use.
+func (m *Model) recoverPreviewOffset(path string, pos notePosition) int {
+	offset := pos.PrimaryPreviewOffset
+	if offset <= 0 {
+		offset = pos.PreviewOffset
+	}
+	entry, ok := m.renderCache[path]
+	if pos.ContentHash == "" || !ok || entry.raw == "" {
+		return max(0, offset)
+	}
+	hash := contentHash([]byte(entry.raw))
+	if hash == pos.ContentHash {
+		return max(0, offset)
+	}
+
+	recovered := 0
+	if pos.PreviewHeading != "" {
+		lines := strings.Split(strings.ToLower(entry.content), "\n")
+		if line := renderedLineContaining(lines, pos.PreviewHeading, 0); line >= 0 {
+			recovered = line
+		} else {
+			m.status = positionResetStatus
+		}
+	} else if offset > 0 {
+		m.status = positionResetStatus
+	}
+	pos.PrimaryPreviewOffset = recovered
+	pos.PreviewOffset = recovered
+	pos.ContentHash = hash
+	m.notePositions[path] = pos
+	return recovered
+}
+
+// recoverEditorCursor returns where the saved editor cursor of path belongs
+// in value, re-locating it when the text around it changed.
+func (m *Model) recoverEditorCursor(path, value string, pos notePosition) int {
+	if pos.CursorBefore == "" && pos.CursorAfter == "" {
+		return pos.EditorCursor
+	}
+	if cursorContextMatches(value, pos.EditorCursor, pos.CursorBefore, pos.CursorAfter) {
+		return pos.EditorCursor
+	}
+	offset, ok := relocateCursor(value, pos.EditorCursor, pos.CursorBefore, pos.CursorAfter)
+	if !ok {
+		m.status = positionResetStatus
+	}
+	pos.EditorCursor = offset
+	pos.CursorBefore, pos.CursorAfter = cursorContext(value, offset)
+	m.notePositions[path] = pos
+	return offset
+}
+
+// rebaseNotePosition marks the saved preview offset of path as measured on
+// its current content, after the app itself rewrote the note.
+func (m *Model) rebaseNotePosition(path string) {
+	pos, ok := m.notePositions[path]
+	if !ok || pos.ContentHash == "" {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	pos.ContentHash = contentHash(data)
+	m.notePositions[path] = pos
+}
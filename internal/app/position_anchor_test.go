@@ -0,0 +1,306 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Fixture pair: the same note before and after a formatter reflowed the
+// paragraph and converted "*" list markers to "-".
+const (
+	anchorFixtureBefore = "# Notes\n\nThe quick brown fox jumps over\nthe lazy dog near the riverbank today.\n\n* first item\n* second item\n"
+	anchorFixtureAfter  = "# Notes\n\nThe quick brown fox jumps over the lazy dog near the\nriverbank today.\n\n- first item\n- second item\n"
+)
+
+func runeIndex(t *testing.T, s, sub string) int {
+	t.Helper()
+	idx := strings.Index(s, sub)
+	if idx < 0 {
+		t.Fatalf("%q not in %q", sub, s)
+	}
+	return len([]rune(s[:idx]))
+}
+
+func TestRelocateCursorAfterReformat(t *testing.T) {
+	cases := []struct {
+		name     string
+		old, new string
+		oldAt    int
+		want     int
+	}{
+		{
+			name:  "after a word in a reflowed paragraph",
+			old:   anchorFixtureBefore,
+			new:   anchorFixtureAfter,
+			oldAt: runeIndex(t, anchorFixtureBefore, "lazy") + len("lazy"),
+			want:  runeIndex(t, anchorFixtureAfter, "lazy") + len("lazy"),
+		},
+		{
+			name:  "start of a line that was joined",
+			old:   anchorFixtureBefore,
+			new:   anchorFixtureAfter,
+			oldAt: runeIndex(t, anchorFixtureBefore, "the lazy"),
+			want:  runeIndex(t, anchorFixtureAfter, "the lazy"),
+		},
+		{
+			name:  "list item with a converted marker",
+			old:   anchorFixtureBefore,
+			new:   anchorFixtureAfter,
+			oldAt: runeIndex(t, anchorFixtureBefore, "second"),
+			want:  runeIndex(t, anchorFixtureAfter, "second"),
+		},
+		{
+			name:  "end of note",
+			old:   "# Title\n\nsome closing words",
+			new:   "# Title\n\nsome closing words\n",
+			oldAt: len("# Title\n\nsome closing words"),
+			want:  len("# Title\n\nsome closing words"),
+		},
+	}
+	for _, tc := range cases {
+		before, after := cursorContext(tc.old, tc.oldAt)
+		got, ok := relocateCursor(tc.new, tc.oldAt, before, after)
+		if !ok || got != tc.want {
+			t.Errorf("%s: got %d ok=%v, want %d (%q|%q)", tc.name, got, ok, tc.want, string([]rune(tc.new)[:got]), string([]rune(tc.new)[got:]))
+		}
+	}
+}
+
+func TestRelocateCursorPicksNearestRepeat(t *testing.T) {
+	old := "intro\n\nrepeated paragraph text\n\nmiddle\n\nrepeated paragraph text\n"
+	updated := "intro line added\n\nrepeated paragraph text\n\nmiddle\n\nrepeated paragraph text\n"
+	oldAt := strings.LastIndex(old, "paragraph")
+	before, after := cursorContext(old, oldAt)
+	got, ok := relocateCursor(updated, oldAt, before, after)
+	if want := strings.LastIndex(updated, "paragraph"); !ok || got != want {
+		t.Fatalf("got %d ok=%v, want %d", got, ok, want)
+	}
+}
+
+func TestRelocateCursorLowConfidence(t *testing.T) {
+	before, after := cursorContext(anchorFixtureBefore, runeIndex(t, anchorFixtureBefore, "lazy"))
+	if got, ok := relocateCursor("# Notes\n\nEntirely rewritten.\n", 30, before, after); ok {
+		t.Fatalf("expected no confident match, got %d", got)
+	}
+	// Too little text around the cursor to trust a match.
+	before, after = cursorContext("a b", 1)
+	if got, ok := relocateCursor("b a b", 1, before, after); ok {
+		t.Fatalf("expected short context to be rejected, got %d", got)
+	}
+}
+
+func TestRememberCapturesCursorContext(t *testing.T) {
+	m := newFocusedEditModel("")
+	m.currentFile = "/notes/a.md"
+	offset := runeIndex(t, anchorFixtureBefore, "lazy")
+	m.setEditorValueAndCursorOffset(anchorFixtureBefore, offset)
+	m.rememberNotePosition(m.currentFile)
+
+	pos := m.notePositions[m.currentFile]
+	wantBefore, wantAfter := cursorContext(anchorFixtureBefore, offset)
+	if pos.EditorCursor != offset || pos.CursorBefore != wantBefore || pos.CursorAfter != wantAfter {
+		t.Fatalf("position = %+v", pos)
+	}
+	if !strings.HasSuffix(pos.CursorBefore, "over\nthe ") || !strings.HasPrefix(pos.CursorAfter, "lazy dog") {
+		t.Fatalf("unexpected context %q|%q", pos.CursorBefore, pos.CursorAfter)
+	}
+	if len([]rune(pos.CursorBefore)) != positionContextRunes || len([]rune(pos.CursorAfter)) != positionContextRunes {
+		t.Fatalf("expected %d runes of context on each side", positionContextRunes)
+	}
+}
+
+func TestRestoreEditorCursorRecoversAfterExternalReformat(t *testing.T) {
+	path := "/notes/a.md"
+	m := newFocusedEditModel("")
+	m.editor.SetWidth(120) // keep rows unwrapped so offsets map to lines
+	offset := runeIndex(t, anchorFixtureBefore, "lazy")
+	before, after := cursorContext(anchorFixtureBefore, offset)
+	m.notePositions = map[string]notePosition{path: {EditorCursor: offset, CursorBefore: before, CursorAfter: after}}
+
+	m.setEditorValue(anchorFixtureAfter)
+	m.restoreEditorCursor(path)
+	if got, want := m.currentEditorCursorOffset(), runeIndex(t, anchorFixtureAfter, "lazy"); got != want {
+		t.Fatalf("cursor = %d, want %d", got, want)
+	}
+	if m.status != "" {
+		t.Fatalf("expected a quiet recovery, status %q", m.status)
+	}
+	if pos := m.notePositions[path]; !cursorContextMatches(anchorFixtureAfter, pos.EditorCursor, pos.CursorBefore, pos.CursorAfter) {
+		t.Fatalf("expected the recovered position to be saved, got %+v", pos)
+	}
+
+	m.setEditorValue("# Something else\n\nnothing in common here at all\n")
+	m.restoreEditorCursor(path)
+	if m.currentEditorCursorOffset() != 0 || m.status != positionResetStatus {
+		t.Fatalf("expected a reset to the top, cursor %d status %q", m.currentEditorCursorOffset(), m.status)
+	}
+	m.status = ""
+	m.restoreEditorCursor(path)
+	if m.status != "" {
+		t.Fatalf("expected the reset status only once, got %q", m.status)
+	}
+}
+
+func TestRestoreEditorCursorWithoutContextKeepsOffset(t *testing.T) {
+	path := "/notes/a.md"
+	m := newFocusedEditModel("")
+	m.notePositions = map[string]notePosition{path: {EditorCursor: 5}}
+	m.setEditorValue(anchorFixtureAfter)
+	m.restoreEditorCursor(path)
+	if got := m.currentEditorCursorOffset(); got != 5 || m.status != "" {
+		t.Fatalf("cursor = %d status %q", got, m.status)
+	}
+}
+
+func TestRestorePreviewOffsetReanchorsToHeading(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.md")
+	oldRaw := "# Intro\n\n" + strings.Repeat("intro text\n\n", 10) + "## Setup\n\nsetup text\n\n## Usage\n\nusage text\n"
+	newRaw := "# Intro\n\n" + strings.Repeat("intro text that was reflowed and made longer\n\n", 25) + "## Setup\n\nsetup text\n\n## Usage\n\nusage text\n"
+	mustWriteFile(t, path, oldRaw)
+	m := newTestCRUDModel(root)
+	cache := func(raw string) string {
+		rendered := renderMarkdown(raw, 80)
+		m.renderCache[path] = renderCacheEntry{width: 80, content: rendered, raw: raw}
+		return rendered
+	}
+	headingLine := func(rendered, title string) int {
+		return renderedLineContaining(strings.Split(strings.ToLower(rendered), "\n"), title, 0)
+	}
+
+	oldRendered := cache(oldRaw)
+	oldOffset := headingLine(oldRendered, "Setup") + 1
+	m.setPaneOffset(path, false, oldOffset)
+	if pos := m.notePositions[path]; pos.PreviewHeading != "Setup" || pos.ContentHash != contentHash([]byte(oldRaw)) {
+		t.Fatalf("expected a Setup anchor, got %+v", pos)
+	}
+
+	// An in-app rewrite keeps the exact offset.
+	mustWriteFile(t, path, oldRaw+"\nappended\n")
+	_ = m.applyMutationEffects(mutationEffects{upsertPaths: []string{path}})
+	cache(oldRaw + "\nappended\n")
+	m.restorePreviewOffset(path)
+	if m.viewport.YOffset != oldOffset {
+		t.Fatalf("expected the offset kept after an in-app write, got %d", m.viewport.YOffset)
+	}
+
+	newRendered := cache(newRaw)
+	m.restorePreviewOffset(path)
+	if want := headingLine(newRendered, "Setup"); m.viewport.YOffset != want || want == oldOffset {
+		t.Fatalf("offset = %d, want the Setup heading at %d", m.viewport.YOffset, want)
+	}
+	if m.status == positionResetStatus {
+		t.Fatal("expected no reset status when the heading was found")
+	}
+
+	cache("# Intro\n\nThe note was rewritten without the old sections.\n")
+	m.restorePreviewOffset(path)
+	if m.viewport.YOffset != 0 || m.status != positionResetStatus {
+		t.Fatalf("expected a reset to the top, offset %d status %q", m.viewport.YOffset, m.status)
+	}
+}
+
+func TestNotePositionAnchorsRoundTripState(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.md")
+	mustWriteFile(t, path, "# A\n")
+	legacy := `{"positions":{"a.md":{"preview_offset":3,"editor_cursor":2}}}`
+	if err := os.MkdirAll(filepath.Dir(appStatePath(root)), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	mustWriteFile(t, appStatePath(root), legacy)
+	state, err := loadAppState(root)
+	if err != nil {
+		t.Fatalf("load legacy state: %v", err)
+	}
+	if pos := state.Positions[path]; pos.PrimaryPreviewOffset != 3 || pos.EditorCursor != 2 || pos.ContentHash != "" {
+		t.Fatalf("legacy position = %+v", pos)
+	}
+
+	m := newTestCRUDModel(root)
+	m.notePositions[path] = notePosition{PrimaryPreviewOffset: 4, EditorCursor: 2, CursorBefore: "# ", CursorAfter: "A\n", PreviewHeading: "A", ContentHash: "abc"}
+	m.saveAppState()
+	state, err = loadAppState(root)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if pos := state.Positions[path]; pos.CursorBefore != "# " || pos.CursorAfter != "A\n" || pos.PreviewHeading != "A" || pos.ContentHash != "abc" {
+		t.Fatalf("position = %+v", pos)
+	}
+}
+
+func TestEncryptedNotePositionKeepsNoteTextOutOfState(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "secret.md"+EncryptedNoteExt)
+	plain := "# Vault\n\nlaunch codes 1234 alpha bravo\ncharlie delta echo foxtrot\n"
+	mustWriteFile(t, path, "sealed")
+	moved := filepath.Join(root, "moved.md"+EncryptedNoteExt)
+
+	m := newTestCRUDModel(root)
+	m.mode = modeEditNote
+	m.currentFile = path
+	m.renderCache[path] = renderCacheEntry{width: 80, content: renderMarkdown(plain, 80), raw: plain}
+	m.setEditorValueAndCursorOffset(plain, runeIndex(t, plain, "alpha"))
+	m.viewport.YOffset = 2
+	m.rememberNotePosition(path)
+	// A note encrypted after its position was saved keeps the plain note's
+	// anchors in memory until the next save.
+	m.notePositions[moved] = notePosition{EditorCursor: 4, CursorBefore: "launch ", CursorAfter: "codes", PreviewHeading: "Vault", ContentHash: "abc"}
+	m.saveAppState()
+
+	data, err := os.ReadFile(appStatePath(root))
+	if err != nil {
+		t.Fatalf("read state: %v", err)
+	}
+	for _, text := range []string{"launch", "codes", "alpha", "Vault", "abc"} {
+		if strings.Contains(string(data), text) {
+			t.Fatalf("state.json holds note content %q: %s", text, data)
+		}
+	}
+	state, err := loadAppState(root)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if pos := state.Positions[path]; pos.EditorCursor != runeIndex(t, plain, "alpha") {
+		t.Fatalf("expected the raw cursor offset kept, got %+v", pos)
+	}
+}
+
+func TestRestorePreviewOffsetReanchorsAfterWatcherReformat(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "a.md")
+	oldRaw := "# Intro\n\n" + strings.Repeat("intro text\n\n", 10) + "## Setup\n\nsetup text\n"
+	newRaw := "# Intro\n\n" + strings.Repeat("intro text that was reflowed and made longer\n\n", 25) + "## Setup\n\nsetup text\n"
+	mustWriteFile(t, path, oldRaw)
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.currentFile = path
+	m.fsWatcher = &fsEventWatcher{root: root, batches: make(chan fsEventsMsg)}
+	cache := func(raw string) string {
+		rendered := renderMarkdown(raw, 80)
+		m.renderCache[path] = renderCacheEntry{width: 80, content: rendered, raw: raw}
+		return rendered
+	}
+	headingLine := func(rendered, title string) int {
+		return renderedLineContaining(strings.Split(strings.ToLower(rendered), "\n"), title, 0)
+	}
+
+	oldOffset := headingLine(cache(oldRaw), "Setup") + 1
+	m.viewport.YOffset = oldOffset
+	m.rememberCurrentNotePosition()
+
+	// The formatter rewrites the note; the watcher reports it.
+	mustWriteFile(t, path, newRaw)
+	_, _ = m.handleFSEvents(fsEventsMsg{watcher: m.fsWatcher, paths: []string{path}})
+	if pos := m.notePositions[path]; pos.ContentHash != contentHash([]byte(oldRaw)) || pos.PreviewHeading != "Setup" {
+		t.Fatalf("expected the anchor measured on the old content to survive, got %+v", pos)
+	}
+
+	newRendered := cache(newRaw)
+	m.restorePreviewOffset(path)
+	if want := headingLine(newRendered, "Setup"); m.viewport.YOffset != want || want == oldOffset {
+		t.Fatalf("offset = %d, want the Setup heading at %d", m.viewport.YOffset, want)
+	}
+}
@@ -0,0 +1,186 @@
+// preview_highlight.go colors fenced code blocks in the rendered preview.
+//
+// Glamour highlights code blocks with Chroma using the chroma style named by
+// the markdown style's code_block theme. Instead of Glamour's fixed palette,
+// the preview uses a chroma style built from the active UI theme, so
+// keywords, strings, numbers, comments, and names pick up the preset's
+// accent colors (see codeHighlightStyle). Styles for the built-in presets are
+// registered at startup; a custom theme_file gets its own when applied.
+//
+// Before rendering, prepareCodeFencesForPreview relabels fences Chroma cannot
+// highlight well as plain text: a language it does not know (which would
+// otherwise be guessed from the content) and blocks longer than
+// MaxHighlightedCodeLines, which would make the render slow. Those blocks
+// render as before, uncolored.
+package app
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	chromastyles "github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/glamour"
+	glamourstyles "github.com/charmbracelet/glamour/styles"
+	"github.com/treykane/cli-notes/internal/config"
+	"github.com/treykane/cli-notes/internal/theme"
+)
+
+// plainCodeLanguage is Chroma's plain-text lexer alias; a fence labeled with
+// it renders without token colors.
+const plainCodeLanguage = "text"
+
+func init() {
+	// Register the preset styles before any render runs: Chroma's style
+	// registry is a plain map read by background renders.
+	for _, preset := range []string{
+		config.ThemePresetOceanCitrus,
+		config.ThemePresetSunset,
+		config.ThemePresetNeonSlate,
+		config.ThemePresetLight,
+	} {
+		codeHighlightStyle(paletteForPreset(preset))
+	}
+}
+
+// codeHighlightStyle returns the name of the chroma style for p, registering
+// it the first time. The name is derived from the colors used, so themes
+// with the same code colors share a style.
+func codeHighlightStyle(p theme.Theme) string {
+	entries := chroma.StyleEntries{
+		chroma.Error:             hexColor(p.AccentWarn),
+		chroma.Comment:           "italic " + hexColor(p.TextMuted),
+		chroma.CommentPreproc:    hexColor(p.AccentEdit),
+		chroma.Keyword:           "bold " + hexColor(p.AccentBrowse),
+		chroma.KeywordType:       hexColor(p.AccentEdit),
+		chroma.NameBuiltin:       hexColor(p.AccentEdit),
+		chroma.NameClass:         "bold " + hexColor(p.AccentEdit),
+		chroma.NameFunction:      hexColor(p.EditorCode),
+		chroma.NameTag:           hexColor(p.AccentBrowse),
+		chroma.NameAttribute:     hexColor(p.EditorCode),
+		chroma.LiteralString:     hexColor(p.AccentSuccess),
+		chroma.LiteralNumber:     hexColor(p.AccentWarn),
+		chroma.GenericDeleted:    hexColor(p.AccentWarn),
+		chroma.GenericInserted:   hexColor(p.AccentSuccess),
+		chroma.GenericHeading:    "bold " + hexColor(p.AccentBrowse),
+		chroma.GenericSubheading: hexColor(p.AccentBrowse),
+	}
+	for token, value := range entries {
+		if strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(value, "bold "), "italic ")) == "" {
+			delete(entries, token)
+		}
+	}
+
+	h := fnv.New32a()
+	for _, color := range []string{p.AccentWarn, p.TextMuted, p.AccentEdit, p.AccentBrowse, p.EditorCode, p.AccentSuccess} {
+		h.Write([]byte(color + ","))
+	}
+	name := fmt.Sprintf("cli-notes-%08x", h.Sum32())
+	if _, ok := chromastyles.Registry[name]; !ok {
+		style, err := chroma.NewStyle(name, entries)
+		if err != nil {
+			appLog.Warn("build code highlight style", "style", name, "error", err)
+			return ""
+		}
+		chromastyles.Register(style)
+	}
+	return name
+}
+
+// hexColor converts a theme color ("#rgb", "#rrggbb", or an ANSI 256-color
+// number) to the "#rrggbb" form chroma styles use, or "" when it cannot.
+func hexColor(color string) string {
+	color = strings.TrimSpace(color)
+	if strings.HasPrefix(color, "#") {
+		if len(color) == 4 {
+			return "#" + strings.Repeat(color[1:2], 2) + strings.Repeat(color[2:3], 2) + strings.Repeat(color[3:4], 2)
+		}
+		return color
+	}
+	n, err := strconv.Atoi(color)
+	if err != nil || n < 0 || n > 255 {
+		return ""
+	}
+	var r, g, b int
+	switch {
+	case n < 16:
+		base := [16][3]int{
+			{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0},
+			{0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+			{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+			{0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+		}
+		r, g, b = base[n][0], base[n][1], base[n][2]
+	case n < 232:
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		n -= 16
+		r, g, b = level(n/36), level(n/6%6), level(n%6)
+	default:
+		r = 8 + (n-232)*10
+		g, b = r, r
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// glamourStylesWithCodeTheme returns the standard Glamour style (dark or
+// light) with code blocks highlighted by the chroma style codeStyle.
+func glamourStylesWithCodeTheme(style, codeStyle string) glamour.TermRendererOption {
+	cfg := glamourstyles.DarkStyleConfig
+	if style == theme.MarkdownStyleLight {
+		cfg = glamourstyles.LightStyleConfig
+	}
+	if codeStyle != "" {
+		cfg.CodeBlock.Theme = codeStyle
+		cfg.CodeBlock.Chroma = nil
+	}
+	return glamour.WithStyles(cfg)
+}
+
+// prepareCodeFencesForPreview relabels fenced code blocks that should not be
+// highlighted (unknown language, or more than MaxHighlightedCodeLines lines)
+// as plain text. Other content is returned unchanged.
+func prepareCodeFencesForPreview(content string) string {
+	if !strings.Contains(content, "```") && !strings.Contains(content, "~~~") {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	changed := false
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		marker := codeFenceMarker(trimmed)
+		if marker == "" {
+			continue
+		}
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			closing := strings.TrimSpace(lines[j])
+			if strings.HasPrefix(closing, marker) && strings.Trim(closing, marker[:1]) == "" {
+				end = j
+				break
+			}
+		}
+		language := ""
+		if fields := strings.Fields(trimmed[len(marker):]); len(fields) > 0 {
+			language = fields[0]
+		}
+		tooLong := end-i-1 > MaxHighlightedCodeLines
+		if (tooLong || (language != "" && lexers.Get(language) == nil)) && language != plainCodeLanguage {
+			indent := lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+			lines[i] = indent + marker + plainCodeLanguage
+			changed = true
+		}
+		i = end
+	}
+	if !changed {
+		return content
+	}
+	return strings.Join(lines, "\n")
+}
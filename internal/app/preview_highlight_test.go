@@ -0,0 +1,94 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	chromastyles "github.com/alecthomas/chroma/v2/styles"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func TestPrepareCodeFencesForPreviewRelabelsUnhighlightableBlocks(t *testing.T) {
+	long := strings.Repeat("x := 1\n", MaxHighlightedCodeLines+1)
+	content := "# Title\n" +
+		"```go\nfunc main() {}\n```\n" +
+		"  ```nosuchlang extra\nwhatever\n  ```\n" +
+		"~~~go\n" + long + "~~~\n" +
+		"```\nno language\n```\n"
+
+	got := prepareCodeFencesForPreview(content)
+	want := "# Title\n" +
+		"```go\nfunc main() {}\n```\n" +
+		"  ```text\nwhatever\n  ```\n" +
+		"~~~text\n" + long + "~~~\n" +
+		"```\nno language\n```\n"
+	if got != want {
+		t.Fatalf("unexpected fences:\n%s", got[:min(len(got), 200)])
+	}
+
+	plain := "```python\nprint(1)\n```\n"
+	if got := prepareCodeFencesForPreview(plain); got != plain {
+		t.Fatalf("expected a known language to be left alone, got %q", got)
+	}
+}
+
+func TestHexColorConvertsThemeColors(t *testing.T) {
+	for in, want := range map[string]string{
+		"#abc":    "#aabbcc",
+		"#12ab3f": "#12ab3f",
+		"9":       "#ff0000",
+		"39":      "#00afff",
+		"245":     "#8a8a8a",
+		"bogus":   "",
+		"300":     "",
+	} {
+		if got := hexColor(in); got != want {
+			t.Errorf("hexColor(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCodeHighlightStyleFollowsThemePreset(t *testing.T) {
+	ocean := codeHighlightStyle(paletteForPreset(config.ThemePresetOceanCitrus))
+	light := codeHighlightStyle(paletteForPreset(config.ThemePresetLight))
+	if ocean == "" || light == "" || ocean == light {
+		t.Fatalf("expected distinct styles per preset, got %q and %q", ocean, light)
+	}
+	if _, ok := chromastyles.Registry[ocean]; !ok {
+		t.Fatalf("expected %q to be registered", ocean)
+	}
+	if again := codeHighlightStyle(paletteForPreset(config.ThemePresetOceanCitrus)); again != ocean {
+		t.Fatalf("expected a stable style name, got %q then %q", ocean, again)
+	}
+}
+
+func TestRendererCacheIsKeyedByTheme(t *testing.T) {
+	t.Setenv("CLI_NOTES_GLAMOUR_STYLE", "")
+	t.Setenv("GLAMOUR_STYLE", "")
+	resetRendererCacheForTests()
+	t.Cleanup(func() {
+		applyThemePreset(config.ThemePresetOceanCitrus)
+		resetRendererCacheForTests()
+	})
+
+	applyThemePreset(config.ThemePresetOceanCitrus)
+	if _, err := getRenderer(80); err != nil {
+		t.Fatalf("get renderer: %v", err)
+	}
+	darkKey := currentRendererKey(80)
+	applyThemePreset(config.ThemePresetLight)
+	if _, err := getRenderer(80); err != nil {
+		t.Fatalf("get renderer: %v", err)
+	}
+	lightKey := currentRendererKey(80)
+	if darkKey == lightKey || len(rendererCache) != 2 {
+		t.Fatalf("expected a renderer per preset, got keys %+v and %+v", darkKey, lightKey)
+	}
+	if lightKey.style != "light" {
+		t.Fatalf("expected the light preset to render with the light style, got %q", lightKey.style)
+	}
+	applyThemePreset(config.ThemePresetOceanCitrus)
+	if _, err := getRenderer(80); err != nil || len(rendererCache) != 2 {
+		t.Fatalf("expected the first preset's renderer to be reused, got %d entries", len(rendererCache))
+	}
+}
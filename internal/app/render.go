@@ -7,10 +7,11 @@
 //
 // When the user navigates the tree (e.g. holding down j/k), each cursor move
 // would trigger a new render. Instead, requestRender increments a sequence
-// number and schedules a render after a 500 ms delay. If another navigation
+// number and schedules a render after a short delay. If another navigation
 // happens before the timer fires, the sequence number changes and the stale
 // request is discarded. Only the final render (with the latest sequence) is
-// actually executed.
+// actually executed. The delay adapts to the predicted render cost and to
+// navigation speed (see render_debounce.go), so small notes render at once.
 //
 // # Caching
 //
@@ -24,24 +25,44 @@
 // width changes (e.g. dragging a window edge) reuse cached renders rather than
 // invalidating the cache on every pixel.
 //
+// # Bounded Concurrency
+//
+// Each dispatched render runs as its own tea.Cmd goroutine. A renderLimiter
+// (sized by max_concurrent_renders) caps how many of them read and render at
+// once; the rest wait for a slot. Because requestRender records every new
+// sequence number with the limiter, a waiting render that has been
+// superseded gives up without rendering once it gets (or before it waits
+// for) a slot.
+//
 // # Glamour Renderers
 //
-// Glamour TermRenderer instances are themselves cached per width bucket in a
-// global map (rendererCache) protected by a mutex. Creating a renderer is
-// moderately expensive, so reusing them across renders avoids repeated setup.
-// The rendering style is determined by the CLI_NOTES_GLAMOUR_STYLE or
-// GLAMOUR_STYLE environment variable, defaulting to "dark".
+// Glamour TermRenderer instances are themselves cached per width bucket and
+// theme in a global map (rendererCache) protected by a mutex. Creating a
+// renderer is moderately expensive, so reusing them across renders avoids
+// repeated setup, and keying on the theme means switching presets never
+// reuses a renderer with the old colors. The rendering style is determined by
+// the CLI_NOTES_GLAMOUR_STYLE or GLAMOUR_STYLE environment variable, then the
+// render_style config option (a style name or a Glamour JSON style file),
+// defaulting to the active theme's markdown style ("light" for the light
+// preset, otherwise "dark"). Code blocks are highlighted with the theme's
+// colors (see preview_highlight.go).
 package app
 
 import (
+	"bytes"
 	"container/list"
+	"encoding/json"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/treykane/cli-notes/internal/config"
+	"github.com/treykane/cli-notes/internal/theme"
 )
 
 // renderCacheEntry stores a completed render alongside the inputs that produced
@@ -69,13 +90,14 @@ type renderRequestMsg struct {
 // against the model's current state to discard results that are no longer
 // relevant (e.g. the user navigated away while the render was in flight).
 type renderResultMsg struct {
-	path    string    // file that was rendered
-	width   int       // width bucket used
-	seq     int       // sequence number for staleness detection
-	content string    // ANSI-formatted rendered output
-	raw     string    // raw markdown source
-	mtime   time.Time // file modification time (for cache key)
-	err     error     // non-nil if the render failed
+	path    string        // file that was rendered
+	width   int           // width bucket used
+	seq     int           // sequence number for staleness detection
+	content string        // ANSI-formatted rendered output
+	raw     string        // raw markdown source
+	mtime   time.Time     // file modification time (for cache key)
+	elapsed time.Duration // time spent reading and rendering (slow-op reporting)
+	err     error         // non-nil if the render failed
 }
 
 var (
@@ -88,20 +110,100 @@ var (
 	// so the cache must be thread-safe.
 	rendererCacheMu sync.Mutex
 
-	// rendererCache maps terminal width buckets to reusable Glamour
-	// TermRenderer instances. Creating a renderer involves parsing style
-	// JSON and allocating internal buffers, so caching them avoids
+	// rendererCache maps terminal width buckets and themes to reusable
+	// Glamour TermRenderer instances. Creating a renderer involves parsing
+	// style JSON and allocating internal buffers, so caching them avoids
 	// repeated setup costs when the terminal width hasn't changed.
-	rendererCache = map[int]*glamour.TermRenderer{}
+	rendererCache = map[rendererKey]*glamour.TermRenderer{}
 
-	// rendererCacheOrder tracks width buckets in LRU order (front = least recent,
+	// rendererCacheOrder tracks cache keys in LRU order (front = least recent,
 	// back = most recent).
 	rendererCacheOrder = list.New()
 
-	// rendererCacheNodes stores the LRU-list node for each cached width bucket.
-	rendererCacheNodes = map[int]*list.Element{}
+	// rendererCacheNodes stores the LRU-list node for each cached key.
+	rendererCacheNodes = map[rendererKey]*list.Element{}
+
+	// markdownStyle is the Glamour style of the active theme, used unless an
+	// environment variable overrides it, and codeStyle is the chroma style
+	// for its code blocks. Guarded by rendererCacheMu.
+	markdownStyle = theme.MarkdownStyleDark
+	codeStyle     string
+
+	// renderStyle is the render_style option: a style name, or the path of
+	// the style file whose JSON is renderStyleJSON. Guarded by
+	// rendererCacheMu.
+	renderStyle     string
+	renderStyleJSON []byte
 )
 
+// renderStyleFilePrefix marks a style file in a resolved style name.
+const renderStyleFilePrefix = "file:"
+
+// rendererKey identifies a cached renderer: the width bucket, the resolved
+// Glamour style, and the code highlight style.
+type rendererKey struct {
+	width     int
+	style     string
+	codeStyle string
+}
+
+// renderLimiter bounds the number of renderMarkdownCmd goroutines that run at
+// once and lets queued renders notice they were superseded. A nil limiter
+// imposes no limit.
+type renderLimiter struct {
+	slots  chan struct{}
+	latest atomic.Int64 // highest render sequence number requested so far
+}
+
+// newRenderLimiter returns a limiter allowing at most n concurrent renders
+// (at least one).
+func newRenderLimiter(n int) *renderLimiter {
+	return &renderLimiter{slots: make(chan struct{}, max(1, n))}
+}
+
+// advance records seq as requested, superseding every lower sequence number.
+func (l *renderLimiter) advance(seq int) {
+	if l == nil {
+		return
+	}
+	for {
+		current := l.latest.Load()
+		if int64(seq) <= current || l.latest.CompareAndSwap(current, int64(seq)) {
+			return
+		}
+	}
+}
+
+// superseded reports whether a newer render than seq has been requested.
+func (l *renderLimiter) superseded(seq int) bool {
+	return l != nil && int64(seq) < l.latest.Load()
+}
+
+// acquire waits for a render slot. It returns false without holding a slot
+// when seq is superseded, either before waiting or once a slot frees up.
+func (l *renderLimiter) acquire(seq int) bool {
+	if l == nil {
+		return true
+	}
+	if l.superseded(seq) {
+		return false
+	}
+	l.slots <- struct{}{}
+	if l.superseded(seq) {
+		<-l.slots
+		return false
+	}
+	return true
+}
+
+// release frees a slot taken by a successful acquire.
+func (l *renderLimiter) release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
+
 // maybeShowSelectedFile triggers a render of the currently selected tree item
 // if it is a markdown file. Called after cursor movement so the preview pane
 // tracks the tree selection. Non-markdown files and directories are ignored.
@@ -110,7 +212,7 @@ func (m *Model) maybeShowSelectedFile() tea.Cmd {
 	if item == nil || item.isDir {
 		return nil
 	}
-	if hasSuffixCaseInsensitive(item.path, ".md") {
+	if hasSuffixCaseInsensitive(item.path, ".md") || isEncryptedNotePath(item.path) {
 		return m.setFocusedFile(item.path)
 	}
 	return nil
@@ -128,8 +230,12 @@ func (m *Model) setCurrentFile(path string) tea.Cmd {
 	m.currentFile = path
 	m.trackFileOpen(path)
 	m.trackRecentFile(path)
-	if content, err := os.ReadFile(path); err == nil {
+	if isEncryptedNotePath(path) {
+		// Decrypted by requestRender; the raw bytes are ciphertext.
+		m.currentNoteContent = ""
+	} else if content, err := os.ReadFile(path); err == nil {
 		m.currentNoteContent = string(content)
+		m.refreshMetadataStrip()
 	}
 	return m.requestRender(path)
 }
@@ -149,20 +255,29 @@ func (m *Model) refreshViewport() tea.Cmd {
 // with a matching mtime and width bucket, the cached content is displayed
 // immediately and no Cmd is returned.
 //
-// Slow path (cache miss): A spinner is shown, the renderSeq is incremented
-// (invalidating any in-flight render), and a debounce timer is started. After
-// RenderDebounce (500 ms), a renderRequestMsg is emitted which — if its
-// sequence number still matches — triggers the actual async render via
-// renderMarkdownCmd.
+// Slow path (cache miss): the renderSeq is incremented (invalidating any
+// in-flight render) and a renderRequestMsg is emitted after an adaptive delay
+// (see render_debounce.go) — at once for cheap notes, later for costly ones or
+// during fast navigation. If its sequence number still matches, it triggers
+// the actual async render via renderMarkdownCmd. A spinner replaces the
+// preview only when the wait is expected to be noticeable.
 func (m *Model) requestRender(path string) tea.Cmd {
 	if path == "" {
 		return nil
 	}
+	if isEncryptedNotePath(path) {
+		m.renderEncryptedNote(path)
+		return nil
+	}
 	width := roundWidthToNearestBucket(m.viewport.Width)
+	var size int64
 	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
 		if entry, ok := m.renderCache[path]; ok && entry.width == width && entry.mtime.Equal(info.ModTime()) {
+			m.lastRenderRequest = appNow()
 			m.viewport.SetContent(entry.content)
 			m.currentNoteContent = entry.raw
+			m.refreshMetadataStrip()
 			m.restorePreviewOffset(path)
 			m.rendering = false
 			m.renderingPath = ""
@@ -170,25 +285,40 @@ func (m *Model) requestRender(path string) tea.Cmd {
 			return nil
 		}
 	}
+	delay, placeholder := m.nextRenderDelay(width, size)
 	m.rendering = true
-	m.viewport.SetContent(m.spinner.View() + " Rendering...")
+	m.renderPlaceholder = placeholder
+	if placeholder {
+		m.viewport.SetContent(m.spinner.View() + " Rendering...")
+	}
 	m.renderSeq++
 	seq := m.renderSeq
+	m.renderLimiter.advance(seq)
 	m.pendingPath = path
 	m.pendingWidth = width
 	m.renderingPath = path
 	m.renderingSeq = seq
-	return tea.Tick(RenderDebounce, func(time.Time) tea.Msg {
-		return renderRequestMsg{path: path, width: width, seq: seq}
-	})
+	req := renderRequestMsg{path: path, width: width, seq: seq}
+	if delay <= 0 {
+		return func() tea.Msg { return req }
+	}
+	return renderTick(delay, func(time.Time) tea.Msg { return req })
 }
 
 // renderMarkdownCmd returns a Bubble Tea Cmd that reads and renders a markdown
 // file on a background goroutine. This keeps the UI thread free to process
 // spinner ticks and other input while the (potentially slow) Glamour render
 // runs. The result is sent back to Update as a renderResultMsg.
-func renderMarkdownCmd(path string, width int, seq int) tea.Cmd {
+//
+// The goroutine first takes a slot from limiter. If the render is superseded
+// while it waits, the Cmd returns nil and nothing is read or rendered.
+func renderMarkdownCmd(limiter *renderLimiter, path string, width int, seq int) tea.Cmd {
 	return func() tea.Msg {
+		if !limiter.acquire(seq) {
+			return nil
+		}
+		defer limiter.release()
+		start := time.Now()
 		info, err := os.Stat(path)
 		if err != nil {
 			return renderResultMsg{path: path, width: width, seq: seq, err: err}
@@ -205,6 +335,7 @@ func renderMarkdownCmd(path string, width int, seq int) tea.Cmd {
 			content: rendered,
 			raw:     string(content),
 			mtime:   info.ModTime(),
+			elapsed: time.Since(start),
 		}
 	}
 }
@@ -222,7 +353,7 @@ func renderMarkdown(content string, width int) string {
 		appLog.Error("create markdown renderer", "width", width, "error", err)
 		return content
 	}
-	out, err := renderer.Render(content)
+	out, err := renderer.Render(prepareCodeFencesForPreview(content))
 	if err != nil {
 		appLog.Error("render markdown content", "width", width, "error", err)
 		return content
@@ -230,80 +361,173 @@ func renderMarkdown(content string, width int) string {
 	return out
 }
 
-// getRenderer returns a cached Glamour TermRenderer for the given width,
-// creating one if it doesn't exist. The renderer is configured with word
-// wrapping at the specified width and the user's chosen Glamour style.
-// Access is serialized via rendererCacheMu since renders may run concurrently
-// on background goroutines.
+// getRenderer returns a cached Glamour TermRenderer for the given width and
+// the active theme, creating one if it doesn't exist. The renderer is
+// configured with word wrapping at the specified width and the user's chosen
+// Glamour style. Access is serialized via rendererCacheMu since renders may
+// run concurrently on background goroutines.
 func getRenderer(width int) (*glamour.TermRenderer, error) {
 	if width <= 0 {
 		width = 80
 	}
 	rendererCacheMu.Lock()
 	defer rendererCacheMu.Unlock()
-	if renderer, ok := rendererCache[width]; ok {
-		if node, ok := rendererCacheNodes[width]; ok {
+	key := currentRendererKey(width)
+	if renderer, ok := rendererCache[key]; ok {
+		if node, ok := rendererCacheNodes[key]; ok {
 			rendererCacheOrder.MoveToBack(node)
 		}
 		return renderer, nil
 	}
 	renderer, err := glamour.NewTermRenderer(
-		glamourStyleOption(),
+		glamourStyleOption(key.style, key.codeStyle),
 		glamour.WithWordWrap(width),
 	)
 	if err != nil {
 		return nil, err
 	}
-	rendererCache[width] = renderer
-	rendererCacheNodes[width] = rendererCacheOrder.PushBack(width)
+	rendererCache[key] = renderer
+	rendererCacheNodes[key] = rendererCacheOrder.PushBack(key)
 	evictOldestRendererIfNeeded()
 	return renderer, nil
 }
 
+// currentRendererKey returns the cache key for width under the active theme.
+// Callers hold rendererCacheMu.
+func currentRendererKey(width int) rendererKey {
+	return rendererKey{width: width, style: glamourStyleName(), codeStyle: codeStyle}
+}
+
 func evictOldestRendererIfNeeded() {
 	for len(rendererCache) > maxRendererCacheEntries && rendererCacheOrder.Len() > 0 {
 		oldest := rendererCacheOrder.Front()
-		width, _ := oldest.Value.(int)
+		key, _ := oldest.Value.(rendererKey)
 		rendererCacheOrder.Remove(oldest)
-		delete(rendererCache, width)
-		delete(rendererCacheNodes, width)
+		delete(rendererCache, key)
+		delete(rendererCacheNodes, key)
+	}
+}
+
+// setMarkdownTheme records the theme's Glamour style ("" means dark) and
+// code highlight style. Renderers are cached per theme, so the next render
+// picks them up.
+func setMarkdownTheme(p theme.Theme) {
+	style := p.MarkdownStyle
+	if style == "" {
+		style = theme.MarkdownStyleDark
+	}
+	code := codeHighlightStyle(p)
+	rendererCacheMu.Lock()
+	defer rendererCacheMu.Unlock()
+	markdownStyle = style
+	codeStyle = code
+}
+
+// setRenderStyle records the render_style option and reports whether it
+// changed, in which case the cached renderers are dropped (a style file may
+// have been edited in place). A style file that cannot be read or parsed is
+// logged and replaced by auto.
+func setRenderStyle(style string) bool {
+	var data []byte
+	switch style {
+	case "", config.RenderStyleAuto, config.RenderStyleDark, config.RenderStyleLight, config.RenderStyleNotty:
+	default:
+		var err error
+		data, err = os.ReadFile(style)
+		if err == nil {
+			var parsed ansi.StyleConfig
+			err = json.Unmarshal(data, &parsed)
+		}
+		if err != nil {
+			appLog.Warn("load render_style file; using auto", "path", style, "error", err)
+			style, data = config.RenderStyleAuto, nil
+		}
+	}
+	rendererCacheMu.Lock()
+	defer rendererCacheMu.Unlock()
+	if style == renderStyle && bytes.Equal(data, renderStyleJSON) {
+		return false
+	}
+	renderStyle, renderStyleJSON = style, data
+	clearRendererCacheLocked()
+	return true
+}
+
+// applyRenderStyle applies the render_style option and, when it changed,
+// drops the cached previews so notes render again in the new style.
+func (m *Model) applyRenderStyle(style string) {
+	if setRenderStyle(style) {
+		m.renderCache = map[string]renderCacheEntry{}
 	}
 }
 
 func resetRendererCacheForTests() {
 	rendererCacheMu.Lock()
 	defer rendererCacheMu.Unlock()
-	rendererCache = map[int]*glamour.TermRenderer{}
+	clearRendererCacheLocked()
+}
+
+// clearRendererCacheLocked drops every cached renderer. Callers hold
+// rendererCacheMu.
+func clearRendererCacheLocked() {
+	rendererCache = map[rendererKey]*glamour.TermRenderer{}
 	rendererCacheOrder = list.New()
-	rendererCacheNodes = map[int]*list.Element{}
+	rendererCacheNodes = map[rendererKey]*list.Element{}
 }
 
-// glamourStyleOption resolves the Glamour rendering style from environment
+// glamourStyleName resolves the Glamour rendering style from environment
 // variables. The lookup order is:
 //
 //  1. CLI_NOTES_GLAMOUR_STYLE (app-specific override)
 //  2. GLAMOUR_STYLE (Glamour's own environment variable)
-//  3. "dark" (hardcoded default — avoids OSC background queries that can
-//     leak escape sequences into the editor)
+//  3. The render_style config option (renderStyle); a style file resolves
+//     to "file:" followed by its path
+//  4. The active theme's markdown style (markdownStyle: "light" for the
+//     light preset, "dark" otherwise — an explicit style avoids OSC
+//     background queries that can leak escape sequences into the editor)
 //
 // The special value "auto" delegates to Glamour's auto-detection, which
 // queries the terminal's background color. All other values are passed
-// through as standard style names (dark, light, notty).
-func glamourStyleOption() glamour.TermRendererOption {
+// through as standard style names (dark, light, notty); anything else means
+// dark.
+//
+// Callers hold rendererCacheMu, which also guards markdownStyle and
+// renderStyle.
+func glamourStyleName() string {
 	style := strings.ToLower(strings.TrimSpace(os.Getenv("CLI_NOTES_GLAMOUR_STYLE")))
 	if style == "" {
 		style = strings.ToLower(strings.TrimSpace(os.Getenv("GLAMOUR_STYLE")))
 	}
+	if style == "" && renderStyleJSON != nil {
+		return renderStyleFilePrefix + renderStyle
+	}
 	if style == "" {
-		style = "dark"
+		style = renderStyle
 	}
-	if style == "auto" {
-		return glamour.WithAutoStyle()
+	if style == "" {
+		style = markdownStyle
+	}
+	switch style {
+	case "auto", "dark", "light", "notty":
+		return style
+	default:
+		return "dark"
+	}
+}
+
+// glamourStyleOption returns the renderer option for a style from
+// glamourStyleName. A style file brings its own code block colors; dark and
+// light use codeStyle when set.
+func glamourStyleOption(style, codeStyle string) glamour.TermRendererOption {
+	if strings.HasPrefix(style, renderStyleFilePrefix) {
+		return glamour.WithStylesFromJSONBytes(renderStyleJSON)
 	}
 	switch style {
-	case "dark", "light", "notty":
+	case "auto":
+		return glamour.WithAutoStyle()
+	case "notty":
 		return glamour.WithStandardStyle(style)
 	default:
-		return glamour.WithStandardStyle("dark")
+		return glamourStylesWithCodeTheme(style, codeStyle)
 	}
 }
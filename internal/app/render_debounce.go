@@ -0,0 +1,111 @@
+// render_debounce.go picks how long requestRender waits before a preview
+// render is dispatched.
+//
+// A fixed delay is wrong both ways: small notes render in a few milliseconds
+// and gain nothing from waiting, while large notes are still worth holding
+// back while the user scrolls past them. The delay is therefore derived from
+// the predicted render cost:
+//
+//   - Each finished render records its time per byte in a rolling average
+//     for its width bucket (renderCostModel). Before the first sample, a
+//     conservative default rate is assumed.
+//   - A note whose predicted cost is below RenderInstantThreshold is
+//     dispatched without a timer; costlier notes wait as long as they are
+//     predicted to take, capped at RenderDebounce.
+//   - While render requests arrive faster than RenderNavBurstInterval (held
+//     j/k, fast clicking), every request waits at least RenderNavRestDelay.
+//     Each new request supersedes the previous one through renderSeq, so a
+//     burst dispatches a single render once the cursor rests.
+//
+// The "Rendering..." placeholder is shown right away only when the predicted
+// wait reaches RenderPlaceholderDelay. Otherwise the previous preview stays
+// up, and the spinner tick shows the placeholder if the wait turns out to be
+// longer than predicted.
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// renderTick schedules a delayed render request. Tests replace it to observe
+// delays without waiting for them.
+var renderTick = tea.Tick
+
+const (
+	// renderCostSmoothing weights the newest sample in the rolling average.
+	renderCostSmoothing = 0.3
+	// defaultRenderNsPerByte is the assumed cost before a width bucket has
+	// any samples (about 1 ms per KB).
+	defaultRenderNsPerByte = 1000
+)
+
+// renderCostModel is a rolling average of render cost per width bucket, in
+// nanoseconds per byte of markdown. The zero value is ready to use.
+type renderCostModel struct {
+	nsPerByte map[int]float64
+}
+
+// record folds one finished render into the average for its width bucket.
+// Empty notes say nothing about the per-byte cost and are ignored.
+func (c *renderCostModel) record(width int, bytes int64, elapsed time.Duration) {
+	if bytes <= 0 || elapsed <= 0 {
+		return
+	}
+	sample := float64(elapsed) / float64(bytes)
+	if c.nsPerByte == nil {
+		c.nsPerByte = map[int]float64{}
+	}
+	if current, ok := c.nsPerByte[width]; ok {
+		sample = current + renderCostSmoothing*(sample-current)
+	}
+	c.nsPerByte[width] = sample
+}
+
+// predict estimates how long rendering bytes of markdown at width takes.
+func (c *renderCostModel) predict(width int, bytes int64) time.Duration {
+	rate, ok := c.nsPerByte[width]
+	if !ok {
+		rate = defaultRenderNsPerByte
+	}
+	return time.Duration(rate * float64(max(bytes, 0)))
+}
+
+// renderDelay returns the debounce delay for a render predicted to take
+// predicted, given the time since the previous render request.
+func renderDelay(predicted, sinceLast time.Duration) time.Duration {
+	delay := time.Duration(0)
+	if predicted >= RenderInstantThreshold {
+		delay = min(predicted, RenderDebounce)
+	}
+	if sinceLast < RenderNavBurstInterval {
+		delay = max(delay, RenderNavRestDelay)
+	}
+	return delay
+}
+
+// nextRenderDelay works out the delay for a render of size bytes at width and
+// records the request time for burst detection. It also reports whether the
+// placeholder should be shown immediately.
+func (m *Model) nextRenderDelay(width int, size int64) (time.Duration, bool) {
+	now := appNow()
+	sinceLast := RenderNavBurstInterval
+	if !m.lastRenderRequest.IsZero() {
+		sinceLast = now.Sub(m.lastRenderRequest)
+	}
+	m.lastRenderRequest = now
+	m.renderRequestedAt = now
+	predicted := m.renderCosts.predict(width, size)
+	delay := renderDelay(predicted, sinceLast)
+	return delay, delay+predicted >= RenderPlaceholderDelay
+}
+
+// renderPlaceholderDue reports whether a pending render has waited long
+// enough that the placeholder should replace the previous preview.
+func (m *Model) renderPlaceholderDue() bool {
+	if !m.rendering {
+		return false
+	}
+	return m.renderPlaceholder || appNow().Sub(m.renderRequestedAt) >= RenderPlaceholderDelay
+}
@@ -0,0 +1,218 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// delayedRenderMsg wraps a render request captured from the stubbed
+// renderTick together with its delay.
+type delayedRenderMsg struct {
+	delay time.Duration
+	msg   tea.Msg
+}
+
+// renderSim drives requestRender through a fake clock and counts the renders
+// that handleRenderRequest actually dispatches.
+type renderSim struct {
+	t          *testing.T
+	m          *Model
+	now        time.Time
+	pending    []scheduledRender
+	delays     []time.Duration
+	dispatched []string
+}
+
+type scheduledRender struct {
+	at  time.Time
+	msg renderRequestMsg
+}
+
+func newRenderSim(t *testing.T) *renderSim {
+	t.Helper()
+	sim := &renderSim{
+		t:   t,
+		now: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		m: &Model{
+			viewport:    viewport.New(81, 5),
+			spinner:     spinner.New(),
+			renderCache: map[string]renderCacheEntry{},
+		},
+	}
+	stubNow(t, func() time.Time { return sim.now })
+	oldTick := renderTick
+	renderTick = func(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+		return func() tea.Msg { return delayedRenderMsg{delay: d, msg: fn(time.Time{})} }
+	}
+	t.Cleanup(func() { renderTick = oldTick })
+	return sim
+}
+
+// navigate requests a render of path after gap and schedules the request.
+func (s *renderSim) navigate(gap time.Duration, path string) {
+	s.advance(gap)
+	cmd := s.m.requestRender(path)
+	if cmd == nil {
+		s.t.Fatalf("expected a render request for %s", path)
+	}
+	delay := time.Duration(0)
+	msg := cmd()
+	if delayed, ok := msg.(delayedRenderMsg); ok {
+		delay, msg = delayed.delay, delayed.msg
+	}
+	s.delays = append(s.delays, delay)
+	s.pending = append(s.pending, scheduledRender{at: s.now.Add(delay), msg: msg.(renderRequestMsg)})
+	s.advance(0)
+}
+
+// advance moves the clock forward by d, delivering every request that fires
+// on the way in time order.
+func (s *renderSim) advance(d time.Duration) {
+	end := s.now.Add(d)
+	sort.SliceStable(s.pending, func(i, j int) bool { return s.pending[i].at.Before(s.pending[j].at) })
+	for len(s.pending) > 0 && !s.pending[0].at.After(end) {
+		next := s.pending[0]
+		s.pending = s.pending[1:]
+		s.now = next.at
+		if _, cmd := s.m.handleRenderRequest(next.msg); cmd != nil {
+			s.dispatched = append(s.dispatched, filepath.Base(next.msg.path))
+		}
+	}
+	s.now = end
+}
+
+func writeRenderNotes(t *testing.T, n, size int) []string {
+	t.Helper()
+	root := t.TempDir()
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = filepath.Join(root, fmt.Sprintf("note-%02d.md", i))
+		mustWriteFile(t, paths[i], "# note\n"+strings.Repeat("x", size))
+	}
+	return paths
+}
+
+func TestFastNavigationDispatchesOnlyWhenCursorRests(t *testing.T) {
+	sim := newRenderSim(t)
+	paths := writeRenderNotes(t, 10, 100)
+
+	for _, path := range paths {
+		sim.navigate(30*time.Millisecond, path)
+	}
+	sim.advance(time.Second)
+
+	want := []string{"note-00.md", "note-09.md"}
+	if strings.Join(sim.dispatched, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected renders %v, got %v", want, sim.dispatched)
+	}
+	if sim.delays[0] != 0 {
+		t.Fatalf("expected the first small note to render at once, got %v", sim.delays[0])
+	}
+	for i, delay := range sim.delays[1:] {
+		if delay != RenderNavRestDelay {
+			t.Fatalf("request %d: expected rest delay during fast navigation, got %v", i+1, delay)
+		}
+	}
+}
+
+func TestSlowNavigationRendersSmallNotesImmediately(t *testing.T) {
+	sim := newRenderSim(t)
+	paths := writeRenderNotes(t, 5, 100)
+
+	for _, path := range paths {
+		sim.navigate(400*time.Millisecond, path)
+		if sim.m.renderPlaceholder {
+			t.Fatalf("expected no placeholder for %s", path)
+		}
+	}
+	sim.advance(time.Second)
+
+	if len(sim.dispatched) != len(paths) {
+		t.Fatalf("expected every note rendered, got %v", sim.dispatched)
+	}
+	for i, delay := range sim.delays {
+		if delay != 0 {
+			t.Fatalf("request %d: expected no debounce, got %v", i, delay)
+		}
+	}
+}
+
+func TestCostlyNotesWaitForPredictedCost(t *testing.T) {
+	sim := newRenderSim(t)
+	paths := writeRenderNotes(t, 3, 200*1024)
+	size := int64(len("# note\n") + 200*1024)
+
+	// 1 µs per byte: a note of ~200 KB is predicted at ~200 ms.
+	sim.m.renderCosts.record(80, size, time.Duration(size)*time.Microsecond)
+	sim.navigate(time.Second, paths[0])
+	if want := time.Duration(size) * time.Microsecond; sim.delays[0] != want {
+		t.Fatalf("expected delay %v, got %v", want, sim.delays[0])
+	}
+	if !sim.m.renderPlaceholder || !strings.Contains(sim.m.viewport.View(), "Rendering...") {
+		t.Fatalf("expected placeholder for a costly render, got %q", sim.m.viewport.View())
+	}
+
+	// Much slower renders are capped at RenderDebounce.
+	sim.m.renderCosts = renderCostModel{}
+	sim.m.renderCosts.record(80, size, time.Duration(size)*10*time.Microsecond)
+	sim.navigate(time.Second, paths[1])
+	if sim.delays[1] != RenderDebounce {
+		t.Fatalf("expected delay capped at %v, got %v", RenderDebounce, sim.delays[1])
+	}
+
+	// Skipping quickly past a costly note supersedes its pending render.
+	sim.navigate(50*time.Millisecond, paths[2])
+	sim.advance(time.Second)
+	want := []string{"note-00.md", "note-02.md"}
+	if strings.Join(sim.dispatched, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected renders %v, got %v", want, sim.dispatched)
+	}
+}
+
+func TestRenderCostModelRollingAverage(t *testing.T) {
+	var costs renderCostModel
+	if got := costs.predict(80, 1000); got != time.Millisecond {
+		t.Fatalf("expected default prediction of 1ms, got %v", got)
+	}
+	costs.record(80, 1000, 10*time.Millisecond)
+	if got := costs.predict(80, 1000); got != 10*time.Millisecond {
+		t.Fatalf("expected first sample to set the average, got %v", got)
+	}
+	costs.record(80, 1000, 20*time.Millisecond)
+	if got := costs.predict(80, 1000); got != 13*time.Millisecond {
+		t.Fatalf("expected smoothed average of 13ms, got %v", got)
+	}
+	if got := costs.predict(100, 1000); got != time.Millisecond {
+		t.Fatalf("expected other width buckets unaffected, got %v", got)
+	}
+	costs.record(80, 0, time.Second)
+	if got := costs.predict(80, 1000); got != 13*time.Millisecond {
+		t.Fatalf("expected empty notes ignored, got %v", got)
+	}
+}
+
+func TestSpinnerShowsPlaceholderWhenWaitExceedsPrediction(t *testing.T) {
+	sim := newRenderSim(t)
+	paths := writeRenderNotes(t, 1, 100)
+	sim.m.viewport.SetContent("previous note")
+
+	sim.navigate(time.Second, paths[0])
+	sim.m.rendering = true // the dispatched render is still running
+	_, _ = sim.m.handleSpinnerTick(spinner.TickMsg{})
+	if strings.Contains(sim.m.viewport.View(), "Rendering...") {
+		t.Fatal("expected previous preview kept while the wait is short")
+	}
+	sim.advance(RenderPlaceholderDelay)
+	_, _ = sim.m.handleSpinnerTick(spinner.TickMsg{})
+	if !strings.Contains(sim.m.viewport.View(), "Rendering...") {
+		t.Fatalf("expected placeholder once the render runs long, got %q", sim.m.viewport.View())
+	}
+}
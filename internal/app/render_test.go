@@ -4,7 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -79,8 +82,8 @@ func TestRequestRenderStartsAsyncRenderWhenCacheMissing(t *testing.T) {
 	if m.renderSeq != 1 || m.renderingSeq != 1 {
 		t.Fatalf("expected render sequence to be 1/1, got %d/%d", m.renderSeq, m.renderingSeq)
 	}
-	if !strings.Contains(m.viewport.View(), "Rendering...") {
-		t.Fatalf("expected rendering indicator in viewport, got %q", m.viewport.View())
+	if m.renderPlaceholder || strings.Contains(m.viewport.View(), "Rendering...") {
+		t.Fatalf("expected no rendering indicator for a note that renders at once, got %q", m.viewport.View())
 	}
 }
 
@@ -101,10 +104,10 @@ func TestGetRendererCacheIsBoundedByCap(t *testing.T) {
 	if got := len(rendererCache); got != maxRendererCacheEntries {
 		t.Fatalf("expected renderer cache size %d, got %d", maxRendererCacheEntries, got)
 	}
-	if _, ok := rendererCache[20]; ok {
+	if _, ok := rendererCache[currentRendererKey(20)]; ok {
 		t.Fatal("expected oldest width to be evicted")
 	}
-	if _, ok := rendererCache[240]; !ok {
+	if _, ok := rendererCache[currentRendererKey(240)]; !ok {
 		t.Fatal("expected newest width to remain cached")
 	}
 }
@@ -129,10 +132,153 @@ func TestGetRendererLRURefreshesRecentlyUsedWidth(t *testing.T) {
 		t.Fatalf("insert getRenderer(40): %v", err)
 	}
 
-	if _, ok := rendererCache[20]; ok {
+	if _, ok := rendererCache[currentRendererKey(20)]; ok {
 		t.Fatal("expected width 20 to be evicted as least recently used")
 	}
-	if _, ok := rendererCache[10]; !ok {
+	if _, ok := rendererCache[currentRendererKey(10)]; !ok {
 		t.Fatal("expected width 10 to remain after recent access")
 	}
 }
+
+func TestRenderLimiterBoundsConcurrentRenders(t *testing.T) {
+	const limit = 2
+	limiter := newRenderLimiter(limit)
+	var (
+		active  atomic.Int32
+		peak    atomic.Int32
+		started sync.WaitGroup
+		done    sync.WaitGroup
+	)
+	release := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			started.Done()
+			if !limiter.acquire(0) {
+				t.Error("expected unsuperseded render to acquire a slot")
+				return
+			}
+			defer limiter.release()
+			now := active.Add(1)
+			for {
+				prev := peak.Load()
+				if now <= prev || peak.CompareAndSwap(prev, now) {
+					break
+				}
+			}
+			<-release
+			active.Add(-1)
+		}()
+	}
+	started.Wait()
+	time.Sleep(20 * time.Millisecond)
+	if got := active.Load(); got != limit {
+		t.Fatalf("expected %d renders running while the rest queue, got %d", limit, got)
+	}
+	close(release)
+	done.Wait()
+	if got := peak.Load(); got > limit {
+		t.Fatalf("expected at most %d concurrent renders, peak was %d", limit, got)
+	}
+}
+
+func TestRenderLimiterDropsSupersededRenders(t *testing.T) {
+	limiter := newRenderLimiter(1)
+	limiter.advance(1)
+	if !limiter.acquire(1) {
+		t.Fatal("expected current render to acquire")
+	}
+
+	// Render 2 queues behind render 1, then is superseded by 3 while waiting.
+	limiter.advance(2)
+	queued := make(chan bool)
+	go func() { queued <- limiter.acquire(2) }()
+	time.Sleep(10 * time.Millisecond)
+	limiter.advance(3)
+	limiter.release()
+	if <-queued {
+		t.Fatal("expected superseded queued render to be dropped")
+	}
+	if limiter.acquire(2) {
+		t.Fatal("expected stale render to be dropped before waiting")
+	}
+	if !limiter.acquire(3) {
+		t.Fatal("expected latest render to acquire the freed slot")
+	}
+	limiter.release()
+	limiter.advance(2)
+	if limiter.superseded(3) {
+		t.Fatal("expected advance to never move the latest sequence backwards")
+	}
+}
+
+func TestRenderMarkdownCmdSkipsSupersededSeq(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "note.md")
+	mustWriteFile(t, path, "# note\n")
+	limiter := newRenderLimiter(1)
+	limiter.advance(5)
+
+	if msg := renderMarkdownCmd(limiter, path, 80, 4)(); msg != nil {
+		t.Fatalf("expected superseded render to produce no message, got %#v", msg)
+	}
+	msg, ok := renderMarkdownCmd(limiter, path, 80, 5)().(renderResultMsg)
+	if !ok || msg.err != nil || msg.raw != "# note\n" {
+		t.Fatalf("expected current render result, got %#v", msg)
+	}
+	if len(limiter.slots) != 0 {
+		t.Fatalf("expected render slot released, %d held", len(limiter.slots))
+	}
+}
+
+func TestRenderStyleSelectsRendererAndInvalidatesCaches(t *testing.T) {
+	t.Setenv("CLI_NOTES_GLAMOUR_STYLE", "")
+	t.Setenv("GLAMOUR_STYLE", "")
+	resetRendererCacheForTests()
+	t.Cleanup(func() {
+		setRenderStyle("")
+		resetRendererCacheForTests()
+	})
+	styleKey := func() string {
+		rendererCacheMu.Lock()
+		defer rendererCacheMu.Unlock()
+		return currentRendererKey(80).style
+	}
+
+	m := &Model{renderCache: map[string]renderCacheEntry{"note.md": {width: 80}}}
+	m.applyRenderStyle("notty")
+	if styleKey() != "notty" || len(m.renderCache) != 0 {
+		t.Fatalf("expected notty and a cleared render cache, got %q with %d entries", styleKey(), len(m.renderCache))
+	}
+	if setRenderStyle("notty") {
+		t.Fatal("expected an unchanged style to keep the caches")
+	}
+
+	path := filepath.Join(t.TempDir(), "style.json")
+	mustWriteFile(t, path, `{"document": {"margin": 1}}`)
+	if !setRenderStyle(path) || styleKey() != renderStyleFilePrefix+path {
+		t.Fatalf("expected the style file to be used, got %q", styleKey())
+	}
+	if _, err := getRenderer(80); err != nil {
+		t.Fatalf("renderer from style file: %v", err)
+	}
+	mustWriteFile(t, path, `{"document": {"margin": 2}}`)
+	if !setRenderStyle(path) || len(rendererCache) != 0 {
+		t.Fatal("expected an edited style file to drop the cached renderers")
+	}
+
+	mustWriteFile(t, path, `{"document": `)
+	if setRenderStyle(path); styleKey() != "auto" {
+		t.Fatalf("expected an invalid style file to fall back to auto, got %q", styleKey())
+	}
+	if setRenderStyle(filepath.Join(t.TempDir(), "missing.json")); styleKey() != "auto" {
+		t.Fatalf("expected a missing style file to fall back to auto, got %q", styleKey())
+	}
+
+	t.Setenv("CLI_NOTES_GLAMOUR_STYLE", "light")
+	if styleKey() != "light" {
+		t.Fatalf("expected the environment to override render_style, got %q", styleKey())
+	}
+}
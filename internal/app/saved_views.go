@@ -0,0 +1,199 @@
+// saved_views.go implements saved search views: named search queries kept in
+// workspace state so frequent filters (e.g. "tag:todo -tag:done") are one
+// keystroke away.
+//
+// Ctrl+S in the search popup saves the current query as a view, asking for
+// a name prefilled with the query. Saving under an existing name (ignoring
+// case) replaces that view's query. The saved-views popup (Alt+V) lists the
+// views in the order they were first saved; Enter or 1-9 opens the search
+// popup pre-filled with the view's query and runs it, and d deletes the
+// selected view.
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// savedView is a named search query. It is stored as-is in the state file.
+type savedView struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// normalizeSavedViews drops views without a name or query and later
+// duplicates of a name, ignoring case.
+func normalizeSavedViews(views []savedView) []savedView {
+	out := make([]savedView, 0, len(views))
+	for _, view := range views {
+		view.Name = strings.TrimSpace(view.Name)
+		view.Query = strings.TrimSpace(view.Query)
+		if view.Name == "" || view.Query == "" || savedViewIndex(out, view.Name) >= 0 {
+			continue
+		}
+		out = append(out, view)
+	}
+	return out
+}
+
+// savedViewIndex returns the index of the view called name, or -1.
+func savedViewIndex(views []savedView, name string) int {
+	return slices.IndexFunc(views, func(view savedView) bool {
+		return strings.EqualFold(view.Name, name)
+	})
+}
+
+// saveSearchView stores query under name, replacing a view of the same name,
+// and persists workspace state.
+func (m *Model) saveSearchView(name, query string) {
+	view := savedView{Name: strings.TrimSpace(name), Query: strings.TrimSpace(query)}
+	if idx := savedViewIndex(m.savedViews, view.Name); idx >= 0 {
+		m.savedViews[idx] = view
+	} else {
+		m.savedViews = append(m.savedViews, view)
+	}
+	m.saveAppState()
+}
+
+// startSaveSearchView leaves the search popup for the view-name prompt.
+func (m *Model) startSaveSearchView() {
+	query := strings.TrimSpace(m.search.Value())
+	if query == "" {
+		m.status = "Type a query before saving it as a view"
+		return
+	}
+	m.rememberSearchTag(query)
+	m.closeSearchPopup()
+	m.savedViewDraftQuery = query
+	m.mode = modeSaveView
+	m.showHelp = false
+	m.input.Reset()
+	m.input.Placeholder = "View name"
+	m.input.SetValue(query)
+	m.input.CursorEnd()
+	m.input.Focus()
+	m.status = "Save view: enter a name"
+}
+
+// handleSaveViewKey processes keypresses in the view-name prompt.
+func (m *Model) handleSaveViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	return m.handleInputModeKey(msg, m.saveSearchViewInput, "Save view cancelled")
+}
+
+func (m *Model) saveSearchViewInput() (tea.Model, tea.Cmd) {
+	name := strings.TrimSpace(m.input.Value())
+	if name == "" {
+		m.status = "View name is required"
+		return m, nil
+	}
+	m.saveSearchView(name, m.savedViewDraftQuery)
+	m.mode = modeBrowse
+	m.savedViewDraftQuery = ""
+	m.status = fmt.Sprintf("Saved view %q", name)
+	return m, nil
+}
+
+// openSavedViewsPopup shows the saved views, or says how to save one.
+func (m *Model) openSavedViewsPopup() {
+	if len(m.savedViews) == 0 {
+		m.status = "No saved views: press Ctrl+S in the search popup to save one"
+		return
+	}
+	m.openOverlay(overlaySavedViews)
+	m.savedViewCursor = clamp(m.savedViewCursor, 0, len(m.savedViews)-1)
+	m.status = "Saved views: Enter to search, d to delete, Esc to close"
+}
+
+// handleSavedViewsPopupKey routes key presses while the saved-views popup is
+// visible.
+func (m *Model) handleSavedViewsPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	if idx, ok := m.popupQuickSelect(msg); ok {
+		if idx < len(m.savedViews) {
+			m.savedViewCursor = idx
+			m.openSavedView(m.savedViews[idx])
+		}
+		return m, nil
+	}
+	if m.popupAction(msg) == popupActionDeleteView {
+		m.deleteSelectedSavedView()
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.savedViewCursor, len(m.savedViews))
+	if !handled {
+		return m, nil
+	}
+	if closePressed {
+		m.closeOverlay()
+		m.status = "Saved views closed"
+		return m, nil
+	}
+	if len(m.savedViews) == 0 {
+		return m, nil
+	}
+	m.savedViewCursor = next
+	if selectPressed {
+		m.openSavedView(m.savedViews[m.savedViewCursor])
+	}
+	return m, nil
+}
+
+// openSavedView opens the search popup with the view's query and runs it.
+func (m *Model) openSavedView(view savedView) {
+	m.openSearchPopup()
+	m.search.SetValue(view.Query)
+	m.search.CursorEnd()
+	m.updateSearchRows()
+}
+
+// deleteSelectedSavedView removes the view under the popup cursor, closing
+// the popup when none are left.
+func (m *Model) deleteSelectedSavedView() {
+	if m.savedViewCursor < 0 || m.savedViewCursor >= len(m.savedViews) {
+		return
+	}
+	name := m.savedViews[m.savedViewCursor].Name
+	m.savedViews = slices.Delete(m.savedViews, m.savedViewCursor, m.savedViewCursor+1)
+	m.saveAppState()
+	m.status = fmt.Sprintf("Deleted view %q", name)
+	if len(m.savedViews) == 0 {
+		m.closeOverlay()
+		return
+	}
+	m.savedViewCursor = min(m.savedViewCursor, len(m.savedViews)-1)
+}
+
+// renderSavedViewsPopup draws the saved-views popup: number, name, query.
+func (m *Model) renderSavedViewsPopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	lines := []string{
+		titleStyle.Render("Saved Views (" + m.primaryActionKey(actionSavedViews, "Alt+V") + ")"),
+		"",
+	}
+	limit := max(0, innerHeight-len(lines)-1)
+	start := 0
+	if limit > 0 {
+		start = max(0, m.savedViewCursor-limit+1)
+	}
+	for i := start; i < min(start+limit, len(m.savedViews)); i++ {
+		view := m.savedViews[i]
+		number := "  "
+		if i < 9 {
+			number = fmt.Sprintf("%d ", i+1)
+		}
+		line := truncate(number+view.Name+"  ("+view.Query+")", innerWidth)
+		if i == m.savedViewCursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, mutedStyle.Render("Enter/1-9: search  d: delete  Esc: close"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func TestSaveSearchViewPersistsIt(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.search = textinput.New()
+	m.loadKeybindings(config.Config{})
+
+	m.openSearchPopup()
+	m.search.SetValue("tag:todo -tag:done")
+	_, _ = m.handleSearchKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.mode != modeSaveView || m.isOverlay(overlaySearch) || m.input.Value() != "tag:todo -tag:done" {
+		t.Fatalf("expected the name prompt prefilled with the query, mode %v input %q", m.mode, m.input.Value())
+	}
+	m.input.SetValue("Open todos")
+	_, _ = m.handleSaveViewKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeBrowse || m.status != `Saved view "Open todos"` {
+		t.Fatalf("mode %v status %q", m.mode, m.status)
+	}
+
+	m.saveSearchView("open TODOS", "tag:todo")
+	m.saveSearchView("Drafts", "tag:draft")
+	state, err := loadAppState(root)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	want := []savedView{{Name: "open TODOS", Query: "tag:todo"}, {Name: "Drafts", Query: "tag:draft"}}
+	if len(state.SavedViews) != len(want) || state.SavedViews[0] != want[0] || state.SavedViews[1] != want[1] {
+		t.Fatalf("saved views = %#v, want %#v", state.SavedViews, want)
+	}
+}
+
+func TestSelectingSavedViewRerunsQuery(t *testing.T) {
+	root := t.TempDir()
+	open := filepath.Join(root, "open.md")
+	mustWriteFile(t, open, "---\ntags: [todo]\n---\n# Open\n")
+	mustWriteFile(t, filepath.Join(root, "done.md"), "---\ntags: [todo, done]\n---\n# Done\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.search = textinput.New()
+	m.loadKeybindings(config.Config{})
+	m.savedViews = []savedView{{Name: "Everything", Query: "todo"}, {Name: "Open todos", Query: "tag:todo -tag:done"}}
+
+	_, _ = m.handleBrowseKey("alt+v")
+	if !m.isOverlay(overlaySavedViews) {
+		t.Fatalf("expected saved views popup, overlay %v status %q", m.overlay, m.status)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	if !m.isOverlay(overlaySearch) || m.search.Value() != "tag:todo -tag:done" {
+		t.Fatalf("expected search popup with the view's query, overlay %v query %q", m.overlay, m.search.Value())
+	}
+	if len(m.searchResults) != 1 || m.searchResults[0].path != open {
+		t.Fatalf("search results = %#v", m.searchResults)
+	}
+
+	m.closeSearchPopup()
+	m.openSavedViewsPopup()
+	if m.savedViewCursor != 1 {
+		t.Fatalf("expected the popup to reopen on the last view, cursor %d", m.savedViewCursor)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyUp})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if len(m.savedViews) != 1 || m.savedViews[0].Name != "Open todos" {
+		t.Fatalf("expected the first view deleted, got %#v", m.savedViews)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.search.Value() != "tag:todo -tag:done" {
+		t.Fatalf("expected Enter to open the remaining view, query %q", m.search.Value())
+	}
+	state, _ := loadAppState(root)
+	if len(state.SavedViews) != 1 || !strings.EqualFold(state.SavedViews[0].Name, "open todos") {
+		t.Fatalf("persisted views = %#v", state.SavedViews)
+	}
+}
@@ -6,7 +6,8 @@
 //   - Free-text terms: matched case-insensitively against filename, frontmatter
 //     title, frontmatter category, and note body content.
 //   - Tag filters: queries containing "tag:<name>" restrict results to notes
-//     whose YAML frontmatter includes the specified tag(s).
+//     whose YAML frontmatter includes the specified tag(s); "-tag:<name>"
+//     drops notes that have the tag.
 //
 // Files larger than MaxSearchFileBytes (1 MiB) are excluded from content
 // indexing to avoid excessive memory use, but their filenames are still
@@ -15,9 +16,18 @@
 // The index stores pre-lowercased copies of all searchable fields so that
 // query matching is a simple strings.Contains call with no per-query
 // allocation for case folding.
+//
+// Every mutation (invalidate, rebuild, upsert, remove) goes through apply,
+// which reconciles the touched path against the disk, so
+// events for the same path arriving late or out of order converge on the
+// filesystem's current state instead of leaving stale documents behind. After
+// each mutation the sorted path slice is checked against the document map and
+// rebuilt if they diverged; debug builds (-tags debug) also verify ordering,
+// uniqueness, and that no managed path was indexed.
 package app
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -52,6 +62,27 @@ type searchIndex struct {
 	docs        map[string]searchDoc // path -> indexed document
 	sortedPaths []string             // lexicographically sorted paths for prefix range operations
 	ready       bool                 // true after a successful build; false after invalidate()
+	version     int                  // bumped on every document change, so derived views know when to recompute
+	// caseInsensitive mirrors Model.caseInsensitiveFS; only then can a stat
+	// succeed for a spelling the folder does not list (see statExactName).
+	caseInsensitive bool
+}
+
+// searchIndexOpKind names a mutation applied through searchIndex.apply.
+type searchIndexOpKind int
+
+const (
+	searchOpInvalidate searchIndexOpKind = iota // mark the index stale
+	searchOpRebuild                             // discard everything and walk the root again
+	searchOpUpsert                              // a path was created or changed
+	searchOpRemove                              // a path was deleted or moved away
+)
+
+// searchIndexOp is one mutation of the index. path is unused by
+// searchOpInvalidate and searchOpRebuild.
+type searchIndexOp struct {
+	kind searchIndexOpKind
+	path string
 }
 
 // newSearchIndex creates an unbuilt search index rooted at the given directory.
@@ -67,7 +98,7 @@ func newSearchIndex(root string) *searchIndex {
 // ensureBuilt call. This is used when the file watcher detects external
 // changes or when the user explicitly refreshes (Shift+R).
 func (i *searchIndex) invalidate() {
-	i.ready = false
+	_ = i.apply(searchIndexOp{kind: searchOpInvalidate})
 }
 
 // ensureBuilt lazily builds the index if it has not been built yet or was
@@ -84,8 +115,32 @@ func (i *searchIndex) ensureBuilt() error {
 // is marked ready; on failure it remains in an unready state so the next
 // ensureBuilt call will retry.
 func (i *searchIndex) build() error {
+	return i.apply(searchIndexOp{kind: searchOpRebuild})
+}
+
+// apply performs one mutation. It is the only way the index changes, and it
+// checks the index invariants before returning (see verifyInvariants). Like
+// every other index method it runs on the Bubble Tea update goroutine. Only
+// a rebuild returns an error.
+func (i *searchIndex) apply(op searchIndexOp) error {
+	var err error
+	switch op.kind {
+	case searchOpInvalidate:
+		i.ready = false
+	case searchOpRebuild:
+		err = i.rebuild()
+	case searchOpUpsert, searchOpRemove:
+		i.syncPath(op.path)
+	}
+	i.verifyInvariants()
+	return err
+}
+
+// rebuild discards every document and walks the root again.
+func (i *searchIndex) rebuild() error {
 	i.docs = map[string]searchDoc{}
 	i.sortedPaths = nil
+	i.version++
 	if err := i.walk(i.root, 0); err != nil {
 		i.ready = false
 		return err
@@ -129,43 +184,49 @@ func (i *searchIndex) walk(dir string, depth int) error {
 //
 // Query parsing (via parseSearchQuery):
 //   - Tokens prefixed with "tag:" are treated as tag filters.
+//   - Tokens prefixed with "-tag:" exclude notes with that tag.
+//   - "in:archive" includes archived notes, which are skipped otherwise.
 //   - All other tokens are free-text search terms.
 //
 // Matching algorithm:
 //  1. Parse the query into tag terms and text terms.
 //  2. For each indexed document, check tag match first (all specified tags
-//     must be present in the document's frontmatter tags).
+//     must be present in the document's frontmatter tags, and no excluded
+//     tag may be).
 //  3. Then check text match: every text term must appear in at least one of
 //     the document's searchable fields (filename, title, category, or body
 //     content). Directory entries are only matched against their name.
-//  4. Tag-only queries (no text terms) exclude directories and documents
-//     without tags, since tag filtering only applies to markdown files.
+//  4. Tag-only queries (no text terms) exclude directories, since tag
+//     filtering only applies to markdown files, and "tag:" queries also
+//     exclude documents without tags.
 //  5. Results are sorted: directories first, then alphabetically by path.
 //
 // Returns nil if the query is empty or has no terms after parsing.
 func (i *searchIndex) search(query string) []treeItem {
 	parsed := parseSearchQuery(query)
-	if len(parsed.textTerms) == 0 && len(parsed.tagTerms) == 0 {
+	tagOnly := len(parsed.textTerms) == 0
+	if tagOnly && len(parsed.tagTerms) == 0 && len(parsed.excludedTags) == 0 {
 		return nil
 	}
 
 	results := make([]treeItem, 0, 32)
 	for _, doc := range i.docs {
-		if !docMatchesTags(doc, parsed.tagTerms) {
+		if !parsed.inArchive && isArchivedPath(i.root, doc.item.path) {
 			continue
 		}
-		if !docMatchesText(doc, parsed.textTerms) {
+		if !docMatchesTags(doc, parsed.tagTerms) || docHasAnyTag(doc, parsed.excludedTags) {
 			continue
 		}
-		if len(parsed.textTerms) == 0 && len(parsed.tagTerms) > 0 && doc.item.isDir {
+		if !docMatchesText(doc, parsed.textTerms) {
 			continue
 		}
-		if len(parsed.textTerms) == 0 && len(parsed.tagTerms) > 0 && len(doc.tagsLower) == 0 {
+		if tagOnly && doc.item.isDir {
 			continue
 		}
-		if len(parsed.textTerms) > 0 || len(parsed.tagTerms) > 0 {
-			results = append(results, doc.item)
+		if tagOnly && len(parsed.tagTerms) > 0 && len(doc.tagsLower) == 0 {
+			continue
 		}
+		results = append(results, doc.item)
 	}
 
 	sort.Slice(results, func(a, b int) bool {
@@ -187,25 +248,48 @@ func (i *searchIndex) search(query string) []treeItem {
 // because there is nothing to update incrementally — the next ensureBuilt
 // call will do a full rebuild anyway.
 func (i *searchIndex) upsertPath(path string) {
-	if !i.ready {
+	_ = i.apply(searchIndexOp{kind: searchOpUpsert, path: path})
+}
+
+// removePath removes a single path and all its descendants from the index.
+// A path that exists on disk again by the time the removal is applied (a
+// delete event arriving after the note was recreated) is re-indexed instead.
+// This is a no-op if the index is not built.
+func (i *searchIndex) removePath(path string) {
+	_ = i.apply(searchIndexOp{kind: searchOpRemove, path: path})
+}
+
+// syncPath makes the index agree with the disk for path and everything
+// below it, whichever event reported the change. Upserts and removals both
+// land here so the order they are applied in does not matter: a path that
+// exists is (re)indexed along with any missing parent folders, and a path
+// that is gone is dropped with its descendants. Paths outside the root or
+// inside a managed folder are ignored; the root itself is rebuilt.
+func (i *searchIndex) syncPath(path string) {
+	if !i.ready || path == "" {
 		return
 	}
-	if !isWithinRoot(i.root, path) {
+	path = filepath.Clean(path)
+	if !isWithinRoot(i.root, path) || i.isManagedPath(path) {
 		return
 	}
-	if shouldSkipManagedPath(filepath.Base(path)) {
+	if path == filepath.Clean(i.root) {
+		if err := i.rebuild(); err != nil {
+			appLog.Warn("rebuild search index", "path", path, "error", err)
+		}
 		return
 	}
 
-	info, err := os.Stat(path)
-	if err != nil {
-		i.removePath(path)
+	info, ok := i.stat(path)
+	if !ok {
+		i.deleteDoc(path)
+		i.removeDescendants(path)
 		return
 	}
 
+	i.indexParents(path)
 	depth := depthFromRoot(i.root, path)
-	name := filepath.Base(path)
-	i.indexPath(path, name, depth, info.IsDir())
+	i.indexPath(path, filepath.Base(path), depth, info.IsDir())
 	if !info.IsDir() {
 		return
 	}
@@ -216,14 +300,79 @@ func (i *searchIndex) upsertPath(path string) {
 	}
 }
 
-// removePath removes a single path and all its descendants from the index.
-// This is a no-op if the index is not built.
-func (i *searchIndex) removePath(path string) {
-	if !i.ready {
-		return
+// indexParents adds the folders between the root and path that are not
+// indexed yet, as when a note is created in a new folder before (or
+// without) an event for the folder itself.
+func (i *searchIndex) indexParents(path string) {
+	root := filepath.Clean(i.root)
+	var missing []string
+	for dir := filepath.Dir(path); dir != root && isWithinRoot(root, dir); dir = filepath.Dir(dir) {
+		if _, ok := i.docs[dir]; ok {
+			break
+		}
+		missing = append(missing, dir)
 	}
-	i.deleteDoc(path)
-	i.removeDescendants(path)
+	for n := len(missing) - 1; n >= 0; n-- {
+		dir := missing[n]
+		i.indexPath(dir, filepath.Base(dir), depthFromRoot(root, dir), true)
+	}
+}
+
+// isManagedPath reports whether any component of path below the root is a
+// managed folder, which walk never descends into.
+func (i *searchIndex) isManagedPath(path string) bool {
+	rel, err := filepath.Rel(i.root, path)
+	if err != nil {
+		return false
+	}
+	for _, part := range strings.Split(rel, string(os.PathSeparator)) {
+		if shouldSkipManagedPath(part) {
+			return true
+		}
+	}
+	return false
+}
+
+// stat stats path. On a case-insensitive filesystem it also checks the
+// spelling against the parent folder listing (statExactName); elsewhere a
+// successful stat already means the name exists exactly, and skipping the
+// listing keeps bulk imports into one folder linear.
+func (i *searchIndex) stat(path string) (os.FileInfo, bool) {
+	if i.caseInsensitive {
+		return statExactName(path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	return info, true
+}
+
+// statExactName stats path and also requires its final element to be
+// listed in the parent folder with exactly that spelling. On a
+// case-insensitive filesystem a late event for "note.md" after a rename to
+// "Note.md" would otherwise index the same file twice.
+func statExactName(path string) (os.FileInfo, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return info, true
+	}
+	defer dir.Close()
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return info, true
+	}
+	base := filepath.Base(path)
+	for _, name := range names {
+		if name == base {
+			return info, true
+		}
+	}
+	return nil, false
 }
 
 // removeDescendants deletes all indexed entries whose path is a child of the
@@ -239,6 +388,7 @@ func (i *searchIndex) removeDescendants(path string) {
 		end++
 	}
 	if end > start {
+		i.version++
 		i.sortedPaths = append(i.sortedPaths[:start], i.sortedPaths[end:]...)
 	}
 }
@@ -269,18 +419,69 @@ func (i *searchIndex) indexPath(path, name string, depth int, isDir bool) {
 	i.upsertDoc(path, doc)
 }
 
+// ensurePathIndex rebuilds the sorted path slice when it has visibly fallen
+// out of step with the document map (for example after docs were set
+// directly).
 func (i *searchIndex) ensurePathIndex() {
 	if len(i.sortedPaths) == len(i.docs) {
 		return
 	}
+	i.repairPathIndex()
+}
+
+// verifyInvariants runs after every apply. Release builds only compare the
+// sizes of the path slice and the document map; debug builds run the full
+// checkInvariants and log any violation. Either way a divergence is repaired
+// by rebuilding the path slice from the documents.
+func (i *searchIndex) verifyInvariants() {
+	if !searchIndexDebug {
+		i.ensurePathIndex()
+		return
+	}
+	if err := i.checkInvariants(); err != nil {
+		appLog.Warn("search index invariant violated; repairing", "root", i.root, "error", err)
+		i.repairPathIndex()
+	}
+}
+
+// checkInvariants reports the first way the index is inconsistent: the path
+// slice and document map differ in size or content, the slice is unsorted or
+// holds duplicates, or a managed folder's path was indexed.
+func (i *searchIndex) checkInvariants() error {
+	if len(i.sortedPaths) != len(i.docs) {
+		return fmt.Errorf("%d sorted paths for %d documents", len(i.sortedPaths), len(i.docs))
+	}
+	for n, path := range i.sortedPaths {
+		if n > 0 && i.sortedPaths[n-1] >= path {
+			return fmt.Errorf("sorted paths out of order or duplicated at %q", path)
+		}
+		if _, ok := i.docs[path]; !ok {
+			return fmt.Errorf("sorted path %q has no document", path)
+		}
+		if i.isManagedPath(path) {
+			return errors.New("managed path indexed: " + path)
+		}
+	}
+	return nil
+}
+
+// repairPathIndex drops documents from managed folders, which must never be
+// indexed, and rebuilds the sorted path slice from the document map.
+func (i *searchIndex) repairPathIndex() {
 	i.sortedPaths = i.sortedPaths[:0]
 	for path := range i.docs {
+		if i.isManagedPath(path) {
+			delete(i.docs, path)
+			i.version++
+			continue
+		}
 		i.sortedPaths = append(i.sortedPaths, path)
 	}
 	sort.Strings(i.sortedPaths)
 }
 
 func (i *searchIndex) upsertDoc(path string, doc searchDoc) {
+	i.version++
 	if _, exists := i.docs[path]; exists {
 		i.docs[path] = doc
 		return
@@ -297,6 +498,7 @@ func (i *searchIndex) deleteDoc(path string) {
 	if _, exists := i.docs[path]; !exists {
 		return
 	}
+	i.version++
 	i.ensurePathIndex()
 	delete(i.docs, path)
 	pos := sort.SearchStrings(i.sortedPaths, path)
@@ -385,6 +587,18 @@ func docMatchesTags(doc searchDoc, tags []string) bool {
 	return true
 }
 
+// docHasAnyTag reports whether the document has at least one of tags.
+func docHasAnyTag(doc searchDoc, tags []string) bool {
+	for _, tag := range tags {
+		for _, have := range doc.tagsLower {
+			if have == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // noteTarget represents a candidate for wiki-link autocomplete. It carries
 // the note's path, frontmatter title (if any), and filename stem so the
 // autocomplete popup can display the most useful label.
@@ -415,6 +629,59 @@ func (i *searchIndex) noteTargets() []noteTarget {
 	return out
 }
 
+// knownTags returns every tag used by the indexed notes, sorted. It sources
+// tag completion in the editor's frontmatter block.
+func (i *searchIndex) knownTags() []string {
+	seen := map[string]bool{}
+	for _, doc := range i.docs {
+		for _, tag := range doc.metadata.Tags {
+			seen[tag] = true
+		}
+	}
+	return sortedSet(seen)
+}
+
+// tagCount is one tag and the number of indexed notes carrying it.
+type tagCount struct {
+	name  string
+	count int
+}
+
+// allTags returns every tag used by the indexed notes with its note count,
+// most used first and then by name. It sources the tag browser.
+func (i *searchIndex) allTags() []tagCount {
+	counts := map[string]int{}
+	for _, doc := range i.docs {
+		for _, tag := range doc.tagsLower {
+			counts[tag]++
+		}
+	}
+	out := make([]tagCount, 0, len(counts))
+	for name, count := range counts {
+		out = append(out, tagCount{name: name, count: count})
+	}
+	sort.Slice(out, func(a, b int) bool {
+		if out[a].count != out[b].count {
+			return out[a].count > out[b].count
+		}
+		return out[a].name < out[b].name
+	})
+	return out
+}
+
+// extraFrontmatterKeys returns the frontmatter keys the app does not
+// interpret (NoteMetadata.Extra) seen in the indexed notes, lowercased and
+// sorted.
+func (i *searchIndex) extraFrontmatterKeys() []string {
+	seen := map[string]bool{}
+	for _, doc := range i.docs {
+		for _, field := range doc.metadata.Extra {
+			seen[strings.ToLower(field.Key)] = true
+		}
+	}
+	return sortedSet(seen)
+}
+
 // resolveWikiTarget attempts to find a note matching the given wiki-link label.
 //
 // Resolution strategy (first match wins):
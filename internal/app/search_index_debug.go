@@ -0,0 +1,9 @@
+// search_index_debug.go enables the full search index invariant checks in
+// builds made with -tags debug. See verifyInvariants in search_index.go.
+
+//go:build debug
+
+package app
+
+// searchIndexDebug turns on checkInvariants after every index mutation.
+const searchIndexDebug = true
@@ -0,0 +1,10 @@
+// search_index_release.go keeps the search index invariant checks cheap in
+// normal builds: only the size comparison in ensurePathIndex runs. See
+// search_index_debug.go for the -tags debug variant.
+
+//go:build !debug
+
+package app
+
+// searchIndexDebug turns on checkInvariants after every index mutation.
+const searchIndexDebug = false
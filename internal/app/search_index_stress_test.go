@@ -0,0 +1,147 @@
+package app
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// TestSearchIndexConvergesUnderRandomizedUpdates changes a small notes tree
+// thousands of times, queues the events each change would produce, and
+// applies them in random order with duplicates, dropped-while-invalid events,
+// and rebuilds in between. Once every event is applied the index must match a
+// fresh build of the final tree.
+func TestSearchIndexConvergesUnderRandomizedUpdates(t *testing.T) {
+	for _, seed := range []int64{1, 2, 3} {
+		t.Run(fmt.Sprintf("seed-%d", seed), func(t *testing.T) {
+			runSearchIndexStress(t, seed, 1500)
+		})
+	}
+}
+
+func runSearchIndexStress(t *testing.T, seed int64, steps int) {
+	rng := rand.New(rand.NewSource(seed))
+	root := t.TempDir()
+	dirs := []string{"a", "b", filepath.Join("a", "deep"), managedNotesDirName, filepath.Join("b", managedNotesDirName)}
+	names := []string{"one.md", "two.md", "three.md", "notes.txt"}
+	randomDir := func() string {
+		return filepath.Join(root, dirs[rng.Intn(len(dirs))])
+	}
+	randomFile := func() string {
+		if rng.Intn(4) == 0 {
+			return filepath.Join(root, names[rng.Intn(len(names))])
+		}
+		return filepath.Join(randomDir(), names[rng.Intn(len(names))])
+	}
+
+	idx := newSearchIndex(root)
+	if err := idx.ensureBuilt(); err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	var pending []searchIndexOp
+	queue := func(kind searchIndexOpKind, path string) {
+		pending = append(pending, searchIndexOp{kind: kind, path: path})
+	}
+	applyRandom := func() {
+		n := rng.Intn(len(pending))
+		op := pending[n]
+		if rng.Intn(10) > 0 {
+			pending[n] = pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+		}
+		_ = idx.apply(op)
+	}
+
+	for step := 0; step < steps; step++ {
+		switch rng.Intn(8) {
+		case 0, 1, 2:
+			path := randomFile()
+			if err := os.MkdirAll(filepath.Dir(path), DirPermission); err != nil {
+				t.Fatalf("mkdir: %v", err)
+			}
+			content := fmt.Sprintf("---\ntags: [t%d]\n---\nstep %d\n", rng.Intn(3), step)
+			if err := os.WriteFile(path, []byte(content), FilePermission); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			queue(searchOpUpsert, path)
+			if dir := filepath.Dir(path); dir != root && rng.Intn(6) == 0 {
+				queue(searchOpUpsert, dir)
+			}
+		case 3:
+			path := randomFile()
+			_ = os.Remove(path)
+			queue(searchOpRemove, path)
+		case 4:
+			dir := randomDir()
+			_ = os.RemoveAll(dir)
+			queue(searchOpRemove, dir)
+		case 5:
+			from, to := randomFile(), randomFile()
+			if rng.Intn(2) == 0 {
+				from, to = randomDir(), randomDir()
+			}
+			if _, err := os.Stat(filepath.Dir(to)); err != nil {
+				if err := os.MkdirAll(filepath.Dir(to), DirPermission); err != nil {
+					t.Fatalf("mkdir: %v", err)
+				}
+				queue(searchOpUpsert, filepath.Dir(to))
+			}
+			if os.Rename(from, to) == nil {
+				queue(searchOpRemove, from)
+				queue(searchOpUpsert, to)
+			}
+		case 6:
+			if rng.Intn(4) == 0 {
+				// A bulk invalidation (e.g. after a pull) whose rebuild
+				// completes a few events later.
+				idx.invalidate()
+			} else if err := idx.ensureBuilt(); err != nil {
+				t.Fatalf("rebuild: %v", err)
+			}
+		}
+		for n := rng.Intn(3); n > 0 && len(pending) > 0; n-- {
+			applyRandom()
+		}
+		if err := idx.checkInvariants(); err != nil {
+			t.Fatalf("step %d: %v", step, err)
+		}
+	}
+
+	if err := idx.ensureBuilt(); err != nil {
+		t.Fatalf("final build: %v", err)
+	}
+	rng.Shuffle(len(pending), func(a, b int) { pending[a], pending[b] = pending[b], pending[a] })
+	for _, op := range pending {
+		_ = idx.apply(op)
+	}
+
+	fresh := newSearchIndex(root)
+	if err := fresh.ensureBuilt(); err != nil {
+		t.Fatalf("fresh build: %v", err)
+	}
+	if err := idx.checkInvariants(); err != nil {
+		t.Fatalf("final index: %v", err)
+	}
+	for _, path := range idx.sortedPaths {
+		if _, ok := fresh.docs[path]; !ok {
+			t.Fatalf("stale path indexed: %s", path)
+		}
+	}
+	for _, path := range fresh.sortedPaths {
+		if _, ok := idx.docs[path]; !ok {
+			t.Fatalf("path missing from the index: %s", path)
+		}
+	}
+	for path, want := range fresh.docs {
+		if got := idx.docs[path]; !reflect.DeepEqual(got, want) {
+			t.Fatalf("document %s diverged from a fresh build:\n got %+v\nwant %+v", path, got, want)
+		}
+	}
+	if !sort.StringsAreSorted(idx.sortedPaths) {
+		t.Fatalf("paths unsorted: %v", idx.sortedPaths)
+	}
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -78,3 +79,125 @@ func TestSearchIndexTagQueryMatchesFrontmatterTags(t *testing.T) {
 	expectContains(t, got, "Alpha.md")
 	expectNotContains(t, got, "Beta.md")
 }
+
+func TestSearchIndexExcludedTagDropsNotes(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "Open.md"), "---\ntags: [todo]\n---\nhello\n")
+	mustWriteFile(t, filepath.Join(root, "Done.md"), "---\ntags: [todo, done]\n---\nhello\n")
+	mustWriteFile(t, filepath.Join(root, "Plain.md"), "hello\n")
+	if err := os.MkdirAll(filepath.Join(root, "Folder"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	idx := newSearchIndex(root)
+	if err := idx.ensureBuilt(); err != nil {
+		t.Fatalf("build index: %v", err)
+	}
+
+	got := relPathSet(root, idx.search("tag:todo -tag:done"))
+	expectContains(t, got, "Open.md")
+	expectNotContains(t, got, "Done.md")
+	expectNotContains(t, got, "Plain.md")
+
+	got = relPathSet(root, idx.search("-tag:done"))
+	expectContains(t, got, "Open.md")
+	expectContains(t, got, "Plain.md")
+	expectNotContains(t, got, "Done.md")
+	expectNotContains(t, got, "Folder")
+}
+
+func TestSearchIndexLateRemoveKeepsRecreatedNote(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "Note.md")
+	mustWriteFile(t, path, "first\n")
+	idx := newSearchIndex(root)
+	if err := idx.ensureBuilt(); err != nil {
+		t.Fatalf("build index: %v", err)
+	}
+
+	// The note is deleted and recreated; the delete event arrives last.
+	mustWriteFile(t, path, "second\n")
+	idx.upsertPath(path)
+	idx.removePath(path)
+
+	if got := relPathSet(root, idx.search("second")); len(got) != 1 {
+		t.Fatalf("expected the recreated note to stay indexed, got %v", got)
+	}
+	if err := idx.checkInvariants(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchIndexUpsertIndexesNewParentsAndSkipsManaged(t *testing.T) {
+	root := t.TempDir()
+	idx := newSearchIndex(root)
+	if err := idx.ensureBuilt(); err != nil {
+		t.Fatalf("build index: %v", err)
+	}
+
+	note := filepath.Join(root, "new", "nested", "Note.md")
+	managed := filepath.Join(root, "new", managedNotesDirName, "state.md")
+	mustWriteFile(t, note, "hello\n")
+	mustWriteFile(t, managed, "hello\n")
+	idx.upsertPath(note)
+	idx.upsertPath(managed)
+	idx.upsertPath(note)
+
+	fresh := newSearchIndex(root)
+	if err := fresh.ensureBuilt(); err != nil {
+		t.Fatalf("fresh build: %v", err)
+	}
+	if len(idx.sortedPaths) != 3 || len(fresh.sortedPaths) != 3 {
+		t.Fatalf("expected the note and both new folders, got %v (fresh %v)", idx.sortedPaths, fresh.sortedPaths)
+	}
+	for n, path := range fresh.sortedPaths {
+		if idx.sortedPaths[n] != path || !reflect.DeepEqual(idx.docs[path], fresh.docs[path]) {
+			t.Fatalf("expected %v to match a fresh build %v", idx.docs, fresh.docs)
+		}
+	}
+}
+
+func TestSearchIndexRepairsDivergedPathSlice(t *testing.T) {
+	root := t.TempDir()
+	a := filepath.Join(root, "a.md")
+	b := filepath.Join(root, "b.md")
+	mustWriteFile(t, a, "a\n")
+	mustWriteFile(t, b, "b\n")
+	idx := newSearchIndex(root)
+	if err := idx.ensureBuilt(); err != nil {
+		t.Fatalf("build index: %v", err)
+	}
+
+	idx.sortedPaths = []string{b}
+	idx.docs[filepath.Join(root, managedNotesDirName, "x.md")] = searchDoc{}
+	idx.upsertPath(b)
+
+	if err := idx.checkInvariants(); err != nil {
+		t.Fatalf("expected the path slice repaired, got %v", err)
+	}
+	if len(idx.sortedPaths) != 2 || idx.sortedPaths[0] != a {
+		t.Fatalf("expected both notes and no managed path, got %v", idx.sortedPaths)
+	}
+}
+
+func TestSearchIndexChecksSpellingOnlyOnCaseInsensitiveFS(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "Note.md")
+	mustWriteFile(t, note, "hello\n")
+	m := newTestCRUDModel(root)
+	if err := m.searchIndex.ensureBuilt(); err != nil {
+		t.Fatalf("build index: %v", err)
+	}
+
+	m.caseInsensitiveFS = true
+	m.applyMutationEffects(mutationEffects{upsertPaths: []string{note}})
+	if !m.searchIndex.caseInsensitive {
+		t.Fatal("expected the index to pick up the filesystem case flag")
+	}
+	if _, ok := m.searchIndex.docs[note]; !ok {
+		t.Fatal("expected the note indexed through the spelling check")
+	}
+	if _, ok := m.searchIndex.stat(filepath.Join(root, "NOTE.md")); ok {
+		t.Fatal("expected a spelling the folder does not list to be rejected")
+	}
+}
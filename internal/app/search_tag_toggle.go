@@ -0,0 +1,68 @@
+// search_tag_toggle.go implements the remembered tag filter of the search
+// popup.
+//
+// When a search whose query holds a "tag:" filter is used (a result is
+// opened or the query is saved as a view), its last tag filter is remembered
+// in workspace state. Ctrl+T in the search popup then adds "tag:<name>" to
+// the current query, or removes it when the query already filters on that
+// tag, so a common tag can be switched on and off without retyping it.
+package app
+
+import (
+	"strings"
+)
+
+// lastQueryTag returns the last "tag:" filter in query, lowercased, or "".
+func lastQueryTag(query string) string {
+	tags := parseSearchQuery(query).tagTerms
+	if len(tags) == 0 {
+		return ""
+	}
+	return tags[len(tags)-1]
+}
+
+// rememberSearchTag stores the last tag filter of query, if any, and
+// persists workspace state when it changes.
+func (m *Model) rememberSearchTag(query string) {
+	tag := lastQueryTag(query)
+	if tag == "" || tag == m.lastSearchTag {
+		return
+	}
+	m.lastSearchTag = tag
+	m.saveAppState()
+}
+
+// toggleQueryTag adds "tag:<tag>" to query, or removes every filter on tag
+// when query already has one. It reports whether the filter is now on.
+func toggleQueryTag(query, tag string) (string, bool) {
+	fields := strings.Fields(query)
+	kept := fields[:0:0]
+	for _, field := range fields {
+		if strings.EqualFold(field, "tag:"+tag) {
+			continue
+		}
+		kept = append(kept, field)
+	}
+	if len(kept) < len(fields) {
+		return strings.Join(kept, " "), false
+	}
+	return strings.Join(append(kept, "tag:"+tag), " "), true
+}
+
+// toggleSearchTag applies toggleQueryTag with the remembered tag to the
+// search popup query and reruns the search.
+func (m *Model) toggleSearchTag() {
+	if m.lastSearchTag == "" {
+		m.status = "No tag filter used yet"
+		return
+	}
+	query, on := toggleQueryTag(m.search.Value(), m.lastSearchTag)
+	m.search.SetValue(query)
+	m.search.CursorEnd()
+	m.updateSearchRows()
+	if on {
+		m.status = "Added tag:" + m.lastSearchTag
+	} else {
+		m.status = "Removed tag:" + m.lastSearchTag
+	}
+}
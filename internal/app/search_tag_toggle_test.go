@@ -0,0 +1,75 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func TestToggleQueryTag(t *testing.T) {
+	cases := []struct {
+		query, tag, want string
+		on               bool
+	}{
+		{"", "work", "tag:work", true},
+		{"meeting notes", "work", "meeting notes tag:work", true},
+		{"meeting tag:work notes", "work", "meeting notes", false},
+		{"TAG:Work", "work", "", false},
+		{"-tag:work", "work", "-tag:work tag:work", true},
+		{"tag:workshop", "work", "tag:workshop tag:work", true},
+	}
+	for _, tc := range cases {
+		got, on := toggleQueryTag(tc.query, tc.tag)
+		if got != tc.want || on != tc.on {
+			t.Errorf("toggleQueryTag(%q, %q) = %q, %v; want %q, %v", tc.query, tc.tag, got, on, tc.want, tc.on)
+		}
+	}
+}
+
+func TestSearchPopupTogglesRememberedTag(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "---\ntags: [work]\n---\nmeeting agenda\n")
+	mustWriteFile(t, filepath.Join(root, "b.md"), "meeting recap\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.search = textinput.New()
+	m.loadKeybindings(config.Config{})
+
+	m.openSearchPopup()
+	_, _ = m.handleSearchKey(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.status != "No tag filter used yet" {
+		t.Fatalf("expected no remembered tag, status %q", m.status)
+	}
+
+	m.search.SetValue("meeting tag:Work")
+	m.updateSearchRows()
+	_, _ = m.handleSearchKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.lastSearchTag != "work" {
+		t.Fatalf("expected the tag filter to be remembered, got %q", m.lastSearchTag)
+	}
+	state, err := loadAppState(root)
+	if err != nil {
+		t.Fatalf("load state: %v", err)
+	}
+	if state.LastSearchTag != "work" {
+		t.Fatalf("expected the tag to persist, got %q", state.LastSearchTag)
+	}
+
+	m.openSearchPopup()
+	m.search.SetValue("meeting")
+	m.updateSearchRows()
+	if len(m.searchResults) != 2 {
+		t.Fatalf("expected both notes before the toggle, got %d", len(m.searchResults))
+	}
+	_, _ = m.handleSearchKey(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.search.Value() != "meeting tag:work" || len(m.searchResults) != 1 || m.status != "Added tag:work" {
+		t.Fatalf("expected the tag applied, query %q results %d status %q", m.search.Value(), len(m.searchResults), m.status)
+	}
+	_, _ = m.handleSearchKey(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.search.Value() != "meeting" || len(m.searchResults) != 2 || m.status != "Removed tag:work" {
+		t.Fatalf("expected the tag removed, query %q results %d status %q", m.search.Value(), len(m.searchResults), m.status)
+	}
+}
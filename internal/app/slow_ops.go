@@ -0,0 +1,128 @@
+// slow_ops.go reports user-facing operations that take unusually long.
+//
+// Opening a note (the Glamour render), switching workspace, refreshing, and
+// searching are timed with two monotonic clock reads. Only when the elapsed
+// time crosses the configured threshold (slow_operation_threshold_ms, default
+// 1s) is a slowOpReport built: it is logged at warn level and shown in the
+// footer status with what was slow, the inputs that explain it (file size,
+// render width, tree rows, indexed notes), and a hint pointing at a
+// mitigation that exists in the app. The fast path does no other work.
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// slowOp names a timed user-facing operation.
+type slowOp string
+
+const (
+	slowOpOpenNote  slowOp = "open note"
+	slowOpWorkspace slowOp = "switch workspace"
+	slowOpRefresh   slowOp = "refresh"
+	slowOpSearch    slowOp = "search"
+)
+
+// Phases used to pick a more specific hint when the dominant step is known.
+const (
+	slowPhaseRender     = "render"
+	slowPhaseIndexBuild = "index build"
+	slowPhaseQuery      = "query"
+)
+
+// slowOpReport describes one operation that crossed the threshold. Only the
+// fields that apply to the operation are set.
+type slowOpReport struct {
+	op        slowOp
+	phase     string
+	elapsed   time.Duration
+	path      string
+	bytes     int64
+	width     int
+	items     int
+	itemsNoun string
+}
+
+// slowOpHints maps an operation (and optionally a phase) to an actionable
+// hint. Entries with a phase are preferred over the op-wide entry.
+var slowOpHints = []struct {
+	op    slowOp
+	phase string
+	hint  string
+}{
+	{slowOpOpenNote, slowPhaseRender, "large notes render slowly; reopening at this width uses the render cache, or split the note"},
+	{slowOpOpenNote, "", "reopening at this width uses the render cache"},
+	{slowOpWorkspace, "", "the tree and git status load on switch; the search index is built lazily on first search"},
+	{slowOpRefresh, "", "refresh rebuilds the tree and git status and drops cached renders; collapse large folders or let the file watcher pick up changes"},
+	{slowOpSearch, slowPhaseIndexBuild, "the first search after startup or refresh builds the index; later queries reuse it, and files over 1 MB are indexed by name only"},
+	{slowOpSearch, slowPhaseQuery, "each query scans every indexed note; splitting very large workspaces keeps queries fast"},
+}
+
+// slowOpHint returns the hint for op/phase, falling back to the op-wide
+// entry, or "" when the table has nothing for the operation.
+func slowOpHint(op slowOp, phase string) string {
+	fallback := ""
+	for _, entry := range slowOpHints {
+		if entry.op != op {
+			continue
+		}
+		if entry.phase == phase && phase != "" {
+			return entry.hint
+		}
+		if entry.phase == "" {
+			fallback = entry.hint
+		}
+	}
+	return fallback
+}
+
+// isSlowOp reports whether elapsed crosses the slow-operation threshold. A
+// zero threshold (e.g. a model built without config) disables reporting.
+func (m *Model) isSlowOp(elapsed time.Duration) bool {
+	return m.slowOpThreshold > 0 && elapsed >= m.slowOpThreshold
+}
+
+// reportSlowOp logs a slow operation and shows its explanation in the footer.
+func (m *Model) reportSlowOp(report slowOpReport) {
+	hint := slowOpHint(report.op, report.phase)
+	appLog.Warn("slow operation",
+		"op", string(report.op),
+		"phase", report.phase,
+		"elapsed", report.elapsed,
+		"path", report.path,
+		"bytes", report.bytes,
+		"width", report.width,
+		"items", report.items,
+		"hint", hint,
+	)
+	m.status = formatSlowOp(report, hint)
+}
+
+// formatSlowOp builds the user-facing message, e.g. "open note: render took
+// 2.3s for 1.8 MB file at width 160; <hint>".
+func formatSlowOp(report slowOpReport, hint string) string {
+	var b strings.Builder
+	b.WriteString(string(report.op))
+	b.WriteString(": ")
+	if report.phase != "" {
+		b.WriteString(report.phase)
+		b.WriteString(" ")
+	}
+	fmt.Fprintf(&b, "took %.1fs", report.elapsed.Seconds())
+	if report.bytes > 0 {
+		fmt.Fprintf(&b, " for %s file", formatByteSize(report.bytes))
+	}
+	if report.width > 0 {
+		fmt.Fprintf(&b, " at width %d", report.width)
+	}
+	if report.items > 0 && report.itemsNoun != "" {
+		fmt.Fprintf(&b, " (%d %s)", report.items, report.itemsNoun)
+	}
+	if hint != "" {
+		b.WriteString("; ")
+		b.WriteString(hint)
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+func TestSlowOpHintTable(t *testing.T) {
+	cases := []struct {
+		op    slowOp
+		phase string
+		want  string
+	}{
+		{slowOpOpenNote, slowPhaseRender, "large notes render slowly"},
+		{slowOpOpenNote, "", "render cache"},
+		{slowOpOpenNote, "unknown phase", "render cache"},
+		{slowOpWorkspace, "", "search index is built lazily"},
+		{slowOpRefresh, "", "collapse large folders"},
+		{slowOpSearch, slowPhaseIndexBuild, "builds the index"},
+		{slowOpSearch, slowPhaseQuery, "each query scans"},
+	}
+	for _, tc := range cases {
+		if got := slowOpHint(tc.op, tc.phase); !strings.Contains(got, tc.want) {
+			t.Fatalf("%s/%q: expected hint containing %q, got %q", tc.op, tc.phase, tc.want, got)
+		}
+	}
+	if got := slowOpHint(slowOp("unknown"), ""); got != "" {
+		t.Fatalf("expected no hint for unknown op, got %q", got)
+	}
+	for _, op := range []slowOp{slowOpOpenNote, slowOpWorkspace, slowOpRefresh, slowOpSearch} {
+		if slowOpHint(op, "") == "" && slowOpHint(op, slowPhaseQuery) == "" && slowOpHint(op, slowPhaseIndexBuild) == "" {
+			t.Fatalf("expected at least one hint for %s", op)
+		}
+	}
+}
+
+func TestFormatSlowOpIncludesKnownInputs(t *testing.T) {
+	got := formatSlowOp(slowOpReport{
+		op:      slowOpOpenNote,
+		phase:   slowPhaseRender,
+		elapsed: 2300 * time.Millisecond,
+		bytes:   1887437,
+		width:   160,
+	}, "hint")
+	if want := "open note: render took 2.3s for 1.8 MB file at width 160; hint"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	got = formatSlowOp(slowOpReport{op: slowOpRefresh, elapsed: 1500 * time.Millisecond, items: 42, itemsNoun: "tree rows"}, "")
+	if want := "refresh: took 1.5s (42 tree rows)"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSlowRenderResultReportsOnlyPastThreshold(t *testing.T) {
+	newModel := func() *Model {
+		return &Model{
+			currentFile:     "/notes/big.md",
+			renderSeq:       1,
+			renderCache:     map[string]renderCacheEntry{},
+			viewport:        viewport.New(80, 10),
+			slowOpThreshold: time.Second,
+			status:          "Opened",
+		}
+	}
+	msg := renderResultMsg{
+		path:    "/notes/big.md",
+		width:   roundWidthToNearestBucket(80),
+		seq:     1,
+		content: "rendered",
+		raw:     strings.Repeat("x", 2048),
+	}
+
+	m := newModel()
+	msg.elapsed = 200 * time.Millisecond
+	m.handleRenderResult(msg)
+	if m.status != "Opened" {
+		t.Fatalf("fast render should not touch status, got %q", m.status)
+	}
+
+	m = newModel()
+	msg.elapsed = 2 * time.Second
+	m.handleRenderResult(msg)
+	if !strings.HasPrefix(m.status, "open note: render took 2.0s for 2.0 KB file at width 80;") {
+		t.Fatalf("expected slow render report, got %q", m.status)
+	}
+
+	m = newModel()
+	m.slowOpThreshold = 0
+	m.handleRenderResult(msg)
+	if m.status != "Opened" {
+		t.Fatalf("zero threshold should disable reporting, got %q", m.status)
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	cases := map[int64]string{
+		0:                      "0 B",
+		1023:                   "1023 B",
+		1024:                   "1.0 KB",
+		1887437:                "1.8 MB",
+		3 * 1024 * 1024 * 1024: "3.0 GB",
+	}
+	for in, want := range cases {
+		if got := formatByteSize(in); got != want {
+			t.Fatalf("formatByteSize(%d): expected %q, got %q", in, want, got)
+		}
+	}
+}
@@ -1,30 +1,133 @@
-// sort.go implements sort-mode cycling and persistence for the tree view.
+// sort.go implements sort-mode cycling, direction toggling, per-folder sort
+// overrides, and persistence for the tree view.
 //
 // The user presses `s` in browse mode to cycle through sort modes
-// (name → modified → size → created → name). The chosen mode is persisted
-// in config.json under "tree_sort_by_workspace" keyed by notes_dir, with
-// "tree_sort" kept as compatibility fallback. After changing the sort mode
-// the tree is rebuilt immediately to reflect the new ordering.
+// (name → modified → size → created → name) and `S` to reverse the current
+// direction. Cycling resets the direction to the new mode's natural one
+// (ascending for name, descending for the rest). The chosen mode and
+// direction are persisted in config.json under "tree_sort_by_workspace" and
+// "tree_sort_direction_by_workspace" keyed by notes_dir, with "tree_sort" /
+// "tree_sort_direction" kept as compatibility fallback.
+//
+// Alt+S toggles a sort override on the selected folder (or the folder
+// containing the selected note). While the selection is inside an overridden
+// folder, `s` and `S` edit that folder's override instead of the global
+// order. Overrides live in per-workspace app state (state.json), not config.
+// After any change the tree is rebuilt immediately to reflect the new ordering.
 package app
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/treykane/cli-notes/internal/config"
 )
 
 // cycleSortMode advances to the next sort mode, rebuilds the tree to apply
-// the new ordering, and persists the per-workspace preference to config.json.
-// If the config save fails the sort mode is still applied in-memory for the
-// current session.
+// the new ordering, and persists the preference. If the selection is inside a
+// folder with a sort override, the override is cycled instead. If the config
+// save fails the sort mode is still applied in-memory for the current session.
 func (m *Model) cycleSortMode() {
+	if dir, override, ok := m.scopedFolderSort(); ok {
+		override = folderSort{mode: nextSortMode(override.mode)}
+		m.setFolderSort(dir, override)
+		return
+	}
 	m.sortMode = nextSortMode(m.sortMode)
+	m.sortDirection = ""
+	m.applyGlobalSortChange()
+}
+
+// reverseSortDirection flips the direction of the current sort (the folder
+// override when the selection is inside one, otherwise the global order).
+func (m *Model) reverseSortDirection() {
+	if dir, override, ok := m.scopedFolderSort(); ok {
+		override.direction = override.direction.reversed()
+		m.setFolderSort(dir, override)
+		return
+	}
+	m.sortDirection = m.globalSort().direction.reversed()
+	m.applyGlobalSortChange()
+}
+
+// toggleFolderSortOverride adds a sort override to the selected folder,
+// seeded from the global order, or removes it if one already exists.
+func (m *Model) toggleFolderSortOverride() {
+	dir := m.sortScopeDir()
+	if dir == m.notesDir {
+		m.status = "Select a folder to override its sort"
+		return
+	}
+	if _, ok := m.folderSorts[dir]; ok {
+		delete(m.folderSorts, dir)
+		m.rebuildTreeKeep(m.selectedPath())
+		m.saveAppState()
+		m.status = fmt.Sprintf("Folder sort cleared: %s (tree sort: %s)", m.displayRelative(dir), m.globalSort().Label())
+		return
+	}
+	m.setFolderSort(dir, m.globalSort())
+	m.status += " (s/S to change, Alt+S to clear)"
+}
+
+// setFolderSort stores a folder override, rebuilds the tree, and saves state.
+func (m *Model) setFolderSort(dir string, override folderSort) {
+	if m.folderSorts == nil {
+		m.folderSorts = map[string]folderSort{}
+	}
+	override = override.resolved()
+	m.folderSorts[dir] = override
+	m.rebuildTreeKeep(m.selectedPath())
+	m.saveAppState()
+	m.status = fmt.Sprintf("Folder sort (%s): %s", m.displayRelative(dir), override.Label())
+}
+
+// applyGlobalSortChange rebuilds the tree after a global sort change and
+// persists it to config.
+func (m *Model) applyGlobalSortChange() {
 	m.refreshTree()
 	if err := m.persistWorkspaceSortMode(); err != nil {
 		m.setStatusError("Sort mode changed but config save failed", err)
 		return
 	}
-	m.status = fmt.Sprintf("Tree sort: %s", m.sortMode.Label())
+	m.status = fmt.Sprintf("Tree sort: %s", m.globalSort().Label())
+}
+
+// globalSort returns the resolved workspace-wide mode and direction.
+func (m *Model) globalSort() folderSort {
+	return folderSort{mode: m.sortMode, direction: m.sortDirection}.resolved()
+}
+
+// sortScopeDir returns the folder that sort commands target: the selected
+// folder itself, or the folder containing the selected note. It reads the
+// tree row instead of stat-ing, since the footer calls it on every render.
+func (m *Model) sortScopeDir() string {
+	item := m.selectedItem()
+	if item == nil {
+		return m.notesDir
+	}
+	if item.isDir {
+		return item.path
+	}
+	return filepath.Dir(item.path)
+}
+
+// scopedFolderSort returns the override for the sort scope folder, if any.
+func (m *Model) scopedFolderSort() (string, folderSort, bool) {
+	dir := m.sortScopeDir()
+	override, ok := m.folderSorts[dir]
+	if !ok {
+		return "", folderSort{}, false
+	}
+	return dir, override.resolved(), true
+}
+
+// sortFooterSummary returns the footer segment describing the sort that
+// applies in the current sort scope, e.g. "sort: modified ↓".
+func (m *Model) sortFooterSummary() string {
+	if _, override, ok := m.scopedFolderSort(); ok {
+		return "sort: " + override.Label() + " (folder)"
+	}
+	return "sort: " + m.globalSort().Label()
 }
 
 func loadWorkspaceSortMode(cfg config.Config, notesDir string) sortMode {
@@ -36,19 +139,44 @@ func loadWorkspaceSortMode(cfg config.Config, notesDir string) sortMode {
 	return parseSortMode(cfg.TreeSort)
 }
 
-// persistWorkspaceSortMode writes the current sort mode to the workspace map
-// and updates the legacy tree_sort field as fallback compatibility.
+// loadWorkspaceSortDirection mirrors loadWorkspaceSortMode for direction.
+// A workspace with a stored mode but no stored direction uses the mode's
+// natural direction rather than the legacy fallback.
+func loadWorkspaceSortDirection(cfg config.Config, notesDir string) sortDirection {
+	if notesDir != "" {
+		if direction, ok := cfg.TreeSortDirectionByWorkspace[notesDir]; ok {
+			return parseSortDirection(direction)
+		}
+		if _, ok := cfg.TreeSortByWorkspace[notesDir]; ok {
+			return ""
+		}
+	}
+	return parseSortDirection(cfg.TreeSortDirection)
+}
+
+// persistWorkspaceSortMode writes the current sort mode and direction to the
+// workspace maps and updates the legacy tree_sort fields as fallback
+// compatibility.
 func (m *Model) persistWorkspaceSortMode() error {
 	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
 	cfg.TreeSort = m.sortMode.String()
+	cfg.TreeSortDirection = string(m.sortDirection)
 	if cfg.TreeSortByWorkspace == nil {
 		cfg.TreeSortByWorkspace = map[string]string{}
 	}
+	if cfg.TreeSortDirectionByWorkspace == nil {
+		cfg.TreeSortDirectionByWorkspace = map[string]string{}
+	}
 	if m.notesDir != "" {
 		cfg.TreeSortByWorkspace[m.notesDir] = m.sortMode.String()
+		if m.sortDirection == "" {
+			delete(cfg.TreeSortDirectionByWorkspace, m.notesDir)
+		} else {
+			cfg.TreeSortDirectionByWorkspace[m.notesDir] = string(m.sortDirection)
+		}
 	}
 	if cfg.TemplatesDir == "" {
 		cfg.TemplatesDir = m.templatesDir
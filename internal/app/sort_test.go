@@ -1,8 +1,11 @@
 package app
 
 import (
+	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/treykane/cli-notes/internal/config"
 )
@@ -65,3 +68,137 @@ func TestSelectWorkspaceEntryRestoresWorkspaceSortMode(t *testing.T) {
 		t.Fatalf("expected sort mode size for workspace B, got %s", got.sortMode)
 	}
 }
+
+func TestLoadWorkspaceSortDirectionFallsBackPerWorkspace(t *testing.T) {
+	cfg := config.Config{
+		TreeSortDirection: "desc",
+		TreeSortByWorkspace: map[string]string{
+			"/notes/a": "modified",
+			"/notes/b": "name",
+		},
+		TreeSortDirectionByWorkspace: map[string]string{
+			"/notes/a": "asc",
+		},
+	}
+	if got := loadWorkspaceSortDirection(cfg, "/notes/a"); got != sortAscending {
+		t.Fatalf("expected asc for workspace a, got %q", got)
+	}
+	if got := loadWorkspaceSortDirection(cfg, "/notes/b"); got != "" {
+		t.Fatalf("expected natural direction for workspace b, got %q", got)
+	}
+	if got := loadWorkspaceSortDirection(cfg, "/notes/c"); got != sortDescending {
+		t.Fatalf("expected legacy fallback desc, got %q", got)
+	}
+}
+
+func TestBuildTreeHonorsDirectionFolderOverrideAndPins(t *testing.T) {
+	// Pinned entries stay first and folders before files in every direction;
+	// the journal override only reorders journal's direct children.
+	root := t.TempDir()
+	journal := filepath.Join(root, "journal")
+	for _, name := range []string{"alpha.md", "bravo.md", "charlie.md"} {
+		mustWriteFile(t, filepath.Join(root, name), name)
+	}
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, name := range []string{"2026-01-01.md", "2026-01-02.md", "2026-01-03.md"} {
+		path := filepath.Join(journal, name)
+		mustWriteFile(t, path, name)
+		stamp := base.Add(time.Duration(i) * time.Hour)
+		if err := os.Chtimes(path, stamp, stamp); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	expanded := map[string]bool{root: true, journal: true}
+	pinned := map[string]bool{filepath.Join(root, "bravo.md"): true}
+
+	order := treeOrder{
+		mode:      sortModeName,
+		direction: sortDescending,
+		tiebreak:  sortTiebreakName,
+		overrides: map[string]folderSort{
+			journal: {mode: sortModeModified},
+		},
+	}
+	got := relPaths(root, buildTreeWithMetadataCache(root, expanded, order, pinned, nil))
+	want := []string{
+		"bravo.md",
+		"journal",
+		"journal/2026-01-03.md",
+		"journal/2026-01-02.md",
+		"journal/2026-01-01.md",
+		"charlie.md",
+		"alpha.md",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("unexpected order.\nwant: %v\ngot:  %v", want, got)
+	}
+
+	order.overrides[journal] = folderSort{mode: sortModeModified, direction: sortAscending}
+	order.direction = ""
+	got = relPaths(root, buildTreeWithMetadataCache(root, expanded, order, pinned, nil))
+	want = []string{
+		"bravo.md",
+		"journal",
+		"journal/2026-01-01.md",
+		"journal/2026-01-02.md",
+		"journal/2026-01-03.md",
+		"alpha.md",
+		"charlie.md",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("unexpected order after reversing.\nwant: %v\ngot:  %v", want, got)
+	}
+}
+
+func TestFolderSortOverrideTogglesAndPersistsInState(t *testing.T) {
+	root := t.TempDir()
+	journal := filepath.Join(root, "journal")
+	mustWriteFile(t, filepath.Join(journal, "entry.md"), "entry\n")
+	mustWriteFile(t, filepath.Join(root, "top.md"), "top\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.sortMode = sortModeName
+	m.expanded[journal] = true
+	m.rebuildTreeKeep(filepath.Join(journal, "entry.md"))
+
+	m.toggleFolderSortOverride()
+	if got := m.folderSorts[journal]; got != (folderSort{mode: sortModeName, direction: sortAscending}) {
+		t.Fatalf("expected override seeded from global sort, got %+v", got)
+	}
+	m.cycleSortMode()
+	m.cycleSortMode()
+	m.cycleSortMode()
+	m.reverseSortDirection()
+	if got := m.folderSorts[journal]; got != (folderSort{mode: sortModeCreated, direction: sortAscending}) {
+		t.Fatalf("expected created ascending override, got %+v", got)
+	}
+	if m.sortMode != sortModeName || m.sortDirection != "" {
+		t.Fatalf("expected global sort untouched, got %s %q", m.sortMode, m.sortDirection)
+	}
+	if got := m.sortFooterSummary(); got != "sort: created ↑ (folder)" {
+		t.Fatalf("unexpected footer summary %q", got)
+	}
+
+	state, err := loadAppState(root)
+	if err != nil {
+		t.Fatalf("load app state: %v", err)
+	}
+	if got := state.FolderSorts[journal]; got != (folderSort{mode: sortModeCreated, direction: sortAscending}) {
+		t.Fatalf("expected override persisted in state, got %+v", got)
+	}
+
+	m.remapStatePaths(journal, filepath.Join(root, "diary"))
+	if _, ok := m.folderSorts[filepath.Join(root, "diary")]; !ok {
+		t.Fatalf("expected override to follow rename, got %+v", m.folderSorts)
+	}
+	m.remapStatePaths(filepath.Join(root, "diary"), journal)
+
+	m.toggleFolderSortOverride()
+	if _, ok := m.folderSorts[journal]; ok {
+		t.Fatal("expected second toggle to clear the override")
+	}
+	if got := m.sortFooterSummary(); got != "sort: name ↑" {
+		t.Fatalf("unexpected footer summary %q", got)
+	}
+}
@@ -0,0 +1,53 @@
+// split_edit.go lets the secondary split pane edit its note.
+//
+// The editor and everything around it (drafts, conflict checks, undo
+// history, autocomplete) work on m.currentFile. To edit the note in the
+// secondary pane, startEditNote binds the editor to that pane instead: the
+// two pane notes trade places in the model for the length of the edit
+// (splitEditSecondary) while renderRightSplit keeps each note in its own
+// pane on screen. Leaving edit mode (Ctrl+S, Esc, or saving a conflict copy)
+// trades them back.
+//
+// Positions stay with their pane: the edited note's cursor is saved next to
+// its secondary preview offset, and the primary note keeps the offset it had
+// in the viewport.
+package app
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// bindEditorToFocusedPane makes the focused split pane's note the edited
+// note. It is a no-op outside split mode or with the primary pane focused.
+func (m *Model) bindEditorToFocusedPane() {
+	if !m.splitMode || !m.splitFocusSecondary || m.splitEditSecondary {
+		return
+	}
+	m.rememberCurrentNotePosition()
+	m.currentFile, m.secondaryFile = m.secondaryFile, m.currentFile
+	m.splitEditSecondary = true
+}
+
+// endSplitSecondaryEdit returns the edited note to the secondary pane once
+// editing ends, and re-renders the primary note into the viewport.
+func (m *Model) endSplitSecondaryEdit() tea.Cmd {
+	if !m.splitEditSecondary {
+		return nil
+	}
+	m.currentFile, m.secondaryFile = m.secondaryFile, m.currentFile
+	m.splitEditSecondary = false
+	return m.requestRender(m.currentFile)
+}
+
+// splitPaneFiles returns the notes shown in the primary and secondary split
+// panes.
+func (m *Model) splitPaneFiles() (primary, secondary string) {
+	if m.splitEditSecondary {
+		return m.secondaryFile, m.currentFile
+	}
+	return m.currentFile, m.secondaryFile
+}
+
+// paneHoldsEditor reports whether the given split pane shows the editor
+// while in edit mode.
+func (m *Model) paneHoldsEditor(secondary bool) bool {
+	return m.splitEditSecondary == secondary
+}
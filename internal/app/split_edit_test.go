@@ -0,0 +1,79 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newSplitEditModel(t *testing.T) (m *Model, primary, secondary string) {
+	t.Helper()
+	root := t.TempDir()
+	primary = filepath.Join(root, "primary.md")
+	secondary = filepath.Join(root, "secondary.md")
+	mustWriteFile(t, primary, "# Primary\n")
+	mustWriteFile(t, secondary, "# Secondary\n")
+	m = newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.splitMode = true
+	m.splitFocusSecondary = true
+	m.currentFile = primary
+	m.secondaryFile = secondary
+	m.viewport.YOffset = 4
+	m.notePositions[secondary] = notePosition{SecondaryPreviewOffset: 6}
+	return m, primary, secondary
+}
+
+func TestEditInSecondaryPaneSavesThatNote(t *testing.T) {
+	m, primary, secondary := newSplitEditModel(t)
+
+	_, _ = m.startEditNote()
+	if m.mode != modeEditNote || m.editor.Value() != "# Secondary\n" {
+		t.Fatalf("expected the secondary note in the editor, mode %v value %q", m.mode, m.editor.Value())
+	}
+	if left, right := m.splitPaneFiles(); left != primary || right != secondary {
+		t.Fatalf("expected panes to keep their notes, got %q | %q", left, right)
+	}
+	if !m.paneHoldsEditor(true) || m.paneHoldsEditor(false) {
+		t.Fatal("expected the editor in the secondary pane")
+	}
+	if got := m.notePositions[primary].PrimaryPreviewOffset; got != 4 {
+		t.Fatalf("expected the primary pane offset saved, got %d", got)
+	}
+
+	m.editor.CursorEnd()
+	m.editor.InsertString("more\n")
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if data, _ := os.ReadFile(secondary); string(data) != "# Secondary\nmore\n" {
+		t.Fatalf("secondary note = %q", data)
+	}
+	if data, _ := os.ReadFile(primary); string(data) != "# Primary\n" {
+		t.Fatalf("expected the primary note untouched, got %q", data)
+	}
+	if m.mode != modeBrowse || m.currentFile != primary || m.secondaryFile != secondary || m.splitEditSecondary {
+		t.Fatalf("expected panes restored, current %q secondary %q", m.currentFile, m.secondaryFile)
+	}
+	pos := m.notePositions[secondary]
+	if pos.SecondaryPreviewOffset != 6 || pos.PrimaryPreviewOffset != 0 || pos.EditorCursor == 0 {
+		t.Fatalf("expected the cursor kept with the secondary pane offset, got %+v", pos)
+	}
+}
+
+func TestCancelSecondaryPaneEditRestoresPanes(t *testing.T) {
+	m, primary, secondary := newSplitEditModel(t)
+
+	_, _ = m.startEditNote()
+	_, _ = m.handleEditNoteKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeBrowse || m.currentFile != primary || m.secondaryFile != secondary || m.splitEditSecondary {
+		t.Fatalf("expected panes restored, current %q secondary %q", m.currentFile, m.secondaryFile)
+	}
+
+	m.splitFocusSecondary = false
+	_, _ = m.startEditNote()
+	if m.splitEditSecondary || !strings.HasPrefix(m.editor.Value(), "# Primary") {
+		t.Fatalf("expected the primary pane to edit its own note, got %q", m.editor.Value())
+	}
+}
@@ -1,5 +1,6 @@
 // state.go implements per-workspace persistent state: recent files, pinned
-// paths, and per-note scroll/cursor position memory.
+// paths, per-folder sort overrides, per-note scroll/cursor position memory,
+// completed focus sessions, and saved search views.
 //
 // State is stored as JSON at <notes_dir>/.cli-notes/state.json so each
 // workspace maintains independent state that travels with the notes directory
@@ -15,9 +16,11 @@
 // State is saved:
 //   - After every file navigation (recent file tracking)
 //   - Before switching files or workspaces (position memory)
-//   - After pin/unpin toggles
+//   - After pin/unpin toggles and folder sort override changes
 //   - After rename/move/delete operations (state path remapping)
 //   - On external filesystem change detection (watcher refresh)
+//   - When a focus session completes
+//   - When a saved search view is added, replaced, or deleted
 package app
 
 import (
@@ -26,16 +29,31 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 )
 
 // notePosition records the viewport scroll offset and editor cursor position
 // for a single note so the app can restore the user's reading/editing position
 // when they return to a previously viewed file.
+//
+// The anchor fields let a position survive the note being rewritten outside
+// the app (see position_anchor.go); they are empty in older state files.
 type notePosition struct {
 	PreviewOffset          int `json:"preview_offset,omitempty"` // legacy fallback
 	PrimaryPreviewOffset   int `json:"primary_preview_offset,omitempty"`
 	SecondaryPreviewOffset int `json:"secondary_preview_offset,omitempty"`
 	EditorCursor           int `json:"editor_cursor,omitempty"`
+	// CursorBefore and CursorAfter are the text around EditorCursor.
+	CursorBefore string `json:"cursor_before,omitempty"`
+	CursorAfter  string `json:"cursor_after,omitempty"`
+	// PreviewHeading is the heading at or above PrimaryPreviewOffset, and
+	// ContentHash identifies the content that offset was measured on.
+	PreviewHeading string `json:"preview_heading,omitempty"`
+	ContentHash    string `json:"content_hash,omitempty"`
+	// Updated is when the position last changed, in Unix seconds; a state
+	// import keeps whichever side is newer (state_transfer.go).
+	Updated int64 `json:"updated,omitempty"`
 }
 
 // persistedState is the on-disk JSON representation of per-workspace app state.
@@ -45,10 +63,37 @@ type notePosition struct {
 // between absolute and relative paths happens at load/save boundaries via
 // statePathToAbs and absToStatePath.
 type persistedState struct {
-	RecentFiles []string                `json:"recent_files,omitempty"`
-	PinnedPaths []string                `json:"pinned_paths,omitempty"`
-	Positions   map[string]notePosition `json:"positions,omitempty"`
-	OpenCounts  map[string]int          `json:"open_counts,omitempty"`
+	RecentFiles []string                       `json:"recent_files,omitempty"`
+	PinnedPaths []string                       `json:"pinned_paths,omitempty"`
+	Positions   map[string]notePosition        `json:"positions,omitempty"`
+	OpenCounts  map[string]int                 `json:"open_counts,omitempty"`
+	FolderSorts map[string]persistedFolderSort `json:"folder_sorts,omitempty"`
+	// ArchivedFrom maps an archived path to where it was archived from.
+	ArchivedFrom map[string]string `json:"archived_from,omitempty"`
+	// ShowMetadataStrip remembers whether the preview metadata strip is on.
+	ShowMetadataStrip bool `json:"show_metadata_strip,omitempty"`
+	// FocusSessions lists completed focus sessions, oldest first.
+	FocusSessions []persistedFocusSession `json:"focus_sessions,omitempty"`
+	// SavedViews lists named search queries in the order they were saved.
+	SavedViews []savedView `json:"saved_views,omitempty"`
+	// LastSearchTag is the tag filter Ctrl+T toggles in the search popup.
+	LastSearchTag string `json:"last_search_tag,omitempty"`
+}
+
+// persistedFocusSession is the on-disk form of a completed focus session.
+// Note is empty when the session was not bound to a note.
+type persistedFocusSession struct {
+	Note            string    `json:"note,omitempty"`
+	Start           time.Time `json:"start"`
+	DurationMinutes int       `json:"duration_minutes"`
+	WordsAdded      int       `json:"words_added,omitempty"`
+}
+
+// persistedFolderSort is the on-disk form of a per-folder sort override.
+// An empty direction means the mode's natural direction.
+type persistedFolderSort struct {
+	Mode      string `json:"mode"`
+	Direction string `json:"direction,omitempty"`
 }
 
 // appPersistentState is the in-memory representation of workspace state.
@@ -56,10 +101,20 @@ type persistedState struct {
 // Unlike persistedState, all paths here are absolute. PinnedPaths uses a
 // map[string]bool for O(1) lookup during tree sorting and rendering.
 type appPersistentState struct {
-	RecentFiles []string
-	PinnedPaths map[string]bool
-	Positions   map[string]notePosition
-	OpenCounts  map[string]int
+	RecentFiles  []string
+	PinnedPaths  map[string]bool
+	Positions    map[string]notePosition
+	OpenCounts   map[string]int
+	FolderSorts  map[string]folderSort
+	ArchivedFrom map[string]string
+	// ShowMetadataStrip mirrors persistedState.ShowMetadataStrip.
+	ShowMetadataStrip bool
+	// FocusSessions mirrors persistedState.FocusSessions.
+	FocusSessions []focusRecord
+	// SavedViews mirrors persistedState.SavedViews.
+	SavedViews []savedView
+	// LastSearchTag mirrors persistedState.LastSearchTag.
+	LastSearchTag string
 }
 
 // appStatePath returns the filesystem path to the per-workspace state file.
@@ -77,9 +132,11 @@ func appStatePath(notesDir string) string {
 // outside the workspace root) are silently discarded to keep state clean.
 func loadAppState(notesDir string) (appPersistentState, error) {
 	state := appPersistentState{
-		PinnedPaths: map[string]bool{},
-		Positions:   map[string]notePosition{},
-		OpenCounts:  map[string]int{},
+		PinnedPaths:  map[string]bool{},
+		Positions:    map[string]notePosition{},
+		OpenCounts:   map[string]int{},
+		FolderSorts:  map[string]folderSort{},
+		ArchivedFrom: map[string]string{},
 	}
 
 	path := appStatePath(notesDir)
@@ -142,6 +199,48 @@ func loadAppState(notesDir string) (appPersistentState, error) {
 		}
 		state.OpenCounts[abs] = count
 	}
+	for rel, override := range persisted.FolderSorts {
+		abs, ok := statePathToAbs(notesDir, rel)
+		if !ok {
+			continue
+		}
+		state.FolderSorts[abs] = folderSort{
+			mode:      parseSortMode(override.Mode),
+			direction: parseSortDirection(override.Direction),
+		}
+	}
+	for rel, originRel := range persisted.ArchivedFrom {
+		abs, ok := statePathToAbs(notesDir, rel)
+		if !ok {
+			continue
+		}
+		origin, ok := statePathToAbs(notesDir, originRel)
+		if !ok {
+			continue
+		}
+		state.ArchivedFrom[abs] = origin
+	}
+
+	state.ShowMetadataStrip = persisted.ShowMetadataStrip
+
+	for _, session := range persisted.FocusSessions {
+		if session.Start.IsZero() || session.DurationMinutes <= 0 {
+			continue
+		}
+		record := focusRecord{
+			StartedAt:  session.Start,
+			Duration:   time.Duration(session.DurationMinutes) * time.Minute,
+			WordsAdded: session.WordsAdded,
+		}
+		if session.Note != "" {
+			// A note outside the root is dropped but the focus time is kept.
+			record.NotePath, _ = statePathToAbs(notesDir, session.Note)
+		}
+		state.FocusSessions = appendFocusRecord(state.FocusSessions, record)
+	}
+
+	state.SavedViews = normalizeSavedViews(persisted.SavedViews)
+	state.LastSearchTag = strings.ToLower(strings.TrimSpace(persisted.LastSearchTag))
 
 	state.RecentFiles = dedupePaths(state.RecentFiles)
 	trimRecentFiles(&state.RecentFiles)
@@ -161,10 +260,16 @@ func (m *Model) saveAppState() {
 		return
 	}
 	state := persistedState{
-		RecentFiles: make([]string, 0, len(m.recentFiles)),
-		PinnedPaths: make([]string, 0, len(m.pinnedPaths)),
-		Positions:   make(map[string]notePosition, len(m.notePositions)),
-		OpenCounts:  make(map[string]int, len(m.noteOpenCounts)),
+		RecentFiles:  make([]string, 0, len(m.recentFiles)),
+		PinnedPaths:  make([]string, 0, len(m.pinnedPaths)),
+		Positions:    make(map[string]notePosition, len(m.notePositions)),
+		OpenCounts:   make(map[string]int, len(m.noteOpenCounts)),
+		FolderSorts:  make(map[string]persistedFolderSort, len(m.folderSorts)),
+		ArchivedFrom: make(map[string]string, len(m.archiveOrigins)),
+
+		ShowMetadataStrip: m.showMetadataStrip,
+		SavedViews:        m.savedViews,
+		LastSearchTag:     m.lastSearchTag,
 	}
 
 	for _, path := range m.recentFiles {
@@ -191,11 +296,17 @@ func (m *Model) saveAppState() {
 		if !ok {
 			continue
 		}
+		pos = scrubEncryptedPosition(path, pos)
 		state.Positions[rel] = notePosition{
 			PreviewOffset:          max(0, pos.PrimaryPreviewOffset),
 			PrimaryPreviewOffset:   max(0, pos.PrimaryPreviewOffset),
 			SecondaryPreviewOffset: max(0, pos.SecondaryPreviewOffset),
 			EditorCursor:           max(0, pos.EditorCursor),
+			CursorBefore:           pos.CursorBefore,
+			CursorAfter:            pos.CursorAfter,
+			PreviewHeading:         pos.PreviewHeading,
+			ContentHash:            pos.ContentHash,
+			Updated:                pos.Updated,
 		}
 	}
 	for path, count := range m.noteOpenCounts {
@@ -208,6 +319,35 @@ func (m *Model) saveAppState() {
 		}
 		state.OpenCounts[rel] = count
 	}
+	for path, override := range m.folderSorts {
+		rel, ok := absToStatePath(m.notesDir, path)
+		if !ok {
+			continue
+		}
+		state.FolderSorts[rel] = persistedFolderSort{
+			Mode:      override.mode.String(),
+			Direction: string(override.direction),
+		}
+	}
+	for path, origin := range m.archiveOrigins {
+		rel, ok := absToStatePath(m.notesDir, path)
+		if !ok {
+			continue
+		}
+		if originRel, ok := absToStatePath(m.notesDir, origin); ok {
+			state.ArchivedFrom[rel] = originRel
+		}
+	}
+
+	for _, record := range m.focusHistory {
+		session := persistedFocusSession{
+			Start:           record.StartedAt,
+			DurationMinutes: int(record.Duration / time.Minute),
+			WordsAdded:      record.WordsAdded,
+		}
+		session.Note, _ = absToStatePath(m.notesDir, record.NotePath)
+		state.FocusSessions = append(state.FocusSessions, session)
+	}
 
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
@@ -264,11 +404,12 @@ func statePathToAbs(root, rel string) (string, bool) {
 // cursor for the active note so the position can be restored later. This is
 // called before switching files, saving state, or exiting edit mode.
 func (m *Model) rememberCurrentNotePosition() {
-	if m.splitMode && m.secondaryFile != "" {
-		m.rememberPanePosition(m.secondaryFile, true)
+	primary, secondary := m.splitPaneFiles()
+	if m.splitMode && secondary != "" {
+		m.rememberPanePosition(secondary, true)
 	}
-	if m.currentFile != "" {
-		m.rememberPanePosition(m.currentFile, false)
+	if primary != "" {
+		m.rememberPanePosition(primary, false)
 	}
 }
 
@@ -276,7 +417,7 @@ func (m *Model) rememberCurrentNotePosition() {
 // cursor position for the given note path. The position is stored in the
 // notePositions map and persisted to disk on the next saveAppState call.
 func (m *Model) rememberNotePosition(path string) {
-	m.rememberPanePosition(path, false)
+	m.rememberPanePosition(path, m.splitEditSecondary && path == m.currentFile)
 }
 
 func (m *Model) rememberPanePosition(path string, secondary bool) {
@@ -296,10 +437,15 @@ func (m *Model) rememberPanePosition(path string, secondary bool) {
 	} else {
 		pos.PrimaryPreviewOffset = offset
 		pos.PreviewOffset = offset
+		m.anchorPreviewPosition(path, &pos)
 	}
 	if m.mode == modeEditNote && path == m.currentFile {
 		pos.EditorCursor = max(0, m.currentEditorCursorOffset())
+		if !isEncryptedNotePath(path) {
+			pos.CursorBefore, pos.CursorAfter = cursorContext(m.editor.Value(), pos.EditorCursor)
+		}
 	}
+	pos.Updated = time.Now().Unix()
 	m.notePositions[path] = pos
 }
 
@@ -316,18 +462,40 @@ func (m *Model) setPaneOffset(path string, secondary bool, offset int) {
 	} else {
 		pos.PrimaryPreviewOffset = max(0, offset)
 		pos.PreviewOffset = max(0, offset)
+		m.anchorPreviewPosition(path, &pos)
 	}
+	pos.Updated = time.Now().Unix()
+	m.notePositions[path] = pos
+}
+
+// setNoteEditorCursor records where the editor cursor goes the next time
+// path is edited. The offset is into content just written, so it carries no
+// context to re-locate it by.
+func (m *Model) setNoteEditorCursor(path string, offset int) {
+	if m.notePositions == nil {
+		m.notePositions = map[string]notePosition{}
+	}
+	pos := m.notePositions[path]
+	pos.EditorCursor = max(0, offset)
+	pos.CursorBefore, pos.CursorAfter = "", ""
+	pos.Updated = time.Now().Unix()
 	m.notePositions[path] = pos
 }
 
 // restorePreviewOffset restores the viewport scroll position for a note that
 // was previously viewed. If no saved position exists, the viewport is reset
-// to the top of the document.
+// to the top of the document. A position measured on different content is
+// re-anchored to its heading first (recoverPreviewOffset).
 func (m *Model) restorePreviewOffset(path string) {
 	if path == "" {
 		return
 	}
-	m.viewport.YOffset = m.restorePaneOffset(path, false)
+	pos, ok := m.notePositions[path]
+	if !ok {
+		m.viewport.YOffset = 0
+		return
+	}
+	m.viewport.YOffset = m.recoverPreviewOffset(path, pos)
 }
 
 func (m *Model) restorePaneOffset(path string, secondary bool) int {
@@ -350,7 +518,8 @@ func (m *Model) restorePaneOffset(path string, secondary bool) int {
 // restoreEditorCursor restores the editor cursor to the previously saved
 // position when re-entering edit mode for a note. If no position was saved
 // or the saved position is zero, the cursor is placed at the end of the
-// document as a sensible default.
+// document as a sensible default. When the text around the saved cursor
+// changed, it is re-located first (recoverEditorCursor).
 func (m *Model) restoreEditorCursor(path string) {
 	if path == "" {
 		return
@@ -360,7 +529,8 @@ func (m *Model) restoreEditorCursor(path string) {
 		m.editor.CursorEnd()
 		return
 	}
-	m.setEditorValueAndCursorOffset(m.editor.Value(), pos.EditorCursor)
+	value := m.editor.Value()
+	m.setEditorValueAndCursorOffset(value, m.recoverEditorCursor(path, value, pos))
 }
 
 // trackRecentFile adds a note path to the front of the recent files list.
@@ -426,9 +596,10 @@ func trimRecentFiles(paths *[]string) {
 }
 
 // clearStateForPath removes all persisted state associated with the given
-// path: pinned status, saved positions, and recent file entries. If the path
-// is a directory, all descendant paths are also cleared. This is called after
-// a file or folder is deleted to avoid stale references in state.
+// path: pinned status, saved positions, folder sort overrides, archive
+// origins, and recent file entries. If the path is a directory, all descendant paths are also
+// cleared. This is called after a file or folder is deleted to avoid stale
+// references in state.
 func (m *Model) clearStateForPath(path string) {
 	if path == "" {
 		return
@@ -453,15 +624,37 @@ func (m *Model) clearStateForPath(path string) {
 			delete(m.noteOpenCounts, p)
 		}
 	}
+	for p := range m.folderSorts {
+		if p == path || hasPathPrefix(p, prefix) {
+			delete(m.folderSorts, p)
+		}
+	}
+	for p := range m.archiveOrigins {
+		if p == path || hasPathPrefix(p, prefix) {
+			delete(m.archiveOrigins, p)
+		}
+	}
 	m.recentFiles = removePathsWithPrefix(m.recentFiles, prefix)
 	m.rebuildRecentEntries()
 	m.saveAppState()
 }
 
+// scrubEncryptedPosition clears the text anchors of pos when path is an
+// encrypted note. A note encrypted after its position was recorded still
+// carries plaintext anchors in memory; they never leave it, whether through
+// state.json or a state export.
+func scrubEncryptedPosition(path string, pos notePosition) notePosition {
+	if isEncryptedNotePath(path) {
+		pos.CursorBefore, pos.CursorAfter, pos.PreviewHeading, pos.ContentHash = "", "", "", ""
+	}
+	return pos
+}
+
 // remapStatePaths updates all persisted state references when a file or folder
-// is renamed or moved. Pinned paths, note positions, and recent file entries
-// are all updated so that the old path prefix is replaced with the new one.
-// This ensures state survives rename/move operations without data loss.
+// is renamed or moved. Pinned paths, note positions, folder sort overrides,
+// archive origins, and recent file entries are all updated so that the old path prefix is
+// replaced with the new one. This ensures state survives rename/move
+// operations without data loss.
 func (m *Model) remapStatePaths(oldPath, newPath string) {
 	if oldPath == "" || newPath == "" || oldPath == newPath {
 		return
@@ -469,6 +662,8 @@ func (m *Model) remapStatePaths(oldPath, newPath string) {
 	m.remapPinnedPaths(oldPath, newPath)
 	m.remapPositionPaths(oldPath, newPath)
 	m.remapOpenCountPaths(oldPath, newPath)
+	m.remapFolderSortPaths(oldPath, newPath)
+	m.remapArchiveOriginPaths(oldPath, newPath)
 	m.remapRecentPaths(oldPath, newPath)
 	m.rebuildRecentEntries()
 	m.saveAppState()
@@ -496,7 +691,8 @@ func (m *Model) remapPositionPaths(oldPath, newPath string) {
 	}
 	remapped := make(map[string]notePosition, len(m.notePositions))
 	for path, pos := range m.notePositions {
-		remapped[replacePathPrefix(path, oldPath, newPath)] = pos
+		target := replacePathPrefix(path, oldPath, newPath)
+		remapped[target] = scrubEncryptedPosition(target, pos)
 	}
 	m.notePositions = remapped
 }
@@ -526,6 +722,33 @@ func (m *Model) remapOpenCountPaths(oldPath, newPath string) {
 	m.noteOpenCounts = remapped
 }
 
+// remapFolderSortPaths replaces oldPath prefix with newPath in all folder
+// sort overrides.
+func (m *Model) remapFolderSortPaths(oldPath, newPath string) {
+	if len(m.folderSorts) == 0 {
+		return
+	}
+	remapped := make(map[string]folderSort, len(m.folderSorts))
+	for path, override := range m.folderSorts {
+		remapped[replacePathPrefix(path, oldPath, newPath)] = override
+	}
+	m.folderSorts = remapped
+}
+
+// remapArchiveOriginPaths replaces oldPath prefix with newPath in the keys of
+// archive origin records, so moving an item within the archive keeps its
+// restore location.
+func (m *Model) remapArchiveOriginPaths(oldPath, newPath string) {
+	if len(m.archiveOrigins) == 0 {
+		return
+	}
+	remapped := make(map[string]string, len(m.archiveOrigins))
+	for path, origin := range m.archiveOrigins {
+		remapped[replacePathPrefix(path, oldPath, newPath)] = origin
+	}
+	m.archiveOrigins = remapped
+}
+
 // removePathFromList returns a new slice with all occurrences of target removed.
 func removePathFromList(paths []string, target string) []string {
 	if len(paths) == 0 {
@@ -0,0 +1,401 @@
+// state_transfer.go implements workspace state export and import, for
+// carrying pins, reading positions, open counts, and saved search views to
+// a clone of the notes directory on another machine (the managed
+// .cli-notes folder holding state.json is usually not committed).
+//
+// `notes state export <file>` writes the sharable parts of the configured
+// workspace's state as portable JSON, with slash-separated paths relative
+// to the notes directory. Recent files, folder sort overrides, archive
+// origins, focus sessions, and view toggles stay machine-local.
+// `notes state import <file>` merges such a file into the current state:
+//
+//   - pins are unioned;
+//   - a reading position replaces the local one only when it was recorded
+//     later (positions carry the time they were last updated);
+//   - open counts keep the higher count, so importing twice is harmless;
+//   - saved views are added by name; on a name clash with a different query
+//     the local view is kept.
+//
+// Every imported path must resolve inside the workspace and exist there;
+// the rest are skipped and counted as missing. The maintenance popup (Alt+W)
+// runs the same export and import from inside the app.
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// stateExportVersion is written to exported state files; newer versions are
+// rejected on import.
+const stateExportVersion = 1
+
+// portableState is the on-disk form of an exported workspace state.
+type portableState struct {
+	Version     int                     `json:"version"`
+	PinnedPaths []string                `json:"pinned_paths,omitempty"`
+	Positions   map[string]notePosition `json:"positions,omitempty"`
+	OpenCounts  map[string]int          `json:"open_counts,omitempty"`
+	SavedViews  []savedView             `json:"saved_views,omitempty"`
+}
+
+// stateImportResult counts what an import did with each entry.
+type stateImportResult struct {
+	imported  int
+	unchanged int
+	// conflicts counts saved views kept local over a different query.
+	conflicts int
+	missing   int
+}
+
+// String summarizes the result for the status bar and the CLI.
+func (r stateImportResult) String() string {
+	s := fmt.Sprintf("Imported %d, skipped %d missing", r.imported, r.missing)
+	if r.unchanged > 0 {
+		s += fmt.Sprintf(", %d already up to date", r.unchanged)
+	}
+	if r.conflicts > 0 {
+		noun := "views"
+		if r.conflicts == 1 {
+			noun = "view"
+		}
+		s += fmt.Sprintf(", kept %d local %s with the same name", r.conflicts, noun)
+	}
+	return s
+}
+
+// RunState runs `notes state export|import <file>` against the configured
+// workspace and prints a one-line summary to out.
+func RunState(out io.Writer, op, file string) error {
+	if file == "" || (op != "export" && op != "import") {
+		return errors.New("usage: notes state export|import <file>")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	state, err := loadAppState(cfg.NotesDir)
+	if err != nil {
+		return err
+	}
+	m := &Model{notesDir: cfg.NotesDir}
+	m.applyAppState(state)
+	path, err := config.NormalizeNotesDir(file)
+	if err != nil {
+		return fmt.Errorf("state file: %w", err)
+	}
+	if op == "export" {
+		if err := writePortableState(path, m.portableState()); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Exported workspace state to %s\n", path)
+		return nil
+	}
+	res, err := m.importStateFile(path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, res)
+	return nil
+}
+
+// applyAppState replaces the model's persisted state with state.
+func (m *Model) applyAppState(state appPersistentState) {
+	m.pinnedPaths = state.PinnedPaths
+	m.recentFiles = state.RecentFiles
+	m.notePositions = state.Positions
+	m.noteOpenCounts = state.OpenCounts
+	m.folderSorts = state.FolderSorts
+	m.archiveOrigins = state.ArchivedFrom
+	m.showMetadataStrip = state.ShowMetadataStrip
+	m.focusHistory = state.FocusSessions
+	m.savedViews = state.SavedViews
+	m.lastSearchTag = state.LastSearchTag
+}
+
+// portableState collects the sharable state of the workspace.
+func (m *Model) portableState() portableState {
+	out := portableState{
+		Version:    stateExportVersion,
+		Positions:  map[string]notePosition{},
+		OpenCounts: map[string]int{},
+		SavedViews: m.savedViews,
+	}
+	for path, pinned := range m.pinnedPaths {
+		if rel, ok := absToStatePath(m.notesDir, path); ok && pinned {
+			out.PinnedPaths = append(out.PinnedPaths, filepath.ToSlash(rel))
+		}
+	}
+	sort.Strings(out.PinnedPaths)
+	for path, pos := range m.notePositions {
+		if pos.PrimaryPreviewOffset <= 0 && pos.SecondaryPreviewOffset <= 0 && pos.EditorCursor <= 0 {
+			continue
+		}
+		if rel, ok := absToStatePath(m.notesDir, path); ok {
+			out.Positions[filepath.ToSlash(rel)] = scrubEncryptedPosition(path, pos)
+		}
+	}
+	for path, count := range m.noteOpenCounts {
+		if rel, ok := absToStatePath(m.notesDir, path); ok && count > 0 {
+			out.OpenCounts[filepath.ToSlash(rel)] = count
+		}
+	}
+	return out
+}
+
+// writePortableState writes state to path as indented JSON.
+func writePortableState(path string, state portableState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), DirPermission); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// readPortableState reads an exported state file.
+func readPortableState(path string) (portableState, error) {
+	var state portableState
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("parse state file %q: %w", path, err)
+	}
+	if state.Version < 1 || state.Version > stateExportVersion {
+		return state, fmt.Errorf("unsupported state file version %d", state.Version)
+	}
+	return state, nil
+}
+
+// importStateFile merges the state file at path into the workspace state
+// and saves it.
+func (m *Model) importStateFile(path string) (stateImportResult, error) {
+	state, err := readPortableState(path)
+	if err != nil {
+		return stateImportResult{}, err
+	}
+	res := m.mergePortableState(state)
+	m.saveAppState()
+	return res, nil
+}
+
+// mergePortableState merges state into the model using the rules in the
+// file comment.
+func (m *Model) mergePortableState(state portableState) stateImportResult {
+	var res stateImportResult
+	resolve := func(rel string) (string, bool) {
+		abs, ok := statePathToAbs(m.notesDir, filepath.FromSlash(rel))
+		if ok {
+			_, err := os.Stat(abs)
+			ok = err == nil
+		}
+		if !ok {
+			res.missing++
+		}
+		return abs, ok
+	}
+
+	if m.pinnedPaths == nil {
+		m.pinnedPaths = map[string]bool{}
+	}
+	for _, rel := range state.PinnedPaths {
+		abs, ok := resolve(rel)
+		switch {
+		case !ok:
+		case m.pinnedPaths[abs]:
+			res.unchanged++
+		default:
+			m.pinnedPaths[abs] = true
+			res.imported++
+		}
+	}
+
+	if m.notePositions == nil {
+		m.notePositions = map[string]notePosition{}
+	}
+	for rel, pos := range state.Positions {
+		abs, ok := resolve(rel)
+		if !ok {
+			continue
+		}
+		if local, exists := m.notePositions[abs]; exists && local.Updated >= pos.Updated {
+			res.unchanged++
+			continue
+		}
+		m.notePositions[abs] = pos
+		res.imported++
+	}
+
+	if m.noteOpenCounts == nil {
+		m.noteOpenCounts = map[string]int{}
+	}
+	for rel, count := range state.OpenCounts {
+		abs, ok := resolve(rel)
+		if !ok {
+			continue
+		}
+		if count <= m.noteOpenCounts[abs] {
+			res.unchanged++
+			continue
+		}
+		m.noteOpenCounts[abs] = count
+		res.imported++
+	}
+
+	for _, view := range normalizeSavedViews(state.SavedViews) {
+		idx := savedViewIndex(m.savedViews, view.Name)
+		switch {
+		case idx < 0:
+			m.savedViews = append(m.savedViews, view)
+			res.imported++
+		case m.savedViews[idx].Query == view.Query:
+			res.unchanged++
+		default:
+			res.conflicts++
+		}
+	}
+	return res
+}
+
+// maintenanceItem is one row of the maintenance popup.
+type maintenanceItem struct {
+	label string
+	run   func(*Model)
+}
+
+// maintenanceItems lists the maintenance popup rows in display order.
+var maintenanceItems = []maintenanceItem{
+	{"Export workspace state (pins, positions, open counts, saved views)", (*Model).startStateExport},
+	{"Import workspace state from another machine", (*Model).startStateImport},
+}
+
+// openMaintenancePopup shows the workspace maintenance operations.
+func (m *Model) openMaintenancePopup() {
+	m.openOverlay(overlayMaintenance)
+	m.maintenanceCursor = clamp(m.maintenanceCursor, 0, len(maintenanceItems)-1)
+	m.status = "Maintenance: Enter to run, Esc to close"
+}
+
+// handleMaintenancePopupKey routes key presses while the maintenance popup
+// is visible.
+func (m *Model) handleMaintenancePopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	if idx, ok := m.popupQuickSelect(msg); ok {
+		if idx < len(maintenanceItems) {
+			m.maintenanceCursor = idx
+			maintenanceItems[idx].run(m)
+		}
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.maintenanceCursor, len(maintenanceItems))
+	if !handled {
+		return m, nil
+	}
+	if closePressed {
+		m.closeOverlay()
+		m.status = "Maintenance closed"
+		return m, nil
+	}
+	m.maintenanceCursor = next
+	if selectPressed {
+		maintenanceItems[m.maintenanceCursor].run(m)
+	}
+	return m, nil
+}
+
+// renderMaintenancePopup draws the maintenance popup.
+func (m *Model) renderMaintenancePopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	lines := []string{
+		titleStyle.Render("Maintenance (" + m.primaryActionKey(actionMaintenance, "Alt+W") + ")"),
+		"",
+	}
+	for i, item := range maintenanceItems {
+		line := truncate(fmt.Sprintf("%d %s", i+1, item.label), innerWidth)
+		if i == m.maintenanceCursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", mutedStyle.Render("Enter/1-9: run  Esc: close"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
+
+// startStateExport asks for the file to export workspace state to.
+func (m *Model) startStateExport() { m.startStateTransfer(false) }
+
+// startStateImport asks for the state file to import.
+func (m *Model) startStateImport() { m.startStateTransfer(true) }
+
+// startStateTransfer opens the state file prompt, prefilled with a file
+// next to the notes directory.
+func (m *Model) startStateTransfer(importing bool) {
+	m.closeOverlay()
+	m.stateImport = importing
+	m.mode = modeStateTransfer
+	m.showHelp = false
+	m.input.Reset()
+	m.input.Placeholder = "State file (~ allowed)"
+	m.input.SetValue(filepath.Join(filepath.Dir(m.notesDir), filepath.Base(m.notesDir)+"-state.json"))
+	m.input.CursorEnd()
+	m.input.Focus()
+	if importing {
+		m.status = "Import state: Enter or Ctrl+S to import, Esc to cancel"
+	} else {
+		m.status = "Export state: Enter or Ctrl+S to export, Esc to cancel"
+	}
+}
+
+// handleStateTransferKey processes keypresses in the state file prompt.
+func (m *Model) handleStateTransferKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	cancel := "State export cancelled"
+	if m.stateImport {
+		cancel = "State import cancelled"
+	}
+	return m.handleInputModeKey(msg, m.submitStateTransfer, cancel)
+}
+
+// submitStateTransfer runs the export or import for the entered file.
+func (m *Model) submitStateTransfer() (tea.Model, tea.Cmd) {
+	path, err := config.NormalizeNotesDir(m.input.Value())
+	if err != nil {
+		m.status = "State file is required"
+		return m, nil
+	}
+	m.mode = modeBrowse
+	m.rememberCurrentNotePosition()
+	if !m.stateImport {
+		if err := writePortableState(path, m.portableState()); err != nil {
+			m.setStatusError("Error exporting state", err, "path", path)
+			return m, nil
+		}
+		m.status = "Exported workspace state to " + path
+		return m, nil
+	}
+	res, err := m.importStateFile(path)
+	if err != nil {
+		m.setStatusError("Error importing state", err, "path", path)
+		return m, nil
+	}
+	m.rebuildTreeKeep(m.selectedPath())
+	m.status = res.String()
+	return m, nil
+}
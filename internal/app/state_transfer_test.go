@@ -0,0 +1,195 @@
+package app
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStateExportImportRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	for _, rel := range []string{"a.md", "work/b.md", "gone.md"} {
+		mustWriteFile(t, filepath.Join(src, rel), "# note\n")
+	}
+	from := &Model{notesDir: src}
+	from.applyAppState(appPersistentState{
+		PinnedPaths: map[string]bool{filepath.Join(src, "work"): true, filepath.Join(src, "gone.md"): true},
+		Positions: map[string]notePosition{
+			filepath.Join(src, "work/b.md"): {PrimaryPreviewOffset: 12, EditorCursor: 40, Updated: 100},
+		},
+		OpenCounts: map[string]int{filepath.Join(src, "a.md"): 3},
+		SavedViews: []savedView{{Name: "todo", Query: "tag:todo"}},
+		// Machine-local state is not exported.
+		RecentFiles: []string{filepath.Join(src, "a.md")},
+	})
+	file := filepath.Join(t.TempDir(), "state.json")
+	if err := writePortableState(file, from.portableState()); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := t.TempDir()
+	for _, rel := range []string{"a.md", "work/b.md"} {
+		mustWriteFile(t, filepath.Join(dst, rel), "# note\n")
+	}
+	to := &Model{notesDir: dst}
+	res, err := to.importStateFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (stateImportResult{imported: 4, missing: 1}) {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if res.String() != "Imported 4, skipped 1 missing" {
+		t.Fatalf("unexpected summary %q", res.String())
+	}
+
+	reloaded, err := loadAppState(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reloaded.PinnedPaths, map[string]bool{filepath.Join(dst, "work"): true}) {
+		t.Fatalf("unexpected pins %v", reloaded.PinnedPaths)
+	}
+	if pos := reloaded.Positions[filepath.Join(dst, "work/b.md")]; pos.PrimaryPreviewOffset != 12 || pos.EditorCursor != 40 || pos.Updated != 100 {
+		t.Fatalf("unexpected position %+v", pos)
+	}
+	if reloaded.OpenCounts[filepath.Join(dst, "a.md")] != 3 {
+		t.Fatalf("unexpected open counts %v", reloaded.OpenCounts)
+	}
+	if !reflect.DeepEqual(reloaded.SavedViews, []savedView{{Name: "todo", Query: "tag:todo"}}) {
+		t.Fatalf("unexpected views %v", reloaded.SavedViews)
+	}
+	if len(reloaded.RecentFiles) != 0 {
+		t.Fatalf("recent files should stay machine-local, got %v", reloaded.RecentFiles)
+	}
+
+	// Importing the same file again changes nothing.
+	res, err = to.importStateFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != (stateImportResult{unchanged: 4, missing: 1}) {
+		t.Fatalf("unexpected result on re-import %+v", res)
+	}
+}
+
+func TestStateExportScrubsEncryptedNoteAnchors(t *testing.T) {
+	root := t.TempDir()
+	plain := filepath.Join(root, "x.md")
+	enc := plain + EncryptedNoteExt
+	m := &Model{notesDir: root}
+	m.applyAppState(appPersistentState{
+		Positions: map[string]notePosition{
+			plain: {
+				EditorCursor:   12,
+				CursorBefore:   "my secret pin 1234",
+				CursorAfter:    "and more",
+				PreviewHeading: "Bank PIN",
+				ContentHash:    "abc",
+				Updated:        100,
+			},
+		},
+	})
+	// Encrypting the note moves its position onto the encrypted path.
+	m.remapStatePaths(plain, enc)
+	if pos := m.notePositions[enc]; pos.CursorBefore != "" || pos.PreviewHeading != "" {
+		t.Fatalf("remap kept plaintext anchors for an encrypted note: %+v", pos)
+	}
+
+	// Anchors already in memory under the encrypted path are scrubbed on export.
+	m.notePositions[enc] = notePosition{EditorCursor: 12, CursorBefore: "my secret pin 1234", PreviewHeading: "Bank PIN", Updated: 100}
+	pos, ok := m.portableState().Positions["x.md"+EncryptedNoteExt]
+	if !ok {
+		t.Fatal("expected the encrypted note's position to be exported")
+	}
+	if pos.CursorBefore != "" || pos.CursorAfter != "" || pos.PreviewHeading != "" || pos.ContentHash != "" {
+		t.Fatalf("exported plaintext anchors for an encrypted note: %+v", pos)
+	}
+	if pos.EditorCursor != 12 || pos.Updated != 100 {
+		t.Fatalf("expected offsets to survive the scrub, got %+v", pos)
+	}
+}
+
+func TestMergePortableStateResolvesConflicts(t *testing.T) {
+	root := t.TempDir()
+	for _, rel := range []string{"old.md", "new.md", "local.md"} {
+		mustWriteFile(t, filepath.Join(root, rel), "# note\n")
+	}
+	path := func(rel string) string { return filepath.Join(root, rel) }
+	m := &Model{
+		notesDir:    root,
+		pinnedPaths: map[string]bool{path("local.md"): true},
+		notePositions: map[string]notePosition{
+			path("old.md"): {EditorCursor: 5, Updated: 200},
+			path("new.md"): {EditorCursor: 5, Updated: 100},
+		},
+		noteOpenCounts: map[string]int{path("old.md"): 9, path("new.md"): 1},
+		savedViews:     []savedView{{Name: "Todo", Query: "tag:todo"}},
+	}
+
+	res := m.mergePortableState(portableState{
+		Version:     stateExportVersion,
+		PinnedPaths: []string{"new.md", "../outside.md"},
+		Positions: map[string]notePosition{
+			"old.md": {EditorCursor: 50, Updated: 150},
+			"new.md": {EditorCursor: 60, Updated: 300},
+		},
+		OpenCounts: map[string]int{"old.md": 2, "new.md": 4},
+		SavedViews: []savedView{{Name: "todo", Query: "tag:later"}, {Name: "done", Query: "tag:done"}},
+	})
+
+	if !m.pinnedPaths[path("local.md")] || !m.pinnedPaths[path("new.md")] {
+		t.Fatalf("expected pins to be unioned, got %v", m.pinnedPaths)
+	}
+	if m.notePositions[path("old.md")].EditorCursor != 5 || m.notePositions[path("new.md")].EditorCursor != 60 {
+		t.Fatalf("expected the newer position to win, got %v", m.notePositions)
+	}
+	if m.noteOpenCounts[path("old.md")] != 9 || m.noteOpenCounts[path("new.md")] != 4 {
+		t.Fatalf("expected the higher open count to win, got %v", m.noteOpenCounts)
+	}
+	want := []savedView{{Name: "Todo", Query: "tag:todo"}, {Name: "done", Query: "tag:done"}}
+	if !reflect.DeepEqual(m.savedViews, want) {
+		t.Fatalf("expected the local view kept on a name clash, got %v", m.savedViews)
+	}
+	if res != (stateImportResult{imported: 4, unchanged: 2, conflicts: 1, missing: 1}) {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if res.String() != "Imported 4, skipped 1 missing, 2 already up to date, kept 1 local view with the same name" {
+		t.Fatalf("unexpected summary %q", res.String())
+	}
+}
+
+func TestReadPortableStateRejectsUnknownVersion(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state.json")
+	mustWriteFile(t, file, `{"version": 2}`)
+	if _, err := readPortableState(file); err == nil {
+		t.Fatal("expected an error for a newer state file version")
+	}
+}
+
+func TestMaintenancePopupExportsState(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "# A\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.pinnedPaths = map[string]bool{filepath.Join(root, "a.md"): true}
+	m.openMaintenancePopup()
+
+	_, _ = m.handleKey(keyPress("1"))
+	if m.mode != modeStateTransfer || m.stateImport || m.overlay != overlayNone {
+		t.Fatalf("expected the export prompt, mode %v import %v overlay %v", m.mode, m.stateImport, m.overlay)
+	}
+	file := filepath.Join(t.TempDir(), "out", "state.json")
+	m.input.SetValue(file)
+	_, _ = m.submitStateTransfer()
+	if m.status != "Exported workspace state to "+file {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	state, err := readPortableState(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(state.PinnedPaths, []string{"a.md"}) {
+		t.Fatalf("unexpected exported pins %v", state.PinnedPaths)
+	}
+}
@@ -2,12 +2,15 @@
 //
 // The UI uses ANSI 256-color palettes so it renders correctly in virtually all
 // modern terminal emulators without requiring true-color support. The palette
-// is selected from config via theme_preset (ocean_citrus, sunset, neon_slate).
+// is selected from config via theme_preset (ocean_citrus, sunset, neon_slate,
+// light),
+// or loaded from a custom JSON theme via theme_file (see the theme package).
 // Preview and edit modes are distinguished by separate accent tokens.
 //
 // Tree rows use green for directories and blue for markdown files, with
-// distinct badge styles for DIR/MD/PIN/TAGS labels. The selected row is
-// rendered with reversed colors for high contrast.
+// distinct badge styles for DIR/MD/PIN/TAGS labels. The selected row uses
+// explicit palette colors rather than reversed video, so its contrast does not
+// depend on the terminal's own foreground and background.
 //
 // The editor textarea has its own theme (see applyEditorTheme) with a dark
 // background cursor line, pink line numbers, and a muted placeholder. Fenced
@@ -19,11 +22,12 @@ import (
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/treykane/cli-notes/internal/config"
+	"github.com/treykane/cli-notes/internal/theme"
 )
 
 var (
 	// Semantic palette tokens reused across panes, badges, editor, and footer.
-	// Values are set by applyThemePreset during app startup.
+	// Values are set by applyTheme during app startup.
 	surface     lipgloss.Color
 	surfaceAlt  lipgloss.Color
 	textPrimary lipgloss.Color
@@ -34,11 +38,14 @@ var (
 	accentWarn    lipgloss.Color
 	accentSuccess lipgloss.Color
 
-	badgeDir  lipgloss.Color
-	badgeFile lipgloss.Color
-	badgePin  lipgloss.Color
-	badgeTags lipgloss.Color
+	badgeDir     lipgloss.Color
+	badgeFile    lipgloss.Color
+	badgePin     lipgloss.Color
+	badgePinText lipgloss.Color
+	badgeTags    lipgloss.Color
 
+	selectedBg  lipgloss.Color
+	selectedFg  lipgloss.Color
 	selectionBg lipgloss.Color
 	selectionFg lipgloss.Color
 
@@ -58,9 +65,8 @@ var (
 	// editPane styles the right pane border in edit mode.
 	editPane = paneStyle.Copy().BorderForeground(accentEdit)
 
-	// selectedStyle highlights the currently selected tree row or popup entry
-	// by reversing foreground and background colors.
-	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	// selectedStyle highlights the currently selected tree row or popup entry.
+	selectedStyle = lipgloss.NewStyle().Background(selectedBg).Foreground(selectedFg)
 
 	// titleStyle renders section headings (popup titles, tree header) in bold.
 	titleStyle = lipgloss.NewStyle().Bold(true)
@@ -72,6 +78,10 @@ var (
 	// editStatus renders the footer status bar in edit mode with the edit accent.
 	editStatus = lipgloss.NewStyle().Bold(true).Foreground(textPrimary).Background(accentEdit)
 
+	// wordGoalMetStyle highlights footer word-goal progress once the note's
+	// word_goal is reached.
+	wordGoalMetStyle = lipgloss.NewStyle().Bold(true).Foreground(accentSuccess)
+
 	// mutedStyle renders de-emphasized text (hints, placeholders, empty-state
 	// messages) in a mid-gray that recedes visually.
 	mutedStyle = lipgloss.NewStyle().Foreground(textMuted)
@@ -90,6 +100,12 @@ var (
 	// treeFileName styles markdown file names in the tree view (light blue).
 	treeFileName = lipgloss.NewStyle().Foreground(accentBrowse)
 
+	// treeFileNameFresh and treeFileNameStale replace treeFileName for
+	// recently modified (bold) and long untouched (muted) notes when note
+	// age colors are on.
+	treeFileNameFresh = lipgloss.NewStyle().Bold(true).Foreground(accentBrowse)
+	treeFileNameStale = lipgloss.NewStyle().Foreground(textMuted)
+
 	// treeDirTag is the badge style for the "DIR" label on directory rows
 	// (white text on dark green background).
 	treeDirTag = lipgloss.NewStyle().Bold(true).Foreground(textPrimary).Background(badgeDir)
@@ -100,7 +116,11 @@ var (
 
 	// treePinTag is the badge style for the "PIN" label on pinned items
 	// (black text on yellow background for maximum visibility).
-	treePinTag = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("16")).Background(badgePin)
+	treePinTag = lipgloss.NewStyle().Bold(true).Foreground(badgePinText).Background(badgePin)
+
+	// treeLockTag replaces the "MD" badge on encrypted notes (dark text on the
+	// warning accent).
+	treeLockTag = lipgloss.NewStyle().Bold(true).Foreground(badgePinText).Background(accentWarn)
 
 	// treeTagBadge styles the compact "TAGS:..." label shown next to markdown
 	// files that have frontmatter tags (light text on muted purple background).
@@ -112,6 +132,12 @@ var (
 	// treeClosedMark styles the "[+]" marker for collapsed directories (orange).
 	treeClosedMark = lipgloss.NewStyle().Bold(true).Foreground(accentWarn)
 
+	// diffAddedLine, diffRemovedLine, and diffHunkLine color added lines,
+	// removed lines, and @@ hunk headers in the git diff popup.
+	diffAddedLine   = lipgloss.NewStyle().Foreground(accentSuccess)
+	diffRemovedLine = lipgloss.NewStyle().Foreground(accentWarn)
+	diffHunkLine    = lipgloss.NewStyle().Foreground(accentBrowse)
+
 	// selectionText styles editor text that is currently selected (white
 	// background with black text for a clear highlight).
 	selectionText = lipgloss.NewStyle().Background(selectionBg).Foreground(selectionFg)
@@ -123,136 +149,193 @@ var (
 	// editorFenceLine styles the ``` fence delimiters themselves in the
 	// editor (gold/amber for easy identification of code block boundaries).
 	editorFenceLine = lipgloss.NewStyle()
-)
 
-type themePalette struct {
-	surface     string
-	surfaceAlt  string
-	textPrimary string
-	textMuted   string
-
-	accentBrowse  string
-	accentEdit    string
-	accentWarn    string
-	accentSuccess string
-
-	badgeDir  string
-	badgeFile string
-	badgePin  string
-	badgeTags string
-
-	selectionBg  string
-	selectionFg  string
-	editorCodeFg string
-}
+	// editorActiveLine tints the rows of the editor line holding the cursor
+	// (see editor_active_line.go).
+	editorActiveLine = lipgloss.NewStyle()
+)
 
 func init() {
 	applyThemePreset(config.ThemePresetOceanCitrus)
 }
 
-func paletteForPreset(preset string) themePalette {
+// paletteForPreset returns the built-in colors of a theme preset.
+func paletteForPreset(preset string) theme.Theme {
 	switch config.NormalizeThemePreset(preset) {
 	case config.ThemePresetSunset:
-		return themePalette{
-			surface:       "236",
-			surfaceAlt:    "238",
-			textPrimary:   "230",
-			textMuted:     "180",
-			accentBrowse:  "209",
-			accentEdit:    "175",
-			accentWarn:    "220",
-			accentSuccess: "150",
-			badgeDir:      "94",
-			badgeFile:     "130",
-			badgePin:      "220",
-			badgeTags:     "131",
-			selectionBg:   "224",
-			selectionFg:   "52",
-			editorCodeFg:  "216",
+		return theme.Theme{
+			Surface:       "236",
+			SurfaceAlt:    "238",
+			TextPrimary:   "230",
+			TextMuted:     "180",
+			AccentBrowse:  "209",
+			AccentEdit:    "175",
+			AccentWarn:    "220",
+			AccentSuccess: "150",
+			BadgeDir:      "94",
+			BadgeFile:     "130",
+			BadgePin:      "220",
+			BadgePinText:  "16",
+			BadgeTags:     "131",
+			SelectedBg:    "230",
+			SelectedFg:    "236",
+			SelectionBg:   "224",
+			SelectionFg:   "52",
+			EditorCode:    "216",
 		}
 	case config.ThemePresetNeonSlate:
-		return themePalette{
-			surface:       "234",
-			surfaceAlt:    "236",
-			textPrimary:   "255",
-			textMuted:     "249",
-			accentBrowse:  "51",
-			accentEdit:    "141",
-			accentWarn:    "227",
-			accentSuccess: "118",
-			badgeDir:      "22",
-			badgeFile:     "24",
-			badgePin:      "227",
-			badgeTags:     "60",
-			selectionBg:   "195",
-			selectionFg:   "16",
-			editorCodeFg:  "87",
+		return theme.Theme{
+			Surface:       "234",
+			SurfaceAlt:    "236",
+			TextPrimary:   "255",
+			TextMuted:     "249",
+			AccentBrowse:  "51",
+			AccentEdit:    "141",
+			AccentWarn:    "227",
+			AccentSuccess: "118",
+			BadgeDir:      "22",
+			BadgeFile:     "24",
+			BadgePin:      "227",
+			BadgePinText:  "16",
+			BadgeTags:     "60",
+			SelectedBg:    "255",
+			SelectedFg:    "234",
+			SelectionBg:   "195",
+			SelectionFg:   "16",
+			EditorCode:    "87",
+		}
+	case config.ThemePresetLight:
+		return theme.Theme{
+			Surface:       "254",
+			SurfaceAlt:    "250",
+			TextPrimary:   "234",
+			TextMuted:     "243",
+			AccentBrowse:  "32",
+			AccentEdit:    "133",
+			AccentWarn:    "166",
+			AccentSuccess: "28",
+			BadgeDir:      "151",
+			BadgeFile:     "153",
+			BadgePin:      "220",
+			BadgePinText:  "16",
+			BadgeTags:     "189",
+			SelectedBg:    "25",
+			SelectedFg:    "231",
+			SelectionBg:   "24",
+			SelectionFg:   "231",
+			EditorCode:    "25",
+			MarkdownStyle: theme.MarkdownStyleLight,
 		}
 	default:
-		return themePalette{
-			surface:       "236",
-			surfaceAlt:    "238",
-			textPrimary:   "255",
-			textMuted:     "250",
-			accentBrowse:  "39",
-			accentEdit:    "44",
-			accentWarn:    "214",
-			accentSuccess: "114",
-			badgeDir:      "29",
-			badgeFile:     "25",
-			badgePin:      "214",
-			badgeTags:     "37",
-			selectionBg:   "230",
-			selectionFg:   "17",
-			editorCodeFg:  "117",
+		return theme.Theme{
+			Surface:       "236",
+			SurfaceAlt:    "238",
+			TextPrimary:   "255",
+			TextMuted:     "250",
+			AccentBrowse:  "39",
+			AccentEdit:    "44",
+			AccentWarn:    "214",
+			AccentSuccess: "114",
+			BadgeDir:      "29",
+			BadgeFile:     "25",
+			BadgePin:      "214",
+			BadgePinText:  "16",
+			BadgeTags:     "37",
+			SelectedBg:    "255",
+			SelectedFg:    "236",
+			SelectionBg:   "230",
+			SelectionFg:   "17",
+			EditorCode:    "117",
+		}
+	}
+}
+
+// loadWorkspaceThemePreset resolves the theme preset for the workspace at
+// notesDir, falling back to the global theme_preset.
+func loadWorkspaceThemePreset(cfg config.Config, notesDir string) string {
+	if notesDir != "" {
+		if preset, ok := cfg.ThemePresetByWorkspace[notesDir]; ok {
+			return preset
 		}
 	}
+	return cfg.ThemePreset
 }
 
-// applyThemePreset rebuilds global style tokens for the selected theme.
+// loadWorkspaceTheme resolves the colors for the workspace at notesDir. A
+// loadable theme_file wins over every preset; when it is unset or fails to
+// load, the workspace's theme preset is used.
+func loadWorkspaceTheme(cfg config.Config, notesDir string) theme.Theme {
+	if cfg.ThemeFile != "" {
+		t, err := theme.LoadTheme(cfg.ThemeFile)
+		if err == nil {
+			return t
+		}
+		appLog.Warn("load theme file", "path", cfg.ThemeFile, "error", err)
+	}
+	return paletteForPreset(loadWorkspaceThemePreset(cfg, notesDir))
+}
+
+// applyThemePreset rebuilds global style tokens for the selected preset.
 func applyThemePreset(preset string) {
-	p := paletteForPreset(preset)
-
-	surface = lipgloss.Color(p.surface)
-	surfaceAlt = lipgloss.Color(p.surfaceAlt)
-	textPrimary = lipgloss.Color(p.textPrimary)
-	textMuted = lipgloss.Color(p.textMuted)
-	accentBrowse = lipgloss.Color(p.accentBrowse)
-	accentEdit = lipgloss.Color(p.accentEdit)
-	accentWarn = lipgloss.Color(p.accentWarn)
-	accentSuccess = lipgloss.Color(p.accentSuccess)
-	badgeDir = lipgloss.Color(p.badgeDir)
-	badgeFile = lipgloss.Color(p.badgeFile)
-	badgePin = lipgloss.Color(p.badgePin)
-	badgeTags = lipgloss.Color(p.badgeTags)
-	selectionBg = lipgloss.Color(p.selectionBg)
-	selectionFg = lipgloss.Color(p.selectionFg)
+	applyTheme(paletteForPreset(preset))
+}
+
+// applyTheme rebuilds global style tokens from the colors in p.
+func applyTheme(p theme.Theme) {
+	surface = lipgloss.Color(p.Surface)
+	surfaceAlt = lipgloss.Color(p.SurfaceAlt)
+	textPrimary = lipgloss.Color(p.TextPrimary)
+	textMuted = lipgloss.Color(p.TextMuted)
+	accentBrowse = lipgloss.Color(p.AccentBrowse)
+	accentEdit = lipgloss.Color(p.AccentEdit)
+	accentWarn = lipgloss.Color(p.AccentWarn)
+	accentSuccess = lipgloss.Color(p.AccentSuccess)
+	badgeDir = lipgloss.Color(p.BadgeDir)
+	badgeFile = lipgloss.Color(p.BadgeFile)
+	badgePin = lipgloss.Color(p.BadgePin)
+	badgePinText = lipgloss.Color(p.BadgePinText)
+	badgeTags = lipgloss.Color(p.BadgeTags)
+	selectedBg = lipgloss.Color(p.SelectedBg)
+	selectedFg = lipgloss.Color(p.SelectedFg)
+	selectionBg = lipgloss.Color(p.SelectionBg)
+	selectionFg = lipgloss.Color(p.SelectionFg)
 
 	previewPane = paneStyle.Copy().BorderForeground(accentBrowse)
 	editPane = paneStyle.Copy().BorderForeground(accentEdit)
+	selectedStyle = lipgloss.NewStyle().Background(selectedBg).Foreground(selectedFg)
 	statusStyle = lipgloss.NewStyle().Bold(true).Foreground(textPrimary).Background(accentBrowse)
 	editStatus = lipgloss.NewStyle().Bold(true).Foreground(textPrimary).Background(accentEdit)
 	mutedStyle = lipgloss.NewStyle().Foreground(textMuted)
+	wordGoalMetStyle = lipgloss.NewStyle().Bold(true).Foreground(accentSuccess)
 	previewHeader = lipgloss.NewStyle().Bold(true).Foreground(textPrimary).Background(accentBrowse)
 	editHeader = lipgloss.NewStyle().Bold(true).Foreground(textPrimary).Background(accentEdit)
 	treeDirName = lipgloss.NewStyle().Bold(true).Foreground(accentSuccess)
 	treeFileName = lipgloss.NewStyle().Foreground(accentBrowse)
+	treeFileNameFresh = lipgloss.NewStyle().Bold(true).Foreground(accentBrowse)
+	treeFileNameStale = lipgloss.NewStyle().Foreground(textMuted)
 	treeDirTag = lipgloss.NewStyle().Bold(true).Foreground(textPrimary).Background(badgeDir)
 	treeFileTag = lipgloss.NewStyle().Bold(true).Foreground(textPrimary).Background(badgeFile)
-	treePinTag = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("16")).Background(badgePin)
+	treePinTag = lipgloss.NewStyle().Bold(true).Foreground(badgePinText).Background(badgePin)
+	treeLockTag = lipgloss.NewStyle().Bold(true).Foreground(badgePinText).Background(accentWarn)
 	treeTagBadge = lipgloss.NewStyle().Foreground(textPrimary).Background(badgeTags)
 	treeOpenMark = lipgloss.NewStyle().Bold(true).Foreground(accentSuccess)
 	treeClosedMark = lipgloss.NewStyle().Bold(true).Foreground(accentWarn)
+	diffAddedLine = lipgloss.NewStyle().Foreground(accentSuccess)
+	diffRemovedLine = lipgloss.NewStyle().Foreground(accentWarn)
+	diffHunkLine = lipgloss.NewStyle().Foreground(accentBrowse)
 	selectionText = lipgloss.NewStyle().Background(selectionBg).Foreground(selectionFg)
-	editorCodeLine = lipgloss.NewStyle().Foreground(lipgloss.Color(p.editorCodeFg))
+	editorCodeLine = lipgloss.NewStyle().Foreground(lipgloss.Color(p.EditorCode))
 	editorFenceLine = lipgloss.NewStyle().Foreground(accentWarn)
+	editorActiveLine = lipgloss.NewStyle().Background(surface)
+	setMarkdownTheme(p)
 }
 
 // applyEditorTheme configures the textarea widget's visual appearance to match
 // the app's dark theme. It sets distinct styles for focused and blurred states:
 //
-//   - Focused: light gray text, dark-gray cursor-line highlight, pink line
-//     numbers and prompt, and a muted placeholder.
+//   - Focused: light gray text, a bold cursor line number, pink line numbers
+//     and prompt, and a muted placeholder. The cursor line's background tint
+//     is drawn over the view by highlightEditorActiveLine, not the textarea.
 //   - Blurred: dimmed text so the editor visually recedes when not active.
 //
 // The prompt character "│ " provides a subtle vertical gutter between line
@@ -261,13 +344,12 @@ func applyEditorTheme(editor *textarea.Model) {
 	focused, blurred := textarea.DefaultStyles()
 
 	base := lipgloss.NewStyle().Foreground(textPrimary)
-	cursorLine := lipgloss.NewStyle().Background(surface).Foreground(textPrimary)
 	lineNumber := lipgloss.NewStyle().Foreground(accentBrowse)
 	prompt := lipgloss.NewStyle().Foreground(accentEdit)
 
 	focused.Base = base
 	focused.Text = base
-	focused.CursorLine = cursorLine
+	focused.CursorLine = base
 	focused.CursorLineNumber = lineNumber.Bold(true)
 	focused.LineNumber = lineNumber
 	focused.Prompt = prompt
@@ -297,7 +379,6 @@ func applyEditorTheme(editor *textarea.Model) {
 func applyEditorSelectionVisual(editor *textarea.Model) {
 	// Keep cursor-line visuals stable; selection highlighting is applied to selected text only.
 	editor.FocusedStyle.CursorLine = lipgloss.NewStyle().
-		Background(surface).
 		Foreground(textPrimary)
 	editor.FocusedStyle.CursorLineNumber = lipgloss.NewStyle().
 		Foreground(accentBrowse).
@@ -0,0 +1,106 @@
+// tag_browser.go implements the tag browser popup (Alt+B): every tag used
+// in the workspace with the number of notes carrying it, most used first
+// (searchIndex.allTags). Enter opens the search popup on `tag:<name>`; t
+// limits the tree to the tag instead (tag_filter.go), and r renames it in
+// every note (tag_rename.go).
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// openTagBrowserPopup counts the tags of the indexed notes and shows them.
+func (m *Model) openTagBrowserPopup() {
+	if m.searchIndex == nil {
+		return
+	}
+	if err := m.searchIndex.ensureBuilt(); err != nil {
+		m.setStatusError("Error loading tags", err, "root", m.notesDir)
+		return
+	}
+	tags := m.searchIndex.allTags()
+	if len(tags) == 0 {
+		m.status = "No tags found in notes"
+		return
+	}
+	m.tagBrowserTags = tags
+	m.tagBrowserCursor = clamp(m.tagBrowserCursor, 0, len(tags)-1)
+	m.openOverlay(overlayTagBrowser)
+	m.status = fmt.Sprintf("%d tags: Enter to search, t to filter the tree, r to rename, Esc to close", len(tags))
+}
+
+// handleTagBrowserPopupKey routes key presses while the tag browser is
+// visible.
+func (m *Model) handleTagBrowserPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	switch m.popupAction(msg) {
+	case popupActionTagFilter:
+		if m.tagBrowserCursor < len(m.tagBrowserTags) {
+			m.applyTagFilter(m.tagBrowserTags[m.tagBrowserCursor].name)
+		}
+		return m, nil
+	case popupActionTagRename:
+		if m.tagBrowserCursor < len(m.tagBrowserTags) {
+			m.startRenameTag(m.tagBrowserTags[m.tagBrowserCursor].name)
+		}
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.tagBrowserCursor, len(m.tagBrowserTags))
+	if !handled {
+		return m, nil
+	}
+	if closePressed {
+		m.closeOverlay()
+		m.status = "Tag browser closed"
+		return m, nil
+	}
+	m.tagBrowserCursor = next
+	if selectPressed && m.tagBrowserCursor < len(m.tagBrowserTags) {
+		m.searchTag(m.tagBrowserTags[m.tagBrowserCursor].name)
+	}
+	return m, nil
+}
+
+// searchTag opens the search popup on the notes tagged tag.
+func (m *Model) searchTag(tag string) {
+	m.openSearchPopup()
+	m.search.SetValue("tag:" + tag)
+	m.search.CursorEnd()
+	m.updateSearchRows()
+}
+
+// renderTagBrowserPopup draws the tag browser: tag names with their note
+// counts right-aligned.
+func (m *Model) renderTagBrowserPopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("Tags (%d)", len(m.tagBrowserTags))),
+		"",
+	}
+	limit := max(0, innerHeight-len(lines)-1)
+	start := 0
+	if limit > 0 {
+		start = max(0, m.tagBrowserCursor-limit+1)
+	}
+	for i := start; i < min(start+limit, len(m.tagBrowserTags)); i++ {
+		tag := m.tagBrowserTags[i]
+		count := fmt.Sprintf("%d", tag.count)
+		name := truncate("#"+tag.name, max(0, innerWidth-len(count)-1))
+		gap := max(1, innerWidth-lipgloss.Width(name)-len(count))
+		line := name + strings.Repeat(" ", gap) + count
+		if i == m.tagBrowserCursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, mutedStyle.Render("Enter: search  t: filter  r: rename  Esc: close"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+func TestAllTagsCountsAndSortsByFrequency(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "---\ntags: [work, Ideas]\n---\n# A\n")
+	mustWriteFile(t, filepath.Join(root, "b.md"), "---\ntags: [work, home]\n---\n# B\n")
+	mustWriteFile(t, filepath.Join(root, "c.md"), "---\ntags: [ideas]\n---\n# C\n")
+	mustWriteFile(t, filepath.Join(root, "d.md"), "# untagged\n")
+	idx := newSearchIndex(root)
+	if err := idx.ensureBuilt(); err != nil {
+		t.Fatalf("build index: %v", err)
+	}
+
+	got := idx.allTags()
+	want := []tagCount{{"ideas", 2}, {"work", 2}, {"home", 1}}
+	if len(got) != len(want) {
+		t.Fatalf("allTags = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("allTags = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestTagBrowserSearchesOrFiltersBySelectedTag(t *testing.T) {
+	root := t.TempDir()
+	work := filepath.Join(root, "work.md")
+	mustWriteFile(t, work, "---\ntags: [work]\n---\n# Work\n")
+	mustWriteFile(t, filepath.Join(root, "home.md"), "---\ntags: [home]\n---\n# Home\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.search = textinput.New()
+	m.loadKeybindings(config.Config{})
+
+	_, _ = m.handleBrowseKey("alt+b")
+	if !m.isOverlay(overlayTagBrowser) || len(m.tagBrowserTags) != 2 {
+		t.Fatalf("expected the tag browser, overlay %v tags %v", m.overlay, m.tagBrowserTags)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.isOverlay(overlaySearch) || m.search.Value() != "tag:work" {
+		t.Fatalf("expected a tag:work search, overlay %v query %q", m.overlay, m.search.Value())
+	}
+	if len(m.searchResults) != 1 || m.searchResults[0].path != work {
+		t.Fatalf("search results = %#v", m.searchResults)
+	}
+
+	m.closeSearchPopup()
+	m.openTagBrowserPopup()
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if m.overlay != overlayNone || m.tagFilter != "work" {
+		t.Fatalf("expected the tree filtered by #work, overlay %v filter %q", m.overlay, m.tagFilter)
+	}
+}
@@ -0,0 +1,170 @@
+// tag_filter.go implements the tree tag filter (Alt+T).
+//
+// Where a `tag:` search shows a flat result list, the tag filter keeps the
+// tree in place and limits it to notes whose frontmatter tags include the
+// chosen tag, plus the folders needed to reach them (see buildFilteredTree).
+// The picker lists every tag known to the search index. The filter stays
+// until Esc in browse mode (or x in the picker) clears it, and it combines
+// with the `/` name filter: with both active a note must match both. Like
+// the name filter it never touches the saved expansion state.
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hasTag reports whether tags contains tag, ignoring case.
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// treeFiltered reports whether a name or tag filter narrows the tree.
+func (m *Model) treeFiltered() bool {
+	return m.treeFilterQuery != "" || m.tagFilter != ""
+}
+
+// treeFilterLabel describes the active tree filters for the tree header.
+func (m *Model) treeFilterLabel() string {
+	parts := make([]string, 0, 2)
+	if m.treeFilterQuery != "" {
+		parts = append(parts, "Filter: "+m.treeFilterQuery)
+	}
+	if m.tagFilter != "" {
+		parts = append(parts, "Tag: #"+m.tagFilter)
+	}
+	return strings.Join(parts, "  ")
+}
+
+// tagFilterFooterSegment is the footer indicator for an active tag filter.
+func (m *Model) tagFilterFooterSegment() string {
+	if m.tagFilter == "" {
+		return ""
+	}
+	return "TAG #" + m.tagFilter
+}
+
+// openTagFilterPopup lists the tags of the indexed notes, with the active
+// filter tag selected.
+func (m *Model) openTagFilterPopup() {
+	if m.searchIndex == nil {
+		return
+	}
+	if err := m.searchIndex.ensureBuilt(); err != nil {
+		m.setStatusError("Error loading tags", err, "root", m.notesDir)
+		return
+	}
+	tags := m.searchIndex.knownTags()
+	if len(tags) == 0 {
+		m.status = "No tags found in notes"
+		return
+	}
+	m.tagFilterTags = tags
+	m.tagFilterCursor = max(0, slices.Index(tags, m.tagFilter))
+	m.openOverlay(overlayTagFilter)
+	m.status = "Tag filter: Enter to filter the tree, x to clear, Esc to close"
+}
+
+// handleTagFilterPopupKey routes key presses while the tag filter popup is
+// visible.
+func (m *Model) handleTagFilterPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	if idx, ok := m.popupQuickSelect(msg); ok {
+		if idx < len(m.tagFilterTags) {
+			m.tagFilterCursor = idx
+			m.applyTagFilter(m.tagFilterTags[idx])
+		}
+		return m, nil
+	}
+	if m.popupAction(msg) == popupActionClearTagFilter {
+		m.closeOverlay()
+		m.clearTagFilter()
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.tagFilterCursor, len(m.tagFilterTags))
+	if !handled {
+		return m, nil
+	}
+	if closePressed {
+		m.closeOverlay()
+		m.status = "Tag filter closed"
+		return m, nil
+	}
+	m.tagFilterCursor = next
+	if selectPressed && m.tagFilterCursor < len(m.tagFilterTags) {
+		m.applyTagFilter(m.tagFilterTags[m.tagFilterCursor])
+	}
+	return m, nil
+}
+
+// applyTagFilter closes the popup and limits the tree to notes tagged tag.
+func (m *Model) applyTagFilter(tag string) {
+	m.closeOverlay()
+	if !m.treeFiltered() {
+		m.treeFilterRestorePath = m.selectedPath()
+	}
+	m.tagFilter = tag
+	m.rebuildTreeKeep(m.selectedPath())
+	m.status = "Tag: #" + tag + " (Esc to clear)"
+}
+
+// clearTagFilter drops the tag filter and, when no name filter remains,
+// restores the row selected before filtering started.
+func (m *Model) clearTagFilter() {
+	if m.tagFilter == "" {
+		m.status = "No tag filter active"
+		return
+	}
+	m.tagFilter = ""
+	restore := m.selectedPath()
+	if m.treeFilterQuery == "" {
+		restore = m.treeFilterRestorePath
+		m.treeFilterRestorePath = ""
+	}
+	m.rebuildTreeKeep(restore)
+	m.status = "Tag filter cleared"
+}
+
+// renderTagFilterPopup draws the tag picker: number, tag, active marker.
+func (m *Model) renderTagFilterPopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	lines := []string{
+		titleStyle.Render("Filter Tree by Tag (" + m.primaryActionKey(actionTagFilter, "Alt+T") + ")"),
+		"",
+	}
+	limit := max(0, innerHeight-len(lines)-1)
+	start := 0
+	if limit > 0 {
+		start = max(0, m.tagFilterCursor-limit+1)
+	}
+	for i := start; i < min(start+limit, len(m.tagFilterTags)); i++ {
+		tag := m.tagFilterTags[i]
+		number := "  "
+		if i < 9 {
+			number = fmt.Sprintf("%d ", i+1)
+		}
+		line := number + "#" + tag
+		if strings.EqualFold(tag, m.tagFilter) {
+			line += "  (active)"
+		}
+		line = truncate(line, innerWidth)
+		if i == m.tagFilterCursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, mutedStyle.Render("Enter/1-9: filter  x: clear  Esc: close"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
@@ -0,0 +1,184 @@
+// tag_rename.go implements renaming a tag across every note (`r` in the tag
+// browser).
+//
+// The new name is typed into an input prefilled with the old one; the bulk
+// rewrite then waits for a y/n confirmation naming the number of notes it
+// touches. renameTagEverywhere rewrites only the tags key of each note's
+// frontmatter through frontmatterDoc, so key order, list style, and the other
+// tags' quoting survive. When a note already carries the new tag, the old one
+// is dropped instead of duplicated. The changed notes are re-indexed and git
+// status is refreshed so the edits show up for commit.
+package app
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startRenameTag opens the new-name input for tag.
+func (m *Model) startRenameTag(tag string) {
+	if m.rejectWhileLocked("renaming tags") {
+		return
+	}
+	m.closeOverlay()
+	m.renameTagFrom = tag
+	m.mode = modeRenameTag
+	m.showHelp = false
+	m.input.Reset()
+	m.input.Placeholder = "New tag name"
+	m.input.SetValue(tag)
+	m.input.CursorEnd()
+	m.input.Focus()
+	m.status = "Rename tag: Enter or Ctrl+S to continue, Esc to cancel"
+}
+
+// handleRenameTagKey processes keypresses in the new-name input.
+func (m *Model) handleRenameTagKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	return m.handleInputModeKey(msg, m.submitRenameTag, "Tag rename cancelled")
+}
+
+// submitRenameTag validates the new name and asks before rewriting notes.
+func (m *Model) submitRenameTag() (tea.Model, tea.Cmd) {
+	tags, err := parseTagInput(m.input.Value())
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	if len(tags) != 1 {
+		m.status = "Enter a single tag name"
+		return m, nil
+	}
+	from, to := m.renameTagFrom, tags[0]
+	if to == from {
+		m.mode = modeBrowse
+		m.status = "Tag unchanged"
+		return m, nil
+	}
+	paths := m.notesWithTag(from)
+	if len(paths) == 0 {
+		m.mode = modeBrowse
+		m.status = "No notes tagged #" + from
+		return m, nil
+	}
+	m.askConfirm(confirmPrompt{
+		question:     fmt.Sprintf("Rename #%s to #%s in %s? (y/n)", from, to, noteCountLabel(len(paths))),
+		yesHint:      "rename",
+		noHint:       "cancel",
+		cancelStatus: "Tag rename cancelled",
+		returnMode:   modeBrowse,
+		onConfirm: func() (tea.Model, tea.Cmd) {
+			return m.confirmRenameTag(from, to)
+		},
+	})
+	return m, nil
+}
+
+// confirmRenameTag runs the rename and reports the outcome.
+func (m *Model) confirmRenameTag(from, to string) (tea.Model, tea.Cmd) {
+	m.renameTagFrom = ""
+	if m.rejectWhileLocked("renaming tags") {
+		return m, nil
+	}
+	current := m.currentFile != "" && slices.Contains(m.notesWithTag(from), m.currentFile)
+	if m.tagFilter == from {
+		m.tagFilter = to
+	}
+	n, err := m.renameTagEverywhere(from, to)
+	if err != nil {
+		m.setStatusError(fmt.Sprintf("Error renaming tag after %s", noteCountLabel(n)), err, "from", from, "to", to)
+	} else {
+		m.status = fmt.Sprintf("Renamed #%s to #%s in %s", from, to, noteCountLabel(n))
+	}
+	if current && n > 0 {
+		return m, m.setCurrentFile(m.currentFile)
+	}
+	return m, nil
+}
+
+// noteCountLabel formats n as "1 note" or "n notes".
+func noteCountLabel(n int) string {
+	if n == 1 {
+		return "1 note"
+	}
+	return fmt.Sprintf("%d notes", n)
+}
+
+// notesWithTag returns the indexed notes carrying tag, sorted.
+func (m *Model) notesWithTag(tag string) []string {
+	if m.searchIndex == nil || m.searchIndex.ensureBuilt() != nil {
+		return nil
+	}
+	var paths []string
+	for path, doc := range m.searchIndex.docs {
+		if !doc.item.isDir && slices.Contains(doc.tagsLower, tag) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// renameTagEverywhere replaces oldTag with newTag in the frontmatter tags of
+// every indexed note carrying it and re-indexes the changed notes. It stops
+// at the first note it cannot rewrite and returns how many notes changed.
+func (m *Model) renameTagEverywhere(oldTag, newTag string) (int, error) {
+	oldTag = strings.ToLower(strings.TrimSpace(oldTag))
+	newTag = strings.ToLower(strings.TrimSpace(newTag))
+	var changed []string
+	var err error
+	for _, path := range m.notesWithTag(oldTag) {
+		var content []byte
+		if content, err = os.ReadFile(path); err != nil {
+			break
+		}
+		updated, ok := renameFrontmatterTag(string(content), oldTag, newTag)
+		if !ok {
+			continue
+		}
+		if err = os.WriteFile(path, []byte(updated), FilePermission); err != nil {
+			break
+		}
+		m.invalidateTreeMetadataPath(path)
+		delete(m.renderCache, path)
+		changed = append(changed, path)
+	}
+	if len(changed) > 0 {
+		m.applyMutationEffects(mutationEffects{
+			upsertPaths:     changed,
+			refreshGit:      true,
+			rebuildKeepPath: m.selectedPath(),
+		})
+	}
+	return len(changed), err
+}
+
+// renameFrontmatterTag swaps oldTag for newTag in content's tags list,
+// matching case-insensitively and keeping the other items as written. It
+// reports false when the note does not carry oldTag.
+func renameFrontmatterTag(content, oldTag, newTag string) (string, bool) {
+	doc := parseFrontmatterDoc(content)
+	items := doc.list(frontmatterKeyTags)
+	found := false
+	hasNew := slices.ContainsFunc(items, func(item string) bool { return strings.EqualFold(item, newTag) })
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if !strings.EqualFold(strings.TrimSpace(item), oldTag) {
+			out = append(out, item)
+			continue
+		}
+		if !found && !hasNew {
+			out = append(out, newTag)
+		}
+		found = true
+	}
+	if !found {
+		return content, false
+	}
+	doc.setList(frontmatterKeyTags, out)
+	return doc.String(), true
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRenameTagEverywhereRewritesOnlyTags(t *testing.T) {
+	root := t.TempDir()
+	bullets := filepath.Join(root, "a.md")
+	inline := filepath.Join(root, "b.md")
+	both := filepath.Join(root, "c.md")
+	other := filepath.Join(root, "d.md")
+	mustWriteFile(t, bullets, "---\ntitle: A\ntags:\n    - Work\n    - \"x y\"\nowner: me\n---\nbody #work\n")
+	mustWriteFile(t, inline, "---\ntags: [home, work]\n---\n")
+	mustWriteFile(t, both, "---\ntags: [work, job]\n---\n")
+	mustWriteFile(t, other, "---\ntags: [home]\n---\n")
+	m := newTestCRUDModel(root)
+
+	n, err := m.renameTagEverywhere("work", "job")
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3 notes changed, got %d, %v", n, err)
+	}
+	want := map[string]string{
+		bullets: "---\ntitle: A\ntags:\n    - job\n    - \"x y\"\nowner: me\n---\nbody #work\n",
+		inline:  "---\ntags: [home, job]\n---\n",
+		both:    "---\ntags: [job]\n---\n",
+		other:   "---\ntags: [home]\n---\n",
+	}
+	for path, content := range want {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Fatalf("%s:\n got %q\nwant %q", filepath.Base(path), data, content)
+		}
+	}
+	if got := m.notesWithTag("work"); len(got) != 0 {
+		t.Fatalf("expected the index to drop #work, still on %v", got)
+	}
+	if got := m.notesWithTag("job"); len(got) != 3 {
+		t.Fatalf("expected #job re-indexed on 3 notes, got %v", got)
+	}
+}
+
+func TestRenameTagFlowAsksBeforeRewriting(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "a.md")
+	mustWriteFile(t, note, "---\ntags: [work]\n---\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+
+	m.startRenameTag("work")
+	m.input.SetValue("job")
+	_, _ = m.handleRenameTagKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeConfirm || m.status != "Rename #work to #job in 1 note? (y/n)" {
+		t.Fatalf("expected a confirmation, got mode %v status %q", m.mode, m.status)
+	}
+	_, _ = m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if data, _ := os.ReadFile(note); string(data) != "---\ntags: [work]\n---\n" {
+		t.Fatalf("expected nothing written after declining, got %q", data)
+	}
+
+	m.startRenameTag("work")
+	m.input.SetValue("job")
+	_, _ = m.handleRenameTagKey(tea.KeyMsg{Type: tea.KeyEnter})
+	_, _ = m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m.status != "Renamed #work to #job in 1 note" || m.mode != modeBrowse {
+		t.Fatalf("unexpected status %q mode %v", m.status, m.mode)
+	}
+	if data, _ := os.ReadFile(note); string(data) != "---\ntags: [job]\n---\n" {
+		t.Fatalf("unexpected content %q", data)
+	}
+}
@@ -0,0 +1,156 @@
+// tags.go implements the tag-edit flow for the selected note.
+//
+// Pressing `#` in browse mode opens an input prefilled with the note's
+// current frontmatter tags. Saving rewrites only the tags key of the
+// frontmatter (see setFrontmatterTags), creating a frontmatter block when the
+// note has none, and then refreshes the search index, tree tag badges, and
+// render cache for the note.
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startEditTagsSelected switches to tag-edit mode for the selected note with
+// its current tags prefilled as a comma-separated list.
+func (m *Model) startEditTagsSelected() {
+	item := m.selectedItem()
+	if item == nil || item.isDir || !hasSuffixCaseInsensitive(item.path, ".md") {
+		m.status = "Select a markdown note to edit tags"
+		return
+	}
+	if !isWithinRoot(m.notesDir, item.path) {
+		m.status = "Cannot edit tags outside notes directory"
+		return
+	}
+	content, err := os.ReadFile(item.path)
+	if err != nil {
+		m.setStatusError("Error reading note", err, "path", item.path)
+		return
+	}
+	meta, _ := parseFrontmatterAndBody(string(content))
+
+	m.mode = modeEditTags
+	m.showHelp = false
+	m.actionPath = item.path
+	m.input.Reset()
+	m.input.Placeholder = "Tags (comma or space separated)"
+	m.input.SetValue(strings.Join(meta.Tags, ", "))
+	m.input.CursorEnd()
+	m.input.Focus()
+	m.status = "Tags: Enter or Ctrl+S to save, Esc to cancel"
+}
+
+// saveEditTags validates the tag input, rewrites the note's frontmatter, and
+// refreshes every view of the note's metadata.
+func (m *Model) saveEditTags() (tea.Model, tea.Cmd) {
+	path := m.actionPath
+	if !isWithinRoot(m.notesDir, path) {
+		m.status = "Invalid tag target"
+		m.mode = modeBrowse
+		return m, nil
+	}
+	tags, err := parseTagInput(m.input.Value())
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		m.setStatusError("Error reading note", err, "path", path)
+		m.mode = modeBrowse
+		return m, nil
+	}
+	meta, _ := parseFrontmatterAndBody(string(content))
+	added, removed := diffTags(meta.Tags, tags)
+	if len(added) == 0 && len(removed) == 0 {
+		m.mode = modeBrowse
+		m.status = "Tags unchanged"
+		return m, nil
+	}
+
+	updated := setFrontmatterTags(string(content), tags)
+	if err := os.WriteFile(path, []byte(updated), FilePermission); err != nil {
+		m.setStatusError("Error saving tags", err, "path", path)
+		return m, nil
+	}
+
+	m.mode = modeBrowse
+	m.invalidateTreeMetadataPath(path)
+	delete(m.renderCache, path)
+	effects := mutationEffects{
+		upsertPaths:     []string{path},
+		refreshGit:      true,
+		rebuildKeepPath: path,
+	}
+	if m.currentFile == path {
+		effects.setCurrentFile = path
+	}
+	cmd := m.applyMutationEffects(effects)
+	m.status = tagChangeSummary(added, removed)
+	return m, cmd
+}
+
+// parseTagInput splits the tag-edit input into a normalized tag list.
+//
+// Input containing commas is split on commas; otherwise it is split on
+// whitespace. Tags may not contain spaces or colons. Duplicates are removed
+// case-insensitively (tags are stored lowercase, matching the frontmatter
+// parser).
+func parseTagInput(value string) ([]string, error) {
+	var raw []string
+	if strings.Contains(value, ",") {
+		raw = strings.Split(value, ",")
+	} else {
+		raw = strings.Fields(value)
+	}
+	for _, tag := range raw {
+		tag = strings.TrimSpace(tag)
+		if strings.ContainsAny(tag, " \t") {
+			return nil, fmt.Errorf("Tag %q cannot contain spaces", tag)
+		}
+		if strings.Contains(tag, ":") {
+			return nil, fmt.Errorf("Tag %q cannot contain colons", tag)
+		}
+	}
+	return normalizeTagList(raw), nil
+}
+
+// diffTags reports which tags were added and removed going from before to after.
+func diffTags(before, after []string) (added, removed []string) {
+	had := make(map[string]bool, len(before))
+	for _, tag := range before {
+		had[tag] = true
+	}
+	has := make(map[string]bool, len(after))
+	for _, tag := range after {
+		has[tag] = true
+		if !had[tag] {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range before {
+		if !has[tag] {
+			removed = append(removed, tag)
+		}
+	}
+	return added, removed
+}
+
+// tagChangeSummary formats the status line shown after a tag edit, e.g.
+// "Tags: +projects, -inbox".
+func tagChangeSummary(added, removed []string) string {
+	parts := make([]string, 0, len(added)+len(removed))
+	for _, tag := range added {
+		parts = append(parts, "+"+tag)
+	}
+	for _, tag := range removed {
+		parts = append(parts, "-"+tag)
+	}
+	return "Tags: " + strings.Join(parts, ", ")
+}
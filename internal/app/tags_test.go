@@ -0,0 +1,124 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSetFrontmatterTagsKeepsBulletStyleAndOtherLines(t *testing.T) {
+	content := "---\n" +
+		"title: Plan\n" +
+		"tags:\n" +
+		"  - inbox\n" +
+		"  - go\n" +
+		"custom: keep me\n" +
+		"---\n" +
+		"# Body\n\nunchanged  \n"
+
+	got := setFrontmatterTags(content, []string{"go", "projects"})
+	want := "---\n" +
+		"title: Plan\n" +
+		"tags:\n" +
+		"  - go\n" +
+		"  - projects\n" +
+		"custom: keep me\n" +
+		"---\n" +
+		"# Body\n\nunchanged  \n"
+	if got != want {
+		t.Fatalf("unexpected rewrite.\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+func TestSetFrontmatterTagsCreatesBlockWhenMissing(t *testing.T) {
+	content := "# Body\nno frontmatter"
+	got := setFrontmatterTags(content, []string{"inbox"})
+	want := "---\ntags: [inbox]\n---\n# Body\nno frontmatter"
+	if got != want {
+		t.Fatalf("unexpected rewrite.\nwant: %q\ngot:  %q", want, got)
+	}
+	if unchanged := setFrontmatterTags(content, nil); unchanged != content {
+		t.Fatalf("expected content without tags to stay unchanged, got %q", unchanged)
+	}
+}
+
+func TestSetFrontmatterTagsPreservesCRLFAndRemovesKey(t *testing.T) {
+	content := "---\r\ntitle: Plan\r\ntags: [a, b]\r\n---\r\nbody\r\n"
+
+	got := setFrontmatterTags(content, []string{"c"})
+	if want := "---\r\ntitle: Plan\r\ntags: [c]\r\n---\r\nbody\r\n"; got != want {
+		t.Fatalf("unexpected CRLF rewrite.\nwant: %q\ngot:  %q", want, got)
+	}
+
+	removed := setFrontmatterTags(content, nil)
+	if want := "---\r\ntitle: Plan\r\n---\r\nbody\r\n"; removed != want {
+		t.Fatalf("unexpected tag removal.\nwant: %q\ngot:  %q", want, removed)
+	}
+}
+
+func TestParseTagInputValidatesAndDedupes(t *testing.T) {
+	tags, err := parseTagInput("Go, projects, go ,INBOX")
+	if err != nil {
+		t.Fatalf("parse tags: %v", err)
+	}
+	if want := []string{"go", "projects", "inbox"}; !slices.Equal(tags, want) {
+		t.Fatalf("expected %v, got %v", want, tags)
+	}
+
+	tags, err = parseTagInput("alpha beta  alpha")
+	if err != nil {
+		t.Fatalf("parse space separated tags: %v", err)
+	}
+	if want := []string{"alpha", "beta"}; !slices.Equal(tags, want) {
+		t.Fatalf("expected %v, got %v", want, tags)
+	}
+
+	if _, err := parseTagInput("two words, ok"); err == nil {
+		t.Fatal("expected error for tag containing a space")
+	}
+	if _, err := parseTagInput("tag:work"); err == nil {
+		t.Fatal("expected error for tag containing a colon")
+	}
+}
+
+func TestSaveEditTagsRewritesFrontmatterAndRefreshesIndex(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "todo.md")
+	mustWriteFile(t, path, "---\ntitle: Todo\ntags: [inbox]\n---\nbody text\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	reselectTreeItem(t, m, path)
+	m.startEditTagsSelected()
+	if m.mode != modeEditTags {
+		t.Fatalf("expected tag edit mode, got %v", m.mode)
+	}
+	if got := m.input.Value(); got != "inbox" {
+		t.Fatalf("expected prefilled tags %q, got %q", "inbox", got)
+	}
+
+	m.input.SetValue("projects")
+	m.saveEditTags()
+
+	if m.mode != modeBrowse {
+		t.Fatalf("expected browse mode after save, got %v", m.mode)
+	}
+	if m.status != "Tags: +projects, -inbox" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read note: %v", err)
+	}
+	if want := "---\ntitle: Todo\ntags: [projects]\n---\nbody text\n"; string(data) != want {
+		t.Fatalf("unexpected note content.\nwant: %q\ngot:  %q", want, string(data))
+	}
+	assertSearchHasQuery(t, m.searchIndex, "tag:projects", true)
+	assertSearchHasQuery(t, m.searchIndex, "tag:inbox", false)
+	for _, item := range m.items {
+		if item.path == path && !slices.Equal(item.tags, []string{"projects"}) {
+			t.Fatalf("expected tree tags to refresh, got %v", item.tags)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+// template_compose.go resolves template composition: partial includes and
+// template inheritance.
+//
+// A template may include a partial with {{> name}}. Partials are .md files in
+// a "partials" folder, looked up first in the workspace (.cli-notes/partials
+// under the notes directory) and then in the templates directory, so a
+// workspace can override a shared header or footer. The ".md" extension is
+// optional in the include. Partials may include other partials. A missing
+// partial renders as an empty string and is logged as a warning; it never
+// stops a note from being created.
+//
+// A template whose frontmatter has "extends: base" is rendered from the base
+// template in the templates directory instead of from its own body. The base
+// marks overridable regions with {{block name}}…{{end}}; each block the
+// child defines replaces the base's default, and the other blocks keep it.
+// The child's other frontmatter keys are merged into the base's block, child
+// values winning. Bases may extend further bases; the most derived block
+// wins. Blocks do not nest.
+//
+// Resolution runs when a template is chosen, before placeholder expansion
+// (see expandTemplateVariables). Include and extends cycles fail with an
+// error naming the cycle. `notes doctor` resolves every template to catch
+// these problems ahead of time (see lintTemplates).
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// templateExtendsKey is the frontmatter key naming a template's base.
+const templateExtendsKey = "extends"
+
+var (
+	templateIncludePattern = regexp.MustCompile(`\{\{>\s*([^{}\s]+)\s*\}\}`)
+	templateBlockPattern   = regexp.MustCompile(`\{\{block\s+([\w.-]+)\s*\}\}`)
+	// templateMarkerLinePattern matches a block marker alone on its line,
+	// which is removed together with the line break.
+	templateMarkerLinePattern = regexp.MustCompile(`(?m)^[ \t]*(?:\{\{block\s+[\w.-]+\s*\}\}|\{\{end\}\})[ \t]*\r?\n`)
+	templateMarkerPattern     = regexp.MustCompile(`\{\{block\s+[\w.-]+\s*\}\}|\{\{end\}\}`)
+)
+
+// templateBlockEnd closes a {{block name}} region.
+const templateBlockEnd = "{{end}}"
+
+// templateResolver expands partials and inheritance for template content.
+type templateResolver struct {
+	// baseDir holds the templates named by extends.
+	baseDir string
+	// partialDirs are searched in order for includes.
+	partialDirs []string
+}
+
+// newTemplateResolver returns the resolver for templatesDir, with the
+// workspace partials of notesDir taking precedence over the shared ones.
+// Either directory may be empty.
+func newTemplateResolver(templatesDir, notesDir string) templateResolver {
+	r := templateResolver{baseDir: templatesDir}
+	if notesDir != "" {
+		r.partialDirs = append(r.partialDirs, filepath.Join(notesDir, managedNotesDirName, TemplatePartialsDirName))
+	}
+	if templatesDir != "" {
+		r.partialDirs = append(r.partialDirs, filepath.Join(templatesDir, TemplatePartialsDirName))
+	}
+	return r
+}
+
+// resolve returns content with its inheritance and includes applied and the
+// block markers removed. name identifies the template in cycle errors.
+// Warnings (missing partials, unknown blocks) do not fail the resolution.
+func (r templateResolver) resolve(name, content string) (string, []string, error) {
+	var warnings []string
+	resolved, err := r.resolveExtends(content, []string{name}, &warnings)
+	if err != nil {
+		return "", warnings, err
+	}
+	resolved = templateMarkerLinePattern.ReplaceAllString(resolved, "")
+	return templateMarkerPattern.ReplaceAllString(resolved, ""), warnings, nil
+}
+
+// resolveExtends expands the includes of content and, when it extends a
+// base, renders the base with content's blocks. Block markers are kept so a
+// more derived template can still override them. chain lists the templates
+// being resolved, outermost first.
+func (r templateResolver) resolveExtends(content string, chain []string, warnings *[]string) (string, error) {
+	content, err := r.expandPartials(content, nil, warnings)
+	if err != nil {
+		return "", err
+	}
+	doc := parseFrontmatterDoc(content)
+	base := doc.scalar(templateExtendsKey)
+	if base == "" {
+		if _, err := templateBlocks(content); err != nil {
+			return "", inTemplate(chain, err)
+		}
+		return content, nil
+	}
+
+	path, baseName, err := r.templatePath(base)
+	if err != nil {
+		return "", inTemplate(chain, err)
+	}
+	if slices.Contains(chain, baseName) {
+		return "", fmt.Errorf("template cycle: %s", strings.Join(append(chain, baseName), " -> "))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read base template %s: %w", baseName, err)
+	}
+	parent, err := r.resolveExtends(string(data), append(chain, baseName), warnings)
+	if err != nil {
+		return "", err
+	}
+
+	blocks, err := templateBlocks(content)
+	if err != nil {
+		return "", inTemplate(chain, err)
+	}
+	overrides := make(map[string]string, len(blocks))
+	for _, block := range blocks {
+		overrides[block.name] = content[block.bodyStart:block.bodyEnd]
+	}
+	merged, used := overrideTemplateBlocks(parent, overrides)
+	for _, block := range blocks {
+		if !used[block.name] {
+			*warnings = append(*warnings, fmt.Sprintf("block %q is not defined by %s", block.name, baseName))
+		}
+	}
+
+	out := parseFrontmatterDoc(merged)
+	for _, entry := range doc.entries {
+		if entry.key != "" && !strings.EqualFold(entry.key, templateExtendsKey) {
+			out.put(entry.key, entry.lines)
+		}
+	}
+	return out.String(), nil
+}
+
+// inTemplate prefixes err with the base template it came from; errors in
+// the template being resolved itself are returned as is.
+func inTemplate(chain []string, err error) error {
+	if len(chain) == 1 {
+		return err
+	}
+	return fmt.Errorf("%s: %w", chain[len(chain)-1], err)
+}
+
+// templatePath finds the base template name in baseDir, with or without a
+// ".md" extension, and returns its path and file name.
+func (r templateResolver) templatePath(name string) (string, string, error) {
+	if r.baseDir == "" {
+		return "", "", fmt.Errorf("base template %q: no templates directory", name)
+	}
+	for _, candidate := range []string{name, name + ".md"} {
+		path := filepath.Join(r.baseDir, filepath.FromSlash(candidate))
+		if !isWithinRoot(r.baseDir, path) || path == filepath.Clean(r.baseDir) {
+			break
+		}
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, filepath.ToSlash(candidate), nil
+		}
+	}
+	return "", "", fmt.Errorf("base template %q not found", name)
+}
+
+// expandPartials replaces every {{> name}} in content with the partial's
+// expanded content. stack lists the partials being expanded.
+func (r templateResolver) expandPartials(content string, stack []string, warnings *[]string) (string, error) {
+	var out strings.Builder
+	last := 0
+	for _, match := range templateIncludePattern.FindAllStringSubmatchIndex(content, -1) {
+		out.WriteString(content[last:match[0]])
+		last = match[1]
+		name := strings.TrimSuffix(content[match[2]:match[3]], ".md")
+		if slices.Contains(stack, name) {
+			return "", fmt.Errorf("partial cycle: %s", strings.Join(append(stack, name), " -> "))
+		}
+		partial, ok := r.readPartial(name)
+		if !ok {
+			*warnings = append(*warnings, fmt.Sprintf("partial %q not found", name))
+			continue
+		}
+		expanded, err := r.expandPartials(partial, append(stack, name), warnings)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(expanded)
+	}
+	out.WriteString(content[last:])
+	return out.String(), nil
+}
+
+// readPartial returns the content of the partial name from the first
+// partials folder that has it, without its final line break.
+func (r templateResolver) readPartial(name string) (string, bool) {
+	for _, dir := range r.partialDirs {
+		path := filepath.Join(dir, filepath.FromSlash(name+".md"))
+		if !isWithinRoot(dir, path) {
+			return "", false
+		}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			content := strings.TrimSuffix(string(data), "\n")
+			return strings.TrimSuffix(content, "\r"), true
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			appLog.Warn("read template partial", "path", path, "error", err)
+		}
+	}
+	return "", false
+}
+
+// templateBlock is a {{block name}}…{{end}} region; bodyStart and bodyEnd
+// delimit the text between the markers.
+type templateBlock struct {
+	name      string
+	bodyStart int
+	bodyEnd   int
+}
+
+// templateBlocks lists the blocks of content in order.
+func templateBlocks(content string) ([]templateBlock, error) {
+	var blocks []templateBlock
+	seen := map[string]bool{}
+	pos := 0
+	for {
+		loc := templateBlockPattern.FindStringSubmatchIndex(content[pos:])
+		if loc == nil {
+			if i := strings.Index(content[pos:], templateBlockEnd); i >= 0 {
+				return nil, fmt.Errorf("%s without {{block}}", templateBlockEnd)
+			}
+			return blocks, nil
+		}
+		name := content[pos+loc[2] : pos+loc[3]]
+		bodyStart := pos + loc[1]
+		end := strings.Index(content[bodyStart:], templateBlockEnd)
+		if end < 0 {
+			return nil, fmt.Errorf("{{block %s}} is never closed", name)
+		}
+		bodyEnd := bodyStart + end
+		if templateBlockPattern.MatchString(content[bodyStart:bodyEnd]) {
+			return nil, fmt.Errorf("{{block %s}} contains another block; blocks cannot nest", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("block %q is defined twice", name)
+		}
+		seen[name] = true
+		blocks = append(blocks, templateBlock{name: name, bodyStart: bodyStart, bodyEnd: bodyEnd})
+		pos = bodyEnd + len(templateBlockEnd)
+	}
+}
+
+// overrideTemplateBlocks replaces the bodies of content's blocks that have
+// an override, keeping the markers, and reports which overrides were used.
+// content's blocks must already be valid (see templateBlocks).
+func overrideTemplateBlocks(content string, overrides map[string]string) (string, map[string]bool) {
+	blocks, _ := templateBlocks(content)
+	used := map[string]bool{}
+	var out strings.Builder
+	last := 0
+	for _, block := range blocks {
+		body, ok := overrides[block.name]
+		if !ok {
+			continue
+		}
+		used[block.name] = true
+		out.WriteString(content[last:block.bodyStart])
+		out.WriteString(body)
+		last = block.bodyEnd
+	}
+	out.WriteString(content[last:])
+	return out.String(), used
+}
+
+// resolveNoteTemplate returns the content of t with its composition
+// resolved. Warnings are logged; an error is shown in the status bar and
+// reported as false.
+func (m *Model) resolveNoteTemplate(t noteTemplate) (string, bool) {
+	content, warnings, err := newTemplateResolver(m.templatesDir, m.notesDir).resolve(t.name, t.content)
+	for _, warning := range warnings {
+		appLog.Warn("resolve template", "template", t.name, "warning", warning)
+	}
+	if err != nil {
+		m.setStatusError("Template error: "+err.Error(), err, "template", t.name)
+		return "", false
+	}
+	return content, true
+}
+
+// lintTemplates resolves every template in templatesDir for `notes
+// doctor` and returns one line per error or warning, sorted by template.
+func lintTemplates(templatesDir, notesDir string) ([]string, int, error) {
+	entries, err := os.ReadDir(templatesDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	resolver := newTemplateResolver(templatesDir, notesDir)
+	var problems []string
+	count := 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		count++
+		data, err := os.ReadFile(filepath.Join(templatesDir, entry.Name()))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", entry.Name(), err))
+			continue
+		}
+		_, warnings, err := resolver.resolve(entry.Name(), string(data))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", entry.Name(), err))
+		}
+		for _, warning := range warnings {
+			problems = append(problems, fmt.Sprintf("%s: %s", entry.Name(), warning))
+		}
+	}
+	sort.Strings(problems)
+	return problems, count, nil
+}
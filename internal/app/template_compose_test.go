@@ -0,0 +1,155 @@
+package app
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTemplateResolverExpandsNestedPartials(t *testing.T) {
+	templates := t.TempDir()
+	notes := t.TempDir()
+	mustWriteFile(t, filepath.Join(templates, "partials", "header.md"), "---\ntags: [shared]\n---\n{{> sub/title}}\n")
+	mustWriteFile(t, filepath.Join(templates, "partials", "sub", "title.md"), "# {{title}}")
+	mustWriteFile(t, filepath.Join(templates, "partials", "footer.md"), "global footer\n")
+	mustWriteFile(t, filepath.Join(notes, ".cli-notes", "partials", "footer.md"), "workspace footer\n")
+
+	r := newTemplateResolver(templates, notes)
+	got, warnings, err := r.resolve("note.md", "{{> header}}\nbody\n{{> footer.md}}\n{{> missing}}\n")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	want := "---\ntags: [shared]\n---\n# {{title}}\nbody\nworkspace footer\n\n"
+	if got != want {
+		t.Fatalf("unexpected content.\nwant: %q\ngot:  %q", want, got)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `partial "missing" not found`) {
+		t.Fatalf("expected a missing-partial warning, got %v", warnings)
+	}
+}
+
+func TestTemplateResolverExtendsOverridesBlocks(t *testing.T) {
+	templates := t.TempDir()
+	mustWriteFile(t, filepath.Join(templates, "base.md"), "---\ntype: note\ntags: [base]\n---\n"+
+		"# {{title}}\n"+
+		"{{block body}}\nbase body\n{{end}}\n"+
+		"{{block footer}}\nbase footer\n{{end}}\n")
+	mustWriteFile(t, filepath.Join(templates, "meeting.md"), "---\nextends: base\ntags: [meeting]\n---\n"+
+		"{{block body}}\n## Agenda\n{{end}}\n"+
+		"{{block footer}}\nmeeting footer\n{{end}}\n"+
+		"ignored outside blocks\n")
+	child := "---\nextends: meeting.md\nattendees: 3\n---\n" +
+		"{{block body}}\n## Standup\n{{end}}\n" +
+		"{{block extra}}\nnot in the base\n{{end}}\n"
+
+	r := newTemplateResolver(templates, "")
+	got, warnings, err := r.resolve("standup.md", child)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	want := "---\ntype: note\ntags: [meeting]\nattendees: 3\n---\n" +
+		"# {{title}}\n" +
+		"## Standup\n" +
+		"meeting footer\n"
+	if got != want {
+		t.Fatalf("unexpected content.\nwant: %q\ngot:  %q", want, got)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `block "extra"`) {
+		t.Fatalf("expected an unknown-block warning, got %v", warnings)
+	}
+
+	got, _, err = r.resolve("base.md", "---\nextends: base\n---\n")
+	if err == nil || !strings.Contains(err.Error(), "template cycle: base.md -> base.md") {
+		t.Fatalf("expected a self-extends cycle, got %q, %v", got, err)
+	}
+}
+
+func TestTemplateResolverReportsCycles(t *testing.T) {
+	templates := t.TempDir()
+	mustWriteFile(t, filepath.Join(templates, "a.md"), "---\nextends: b\n---\n")
+	mustWriteFile(t, filepath.Join(templates, "b.md"), "---\nextends: a\n---\n")
+	mustWriteFile(t, filepath.Join(templates, "partials", "x.md"), "{{> y}}")
+	mustWriteFile(t, filepath.Join(templates, "partials", "y.md"), "{{> x}}")
+
+	r := newTemplateResolver(templates, "")
+	if _, _, err := r.resolve("a.md", "---\nextends: b\n---\n"); err == nil || err.Error() != "template cycle: a.md -> b.md -> a.md" {
+		t.Fatalf("expected an extends cycle, got %v", err)
+	}
+	if _, _, err := r.resolve("p.md", "{{> x}}"); err == nil || err.Error() != "partial cycle: x -> y -> x" {
+		t.Fatalf("expected a partial cycle, got %v", err)
+	}
+	for content, want := range map[string]string{
+		"---\nextends: nope\n---\n":              `base template "nope" not found`,
+		"{{block a}}\nunclosed\n":                "{{block a}} is never closed",
+		"{{block a}}{{block b}}{{end}}{{end}}\n": "blocks cannot nest",
+	} {
+		if _, _, err := r.resolve("t.md", content); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("resolve(%q) error = %v, want %q", content, err, want)
+		}
+	}
+}
+
+func TestTemplatePickerResolvesCompositionOnSelect(t *testing.T) {
+	root := t.TempDir()
+	templates := t.TempDir()
+	mustWriteFile(t, filepath.Join(templates, "partials", "sig.md"), "-- {{workspace}}\n")
+	mustWriteFile(t, filepath.Join(templates, "loop.md"), "---\nextends: loop\n---\n")
+	mustWriteFile(t, filepath.Join(templates, "signed.md"), "# {{title}}\n{{> sig}}\n")
+
+	m := newTestCRUDModel(root)
+	m.templatesDir = templates
+	m.activeWorkspace = "work"
+	m.templates = m.loadTemplates()
+	if len(m.templates) != 3 {
+		t.Fatalf("expected the partials folder to be skipped, got %d templates", len(m.templates))
+	}
+	m.mode = modeTemplatePicker
+	m.templateCursor = 1
+	_, _ = m.handleTemplatePickerKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeTemplatePicker || m.status != "Template error: template cycle: loop.md -> loop.md" {
+		t.Fatalf("expected the cycle to be refused, mode %v status %q", m.mode, m.status)
+	}
+
+	m.templateCursor = 2
+	_, _ = m.handleTemplatePickerKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeNewNote || m.selectedTemplate == nil {
+		t.Fatalf("expected the name input, mode %v status %q", m.mode, m.status)
+	}
+	m.newParent = root
+	m.input.SetValue("hello")
+	_, _ = settlePendingOp(m.saveNewNote())
+	data := readFileString(t, filepath.Join(root, "hello.md"))
+	if want := "# hello\n-- work\n"; data != want {
+		t.Fatalf("unexpected note.\nwant: %q\ngot:  %q", want, data)
+	}
+}
+
+func TestDoctorReportsUnresolvedTemplates(t *testing.T) {
+	root := t.TempDir()
+	templates := t.TempDir()
+	mustWriteFile(t, filepath.Join(templates, "ok.md"), "# ok\n")
+	mustWriteFile(t, filepath.Join(templates, "broken.md"), "---\nextends: gone\n---\n{{> nope}}\n")
+
+	var out bytes.Buffer
+	problems, err := doctorReport(&out, root, templates)
+	if err != nil {
+		t.Fatalf("doctor: %v", err)
+	}
+	if problems != 2 {
+		t.Fatalf("expected 2 problems, got %d:\n%s", problems, out.String())
+	}
+	for _, want := range []string{`broken.md: base template "gone" not found`, `broken.md: partial "nope" not found`} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in report:\n%s", want, out.String())
+		}
+	}
+
+	mustWriteFile(t, filepath.Join(templates, "broken.md"), "# fixed\n")
+	out.Reset()
+	if problems, _ := doctorReport(&out, root, templates); problems != 0 || !strings.Contains(out.String(), "OK: 2 template(s) resolve") {
+		t.Fatalf("expected templates to pass, got %d:\n%s", problems, out.String())
+	}
+}
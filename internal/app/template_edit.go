@@ -0,0 +1,94 @@
+// template_edit.go implements editing a template from the template picker.
+//
+// Pressing e on a template opens its file in the editor (modeEditTemplate).
+// The mode uses the note editor widget but none of the note machinery: no
+// drafts, no conflict checks, no frontmatter stamping, and the open note is
+// left alone. Ctrl+S writes the template back to the templates directory and
+// returns to the picker with the templates reloaded and the edited one
+// highlighted; Esc returns without saving.
+package app
+
+import (
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startEditTemplate opens the template under the picker cursor in the
+// editor. The file is read again so the editor shows what is on disk.
+func (m *Model) startEditTemplate() {
+	if len(m.templates) == 0 {
+		return
+	}
+	chosen := m.templates[m.templateCursor]
+	if chosen.path == "" {
+		m.status = "The default entry has no template file"
+		return
+	}
+	data, err := os.ReadFile(chosen.path)
+	if err != nil {
+		m.setStatusError("Error reading template", err, "path", chosen.path)
+		return
+	}
+	m.templateEditPath = chosen.path
+	m.mode = modeEditTemplate
+	m.clearEditorSelection()
+	m.resetEditHistory()
+	m.editorNoWrap = false
+	m.editorHScroll = 0
+	m.setEditorValue(string(data))
+	m.editor.Focus()
+	m.status = "Editing template " + chosen.name + ": Ctrl+S save, Esc back"
+}
+
+// handleEditTemplateKey routes key presses while a template is in the editor.
+func (m *Model) handleEditTemplateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	switch msg.String() {
+	case "ctrl+s":
+		m.saveTemplateEdit()
+		return m, nil
+	case "esc":
+		m.returnToTemplatePicker("Template edit cancelled")
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+// saveTemplateEdit writes the editor buffer to the template file and returns
+// to the picker. A failed write keeps the editor open.
+func (m *Model) saveTemplateEdit() {
+	path := m.templateEditPath
+	if err := os.WriteFile(path, []byte(normalizeNoteContent(m.editor.Value())), FilePermission); err != nil {
+		m.setStatusError("Error saving template", err, "path", path)
+		return
+	}
+	m.returnToTemplatePicker("Saved template: " + filepath.Base(path))
+}
+
+// returnToTemplatePicker leaves the template editor for the picker, with the
+// templates reloaded and the cursor on the template that was edited.
+func (m *Model) returnToTemplatePicker(status string) {
+	path := m.templateEditPath
+	m.templateEditPath = ""
+	m.editor.Blur()
+	m.resetEditHistory()
+	m.templates = m.loadTemplates()
+	m.templateCursor = 0
+	for i, t := range m.templates {
+		if t.path == path {
+			m.templateCursor = i
+			break
+		}
+	}
+	m.mode = modeTemplatePicker
+	if len(m.templates) == 0 {
+		m.mode = modeBrowse
+	}
+	m.status = status
+}
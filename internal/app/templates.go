@@ -7,11 +7,22 @@
 // templates are found, the flow falls through directly to the name input
 // with the built-in default template.
 //
+// A folder can carry its own default template in a .cli-notes-template.md
+// file. When the new note's folder (or its nearest ancestor within the notes
+// directory) has one, the picker is skipped and that file seeds the note;
+// pressing Tab in the name input still opens the picker, and an explicit
+// choice there overrides the folder default.
+//
 // Templates are plain files (any format, though typically .md) stored in
 // the templates directory. Each file's content is read at picker-open time
-// and used verbatim as the initial content of the new note. A synthetic
-// "Default (no template)" entry is always prepended so the user can opt out
-// of templating.
+// and becomes the initial content of the new note after placeholder
+// expansion (see expandTemplateVariables); a {{cursor}} marker in it picks
+// where the editor cursor starts (extractTemplateCursor), and a template
+// holding only frontmatter is merged onto the default note (see
+// mergeTemplateDefaults). Templates can include partials and extend a base
+// template; that composition is resolved when the template is chosen (see
+// template_compose.go). A synthetic "Default (no template)" entry is
+// always prepended so the user can opt out of templating.
 package app
 
 import (
@@ -19,6 +30,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -35,6 +47,114 @@ type noteTemplate struct {
 	content string // raw file content to seed the new note with
 }
 
+// Default layouts for the {{date}} and {{time}} placeholders, used when
+// template_date_format / template_time_format are unset.
+const (
+	defaultTemplateDateFormat = "2006-01-02"
+	defaultTemplateTimeFormat = "15:04"
+)
+
+// templateCursorMarker marks where the editor cursor lands when a note
+// created from a template is first edited. It is removed from the note.
+const templateCursorMarker = "{{cursor}}"
+
+// templateVars holds the values substituted into new-note content.
+type templateVars struct {
+	title      string // note path or name; only the base name without ".md" is used
+	workspace  string
+	dateFormat string
+	timeFormat string
+}
+
+// newNoteTemplateVars returns the placeholder values for a note named name.
+func (m *Model) newNoteTemplateVars(name string) templateVars {
+	return templateVars{
+		title:      name,
+		workspace:  m.activeWorkspace,
+		dateFormat: m.templateDateFormat,
+		timeFormat: m.templateTimeFormat,
+	}
+}
+
+// expandTemplateVariables fills the placeholders of new-note content:
+//
+//	{{title}}     the note's file name without directories or ".md"
+//	{{date}}      template_date_format (default 2006-01-02)
+//	{{time}}      template_time_format (default 15:04)
+//	{{datetime}}  {{date}} {{time}}
+//	{{workspace}} the active workspace name
+//
+// Every occurrence is replaced. Unknown placeholders, including
+// {{cursor}}, are left untouched.
+func expandTemplateVariables(content string, vars templateVars) string {
+	now := appNow()
+	title := filepath.Base(vars.title)
+	if strings.HasSuffix(strings.ToLower(title), ".md") {
+		title = title[:len(title)-len(".md")]
+	}
+	dateFormat := vars.dateFormat
+	if dateFormat == "" {
+		dateFormat = defaultTemplateDateFormat
+	}
+	timeFormat := vars.timeFormat
+	if timeFormat == "" {
+		timeFormat = defaultTemplateTimeFormat
+	}
+	return strings.NewReplacer(
+		"{{title}}", title,
+		"{{date}}", now.Format(dateFormat),
+		"{{time}}", now.Format(timeFormat),
+		"{{datetime}}", now.Format(dateFormat+" "+timeFormat),
+		"{{workspace}}", vars.workspace,
+	).Replace(content)
+}
+
+// extractTemplateCursor removes every {{cursor}} marker from content and
+// returns the rune offset of the first one, or -1 when there is none.
+func extractTemplateCursor(content string) (string, int) {
+	i := strings.Index(content, templateCursorMarker)
+	if i < 0 {
+		return content, -1
+	}
+	offset := utf8.RuneCountInString(content[:i])
+	return strings.ReplaceAll(content, templateCursorMarker, ""), offset
+}
+
+// mergeTemplateDefaults gives a template that carries only frontmatter the
+// default note body, so its keys (tags, type, ...) merge into the note a
+// plain "n" would have created instead of leaving it empty.
+func mergeTemplateDefaults(content, defaultContent string) string {
+	_, body := parseFrontmatterAndBody(content)
+	if body == content || strings.TrimSpace(body) != "" {
+		return content
+	}
+	return strings.TrimRight(content, "\r\n") + "\n" + defaultContent
+}
+
+// folderDefaultTemplate returns the nearest .cli-notes-template.md found by
+// walking up from dir to the notes root. It reports false when dir is
+// outside the notes directory or no folder on the way has a template.
+func (m *Model) folderDefaultTemplate(dir string) (*noteTemplate, bool) {
+	dir = filepath.Clean(dir)
+	if !isWithinRoot(m.notesDir, dir) {
+		return nil, false
+	}
+	for {
+		path := filepath.Join(dir, FolderTemplateFileName)
+		content, err := os.ReadFile(path)
+		if err == nil {
+			return &noteTemplate{name: m.displayRelative(path), path: path, content: string(content)}, true
+		}
+		if !os.IsNotExist(err) {
+			appLog.Warn("read folder template", "path", path, "error", err)
+		}
+		if dir == filepath.Clean(m.notesDir) {
+			return nil, false
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
 // loadTemplates reads template files from the configured templates directory
 // and returns a slice of noteTemplate entries for the picker popup.
 //
@@ -91,10 +211,27 @@ func (m *Model) loadTemplates() []noteTemplate {
 	return templates
 }
 
+// openTemplatePickerOverFolderDefault shows the template picker from the
+// new-note name input so the user can replace the folder default template.
+func (m *Model) openTemplatePickerOverFolderDefault() {
+	if _, ok := m.folderDefaultTemplate(m.newParent); !ok {
+		return
+	}
+	m.templates = m.loadTemplates()
+	if len(m.templates) == 0 {
+		m.status = "No templates available"
+		return
+	}
+	m.templateCursor = 0
+	m.mode = modeTemplatePicker
+	m.status = "Choose a template for the new note"
+}
+
 // handleTemplatePickerKey processes key events while the template picker popup
 // is active. Navigation uses j/k or arrow keys. Enter/Ctrl+S confirms the
-// selection and transitions to the note-name input (modeNewNote). Esc cancels
-// the entire new-note flow and returns to browse mode.
+// selection and transitions to the note-name input (modeNewNote), and e opens
+// the highlighted template in the editor (template_edit.go). Esc cancels the
+// entire new-note flow and returns to browse mode.
 //
 // When the user selects the default entry (path == ""), selectedTemplate is set
 // to nil so saveNewNote uses the auto-generated heading template. Otherwise,
@@ -105,37 +242,40 @@ func (m *Model) handleTemplatePickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	switch msg.String() {
-	case "up", "k":
-		if len(m.templates) > 0 {
-			m.templateCursor = clamp(m.templateCursor-1, 0, len(m.templates)-1)
-		}
-		return m, nil
-	case "down", "j":
-		if len(m.templates) > 0 {
-			m.templateCursor = clamp(m.templateCursor+1, 0, len(m.templates)-1)
-		}
+	switch action := m.popupAction(msg); action {
+	case actionPopupUp, actionPopupDown, actionPopupPageUp, actionPopupPageDown, actionPopupTop, actionPopupBottom:
+		m.templateCursor, _, _, _ = popupListNav(action, m.templateCursor, len(m.templates))
 		return m, nil
-	case "enter", "ctrl+s":
+	case actionPopupSelect:
 		if len(m.templates) == 0 {
 			m.mode = modeBrowse
 			m.status = "No templates available"
 			return m, nil
 		}
 		chosen := m.templates[m.templateCursor]
+		m.templateChosen = true
 		if chosen.path == "" {
 			m.selectedTemplate = nil
 			m.status = "Using default note template"
 		} else {
-			m.selectedTemplate = &noteTemplate{name: chosen.name, path: chosen.path, content: chosen.content}
+			content, ok := m.resolveNoteTemplate(chosen)
+			if !ok {
+				m.templateChosen = false
+				return m, nil
+			}
+			m.selectedTemplate = &noteTemplate{name: chosen.name, path: chosen.path, content: content}
 			m.status = "Using template: " + chosen.name
 		}
 		m.configureInputForMode(modeNewNote, "Note name (without .md extension)")
 		return m, nil
-	case "esc":
+	case popupActionTemplateEdit:
+		m.startEditTemplate()
+		return m, nil
+	case actionPopupClose:
 		m.mode = modeBrowse
 		m.templates = nil
 		m.selectedTemplate = nil
+		m.templateChosen = false
 		m.status = "New note cancelled"
 		return m, nil
 	default:
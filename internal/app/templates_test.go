@@ -0,0 +1,216 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandTemplateVariables(t *testing.T) {
+	withFixedNow(t, time.Date(2026, 3, 4, 9, 5, 0, 0, time.Local))
+
+	got := expandTemplateVariables("# {{title}}\n{{date}} {{time}} | {{datetime}} {{unknown}} {{ title }}\n", templateVars{title: "meetings/Standup.MD"})
+	want := "# Standup\n2026-03-04 09:05 | 2026-03-04 09:05 {{unknown}} {{ title }}\n"
+	if got != want {
+		t.Fatalf("unexpected expansion.\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+func TestExpandTemplateVariablesReplacesEveryOccurrence(t *testing.T) {
+	withFixedNow(t, time.Date(2026, 3, 4, 9, 5, 0, 0, time.Local))
+
+	got := expandTemplateVariables(
+		"{{title}} / {{title}} in {{workspace}}\n{{date}} {{date}} {{time}}\n{{datetime}}\n{{workspace}} {{cursor}}",
+		templateVars{title: "Plan.md", workspace: "work", dateFormat: "Jan 2, 2006", timeFormat: "3:04PM"},
+	)
+	want := "Plan / Plan in work\nMar 4, 2026 Mar 4, 2026 9:05AM\nMar 4, 2026 9:05AM\nwork {{cursor}}"
+	if got != want {
+		t.Fatalf("unexpected expansion.\nwant: %q\ngot:  %q", want, got)
+	}
+}
+
+func TestExtractTemplateCursor(t *testing.T) {
+	got, offset := extractTemplateCursor("# Tëst\n\n{{cursor}}\nend {{cursor}}")
+	if got != "# Tëst\n\n\nend " || offset != 8 {
+		t.Fatalf("got %q at %d", got, offset)
+	}
+	if got, offset := extractTemplateCursor("plain"); got != "plain" || offset != -1 {
+		t.Fatalf("got %q at %d, want no marker", got, offset)
+	}
+}
+
+func TestSaveNewNotePlacesEditorCursorAtMarker(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.newParent = root
+	m.input.SetValue("Standup")
+	m.selectedTemplate = &noteTemplate{content: "# {{title}}\n\n## Notes\n{{cursor}}\n"}
+
+	settlePendingOp(m.saveNewNote())
+
+	path := filepath.Join(root, "Standup.md")
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read created note: %v", err)
+	}
+	if want := "# Standup\n\n## Notes\n"; string(got) != want {
+		t.Fatalf("unexpected content.\nwant: %q\ngot:  %q", want, string(got))
+	}
+	if pos := m.notePositions[path].EditorCursor; pos != len("# Standup\n\n## Notes\n") {
+		t.Fatalf("editor cursor = %d", pos)
+	}
+
+	m.editor.SetValue(string(got))
+	m.restoreEditorCursor(path)
+	if line := m.editor.Line(); line != 3 {
+		t.Fatalf("cursor line = %d, want 3", line)
+	}
+}
+
+func TestSaveNewNoteMergesTemplateFrontmatterWithDefaults(t *testing.T) {
+	withFixedNow(t, time.Date(2026, 3, 4, 9, 5, 0, 0, time.UTC))
+	root := t.TempDir()
+	mustWriteFile(t, autoTagPath(root), `{"rules":[{"folder":".","tags":["inbox"]}]}`)
+	m := newTestCRUDModel(root)
+	m.newParent = root
+	m.frontmatterTimestamps = true
+	m.input.SetValue("Idea")
+	m.selectedTemplate = &noteTemplate{content: "---\ntags: [idea, draft]\n---\n"}
+
+	settlePendingOp(m.saveNewNote())
+
+	got, err := os.ReadFile(filepath.Join(root, "Idea.md"))
+	if err != nil {
+		t.Fatalf("read created note: %v", err)
+	}
+	meta, body := parseFrontmatterAndBody(string(got))
+	if want := []string{"idea", "draft", "inbox"}; strings.Join(meta.Tags, ",") != strings.Join(want, ",") {
+		t.Fatalf("tags = %v, want %v\n%s", meta.Tags, want, got)
+	}
+	if !strings.Contains(string(got), "created:") {
+		t.Fatalf("expected created timestamp:\n%s", got)
+	}
+	if !strings.HasPrefix(strings.TrimLeft(body, "\n"), "# Idea\n") {
+		t.Fatalf("expected default body, got %q", body)
+	}
+}
+
+func TestSaveNewNoteExpandsTemplateVariables(t *testing.T) {
+	withFixedNow(t, time.Date(2026, 3, 4, 9, 5, 0, 0, time.Local))
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.newParent = root
+	m.input.SetValue("Weekly sync")
+	m.selectedTemplate = &noteTemplate{content: "# {{title}}\n\nDate: {{date}}\n"}
+
+	settlePendingOp(m.saveNewNote())
+
+	got, err := os.ReadFile(filepath.Join(root, "Weekly sync.md"))
+	if err != nil {
+		t.Fatalf("read created note: %v", err)
+	}
+	if want := "# Weekly sync\n\nDate: 2026-03-04\n"; string(got) != want {
+		t.Fatalf("unexpected content.\nwant: %q\ngot:  %q", want, string(got))
+	}
+}
+
+func TestFolderDefaultTemplateUsesNearestAncestor(t *testing.T) {
+	root := t.TempDir()
+	meetings := filepath.Join(root, "meetings")
+	mustWriteFile(t, filepath.Join(meetings, FolderTemplateFileName), "# Meeting {{title}}\n")
+	mustWriteFile(t, filepath.Join(meetings, "weekly", "standup.md"), "")
+	m := newTestCRUDModel(root)
+
+	tmpl, ok := m.folderDefaultTemplate(filepath.Join(meetings, "weekly"))
+	if !ok || tmpl.path != filepath.Join(meetings, FolderTemplateFileName) {
+		t.Fatalf("expected meetings template, got %#v ok=%v", tmpl, ok)
+	}
+	if _, ok := m.folderDefaultTemplate(root); ok {
+		t.Fatalf("expected no template at the root")
+	}
+	if _, ok := m.folderDefaultTemplate(filepath.Dir(root)); ok {
+		t.Fatalf("expected directories outside the notes root to be ignored")
+	}
+
+	mustWriteFile(t, filepath.Join(root, FolderTemplateFileName), "root\n")
+	tmpl, ok = m.folderDefaultTemplate(filepath.Join(meetings, "weekly"))
+	if !ok || tmpl.content != "# Meeting {{title}}\n" {
+		t.Fatalf("expected nearest template to win, got %#v", tmpl)
+	}
+}
+
+func TestSaveNewNoteUsesFolderTemplateUnlessPickerChosen(t *testing.T) {
+	root := t.TempDir()
+	meetings := filepath.Join(root, "meetings")
+	mustWriteFile(t, filepath.Join(meetings, FolderTemplateFileName), "# Meeting {{title}}\n")
+	m := newTestCRUDModel(root)
+
+	m.newParent = meetings
+	m.input.SetValue("sync")
+	settlePendingOp(m.saveNewNote())
+	got, err := os.ReadFile(filepath.Join(meetings, "sync.md"))
+	if err != nil {
+		t.Fatalf("read created note: %v", err)
+	}
+	if string(got) != "# Meeting sync\n" {
+		t.Fatalf("expected folder template content, got %q", string(got))
+	}
+
+	m.mode = modeNewNote
+	m.newParent = meetings
+	m.templateChosen = true
+	m.input.SetValue("plain")
+	settlePendingOp(m.saveNewNote())
+	got, err = os.ReadFile(filepath.Join(meetings, "plain.md"))
+	if err != nil {
+		t.Fatalf("read created note: %v", err)
+	}
+	if string(got) != m.defaultNewNoteContent("plain.md") {
+		t.Fatalf("expected explicit Default choice to override folder template, got %q", string(got))
+	}
+}
+
+func TestTemplatePickerEditsAndReloadsTemplate(t *testing.T) {
+	root := t.TempDir()
+	templates := t.TempDir()
+	mustWriteFile(t, filepath.Join(templates, "a.md"), "# A\n")
+	mustWriteFile(t, filepath.Join(templates, "meeting.md"), "# Meeting\n")
+
+	m := newTestCRUDModel(root)
+	m.templatesDir = templates
+	m.templates = m.loadTemplates()
+	m.mode = modeTemplatePicker
+	m.templateCursor = 2
+
+	_, _ = m.handleTemplatePickerKey(keyPress("e"))
+	if m.mode != modeEditTemplate || m.editor.Value() != "# Meeting\n" {
+		t.Fatalf("expected the template in the editor, mode %v value %q", m.mode, m.editor.Value())
+	}
+	m.editor.SetValue("# Meeting\n\n## Agenda")
+	_, _ = m.handleEditTemplateKey(keyPress("ctrl+s"))
+
+	if data := readFileString(t, filepath.Join(templates, "meeting.md")); data != "# Meeting\n\n## Agenda\n" {
+		t.Fatalf("unexpected template file %q", data)
+	}
+	if m.mode != modeTemplatePicker || m.status != "Saved template: meeting.md" {
+		t.Fatalf("expected to return to the picker, mode %v status %q", m.mode, m.status)
+	}
+	if m.templateCursor != 2 || m.templates[2].content != "# Meeting\n\n## Agenda\n" {
+		t.Fatalf("expected the reloaded template under the cursor, got %d %+v", m.templateCursor, m.templates)
+	}
+
+	_, _ = m.handleTemplatePickerKey(keyPress("e"))
+	m.editor.SetValue("discarded")
+	_, _ = m.handleEditTemplateKey(keyPress("esc"))
+	if data := readFileString(t, filepath.Join(templates, "meeting.md")); !strings.Contains(data, "## Agenda") || m.mode != modeTemplatePicker {
+		t.Fatalf("expected Esc to keep the file, mode %v file %q", m.mode, data)
+	}
+
+	m.templateCursor = 0
+	_, _ = m.handleTemplatePickerKey(keyPress("e"))
+	if m.mode != modeTemplatePicker || m.status != "The default entry has no template file" {
+		t.Fatalf("expected the default entry to stay in the picker, mode %v status %q", m.mode, m.status)
+	}
+}
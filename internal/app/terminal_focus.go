@@ -0,0 +1,89 @@
+// terminal_focus.go reacts to the terminal losing and regaining focus
+// (tea.WithReportFocus), so coming back from another window never acts on
+// state that went stale in the meantime.
+//
+// On blur the editor selection anchor is dropped, a draft is written if the
+// buffer has unsaved edits, and watcher refreshes (events and polling) are
+// held back. On focus the held-back changes are applied, git status is
+// refreshed, and the current note is re-checked: while editing, a change on
+// disk enters the edit conflict prompt at once (with the transition guard
+// armed so a key typed on return cannot pick an answer); in browse mode the
+// preview is re-requested, which re-renders only when the file changed and
+// supersedes any render still in flight.
+//
+// Terminals that don't report focus never send these messages, so nothing
+// here runs and nothing is paused.
+package app
+
+import (
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleTerminalBlur runs when the terminal loses focus.
+func (m *Model) handleTerminalBlur(_ tea.BlurMsg) (tea.Model, tea.Cmd) {
+	m.terminalBlurred = true
+	m.clearEditorSelection()
+	if m.mode == modeEditNote && m.currentFile != "" {
+		if err := m.saveDraftForCurrentFile(); err != nil {
+			appLog.Warn("save draft on focus loss", "path", m.currentFile, "error", err)
+		}
+	}
+	return m, nil
+}
+
+// handleTerminalFocus runs when the terminal regains focus.
+func (m *Model) handleTerminalFocus(_ tea.FocusMsg) (tea.Model, tea.Cmd) {
+	if !m.terminalBlurred {
+		return m, nil
+	}
+	m.terminalBlurred = false
+	prevMode, prevOverlay := m.mode, m.overlay
+
+	var cmd tea.Cmd
+	refreshed := true
+	switch {
+	case m.blurredFSFull:
+		cmd = m.handleExternalFilesystemChange(true)
+	case len(m.blurredFSPaths) > 0:
+		paths := make([]string, 0, len(m.blurredFSPaths))
+		for path := range m.blurredFSPaths {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		cmd = m.handleExternalPathChanges(paths)
+	default:
+		refreshed = false
+	}
+	m.blurredFSPaths = nil
+	m.blurredFSFull = false
+	m.refreshGitStatus()
+
+	switch m.mode {
+	case modeEditNote:
+		if m.editChangedOnDisk() {
+			m.enterEditConflict()
+		}
+	case modeBrowse:
+		if !refreshed && m.reconcileCurrentFileAfterFilesystemChange() {
+			cmd = m.refreshViewport()
+		}
+	}
+	m.armTransitionGuard(prevMode, prevOverlay)
+	return m, cmd
+}
+
+// deferFSEvents holds back a watcher batch while the terminal is blurred.
+func (m *Model) deferFSEvents(msg fsEventsMsg) {
+	if msg.full {
+		m.blurredFSFull = true
+		return
+	}
+	if m.blurredFSPaths == nil {
+		m.blurredFSPaths = map[string]bool{}
+	}
+	for _, path := range msg.paths {
+		m.blurredFSPaths[path] = true
+	}
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFocusRegainAfterExternalChangeShowsConflictBeforeKeys(t *testing.T) {
+	advance := withFixedTransitionClock(t)
+	root := t.TempDir()
+	path := filepath.Join(root, "note.md")
+	mustWriteFile(t, path, "original\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.currentFile = path
+	_, _ = m.startEditNote()
+	m.editor.SetValue("mine\n")
+	m.editorSelectionAnchor = 0
+	m.editorSelectionActive = true
+
+	_, _ = m.Update(tea.BlurMsg{})
+	if m.hasEditorSelectionAnchor() {
+		t.Fatal("expected blur to drop the selection anchor")
+	}
+	if _, err := os.Stat(m.draftPathForSource(path)); err != nil {
+		t.Fatalf("expected a draft snapshot on blur: %v", err)
+	}
+
+	mustWriteFile(t, path, "pulled\n")
+	_, _ = m.Update(tea.FocusMsg{})
+	if m.mode != modeEditConflict {
+		t.Fatalf("expected conflict prompt on focus, got mode %v (status %q)", m.mode, m.status)
+	}
+
+	// A key typed the moment the terminal comes back must not pick "overwrite".
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if got := readFileString(t, path); got != "pulled\n" {
+		t.Fatalf("expected external change to survive, got %q", got)
+	}
+	if m.mode != modeEditConflict || m.editor.Value() != "mine\n" {
+		t.Fatalf("expected prompt and buffer kept, mode %v buffer %q", m.mode, m.editor.Value())
+	}
+
+	advance(ModeTransitionKeyGuard)
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if got := readFileString(t, path); got != "pulled\n" {
+		t.Fatalf("expected conflict copy to leave the note alone, got %q", got)
+	}
+	if got := readFileString(t, filepath.Join(root, "note.conflict.md")); got != "mine\n" {
+		t.Fatalf("expected buffer in the conflict copy, got %q", got)
+	}
+}
+
+func TestBlurDefersWatcherChangesUntilFocus(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.fsWatcher = &fsEventWatcher{root: root, batches: make(chan fsEventsMsg)}
+
+	_, _ = m.Update(tea.BlurMsg{})
+	added := filepath.Join(root, "added.md")
+	mustWriteFile(t, added, "new\n")
+	_, _ = m.handleFSEvents(fsEventsMsg{watcher: m.fsWatcher, paths: []string{added}})
+	assertTreeHasPath(t, m.items, added, false)
+
+	_, _ = m.Update(tea.FocusMsg{})
+	assertTreeHasPath(t, m.items, added)
+	if m.terminalBlurred || m.blurredFSPaths != nil {
+		t.Fatal("expected deferred changes to be cleared after focus")
+	}
+}
+
+func TestFocusWithoutBlurIsInert(t *testing.T) {
+	m := newTestCRUDModel(t.TempDir())
+	m.mode = modeBrowse
+	m.status = "Ready"
+	if _, cmd := m.Update(tea.FocusMsg{}); cmd != nil || m.status != "Ready" {
+		t.Fatalf("expected focus without a prior blur to do nothing, status %q", m.status)
+	}
+}
@@ -0,0 +1,62 @@
+// toggle_folder.go implements the two-folder toggle (Alt+M) for workflows
+// with a binary state such as active/done.
+//
+// toggle_folders names two folders relative to the notes directory
+// (default active and done). The action moves the selected note from one
+// folder to the other at the same subpath (active/work/x.md ↔
+// done/work/x.md); a note in neither folder moves into the first one. The
+// move goes through relocatePath, so missing parent folders are created,
+// a destination that differs from an existing name only by case is refused,
+// and expansion, pins, positions, and caches follow the note. Like other
+// moves, it waits while a git operation holds the interlock (op_lock.go).
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// toggleFolderPaths returns the two absolute toggle folders.
+func (m *Model) toggleFolderPaths() (first, second string) {
+	dirs := m.toggleFolders
+	if len(dirs) != 2 {
+		dirs = []string{DefaultToggleFolderFirst, DefaultToggleFolderSecond}
+	}
+	return filepath.Join(m.notesDir, dirs[0]), filepath.Join(m.notesDir, dirs[1])
+}
+
+// toggleFolderTarget returns where path moves to: the same subpath in the
+// other folder, or the first folder when path is in neither.
+func (m *Model) toggleFolderTarget(path string) string {
+	first, second := m.toggleFolderPaths()
+	for _, pair := range [][2]string{{first, second}, {second, first}} {
+		if rel, err := filepath.Rel(pair[0], path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.Join(pair[1], rel)
+		}
+	}
+	return filepath.Join(first, filepath.Base(path))
+}
+
+// toggleSelectedFolder moves the selected note to the other toggle folder.
+func (m *Model) toggleSelectedFolder() {
+	if m.rejectWhileLocked("folder toggle") {
+		return
+	}
+	item := m.selectedItem()
+	if item == nil || item.isDir {
+		m.status = "Select a note to toggle its folder"
+		return
+	}
+	dest := m.toggleFolderTarget(item.path)
+	if _, err := os.Stat(dest); err == nil {
+		m.status = "Already exists: " + m.displayRelative(dest)
+		return
+	}
+	if !m.relocatePath(item.path, dest, "Error moving note") {
+		return
+	}
+	m.expandParentDirs(dest)
+	m.rebuildTreeKeep(dest)
+	m.status = "Moved to " + m.displayRelative(filepath.Dir(dest))
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToggleFolderMovesNoteBackAndForth(t *testing.T) {
+	root := t.TempDir()
+	active := filepath.Join(root, "active", "work")
+	note := filepath.Join(active, "x.md")
+	mustWriteFile(t, note, "# X\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.expanded[filepath.Join(root, "active")] = true
+	m.expanded[active] = true
+	m.pinnedPaths[note] = true
+	selectTreePath(t, m, note)
+
+	m.toggleSelectedFolder()
+
+	done := filepath.Join(root, "done", "work", "x.md")
+	if _, err := os.Stat(done); err != nil {
+		t.Fatalf("expected note moved to %q: %v", done, err)
+	}
+	if m.selectedPath() != done || m.status != "Moved to "+filepath.Join("done", "work") {
+		t.Fatalf("unexpected selection %q status %q", m.selectedPath(), m.status)
+	}
+	if !m.pinnedPaths[done] {
+		t.Fatal("expected pinned state to follow the note")
+	}
+
+	m.toggleSelectedFolder()
+
+	if _, err := os.Stat(note); err != nil {
+		t.Fatalf("expected note back at %q: %v", note, err)
+	}
+	if _, err := os.Stat(done); !os.IsNotExist(err) {
+		t.Fatalf("expected %q gone, got %v", done, err)
+	}
+}
+
+func TestToggleFolderUsesConfiguredFolders(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "loose.md")
+	mustWriteFile(t, note, "# Loose\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.toggleFolders = []string{"todo", "later"}
+	selectTreePath(t, m, note)
+
+	m.toggleSelectedFolder()
+	if _, err := os.Stat(filepath.Join(root, "todo", "loose.md")); err != nil {
+		t.Fatalf("expected a note outside both folders to move into the first: %v", err)
+	}
+
+	mustWriteFile(t, filepath.Join(root, "later", "loose.md"), "# Other\n")
+	m.toggleSelectedFolder()
+	if m.status != "Already exists: "+filepath.Join("later", "loose.md") {
+		t.Fatalf("expected a collision to be refused, got %q", m.status)
+	}
+}
+
+func TestToggleFolderWaitsForGitAndRefusesCaseClash(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "active", "x.md")
+	mustWriteFile(t, note, "# X\n")
+	mustWriteFile(t, filepath.Join(root, "done", "X.md"), "# Other X\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.expanded[filepath.Join(root, "active")] = true
+	selectTreePath(t, m, note)
+
+	m.acquireOpLock("git pull")
+	m.toggleSelectedFolder()
+	if !strings.Contains(m.status, "Git pull in progress: folder toggle") {
+		t.Fatalf("expected the toggle to wait for the pull, got %q", m.status)
+	}
+	if _, err := os.Stat(note); err != nil {
+		t.Fatalf("expected note to stay while locked: %v", err)
+	}
+
+	m.releaseOpLock(m.opLock.token)
+	m.toggleSelectedFolder()
+	if _, err := os.Stat(note); err != nil {
+		t.Fatalf("expected the case clash to be refused, got status %q: %v", m.status, err)
+	}
+}
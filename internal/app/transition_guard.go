@@ -0,0 +1,47 @@
+// transition_guard.go drops key presses that were buffered before a mode
+// change but dispatched after it.
+//
+// Bubble Tea delivers queued key messages one at a time against whatever
+// mode is active when each is processed. Without a guard, a fast double `e`
+// enters edit mode and then types a literal "e" into the note, a double Enter
+// in the new-note prompt creates the note and then toggles the selected
+// folder, and a `d` queued behind a delete confirmation acts on the next
+// item. Key messages carry no timestamp, so after any key that changes the
+// mode or opens/closes a popup, keys arriving within ModeTransitionKeyGuard
+// are swallowed. Ctrl+C always gets through, and the edit-mode wiki-link
+// autocomplete popup does not arm the guard because it opens while typing.
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// swallowTransitionKey reports whether msg arrived inside the guard window
+// of the last mode transition and should be dropped.
+func (m *Model) swallowTransitionKey(msg tea.KeyMsg) bool {
+	if m.transitionGuardUntil.IsZero() || msg.String() == "ctrl+c" {
+		return false
+	}
+	if !appNow().Before(m.transitionGuardUntil) {
+		m.transitionGuardUntil = time.Time{}
+		return false
+	}
+	if m.debugInput {
+		m.status = fmt.Sprintf("Ignored key after mode change: %q", msg.String())
+	}
+	return true
+}
+
+// armTransitionGuard starts the guard window when the key just handled
+// moved the UI out of prevMode/prevOverlay.
+func (m *Model) armTransitionGuard(prevMode mode, prevOverlay overlayMode) {
+	overlayChanged := m.overlay != prevOverlay &&
+		m.overlay != overlayWikiAutocomplete && prevOverlay != overlayWikiAutocomplete
+	if m.mode == prevMode && !overlayChanged {
+		return
+	}
+	m.transitionGuardUntil = appNow().Add(ModeTransitionKeyGuard)
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// withFixedTransitionClock pins the transition guard clock and returns a
+// function that advances it.
+func withFixedTransitionClock(t *testing.T) func(time.Duration) {
+	t.Helper()
+	now := time.Date(2026, 2, 7, 9, 30, 0, 0, time.UTC)
+	stubNow(t, func() time.Time { return now })
+	return func(d time.Duration) { now = now.Add(d) }
+}
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestDoubleEditKeyDoesNotInsertIntoNote(t *testing.T) {
+	advance := withFixedTransitionClock(t)
+	root := t.TempDir()
+	note := filepath.Join(root, "note.md")
+	mustWriteFile(t, note, "hello")
+
+	m := newFocusedEditModel("")
+	m.notesDir = root
+	m.mode = modeBrowse
+	m.currentFile = note
+	m.keyToAction = map[string]string{"e": actionEditNote}
+
+	_, _ = m.Update(runeKey('e'))
+	_, _ = m.Update(runeKey('e'))
+	if m.mode != modeEditNote {
+		t.Fatalf("expected edit mode, got %v", m.mode)
+	}
+	if got := m.editor.Value(); got != "hello" {
+		t.Fatalf("expected buffered key to be dropped, editor has %q", got)
+	}
+
+	advance(ModeTransitionKeyGuard)
+	_, _ = m.Update(runeKey('!'))
+	if got := m.editor.Value(); got != "hello!" {
+		t.Fatalf("expected key after the guard window to be typed, editor has %q", got)
+	}
+}
+
+func TestDoubleEnterInNewNotePromptDoesNotToggleFolder(t *testing.T) {
+	withFixedTransitionClock(t)
+	root := t.TempDir()
+	projects := filepath.Join(root, "projects")
+	if err := os.MkdirAll(filepath.Join(projects, "sub"), DirPermission); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	m := newTestCRUDModel(root)
+	m.expanded[projects] = true
+	m.rebuildTreeKeep(projects)
+	m.keyToAction = map[string]string{"enter": actionExpandToggle}
+	m.newParent = projects
+	m.input.SetValue("idea")
+
+	settlePendingOp(m.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	selected := m.selectedItem()
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	notePath := filepath.Join(projects, "idea.md")
+	if _, err := os.Stat(notePath); err != nil {
+		t.Fatalf("expected note to be created: %v", err)
+	}
+	if m.mode != modeBrowse {
+		t.Fatalf("expected browse mode, got %v", m.mode)
+	}
+	if !m.expanded[projects] || m.expanded[filepath.Join(projects, "sub")] {
+		t.Fatalf("expected folder expansion to be unchanged, got %v", m.expanded)
+	}
+	if got := m.selectedItem(); selected == nil || got == nil || got.path != selected.path {
+		t.Fatalf("expected selection to stay on %v, got %v", selected, got)
+	}
+}
+
+func TestKeyAfterDeleteConfirmationIsDropped(t *testing.T) {
+	withFixedTransitionClock(t)
+	root := t.TempDir()
+	first := filepath.Join(root, "a.md")
+	second := filepath.Join(root, "b.md")
+	mustWriteFile(t, first, "a")
+	mustWriteFile(t, second, "b")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.rebuildTreeKeep(first)
+	m.keyToAction = map[string]string{"d": actionDelete}
+
+	_, _ = m.Update(runeKey('d'))
+	if m.mode != modeConfirmDelete {
+		t.Fatalf("expected delete confirmation, got mode %v", m.mode)
+	}
+	m.transitionGuardUntil = time.Time{} // the user reads the prompt
+	_, _ = m.Update(runeKey('y'))
+	_, _ = m.Update(runeKey('d'))
+
+	if m.mode != modeBrowse {
+		t.Fatalf("expected queued d to be dropped, got mode %v", m.mode)
+	}
+	if _, err := os.Stat(second); err != nil {
+		t.Fatalf("expected next note to be untouched: %v", err)
+	}
+}
+
+func TestCtrlCIsNeverSwallowed(t *testing.T) {
+	withFixedTransitionClock(t)
+	m := &Model{mode: modeBrowse}
+	m.armTransitionGuard(modeEditNote, overlayNone)
+
+	if m.swallowTransitionKey(tea.KeyMsg{Type: tea.KeyCtrlC}) {
+		t.Fatal("expected ctrl+c to pass the transition guard")
+	}
+	if !m.swallowTransitionKey(runeKey('j')) {
+		t.Fatal("expected j to be swallowed inside the guard window")
+	}
+}
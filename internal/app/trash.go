@@ -0,0 +1,242 @@
+// trash.go implements soft delete.
+//
+// Deleting (`d`) moves the note or folder into <notesDir>/.cli-notes/trash/
+// at the same relative subpath, with a timestamp suffix appended to its name
+// (notes/a.md → .cli-notes/trash/notes/a.md~20260207-093000). Folders move
+// as a whole, so non-empty folders can be deleted too. The trash lives under
+// the managed .cli-notes directory, which the tree walker and search index
+// always skip.
+//
+// The trash popup (Ctrl+T) lists trashed items newest first; Enter restores
+// the selected one to its original relative path, recreating missing parent
+// folders. Its footer also reports the workspace's drafts count and size
+// (see draft_retention.go). Setting hard_delete in config restores the old behavior of
+// removing files and empty folders immediately.
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// trashTimeLayout formats the suffix appended to trashed item names.
+const trashTimeLayout = "20060102-150405"
+
+// trashSuffixPattern matches the "~<timestamp>" suffix (plus an optional
+// "-N" collision counter) that marks an entry as a trashed item rather than
+// a folder mirroring the original path.
+var trashSuffixPattern = regexp.MustCompile(`~(\d{8}-\d{6})(-\d+)?$`)
+
+// trashEntry is one item in the trash.
+type trashEntry struct {
+	path      string    // current location inside the trash directory
+	origPath  string    // absolute path the item was deleted from
+	deletedAt time.Time // parsed from the name suffix (local time)
+	isDir     bool
+}
+
+// trashDir returns the trash directory for a notes root.
+func trashDir(root string) string {
+	return filepath.Join(root, managedNotesDirName, "trash")
+}
+
+// moveToTrash moves path into the trash, keeping its relative location and
+// appending a timestamp suffix. Missing parent folders inside the trash are
+// created; folders move recursively.
+func (m *Model) moveToTrash(path string) error {
+	rel, err := filepath.Rel(m.notesDir, path)
+	if err != nil || rel == "." || !isWithinRoot(m.notesDir, path) {
+		return fmt.Errorf("path is outside notes directory")
+	}
+	base := filepath.Join(trashDir(m.notesDir), rel) + "~" + appNow().Format(trashTimeLayout)
+	dest := base
+	for n := 2; ; n++ {
+		if _, err := os.Lstat(dest); os.IsNotExist(err) {
+			break
+		}
+		dest = fmt.Sprintf("%s-%d", base, n)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), DirPermission); err != nil {
+		return err
+	}
+	return movePathWithFallback(path, dest)
+}
+
+// listTrash returns every trashed item, newest first. Folders that only
+// mirror the original directory structure are walked but not listed.
+func (m *Model) listTrash() []trashEntry {
+	root := trashDir(m.notesDir)
+	var entries []trashEntry
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+		match := trashSuffixPattern.FindStringSubmatch(d.Name())
+		if match == nil {
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return nil
+		}
+		deletedAt, _ := time.ParseInLocation(trashTimeLayout, match[1], time.Local)
+		entries = append(entries, trashEntry{
+			path:      path,
+			origPath:  filepath.Join(m.notesDir, strings.TrimSuffix(rel, match[0])),
+			deletedAt: deletedAt,
+			isDir:     d.IsDir(),
+		})
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].deletedAt.Equal(entries[j].deletedAt) {
+			return entries[i].deletedAt.After(entries[j].deletedAt)
+		}
+		return entries[i].path > entries[j].path
+	})
+	return entries
+}
+
+// restoreFromTrash moves a trashed item back to its original path. It
+// refuses when something already exists there, or a sibling whose name
+// differs only by case, and recreates any parent
+// folders removed since the delete.
+func (m *Model) restoreFromTrash(entry trashEntry) bool {
+	if !isWithinRoot(m.notesDir, entry.origPath) || entry.origPath == m.notesDir {
+		m.status = "Invalid restore target"
+		return false
+	}
+	if _, err := os.Lstat(entry.origPath); err == nil {
+		m.status = "Original location already exists: " + m.displayRelative(entry.origPath)
+		return false
+	}
+	createdDirs := missingDirs(m.notesDir, filepath.Dir(entry.origPath))
+	if status, collides := firstCaseCollision("", append(createdDirs, entry.origPath)...); collides {
+		m.status = status
+		return false
+	}
+	if err := os.MkdirAll(filepath.Dir(entry.origPath), DirPermission); err != nil {
+		m.setStatusError("Error restoring from trash", err, "path", entry.origPath)
+		return false
+	}
+	if err := movePathWithFallback(entry.path, entry.origPath); err != nil {
+		m.setStatusError("Error restoring from trash", err, "from", entry.path, "to", entry.origPath)
+		return false
+	}
+	pruneEmptyTrashDirs(trashDir(m.notesDir), filepath.Dir(entry.path))
+
+	m.expandParentDirs(entry.origPath)
+	_ = m.applyMutationEffects(mutationEffects{
+		upsertPaths:     append(createdDirs, entry.origPath),
+		refreshGit:      true,
+		refreshTree:     true,
+		rebuildKeepPath: entry.origPath,
+	})
+	m.status = "Restored: " + m.displayRelative(entry.origPath)
+	return true
+}
+
+// pruneEmptyTrashDirs removes the now-empty mirror folders between dir and
+// the trash root after an item has been restored.
+func pruneEmptyTrashDirs(root, dir string) {
+	for dir != root && isWithinRoot(root, dir) && isDirEmpty(dir) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// openTrashPopup lists trashed items (Ctrl+T in browse mode).
+func (m *Model) openTrashPopup() {
+	entries := m.listTrash()
+	usage, err := scanDraftUsage(m.notesDir)
+	if err != nil {
+		appLog.Warn("scan drafts", "dir", m.draftsDir(), "error", err)
+	}
+	if len(entries) == 0 && usage.count == 0 {
+		m.status = "Trash is empty"
+		return
+	}
+	m.openOverlay(overlayTrash)
+	m.trashDraftUsage = usage
+	m.trashEntries = entries
+	m.trashCursor = 0
+	m.status = "Trash: Enter to restore, Esc to close"
+}
+
+// handleTrashPopupKey routes key presses while the trash popup is visible.
+// Restoring keeps the popup open on the remaining items until it is empty.
+func (m *Model) handleTrashPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.trashCursor, len(m.trashEntries))
+	if !handled {
+		return m, nil
+	}
+	if closePressed {
+		m.closeOverlay()
+		m.status = "Trash closed"
+		return m, nil
+	}
+	if len(m.trashEntries) == 0 {
+		return m, nil
+	}
+	m.trashCursor = next
+	if !selectPressed || !m.restoreFromTrash(m.trashEntries[m.trashCursor]) {
+		return m, nil
+	}
+	m.trashEntries = m.listTrash()
+	if len(m.trashEntries) == 0 {
+		m.closeOverlay()
+		return m, nil
+	}
+	m.trashCursor = min(m.trashCursor, len(m.trashEntries)-1)
+	return m, nil
+}
+
+// renderTrashPopup draws the trash popup: original path and delete time.
+func (m *Model) renderTrashPopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	lines := []string{
+		titleStyle.Render("Trash"),
+		"",
+	}
+	limit := max(0, innerHeight-len(lines)-3)
+	start := 0
+	if limit > 0 {
+		start = max(0, m.trashCursor-limit+1)
+	}
+	for i := start; i < min(start+limit, len(m.trashEntries)); i++ {
+		entry := m.trashEntries[i]
+		label := m.displayRelative(entry.origPath)
+		if entry.isDir {
+			label += "/"
+		}
+		line := truncate(label+"  (deleted "+entry.deletedAt.Format("2006-01-02 15:04")+")", innerWidth)
+		if i == m.trashCursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(m.trashEntries) == 0 {
+		lines = append(lines, mutedStyle.Render("Trash is empty"))
+	}
+	lines = append(lines, "", mutedStyle.Render(truncate("Drafts: "+m.trashDraftUsage.String(), innerWidth)))
+	lines = append(lines, mutedStyle.Render("Enter: restore  Esc: close"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDeleteMovesNoteToTrashAndRestores(t *testing.T) {
+	root := t.TempDir()
+	withFixedNow(t, time.Date(2026, 2, 7, 9, 30, 0, 0, time.Local))
+	projects := filepath.Join(root, "projects")
+	note := filepath.Join(projects, "x.md")
+	mustWriteFile(t, note, "# X\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.expanded[projects] = true
+	selectTreePath(t, m, note)
+
+	m.deleteSelected()
+	if m.status != `Move note "x.md" to trash? (y/N)` {
+		t.Fatalf("unexpected confirmation %q", m.status)
+	}
+	m.performDelete(&m.pendingDelete)
+
+	trashed := filepath.Join(trashDir(root), "projects", "x.md~20260207-093000")
+	if _, err := os.Stat(trashed); err != nil {
+		t.Fatalf("expected trashed note at %q: %v", trashed, err)
+	}
+	if _, err := os.Stat(note); !os.IsNotExist(err) {
+		t.Fatalf("expected original removed, stat err: %v", err)
+	}
+	assertTreeHasPath(t, m.items, note, false)
+
+	// Remove the parent so restore has to recreate it.
+	if err := os.Remove(projects); err != nil {
+		t.Fatalf("remove parent: %v", err)
+	}
+	entries := m.listTrash()
+	if len(entries) != 1 || entries[0].origPath != note || entries[0].isDir {
+		t.Fatalf("unexpected trash entries %#v", entries)
+	}
+	if !m.restoreFromTrash(entries[0]) {
+		t.Fatalf("restore failed: %q", m.status)
+	}
+	if data, err := os.ReadFile(note); err != nil || string(data) != "# X\n" {
+		t.Fatalf("expected restored note, got %q err %v", string(data), err)
+	}
+	if m.status != "Restored: "+filepath.Join("projects", "x.md") {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	if _, err := os.Stat(filepath.Join(trashDir(root), "projects")); !os.IsNotExist(err) {
+		t.Fatalf("expected empty mirror folder pruned, stat err: %v", err)
+	}
+	assertTreeHasPath(t, m.items, note)
+}
+
+func TestTrashFolderRecursivelyAndListNewestFirst(t *testing.T) {
+	root := t.TempDir()
+	folder := filepath.Join(root, "old")
+	mustWriteFile(t, filepath.Join(folder, "sub", "a.md"), "# A\n")
+	note := filepath.Join(root, "b.md")
+	mustWriteFile(t, note, "# B\n")
+	m := newTestCRUDModel(root)
+
+	withFixedNow(t, time.Date(2026, 2, 7, 9, 0, 0, 0, time.Local))
+	if err := m.moveToTrash(folder); err != nil {
+		t.Fatalf("trash folder: %v", err)
+	}
+	withFixedNow(t, time.Date(2026, 2, 8, 9, 0, 0, 0, time.Local))
+	if err := m.moveToTrash(note); err != nil {
+		t.Fatalf("trash note: %v", err)
+	}
+
+	entries := m.listTrash()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 trash entries, got %#v", entries)
+	}
+	if entries[0].origPath != note || entries[1].origPath != folder || !entries[1].isDir {
+		t.Fatalf("expected newest first with folder listed once, got %#v", entries)
+	}
+	if _, err := os.Stat(filepath.Join(entries[1].path, "sub", "a.md")); err != nil {
+		t.Fatalf("expected folder contents trashed: %v", err)
+	}
+}
+
+func TestTrashSameSecondGetsCounterAndRestoreRefusesExisting(t *testing.T) {
+	root := t.TempDir()
+	withFixedNow(t, time.Date(2026, 2, 7, 9, 0, 0, 0, time.Local))
+	note := filepath.Join(root, "a.md")
+	m := newTestCRUDModel(root)
+	for i := 0; i < 2; i++ {
+		mustWriteFile(t, note, "# A\n")
+		if err := m.moveToTrash(note); err != nil {
+			t.Fatalf("trash: %v", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(trashDir(root), "a.md~20260207-090000-2")); err != nil {
+		t.Fatalf("expected collision counter: %v", err)
+	}
+
+	mustWriteFile(t, note, "new\n")
+	entries := m.listTrash()
+	if m.restoreFromTrash(entries[0]) {
+		t.Fatalf("expected restore over existing note to be refused")
+	}
+	if m.status != "Original location already exists: a.md" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
+func TestHardDeleteRemovesImmediately(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "a.md")
+	mustWriteFile(t, note, "# A\n")
+	m := newTestCRUDModel(root)
+	m.hardDelete = true
+
+	m.performDelete(&treeItem{path: note, name: "a.md"})
+
+	if _, err := os.Stat(note); !os.IsNotExist(err) {
+		t.Fatalf("expected note removed, stat err: %v", err)
+	}
+	if _, err := os.Stat(trashDir(root)); !os.IsNotExist(err) {
+		t.Fatalf("expected no trash directory, stat err: %v", err)
+	}
+	if m.status != "Deleted: a.md" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
@@ -17,13 +17,20 @@
 //   - size:     Largest first
 //   - created:  Most recently created first (platform-dependent; see file_time_*.go)
 //
-// In every mode, directories are sorted before files, and pinned items are
-// sorted before unpinned items at the same level. When the primary sort key
-// is equal (e.g. two files with the same modification time), the tiebreaker
-// is always case-insensitive alphabetical order.
+// Each mode has a natural direction (ascending for name, descending for the
+// others) which `S` reverses. A folder can also carry its own mode/direction
+// override (see treeOrder), stored in per-workspace app state.
+//
+// In every mode and direction, directories are sorted before files, and
+// pinned items are sorted before unpinned items at the same level. When the primary sort key
+// is equal (e.g. two files with the same modification time), the configured
+// tiebreaker decides the order (see sortTiebreak). The default is
+// case-insensitive alphabetical order.
 package app
 
 import (
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
@@ -34,6 +41,7 @@ import (
 type treeMetadataCacheEntry struct {
 	modTime time.Time
 	tags    []string
+	title   string
 }
 
 // sortMode determines how entries are ordered within each directory level
@@ -97,6 +105,142 @@ func nextSortMode(current sortMode) sortMode {
 	}
 }
 
+// sortTiebreak decides the order of entries whose primary sort key is equal
+// (same mtime, same size, ...). It is persisted in config.json under
+// "tree_sort_tiebreak".
+type sortTiebreak string
+
+const (
+	sortTiebreakName     sortTiebreak = "name"      // Case-insensitive name ascending (default)
+	sortTiebreakNameDesc sortTiebreak = "name_desc" // Case-insensitive name descending
+)
+
+// parseSortTiebreak converts a config string to a sortTiebreak constant.
+// Unrecognized values fall back to sortTiebreakName.
+func parseSortTiebreak(value string) sortTiebreak {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case string(sortTiebreakNameDesc):
+		return sortTiebreakNameDesc
+	default:
+		return sortTiebreakName
+	}
+}
+
+// lessByTiebreak orders two entry names according to the tiebreaker. Names
+// that only differ by case are ordered by their exact bytes so the result is
+// fully deterministic regardless of the order os.ReadDir returned them in.
+func lessByTiebreak(left, right string, tiebreak sortTiebreak) bool {
+	leftKey := strings.ToLower(left)
+	rightKey := strings.ToLower(right)
+	if leftKey == rightKey {
+		leftKey, rightKey = left, right
+	}
+	if tiebreak == sortTiebreakNameDesc {
+		return leftKey > rightKey
+	}
+	return leftKey < rightKey
+}
+
+// sortDirection is the direction applied to a sort mode's primary key. The
+// empty value means the mode's natural direction (see defaultDirection).
+type sortDirection string
+
+const (
+	sortAscending  sortDirection = "asc"  // A→Z, oldest first, smallest first
+	sortDescending sortDirection = "desc" // Z→A, newest first, largest first
+)
+
+// parseSortDirection converts a config/state string to a sortDirection.
+// Unrecognized values return "" so the mode's natural direction applies.
+func parseSortDirection(value string) sortDirection {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case string(sortAscending):
+		return sortAscending
+	case string(sortDescending):
+		return sortDescending
+	default:
+		return ""
+	}
+}
+
+// defaultDirection returns the mode's natural direction: ascending for name,
+// descending (newest/largest first) for everything else.
+func (s sortMode) defaultDirection() sortDirection {
+	if s == sortModeName || s == "" {
+		return sortAscending
+	}
+	return sortDescending
+}
+
+// reversed returns the opposite direction.
+func (d sortDirection) reversed() sortDirection {
+	if d == sortDescending {
+		return sortAscending
+	}
+	return sortDescending
+}
+
+// Arrow returns the footer/status glyph for the direction.
+func (d sortDirection) Arrow() string {
+	if d == sortDescending {
+		return "↓"
+	}
+	return "↑"
+}
+
+// folderSort is a sort mode and direction pair, used both for the global
+// tree order and for per-folder overrides.
+type folderSort struct {
+	mode      sortMode
+	direction sortDirection
+}
+
+// resolved fills in the mode's natural direction when none is set.
+func (f folderSort) resolved() folderSort {
+	if f.mode == "" {
+		f.mode = sortModeName
+	}
+	if f.direction == "" {
+		f.direction = f.mode.defaultDirection()
+	}
+	return f
+}
+
+// Label returns the human-readable form, e.g. "modified ↓".
+func (f folderSort) Label() string {
+	f = f.resolved()
+	return f.mode.Label() + " " + f.direction.Arrow()
+}
+
+// treeOrder bundles everything walkTree needs to order one directory level:
+// the global mode/direction, the tiebreaker, and per-folder overrides keyed
+// by absolute directory path. An override applies to the folder's direct
+// children only.
+type treeOrder struct {
+	mode      sortMode
+	direction sortDirection
+	tiebreak  sortTiebreak
+	overrides map[string]folderSort
+}
+
+// forDir returns the resolved mode and direction used for dir's children.
+func (o treeOrder) forDir(dir string) folderSort {
+	if override, ok := o.overrides[dir]; ok {
+		return override.resolved()
+	}
+	return folderSort{mode: o.mode, direction: o.direction}.resolved()
+}
+
+// treeOrder returns the model's current ordering settings.
+func (m *Model) treeOrder() treeOrder {
+	return treeOrder{
+		mode:      m.sortMode,
+		direction: m.sortDirection,
+		tiebreak:  m.sortTiebreak,
+		overrides: m.folderSorts,
+	}
+}
+
 // moveCursor changes the selection and keeps it within bounds.
 func (m *Model) moveCursor(delta int) {
 	if len(m.items) == 0 {
@@ -107,9 +251,18 @@ func (m *Model) moveCursor(delta int) {
 	m.adjustTreeOffset()
 }
 
+// treeHeaderLines is the number of rows above the tree items: the notes path
+// header, plus the filter line while a tree filter is being typed or active.
+func (m *Model) treeHeaderLines() int {
+	if m.mode == modeTreeFilter || m.treeFiltered() {
+		return 2
+	}
+	return 1
+}
+
 // adjustTreeOffset scrolls the tree so the cursor remains visible.
 func (m *Model) adjustTreeOffset() {
-	visibleHeight := max(0, m.leftHeight-2-1)
+	visibleHeight := max(0, m.leftHeight-2-m.treeHeaderLines())
 	if visibleHeight == 0 {
 		m.treeOffset = 0
 		return
@@ -132,6 +285,10 @@ func (m *Model) toggleExpand(expandIfDir bool) {
 	if item == nil || !item.isDir {
 		return
 	}
+	if m.treeFiltered() {
+		m.status = "Folders stay open while filtering (Esc clears the filter)"
+		return
+	}
 
 	if expandIfDir {
 		m.expanded[item.path] = !m.expanded[item.path]
@@ -145,6 +302,61 @@ func (m *Model) toggleExpand(expandIfDir bool) {
 	m.rebuildTreeKeep(item.path)
 }
 
+// setAllExpanded expands every folder under the notes directory (up to
+// MaxExpandAllDirs, skipping the managed directory and, while hidden, the
+// archive) or collapses everything but the root. After collapsing, the
+// cursor moves to the top-level item that contained the selection.
+func (m *Model) setAllExpanded(expand bool) {
+	if m.treeFiltered() {
+		m.status = "Folders stay open while filtering (Esc clears the filter)"
+		return
+	}
+	keep := m.selectedPath()
+	if !expand {
+		m.expanded = map[string]bool{m.notesDir: true}
+		for keep != "" && keep != m.notesDir && filepath.Dir(keep) != m.notesDir {
+			keep = filepath.Dir(keep)
+		}
+		m.rebuildTreeKeep(keep)
+		m.status = "Collapsed all folders"
+		return
+	}
+
+	expanded := map[string]bool{m.notesDir: true}
+	truncated := false
+	_ = filepath.WalkDir(m.notesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == m.notesDir {
+			return nil
+		}
+		if shouldSkipManagedPath(d.Name()) || (!m.showArchived && isArchivedPath(m.notesDir, path)) {
+			return filepath.SkipDir
+		}
+		if len(expanded) > MaxExpandAllDirs {
+			truncated = true
+			return fs.SkipAll
+		}
+		expanded[path] = true
+		return nil
+	})
+	m.expanded = expanded
+	m.rebuildTreeKeep(keep)
+	m.status = fmt.Sprintf("Expanded %d folders", len(expanded)-1)
+	if truncated {
+		m.status = fmt.Sprintf("Expanded the first %d folders; open the rest one by one", MaxExpandAllDirs)
+	}
+}
+
+// toggleFileSizes shows or hides the tree's size column. Sizes come from the
+// stat done during the tree walk, so toggling needs no filesystem access.
+func (m *Model) toggleFileSizes() {
+	m.showFileSizes = !m.showFileSizes
+	if m.showFileSizes {
+		m.status = "File sizes: on"
+	} else {
+		m.status = "File sizes: off"
+	}
+}
+
 // refreshTree rebuilds the tree while preserving selection.
 func (m *Model) refreshTree() {
 	selected := m.selectedPath()
@@ -153,8 +365,12 @@ func (m *Model) refreshTree() {
 }
 
 // rebuildTreeKeep rebuilds the tree and keeps the cursor near the given path.
+//
+// When a tree filter is active the filtered variant is used, so notes created,
+// renamed, or deleted while filtering show up (or vanish) immediately.
 func (m *Model) rebuildTreeKeep(path string) {
-	m.items = buildTreeWithMetadataCache(m.notesDir, m.expanded, m.sortMode, m.pinnedPaths, m.cachedTagsForPath)
+	m.items = m.buildTreeItems()
+	m.refreshWorkspaceNoteCount()
 	if len(m.items) == 0 {
 		m.cursor = 0
 		m.treeOffset = 0
@@ -170,6 +386,21 @@ func (m *Model) rebuildTreeKeep(path string) {
 	m.adjustTreeOffset()
 }
 
+// buildTreeItems builds the rows for the current view: filtered when a tree
+// or tag filter is active, and without the archive folder unless it is shown.
+func (m *Model) buildTreeItems() []treeItem {
+	var items []treeItem
+	if m.treeFiltered() {
+		items = buildFilteredTree(m.notesDir, m.treeFilterQuery, m.tagFilter, m.treeOrder(), m.pinnedPaths, m.cachedTagsForPath, m.cachedTitleForPath)
+	} else {
+		items = buildTreeWithMetadataCache(m.notesDir, m.expanded, m.treeOrder(), m.pinnedPaths, m.cachedTagsForPath)
+	}
+	if !m.showArchived {
+		items = withoutArchived(m.notesDir, items)
+	}
+	return items
+}
+
 func (m *Model) cachedTagsForPath(path string, info os.FileInfo) []string {
 	if m.treeMetadataCache == nil {
 		m.treeMetadataCache = map[string]treeMetadataCacheEntry{}
@@ -182,6 +413,7 @@ func (m *Model) cachedTagsForPath(path string, info os.FileInfo) []string {
 	m.treeMetadataCache[path] = treeMetadataCacheEntry{
 		modTime: info.ModTime(),
 		tags:    tags,
+		title:   meta.Title,
 	}
 	return tags
 }
@@ -229,12 +461,12 @@ func (m *Model) invalidateTreeMetadataCache() {
 //
 // This produces a depth-first traversal that matches typical file browser UIs.
 func buildTree(root string, expanded map[string]bool, mode sortMode, pinned map[string]bool) []treeItem {
-	return buildTreeWithMetadataCache(root, expanded, mode, pinned, nil)
+	return buildTreeWithMetadataCache(root, expanded, treeOrder{mode: mode, tiebreak: sortTiebreakName}, pinned, nil)
 }
 
-func buildTreeWithMetadataCache(root string, expanded map[string]bool, mode sortMode, pinned map[string]bool, metadata func(path string, info os.FileInfo) []string) []treeItem {
+func buildTreeWithMetadataCache(root string, expanded map[string]bool, order treeOrder, pinned map[string]bool, metadata func(path string, info os.FileInfo) []string) []treeItem {
 	items := []treeItem{}
-	walkTree(root, 0, expanded, mode, pinned, metadata, &items)
+	walkTree(root, 0, expanded, order, pinned, metadata, &items)
 	return items
 }
 
@@ -246,15 +478,15 @@ func buildTreeWithMetadataCache(root string, expanded map[string]bool, mode sort
 //  3. Sorts entries using a multi-key comparator:
 //     - Pinned items first (within the same directory level)
 //     - Directories before files
-//     - Primary key determined by sortMode (name, modified, size, or created)
-//     - Tiebreaker: case-insensitive alphabetical name
+//     - Primary key from the level's mode and direction (treeOrder.forDir)
+//     - Tiebreaker: case-insensitive name in the configured direction
 //  4. Appends each entry as a treeItem. For markdown files, frontmatter tags
 //     are parsed and attached to the item for display in the tree row.
 //  5. If a directory is marked as expanded, recurses into it at depth+1.
 //
 // Only expanded folders have their children added to the tree, which keeps the
 // flat items slice compact and makes cursor indexing simple.
-func walkTree(dir string, depth int, expanded map[string]bool, mode sortMode, pinned map[string]bool, metadata func(path string, info os.FileInfo) []string, items *[]treeItem) {
+func walkTree(dir string, depth int, expanded map[string]bool, order treeOrder, pinned map[string]bool, metadata func(path string, info os.FileInfo) []string, items *[]treeItem) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		appLog.Warn("read tree directory", "path", dir, "error", err)
@@ -292,6 +524,8 @@ func walkTree(dir string, depth int, expanded map[string]bool, mode sortMode, pi
 		})
 	}
 
+	levelSort := order.forDir(dir)
+	descending := levelSort.direction == sortDescending
 	sort.Slice(sortable, func(i, j int) bool {
 		left := sortable[i]
 		right := sortable[j]
@@ -304,22 +538,26 @@ func walkTree(dir string, depth int, expanded map[string]bool, mode sortMode, pi
 			return left.entry.IsDir()
 		}
 
-		switch mode {
+		switch levelSort.mode {
 		case sortModeModified:
 			if !left.modTime.Equal(right.modTime) {
-				return left.modTime.After(right.modTime)
+				return left.modTime.After(right.modTime) == descending
 			}
 		case sortModeSize:
 			if left.size != right.size {
-				return left.size > right.size
+				return (left.size > right.size) == descending
 			}
 		case sortModeCreated:
 			if !left.created.Equal(right.created) {
-				return left.created.After(right.created)
+				return left.created.After(right.created) == descending
+			}
+		case sortModeName:
+			if descending {
+				return lessByTiebreak(right.entry.Name(), left.entry.Name(), order.tiebreak)
 			}
 		}
 
-		return strings.ToLower(left.entry.Name()) < strings.ToLower(right.entry.Name())
+		return lessByTiebreak(left.entry.Name(), right.entry.Name(), order.tiebreak)
 	})
 
 	for _, entry := range sortable {
@@ -331,6 +569,10 @@ func walkTree(dir string, depth int, expanded map[string]bool, mode sortMode, pi
 			isDir:  entry.entry.IsDir(),
 			pinned: pinned[path],
 		}
+		if !item.isDir {
+			item.size = entry.size
+			item.modTime = entry.modTime
+		}
 		if !item.isDir && hasSuffixCaseInsensitive(path, ".md") {
 			if metadata != nil {
 				item.tags = metadata(path, entry.info)
@@ -341,7 +583,7 @@ func walkTree(dir string, depth int, expanded map[string]bool, mode sortMode, pi
 		}
 		*items = append(*items, item)
 		if entry.entry.IsDir() && expanded[path] {
-			walkTree(path, depth+1, expanded, mode, pinned, metadata, items)
+			walkTree(path, depth+1, expanded, order, pinned, metadata, items)
 		}
 	}
 }
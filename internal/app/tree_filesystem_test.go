@@ -21,7 +21,7 @@ func TestWalkTreeReadDirError(t *testing.T) {
 
 	logs := captureLogOutput(t, func() {
 		var items []treeItem
-		walkTree(noReadDir, 0, make(map[string]bool), sortModeName, nil, nil, &items)
+		walkTree(noReadDir, 0, make(map[string]bool), treeOrder{mode: sortModeName}, nil, nil, &items)
 
 		// Should not crash, but should log a warning
 		if len(items) != 0 {
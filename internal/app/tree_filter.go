@@ -0,0 +1,154 @@
+// tree_filter.go implements the browse-mode tree filter (`/`).
+//
+// Unlike the Ctrl+P popup, the filter narrows the left pane in place. While
+// typing (modeTreeFilter) the tree is rebuilt on every keystroke to show only
+// files and folders whose name or frontmatter title contains the query, plus
+// the ancestor folders needed to reach them. Enter keeps the filter and
+// returns to normal tree navigation, so j/k move among the matches only. Esc
+// (while typing, or in browse mode with a filter active) clears the filter
+// and restores the cursor to where it was before filtering began.
+//
+// The saved expansion state is never modified by filtering: ancestors of
+// matches are shown open in the filtered view only, so clearing the filter
+// brings back the exact previous tree layout.
+package app
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// startTreeFilter enters filter-typing mode, prefilled with any active query.
+func (m *Model) startTreeFilter() {
+	if !m.treeFiltered() {
+		m.treeFilterRestorePath = m.selectedPath()
+	}
+	m.mode = modeTreeFilter
+	m.showHelp = false
+	m.input.Reset()
+	m.input.Placeholder = "Filter tree"
+	m.input.SetValue(m.treeFilterQuery)
+	m.input.CursorEnd()
+	m.input.Focus()
+	m.status = "Filter: type to narrow, Enter to keep, Esc to clear"
+}
+
+// handleTreeFilterKey processes keypresses while typing a tree filter.
+func (m *Model) handleTreeFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shouldIgnoreInput(msg) {
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc":
+		m.clearTreeFilter()
+		return m, nil
+	case "enter", "ctrl+s":
+		m.mode = modeBrowse
+		m.input.Blur()
+		if m.treeFilterQuery == "" {
+			m.status = "Filter cleared"
+		} else {
+			m.status = "Filter: " + m.treeFilterQuery + " (Esc to clear)"
+		}
+		return m, nil
+	case "up":
+		return m.handleCursorUp()
+	case "down":
+		return m.handleCursorDown()
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if query := strings.TrimSpace(m.input.Value()); query != m.treeFilterQuery {
+		m.treeFilterQuery = query
+		m.rebuildTreeKeep(m.selectedPath())
+	}
+	return m, cmd
+}
+
+// clearTreeFilter drops the active filter, rebuilds the unfiltered tree, and
+// restores the cursor to the row selected before filtering started.
+func (m *Model) clearTreeFilter() {
+	m.mode = modeBrowse
+	m.input.Blur()
+	restore := m.treeFilterRestorePath
+	m.treeFilterQuery = ""
+	if m.tagFilter != "" {
+		// The tag filter still holds the tree; keep the original row for
+		// when it clears too.
+		restore = m.selectedPath()
+	} else {
+		m.treeFilterRestorePath = ""
+	}
+	m.rebuildTreeKeep(restore)
+	m.status = "Filter cleared"
+}
+
+// cachedTitleForPath returns the frontmatter title recorded in the tree
+// metadata cache during the last tree build, or "" if unknown.
+func (m *Model) cachedTitleForPath(path string) string {
+	return m.treeMetadataCache[path].title
+}
+
+// buildFilteredTree returns the rows that survive a tree filter query and
+// tag filter.
+//
+// Every file or folder whose name, or markdown frontmatter title (via the
+// title callback), contains query case-insensitively is kept, together with
+// all of its ancestor folders so it stays reachable. A non-empty tag further
+// limits matches to files carrying that tag; folders then only appear as
+// ancestors. The walk ignores the
+// saved expansion state; ancestors of matches are shown open. Row order
+// follows the normal tree sort.
+func buildFilteredTree(root, query, tag string, order treeOrder, pinned map[string]bool, metadata func(path string, info os.FileInfo) []string, title func(path string) string) []treeItem {
+	expandAll := map[string]bool{}
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != root && shouldSkipManagedPath(entry.Name()) {
+			return filepath.SkipDir
+		}
+		expandAll[path] = true
+		return nil
+	})
+	if err != nil {
+		appLog.Warn("walk tree for filter", "root", root, "error", err)
+	}
+
+	all := buildTreeWithMetadataCache(root, expandAll, order, pinned, metadata)
+	needle := strings.ToLower(query)
+	keep := map[string]bool{}
+	for _, item := range all {
+		matched := strings.Contains(strings.ToLower(item.name), needle)
+		if !matched && !item.isDir && title != nil {
+			matched = strings.Contains(strings.ToLower(title(item.path)), needle)
+		}
+		if tag != "" && (item.isDir || !hasTag(item.tags, tag)) {
+			matched = false
+		}
+		if !matched {
+			continue
+		}
+		for path := item.path; path != root && !keep[path]; path = filepath.Dir(path) {
+			keep[path] = true
+			if filepath.Dir(path) == path {
+				break
+			}
+		}
+	}
+
+	items := make([]treeItem, 0, len(keep))
+	for _, item := range all {
+		if keep[item.path] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
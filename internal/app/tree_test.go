@@ -4,7 +4,14 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/treykane/cli-notes/internal/config"
 )
 
 func TestSearchTreeItemsMatchesNamesAndMarkdownContent(t *testing.T) {
@@ -96,6 +103,52 @@ func TestBuildTreeCollapsedDirectoryExcludesChildren(t *testing.T) {
 	expectNotContains(t, got, filepath.Join("Docs", "Guide.md"))
 }
 
+func TestBuildTreeEqualModTimeOrdersByNameDeterministically(t *testing.T) {
+	root := t.TempDir()
+	stamp := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, name := range []string{"charlie.md", "Alpha.md", "bravo.md", "alpha.md"} {
+		path := filepath.Join(root, name)
+		mustWriteFile(t, path, "same\n")
+		if err := os.Chtimes(path, stamp, stamp); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 4 {
+		// Case-insensitive filesystems collapse Alpha.md/alpha.md.
+		t.Skip("filesystem is case-insensitive")
+	}
+
+	expanded := map[string]bool{root: true}
+	for _, mode := range []sortMode{sortModeModified, sortModeSize} {
+		want := []string{"Alpha.md", "alpha.md", "bravo.md", "charlie.md"}
+		for i := 0; i < 5; i++ {
+			got := relPaths(root, buildTree(root, expanded, mode, nil))
+			if !slices.Equal(got, want) {
+				t.Fatalf("mode %s: unexpected order.\nwant: %v\ngot:  %v", mode, want, got)
+			}
+		}
+
+		desc := relPaths(root, buildTreeWithMetadataCache(root, expanded, treeOrder{mode: mode, tiebreak: sortTiebreakNameDesc}, nil, nil))
+		wantDesc := []string{"charlie.md", "bravo.md", "alpha.md", "Alpha.md"}
+		if !slices.Equal(desc, wantDesc) {
+			t.Fatalf("mode %s: unexpected descending tiebreak order.\nwant: %v\ngot:  %v", mode, wantDesc, desc)
+		}
+	}
+}
+
+func TestParseSortTiebreakFallsBackToName(t *testing.T) {
+	if got := parseSortTiebreak("name_desc"); got != sortTiebreakNameDesc {
+		t.Fatalf("expected name_desc, got %s", got)
+	}
+	if got := parseSortTiebreak("unknown"); got != sortTiebreakName {
+		t.Fatalf("expected name fallback, got %s", got)
+	}
+}
+
 func mustWriteFile(t *testing.T, path, content string) {
 	t.Helper()
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -143,3 +196,215 @@ func expectNotContains(t *testing.T, paths map[string]bool, rel string) {
 		t.Fatalf("did not expect %q in search results; got %v", rel, paths)
 	}
 }
+
+func TestBuildFilteredTreeKeepsMatchesAndAncestors(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "Projects", "deep", "Rocket.md"), "r\n")
+	mustWriteFile(t, filepath.Join(root, "Projects", "Other.md"), "o\n")
+	mustWriteFile(t, filepath.Join(root, "Journal", "day.md"), "---\ntitle: Rocket launch log\n---\nbody\n")
+	mustWriteFile(t, filepath.Join(root, "unrelated.md"), "u\n")
+
+	// The metadata callback fills the cache that the title lookup reads.
+	m := &Model{notesDir: root}
+	items := buildFilteredTree(root, "ROCKET", "", treeOrder{mode: sortModeName}, nil, m.cachedTagsForPath, m.cachedTitleForPath)
+	want := []string{
+		"Journal",
+		filepath.Join("Journal", "day.md"),
+		"Projects",
+		filepath.Join("Projects", "deep"),
+		filepath.Join("Projects", "deep", "Rocket.md"),
+	}
+	if got := relPaths(root, items); !slices.Equal(got, want) {
+		t.Fatalf("unexpected filtered tree.\nwant: %v\ngot:  %v", want, got)
+	}
+
+	if items := buildFilteredTree(root, "nothing-here", "", treeOrder{mode: sortModeName}, nil, nil, nil); len(items) != 0 {
+		t.Fatalf("expected no rows, got %v", relPaths(root, items))
+	}
+}
+
+func TestTreeFilterModeNarrowsKeepsAndRestores(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "alpha.md"), "a\n")
+	mustWriteFile(t, filepath.Join(root, "Projects", "rocket.md"), "r\n")
+	mustWriteFile(t, filepath.Join(root, "zeta.md"), "z\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	reselectTreeItem(t, m, filepath.Join(root, "zeta.md"))
+	expandedBefore := len(m.expanded)
+
+	m.startTreeFilter()
+	if m.mode != modeTreeFilter {
+		t.Fatalf("expected tree filter mode, got %v", m.mode)
+	}
+	for _, r := range "rock" {
+		m.handleTreeFilterKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	want := []string{"Projects", filepath.Join("Projects", "rocket.md")}
+	if got := relPaths(root, m.items); !slices.Equal(got, want) {
+		t.Fatalf("expected filtered rows %v, got %v", want, got)
+	}
+
+	m.handleTreeFilterKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeBrowse || m.treeFilterQuery != "rock" {
+		t.Fatalf("expected browse mode with filter kept, got mode %v query %q", m.mode, m.treeFilterQuery)
+	}
+
+	// A newly created matching note appears while the filter is active.
+	mustWriteFile(t, filepath.Join(root, "rockets-2.md"), "r2\n")
+	m.refreshTree()
+	if got := relPathSet(root, m.items); !got["rockets-2.md"] || got["alpha.md"] {
+		t.Fatalf("expected refresh to keep filter and show new match, got %v", relPaths(root, m.items))
+	}
+
+	m.handleBrowseKey("esc")
+	if m.treeFilterQuery != "" {
+		t.Fatalf("expected filter cleared, got %q", m.treeFilterQuery)
+	}
+	if got := m.selectedPath(); got != filepath.Join(root, "zeta.md") {
+		t.Fatalf("expected cursor restored to zeta.md, got %q", got)
+	}
+	if len(m.expanded) != expandedBefore || m.expanded[filepath.Join(root, "Projects")] {
+		t.Fatalf("expected expansion state untouched, got %v", m.expanded)
+	}
+}
+
+func TestTagFilterNarrowsTreeAndCombinesWithNameFilter(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "alpha.md"), "---\ntags: [work]\n---\na\n")
+	mustWriteFile(t, filepath.Join(root, "Projects", "rocket.md"), "---\ntags: [Work, space]\n---\nr\n")
+	mustWriteFile(t, filepath.Join(root, "Projects", "garden.md"), "---\ntags: [home]\n---\ng\n")
+	mustWriteFile(t, filepath.Join(root, "Work", "untagged.md"), "u\n")
+	mustWriteFile(t, filepath.Join(root, "zeta.md"), "z\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.loadKeybindings(config.Config{})
+	reselectTreeItem(t, m, filepath.Join(root, "zeta.md"))
+
+	_, _ = m.handleBrowseKey("alt+t")
+	if !m.isOverlay(overlayTagFilter) || !slices.Equal(m.tagFilterTags, []string{"home", "space", "work"}) {
+		t.Fatalf("expected tag picker with indexed tags, overlay %v tags %v", m.overlay, m.tagFilterTags)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	want := []string{"Projects", filepath.Join("Projects", "rocket.md"), "alpha.md"}
+	if got := relPaths(root, m.items); m.tagFilter != "work" || !slices.Equal(got, want) {
+		t.Fatalf("expected rows %v for #work, got %v (filter %q)", want, got, m.tagFilter)
+	}
+	if seg := m.tagFilterFooterSegment(); seg != "TAG #work" {
+		t.Fatalf("footer segment = %q", seg)
+	}
+
+	m.treeFilterQuery = "rock"
+	m.rebuildTreeKeep(m.selectedPath())
+	if got := relPaths(root, m.items); !slices.Equal(got, want[:2]) {
+		t.Fatalf("expected both filters to apply, got %v", got)
+	}
+	m.handleBrowseKey("esc")
+	if m.treeFilterQuery != "" || m.tagFilter != "work" || len(m.items) != 3 {
+		t.Fatalf("expected Esc to clear the name filter first, query %q tag %q rows %v", m.treeFilterQuery, m.tagFilter, relPaths(root, m.items))
+	}
+	m.handleBrowseKey("esc")
+	if m.tagFilter != "" || !relPathSet(root, m.items)["zeta.md"] {
+		t.Fatalf("expected the tag filter cleared, got %q rows %v", m.tagFilter, relPaths(root, m.items))
+	}
+	if got := m.selectedPath(); got != filepath.Join(root, "zeta.md") {
+		t.Fatalf("expected cursor restored to zeta.md, got %q", got)
+	}
+}
+
+func TestFormatCompactSize(t *testing.T) {
+	cases := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0B"},
+		{512, "512B"},
+		{1023, "1023B"},
+		{1024, "1.0K"},
+		{1229, "1.2K"},
+		{5 * 1024 * 1024, "5.0M"},
+		{2 * 1024 * 1024 * 1024, "2.0G"},
+	}
+	for _, tc := range cases {
+		if got := formatCompactSize(tc.in); got != tc.want {
+			t.Fatalf("formatCompactSize(%d): expected %q, got %q", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestTreeRowShowsRightAlignedSizeWhenEnabled(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "big.md"), strings.Repeat("x", 1229))
+	mustWriteFile(t, filepath.Join(root, "sub", "inner.md"), "x")
+
+	items := buildTree(root, map[string]bool{root: true}, sortModeName, nil)
+	var note, dir treeItem
+	for _, item := range items {
+		switch item.name {
+		case "big.md":
+			note = item
+		case "sub":
+			dir = item
+		}
+	}
+	if note.size != 1229 {
+		t.Fatalf("expected tree walk to record size 1229, got %d", note.size)
+	}
+
+	m := &Model{expanded: map[string]bool{}}
+	const width = 40
+	if row := ansi.Strip(m.renderTreeRow(note, false, width)); strings.Contains(row, "1.2K") {
+		t.Fatalf("expected no size column when disabled, got %q", row)
+	}
+
+	m.toggleFileSizes()
+	for _, selected := range []bool{false, true} {
+		row := m.renderTreeRow(note, selected, width)
+		plain := strings.TrimRight(ansi.Strip(row), " ")
+		if !strings.HasSuffix(plain, "1.2K") {
+			t.Fatalf("selected=%v: expected row to end with size, got %q", selected, plain)
+		}
+		if got := lipgloss.Width(row); got != width {
+			t.Fatalf("selected=%v: expected row width %d, got %d", selected, width, got)
+		}
+	}
+	withSizes := m.renderTreeRow(dir, false, width)
+	m.toggleFileSizes()
+	if withoutSizes := m.renderTreeRow(dir, false, width); withSizes != withoutSizes {
+		t.Fatalf("expected folder rows to be unaffected, got %q vs %q", withSizes, withoutSizes)
+	}
+}
+
+func TestSetAllExpandedOpensEveryFolderAndCollapsesToRoot(t *testing.T) {
+	root := t.TempDir()
+	deep := filepath.Join(root, "a", "b", "c")
+	note := filepath.Join(deep, "n.md")
+	mustWriteFile(t, note, "# N\n")
+	mustWriteFile(t, filepath.Join(root, "z", "y", "x.md"), "# X\n")
+	mustWriteFile(t, filepath.Join(root, ".cli-notes", "trash", "old.md"), "# Old\n")
+	mustWriteFile(t, filepath.Join(root, ArchiveDirName, "gone", "g.md"), "# G\n")
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+
+	m.setAllExpanded(true)
+	if m.status != "Expanded 5 folders" {
+		t.Fatalf("status = %q", m.status)
+	}
+	assertTreeHasPath(t, m.items, note)
+	assertTreeHasPath(t, m.items, filepath.Join(root, "z", "y", "x.md"))
+	if m.expanded[filepath.Join(root, ".cli-notes")] || m.expanded[filepath.Join(root, ArchiveDirName, "gone")] {
+		t.Fatalf("expected managed and hidden archive folders to stay closed: %v", m.expanded)
+	}
+
+	selectTreePath(t, m, note)
+	m.setAllExpanded(false)
+	assertTreeHasPath(t, m.items, note, false)
+	if len(m.expanded) != 1 || !m.expanded[root] {
+		t.Fatalf("expected only the root expanded, got %v", m.expanded)
+	}
+	if got := m.selectedPath(); got != filepath.Join(root, "a") {
+		t.Fatalf("expected cursor on the top-level ancestor, got %q", got)
+	}
+}
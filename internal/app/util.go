@@ -1,7 +1,7 @@
 // util.go provides small, general-purpose helper functions used throughout the
 // app package. These include ANSI-aware string truncation, fixed-size block
-// padding for the TUI layout, numeric clamping, render-width bucketing, and
-// filesystem helpers for managed-path detection and file creation-time
+// padding for the TUI layout, numeric clamping, render-width bucketing,
+// human-readable byte sizes, and filesystem helpers for managed-path detection and file creation-time
 // resolution.
 //
 // None of the helpers in this file hold or mutate Model state — they are pure
@@ -10,7 +10,9 @@
 package app
 
 import (
+	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -24,6 +26,13 @@ import (
 // filesystem watching so its contents never appear as user-visible notes.
 const managedNotesDirName = ".cli-notes"
 
+// appNow is the clock for features whose tests pin or script the time, such
+// as note ages. New features that need a replaceable clock use it rather than
+// a clock of their own; tests replace it with withFixedNow or stubNow
+// (helpers_test.go). Elapsed-time measurements and timestamps no test pins
+// read time.Now directly.
+var appNow = time.Now
+
 // truncate fits a string to the given terminal width, accounting for ANSI
 // escape sequences that take up zero visible columns. If the string already
 // fits, it is returned unchanged. This is used extensively in the View layer
@@ -96,6 +105,23 @@ func roundWidthToNearestBucket(width int) int {
 	return (width / RenderWidthBucket) * RenderWidthBucket
 }
 
+// formatByteSize renders a byte count with a binary unit (B, KB, MB, GB),
+// using one decimal place above bytes, e.g. "1.8 MB".
+func formatByteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n) / unit
+	for _, suffix := range []string{"KB", "MB"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f %s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1f GB", value)
+}
+
 // hasSuffixCaseInsensitive checks whether value ends with suffix, ignoring
 // case differences. Used primarily to identify markdown files (".md") regardless
 // of whether the user named them with uppercase or mixed-case extensions.
@@ -103,6 +129,16 @@ func hasSuffixCaseInsensitive(value, suffix string) bool {
 	return strings.HasSuffix(strings.ToLower(value), strings.ToLower(suffix))
 }
 
+// sortedSet returns the members of set in sorted order.
+func sortedSet(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for key := range set {
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out
+}
+
 // shouldSkipManagedPath reports whether the given directory entry name is the
 // internal managed directory (.cli-notes). This is checked during tree walks,
 // search indexing, and filesystem watching to exclude app-internal files from
@@ -44,11 +44,35 @@ func (m *Model) renderWorkspacePopupOverlay(width, height int) string {
 // renderExportPopupOverlay sizes and centers the export format popup.
 func (m *Model) renderExportPopupOverlay(width, height int) string {
 	popupWidth := min(52, max(40, width-SearchPopupPadding))
-	popupHeight := min(12, max(ExportPopupHeight, height-4))
+	popupHeight := min(16, max(ExportPopupHeight, height-4))
 	popup := m.renderExportPopup(popupWidth, popupHeight)
 	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
 }
 
+// renderHeadingCasePopupOverlay sizes and centers the heading case popup.
+func (m *Model) renderHeadingCasePopupOverlay(width, height int) string {
+	popupWidth := min(52, max(40, width-SearchPopupPadding))
+	popupHeight := min(12, max(HeadingCasePopupHeight, height-4))
+	popup := m.renderHeadingCasePopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderAgendaPopupOverlay sizes and centers the agenda popup.
+func (m *Model) renderAgendaPopupOverlay(width, height int) string {
+	popupWidth := min(80, max(50, width-SearchPopupPadding))
+	popupHeight := min(24, max(AgendaPopupHeight, height-4))
+	popup := m.renderAgendaPopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderKeymapPopupOverlay sizes and centers the keybindings popup.
+func (m *Model) renderKeymapPopupOverlay(width, height int) string {
+	popupWidth := min(80, max(50, width-SearchPopupPadding))
+	popupHeight := min(24, max(KeymapPopupHeight, height-4))
+	popup := m.renderKeymapPopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
 // renderWikiLinksPopupOverlay sizes and centers the wiki-links popup.
 func (m *Model) renderWikiLinksPopupOverlay(width, height int) string {
 	popupWidth := min(90, max(52, width-SearchPopupPadding))
@@ -57,6 +81,103 @@ func (m *Model) renderWikiLinksPopupOverlay(width, height int) string {
 	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
 }
 
+// renderIssuesPopupOverlay sizes and centers the current-note issues popup.
+func (m *Model) renderIssuesPopupOverlay(width, height int) string {
+	popupWidth := min(90, max(52, width-SearchPopupPadding))
+	popupHeight := min(20, max(IssuesPopupHeight, height-4))
+	popup := m.renderIssuesPopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderMetadataPopupOverlay sizes and centers the frontmatter metadata popup.
+func (m *Model) renderMetadataPopupOverlay(width, height int) string {
+	popupWidth := min(80, max(50, width-SearchPopupPadding))
+	popupHeight := min(20, max(MetadataPopupHeight, height-4))
+	popup := m.renderMetadataPopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderGitPanelOverlay sizes and centers the git panel popup.
+func (m *Model) renderGitPanelOverlay(width, height int) string {
+	popupWidth := min(80, max(50, width-SearchPopupPadding))
+	popupHeight := min(24, max(GitPanelPopupHeight, height-4))
+	popup := m.renderGitPanel(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderSavedViewsPopupOverlay sizes and centers the saved-views popup.
+func (m *Model) renderSavedViewsPopupOverlay(width, height int) string {
+	popupWidth := min(80, max(48, width-SearchPopupPadding))
+	popupHeight := min(18, max(SavedViewsPopupHeight, height-4))
+	popup := m.renderSavedViewsPopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderTagBrowserPopupOverlay sizes and centers the tag browser popup.
+func (m *Model) renderTagBrowserPopupOverlay(width, height int) string {
+	popupWidth := min(60, max(40, width-SearchPopupPadding))
+	popupHeight := min(24, max(TagBrowserPopupHeight, height-4))
+	popup := m.renderTagBrowserPopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderCommandPaletteOverlay sizes and centers the command palette.
+func (m *Model) renderCommandPaletteOverlay(width, height int) string {
+	popupWidth := min(80, max(50, width-SearchPopupPadding))
+	popupHeight := min(24, max(CommandPalettePopupHeight, height-4))
+	popup := m.renderCommandPalette(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderMaintenancePopupOverlay sizes and centers the maintenance popup.
+func (m *Model) renderMaintenancePopupOverlay(width, height int) string {
+	popupWidth := min(80, max(48, width-SearchPopupPadding))
+	popup := m.renderMaintenancePopup(popupWidth, min(MaintenancePopupHeight, max(6, height-4)))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderTagFilterPopupOverlay sizes and centers the tag filter popup.
+func (m *Model) renderTagFilterPopupOverlay(width, height int) string {
+	popupWidth := min(60, max(40, width-SearchPopupPadding))
+	popupHeight := min(20, max(TagFilterPopupHeight, height-4))
+	popup := m.renderTagFilterPopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderTrashPopupOverlay sizes and centers the trash popup.
+func (m *Model) renderTrashPopupOverlay(width, height int) string {
+	popupWidth := min(90, max(52, width-SearchPopupPadding))
+	popupHeight := min(20, max(TrashPopupHeight, height-4))
+	popup := m.renderTrashPopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderNoteStatsPopupOverlay sizes and centers the note stats popup.
+func (m *Model) renderNoteStatsPopupOverlay(width, height int) string {
+	popupWidth := min(70, max(44, width-SearchPopupPadding))
+	popupHeight := min(20, max(NoteStatsPopupHeight, height-4))
+	popup := m.renderNoteStatsPopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderGitDiffPopupOverlay sizes and centers the git diff popup, using most
+// of the screen so long diffs stay readable.
+func (m *Model) renderGitDiffPopupOverlay(width, height int) string {
+	popupWidth := min(120, max(52, width-SearchPopupPadding))
+	popupHeight := max(GitDiffPopupHeight, height-4)
+	popup := m.renderGitDiffPopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// renderGitLogPopupOverlay sizes and centers the git history popup, which
+// also hosts the rendered revision and so uses most of the screen.
+func (m *Model) renderGitLogPopupOverlay(width, height int) string {
+	popupWidth := min(120, max(52, width-SearchPopupPadding))
+	popupHeight := max(GitLogPopupHeight, height-4)
+	popup := m.renderGitLogPopup(popupWidth, popupHeight)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, popup)
+}
+
 // renderWikiAutocompletePopupOverlay sizes and bottom-aligns the wiki autocomplete popup.
 func (m *Model) renderWikiAutocompletePopupOverlay(width, height int) string {
 	popupWidth := min(70, max(42, width-SearchPopupPadding))
@@ -101,7 +222,7 @@ func (m *Model) renderSearchPopup(width, height int) string {
 			lines = append(lines, mutedStyle.Render(fmt.Sprintf("%d of %d", m.searchResultCursor+1, len(m.searchResults))))
 		}
 	}
-	lines = append(lines, mutedStyle.Render("Enter: jump  Esc: close"))
+	lines = append(lines, mutedStyle.Render("Enter: jump  Ctrl+S: save view  Esc: close"))
 
 	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
 	return popupStyle.Width(width).Height(height).Render(content)
@@ -157,6 +278,38 @@ func (m *Model) renderOutlinePopup(width, height int) string {
 	return popupStyle.Width(width).Height(height).Render(content)
 }
 
+// renderMetadataPopup draws the frontmatter key/value list, scrolling so the
+// selected row stays visible.
+func (m *Model) renderMetadataPopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	lines := []string{
+		titleStyle.Render("Note Metadata"),
+		"",
+	}
+	keyWidth := 0
+	for _, field := range m.metadataFields {
+		keyWidth = max(keyWidth, len([]rune(field.Key)))
+	}
+	limit := max(0, innerHeight-len(lines)-1)
+	start := 0
+	if limit > 0 {
+		start = max(0, m.metadataCursor-limit+1)
+	}
+	for i := start; i < min(start+limit, len(m.metadataFields)); i++ {
+		field := m.metadataFields[i]
+		key := field.Key + strings.Repeat(" ", keyWidth-len([]rune(field.Key)))
+		line := truncate(fmt.Sprintf("%s  %s", key, field.Value), innerWidth)
+		if i == m.metadataCursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, mutedStyle.Render("↑/↓: scroll  Esc: close"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
+
 // renderWorkspacePopup draws the workspace chooser popup.
 func (m *Model) renderWorkspacePopup(width, height int) string {
 	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
@@ -166,11 +319,17 @@ func (m *Model) renderWorkspacePopup(width, height int) string {
 		"",
 	}
 	limit := max(0, innerHeight-len(lines)-1)
-	for i := 0; i < min(limit, len(m.workspaces)); i++ {
-		ws := m.workspaces[i]
+	list := m.workspaceList()
+	for i := 0; i < min(limit, len(list)); i++ {
+		ws := list[i]
 		label := ws.Name + "  (" + ws.NotesDir + ")"
+		if i < 9 {
+			label = fmt.Sprintf("%d %s", i+1, label)
+		}
 		if ws.Name == m.activeWorkspace {
 			label = "* " + label
+		} else {
+			label = "  " + label
 		}
 		label = truncate(label, innerWidth)
 		if i == m.workspaceCursor {
@@ -178,18 +337,18 @@ func (m *Model) renderWorkspacePopup(width, height int) string {
 		}
 		lines = append(lines, label)
 	}
-	lines = append(lines, mutedStyle.Render("Enter: switch  Esc: close"))
+	lines = append(lines, mutedStyle.Render("Enter/1-9: switch  Alt+↑/↓: move  a: add  d: remove  Esc: close"))
 	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
 	return popupStyle.Width(width).Height(height).Render(content)
 }
 
-// renderExportPopup draws the export format chooser with HTML and PDF options.
+// renderExportPopup draws the export format chooser.
 func (m *Model) renderExportPopup(width, height int) string {
 	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
 	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
-	options := []string{"HTML", "PDF (pandoc)"}
+	options := exportOptions
 	lines := []string{
-		titleStyle.Render("Export Note"),
+		titleStyle.Render("Export"),
 		"",
 	}
 	for i, opt := range options {
@@ -205,6 +364,27 @@ func (m *Model) renderExportPopup(width, height int) string {
 	return popupStyle.Width(width).Height(height).Render(content)
 }
 
+// renderHeadingCasePopup draws the title/sentence case chooser.
+func (m *Model) renderHeadingCasePopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	lines := []string{
+		titleStyle.Render("Convert Headings"),
+		"",
+	}
+	for i, opt := range headingCaseOptions {
+		line := truncate(opt, innerWidth)
+		if i == m.headingCaseCursor {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "")
+	lines = append(lines, mutedStyle.Render("Enter: convert  Esc: cancel"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
+
 // renderTemplatePicker draws the template selection list shown during the new-note flow.
 func (m *Model) renderTemplatePicker(width, height int) string {
 	lines := []string{
@@ -251,3 +431,41 @@ func (m *Model) updateLayout() {
 	layout := m.calculateLayout()
 	m.applyLayout(layout)
 }
+
+// renderAgendaPopup draws the agenda grouped by day, scrolled so the
+// selected entry stays visible.
+func (m *Model) renderAgendaPopup(width, height int) string {
+	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
+	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	start, end := agendaRangeBounds(m.agendaRange, appNow())
+	lines := []string{
+		titleStyle.Render(truncate("Agenda: "+agendaRangeTitle(m.agendaRange, start, end), innerWidth)),
+		"",
+	}
+
+	var body []string
+	cursorLine := 0
+	index := 0
+	for _, day := range groupAgenda(m.agendaEntries) {
+		body = append(body, mutedStyle.Render(day.day.Format("Mon Jan 2")))
+		for _, entry := range day.entries {
+			label := truncate("  "+agendaEntryLabel(entry), innerWidth)
+			if index == m.agendaCursor {
+				label = selectedStyle.Render(label)
+				cursorLine = len(body)
+			}
+			body = append(body, label)
+			index++
+		}
+	}
+	if len(body) == 0 {
+		body = append(body, mutedStyle.Render("No dated notes"))
+	}
+	limit := max(1, innerHeight-len(lines)-2)
+	offset := clamp(cursorLine-limit+1, 0, max(0, len(body)-limit))
+	lines = append(lines, body[offset:min(len(body), offset+limit)]...)
+	lines = append(lines, "")
+	lines = append(lines, mutedStyle.Render("Tab: range  Enter: open  Esc: close"))
+	content := padBlock(strings.Join(lines, "\n"), innerWidth, innerHeight)
+	return popupStyle.Width(width).Height(height).Render(content)
+}
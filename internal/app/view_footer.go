@@ -2,10 +2,12 @@ package app
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/treykane/cli-notes/internal/config"
 )
 
 func (m *Model) renderStatus(width, rows int) string {
@@ -26,28 +28,85 @@ func (m *Model) renderStatus(width, rows int) string {
 	return strings.Join(rendered, "\n")
 }
 
+// footerSegment is one footer entry and its kind (config.FooterSegment*).
+type footerSegment struct {
+	kind string
+	text string
+}
+
+// buildStatusRows packs the footer segments into at most rowLimit rows of
+// width columns, reporting whether everything fit. When it does not and
+// footer_priority is set, segments of unlisted kinds are dropped (help hints
+// first, from the end) until the rest fits, so the listed kinds survive.
 func (m *Model) buildStatusRows(width, rowLimit int) ([]string, bool) {
 	if width <= 0 || rowLimit <= 0 {
 		return nil, true
 	}
 
-	help := m.statusHelpSegments()
-	context := m.statusContextSegments()
-	status := m.statusMessageSegment()
+	var segments []footerSegment
+	for _, text := range m.statusHelpSegments() {
+		segments = append(segments, footerSegment{config.FooterSegmentHelp, text})
+	}
+	for _, text := range m.statusContextSegments() {
+		segments = append(segments, footerSegment{config.FooterSegmentContext, text})
+	}
+	if git := m.gitFooterSummary(); git != "" {
+		segments = append(segments, footerSegment{config.FooterSegmentGit, git})
+	}
+	if status := m.statusMessageSegment(); status != "" {
+		segments = append(segments, footerSegment{config.FooterSegmentStatus, status})
+	}
 
-	segments := make([]string, 0, len(help)+len(context)+2)
-	if len(help) > 0 {
-		segments = append(segments, "Keys: "+help[0])
-		segments = append(segments, help[1:]...)
+	rows, fit := packStatusRows(labelFooterSegments(segments), width, rowLimit)
+	if fit || len(m.footerPriority) == 0 {
+		return rows, fit
 	}
-	if len(context) > 0 {
-		segments = append(segments, "Context: "+context[0])
-		segments = append(segments, context[1:]...)
+	for _, kind := range []string{config.FooterSegmentHelp, config.FooterSegmentContext, config.FooterSegmentStatus, config.FooterSegmentGit} {
+		if slices.Contains(m.footerPriority, kind) {
+			continue
+		}
+		for i := len(segments) - 1; i >= 0; i-- {
+			if segments[i].kind != kind {
+				continue
+			}
+			segments = slices.Delete(segments, i, i+1)
+			if packed, ok := packStatusRows(labelFooterSegments(segments), width, rowLimit); ok {
+				return packed, false
+			}
+		}
 	}
-	if status != "" {
-		segments = append(segments, "Status: "+status)
+	rows, _ = packStatusRows(labelFooterSegments(segments), width, rowLimit)
+	return rows, false
+}
+
+// labelFooterSegments returns the segment texts with "Keys: " before the
+// first help hint, "Context: " before the first context or git segment, and
+// "Status: " before the status message.
+func labelFooterSegments(segments []footerSegment) []string {
+	out := make([]string, 0, len(segments))
+	keys, context := false, false
+	for _, seg := range segments {
+		text := seg.text
+		switch seg.kind {
+		case config.FooterSegmentHelp:
+			if !keys {
+				text, keys = "Keys: "+text, true
+			}
+		case config.FooterSegmentContext, config.FooterSegmentGit:
+			if !context {
+				text, context = "Context: "+text, true
+			}
+		case config.FooterSegmentStatus:
+			text = "Status: " + text
+		}
+		out = append(out, text)
 	}
+	return out
+}
 
+// packStatusRows joins segments with " | " into at most rowLimit rows,
+// truncating with an ellipsis and reporting false when they do not fit.
+func packStatusRows(segments []string, width, rowLimit int) ([]string, bool) {
 	rows := make([]string, 1, rowLimit)
 	rowIndex := 0
 	fit := true
@@ -115,17 +174,40 @@ func (m *Model) statusHelpSegments() []string {
 			"Alt+X strike",
 			"Ctrl+K link",
 			"Ctrl+1..3 heading",
+			"Ctrl+T table",
+			"Alt+T format table",
+			"Alt+= calc",
+			"Alt++ sum",
+			"Alt+D checkbox",
+			"Ctrl+C copy",
+			"Ctrl+X cut",
 			"Ctrl+V paste",
 			"Esc cancel",
 		}
-	case modeNewNote, modeNewFolder, modeRenameItem, modeMoveItem, modeGitCommit:
+	case modeImport:
+		return []string{"Enter/Ctrl+S import", "Tab all files/markdown", "Esc cancel"}
+	case modeNewNote, modeNewFolder, modeRenameItem, modeMoveItem, modeDuplicateItem, modeAddWorkspace, modeExportFolder, modeGitCommit, modeEditTags, modeNotePassphrase, modeSaveView, modeRenameTag, modeStateTransfer:
 		return []string{"Enter/Ctrl+S save", "Esc cancel"}
+	case modeFrontmatterForm:
+		return []string{"Frontmatter", "↑/↓ move", "Ctrl+N add key", "Ctrl+D remove", "Ctrl+S save", "Esc cancel"}
+	case modeInbox:
+		return []string{"Inbox", "Enter apply", "Tab skip", "Esc stop"}
+	case modeTreeFilter:
+		return []string{"Tree filter", "type", "↑/↓ move", "Enter keep", "Esc clear"}
 	case modeTemplatePicker:
-		return []string{"Template picker", "↑/↓ move", "Enter choose", "Esc cancel"}
+		return m.popupFooterHints(popupTemplates)
+	case modeEditTemplate:
+		return []string{"Template", "Ctrl+S save", "Esc back"}
 	case modeDraftRecovery:
 		return []string{"Draft recovery", "y recover", "n discard", "Esc skip all"}
+	case modeEditConflict:
+		return []string{"Changed on disk", "o overwrite", "r reload", "c save copy", "Esc keep editing"}
+	case modeImportConflict:
+		return []string{"Import conflict", "o overwrite", "r rename", "s skip", "O/R/S all", "Esc cancel"}
 	case modeConfirmDelete:
 		return []string{"y confirm delete", "n/Esc cancel"}
+	case modeConfirm:
+		return m.confirmFooterHints()
 	default:
 		if m.showHelp {
 			return []string{
@@ -136,21 +218,11 @@ func (m *Model) statusHelpSegments() []string {
 				"? close",
 			}
 		}
-		switch m.overlay {
-		case overlaySearch:
-			return []string{"Search popup", "type", "↑/↓ move", "Enter jump", "Esc cancel"}
-		case overlayRecent:
-			return []string{"Recent popup", "↑/↓ move", "Enter jump", "Esc cancel"}
-		case overlayOutline:
-			return []string{"Outline popup", "↑/↓ move", "Enter jump", "Esc cancel"}
-		case overlayWorkspace:
-			return []string{"Workspace popup", "↑/↓ move", "Enter switch", "Esc cancel"}
-		case overlayExport:
-			return []string{"Export popup", "↑/↓ move", "Enter export", "Esc cancel"}
-		case overlayWikiLinks:
-			return []string{"Wiki links popup", "↑/↓ move", "Enter jump", "Esc cancel"}
-		case overlayWikiAutocomplete:
-			return []string{"Wiki autocomplete", "↑/↓ move", "Tab/Enter insert", "Esc close"}
+		if m.overlay == overlayKeymap && m.keymapCapture != "" {
+			return []string{"Keybindings", "press new key", "Esc cancel"}
+		}
+		if popup := m.activePopup(); popup != "" {
+			return m.popupFooterHints(popup)
 		}
 		help := []string{
 			fmt.Sprintf("%s up", m.primaryActionKey(actionCursorUp, "↑")),
@@ -162,6 +234,7 @@ func (m *Model) statusHelpSegments() []string {
 			fmt.Sprintf("%s page-up", m.primaryActionKey(actionPreviewScrollPageUp, "PgUp")),
 			fmt.Sprintf("%s page-down", m.primaryActionKey(actionPreviewScrollPageDown, "PgDn")),
 			fmt.Sprintf("%s search", m.primaryActionKey(actionSearch, "Ctrl+P")),
+			fmt.Sprintf("%s filter", m.primaryActionKey(actionTreeFilter, "/")),
 			fmt.Sprintf("%s recents", m.primaryActionKey(actionRecent, "Ctrl+O")),
 			fmt.Sprintf("%s workspace", m.primaryActionKey(actionWorkspace, "Ctrl+W")),
 			fmt.Sprintf("%s edit", m.primaryActionKey(actionEditNote, "E")),
@@ -187,14 +260,27 @@ func (m *Model) statusHelpSegments() []string {
 }
 
 func (m *Model) statusContextSegments() []string {
-	parts := make([]string, 0, 2)
+	parts := make([]string, 0, 5)
 	if (m.mode == modeBrowse || m.mode == modeEditNote) && m.currentFile != "" {
 		if metrics := m.noteMetricsSummary(); metrics != "" {
 			parts = append(parts, metrics)
+			parts = append(parts, m.noteReadingSegments()...)
 		}
 	}
-	if git := m.gitFooterSummary(); git != "" {
-		parts = append(parts, git)
+	if m.mode == modeBrowse && !m.showHelp {
+		parts = append(parts, m.sortFooterSummary())
+	}
+	if agenda := m.agendaFooterSegment(); agenda != "" {
+		parts = append(parts, agenda)
+	}
+	if focus := m.focusFooterSegment(); focus != "" {
+		parts = append(parts, focus)
+	}
+	if tag := m.tagFilterFooterSegment(); tag != "" {
+		parts = append(parts, tag)
+	}
+	if lock := m.opLockFooterSegment(); lock != "" {
+		parts = append(parts, lock)
 	}
 	return parts
 }
@@ -203,49 +289,130 @@ func (m *Model) statusMessageSegment() string {
 	return strings.TrimSpace(m.status)
 }
 
+// actionHelpEntry is one help-panel row for a remappable action.
+type actionHelpEntry struct {
+	action      string
+	fallback    string // keys shown when the action has no binding
+	description string
+}
+
+// browseActionHelp lists the browse actions shown in the help panel, in
+// order. The describe-key mode (Alt+D) reads descriptions from here too.
+var browseActionHelp = []actionHelpEntry{
+	{actionCursorUp, "↑, K", "Move selection up"},
+	{actionCursorDown, "↓, J, Ctrl+N", "Move selection down"},
+	{actionExpandToggle, "Enter, →, L", "Expand/collapse folder"},
+	{actionCollapse, "←, H", "Collapse folder"},
+	{actionExpandAll, "=", "Expand all folders"},
+	{actionCollapseAll, "-", "Collapse all folders"},
+	{actionJumpTop, "G", "Jump to top"},
+	{actionJumpBottom, "Shift+G", "Jump to bottom"},
+	{actionJumpFirstChild, "[", "Jump to first child of folder"},
+	{actionJumpLastChild, "]", "Jump to last child of folder"},
+	{actionPreviewScrollPageUp, "PgUp", "Scroll preview up one page"},
+	{actionPreviewScrollPageDown, "PgDn", "Scroll preview down one page"},
+	{actionPreviewScrollHalfUp, "Ctrl+U", "Scroll preview up half page"},
+	{actionPreviewScrollHalfDown, "Ctrl+D", "Scroll preview down half page"},
+	{actionSearch, "Ctrl+P", "Open search popup"},
+	{actionSavedViews, "Alt+V", "Open saved search views"},
+	{actionTagBrowser, "Alt+B", "Browse all tags with note counts"},
+	{actionTreeFilter, "/", "Filter tree by name/title (Esc clears)"},
+	{actionTagFilter, "Alt+T", "Filter tree by tag (Esc clears)"},
+	{actionRecent, "Ctrl+O", "Open recent-files popup"},
+	{actionOutline, "O", "Open heading outline popup"},
+	{actionWorkspace, "Ctrl+W", "Open workspace popup"},
+	{actionMaintenance, "Alt+W", "Export or import workspace state"},
+	{actionExport, "X", "Export note (HTML/PDF/text/clipboard) or folder"},
+	{actionHeadingCase, "Shift+H", "Convert headings to title/sentence case"},
+	{actionWikiLinks, "Shift+L", "Open wiki-links popup"},
+	{actionMetadata, "I", "Show frontmatter metadata popup"},
+	{actionMetadataStrip, "Shift+M", "Toggle metadata strip in preview"},
+	{actionNoteStats, "W", "Show/copy note stats"},
+	{actionFocus, "Shift+F", "Start/pause/resume focus session"},
+	{actionFocusCancel, "Alt+F", "Cancel focus session or skip break"},
+	{actionIssueNext, "F8", "Jump to next issue in note"},
+	{actionIssuePrev, "Shift+F8", "Jump to previous issue in note"},
+	{actionIssues, "!", "Open issues popup"},
+	{actionSplitToggle, "Z", "Toggle split mode"},
+	{actionSplitFocus, "Tab", "Toggle split focus"},
+	{actionNewNote, "N", "New note"},
+	{actionNewFolder, "F", "New folder"},
+	{actionEditNote, "E", "Edit note"},
+	{actionDailyNote, "Shift+J", "Open today's journal entry"},
+	{actionDailyPrev, "{", "Previous journal entry"},
+	{actionDailyNext, "}", "Next journal entry"},
+	{actionAgenda, "Shift+C", "Open agenda of dated notes"},
+	{actionImport, "Alt+I", "Import notes from a folder/file"},
+	{actionGitInit, "Alt+G", "Initialize git in notes directory"},
+	{actionTutorial, "F1", "Open the tutorial (Welcome.md)"},
+	{actionRename, "R", "Rename selected item"},
+	{actionMove, "M", "Move selected item"},
+	{actionDuplicate, "Shift+D", "Duplicate selected item"},
+	{actionDelete, "D", "Delete to trash (with confirmation)"},
+	{actionTrash, "Ctrl+T", "Restore from trash"},
+	{actionRefresh, "Ctrl+R, Shift+R", "Refresh"},
+	{actionSort, "S", "Cycle tree sort mode"},
+	{actionSortReverse, "Shift+S", "Reverse tree sort direction"},
+	{actionSortFolder, "Alt+S", "Toggle sort override for folder"},
+	{actionPin, "T", "Pin/unpin selected item"},
+	{actionTreeSizes, "B", "Toggle file sizes in tree"},
+	{actionNoteAge, "Shift+B", "Toggle note age colors in tree"},
+	{actionArchive, "Shift+A", "Archive/restore selected item"},
+	{actionShowArchived, "A", "Show/hide archived notes"},
+	{actionToggleFolder, "Alt+M", "Move note between toggle folders"},
+	{actionInbox, "Shift+I", "Process inbox one item at a time"},
+	{actionEditTags, "#", "Edit tags of selected note"},
+	{actionEditFrontmatter, "Shift+E", "Edit frontmatter as a form"},
+	{actionCopyContent, "Y", "Copy note content"},
+	{actionCopyPath, "Shift+Y", "Copy note path"},
+	{actionEncryptToggle, "Alt+E", "Encrypt/decrypt selected note"},
+	{actionKeymap, "Alt+K", "Remap keybindings"},
+	{actionDescribeKey, "Alt+D", "Show the action bound to the next key"},
+	{actionCommandPalette, ":", "Command palette (run any action)"},
+	{actionHelp, "?", "Toggle help"},
+	{actionQuit, "Q, Ctrl+C", "Quit"},
+}
+
+// gitActionHelp lists the git actions, shown only inside a git repository.
+var gitActionHelp = []actionHelpEntry{
+	{actionGitCommit, "C", "Git add+commit"},
+	{actionGitPull, "P", "Git pull --ff-only"},
+	{actionGitPush, "Shift+P", "Git push"},
+	{actionGitDiff, "V", "Show git diff of current note"},
+	{actionGitLog, "Shift+V", "Browse git history of current note"},
+	{actionGitPanel, "Ctrl+G", "Git panel (changes + actions)"},
+}
+
+// actionHelpLines formats help rows with each action's current keys.
+func (m *Model) actionHelpLines(entries []actionHelpEntry) []string {
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, fmt.Sprintf("  %-24s %s", m.allActionKeys(e.action, e.fallback), e.description))
+	}
+	return lines
+}
+
+// actionDescription returns the help-panel description of action, or "".
+func actionDescription(action string) string {
+	for _, entries := range [][]actionHelpEntry{browseActionHelp, gitActionHelp} {
+		for _, e := range entries {
+			if e.action == action {
+				return e.description
+			}
+		}
+	}
+	return ""
+}
+
 func (m *Model) helpContent() string {
 	lines := []string{
 		titleStyle.Render("Keyboard Shortcuts"),
 		"",
 		"Browse",
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionCursorUp, "↑, K"), "Move selection up"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionCursorDown, "↓, J, Ctrl+N"), "Move selection down"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionExpandToggle, "Enter, →, L"), "Expand/collapse folder"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionCollapse, "←, H"), "Collapse folder"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionJumpTop, "G"), "Jump to top"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionJumpBottom, "Shift+G"), "Jump to bottom"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionPreviewScrollPageUp, "PgUp"), "Scroll preview up one page"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionPreviewScrollPageDown, "PgDn"), "Scroll preview down one page"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionPreviewScrollHalfUp, "Ctrl+U"), "Scroll preview up half page"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionPreviewScrollHalfDown, "Ctrl+D"), "Scroll preview down half page"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionSearch, "Ctrl+P"), "Open search popup"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionRecent, "Ctrl+O"), "Open recent-files popup"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionOutline, "O"), "Open heading outline popup"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionWorkspace, "Ctrl+W"), "Open workspace popup"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionExport, "X"), "Export current note (HTML/PDF)"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionWikiLinks, "Shift+L"), "Open wiki-links popup"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionSplitToggle, "Z"), "Toggle split mode"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionSplitFocus, "Tab"), "Toggle split focus"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionNewNote, "N"), "New note"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionNewFolder, "F"), "New folder"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionEditNote, "E"), "Edit note"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionRename, "R"), "Rename selected item"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionMove, "M"), "Move selected item"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionDelete, "D"), "Delete (with confirmation)"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionRefresh, "Ctrl+R, Shift+R"), "Refresh"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionSort, "S"), "Cycle tree sort mode"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionPin, "T"), "Pin/unpin selected item"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionCopyContent, "Y"), "Copy note content"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionCopyPath, "Shift+Y"), "Copy note path"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionHelp, "?"), "Toggle help"),
-		fmt.Sprintf("  %-24s %s", m.allActionKeys(actionQuit, "Q, Ctrl+C"), "Quit"),
 	}
+	lines = append(lines, m.actionHelpLines(browseActionHelp)...)
 	if m.git.isRepo {
-		lines = append(lines,
-			fmt.Sprintf("  %-24s %s", m.allActionKeys(actionGitCommit, "C"), "Git add+commit"),
-			fmt.Sprintf("  %-24s %s", m.allActionKeys(actionGitPull, "P"), "Git pull --ff-only"),
-			fmt.Sprintf("  %-24s %s", m.allActionKeys(actionGitPush, "Shift+P"), "Git push"),
-		)
+		lines = append(lines, m.actionHelpLines(gitActionHelp)...)
 	}
 	lines = append(lines,
 		"",
@@ -274,7 +441,7 @@ func (m *Model) helpContent() string {
 		"  Enter or Ctrl+S  Save",
 		"  Esc              Cancel",
 		"",
-		"Rename/Move/Git Commit",
+		"Rename/Move/Git Commit/Tags",
 		"  Enter or Ctrl+S  Save",
 		"  Esc              Cancel",
 		"",
@@ -306,7 +473,18 @@ func (m *Model) helpContent() string {
 		"  Alt+X          Toggle ~~strikethrough~~ on selection/word",
 		"  Ctrl+K         Insert [text](url) link template",
 		"  Ctrl+1..3      Toggle # / ## / ### heading on current line",
-		"  Ctrl+V         Paste clipboard text",
+		"                 (with a selection: promote it to its own heading)",
+		"  Ctrl+T         Insert table, or align the table under the cursor",
+		"  Alt+T          Format the table under the cursor",
+		"  Tab/Shift+Tab  Next / previous cell inside a table",
+		"  Enter          Continue a list item; on an empty item, end the list",
+		"  Ctrl+Space     Toggle the task checkbox on the current line (or Alt+D)",
+		"  Tab            Accept wiki autocomplete if open, next cell in a table,",
+		"                 else indent 4 spaces",
+		"  F8 / Shift+F8  Jump to next / previous issue",
+		"  Ctrl+C / Alt+C Copy selection",
+		"  Ctrl+X         Cut selection",
+		"  Ctrl+V         Paste clipboard text (replaces the selection)",
 		"  Esc            Cancel",
 		"",
 		"Help Panel Navigation",
@@ -8,6 +8,21 @@ var overlayRenderers = map[overlayMode]func(*Model, int, int) string{
 	overlayExport:           (*Model).renderExportPopupOverlay,
 	overlayWikiLinks:        (*Model).renderWikiLinksPopupOverlay,
 	overlayWikiAutocomplete: (*Model).renderWikiAutocompletePopupOverlay,
+	overlayIssues:           (*Model).renderIssuesPopupOverlay,
+	overlayMetadata:         (*Model).renderMetadataPopupOverlay,
+	overlayGitPanel:         (*Model).renderGitPanelOverlay,
+	overlayTrash:            (*Model).renderTrashPopupOverlay,
+	overlayNoteStats:        (*Model).renderNoteStatsPopupOverlay,
+	overlayGitDiff:          (*Model).renderGitDiffPopupOverlay,
+	overlayGitLog:           (*Model).renderGitLogPopupOverlay,
+	overlayHeadingCase:      (*Model).renderHeadingCasePopupOverlay,
+	overlayAgenda:           (*Model).renderAgendaPopupOverlay,
+	overlayKeymap:           (*Model).renderKeymapPopupOverlay,
+	overlaySavedViews:       (*Model).renderSavedViewsPopupOverlay,
+	overlayTagFilter:        (*Model).renderTagFilterPopupOverlay,
+	overlayTagBrowser:       (*Model).renderTagBrowserPopupOverlay,
+	overlayMaintenance:      (*Model).renderMaintenancePopupOverlay,
+	overlayCommandPalette:   (*Model).renderCommandPaletteOverlay,
 }
 
 func (m *Model) renderActiveOverlay(width, height int) string {
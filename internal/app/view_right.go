@@ -3,6 +3,7 @@ package app
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -16,7 +17,7 @@ func (m *Model) renderRight(width, height int) string {
 	}
 	rightPaneStyle := previewPane
 	headerStyle := previewHeader
-	if m.mode == modeEditNote {
+	if m.mode == modeEditNote || m.mode == modeEditTemplate {
 		rightPaneStyle = editPane
 		headerStyle = editHeader
 	}
@@ -27,15 +28,19 @@ func (m *Model) renderRight(width, height int) string {
 
 	var content string
 	switch m.mode {
-	case modeEditNote:
-		m.editor.SetWidth(innerWidth)
-		m.editor.SetHeight(contentHeight)
-		content = m.editorViewWithSelectionHighlight(m.editor.View())
+	case modeEditNote, modeEditTemplate:
+		content = m.renderEditor(innerWidth, contentHeight)
 	case modeTemplatePicker:
 		content = m.renderTemplatePicker(innerWidth, contentHeight)
 	case modeDraftRecovery:
 		content = m.renderDraftRecovery(innerWidth, contentHeight)
-	case modeNewNote, modeNewFolder, modeRenameItem, modeMoveItem, modeGitCommit:
+	case modeEditConflict:
+		content = m.renderEditConflict(innerWidth, contentHeight)
+	case modeImportConflict:
+		content = m.renderImportConflict(innerWidth, contentHeight)
+	case modeFrontmatterForm:
+		content = m.renderFrontmatterForm(innerWidth, contentHeight)
+	case modeNewNote, modeNewFolder, modeRenameItem, modeMoveItem, modeDuplicateItem, modeImport, modeAddWorkspace, modeExportFolder, modeGitCommit, modeEditTags, modeInbox, modeNotePassphrase, modeSaveView, modeRenameTag, modeStateTransfer:
 		m.input.Width = innerWidth
 		prompt, location, helper := m.inputModeMeta()
 		content = strings.Join([]string{
@@ -52,10 +57,17 @@ func (m *Model) renderRight(width, height int) string {
 			m.helpViewport.Height = contentHeight
 			m.helpViewport.SetContent(m.helpContent())
 			content = m.helpViewport.View()
+		} else if m.emptyStateVisible() {
+			content = m.renderEmptyState(innerWidth, contentHeight)
 		} else {
 			m.viewport.Width = innerWidth
 			m.viewport.Height = contentHeight
-			content = m.viewport.View()
+			if stripHeight := m.metadataStripHeight(innerWidth); stripHeight > 0 {
+				m.viewport.Height = max(0, contentHeight-stripHeight)
+				content = m.renderMetadataStrip(innerWidth) + "\n" + m.viewport.View()
+			} else {
+				content = m.viewport.View()
+			}
 		}
 	}
 
@@ -67,17 +79,18 @@ func (m *Model) renderRight(width, height int) string {
 func (m *Model) renderRightSplit(width, height int) string {
 	leftWidth := width / 2
 	rightWidth := width - leftWidth
+	primary, secondary := m.splitPaneFiles()
 	return lipgloss.JoinHorizontal(
 		lipgloss.Top,
-		m.renderSingleRightPane(leftWidth, height, m.currentFile, false, !m.splitFocusSecondary),
-		m.renderSingleRightPane(rightWidth, height, m.secondaryFile, true, m.splitFocusSecondary),
+		m.renderSingleRightPane(leftWidth, height, primary, false, !m.splitFocusSecondary),
+		m.renderSingleRightPane(rightWidth, height, secondary, true, m.splitFocusSecondary),
 	)
 }
 
 func (m *Model) renderSingleRightPane(width, height int, path string, secondary bool, focused bool) string {
 	rightPaneStyle := previewPane
 	headerStyle := previewHeader
-	if m.mode == modeEditNote && !secondary {
+	if m.mode == modeEditNote && m.paneHoldsEditor(secondary) {
 		rightPaneStyle = editPane
 		headerStyle = editHeader
 	}
@@ -101,10 +114,11 @@ func (m *Model) renderSingleRightPane(width, height int, path string, secondary
 
 	content := "Select a note to view"
 	if path != "" {
-		if m.mode == modeEditNote && !secondary && path == m.currentFile {
-			m.editor.SetWidth(innerWidth)
-			m.editor.SetHeight(contentHeight)
-			content = m.editorViewWithSelectionHighlight(m.editor.View())
+		editing := m.paneHoldsEditor(secondary) && path == m.currentFile
+		if m.mode == modeEditNote && editing {
+			content = m.renderEditor(innerWidth, contentHeight)
+		} else if m.mode == modeEditConflict && editing {
+			content = m.renderEditConflict(innerWidth, contentHeight)
 		} else if rendered, ok := m.renderedForPath(path, innerWidth); ok {
 			content = m.renderPreviewWithOffset(path, rendered, secondary)
 		}
@@ -120,6 +134,9 @@ func (m *Model) renderedForPath(path string, width int) (string, bool) {
 	if err != nil || info.IsDir() {
 		return "", false
 	}
+	if isEncryptedNotePath(path) {
+		return m.renderedEncryptedForPath(path, width), true
+	}
 	bucket := roundWidthToNearestBucket(width)
 	if entry, ok := m.renderCache[path]; ok && entry.width == bucket && entry.mtime.Equal(info.ModTime()) {
 		return entry.content, true
@@ -154,6 +171,11 @@ func (m *Model) renderPreviewWithOffset(path, rendered string, secondary bool) s
 
 func (m *Model) editorViewWithSelectionHighlight(view string) string {
 	view = highlightFencedCodeInEditorView(view)
+	view = m.highlightEditorSelection(view)
+	return m.highlightEditorActiveLine(view)
+}
+
+func (m *Model) highlightEditorSelection(view string) string {
 	start, end, ok := m.editorSelectionRange()
 	if !ok {
 		return view
@@ -174,10 +196,11 @@ func (m *Model) editorViewWithSelectionHighlight(view string) string {
 	lines := strings.Split(view, "\n")
 	contentStart := m.editorContentStartColumn()
 	for _, span := range spans {
-		if span.row < 0 || span.row >= len(lines) {
+		row := span.row - m.editorScrollTop
+		if row < 0 || row >= len(lines) {
 			continue
 		}
-		lines[span.row] = highlightEditorRowSpan(lines[span.row], contentStart, span.startCol, span.endCol)
+		lines[row] = highlightEditorRowSpan(lines[row], contentStart, span.startCol, span.endCol)
 	}
 	return strings.Join(lines, "\n")
 }
@@ -348,14 +371,43 @@ func (m *Model) inputModeMeta() (string, string, string) {
 		return "Rename selected item", "Current path: " + m.displayRelative(m.actionPath), "Ctrl+S or Enter to save. Esc to cancel."
 	case modeMoveItem:
 		return "Move selected item", "Current path: " + m.displayRelative(m.actionPath), "Enter destination folder path. Esc to cancel."
+	case modeDuplicateItem:
+		return "Duplicate selected item", "Copy of: " + m.displayRelative(m.actionPath), "Ctrl+S or Enter to save. Esc to cancel."
+	case modeImport:
+		return "Import notes", "Into: " + m.displayRelative(m.actionPath), "Copying " + m.importFilterLabel() + " (Tab to toggle); existing files prompt to overwrite, rename, or skip. Ctrl+S or Enter to import. Esc to cancel."
+	case modeAddWorkspace:
+		if m.workspaceDraftName == "" {
+			return "Add workspace", "Step 1 of 2: name", "Ctrl+S or Enter to continue. Esc to cancel."
+		}
+		return "Add workspace", "Name: " + m.workspaceDraftName, "Step 2 of 2: notes directory. Ctrl+S or Enter to add. Esc to cancel."
+	case modeExportFolder:
+		return "Export folder to HTML", "Folder: " + m.displayRelative(m.actionPath), "Output directory for the pages and index.html. Ctrl+S or Enter to export. Esc to cancel."
 	case modeGitCommit:
 		return "Git commit message", "Repository: " + m.notesDir, "Ctrl+S or Enter to commit. Esc to cancel."
+	case modeInbox:
+		return m.inboxModeMeta()
+	case modeNotePassphrase:
+		return m.notePassphraseModeMeta()
+	case modeSaveView:
+		return "Save search view", "Query: " + m.savedViewDraftQuery, "Name for the saved-views popup; an existing name is replaced. Ctrl+S or Enter to save. Esc to cancel."
+	case modeRenameTag:
+		return "Rename tag", fmt.Sprintf("Tag: #%s (%s)", m.renameTagFrom, noteCountLabel(len(m.notesWithTag(m.renameTagFrom)))), "Every note carrying the tag is rewritten after a confirmation. Ctrl+S or Enter to continue. Esc to cancel."
+	case modeStateTransfer:
+		if m.stateImport {
+			return "Import workspace state", "Workspace: " + m.notesDir, "Pins are added, newer positions and higher open counts win, and saved views are added by name; paths missing here are skipped. Ctrl+S or Enter to import. Esc to cancel."
+		}
+		return "Export workspace state", "Workspace: " + m.notesDir, "Pins, reading positions, open counts, and saved views are written as portable JSON. Ctrl+S or Enter to export. Esc to cancel."
+	case modeEditTags:
+		return "Edit note tags", "Note: " + m.displayRelative(m.actionPath), "Comma or space separated. Ctrl+S or Enter to save. Esc to cancel."
 	default:
 		return "New note name", "Location: " + m.displayRelative(m.newParent), "Ctrl+S or Enter to save. Esc to cancel."
 	}
 }
 
 func (m *Model) rightHeaderPath() string {
+	if m.mode == modeEditTemplate {
+		return "Template: " + filepath.Base(m.templateEditPath)
+	}
 	path := "No note selected"
 	if m.currentFile != "" {
 		path = m.displayRelative(m.currentFile)
@@ -3,6 +3,8 @@ package app
 import (
 	"fmt"
 	"strings"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 func (m *Model) renderTree(width, height int) string {
@@ -11,23 +13,18 @@ func (m *Model) renderTree(width, height int) string {
 
 	header := titleStyle.Render("Notes: " + m.notesDir)
 	lines := []string{truncate(header, innerWidth)}
+	if m.mode == modeTreeFilter {
+		lines = append(lines, truncate("/"+m.input.View(), innerWidth))
+	} else if m.treeFiltered() {
+		lines = append(lines, truncate(mutedStyle.Render(m.treeFilterLabel()+" (Esc to clear)"), innerWidth))
+	}
 
 	visibleHeight := max(0, innerHeight-len(lines))
 	start := min(m.treeOffset, max(0, len(m.items)-1))
 	end := min(len(m.items), start+visibleHeight)
 
 	for i := start; i < end; i++ {
-		item := m.items[i]
-		line := m.formatTreeItem(item)
-		if i == m.cursor {
-			line = m.formatTreeItemSelected(item)
-			line = truncate(line, innerWidth)
-			line = selectedStyle.Width(innerWidth).Render(line)
-			lines = append(lines, line)
-			continue
-		}
-		line = truncate(line, innerWidth)
-		lines = append(lines, line)
+		lines = append(lines, m.renderTreeRow(m.items[i], i == m.cursor, innerWidth))
 	}
 	if len(m.items) == 0 {
 		lines = append(lines, truncate(mutedStyle.Render("(no matches)"), innerWidth))
@@ -37,12 +34,72 @@ func (m *Model) renderTree(width, height int) string {
 	return paneStyle.Width(width).Height(height).Render(content)
 }
 
+// renderTreeRow formats one tree row at the given width, appending the
+// right-aligned size column when file sizes are shown.
+func (m *Model) renderTreeRow(item treeItem, selected bool, width int) string {
+	line := m.formatTreeItem(item)
+	if selected {
+		line = m.formatTreeItemSelected(item)
+	}
+	if m.showFileSizes && !item.isDir && hasSuffixCaseInsensitive(item.name, ".md") {
+		size := formatCompactSize(item.size)
+		if !selected {
+			size = mutedStyle.Render(size)
+		}
+		line = alignTrailingColumn(line, size, width)
+	} else {
+		line = truncate(line, width)
+	}
+	if m.pendingOpFor(item.path) != nil {
+		// Still being written (optimistic_ops.go).
+		marker := " …"
+		if !selected {
+			marker = mutedStyle.Render(marker)
+		}
+		line = truncate(line, max(0, width-2)) + marker
+	}
+	if selected {
+		line = selectedStyle.Width(width).Render(line)
+	}
+	return line
+}
+
+// alignTrailingColumn truncates line to leave room for column, then pads so
+// column ends flush with the right edge of width. If the pane is too narrow
+// for both, the column is dropped.
+func alignTrailingColumn(line, column string, width int) string {
+	columnWidth := lipgloss.Width(column)
+	if width <= columnWidth+1 {
+		return truncate(line, width)
+	}
+	line = truncate(line, width-columnWidth-1)
+	gap := width - columnWidth - lipgloss.Width(line)
+	return line + strings.Repeat(" ", gap) + column
+}
+
+// formatCompactSize renders a byte count in the tree's compact form, e.g.
+// "512B", "1.2K", "3.4M".
+func formatCompactSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	value := float64(n) / unit
+	for _, suffix := range []string{"K", "M"} {
+		if value < unit {
+			return fmt.Sprintf("%.1f%s", value, suffix)
+		}
+		value /= unit
+	}
+	return fmt.Sprintf("%.1fG", value)
+}
+
 func (m *Model) formatTreeItem(item treeItem) string {
 	indent := strings.Repeat("  ", item.depth)
 	if item.isDir {
 		expanded := m.expanded[item.path]
 		marker := treeClosedMark.Render("[+]")
-		if expanded || strings.TrimSpace(m.search.Value()) != "" {
+		if expanded || m.treeFiltered() || strings.TrimSpace(m.search.Value()) != "" {
 			marker = treeOpenMark.Render("[-]")
 		}
 		pin := ""
@@ -59,7 +116,11 @@ func (m *Model) formatTreeItem(item treeItem) string {
 	if label := compactTagLabel(item.tags, 2); label != "" {
 		tagBadge = " " + treeTagBadge.Render("TAGS:"+label)
 	}
-	return fmt.Sprintf("%s    %s %s%s%s", indent, treeFileTag.Render("MD"), treeFileName.Render(item.name), pin, tagBadge)
+	badge := treeFileTag.Render("MD")
+	if isEncryptedNotePath(item.path) {
+		badge = treeLockTag.Render("LOCK")
+	}
+	return fmt.Sprintf("%s    %s %s%s%s", indent, badge, m.treeFileNameStyle(item).Render(item.name), pin, tagBadge)
 }
 
 func (m *Model) formatTreeItemSelected(item treeItem) string {
@@ -67,7 +128,7 @@ func (m *Model) formatTreeItemSelected(item treeItem) string {
 	if item.isDir {
 		expanded := m.expanded[item.path]
 		marker := "[+]"
-		if expanded || strings.TrimSpace(m.search.Value()) != "" {
+		if expanded || m.treeFiltered() || strings.TrimSpace(m.search.Value()) != "" {
 			marker = "[-]"
 		}
 		pin := ""
@@ -84,5 +145,9 @@ func (m *Model) formatTreeItemSelected(item treeItem) string {
 	if label := compactTagLabel(item.tags, 2); label != "" {
 		tagBadge = " TAGS:" + label
 	}
-	return fmt.Sprintf("%s    MD %s%s%s", indent, item.name, pin, tagBadge)
+	badge := "MD"
+	if isEncryptedNotePath(item.path) {
+		badge = "LOCK"
+	}
+	return fmt.Sprintf("%s    %s %s%s%s", indent, badge, item.name, pin, tagBadge)
 }
@@ -16,6 +16,9 @@
 //
 // The internal `.cli-notes` managed directory is excluded from the snapshot so
 // draft files, state, and other metadata do not trigger spurious refreshes.
+//
+// When fsnotify is available the event watcher (watcher_events.go) replaces
+// polling and the poll tick stays idle.
 package app
 
 import (
@@ -70,6 +73,11 @@ func (m *Model) effectiveFileWatchInterval() time.Duration {
 // Regardless of outcome, the next poll tick is always scheduled so monitoring
 // continues for the lifetime of the application.
 func (m *Model) handleFileWatchTick(_ fileWatchTickMsg) (tea.Model, tea.Cmd) {
+	// The event watcher is in charge; keep ticking so polling can resume if
+	// it stops. Polling also pauses while the terminal is blurred.
+	if m.fsWatcher != nil || m.terminalBlurred {
+		return m, m.scheduleFileWatchTick()
+	}
 	snapshot, err := scanFileWatchSnapshot(m.notesDir)
 	if err != nil {
 		appLog.Warn("scan filesystem watcher", "root", m.notesDir, "error", err)
@@ -83,8 +91,9 @@ func (m *Model) handleFileWatchTick(_ fileWatchTickMsg) (tea.Model, tea.Cmd) {
 	}
 
 	if !fileWatchSnapshotsEqual(m.fileWatchSnapshot, snapshot) {
+		currentChanged := m.fileWatchSnapshot[m.currentFile] != snapshot[m.currentFile]
 		m.fileWatchSnapshot = snapshot
-		cmd := m.handleExternalFilesystemChange()
+		cmd := m.handleExternalFilesystemChange(currentChanged)
 		return m, tea.Batch(cmd, m.scheduleFileWatchTick())
 	}
 	return m, m.scheduleFileWatchTick()
@@ -171,9 +180,13 @@ func fileWatchSnapshotsEqual(left, right fileWatchSnapshot) bool {
 //  4. Clears the render cache (forces re-render on next view).
 //  5. Re-renders the currently viewed file — unless the user is in edit mode,
 //     in which case we leave the editor buffer untouched to avoid clobbering
-//     unsaved changes.
-//  6. If the currently viewed file was deleted externally, clears the viewport.
-func (m *Model) handleExternalFilesystemChange() tea.Cmd {
+//     unsaved changes and warn if that file changed on disk.
+//  6. If the currently viewed file was deleted externally, clears the viewport
+//     (see reconcileCurrentFileAfterFilesystemChange).
+//
+// currentChanged reports whether the current file itself was added, removed,
+// or modified between the two snapshots.
+func (m *Model) handleExternalFilesystemChange(currentChanged bool) tea.Cmd {
 	m.rememberCurrentNotePosition()
 	_ = m.applyMutationEffects(mutationEffects{
 		saveState:        true,
@@ -183,19 +196,21 @@ func (m *Model) handleExternalFilesystemChange() tea.Cmd {
 	})
 	m.invalidateTreeMetadataCache()
 	m.rebuildRecentEntries()
+	return m.finishExternalChange(currentChanged)
+}
 
-	if m.currentFile != "" {
-		if _, err := os.Stat(m.currentFile); err == nil {
-			if m.mode != modeEditNote {
-				m.status = "Auto-refreshed (external filesystem changes detected)"
-				return m.setCurrentFile(m.currentFile)
-			}
-		} else {
-			m.currentFile = ""
-			m.currentNoteContent = ""
-			m.viewport.SetContent("Select a note to view")
+// finishExternalChange reports an external refresh and re-renders the current
+// note (steps 5 and 6 above), shared by the poller and the event watcher.
+func (m *Model) finishExternalChange(currentChanged bool) tea.Cmd {
+	m.status = "Auto-refreshed (external filesystem changes detected)"
+	if !m.reconcileCurrentFileAfterFilesystemChange() {
+		return nil
+	}
+	if m.mode == modeEditNote {
+		if currentChanged {
+			m.status = "Warning: " + filepath.Base(m.currentFile) + " changed on disk; saving will ask before overwriting"
 		}
+		return nil
 	}
-	m.status = "Auto-refreshed (external filesystem changes detected)"
-	return nil
+	return m.setCurrentFile(m.currentFile)
 }
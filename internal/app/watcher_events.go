@@ -0,0 +1,280 @@
+// watcher_events.go implements event-based monitoring of the notes directory
+// with fsnotify, so a git pull or a sync tool dropping files in refreshes the
+// app without Shift+R.
+//
+// fsnotify watches single directories, so every directory under the notes
+// root is added up front and new directories are added as they appear. The
+// managed `.cli-notes` folder and `.git` are never watched. Events are
+// collected in a goroutine and delivered as one fsEventsMsg after
+// FileEventDebounce of quiet. The model then refreshes only what the batch
+// touched: search index entries, render cache entries, and the tree.
+//
+// The poller in watcher.go remains the fallback: it stays idle while an event
+// watcher is running and takes over when fsnotify cannot start (e.g. inotify
+// limits or unsupported mounts) or the watcher stops. Switching workspaces
+// closes the old watcher and starts one on the new root; batches from a
+// closed watcher are dropped.
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// fsEventWatcher owns one fsnotify watcher for a notes root.
+type fsEventWatcher struct {
+	root    string
+	watcher *fsnotify.Watcher
+	batches chan fsEventsMsg
+	done    chan struct{}
+}
+
+// fsEventsMsg is one debounced batch of changed paths. full is set when the
+// kernel dropped events and only a full refresh is safe.
+type fsEventsMsg struct {
+	watcher *fsEventWatcher
+	paths   []string
+	full    bool
+}
+
+// fsWatcherStoppedMsg reports that a watcher's event stream ended on its own.
+type fsWatcherStoppedMsg struct {
+	watcher *fsEventWatcher
+}
+
+// startFSEventWatcher watches root and every directory below it. It returns
+// an error when fsnotify is unavailable; callers fall back to polling.
+func startFSEventWatcher(root string) (*fsEventWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &fsEventWatcher{
+		root:    root,
+		watcher: watcher,
+		batches: make(chan fsEventsMsg),
+		done:    make(chan struct{}),
+	}
+	if err := w.addTree(root); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+// close stops the watcher. Pending batches are discarded.
+func (w *fsEventWatcher) close() {
+	close(w.done)
+	_ = w.watcher.Close()
+}
+
+// addTree adds dir and all of its subdirectories, skipping ignored folders.
+func (w *fsEventWatcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if path == dir {
+				return walkErr
+			}
+			// A subfolder vanished or is unreadable; keep watching the rest.
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != w.root && ignoreFSEventPath(w.root, path) {
+			return filepath.SkipDir
+		}
+		return w.watcher.Add(path)
+	})
+}
+
+// run collects events until the watcher is closed, delivering a batch once
+// no event has arrived for FileEventDebounce.
+func (w *fsEventWatcher) run() {
+	defer close(w.batches)
+	pending := map[string]struct{}{}
+	full := false
+	timer := time.NewTimer(FileEventDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod || ignoreFSEventPath(w.root, event.Name) {
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Lstat(event.Name); err == nil && info.IsDir() {
+					if err := w.addTree(event.Name); err != nil {
+						appLog.Warn("watch new directory", "path", event.Name, "error", err)
+					}
+				}
+			}
+			pending[event.Name] = struct{}{}
+			timer.Reset(FileEventDebounce)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				full = true
+				timer.Reset(FileEventDebounce)
+				continue
+			}
+			appLog.Warn("filesystem event watcher", "root", w.root, "error", err)
+		case <-timer.C:
+			batch := fsEventsMsg{watcher: w, full: full}
+			for path := range pending {
+				batch.paths = append(batch.paths, path)
+			}
+			sort.Strings(batch.paths)
+			pending = map[string]struct{}{}
+			full = false
+			select {
+			case w.batches <- batch:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// wait returns a command that blocks until the next batch.
+func (w *fsEventWatcher) wait() tea.Cmd {
+	return func() tea.Msg {
+		batch, ok := <-w.batches
+		if !ok {
+			return fsWatcherStoppedMsg{watcher: w}
+		}
+		return batch
+	}
+}
+
+// ignoreFSEventPath reports whether path lies in a folder the watcher
+// ignores: the managed `.cli-notes` folder or a `.git` directory.
+func ignoreFSEventPath(root, path string) bool {
+	if !isWithinRoot(root, path) {
+		return true
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return true
+	}
+	for _, part := range strings.Split(rel, string(os.PathSeparator)) {
+		if shouldSkipManagedPath(part) || part == ".git" {
+			return true
+		}
+	}
+	return false
+}
+
+// startFileEvents starts an event watcher for the current notes directory,
+// replacing any previous one. It returns nil (leaving the poller in charge)
+// when fsnotify cannot watch the directory.
+func (m *Model) startFileEvents() tea.Cmd {
+	m.stopFileEvents()
+	if m.notesDir == "" {
+		return nil
+	}
+	w, err := startFSEventWatcher(m.notesDir)
+	if err != nil {
+		appLog.Warn("start filesystem event watcher; falling back to polling", "root", m.notesDir, "error", err)
+		return nil
+	}
+	m.fsWatcher = w
+	return w.wait()
+}
+
+// stopFileEvents closes the current event watcher, if any.
+func (m *Model) stopFileEvents() {
+	if m.fsWatcher == nil {
+		return
+	}
+	m.fsWatcher.close()
+	m.fsWatcher = nil
+}
+
+// handleFSEvents applies one batch from the active watcher and waits for the
+// next. Batches from a replaced watcher are dropped.
+func (m *Model) handleFSEvents(msg fsEventsMsg) (tea.Model, tea.Cmd) {
+	if msg.watcher != m.fsWatcher {
+		return m, nil
+	}
+	next := msg.watcher.wait()
+	if m.terminalBlurred {
+		m.deferFSEvents(msg)
+		return m, next
+	}
+	if msg.full {
+		return m, tea.Batch(m.handleExternalFilesystemChange(true), next)
+	}
+	if len(msg.paths) == 0 {
+		return m, next
+	}
+	return m, tea.Batch(m.handleExternalPathChanges(msg.paths), next)
+}
+
+// handleFSWatcherStopped hands monitoring back to the poller when the active
+// watcher's event stream ends unexpectedly.
+func (m *Model) handleFSWatcherStopped(msg fsWatcherStoppedMsg) (tea.Model, tea.Cmd) {
+	if msg.watcher != m.fsWatcher {
+		return m, nil
+	}
+	appLog.Warn("filesystem event watcher stopped; falling back to polling", "root", m.notesDir)
+	m.fsWatcher = nil
+	m.fileWatchSnapshot = nil
+	return m, nil
+}
+
+// handleExternalPathChanges refreshes the app for the changed paths only:
+// their search index entries and render cache entries (including those of
+// descendants of changed folders) are updated, then the tree is rebuilt with
+// the cursor and expansion state kept.
+func (m *Model) handleExternalPathChanges(paths []string) tea.Cmd {
+	// Positions are anchored on the cached rendering, so remember them before
+	// the changed notes' entries are dropped.
+	m.rememberCurrentNotePosition()
+	currentChanged := false
+	for _, path := range paths {
+		if m.currentFile != "" && isWithinRoot(path, m.currentFile) {
+			currentChanged = true
+		}
+		for cached := range m.renderCache {
+			if isWithinRoot(path, cached) {
+				delete(m.renderCache, cached)
+			}
+		}
+	}
+
+	cursor := m.cursor
+	selected := m.selectedPath()
+	m.invalidateTreeMetadataCache()
+	_ = m.applyMutationEffects(mutationEffects{
+		saveState:   true,
+		upsertPaths: paths,
+		refreshTree: true,
+		external:    true,
+	})
+	// When the selected row itself went away, stay near where it was instead
+	// of jumping to the top.
+	if selected != "" && m.selectedPath() != selected && len(m.items) > 0 {
+		m.cursor = min(cursor, len(m.items)-1)
+		m.adjustTreeOffset()
+	}
+	m.rebuildRecentEntries()
+	return m.finishExternalChange(currentChanged)
+}
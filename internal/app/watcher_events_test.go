@@ -0,0 +1,169 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestIgnoreFSEventPath(t *testing.T) {
+	root := filepath.Join(string(os.PathSeparator), "notes")
+	for _, tc := range []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(root, "a.md"), false},
+		{filepath.Join(root, "..draft.md"), false},
+		{filepath.Join(root, "work", "plan.md"), false},
+		{filepath.Join(root, ".cli-notes", "state.json"), true},
+		{filepath.Join(root, ".git", "index"), true},
+		{filepath.Join(root, "sub", ".git", "HEAD"), true},
+		{root, true},
+		{filepath.Join(string(os.PathSeparator), "elsewhere", "a.md"), true},
+	} {
+		if got := ignoreFSEventPath(root, tc.path); got != tc.want {
+			t.Fatalf("ignoreFSEventPath(%q) = %v, want %v", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestFSEventsKeepEditorBufferWhenEditedNoteChanges(t *testing.T) {
+	root := t.TempDir()
+	note := filepath.Join(root, "note.md")
+	mustWriteFile(t, note, "original\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeEditNote
+	m.currentFile = note
+	m.editor.SetValue("unsaved edits\n")
+	m.fsWatcher = &fsEventWatcher{root: root, batches: make(chan fsEventsMsg)}
+
+	mustWriteFile(t, note, "changed in another editor\n")
+	_, _ = m.handleFSEvents(fsEventsMsg{watcher: m.fsWatcher, paths: []string{note}})
+
+	if got := m.editor.Value(); got != "unsaved edits\n" {
+		t.Fatalf("expected editor buffer to be kept, got %q", got)
+	}
+	if want := "Warning: note.md changed on disk; saving will ask before overwriting"; m.status != want {
+		t.Fatalf("expected %q, got %q", want, m.status)
+	}
+}
+
+func TestExternalPathChangesUpdateOnlyAffectedEntries(t *testing.T) {
+	root := t.TempDir()
+	keep := filepath.Join(root, "keep.md")
+	gone := filepath.Join(root, "gone.md")
+	mustWriteFile(t, keep, "keep\n")
+	mustWriteFile(t, gone, "gone\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	m.renderCache[keep] = renderCacheEntry{}
+	m.renderCache[gone] = renderCacheEntry{}
+	for i, item := range m.items {
+		if item.path == keep {
+			m.cursor = i
+		}
+	}
+
+	added := filepath.Join(root, "added.md")
+	mustWriteFile(t, added, "zebra\n")
+	if err := os.Remove(gone); err != nil {
+		t.Fatalf("remove note: %v", err)
+	}
+	_ = m.handleExternalPathChanges([]string{added, gone})
+
+	assertTreeHasPath(t, m.items, added)
+	assertTreeHasPath(t, m.items, gone, false)
+	if m.selectedPath() != keep {
+		t.Fatalf("expected cursor to stay on %q, got %q", keep, m.selectedPath())
+	}
+	if _, ok := m.renderCache[keep]; !ok {
+		t.Fatal("expected unaffected render cache entry to be kept")
+	}
+	if _, ok := m.renderCache[gone]; ok {
+		t.Fatal("expected render cache entry for removed note to be dropped")
+	}
+	if results := m.searchIndex.search("zebra"); len(results) != 1 || results[0].path != added {
+		t.Fatalf("expected new note in search index, got %+v", results)
+	}
+	if results := m.searchIndex.search("gone"); len(results) != 0 {
+		t.Fatalf("expected removed note out of search index, got %+v", results)
+	}
+}
+
+func TestFSEventWatcherBatchesChangesAndFollowsWorkspace(t *testing.T) {
+	root := t.TempDir()
+	m := newTestCRUDModel(root)
+	m.mode = modeBrowse
+	wait := m.startFileEvents()
+	if wait == nil {
+		t.Skip("fsnotify unavailable")
+	}
+	defer m.stopFileEvents()
+
+	mustWriteFile(t, filepath.Join(root, ".cli-notes", "state.json"), "{}\n")
+	sub := filepath.Join(root, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	// Give the watcher a moment to add the new folder before writing into it.
+	time.Sleep(50 * time.Millisecond)
+	note := filepath.Join(sub, "note.md")
+	mustWriteFile(t, note, "hello\n")
+
+	msg, ok := waitFSEvents(t, wait).(fsEventsMsg)
+	if !ok {
+		t.Fatal("expected an event batch")
+	}
+	seen := map[string]bool{}
+	for _, path := range msg.paths {
+		seen[path] = true
+	}
+	for {
+		if seen[note] {
+			break
+		}
+		// A slow watcher may split the burst; collect until the note shows up.
+		next, ok := waitFSEvents(t, msg.watcher.wait()).(fsEventsMsg)
+		if !ok {
+			t.Fatalf("watcher stopped before reporting %q (saw %v)", note, seen)
+		}
+		for _, path := range next.paths {
+			seen[path] = true
+		}
+	}
+	for path := range seen {
+		if ignoreFSEventPath(root, path) {
+			t.Fatalf("expected ignored path %q to be filtered", path)
+		}
+	}
+
+	old := m.fsWatcher
+	m.notesDir = t.TempDir()
+	if m.startFileEvents() == nil {
+		t.Skip("fsnotify unavailable")
+	}
+	if m.fsWatcher == old || m.fsWatcher.root != m.notesDir {
+		t.Fatal("expected a new watcher on the new root")
+	}
+	if _, cmd := m.handleFSEvents(fsEventsMsg{watcher: old, paths: []string{note}}); cmd != nil {
+		t.Fatal("expected a batch from the old watcher to be dropped")
+	}
+}
+
+func waitFSEvents(t *testing.T, wait tea.Cmd) tea.Msg {
+	t.Helper()
+	got := make(chan tea.Msg, 1)
+	go func() { got <- wait() }()
+	select {
+	case msg := <-got:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for filesystem events")
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newWatchedEditModel(t *testing.T) (*Model, string) {
+	t.Helper()
+	root := t.TempDir()
+	note := filepath.Join(root, "note.md")
+	mustWriteFile(t, note, "original\n")
+
+	m := newTestCRUDModel(root)
+	m.mode = modeEditNote
+	m.currentFile = note
+	m.editor.SetValue("unsaved edits\n")
+	_, _ = m.handleFileWatchTick(fileWatchTickMsg{})
+	return m, note
+}
+
+func TestFileWatchKeepsEditorBufferWhenEditedNoteChanges(t *testing.T) {
+	m, note := newWatchedEditModel(t)
+	mustWriteFile(t, note, "changed in another editor\n")
+
+	_, _ = m.handleFileWatchTick(fileWatchTickMsg{})
+
+	if got := m.editor.Value(); got != "unsaved edits\n" {
+		t.Fatalf("expected editor buffer to be kept, got %q", got)
+	}
+	if want := "Warning: note.md changed on disk; saving will ask before overwriting"; m.status != want {
+		t.Fatalf("expected %q, got %q", want, m.status)
+	}
+}
+
+func TestFileWatchKeepsEditedNoteWhenRemoved(t *testing.T) {
+	m, note := newWatchedEditModel(t)
+	if err := os.Remove(note); err != nil {
+		t.Fatalf("remove note: %v", err)
+	}
+
+	_, _ = m.handleFileWatchTick(fileWatchTickMsg{})
+
+	if m.currentFile != note {
+		t.Fatalf("expected current file to be kept while editing, got %q", m.currentFile)
+	}
+	if !strings.Contains(m.status, "removed on disk") {
+		t.Fatalf("expected removed-on-disk warning, got %q", m.status)
+	}
+}
+
+func TestFileWatchOtherChangesDoNotWarnWhileEditing(t *testing.T) {
+	m, _ := newWatchedEditModel(t)
+	mustWriteFile(t, filepath.Join(m.notesDir, "other.md"), "new\n")
+
+	_, _ = m.handleFileWatchTick(fileWatchTickMsg{})
+
+	if strings.Contains(m.status, "Warning") {
+		t.Fatalf("expected no warning for unrelated change, got %q", m.status)
+	}
+	assertTreeHasPath(t, m.items, filepath.Join(m.notesDir, "other.md"))
+}
+
+func TestFileWatchClearsRemovedNoteInPreview(t *testing.T) {
+	m, note := newWatchedEditModel(t)
+	m.mode = modeBrowse
+	m.currentNoteContent = "original\n"
+	if err := os.Remove(note); err != nil {
+		t.Fatalf("remove note: %v", err)
+	}
+
+	_, _ = m.handleFileWatchTick(fileWatchTickMsg{})
+
+	if m.currentFile != "" || m.currentNoteContent != "" {
+		t.Fatalf("expected removed note to be cleared, got file %q content %q", m.currentFile, m.currentNoteContent)
+	}
+}
@@ -80,7 +80,7 @@ func (m *Model) handleWikiLinksPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.shouldIgnoreInput(msg) {
 		return m, nil
 	}
-	next, selectPressed, closePressed, handled := handlePopupListNav(msg, m.wikiLinkCursor, len(m.wikiLinks))
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.wikiLinkCursor, len(m.wikiLinks))
 	if !handled {
 		return m, nil
 	}
@@ -181,19 +181,32 @@ func (m *Model) renderWikiLinksPopup(width, height int) string {
 
 // renderWikiAutocompletePopup draws the edit-mode autocomplete popup showing
 // matching note titles/names filtered by the prefix typed after "[[".
+// While frontmatter completion is active it lists tags or keys instead.
 func (m *Model) renderWikiAutocompletePopup(width, height int) string {
 	innerWidth := max(0, width-popupStyle.GetHorizontalFrameSize())
 	innerHeight := max(0, height-popupStyle.GetVerticalFrameSize())
+	title := "Wiki Link Autocomplete"
+	if m.fmCompletion != nil {
+		title = "Tag Completion"
+		if m.fmCompletion.kind == fmCompleteKey {
+			title = "Frontmatter Key Completion"
+		}
+	}
 	lines := []string{
-		titleStyle.Render("Wiki Link Autocomplete"),
+		titleStyle.Render(title),
 		"",
 	}
 	limit := max(0, innerHeight-len(lines)-1)
-	for i := 0; i < min(limit, len(m.wikiAutocomplete)); i++ {
-		target := m.wikiAutocomplete[i]
-		label := target.Title
-		if strings.TrimSpace(label) == "" {
-			label = target.Name
+	for i := 0; i < min(limit, m.autocompleteCount()); i++ {
+		var label string
+		if m.fmCompletion != nil {
+			label = m.fmCompletionItems[i]
+		} else {
+			target := m.wikiAutocomplete[i]
+			label = target.Title
+			if strings.TrimSpace(label) == "" {
+				label = target.Name
+			}
 		}
 		line := truncate(label, innerWidth)
 		if i == m.wikiAutocompleteCursor {
@@ -214,32 +227,39 @@ func (m *Model) renderWikiAutocompletePopup(width, height int) string {
 //   - Esc: dismiss the popup without inserting anything.
 //   - Up/Down: navigate the candidate list.
 //   - Enter/Tab: accept the selected candidate, inserting its label and
-//     closing brackets into the editor.
+//     closing brackets into the editor. With no candidates the popup just
+//     closes. Either way the key is consumed.
 //   - Any other key: not handled — falls through to the normal editor handler.
+//
+// Tab precedence is deterministic: handleEditNoteKey calls this first, so Tab
+// accepts the candidate whenever the popup is open and only indents the line
+// (see insertEditorSoftTab) when it is closed.
 func (m *Model) handleWikiAutocompleteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
 	if !m.isOverlay(overlayWikiAutocomplete) {
 		return m, nil, false
 	}
-	switch msg.String() {
-	case "esc":
+	if m.fmCompletion != nil && msg.Type == tea.KeyRunes {
+		// Tags and keys are words; let letters bound to popup keys be typed.
+		return m, nil, false
+	}
+	count := m.autocompleteCount()
+	switch action := m.popupAction(msg); action {
+	case actionPopupClose:
 		m.closeOverlay()
 		return m, nil, true
-	case "up", "k", "ctrl+p":
-		if len(m.wikiAutocomplete) > 0 {
-			m.wikiAutocompleteCursor = clamp(m.wikiAutocompleteCursor-1, 0, len(m.wikiAutocomplete)-1)
-		}
+	case actionPopupUp, actionPopupDown, actionPopupPageUp, actionPopupPageDown:
+		m.wikiAutocompleteCursor, _, _, _ = popupListNav(action, m.wikiAutocompleteCursor, count)
 		return m, nil, true
-	case "down", "j", "ctrl+n":
-		if len(m.wikiAutocomplete) > 0 {
-			m.wikiAutocompleteCursor = clamp(m.wikiAutocompleteCursor+1, 0, len(m.wikiAutocomplete)-1)
-		}
-		return m, nil, true
-	case "enter", "tab":
-		if len(m.wikiAutocomplete) == 0 {
+	case actionPopupSelect:
+		if count == 0 {
 			m.closeOverlay()
 			return m, nil, true
 		}
-		m.acceptWikiAutocomplete(m.wikiAutocomplete[m.wikiAutocompleteCursor])
+		if m.fmCompletion != nil {
+			m.acceptFrontmatterCompletion(m.fmCompletionItems[m.wikiAutocompleteCursor])
+		} else {
+			m.acceptWikiAutocomplete(m.wikiAutocomplete[m.wikiAutocompleteCursor])
+		}
 		m.closeOverlay()
 		return m, nil, true
 	default:
@@ -247,6 +267,14 @@ func (m *Model) handleWikiAutocompleteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd, b
 	}
 }
 
+// autocompleteCount returns the number of candidates in the popup.
+func (m *Model) autocompleteCount() int {
+	if m.fmCompletion != nil {
+		return len(m.fmCompletionItems)
+	}
+	return len(m.wikiAutocomplete)
+}
+
 // maybeTriggerWikiAutocomplete checks whether the editor cursor is positioned
 // inside an open [[ token. If so, it builds a filtered list of note candidates
 // matching the prefix typed so far and opens the autocomplete popup. If the
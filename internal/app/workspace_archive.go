@@ -0,0 +1,132 @@
+// workspace_archive.go implements `notes --export-zip`, which backs up the
+// configured notes directory to a timestamped zip archive.
+//
+// Every file in the workspace is archived under its notes-relative path. The
+// managed .cli-notes folder (trash, templates, drafts) is skipped unless
+// requested, and a .git directory is always skipped. Files are streamed into
+// the archive one at a time, so memory use does not grow with the workspace.
+package app
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// RunExportZip archives the configured notes directory into dest and returns
+// the archive path and the number of files written. dest is either a
+// directory, which receives a timestamped notes-YYYYMMDD-HHMMSS.zip, or a
+// path ending in .zip.
+func RunExportZip(dest string, includeManaged bool) (string, int, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", 0, err
+	}
+	archivePath, err := workspaceArchivePath(dest, time.Now())
+	if err != nil {
+		return "", 0, err
+	}
+	count, err := zipWorkspace(cfg.NotesDir, archivePath, includeManaged)
+	return archivePath, count, err
+}
+
+// workspaceArchivePath resolves dest to an absolute archive path (~ is
+// expanded like notes_dir in config). Anything not ending in .zip is treated
+// as a directory and given a timestamped name.
+func workspaceArchivePath(dest string, now time.Time) (string, error) {
+	abs, err := config.NormalizeNotesDir(dest)
+	if err != nil {
+		return "", fmt.Errorf("export destination: %w", err)
+	}
+	if hasSuffixCaseInsensitive(abs, ".zip") {
+		return abs, nil
+	}
+	return filepath.Join(abs, "notes-"+now.Format("20060102-150405")+".zip"), nil
+}
+
+// zipWorkspace writes every file under root into a new archive at
+// archivePath and returns the number of files archived. An existing archive
+// is never overwritten, the archive skips itself when written inside root,
+// and a failed export removes the partial file.
+func zipWorkspace(root, archivePath string, includeManaged bool) (count int, err error) {
+	if err := os.MkdirAll(filepath.Dir(archivePath), DirPermission); err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(archivePath, os.O_CREATE|os.O_WRONLY|os.O_EXCL, FilePermission)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(archivePath)
+		}
+	}()
+
+	zw := zip.NewWriter(f)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			if path != root && (d.Name() == ".git" || (!includeManaged && shouldSkipManagedPath(d.Name()))) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || path == archivePath {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if err := addFileToZip(zw, path, filepath.ToSlash(rel)); err != nil {
+			return fmt.Errorf("archive %q: %w", rel, err)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = zw.Close(); err != nil {
+		return 0, err
+	}
+	if err = f.Close(); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// addFileToZip streams one file into zw under name, keeping its mode and
+// modification time.
+func addFileToZip(zw *zip.Writer, path, name string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readZipEntries(t *testing.T, path string) map[string]string {
+	t.Helper()
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	defer r.Close()
+	entries := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open entry %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		entries[f.Name] = string(data)
+	}
+	return entries
+}
+
+func sortedKeys(entries map[string]string) []string {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestZipWorkspaceArchivesNotesAndSkipsManagedDir(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "alpha\n")
+	mustWriteFile(t, filepath.Join(root, "work", "plan.md"), "plan\n")
+	mustWriteFile(t, filepath.Join(root, managedNotesDirName, "trash", "old.md"), "old\n")
+	mustWriteFile(t, filepath.Join(root, ".git", "HEAD"), "ref\n")
+
+	archive := filepath.Join(t.TempDir(), "backup.zip")
+	count, err := zipWorkspace(root, archive, false)
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	entries := readZipEntries(t, archive)
+	if got := strings.Join(sortedKeys(entries), ","); got != "a.md,work/plan.md" || count != 2 {
+		t.Fatalf("expected a.md and work/plan.md, got %q (count %d)", got, count)
+	}
+	if entries["work/plan.md"] != "plan\n" {
+		t.Fatalf("unexpected content %q", entries["work/plan.md"])
+	}
+}
+
+func TestZipWorkspaceIncludesManagedDirWhenRequested(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "alpha\n")
+	mustWriteFile(t, filepath.Join(root, managedNotesDirName, "trash", "old.md"), "old\n")
+
+	archive := filepath.Join(t.TempDir(), "backup.zip")
+	if _, err := zipWorkspace(root, archive, true); err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	entries := readZipEntries(t, archive)
+	if _, ok := entries[managedNotesDirName+"/trash/old.md"]; !ok {
+		t.Fatalf("expected managed entry, got %v", sortedKeys(entries))
+	}
+}
+
+func TestZipWorkspaceInsideRootSkipsItselfAndRefusesOverwrite(t *testing.T) {
+	root := t.TempDir()
+	mustWriteFile(t, filepath.Join(root, "a.md"), "alpha\n")
+
+	archive := filepath.Join(root, "backups", "notes.zip")
+	if _, err := zipWorkspace(root, archive, false); err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	if got := sortedKeys(readZipEntries(t, archive)); len(got) != 1 || got[0] != "a.md" {
+		t.Fatalf("expected only a.md, got %v", got)
+	}
+
+	if _, err := zipWorkspace(root, archive, false); !os.IsExist(err) {
+		t.Fatalf("expected existing archive to be refused, got %v", err)
+	}
+	if got := sortedKeys(readZipEntries(t, archive)); len(got) != 1 {
+		t.Fatalf("expected original archive untouched, got %v", got)
+	}
+}
+
+func TestWorkspaceArchivePath(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 16, 9, 5, 3, 0, time.UTC)
+
+	got, err := workspaceArchivePath(dir, now)
+	if err != nil || got != filepath.Join(dir, "notes-20261016-090503.zip") {
+		t.Fatalf("unexpected directory archive path %q (err %v)", got, err)
+	}
+	explicit := filepath.Join(dir, "Backup.ZIP")
+	if got, err := workspaceArchivePath(explicit, now); err != nil || got != explicit {
+		t.Fatalf("expected explicit .zip path kept, got %q (err %v)", got, err)
+	}
+	if _, err := workspaceArchivePath("  ", now); err == nil {
+		t.Fatal("expected empty destination to be rejected")
+	}
+}
@@ -12,14 +12,18 @@
 //
 // # Export
 //
-// The export popup (x key) offers two formats:
+// The export popup (x key) offers three formats:
 //
 //   - HTML: Uses Goldmark to convert the current note's markdown body (with
-//     frontmatter stripped) to HTML and writes it alongside the source file.
+//     frontmatter stripped) to a standalone HTML page, styled with
+//     export_css_file or the built-in stylesheet, and writes it alongside
+//     the source file.
 //   - PDF: Shells out to Pandoc (if installed). If Pandoc is not available,
 //     the user is shown an install guidance message.
+//   - Folder to HTML: Exports every note under the selected folder to a
+//     linked set of HTML pages with an index (folder_export.go).
 //
-// Both export operations run as async Bubble Tea Cmds to keep the UI
+// All export operations run as async Bubble Tea Cmds to keep the UI
 // responsive during file I/O.
 //
 // # Split Pane
@@ -38,44 +42,68 @@ package app
 
 import (
 	"bytes"
+	"fmt"
+	"html"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/treykane/cli-notes/internal/config"
 	"github.com/yuin/goldmark"
 )
 
-// openWorkspacePopup shows the workspace chooser popup (Ctrl+W). If only one
-// workspace is configured, a status message is shown instead. The popup
+// openWorkspacePopup shows the workspace chooser popup (Ctrl+W). The popup
 // pre-selects the currently active workspace so the user can see which one
-// is in use.
+// is in use, and also offers adding and removing workspaces
+// (workspace_manage.go).
 func (m *Model) openWorkspacePopup() {
-	if len(m.workspaces) <= 1 {
-		m.status = "No additional workspaces configured"
-		return
-	}
 	m.openOverlay(overlayWorkspace)
 	m.workspaceCursor = 0
-	for i, ws := range m.workspaces {
+	for i, ws := range m.workspaceList() {
 		if ws.Name == m.activeWorkspace {
 			m.workspaceCursor = i
 			break
 		}
 	}
-	m.status = "Workspace: Enter to switch, Esc to close"
+	m.status = "Workspace: Enter or 1-9 to switch, Alt+↑/↓ to reorder, a to add, d to remove, Esc to close"
+	if len(m.workspaces) <= 1 {
+		m.status = "Workspace: press a to add another workspace, Esc to close"
+	}
 }
 
 // handleWorkspacePopupKey routes key presses while the workspace popup is
 // visible. Up/Down navigate the list, Enter switches to the selected
-// workspace, and Esc dismisses the popup.
+// workspace and 1-9 to the Nth one, Alt+Up/Down reorder, a adds and d
+// removes a workspace, and Esc dismisses the popup.
 func (m *Model) handleWorkspacePopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.shouldIgnoreInput(msg) {
 		return m, nil
 	}
-	next, selectPressed, closePressed, handled := handlePopupListNav(msg, m.workspaceCursor, len(m.workspaces))
+	switch m.popupAction(msg) {
+	case popupActionAddWorkspace:
+		m.startAddWorkspace()
+		return m, nil
+	case popupActionRemoveWorkspace:
+		m.removeSelectedWorkspace()
+		return m, nil
+	case popupActionWorkspaceUp:
+		m.moveSelectedWorkspace(-1)
+		return m, nil
+	case popupActionWorkspaceDown:
+		m.moveSelectedWorkspace(1)
+		return m, nil
+	}
+	if idx, ok := m.popupQuickSelect(msg); ok {
+		if idx < len(m.workspaces) {
+			m.workspaceCursor = idx
+			return m.selectWorkspaceEntry()
+		}
+		return m, nil
+	}
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.workspaceCursor, len(m.workspaces))
 	if !handled {
 		return m, nil
 	}
@@ -106,42 +134,118 @@ func (m *Model) handleWorkspacePopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 //  7. Persists the active workspace choice to config.json.
 //
 // If the selected workspace is already active, the popup is simply closed.
+// With confirm_workspace_switch on (the default), unsaved edits or drafts
+// are confirmed first (see unsavedWorkSummary).
 func (m *Model) selectWorkspaceEntry() (tea.Model, tea.Cmd) {
 	if len(m.workspaces) == 0 {
 		m.closeOverlay()
 		return m, nil
 	}
-	ws := m.workspaces[m.workspaceCursor]
+	ws := m.workspaceList()[m.workspaceCursor]
 	if ws.Name == m.activeWorkspace && ws.NotesDir == m.notesDir {
 		m.closeOverlay()
 		m.status = "Workspace unchanged"
 		return m, nil
 	}
+	if m.confirmWorkspaceSwitch {
+		if summary := m.unsavedWorkSummary(); summary != "" {
+			m.closeOverlay()
+			m.askConfirm(confirmPrompt{
+				question:     fmt.Sprintf("%s. Switch to %s anyway? (y/n)", summary, ws.Name),
+				yesHint:      "switch (edits kept as draft)",
+				noHint:       "stay",
+				cancelStatus: "Workspace switch cancelled",
+				returnMode:   m.mode,
+				onConfirm:    func() (tea.Model, tea.Cmd) { return m.switchWorkspace(ws) },
+			})
+			return m, nil
+		}
+	}
+	return m.switchWorkspace(ws)
+}
 
+// unsavedWorkSummary describes work a workspace switch would leave behind:
+// an editor buffer that differs from the note's text on disk (decrypted for
+// encrypted notes), and drafts still on disk from a skipped recovery prompt.
+// It is empty when nothing is at risk.
+func (m *Model) unsavedWorkSummary() string {
+	var parts []string
+	editing := m.mode == modeEditNote && m.currentFile != ""
+	if editing {
+		if onDisk, _, err := m.readNoteText(m.currentFile); err != nil || onDisk != m.editor.Value() {
+			parts = append(parts, "Unsaved edits in "+m.displayRelative(m.currentFile))
+		}
+	}
+	drafts := 0
+	for _, record := range m.scanPendingDrafts() {
+		if editing && record.SourcePath == m.currentFile {
+			continue
+		}
+		drafts++
+	}
+	switch {
+	case drafts == 1:
+		parts = append(parts, "1 unsaved draft")
+	case drafts > 1:
+		parts = append(parts, fmt.Sprintf("%d unsaved drafts", drafts))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	summary := strings.Join(parts, " and ")
+	return strings.ToUpper(summary[:1]) + summary[1:]
+}
+
+// switchWorkspace performs the switch to ws. An open editor is closed with
+// its buffer saved as a draft, and drafts waiting in ws are offered for
+// recovery just as at startup.
+func (m *Model) switchWorkspace(ws config.WorkspaceConfig) (tea.Model, tea.Cmd) {
+	if m.mode == modeEditNote {
+		if err := m.saveDraftForCurrentFile(); err != nil {
+			appLog.Warn("save draft before workspace switch", "path", m.currentFile, "error", err)
+		}
+		m.mode = modeBrowse
+		m.clearEditorSelection()
+		m.resetEditHistory()
+	}
+
+	start := time.Now()
 	m.rememberCurrentNotePosition()
 	m.saveAppState()
 	m.activeWorkspace = ws.Name
+	m.markWorkspaceUsed(ws.Name)
 	m.notesDir = ws.NotesDir
 	m.expanded = map[string]bool{m.notesDir: true}
+	m.treeFilterQuery = ""
+	m.treeFilterRestorePath = ""
+	m.tagFilter = ""
 	m.currentFile = ""
 	m.secondaryFile = ""
+	m.splitEditSecondary = false
+	m.gitTouched = nil
+	m.gitStageAllNext = false
 	m.currentNoteContent = ""
+	m.metadataStripSegments = nil
+	m.detectFilesystemCase()
 	cfg, cfgErr := config.Load()
 	if cfgErr == nil {
 		m.sortMode = loadWorkspaceSortMode(cfg, m.notesDir)
+		m.sortDirection = loadWorkspaceSortDirection(cfg, m.notesDir)
+		applyTheme(loadWorkspaceTheme(cfg, m.notesDir))
+		applyEditorTheme(&m.editor)
+		m.applyRenderStyle(cfg.RenderStyle)
 	}
+	m.folderSorts = nil
+	m.archiveOrigins = nil
 	m.invalidateTreeMetadataCache()
-	m.items = buildTreeWithMetadataCache(m.notesDir, m.expanded, m.sortMode, nil, m.cachedTagsForPath)
+	m.items = buildTreeWithMetadataCache(m.notesDir, m.expanded, m.treeOrder(), nil, m.cachedTagsForPath)
 	m.cursor = 0
 	m.treeOffset = 0
 	state, err := loadAppState(m.notesDir)
 	if err != nil {
 		appLog.Warn("load workspace app state", "path", appStatePath(m.notesDir), "error", err)
 	}
-	m.pinnedPaths = state.PinnedPaths
-	m.recentFiles = state.RecentFiles
-	m.notePositions = state.Positions
-	m.noteOpenCounts = state.OpenCounts
+	m.applyAppState(state)
 	m.rebuildTreeKeep(m.notesDir)
 	m.rebuildRecentEntries()
 	m.refreshGitStatus()
@@ -154,7 +258,11 @@ func (m *Model) selectWorkspaceEntry() (tea.Model, tea.Cmd) {
 	if err := m.persistActiveWorkspace(); err != nil {
 		m.setStatusError("Switched workspace but failed to persist active workspace", err)
 	}
-	return m, nil
+	if elapsed := time.Since(start); m.isSlowOp(elapsed) {
+		m.reportSlowOp(slowOpReport{op: slowOpWorkspace, elapsed: elapsed, path: ws.NotesDir, items: len(m.items), itemsNoun: "tree rows"})
+	}
+	m.loadPendingDrafts()
+	return m, tea.Batch(m.startFileEvents(), m.scanAgendaBadge())
 }
 
 // persistActiveWorkspace writes the current active workspace name and
@@ -174,21 +282,31 @@ func (m *Model) persistActiveWorkspace() error {
 	return config.Save(cfg)
 }
 
-// openExportPopup shows the export format chooser popup (x key). Only
-// markdown files can be exported; non-markdown files show a status message
-// instead. The popup offers HTML and PDF as export targets.
+// Export popup rows, in display order.
+const (
+	exportRowHTML = iota
+	exportRowPDF
+	exportRowPlainText
+	exportRowClipboardHTML
+	exportRowClipboardRendered
+	exportRowMarkdownCopy
+	exportRowFolder
+	exportRowFolderHTML
+	exportRowFolderPDF
+)
+
+// exportOptions are the export popup labels, indexed by the exportRow constants.
+var exportOptions = []string{"HTML", "PDF (pandoc)", "Plain text (strip markdown)", "HTML to clipboard",
+	"Rendered text to clipboard", "Markdown copy (no frontmatter)", "Folder to HTML",
+	"Folder to single HTML", "Folder to single PDF (pandoc)"}
+
+// openExportPopup shows the export format chooser popup (x key). The
+// single-note formats need a markdown note to be open; that is checked when
+// one is chosen so the folder exports stay available without a note.
 func (m *Model) openExportPopup() {
-	if m.currentFile == "" {
-		m.status = "Select a note first"
-		return
-	}
-	if !hasSuffixCaseInsensitive(m.currentFile, ".md") {
-		m.status = "Export supports markdown notes only"
-		return
-	}
 	m.openOverlay(overlayExport)
 	m.exportCursor = 0
-	m.status = "Export: choose HTML or PDF"
+	m.status = "Export: choose a format for the note, or a whole folder"
 }
 
 // handleExportPopupKey routes key presses while the export popup is visible.
@@ -197,7 +315,7 @@ func (m *Model) handleExportPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.shouldIgnoreInput(msg) {
 		return m, nil
 	}
-	next, selectPressed, closePressed, handled := handlePopupListNav(msg, m.exportCursor, 2)
+	next, selectPressed, closePressed, handled := m.popupListKey(msg, m.exportCursor, len(exportOptions))
 	if !handled {
 		return m, nil
 	}
@@ -209,8 +327,38 @@ func (m *Model) handleExportPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.exportCursor = next
 	if selectPressed {
 		m.closeOverlay()
-		if m.exportCursor == 0 {
+		switch m.exportCursor {
+		case exportRowFolder:
+			m.startFolderExportPrompt()
+			return m, nil
+		case exportRowFolderHTML:
+			return m, m.exportFolderDocument(folderDocumentHTML)
+		case exportRowFolderPDF:
+			return m, m.exportFolderDocument(folderDocumentPDF)
+		}
+		if m.currentFile == "" {
+			m.status = "Select a note first"
+			return m, nil
+		}
+		if isEncryptedNotePath(m.currentFile) {
+			m.status = "Encrypted notes cannot be exported"
+			return m, nil
+		}
+		if !hasSuffixCaseInsensitive(m.currentFile, ".md") {
+			m.status = "Export supports markdown notes only"
+			return m, nil
+		}
+		switch m.exportCursor {
+		case exportRowHTML:
 			return m, m.exportCurrentNoteHTML()
+		case exportRowPlainText:
+			return m, m.exportCurrentNotePlainText()
+		case exportRowClipboardHTML:
+			return m, m.copyCurrentNoteHTMLToClipboard()
+		case exportRowClipboardRendered:
+			return m, m.copyCurrentNoteRenderedToClipboard()
+		case exportRowMarkdownCopy:
+			return m, m.exportCurrentNoteMarkdownCopy()
 		}
 		return m, m.exportCurrentNotePDF()
 	}
@@ -218,29 +366,50 @@ func (m *Model) handleExportPopupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 // exportCurrentNoteHTML returns an async Cmd that converts the current note
-// to HTML using Goldmark and writes it alongside the source file (same name,
-// .html extension). Frontmatter is stripped before conversion so the output
-// contains only rendered markdown content.
+// to a standalone HTML page using Goldmark and writes it alongside the
+// source file (same name, .html extension). Frontmatter is not rendered into
+// the body; it fills the page's <title> and <meta> tags, and the page is
+// styled with export_css_file or the built-in stylesheet.
 func (m *Model) exportCurrentNoteHTML() tea.Cmd {
 	path := m.currentFile
+	cssFile := m.exportCSSFile
 	return func() tea.Msg {
 		content, err := os.ReadFile(path)
 		if err != nil {
 			return statusMsg{Text: "Export failed: unable to read note"}
 		}
-		_, body := parseFrontmatterAndBody(string(content))
-		var out bytes.Buffer
-		if err := goldmark.Convert([]byte(body), &out); err != nil {
+		meta, body := parseFrontmatterAndBody(string(content))
+		stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		page, err := renderNoteHTMLDocument(exportNoteTitle(meta, body, stem), meta, body, exportStyleBlock(cssFile))
+		if err != nil {
 			return statusMsg{Text: "Export failed: unable to convert markdown to HTML"}
 		}
-		htmlPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".html"
-		if err := os.WriteFile(htmlPath, out.Bytes(), FilePermission); err != nil {
+		htmlPath := noteExportPath(path, ".html")
+		if err := os.WriteFile(htmlPath, page, FilePermission); err != nil {
 			return statusMsg{Text: "Export failed: unable to write HTML file"}
 		}
 		return statusMsg{Text: "Exported HTML: " + m.displayRelative(htmlPath)}
 	}
 }
 
+// renderNoteHTMLDocument wraps the HTML of a note's markdown body in a full
+// page with title, frontmatter <meta> tags, and the <style> block style,
+// adding an <h1> title when the body has no heading of its own.
+func renderNoteHTMLDocument(title string, meta NoteMetadata, body, style string) ([]byte, error) {
+	var out bytes.Buffer
+	if err := goldmark.Convert([]byte(body), &out); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	writeExportHead(&b, title, meta, style)
+	if len(parseMarkdownHeadings(body)) == 0 {
+		fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(title))
+	}
+	b.Write(out.Bytes())
+	b.WriteString("</body>\n</html>\n")
+	return b.Bytes(), nil
+}
+
 // exportCurrentNotePDF returns an async Cmd that converts the current note
 // to PDF by shelling out to Pandoc. If Pandoc is not installed (not found in
 // PATH), a user-friendly status message with install guidance is returned
@@ -251,7 +420,7 @@ func (m *Model) exportCurrentNotePDF() tea.Cmd {
 		if _, err := exec.LookPath("pandoc"); err != nil {
 			return statusMsg{Text: "PDF export unavailable: install pandoc to enable PDF export"}
 		}
-		pdfPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".pdf"
+		pdfPath := noteExportPath(path, ".pdf")
 		cmd := exec.Command("pandoc", "-f", "markdown", "-o", pdfPath, path)
 		var stderr bytes.Buffer
 		cmd.Stderr = &stderr
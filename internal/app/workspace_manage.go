@@ -0,0 +1,212 @@
+// workspace_manage.go adds, removes, and orders workspaces from the
+// workspace popup (Ctrl+W), so they no longer have to be edited in
+// config.json.
+//
+// In the popup, `a` starts a two-step prompt (name, then notes directory)
+// and `d` removes the selected workspace. Both go through config.Save, which
+// enforces the same rules as loading config.json: names are required and
+// unique ignoring case, and no two workspaces share a notes directory. The
+// active workspace cannot be removed, and removing a workspace never touches
+// its notes on disk.
+//
+// The popup lists workspaces in config order, which Alt+Up/Alt+Down
+// rearranges and persists. With workspace_order "last_used" the popup sorts
+// by each workspace's LastUsed time instead (set on every switch) and manual
+// moves are refused. Keys 1-9 switch to the Nth listed workspace.
+package app
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/treykane/cli-notes/internal/config"
+)
+
+// addWorkspace validates and persists a new workspace. A missing notes
+// directory is created when create_missing_dirs is on.
+func (m *Model) addWorkspace(name, dir string) error {
+	notesDir, err := config.NormalizeNotesDir(dir)
+	if err != nil {
+		return fmt.Errorf("invalid notes dir: %w", err)
+	}
+	candidate := append(slices.Clone(m.workspaces), config.WorkspaceConfig{Name: name, NotesDir: notesDir})
+	workspaces, err := config.ValidateWorkspaces(candidate)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(notesDir)
+	switch {
+	case err == nil && !info.IsDir():
+		return fmt.Errorf("%s is not a directory", notesDir)
+	case errors.Is(err, os.ErrNotExist) && m.createMissingDirs:
+		if err := os.MkdirAll(notesDir, DirPermission); err != nil {
+			return fmt.Errorf("create notes dir: %w", err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("%s does not exist", notesDir)
+	case err != nil:
+		return err
+	}
+	return m.saveWorkspaces(workspaces)
+}
+
+// removeWorkspace drops the named workspace from the config. The active
+// workspace cannot be removed.
+func (m *Model) removeWorkspace(name string) error {
+	idx := slices.IndexFunc(m.workspaces, func(ws config.WorkspaceConfig) bool {
+		return strings.EqualFold(ws.Name, strings.TrimSpace(name))
+	})
+	if idx < 0 {
+		return fmt.Errorf("workspace %q not found", name)
+	}
+	if strings.EqualFold(m.workspaces[idx].Name, m.activeWorkspace) {
+		return errors.New("cannot remove the active workspace")
+	}
+	return m.saveWorkspaces(slices.Delete(slices.Clone(m.workspaces), idx, idx+1))
+}
+
+// saveWorkspaces writes workspaces to config.json, keeping the active
+// workspace, and reloads the normalized list.
+func (m *Model) saveWorkspaces(workspaces []config.WorkspaceConfig) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	cfg.Workspaces = workspaces
+	cfg.ActiveWorkspace = m.activeWorkspace
+	cfg.NotesDir = m.notesDir
+	if cfg.TemplatesDir == "" {
+		cfg.TemplatesDir = m.templatesDir
+	}
+	if err := config.Save(cfg); err != nil {
+		return err
+	}
+	saved, err := config.Load()
+	if err != nil {
+		return err
+	}
+	m.workspaces = saved.Workspaces
+	return nil
+}
+
+// startAddWorkspace switches to the add-workspace prompt, asking for the
+// name first.
+func (m *Model) startAddWorkspace() {
+	m.closeOverlay()
+	m.mode = modeAddWorkspace
+	m.showHelp = false
+	m.workspaceDraftName = ""
+	m.input.Reset()
+	m.input.Placeholder = "Workspace name"
+	m.input.Focus()
+	m.status = "Add workspace: enter a name"
+}
+
+// handleAddWorkspaceKey processes keypresses in the add-workspace prompt.
+func (m *Model) handleAddWorkspaceKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	return m.handleInputModeKey(msg, m.saveAddWorkspaceInput, "Add workspace cancelled")
+}
+
+// saveAddWorkspaceInput accepts the name, then the notes directory. A
+// rejected entry keeps the prompt open so it can be corrected.
+func (m *Model) saveAddWorkspaceInput() (tea.Model, tea.Cmd) {
+	value := strings.TrimSpace(m.input.Value())
+	if m.workspaceDraftName == "" {
+		if value == "" {
+			m.status = "Workspace name is required"
+			return m, nil
+		}
+		for _, ws := range m.workspaces {
+			if strings.EqualFold(ws.Name, value) {
+				m.status = fmt.Sprintf("Workspace %q already exists", ws.Name)
+				return m, nil
+			}
+		}
+		m.workspaceDraftName = value
+		m.input.Reset()
+		m.input.Placeholder = "Notes directory (~ allowed)"
+		m.status = "Add workspace: enter the notes directory for " + value
+		return m, nil
+	}
+
+	name := m.workspaceDraftName
+	if err := m.addWorkspace(name, value); err != nil {
+		m.status = "Cannot add workspace: " + err.Error()
+		return m, nil
+	}
+	m.mode = modeBrowse
+	m.workspaceDraftName = ""
+	m.openOverlay(overlayWorkspace)
+	m.workspaceCursor = max(0, slices.IndexFunc(m.workspaceList(), func(ws config.WorkspaceConfig) bool {
+		return strings.EqualFold(ws.Name, name)
+	}))
+	m.status = "Added workspace: " + name
+	return m, nil
+}
+
+// removeSelectedWorkspace removes the workspace under the popup cursor.
+func (m *Model) removeSelectedWorkspace() {
+	list := m.workspaceList()
+	if m.workspaceCursor < 0 || m.workspaceCursor >= len(list) {
+		return
+	}
+	name := list[m.workspaceCursor].Name
+	if err := m.removeWorkspace(name); err != nil {
+		m.status = "Cannot remove workspace: " + err.Error()
+		return
+	}
+	m.workspaceCursor = clamp(m.workspaceCursor, 0, max(0, len(m.workspaces)-1))
+	m.status = "Removed workspace: " + name + " (notes left on disk)"
+}
+
+// workspaceList returns the workspaces in popup order. The popup cursor
+// indexes this list, not m.workspaces.
+func (m *Model) workspaceList() []config.WorkspaceConfig {
+	if m.workspaceOrder != config.WorkspaceOrderLastUsed {
+		return m.workspaces
+	}
+	list := slices.Clone(m.workspaces)
+	slices.SortStableFunc(list, func(a, b config.WorkspaceConfig) int {
+		return cmp.Compare(b.LastUsed, a.LastUsed)
+	})
+	return list
+}
+
+// moveSelectedWorkspace moves the workspace under the popup cursor up
+// (delta -1) or down (delta 1) and persists the new order.
+func (m *Model) moveSelectedWorkspace(delta int) {
+	if m.workspaceOrder == config.WorkspaceOrderLastUsed {
+		m.status = "Workspaces are sorted by last use (workspace_order: last_used)"
+		return
+	}
+	from, to := m.workspaceCursor, m.workspaceCursor+delta
+	if from < 0 || from >= len(m.workspaces) || to < 0 || to >= len(m.workspaces) {
+		return
+	}
+	reordered := slices.Clone(m.workspaces)
+	reordered[from], reordered[to] = reordered[to], reordered[from]
+	if err := m.saveWorkspaces(reordered); err != nil {
+		m.status = "Cannot reorder workspaces: " + err.Error()
+		return
+	}
+	m.workspaceCursor = to
+	m.status = fmt.Sprintf("Moved workspace %s to position %d", reordered[to].Name, to+1)
+}
+
+// markWorkspaceUsed records that the named workspace was just activated.
+// The caller persists m.workspaces.
+func (m *Model) markWorkspaceUsed(name string) {
+	for i := range m.workspaces {
+		if m.workspaces[i].Name == name {
+			m.workspaces[i].LastUsed = time.Now().Unix()
+			return
+		}
+	}
+}
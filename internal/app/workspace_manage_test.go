@@ -0,0 +1,343 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/treykane/cli-notes/internal/config"
+	"github.com/treykane/cli-notes/internal/theme"
+)
+
+// newWorkspaceManageModel saves a config with workspaces A (active) and B and
+// returns a model for workspace A.
+func newWorkspaceManageModel(t *testing.T) (*Model, string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	notesA := filepath.Join(home, "notes-a")
+	notesB := filepath.Join(home, "notes-b")
+	mustWriteFile(t, filepath.Join(notesA, "a.md"), "a\n")
+	mustWriteFile(t, filepath.Join(notesB, "b.md"), "b\n")
+
+	cfg := config.Config{
+		NotesDir:        notesA,
+		Workspaces:      []config.WorkspaceConfig{{Name: "A", NotesDir: notesA}, {Name: "B", NotesDir: notesB}},
+		ActiveWorkspace: "A",
+	}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	m := newTestCRUDModel(notesA)
+	m.mode = modeBrowse
+	m.workspaces = cfg.Workspaces
+	m.activeWorkspace = "A"
+	return m, home
+}
+
+func TestAddWorkspaceRejectsCollisionsAndPersists(t *testing.T) {
+	m, home := newWorkspaceManageModel(t)
+
+	if err := m.addWorkspace("a", filepath.Join(home, "elsewhere")); err == nil || !strings.Contains(err.Error(), "duplicate workspace name") {
+		t.Fatalf("expected duplicate name error, got %v", err)
+	}
+	if err := m.addWorkspace("C", filepath.Join(home, "notes-b")+"/"); err == nil || !strings.Contains(err.Error(), "duplicate workspace notes_dir") {
+		t.Fatalf("expected duplicate dir error, got %v", err)
+	}
+	if err := m.addWorkspace("C", filepath.Join(home, "missing")); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("expected missing dir error, got %v", err)
+	}
+
+	m.createMissingDirs = true
+	if err := m.addWorkspace("C", "~/notes-c"); err != nil {
+		t.Fatalf("add workspace: %v", err)
+	}
+	notesC := filepath.Join(home, "notes-c")
+	if info, err := os.Stat(notesC); err != nil || !info.IsDir() {
+		t.Fatalf("expected notes dir to be created: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(cfg.Workspaces) != 3 || cfg.Workspaces[2] != (config.WorkspaceConfig{Name: "C", NotesDir: notesC}) || cfg.ActiveWorkspace != "A" {
+		t.Fatalf("unexpected saved workspaces %+v (active %q)", cfg.Workspaces, cfg.ActiveWorkspace)
+	}
+	if len(m.workspaces) != 3 {
+		t.Fatalf("expected model workspaces reloaded, got %+v", m.workspaces)
+	}
+}
+
+func TestRemoveWorkspaceRefusesActive(t *testing.T) {
+	m, home := newWorkspaceManageModel(t)
+
+	if err := m.removeWorkspace("a"); err == nil {
+		t.Fatal("expected removing the active workspace to fail")
+	}
+	if err := m.removeWorkspace("B"); err != nil {
+		t.Fatalf("remove workspace: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(cfg.Workspaces) != 1 || cfg.Workspaces[0].Name != "A" || len(m.workspaces) != 1 {
+		t.Fatalf("expected only A to remain, got %+v", cfg.Workspaces)
+	}
+	if _, err := os.Stat(filepath.Join(home, "notes-b", "b.md")); err != nil {
+		t.Fatalf("expected removed workspace's notes to stay on disk: %v", err)
+	}
+}
+
+func TestWorkspacePopupAddPrompt(t *testing.T) {
+	m, home := newWorkspaceManageModel(t)
+	notesD := filepath.Join(home, "notes-d")
+	if err := os.Mkdir(notesD, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	typeText := func(s string) {
+		for _, r := range s {
+			_, _ = m.dispatchKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+		_, _ = m.dispatchKey(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	m.openWorkspacePopup()
+	_, _ = m.dispatchKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if m.mode != modeAddWorkspace {
+		t.Fatalf("expected add-workspace prompt, got mode %v", m.mode)
+	}
+	typeText("b")
+	if m.workspaceDraftName != "" || !strings.Contains(m.status, "already exists") {
+		t.Fatalf("expected duplicate name to be rejected, status %q", m.status)
+	}
+	// The rejected name stays in the input for correction.
+	_, _ = m.dispatchKey(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeText("D")
+	typeText(notesD)
+	if m.mode != modeBrowse || m.overlay != overlayWorkspace {
+		t.Fatalf("expected to return to the popup, mode %v overlay %v (status %q)", m.mode, m.overlay, m.status)
+	}
+	if m.workspaces[m.workspaceCursor].Name != "D" {
+		t.Fatalf("expected cursor on the new workspace, got %+v", m.workspaces[m.workspaceCursor])
+	}
+
+	m.workspaceCursor = 0
+	_, _ = m.dispatchKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if !strings.Contains(m.status, "active workspace") || len(m.workspaces) != 3 {
+		t.Fatalf("expected active workspace removal refused, status %q", m.status)
+	}
+}
+
+func TestWorkspaceSwitchWithUnsavedEditsAsksFirst(t *testing.T) {
+	m, home := newWorkspaceManageModel(t)
+	m.confirmWorkspaceSwitch = true
+	notePath := filepath.Join(home, "notes-a", "a.md")
+	m.currentFile = notePath
+	m.mode = modeEditNote
+	m.editor.SetValue("a\nunsaved\n")
+
+	m.openOverlay(overlayWorkspace)
+	m.workspaceCursor = 1
+	_, _ = m.handleWorkspacePopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeConfirm || m.activeWorkspace != "A" || !strings.Contains(m.status, "Unsaved edits in a.md") {
+		t.Fatalf("expected confirm prompt, mode=%v workspace=%s status=%q", m.mode, m.activeWorkspace, m.status)
+	}
+	_, _ = m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != modeEditNote || m.activeWorkspace != "A" || m.editor.Value() != "a\nunsaved\n" {
+		t.Fatalf("expected to keep editing in A, mode=%v workspace=%s", m.mode, m.activeWorkspace)
+	}
+
+	m.openOverlay(overlayWorkspace)
+	m.workspaceCursor = 1
+	_, _ = m.handleWorkspacePopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+	_, _ = m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m.activeWorkspace != "B" || m.mode == modeEditNote {
+		t.Fatalf("expected switch to B, mode=%v workspace=%s", m.mode, m.activeWorkspace)
+	}
+	draftsA := filepath.Join(home, "notes-a", managedNotesDirName, ".drafts")
+	if entries, err := os.ReadDir(draftsA); err != nil || len(entries) != 1 {
+		t.Fatalf("expected unsaved buffer kept as a draft in A, got %v %v", entries, err)
+	}
+}
+
+func TestUnsavedWorkSummaryComparesDecryptedText(t *testing.T) {
+	m, home := newWorkspaceManageModel(t)
+	m.noteKeys.set("pw")
+	sealed, err := m.noteKeys.seal([]byte("# Secret\n"))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	notePath := filepath.Join(home, "notes-a", "secret.md"+EncryptedNoteExt)
+	mustWriteFile(t, notePath, string(sealed))
+	m.currentFile = notePath
+	m.mode = modeEditNote
+	m.editor.SetValue("# Secret\n")
+
+	if got := m.unsavedWorkSummary(); got != "" {
+		t.Fatalf("expected an unchanged encrypted note to need no prompt, got %q", got)
+	}
+	m.editor.SetValue("# Secret\nmore\n")
+	if got := m.unsavedWorkSummary(); !strings.Contains(got, "Unsaved edits in secret.md.enc") {
+		t.Fatalf("expected unsaved edits reported, got %q", got)
+	}
+}
+
+func TestWorkspaceSwitchConfirmsPendingDraftsAndCanBeDisabled(t *testing.T) {
+	m, home := newWorkspaceManageModel(t)
+	m.confirmWorkspaceSwitch = true
+	m.currentFile = filepath.Join(home, "notes-a", "a.md")
+	m.mode = modeEditNote
+	m.editor.SetValue("draft body\n")
+	if err := m.saveDraftForCurrentFile(); err != nil {
+		t.Fatalf("save draft: %v", err)
+	}
+	m.mode = modeBrowse
+
+	m.openOverlay(overlayWorkspace)
+	m.workspaceCursor = 1
+	_, _ = m.handleWorkspacePopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != modeConfirm || !strings.Contains(m.status, "1 unsaved draft") {
+		t.Fatalf("expected draft prompt, mode=%v status=%q", m.mode, m.status)
+	}
+	_, _ = m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.mode != modeBrowse || m.activeWorkspace != "A" {
+		t.Fatalf("expected n to cancel, mode=%v workspace=%s", m.mode, m.activeWorkspace)
+	}
+
+	m.confirmWorkspaceSwitch = false
+	m.openOverlay(overlayWorkspace)
+	m.workspaceCursor = 1
+	_, _ = m.handleWorkspacePopupKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.activeWorkspace != "B" {
+		t.Fatalf("expected immediate switch with confirmation off, got %s", m.activeWorkspace)
+	}
+}
+
+func TestWorkspacePopupReordersAndPersists(t *testing.T) {
+	m, _ := newWorkspaceManageModel(t)
+	m.openOverlay(overlayWorkspace)
+	m.workspaceCursor = 1
+
+	_, _ = m.handleWorkspacePopupKey(tea.KeyMsg{Type: tea.KeyUp, Alt: true})
+	if m.workspaceCursor != 0 || m.workspaces[0].Name != "B" {
+		t.Fatalf("expected B moved to the top, cursor=%d workspaces=%+v", m.workspaceCursor, m.workspaces)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Workspaces[0].Name != "B" || cfg.Workspaces[1].Name != "A" {
+		t.Fatalf("expected reorder persisted, got %+v", cfg.Workspaces)
+	}
+	_, _ = m.handleWorkspacePopupKey(tea.KeyMsg{Type: tea.KeyUp, Alt: true})
+	if m.workspaceCursor != 0 || m.workspaces[0].Name != "B" {
+		t.Fatalf("expected move past the top to be ignored, got %+v", m.workspaces)
+	}
+
+	_, _ = m.handleWorkspacePopupKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	if m.activeWorkspace != "B" || m.isOverlay(overlayWorkspace) {
+		t.Fatalf("expected 1 to switch to B, got %s (overlay %v)", m.activeWorkspace, m.overlay)
+	}
+}
+
+func TestWorkspacePopupLastUsedOrder(t *testing.T) {
+	m, _ := newWorkspaceManageModel(t)
+	m.workspaceOrder = config.WorkspaceOrderLastUsed
+	m.workspaces[1].LastUsed = 100
+
+	m.openOverlay(overlayWorkspace)
+	if list := m.workspaceList(); list[0].Name != "B" || list[1].Name != "A" {
+		t.Fatalf("expected most recently used first, got %+v", list)
+	}
+	_, _ = m.handleWorkspacePopupKey(tea.KeyMsg{Type: tea.KeyDown, Alt: true})
+	if m.workspaces[0].Name != "A" || !strings.Contains(m.status, "last use") {
+		t.Fatalf("expected manual moves refused, workspaces=%+v status=%q", m.workspaces, m.status)
+	}
+
+	_, _ = m.handleWorkspacePopupKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	if m.activeWorkspace != "B" {
+		t.Fatalf("expected 1 to switch to the most recent workspace, got %s", m.activeWorkspace)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Workspaces[1].LastUsed <= 100 {
+		t.Fatalf("expected switch to record last use, got %+v", cfg.Workspaces)
+	}
+}
+
+func TestWorkspaceSwitchAppliesWorkspaceTheme(t *testing.T) {
+	m, home := newWorkspaceManageModel(t)
+	t.Cleanup(func() { applyThemePreset(config.ThemePresetOceanCitrus) })
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.ThemePreset = config.ThemePresetSunset
+	cfg.ThemePresetByWorkspace = map[string]string{filepath.Join(home, "notes-b"): config.ThemePresetNeonSlate}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	_, _ = m.switchWorkspace(m.workspaces[1])
+	if accentBrowse != lipgloss.Color(paletteForPreset(config.ThemePresetNeonSlate).AccentBrowse) {
+		t.Fatalf("expected workspace B theme, got accent %v", accentBrowse)
+	}
+	_, _ = m.switchWorkspace(m.workspaces[0])
+	if accentBrowse != lipgloss.Color(paletteForPreset(config.ThemePresetSunset).AccentBrowse) {
+		t.Fatalf("expected global theme fallback for A, got accent %v", accentBrowse)
+	}
+}
+
+func TestLoadWorkspaceThemePrefersThemeFileAndFallsBack(t *testing.T) {
+	custom := paletteForPreset(config.ThemePresetOceanCitrus)
+	custom.AccentBrowse = "#123456"
+	data, err := json.Marshal(custom)
+	if err != nil {
+		t.Fatalf("marshal theme: %v", err)
+	}
+	themePath := filepath.Join(t.TempDir(), "theme.json")
+	mustWriteFile(t, themePath, string(data))
+
+	cfg := config.Config{ThemePreset: config.ThemePresetSunset, ThemeFile: themePath}
+	if got := loadWorkspaceTheme(cfg, ""); got.AccentBrowse != "#123456" {
+		t.Fatalf("expected theme file colors, got %+v", got)
+	}
+	mustWriteFile(t, themePath, `{"accent_browse": "#123456"}`)
+	if got := loadWorkspaceTheme(cfg, ""); got != paletteForPreset(config.ThemePresetSunset) {
+		t.Fatalf("expected preset fallback for an incomplete theme file, got %+v", got)
+	}
+	cfg.ThemeFile = filepath.Join(t.TempDir(), "missing.json")
+	if got := loadWorkspaceTheme(cfg, ""); got != paletteForPreset(config.ThemePresetSunset) {
+		t.Fatalf("expected preset fallback for a missing theme file, got %+v", got)
+	}
+}
+
+func TestLightThemePresetSelectsLightMarkdownStyle(t *testing.T) {
+	t.Setenv("CLI_NOTES_GLAMOUR_STYLE", "")
+	t.Setenv("GLAMOUR_STYLE", "")
+	t.Cleanup(func() { applyThemePreset(config.ThemePresetOceanCitrus) })
+
+	applyThemePreset(config.ThemePresetLight)
+	light := paletteForPreset(config.ThemePresetLight)
+	if err := light.Validate(); err != nil {
+		t.Fatalf("light preset invalid: %v", err)
+	}
+	if markdownStyle != theme.MarkdownStyleLight {
+		t.Fatalf("expected light markdown style, got %q", markdownStyle)
+	}
+	if selectedStyle.GetReverse() || selectedStyle.GetBackground() != lipgloss.Color(light.SelectedBg) {
+		t.Fatalf("expected explicit selected-row colors, got background %v", selectedStyle.GetBackground())
+	}
+
+	applyThemePreset(config.ThemePresetSunset)
+	if markdownStyle != theme.MarkdownStyleDark {
+		t.Fatalf("expected dark markdown style after switching back, got %q", markdownStyle)
+	}
+}
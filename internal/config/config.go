@@ -8,13 +8,49 @@
 //
 //   - notes_dir:         Legacy single-workspace notes directory (migrated to workspaces).
 //   - tree_sort:         Persisted tree sort mode (name, modified, size, created).
+//   - tree_sort_tiebreak: Ordering for entries whose sort key is equal (name, name_desc).
+//   - tree_sort_direction: Tree sort direction (asc, desc); empty uses the mode's natural direction.
 //   - templates_dir:     Directory containing note templates (default: ~/.cli-notes/templates).
-//   - workspaces:        Named workspace list, each with its own notes_dir.
+//   - workspaces:        Named workspace list, each with its own notes_dir, in popup order.
+//   - workspace_order:   Workspace popup order (config, last_used).
 //   - active_workspace:  Name of the currently active workspace.
-//   - keybindings:       Inline action→key overrides (merged with keymap_file).
+//   - keybindings:       Inline action→key overrides, a key or a list of keys (merged with keymap_file).
 //   - keymap_file:       Path to an external keymap JSON file (default: ~/.cli-notes/keymap.json).
-//   - theme_preset:      UI color preset (ocean_citrus, sunset, neon_slate).
+//   - theme_preset:      UI color preset (ocean_citrus, sunset, neon_slate, light).
+//   - theme_preset_by_workspace: Per-workspace theme preset keyed by notes_dir; falls back to theme_preset.
+//   - theme_file:        Path to a custom JSON theme; overrides the presets when it loads.
+//   - render_style:      Preview markdown style (auto, dark, light, notty) or a Glamour JSON style file; empty follows the theme.
 //   - file_watch_interval_seconds: Poll interval for external filesystem refreshes.
+//   - slow_operation_threshold_ms: Duration after which an operation is reported as slow.
+//   - frontmatter_timestamps: Maintain created/updated frontmatter keys on save.
+//   - frontmatter_on_create: Seed new notes with a title/created/tags frontmatter block (default: false).
+//   - journal_dir:       Daily-note folder, relative to the notes directory (default: journal).
+//   - journal_template:  Seed content for new daily notes ({{date}}, {{weekday}} placeholders); templates_dir/daily.md wins.
+//   - template_date_format / template_time_format: Go layouts for template {{date}} / {{time}} (default: 2006-01-02, 15:04).
+//   - date_display_format: Go layout for frontmatter dates in the metadata popup and strip (default: Jan 2, 2006).
+//   - create_missing_dirs: Create intermediate folders for nested new-note names (default: true).
+//   - inbox_dir:         Inbox folder processed by the inbox workflow, relative to the notes directory (default: inbox).
+//   - hard_delete:       Delete permanently instead of moving items to the trash (default: false).
+//   - max_concurrent_renders: Markdown renders allowed to run at once (default: 2, max 16).
+//   - show_empty_state:  Show the getting-started panel in sparse workspaces (default: true).
+//   - empty_state_threshold: Workspaces with fewer notes than this show the panel (default: 5, max 100).
+//   - confirm_workspace_switch: Ask before switching workspaces with unsaved edits or drafts (default: true).
+//   - draft_max_age_days: Drafts older than this are purged at startup (default: 14, max 3650).
+//   - draft_max_total_mb: Cap on drafts disk usage per workspace; oldest purged first (default: 50, max 10240).
+//   - draft_orphan_skips: Times a draft of a deleted note may be skipped before it is purged (default: 2, max 10).
+//   - editor_active_line: Tint the editor row(s) holding the cursor (default: true).
+//   - git_stage_all:     Commit with "git add -A" instead of staging only notes changed in the app (default: false).
+//   - git_missing_notice: Say once per session when the git executable is not installed (default: true).
+//   - seed_welcome_note: Seed an empty notes directory with Welcome.md on launch (default: true).
+//   - empty_workspace_action: What to open on launch in an empty workspace (none, new_note, template_picker).
+//   - toggle_folders:    Two notes-relative folders the folder toggle moves notes between (default: active, done).
+//   - calc_result:       Where the editor calculator puts its result (append, replace; default: append).
+//   - calc_decimal_comma: Read and write calculator numbers with a decimal comma (default: false).
+//   - footer_priority:   Footer segments (git, status, context, help) to keep when the footer overflows, most important first.
+//   - note_age_colors:   Start with note names in the tree colored by age, bright when fresh and dim when stale (default: false).
+//   - note_age_days:     Fresh and stale age thresholds in days for note_age_colors (default: 7, 90).
+//   - export_css_file:   CSS file inlined into exported HTML pages; empty or unreadable uses the built-in stylesheet.
+//   - export_layout:     Page layout of folder exports (mirror, flatten; default: mirror).
 //
 // # Workspace Migration
 //
@@ -54,6 +90,59 @@ const (
 	ThemePresetSunset = "sunset"
 	// ThemePresetNeonSlate is the cool cyan/lime UI palette.
 	ThemePresetNeonSlate = "neon_slate"
+	// ThemePresetLight is the palette for light terminal backgrounds; it also
+	// switches markdown rendering to Glamour's light style.
+	ThemePresetLight = "light"
+
+	// TreeSortTiebreakName orders equal-key tree entries by name ascending.
+	TreeSortTiebreakName = "name"
+	// TreeSortTiebreakNameDesc orders equal-key tree entries by name descending.
+	TreeSortTiebreakNameDesc = "name_desc"
+
+	// WorkspaceOrderConfig lists workspaces in the order of the workspaces
+	// array, which the popup can rearrange.
+	WorkspaceOrderConfig = "config"
+	// WorkspaceOrderLastUsed lists the most recently activated workspace first.
+	WorkspaceOrderLastUsed = "last_used"
+
+	// EmptyWorkspaceActionNone opens an empty workspace in browse mode.
+	EmptyWorkspaceActionNone = "none"
+	// EmptyWorkspaceActionNewNote opens an empty workspace in the new-note
+	// name prompt.
+	EmptyWorkspaceActionNewNote = "new_note"
+	// EmptyWorkspaceActionTemplatePicker opens an empty workspace in the
+	// template picker (the name prompt when there are no templates).
+	EmptyWorkspaceActionTemplatePicker = "template_picker"
+
+	// CalcResultAppend writes a calculator result after the expression as
+	// " = <result>".
+	CalcResultAppend = "append"
+	// CalcResultReplace replaces the expression with its result.
+	CalcResultReplace = "replace"
+
+	// ExportLayoutMirror writes folder export pages into the same folder
+	// structure as the notes.
+	ExportLayoutMirror = "mirror"
+	// ExportLayoutFlatten writes every folder export page into the output
+	// directory itself, naming it after its folders joined with "—".
+	ExportLayoutFlatten = "flatten"
+
+	// Footer segment kinds accepted by footer_priority.
+	FooterSegmentHelp    = "help"
+	FooterSegmentContext = "context"
+	FooterSegmentGit     = "git"
+	FooterSegmentStatus  = "status"
+
+	// Built-in render_style values; anything else is a style file path.
+	RenderStyleAuto  = "auto"
+	RenderStyleDark  = "dark"
+	RenderStyleLight = "light"
+	RenderStyleNotty = "notty"
+
+	// TreeSortDirectionAsc sorts the tree's primary key ascending (A→Z, oldest, smallest).
+	TreeSortDirectionAsc = "asc"
+	// TreeSortDirectionDesc sorts the tree's primary key descending (Z→A, newest, largest).
+	TreeSortDirectionDesc = "desc"
 
 	// DefaultFileWatchIntervalSeconds is the default filesystem watcher poll interval.
 	DefaultFileWatchIntervalSeconds = 2
@@ -61,6 +150,52 @@ const (
 	MinFileWatchIntervalSeconds = 1
 	// MaxFileWatchIntervalSeconds is the upper bound for filesystem watcher poll interval.
 	MaxFileWatchIntervalSeconds = 300
+
+	// DefaultSlowOperationThresholdMs is the default slow-operation threshold.
+	DefaultSlowOperationThresholdMs = 1000
+	// MinSlowOperationThresholdMs is the lower bound for the slow-operation threshold.
+	MinSlowOperationThresholdMs = 100
+	// MaxSlowOperationThresholdMs is the upper bound for the slow-operation threshold.
+	MaxSlowOperationThresholdMs = 60000
+
+	// DefaultMaxConcurrentRenders is the default number of markdown renders
+	// allowed to run at once.
+	DefaultMaxConcurrentRenders = 2
+	// MaxConcurrentRendersLimit is the upper bound for max_concurrent_renders.
+	MaxConcurrentRendersLimit = 16
+
+	// DefaultEmptyStateThreshold is the default note count below which the
+	// getting-started panel is shown.
+	DefaultEmptyStateThreshold = 5
+	// MaxEmptyStateThreshold is the upper bound for empty_state_threshold.
+	MaxEmptyStateThreshold = 100
+
+	// DefaultFocusMinutes is the default focus session length.
+	DefaultFocusMinutes = 25
+	// MaxFocusMinutes is the upper bound for focus_minutes.
+	MaxFocusMinutes = 240
+	// DefaultBreakMinutes is the default length of the break offered after
+	// a focus session.
+	DefaultBreakMinutes = 5
+	// MaxBreakMinutes is the upper bound for break_minutes.
+	MaxBreakMinutes = 60
+
+	// MaxGitAutocommitMinutes is the upper bound for git_autocommit_minutes.
+	MaxGitAutocommitMinutes = 24 * 60
+
+	// DefaultDraftMaxAgeDays is the default age after which drafts are purged.
+	DefaultDraftMaxAgeDays = 14
+	// MaxDraftMaxAgeDays is the upper bound for draft_max_age_days.
+	MaxDraftMaxAgeDays = 3650
+	// DefaultDraftMaxTotalMB is the default cap on drafts disk usage.
+	DefaultDraftMaxTotalMB = 50
+	// MaxDraftMaxTotalMB is the upper bound for draft_max_total_mb.
+	MaxDraftMaxTotalMB = 10240
+	// DefaultDraftOrphanSkips is the default number of times a draft whose
+	// note no longer exists may be skipped before it is purged.
+	DefaultDraftOrphanSkips = 2
+	// MaxDraftOrphanSkips is the upper bound for draft_orphan_skips.
+	MaxDraftOrphanSkips = 10
 )
 
 // ErrNotConfigured is returned by Load when no config file exists, signaling
@@ -85,6 +220,16 @@ type Config struct {
 	TreeSort string `json:"tree_sort,omitempty"`
 	// TreeSortByWorkspace stores per-workspace sort mode keyed by workspace notes_dir.
 	TreeSortByWorkspace map[string]string `json:"tree_sort_by_workspace,omitempty"`
+	// TreeSortTiebreak orders entries whose primary sort key is equal
+	// (name, name_desc). Defaults to name.
+	TreeSortTiebreak string `json:"tree_sort_tiebreak,omitempty"`
+	// TreeSortDirection is the persisted sort direction (asc, desc). Empty
+	// means the mode's natural direction: ascending for name, descending for
+	// modified/size/created.
+	TreeSortDirection string `json:"tree_sort_direction,omitempty"`
+	// TreeSortDirectionByWorkspace stores per-workspace sort direction keyed
+	// by workspace notes_dir, alongside TreeSortByWorkspace.
+	TreeSortDirectionByWorkspace map[string]string `json:"tree_sort_direction_by_workspace,omitempty"`
 
 	// TemplatesDir is the directory scanned for note templates when creating
 	// new notes. Defaults to ~/.cli-notes/templates if unset.
@@ -98,21 +243,268 @@ type Config struct {
 	// Must match one of the entries in Workspaces.
 	ActiveWorkspace string `json:"active_workspace,omitempty"`
 
-	// Keybindings holds inline action→key overrides from config.json. These
-	// are merged with (and take priority over) any keymap_file bindings.
-	Keybindings map[string]string `json:"keybindings,omitempty"`
+	// Keybindings holds inline action→key overrides from config.json. Each
+	// value is a key or a list of keys. These are merged with any
+	// keymap_file bindings, which take priority.
+	Keybindings map[string]KeyList `json:"keybindings,omitempty"`
 
 	// KeymapFile is the path to an external keymap JSON file with additional
 	// keybinding overrides. Defaults to ~/.cli-notes/keymap.json if unset.
 	KeymapFile string `json:"keymap_file,omitempty"`
 
 	// ThemePreset selects the app UI color palette. Supported values:
-	// ocean_citrus, sunset, neon_slate.
+	// ocean_citrus, sunset, neon_slate, light.
 	ThemePreset string `json:"theme_preset,omitempty"`
+	// ThemePresetByWorkspace stores per-workspace theme presets keyed by
+	// workspace notes_dir. Workspaces without an entry use ThemePreset.
+	ThemePresetByWorkspace map[string]string `json:"theme_preset_by_workspace,omitempty"`
+	// ThemeFile is the path to a custom JSON theme (see the theme package).
+	// When set and loadable it replaces the presets; otherwise the preset
+	// applies.
+	ThemeFile string `json:"theme_file,omitempty"`
+	// RenderStyle selects the Glamour style of the markdown preview: "auto",
+	// "dark", "light", "notty", or the path of a Glamour JSON style file.
+	// Empty uses the theme's markdown style.
+	RenderStyle string `json:"render_style,omitempty"`
 
 	// FileWatchIntervalSeconds controls how often the app polls for external
 	// filesystem changes. Value is clamped to [1,300] and defaults to 2.
 	FileWatchIntervalSeconds int `json:"file_watch_interval_seconds,omitempty"`
+
+	// SlowOperationThresholdMs is how long opening a note, switching
+	// workspace, refreshing, or searching may take before the app reports it
+	// as slow. Value is clamped to [100,60000] and defaults to 1000.
+	SlowOperationThresholdMs int `json:"slow_operation_threshold_ms,omitempty"`
+
+	// FrontmatterTimestamps, when true, writes a created timestamp into new
+	// notes and bumps an updated timestamp on every explicit save. Draft
+	// autosave never writes the note file, so it never touches either key.
+	FrontmatterTimestamps bool `json:"frontmatter_timestamps,omitempty"`
+
+	// FrontmatterOnCreate, when true, starts new notes that have no
+	// frontmatter (from the default content or a template without a block)
+	// with title, created date, and empty tags keys.
+	FrontmatterOnCreate bool `json:"frontmatter_on_create,omitempty"`
+
+	// JournalDir is the folder for daily notes, relative to the notes
+	// directory. Defaults to "journal" when empty.
+	JournalDir string `json:"journal_dir,omitempty"`
+
+	// JournalTemplate seeds newly created daily notes. {{date}} expands to
+	// YYYY-MM-DD and {{weekday}} to the day name. Defaults to "# {{date}}".
+	JournalTemplate string `json:"journal_template,omitempty"`
+
+	// TemplateDateFormat and TemplateTimeFormat are Go time layouts for the
+	// {{date}} and {{time}} template placeholders ({{datetime}} joins both).
+	// Empty means 2006-01-02 and 15:04.
+	TemplateDateFormat string `json:"template_date_format,omitempty"`
+	TemplateTimeFormat string `json:"template_time_format,omitempty"`
+
+	// DateDisplayFormat is the Go time layout frontmatter dates are shown
+	// with in the metadata popup and strip. Empty means "Jan 2, 2006".
+	DateDisplayFormat string `json:"date_display_format,omitempty"`
+
+	// CreateMissingDirs controls whether a new note named with a nested path
+	// (e.g. projects/new/note) creates its missing intermediate folders. Nil
+	// means the default (true); use CreateMissingDirsEnabled to read it.
+	CreateMissingDirs *bool `json:"create_missing_dirs,omitempty"`
+
+	// InboxDir is the folder walked by the inbox workflow, relative to the
+	// notes directory. Defaults to "inbox" when empty.
+	InboxDir string `json:"inbox_dir,omitempty"`
+
+	// HardDelete, when true, removes deleted notes and empty folders
+	// immediately instead of moving them to <notes_dir>/.cli-notes/trash.
+	HardDelete bool `json:"hard_delete,omitempty"`
+
+	// MaxConcurrentRenders caps how many markdown previews render at once;
+	// further requests queue and superseded ones are dropped. Value is
+	// clamped to [1,16] and defaults to 2.
+	MaxConcurrentRenders int `json:"max_concurrent_renders,omitempty"`
+
+	// ShowEmptyState controls the getting-started panel shown in the right
+	// pane of sparse workspaces when no note is selected. Nil means the
+	// default (true); use EmptyStateEnabled to read it.
+	ShowEmptyState *bool `json:"show_empty_state,omitempty"`
+
+	// EmptyStateThreshold is the note count below which the getting-started
+	// panel is shown. Value is clamped to [1,100] and defaults to 5.
+	EmptyStateThreshold int `json:"empty_state_threshold,omitempty"`
+
+	// FocusMinutes is the length of a focus session. Value is clamped to
+	// [1,240] and defaults to 25.
+	FocusMinutes int `json:"focus_minutes,omitempty"`
+
+	// BreakMinutes is the length of the break offered when a focus session
+	// ends. Value is clamped to [1,60] and defaults to 5.
+	BreakMinutes int `json:"break_minutes,omitempty"`
+
+	// FocusBell, when true, rings the terminal bell when a focus session or
+	// break ends.
+	FocusBell bool `json:"focus_bell,omitempty"`
+
+	// GitAutocommitMinutes, when positive and the notes directory is a git
+	// repository, commits all changes on this interval if the working tree
+	// is dirty. Zero (the default) disables auto-commit. Value is clamped to
+	// [0,1440].
+	GitAutocommitMinutes int `json:"git_autocommit_minutes,omitempty"`
+
+	// GitStageAll, when true, makes commits stage everything with
+	// "git add -A". By default only the paths the app changed since the
+	// last commit are staged, so unrelated changes elsewhere in the
+	// repository stay out of note commits.
+	GitStageAll bool `json:"git_stage_all,omitempty"`
+
+	// GitMissingNotice controls whether a missing git executable is reported
+	// in the status bar the first time it is detected. Nil means the default
+	// (true); use GitMissingNoticeEnabled to read it.
+	GitMissingNotice *bool `json:"git_missing_notice,omitempty"`
+
+	// ConfirmWorkspaceSwitch controls whether switching workspaces asks
+	// first when the editor has unsaved edits or drafts are pending. Nil
+	// means the default (true); use ConfirmWorkspaceSwitchEnabled to read it.
+	ConfirmWorkspaceSwitch *bool `json:"confirm_workspace_switch,omitempty"`
+
+	// WorkspaceOrder selects the workspace popup order: "config" (the
+	// default) keeps the workspaces array order, "last_used" sorts by each
+	// workspace's last activation.
+	WorkspaceOrder string `json:"workspace_order,omitempty"`
+
+	// DraftMaxAgeDays purges drafts last written more than this many days
+	// ago when a workspace is opened. Value is clamped to [1,3650] and
+	// defaults to 14.
+	DraftMaxAgeDays int `json:"draft_max_age_days,omitempty"`
+
+	// DraftMaxTotalMB caps the disk usage of a workspace's drafts; beyond
+	// it the oldest drafts are purged when the workspace is opened. Value is
+	// clamped to [1,10240] and defaults to 50.
+	DraftMaxTotalMB int `json:"draft_max_total_mb,omitempty"`
+
+	// DraftOrphanSkips is how many times a draft whose note no longer exists
+	// may be skipped in the recovery prompt before it is purged. Value is
+	// clamped to [1,10] and defaults to 2.
+	DraftOrphanSkips int `json:"draft_orphan_skips,omitempty"`
+
+	// EditorActiveLine controls whether the editor tints the wrapped rows of
+	// the line holding the cursor. Nil means the default (true); use
+	// EditorActiveLineEnabled to read it.
+	EditorActiveLine *bool `json:"editor_active_line,omitempty"`
+
+	// SeedWelcomeNote controls whether an empty notes directory is seeded
+	// with Welcome.md on launch. Nil means the default (true); use
+	// WelcomeNoteEnabled to read it.
+	SeedWelcomeNote *bool `json:"seed_welcome_note,omitempty"`
+
+	// EmptyWorkspaceAction selects what an empty workspace opens into on
+	// launch: "none" (the default, browse mode), "new_note", or
+	// "template_picker". It only applies when nothing seeds the workspace,
+	// i.e. with seed_welcome_note off.
+	EmptyWorkspaceAction string `json:"empty_workspace_action,omitempty"`
+
+	// ToggleFolders names the two folders, relative to the notes directory,
+	// that the folder toggle moves notes between. Anything other than two
+	// distinct, non-nested relative folders means the default (active, done).
+	ToggleFolders []string `json:"toggle_folders,omitempty"`
+
+	// CalcResult selects where the editor calculator writes its result:
+	// "append" (the default) or "replace".
+	CalcResult string `json:"calc_result,omitempty"`
+
+	// CalcDecimalComma, when true, makes the calculator read "1,5" as one and
+	// a half and write results with a decimal comma.
+	CalcDecimalComma bool `json:"calc_decimal_comma,omitempty"`
+
+	// FooterPriority lists the footer segment kinds ("git", "status",
+	// "context", "help") that must survive when the footer does not fit,
+	// most important first. Unlisted kinds are dropped to make room, help
+	// hints first. Empty keeps the plain truncation at the end.
+	FooterPriority []string `json:"footer_priority,omitempty"`
+
+	// NoteAgeColors, when true, starts the session with note names in the
+	// tree colored by how long ago they were modified (toggled with
+	// Shift+B).
+	NoteAgeColors bool `json:"note_age_colors,omitempty"`
+
+	// NoteAgeDays holds the two age thresholds, in days, for note age
+	// colors: notes modified within the first are fresh, notes untouched
+	// for longer than the second are stale. Anything other than two
+	// increasing values in [1,36500] means the default (7, 90).
+	NoteAgeDays []int `json:"note_age_days,omitempty"`
+
+	// ExportCSSFile is the path of a stylesheet whose contents replace the
+	// built-in style of exported HTML pages. Empty, missing, or unreadable
+	// uses the built-in stylesheet.
+	ExportCSSFile string `json:"export_css_file,omitempty"`
+
+	// ExportLayout selects how "Folder to HTML" lays out its pages:
+	// "mirror" (the default) recreates the notes' folders in the output
+	// directory, "flatten" writes all pages side by side as
+	// "folder—sub—Note.html".
+	ExportLayout string `json:"export_layout,omitempty"`
+}
+
+// CreateMissingDirsEnabled reports whether new-note creation should create
+// missing intermediate folders. Defaults to true when unset.
+func (c Config) CreateMissingDirsEnabled() bool {
+	return c.CreateMissingDirs == nil || *c.CreateMissingDirs
+}
+
+// EmptyStateEnabled reports whether the getting-started panel should be
+// shown in sparse workspaces. Defaults to true when unset.
+func (c Config) EmptyStateEnabled() bool {
+	return c.ShowEmptyState == nil || *c.ShowEmptyState
+}
+
+// ConfirmWorkspaceSwitchEnabled reports whether a workspace switch with
+// unsaved work should be confirmed. Defaults to true when unset.
+func (c Config) ConfirmWorkspaceSwitchEnabled() bool {
+	return c.ConfirmWorkspaceSwitch == nil || *c.ConfirmWorkspaceSwitch
+}
+
+// EditorActiveLineEnabled reports whether the editor should highlight the
+// cursor's line. Defaults to true when unset.
+func (c Config) EditorActiveLineEnabled() bool {
+	return c.EditorActiveLine == nil || *c.EditorActiveLine
+}
+
+// GitMissingNoticeEnabled reports whether a missing git executable should
+// be reported in the status bar. Defaults to true when unset.
+func (c Config) GitMissingNoticeEnabled() bool {
+	return c.GitMissingNotice == nil || *c.GitMissingNotice
+}
+
+// WelcomeNoteEnabled reports whether an empty notes directory should be
+// seeded with the welcome note. Defaults to true when unset.
+func (c Config) WelcomeNoteEnabled() bool {
+	return c.SeedWelcomeNote == nil || *c.SeedWelcomeNote
+}
+
+// KeyList is the keys bound to one action in a keybinding override. In JSON
+// it is either a single key ("ctrl+n") or an array of keys (["N", "ctrl+n"]);
+// a single key is written back as a plain string.
+type KeyList []string
+
+// UnmarshalJSON accepts a string or an array of strings.
+func (k *KeyList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*k = KeyList{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("keybinding must be a key or a list of keys: %w", err)
+	}
+	*k = KeyList(many)
+	return nil
+}
+
+// MarshalJSON writes a single key as a string and anything else as an array.
+func (k KeyList) MarshalJSON() ([]byte, error) {
+	if len(k) == 1 {
+		return json.Marshal(k[0])
+	}
+	return json.Marshal([]string(k))
 }
 
 // WorkspaceConfig pairs a human-readable workspace name with the absolute path
@@ -121,6 +513,9 @@ type Config struct {
 type WorkspaceConfig struct {
 	Name     string `json:"name"`
 	NotesDir string `json:"notes_dir"`
+	// LastUsed is when the workspace was last switched to, in Unix seconds
+	// (0 = never). It drives workspace_order "last_used".
+	LastUsed int64 `json:"last_used,omitempty"`
 }
 
 // DefaultNotesDir returns the default notes directory used by the configurator.
@@ -180,10 +575,13 @@ func Exists() (bool, error) {
 //
 // Validation steps performed during load:
 //  1. All directory paths are normalized (~ expanded, made absolute).
-//  2. TreeSort defaults to "name" if empty.
+//  2. TreeSort defaults to "name" if empty; TreeSortTiebreak defaults to "name"
+//     when missing or invalid; unknown TreeSortDirection values are cleared.
 //  3. TemplatesDir defaults to ~/.cli-notes/templates if empty.
 //  4. KeymapFile defaults to ~/.cli-notes/keymap.json if empty.
-//  5. ThemePreset defaults to ocean_citrus when missing or invalid.
+//  5. ThemePreset defaults to ocean_citrus when missing or invalid; invalid
+//     ThemePresetByWorkspace entries are dropped. A non-empty ThemeFile is
+//     normalized like the directories; its contents are read by the app.
 //  6. Workspaces are normalized: names are validated for uniqueness, directories
 //     are expanded and checked for duplicates. If no workspaces are configured,
 //     a "default" workspace is created from the legacy notes_dir field.
@@ -224,6 +622,9 @@ func Load() (Config, error) {
 		cfg.TreeSort = "name"
 	}
 	cfg.TreeSortByWorkspace = normalizeTreeSortByWorkspace(cfg.TreeSortByWorkspace)
+	cfg.TreeSortTiebreak = NormalizeTreeSortTiebreak(cfg.TreeSortTiebreak)
+	cfg.TreeSortDirection = NormalizeTreeSortDirection(cfg.TreeSortDirection)
+	cfg.TreeSortDirectionByWorkspace = normalizeTreeSortDirectionByWorkspace(cfg.TreeSortDirectionByWorkspace)
 
 	templatesDir := strings.TrimSpace(cfg.TemplatesDir)
 	if templatesDir == "" {
@@ -250,9 +651,41 @@ func Load() (Config, error) {
 	}
 	cfg.KeymapFile = keymapPath
 	cfg.ThemePreset = NormalizeThemePreset(cfg.ThemePreset)
+	cfg.ThemePresetByWorkspace = normalizeThemePresetByWorkspace(cfg.ThemePresetByWorkspace)
+	if themeFile := strings.TrimSpace(cfg.ThemeFile); themeFile != "" {
+		themeFile, err = NormalizeNotesDir(themeFile)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid theme_file: %w", err)
+		}
+		cfg.ThemeFile = themeFile
+	}
+	cfg.RenderStyle = NormalizeRenderStyle(cfg.RenderStyle)
+	if cssFile := strings.TrimSpace(cfg.ExportCSSFile); cssFile != "" {
+		cssFile, err = NormalizeNotesDir(cssFile)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid export_css_file: %w", err)
+		}
+		cfg.ExportCSSFile = cssFile
+	}
 	cfg.FileWatchIntervalSeconds = normalizeFileWatchIntervalSeconds(cfg.FileWatchIntervalSeconds)
+	cfg.SlowOperationThresholdMs = normalizeSlowOperationThresholdMs(cfg.SlowOperationThresholdMs)
+	cfg.MaxConcurrentRenders = normalizeMaxConcurrentRenders(cfg.MaxConcurrentRenders)
+	cfg.EmptyStateThreshold = normalizeEmptyStateThreshold(cfg.EmptyStateThreshold)
+	cfg.FocusMinutes = normalizeFocusMinutes(cfg.FocusMinutes)
+	cfg.BreakMinutes = normalizeBreakMinutes(cfg.BreakMinutes)
+	cfg.GitAutocommitMinutes = normalizeGitAutocommitMinutes(cfg.GitAutocommitMinutes)
+	cfg.WorkspaceOrder = NormalizeWorkspaceOrder(cfg.WorkspaceOrder)
+	cfg.EmptyWorkspaceAction = NormalizeEmptyWorkspaceAction(cfg.EmptyWorkspaceAction)
+	cfg.ToggleFolders = NormalizeToggleFolders(cfg.ToggleFolders)
+	cfg.CalcResult = NormalizeCalcResult(cfg.CalcResult)
+	cfg.FooterPriority = NormalizeFooterPriority(cfg.FooterPriority)
+	cfg.NoteAgeDays = NormalizeNoteAgeDays(cfg.NoteAgeDays)
+	cfg.ExportLayout = NormalizeExportLayout(cfg.ExportLayout)
+	cfg.DraftMaxAgeDays = normalizeDraftMaxAgeDays(cfg.DraftMaxAgeDays)
+	cfg.DraftMaxTotalMB = normalizeDraftMaxTotalMB(cfg.DraftMaxTotalMB)
+	cfg.DraftOrphanSkips = normalizeDraftOrphanSkips(cfg.DraftOrphanSkips)
 	if cfg.Keybindings == nil {
-		cfg.Keybindings = map[string]string{}
+		cfg.Keybindings = map[string]KeyList{}
 	}
 	if len(cfg.Workspaces) == 0 && strings.TrimSpace(cfg.NotesDir) == "" {
 		return Config{}, fmt.Errorf("invalid notes_dir: %w", errors.New("path is required"))
@@ -296,6 +729,9 @@ func Save(cfg Config) error {
 		cfg.TreeSort = "name"
 	}
 	cfg.TreeSortByWorkspace = normalizeTreeSortByWorkspace(cfg.TreeSortByWorkspace)
+	cfg.TreeSortTiebreak = NormalizeTreeSortTiebreak(cfg.TreeSortTiebreak)
+	cfg.TreeSortDirection = NormalizeTreeSortDirection(cfg.TreeSortDirection)
+	cfg.TreeSortDirectionByWorkspace = normalizeTreeSortDirectionByWorkspace(cfg.TreeSortDirectionByWorkspace)
 
 	templatesDir := strings.TrimSpace(cfg.TemplatesDir)
 	if templatesDir == "" {
@@ -322,7 +758,39 @@ func Save(cfg Config) error {
 	}
 	cfg.KeymapFile = keymapPath
 	cfg.ThemePreset = NormalizeThemePreset(cfg.ThemePreset)
+	cfg.ThemePresetByWorkspace = normalizeThemePresetByWorkspace(cfg.ThemePresetByWorkspace)
+	if themeFile := strings.TrimSpace(cfg.ThemeFile); themeFile != "" {
+		themeFile, err = NormalizeNotesDir(themeFile)
+		if err != nil {
+			return fmt.Errorf("invalid theme_file: %w", err)
+		}
+		cfg.ThemeFile = themeFile
+	}
+	cfg.RenderStyle = NormalizeRenderStyle(cfg.RenderStyle)
+	if cssFile := strings.TrimSpace(cfg.ExportCSSFile); cssFile != "" {
+		cssFile, err = NormalizeNotesDir(cssFile)
+		if err != nil {
+			return fmt.Errorf("invalid export_css_file: %w", err)
+		}
+		cfg.ExportCSSFile = cssFile
+	}
 	cfg.FileWatchIntervalSeconds = normalizeFileWatchIntervalSeconds(cfg.FileWatchIntervalSeconds)
+	cfg.SlowOperationThresholdMs = normalizeSlowOperationThresholdMs(cfg.SlowOperationThresholdMs)
+	cfg.MaxConcurrentRenders = normalizeMaxConcurrentRenders(cfg.MaxConcurrentRenders)
+	cfg.EmptyStateThreshold = normalizeEmptyStateThreshold(cfg.EmptyStateThreshold)
+	cfg.FocusMinutes = normalizeFocusMinutes(cfg.FocusMinutes)
+	cfg.BreakMinutes = normalizeBreakMinutes(cfg.BreakMinutes)
+	cfg.GitAutocommitMinutes = normalizeGitAutocommitMinutes(cfg.GitAutocommitMinutes)
+	cfg.WorkspaceOrder = NormalizeWorkspaceOrder(cfg.WorkspaceOrder)
+	cfg.EmptyWorkspaceAction = NormalizeEmptyWorkspaceAction(cfg.EmptyWorkspaceAction)
+	cfg.ToggleFolders = NormalizeToggleFolders(cfg.ToggleFolders)
+	cfg.CalcResult = NormalizeCalcResult(cfg.CalcResult)
+	cfg.FooterPriority = NormalizeFooterPriority(cfg.FooterPriority)
+	cfg.NoteAgeDays = NormalizeNoteAgeDays(cfg.NoteAgeDays)
+	cfg.ExportLayout = NormalizeExportLayout(cfg.ExportLayout)
+	cfg.DraftMaxAgeDays = normalizeDraftMaxAgeDays(cfg.DraftMaxAgeDays)
+	cfg.DraftMaxTotalMB = normalizeDraftMaxTotalMB(cfg.DraftMaxTotalMB)
+	cfg.DraftOrphanSkips = normalizeDraftOrphanSkips(cfg.DraftOrphanSkips)
 	if len(cfg.Workspaces) == 0 && strings.TrimSpace(cfg.NotesDir) == "" {
 		return fmt.Errorf("invalid notes_dir: %w", errors.New("path is required"))
 	}
@@ -340,7 +808,7 @@ func Save(cfg Config) error {
 		}
 	}
 	if cfg.Keybindings == nil {
-		cfg.Keybindings = map[string]string{}
+		cfg.Keybindings = map[string]KeyList{}
 	}
 	path, err := ConfigPath()
 	if err != nil {
@@ -414,6 +882,14 @@ func expandHome(path string) (string, error) {
 	return path, nil
 }
 
+// ValidateWorkspaces applies the rules Load and Save enforce on a workspace
+// list (required names, valid notes dirs, no duplicate names or notes dirs)
+// and returns the normalized list.
+func ValidateWorkspaces(workspaces []WorkspaceConfig) ([]WorkspaceConfig, error) {
+	normalized, _, err := normalizeWorkspaces(workspaces, "", "")
+	return normalized, err
+}
+
 // normalizeWorkspaces validates and normalizes the workspace list.
 //
 // It enforces the following invariants:
@@ -429,7 +905,9 @@ func normalizeWorkspaces(workspaces []WorkspaceConfig, activeWorkspace string, f
 	normalized := make([]WorkspaceConfig, 0, len(workspaces)+1)
 	seenNames := map[string]bool{}
 	seenDirs := map[string]bool{}
-	addWorkspace := func(name, notesDir string) error {
+	// Workspaces keep the order they were given in; the popup's ordering
+	// controls rely on it.
+	addWorkspace := func(name, notesDir string, lastUsed int64) error {
 		name = strings.TrimSpace(name)
 		if name == "" {
 			return errors.New("workspace name is required")
@@ -447,12 +925,12 @@ func normalizeWorkspaces(workspaces []WorkspaceConfig, activeWorkspace string, f
 		}
 		seenNames[lower] = true
 		seenDirs[notesDir] = true
-		normalized = append(normalized, WorkspaceConfig{Name: name, NotesDir: notesDir})
+		normalized = append(normalized, WorkspaceConfig{Name: name, NotesDir: notesDir, LastUsed: max(0, lastUsed)})
 		return nil
 	}
 
 	for _, ws := range workspaces {
-		if err := addWorkspace(ws.Name, ws.NotesDir); err != nil {
+		if err := addWorkspace(ws.Name, ws.NotesDir, ws.LastUsed); err != nil {
 			return nil, "", err
 		}
 	}
@@ -462,7 +940,7 @@ func normalizeWorkspaces(workspaces []WorkspaceConfig, activeWorkspace string, f
 		if fallback == "" {
 			return nil, "", errors.New("at least one workspace is required")
 		}
-		if err := addWorkspace("default", fallback); err != nil {
+		if err := addWorkspace("default", fallback, 0); err != nil {
 			return nil, "", err
 		}
 	}
@@ -503,21 +981,282 @@ func normalizeTreeSortByWorkspace(raw map[string]string) map[string]string {
 	return normalized
 }
 
+// normalizeTreeSortDirectionByWorkspace normalizes workspace keys and drops
+// entries whose direction is empty or unknown.
+func normalizeTreeSortDirectionByWorkspace(raw map[string]string) map[string]string {
+	if len(raw) == 0 {
+		return map[string]string{}
+	}
+	normalized := make(map[string]string, len(raw))
+	for notesDir, direction := range raw {
+		dir, err := NormalizeNotesDir(notesDir)
+		if err != nil {
+			continue
+		}
+		if value := NormalizeTreeSortDirection(direction); value != "" {
+			normalized[dir] = value
+		}
+	}
+	return normalized
+}
+
+// NormalizeTreeSortDirection canonicalizes a tree sort direction to "asc" or
+// "desc". Empty or unknown values return "", meaning the sort mode's natural
+// direction.
+func NormalizeTreeSortDirection(raw string) string {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case TreeSortDirectionAsc, "ascending":
+		return TreeSortDirectionAsc
+	case TreeSortDirectionDesc, "descending":
+		return TreeSortDirectionDesc
+	default:
+		return ""
+	}
+}
+
+// NormalizeWorkspaceOrder canonicalizes the workspace popup order and falls
+// back to "config" when the value is empty or unknown.
+func NormalizeWorkspaceOrder(raw string) string {
+	normalized := strings.ToLower(strings.TrimSpace(raw))
+	normalized = strings.NewReplacer("-", "_", " ", "_").Replace(normalized)
+	switch normalized {
+	case WorkspaceOrderLastUsed, "recent":
+		return WorkspaceOrderLastUsed
+	default:
+		return WorkspaceOrderConfig
+	}
+}
+
+// NormalizeEmptyWorkspaceAction canonicalizes the empty-workspace launch
+// action and falls back to "none" when the value is empty or unknown.
+func NormalizeEmptyWorkspaceAction(raw string) string {
+	normalized := strings.ToLower(strings.TrimSpace(raw))
+	normalized = strings.NewReplacer("-", "_", " ", "_").Replace(normalized)
+	switch normalized {
+	case EmptyWorkspaceActionNewNote, "new":
+		return EmptyWorkspaceActionNewNote
+	case EmptyWorkspaceActionTemplatePicker, "template", "templates":
+		return EmptyWorkspaceActionTemplatePicker
+	default:
+		return EmptyWorkspaceActionNone
+	}
+}
+
+// NormalizeRenderStyle canonicalizes render_style: a built-in style name is
+// lowercased and anything else is treated as a style file path, with ~
+// expanded and made absolute. A path that cannot be expanded is kept as
+// written; loading it fails later and falls back to auto.
+func NormalizeRenderStyle(raw string) string {
+	style := strings.TrimSpace(raw)
+	switch lower := strings.ToLower(style); lower {
+	case "", RenderStyleAuto, RenderStyleDark, RenderStyleLight, RenderStyleNotty:
+		return lower
+	}
+	if path, err := NormalizeNotesDir(style); err == nil {
+		return path
+	}
+	return style
+}
+
+// NormalizeCalcResult canonicalizes the calculator result placement and
+// falls back to "append" when the value is empty or unknown.
+func NormalizeCalcResult(raw string) string {
+	if strings.ToLower(strings.TrimSpace(raw)) == CalcResultReplace {
+		return CalcResultReplace
+	}
+	return CalcResultAppend
+}
+
+// NormalizeExportLayout canonicalizes the folder export layout and falls
+// back to "mirror" when the value is empty or unknown.
+func NormalizeExportLayout(raw string) string {
+	if strings.ToLower(strings.TrimSpace(raw)) == ExportLayoutFlatten {
+		return ExportLayoutFlatten
+	}
+	return ExportLayoutMirror
+}
+
+// NormalizeFooterPriority canonicalizes the footer segment kinds, dropping
+// unknown and repeated entries, and returns nil when none remain.
+func NormalizeFooterPriority(raw []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, kind := range raw {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if kind == "keys" {
+			kind = FooterSegmentHelp
+		}
+		switch kind {
+		case FooterSegmentHelp, FooterSegmentContext, FooterSegmentGit, FooterSegmentStatus:
+		default:
+			continue
+		}
+		if !seen[kind] {
+			seen[kind] = true
+			out = append(out, kind)
+		}
+	}
+	return out
+}
+
+// NormalizeNoteAgeDays returns the fresh and stale note age thresholds, or
+// nil (the default pair) unless they are two increasing day counts in
+// [1,36500].
+func NormalizeNoteAgeDays(raw []int) []int {
+	if len(raw) != 2 || raw[0] < 1 || raw[1] > 36500 || raw[0] >= raw[1] {
+		return nil
+	}
+	return []int{raw[0], raw[1]}
+}
+
+// NormalizeToggleFolders cleans the two toggle folders and returns nil
+// (the default pair) unless they are two distinct relative folders inside
+// the notes directory, neither containing the other.
+func NormalizeToggleFolders(raw []string) []string {
+	if len(raw) != 2 {
+		return nil
+	}
+	out := make([]string, 0, 2)
+	for _, dir := range raw {
+		dir = filepath.Clean(strings.TrimSpace(dir))
+		if dir == "." || filepath.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+			return nil
+		}
+		out = append(out, dir)
+	}
+	if out[0] == out[1] ||
+		strings.HasPrefix(out[0], out[1]+string(filepath.Separator)) ||
+		strings.HasPrefix(out[1], out[0]+string(filepath.Separator)) {
+		return nil
+	}
+	return out
+}
+
+// NormalizeTreeSortTiebreak canonicalizes the tree sort tiebreaker and falls
+// back to "name" when the value is empty or unknown.
+func NormalizeTreeSortTiebreak(raw string) string {
+	normalized := strings.ToLower(strings.TrimSpace(raw))
+	normalized = strings.NewReplacer("-", "_", " ", "_").Replace(normalized)
+	switch normalized {
+	case TreeSortTiebreakNameDesc, "desc":
+		return TreeSortTiebreakNameDesc
+	default:
+		return TreeSortTiebreakName
+	}
+}
+
 // NormalizeThemePreset canonicalizes theme preset names and falls back to the
 // default preset when the value is empty or unknown.
 func NormalizeThemePreset(raw string) string {
+	if preset, ok := lookupThemePreset(raw); ok {
+		return preset
+	}
+	return ThemePresetOceanCitrus
+}
+
+// lookupThemePreset canonicalizes a theme preset name, reporting false when
+// it is empty or unknown.
+func lookupThemePreset(raw string) (string, bool) {
 	normalized := strings.ToLower(strings.TrimSpace(raw))
 	normalized = strings.NewReplacer("-", "_", " ", "_", "/", "_").Replace(normalized)
 	switch normalized {
-	case "", ThemePresetOceanCitrus, "oceancitrus":
-		return ThemePresetOceanCitrus
+	case ThemePresetOceanCitrus, "oceancitrus":
+		return ThemePresetOceanCitrus, true
 	case ThemePresetSunset:
-		return ThemePresetSunset
+		return ThemePresetSunset, true
 	case ThemePresetNeonSlate, "neonslate":
-		return ThemePresetNeonSlate
+		return ThemePresetNeonSlate, true
+	case ThemePresetLight, "light_mode":
+		return ThemePresetLight, true
 	default:
-		return ThemePresetOceanCitrus
+		return "", false
+	}
+}
+
+// normalizeThemePresetByWorkspace normalizes workspace keys and presets and
+// drops entries whose preset is empty or unknown, so those workspaces fall
+// back to theme_preset.
+func normalizeThemePresetByWorkspace(raw map[string]string) map[string]string {
+	if len(raw) == 0 {
+		return map[string]string{}
+	}
+	normalized := make(map[string]string, len(raw))
+	for notesDir, preset := range raw {
+		dir, err := NormalizeNotesDir(notesDir)
+		if err != nil {
+			continue
+		}
+		if value, ok := lookupThemePreset(preset); ok {
+			normalized[dir] = value
+		}
+	}
+	return normalized
+}
+
+func normalizeSlowOperationThresholdMs(value int) int {
+	if value <= 0 {
+		return DefaultSlowOperationThresholdMs
+	}
+	if value < MinSlowOperationThresholdMs {
+		return MinSlowOperationThresholdMs
+	}
+	if value > MaxSlowOperationThresholdMs {
+		return MaxSlowOperationThresholdMs
+	}
+	return value
+}
+
+func normalizeMaxConcurrentRenders(value int) int {
+	if value <= 0 {
+		return DefaultMaxConcurrentRenders
+	}
+	return min(value, MaxConcurrentRendersLimit)
+}
+
+func normalizeEmptyStateThreshold(value int) int {
+	if value <= 0 {
+		return DefaultEmptyStateThreshold
+	}
+	return min(value, MaxEmptyStateThreshold)
+}
+
+func normalizeFocusMinutes(value int) int {
+	if value <= 0 {
+		return DefaultFocusMinutes
+	}
+	return min(value, MaxFocusMinutes)
+}
+
+func normalizeBreakMinutes(value int) int {
+	if value <= 0 {
+		return DefaultBreakMinutes
+	}
+	return min(value, MaxBreakMinutes)
+}
+
+func normalizeGitAutocommitMinutes(value int) int {
+	return max(0, min(value, MaxGitAutocommitMinutes))
+}
+
+func normalizeDraftMaxAgeDays(value int) int {
+	if value <= 0 {
+		return DefaultDraftMaxAgeDays
+	}
+	return min(value, MaxDraftMaxAgeDays)
+}
+
+func normalizeDraftMaxTotalMB(value int) int {
+	if value <= 0 {
+		return DefaultDraftMaxTotalMB
+	}
+	return min(value, MaxDraftMaxTotalMB)
+}
+
+func normalizeDraftOrphanSkips(value int) int {
+	if value <= 0 {
+		return DefaultDraftOrphanSkips
 	}
+	return min(value, MaxDraftOrphanSkips)
 }
 
 func normalizeFileWatchIntervalSeconds(value int) int {
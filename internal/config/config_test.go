@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -243,6 +244,69 @@ func TestLoadNormalizesThemePreset(t *testing.T) {
 	}
 }
 
+func TestNormalizeThemePresetAcceptsLight(t *testing.T) {
+	for _, raw := range []string{"light", " Light ", "light-mode"} {
+		if got := NormalizeThemePreset(raw); got != ThemePresetLight {
+			t.Fatalf("NormalizeThemePreset(%q) = %q, want %q", raw, got, ThemePresetLight)
+		}
+	}
+}
+
+func TestLoadExpandsThemeFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatalf("config path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	data := `{
+  "notes_dir": "~/notes",
+  "theme_file": "~/themes/dusk.json"
+}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if want := filepath.Join(home, "themes", "dusk.json"); cfg.ThemeFile != want {
+		t.Fatalf("expected theme file %q, got %q", want, cfg.ThemeFile)
+	}
+	if cfg.ThemePreset != ThemePresetOceanCitrus {
+		t.Fatalf("expected preset kept as fallback, got %q", cfg.ThemePreset)
+	}
+}
+
+func TestLoadExpandsExportCSSFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatalf("config path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	data := `{
+  "notes_dir": "~/notes",
+  "export_css_file": " ~/styles/brand.css "
+}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if want := filepath.Join(home, "styles", "brand.css"); cfg.ExportCSSFile != want {
+		t.Fatalf("expected export css file %q, got %q", want, cfg.ExportCSSFile)
+	}
+}
+
 func TestLoadFallsBackToDefaultThemePresetOnInvalidValue(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
@@ -318,3 +382,532 @@ func TestFileWatchIntervalClampsToBounds(t *testing.T) {
 		t.Fatalf("expected default interval %d for invalid value, got %d", DefaultFileWatchIntervalSeconds, cfg.FileWatchIntervalSeconds)
 	}
 }
+
+func TestTreeSortTiebreakNormalizesAndDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if err := Save(Config{NotesDir: "~/notes"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.TreeSortTiebreak != TreeSortTiebreakName {
+		t.Fatalf("expected default tiebreak %q, got %q", TreeSortTiebreakName, cfg.TreeSortTiebreak)
+	}
+
+	if err := Save(Config{NotesDir: "~/notes", TreeSortTiebreak: "Name-Desc"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.TreeSortTiebreak != TreeSortTiebreakNameDesc {
+		t.Fatalf("expected normalized tiebreak %q, got %q", TreeSortTiebreakNameDesc, cfg.TreeSortTiebreak)
+	}
+
+	if err := Save(Config{NotesDir: "~/notes", TreeSortTiebreak: "bogus"}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.TreeSortTiebreak != TreeSortTiebreakName {
+		t.Fatalf("expected fallback tiebreak %q, got %q", TreeSortTiebreakName, cfg.TreeSortTiebreak)
+	}
+}
+
+func TestTreeSortDirectionNormalizesPerWorkspace(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	notesDir := filepath.Join(home, "notes")
+
+	if err := Save(Config{
+		NotesDir:          notesDir,
+		TreeSortDirection: "Descending",
+		TreeSortDirectionByWorkspace: map[string]string{
+			notesDir:  "ASC",
+			"~/other": "sideways",
+		},
+	}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.TreeSortDirection != TreeSortDirectionDesc {
+		t.Fatalf("expected normalized direction %q, got %q", TreeSortDirectionDesc, cfg.TreeSortDirection)
+	}
+	if got := cfg.TreeSortDirectionByWorkspace[notesDir]; got != TreeSortDirectionAsc {
+		t.Fatalf("expected workspace direction %q, got %q", TreeSortDirectionAsc, got)
+	}
+	if len(cfg.TreeSortDirectionByWorkspace) != 1 {
+		t.Fatalf("expected invalid direction to be dropped, got %v", cfg.TreeSortDirectionByWorkspace)
+	}
+}
+
+func TestFrontmatterTimestampsRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if err := Save(Config{NotesDir: "~/notes", FrontmatterTimestamps: true}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if !cfg.FrontmatterTimestamps {
+		t.Fatal("expected frontmatter_timestamps to round-trip")
+	}
+}
+
+func TestHardDeleteRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if err := Save(Config{NotesDir: "~/notes", HardDelete: true}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if !cfg.HardDelete {
+		t.Fatal("expected hard_delete to round-trip")
+	}
+}
+
+func TestKeybindingsAcceptKeyOrKeyList(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatalf("config path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	raw := `{"notes_dir": "~/notes", "keybindings": {"note.new": ["N", "ctrl+n"], "tree.sort.cycle": "S"}}`
+	if err := os.WriteFile(path, []byte(raw), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := cfg.Keybindings["note.new"]; len(got) != 2 || got[0] != "N" || got[1] != "ctrl+n" {
+		t.Fatalf("note.new = %v", got)
+	}
+	if got := cfg.Keybindings["tree.sort.cycle"]; len(got) != 1 || got[0] != "S" {
+		t.Fatalf("tree.sort.cycle = %v", got)
+	}
+
+	if err := Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	for _, want := range []string{`"note.new": [`, `"tree.sort.cycle": "S"`} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("saved config missing %s:\n%s", want, data)
+		}
+	}
+
+	var keys KeyList
+	if err := keys.UnmarshalJSON([]byte(`42`)); err == nil {
+		t.Fatal("expected a number to be rejected")
+	}
+}
+
+func TestCreateMissingDirsDefaultsOnAndRoundTripsOff(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if !(Config{}).CreateMissingDirsEnabled() {
+		t.Fatal("expected create_missing_dirs to default to true")
+	}
+	off := false
+	if err := Save(Config{NotesDir: "~/notes", CreateMissingDirs: &off}); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.CreateMissingDirsEnabled() {
+		t.Fatal("expected create_missing_dirs=false to round-trip")
+	}
+}
+
+func TestSlowOperationThresholdDefaultsAndClamps(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for _, tc := range []struct {
+		raw  int
+		want int
+	}{
+		{0, DefaultSlowOperationThresholdMs},
+		{5, MinSlowOperationThresholdMs},
+		{2500, 2500},
+		{999999, MaxSlowOperationThresholdMs},
+	} {
+		if err := Save(Config{NotesDir: "~/notes", SlowOperationThresholdMs: tc.raw}); err != nil {
+			t.Fatalf("save config: %v", err)
+		}
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("load config: %v", err)
+		}
+		if cfg.SlowOperationThresholdMs != tc.want {
+			t.Fatalf("threshold %d: expected %d, got %d", tc.raw, tc.want, cfg.SlowOperationThresholdMs)
+		}
+	}
+}
+
+func TestMaxConcurrentRendersDefaultsAndClamps(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for _, tc := range []struct {
+		raw  int
+		want int
+	}{
+		{0, DefaultMaxConcurrentRenders},
+		{-3, DefaultMaxConcurrentRenders},
+		{1, 1},
+		{4, 4},
+		{100, MaxConcurrentRendersLimit},
+	} {
+		if err := Save(Config{NotesDir: "~/notes", MaxConcurrentRenders: tc.raw}); err != nil {
+			t.Fatalf("save config: %v", err)
+		}
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("load config: %v", err)
+		}
+		if cfg.MaxConcurrentRenders != tc.want {
+			t.Fatalf("max renders %d: expected %d, got %d", tc.raw, tc.want, cfg.MaxConcurrentRenders)
+		}
+	}
+}
+
+func TestEmptyStateSettingsDefaultAndClamp(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if !(Config{}).EmptyStateEnabled() {
+		t.Fatal("expected empty state panel to default on")
+	}
+	off := false
+	for _, tc := range []struct {
+		raw  int
+		want int
+	}{
+		{0, DefaultEmptyStateThreshold},
+		{-1, DefaultEmptyStateThreshold},
+		{12, 12},
+		{1000, MaxEmptyStateThreshold},
+	} {
+		if err := Save(Config{NotesDir: "~/notes", ShowEmptyState: &off, EmptyStateThreshold: tc.raw}); err != nil {
+			t.Fatalf("save config: %v", err)
+		}
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("load config: %v", err)
+		}
+		if cfg.EmptyStateEnabled() {
+			t.Fatal("expected show_empty_state=false to round-trip")
+		}
+		if cfg.EmptyStateThreshold != tc.want {
+			t.Fatalf("threshold %d: expected %d, got %d", tc.raw, tc.want, cfg.EmptyStateThreshold)
+		}
+	}
+}
+
+func TestFocusSettingsDefaultAndClamp(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for _, tc := range []struct {
+		focus, brk         int
+		wantFocus, wantBrk int
+	}{
+		{0, 0, DefaultFocusMinutes, DefaultBreakMinutes},
+		{-5, -1, DefaultFocusMinutes, DefaultBreakMinutes},
+		{50, 10, 50, 10},
+		{1000, 1000, MaxFocusMinutes, MaxBreakMinutes},
+	} {
+		if err := Save(Config{NotesDir: "~/notes", FocusMinutes: tc.focus, BreakMinutes: tc.brk, FocusBell: true}); err != nil {
+			t.Fatalf("save config: %v", err)
+		}
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("load config: %v", err)
+		}
+		if cfg.FocusMinutes != tc.wantFocus || cfg.BreakMinutes != tc.wantBrk {
+			t.Fatalf("focus %d/break %d: expected %d/%d, got %d/%d", tc.focus, tc.brk, tc.wantFocus, tc.wantBrk, cfg.FocusMinutes, cfg.BreakMinutes)
+		}
+		if !cfg.FocusBell {
+			t.Fatal("expected focus_bell to round-trip")
+		}
+	}
+}
+
+func TestGitAutocommitMinutesClamp(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for _, tc := range []struct {
+		raw  int
+		want int
+	}{
+		{0, 0},
+		{-3, 0},
+		{15, 15},
+		{100000, MaxGitAutocommitMinutes},
+	} {
+		if err := Save(Config{NotesDir: "~/notes", GitAutocommitMinutes: tc.raw}); err != nil {
+			t.Fatalf("save config: %v", err)
+		}
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("load config: %v", err)
+		}
+		if cfg.GitAutocommitMinutes != tc.want {
+			t.Fatalf("autocommit %d: expected %d, got %d", tc.raw, tc.want, cfg.GitAutocommitMinutes)
+		}
+	}
+}
+
+func TestValidateWorkspacesRejectsCollisions(t *testing.T) {
+	base := []WorkspaceConfig{{Name: "Work", NotesDir: "/tmp/work"}}
+	if _, err := ValidateWorkspaces(append(base, WorkspaceConfig{Name: "work", NotesDir: "/tmp/other"})); err == nil {
+		t.Fatal("expected case-insensitive duplicate name to be rejected")
+	}
+	if _, err := ValidateWorkspaces(append(base, WorkspaceConfig{Name: "Other", NotesDir: "/tmp/work/"})); err == nil {
+		t.Fatal("expected duplicate notes dir to be rejected")
+	}
+	got, err := ValidateWorkspaces(append(base, WorkspaceConfig{Name: " Personal ", NotesDir: "/tmp/personal/../personal"}))
+	if err != nil {
+		t.Fatalf("validate workspaces: %v", err)
+	}
+	if len(got) != 2 || got[1] != (WorkspaceConfig{Name: "Personal", NotesDir: "/tmp/personal"}) {
+		t.Fatalf("unexpected normalized workspaces %+v", got)
+	}
+}
+
+func TestConfirmWorkspaceSwitchDefaultsOn(t *testing.T) {
+	if !(Config{}).ConfirmWorkspaceSwitchEnabled() {
+		t.Fatal("expected confirm_workspace_switch to default to true")
+	}
+	off := false
+	if (Config{ConfirmWorkspaceSwitch: &off}).ConfirmWorkspaceSwitchEnabled() {
+		t.Fatal("expected explicit false to disable the confirmation")
+	}
+}
+
+func TestWorkspaceOrderAndLastUsedRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	work := filepath.Join(home, "work")
+	personal := filepath.Join(home, "personal")
+	for _, dir := range []string{work, personal} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	cfg := Config{
+		NotesDir:        personal,
+		WorkspaceOrder:  "recent",
+		Workspaces:      []WorkspaceConfig{{Name: "Personal", NotesDir: personal, LastUsed: 200}, {Name: "Work", NotesDir: work, LastUsed: 100}},
+		ActiveWorkspace: "Personal",
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	got, err := Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got.WorkspaceOrder != WorkspaceOrderLastUsed {
+		t.Fatalf("expected recent to normalize to last_used, got %q", got.WorkspaceOrder)
+	}
+	if len(got.Workspaces) != 2 || got.Workspaces[0].Name != "Personal" || got.Workspaces[0].LastUsed != 200 || got.Workspaces[1].LastUsed != 100 {
+		t.Fatalf("expected order and last_used preserved, got %+v", got.Workspaces)
+	}
+	if NormalizeWorkspaceOrder("bogus") != WorkspaceOrderConfig {
+		t.Fatal("expected unknown workspace_order to fall back to config")
+	}
+}
+
+func TestEmptyWorkspaceActionNormalizes(t *testing.T) {
+	cases := map[string]string{
+		"":                 EmptyWorkspaceActionNone,
+		"bogus":            EmptyWorkspaceActionNone,
+		"New-Note":         EmptyWorkspaceActionNewNote,
+		" template picker": EmptyWorkspaceActionTemplatePicker,
+		"template":         EmptyWorkspaceActionTemplatePicker,
+	}
+	for raw, want := range cases {
+		if got := NormalizeEmptyWorkspaceAction(raw); got != want {
+			t.Fatalf("NormalizeEmptyWorkspaceAction(%q) = %q, want %q", raw, got, want)
+		}
+	}
+	off := false
+	if !(Config{}).WelcomeNoteEnabled() || (Config{SeedWelcomeNote: &off}).WelcomeNoteEnabled() {
+		t.Fatal("expected welcome seeding on by default and off when disabled")
+	}
+}
+
+func TestToggleFoldersNormalize(t *testing.T) {
+	if got := NormalizeToggleFolders([]string{" todo/ ", "done"}); strings.Join(got, ",") != "todo,done" {
+		t.Fatalf("unexpected cleaned folders %v", got)
+	}
+	for _, raw := range [][]string{nil, {"one"}, {"a", "a"}, {"a", "a/b"}, {"../out", "done"}, {"/abs", "done"}, {".", "done"}} {
+		if got := NormalizeToggleFolders(raw); got != nil {
+			t.Fatalf("NormalizeToggleFolders(%q) = %v, want nil", raw, got)
+		}
+	}
+}
+
+func TestExportLayoutNormalize(t *testing.T) {
+	for raw, want := range map[string]string{"": ExportLayoutMirror, " Flatten ": ExportLayoutFlatten, "tree": ExportLayoutMirror} {
+		if got := NormalizeExportLayout(raw); got != want {
+			t.Fatalf("NormalizeExportLayout(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestNoteAgeDaysNormalize(t *testing.T) {
+	if got := NormalizeNoteAgeDays([]int{3, 30}); len(got) != 2 || got[0] != 3 || got[1] != 30 {
+		t.Fatalf("unexpected thresholds %v", got)
+	}
+	for _, raw := range [][]int{nil, {7}, {0, 30}, {30, 30}, {90, 7}, {1, 40000}, {1, 2, 3}} {
+		if got := NormalizeNoteAgeDays(raw); got != nil {
+			t.Fatalf("NormalizeNoteAgeDays(%v) = %v, want nil", raw, got)
+		}
+	}
+}
+
+func TestThemePresetByWorkspaceNormalizesAndDropsInvalid(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path, err := ConfigPath()
+	if err != nil {
+		t.Fatalf("config path: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	data := `{
+  "notes_dir": "~/notes",
+  "theme_preset": "sunset",
+  "theme_preset_by_workspace": {
+    "~/work": "Neon-Slate",
+    "~/personal": "rainbow",
+    "~/empty": ""
+  }
+}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if len(cfg.ThemePresetByWorkspace) != 1 || cfg.ThemePresetByWorkspace[filepath.Join(home, "work")] != ThemePresetNeonSlate {
+		t.Fatalf("unexpected theme map %+v", cfg.ThemePresetByWorkspace)
+	}
+	if cfg.ThemePreset != ThemePresetSunset {
+		t.Fatalf("expected global theme kept, got %q", cfg.ThemePreset)
+	}
+
+	cfg.ThemePresetByWorkspace["~/personal"] = "sunset"
+	cfg.ThemePresetByWorkspace["~/other"] = "bogus"
+	if err := Save(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	reloaded, err := Load()
+	if err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	if len(reloaded.ThemePresetByWorkspace) != 2 || reloaded.ThemePresetByWorkspace[filepath.Join(home, "personal")] != ThemePresetSunset {
+		t.Fatalf("expected save to normalize keys and drop invalid presets, got %+v", reloaded.ThemePresetByWorkspace)
+	}
+}
+
+func TestDraftRetentionSettingsDefaultAndClamp(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for _, tc := range []struct {
+		age, total, skips             int
+		wantAge, wantTotal, wantSkips int
+	}{
+		{0, 0, 0, DefaultDraftMaxAgeDays, DefaultDraftMaxTotalMB, DefaultDraftOrphanSkips},
+		{-3, -1, -2, DefaultDraftMaxAgeDays, DefaultDraftMaxTotalMB, DefaultDraftOrphanSkips},
+		{30, 5, 3, 30, 5, 3},
+		{99999, 99999, 99, MaxDraftMaxAgeDays, MaxDraftMaxTotalMB, MaxDraftOrphanSkips},
+	} {
+		if err := Save(Config{NotesDir: "~/notes", DraftMaxAgeDays: tc.age, DraftMaxTotalMB: tc.total, DraftOrphanSkips: tc.skips}); err != nil {
+			t.Fatalf("save config: %v", err)
+		}
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("load config: %v", err)
+		}
+		if cfg.DraftMaxAgeDays != tc.wantAge || cfg.DraftMaxTotalMB != tc.wantTotal || cfg.DraftOrphanSkips != tc.wantSkips {
+			t.Fatalf("%d/%d/%d: expected %d/%d/%d, got %d/%d/%d", tc.age, tc.total, tc.skips, tc.wantAge, tc.wantTotal, tc.wantSkips, cfg.DraftMaxAgeDays, cfg.DraftMaxTotalMB, cfg.DraftOrphanSkips)
+		}
+	}
+}
+
+func TestCalcResultNormalizes(t *testing.T) {
+	for raw, want := range map[string]string{
+		"":          CalcResultAppend,
+		"bogus":     CalcResultAppend,
+		" Replace ": CalcResultReplace,
+		"append":    CalcResultAppend,
+	} {
+		if got := NormalizeCalcResult(raw); got != want {
+			t.Fatalf("NormalizeCalcResult(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestFooterPriorityNormalizes(t *testing.T) {
+	got := NormalizeFooterPriority([]string{" Git ", "bogus", "keys", "git", "STATUS"})
+	if strings.Join(got, ",") != "git,help,status" {
+		t.Fatalf("unexpected footer priority %v", got)
+	}
+	if got := NormalizeFooterPriority([]string{"", "nope"}); got != nil {
+		t.Fatalf("expected nil for no known kinds, got %v", got)
+	}
+}
+
+func TestRenderStyleNormalizes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for raw, want := range map[string]string{
+		"":                     "",
+		" Light ":              RenderStyleLight,
+		"NOTTY":                RenderStyleNotty,
+		"auto":                 RenderStyleAuto,
+		"~/styles/dark.json":   filepath.Join(home, "styles", "dark.json"),
+		"/etc/../glamour.json": "/glamour.json",
+	} {
+		if got := NormalizeRenderStyle(raw); got != want {
+			t.Fatalf("NormalizeRenderStyle(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
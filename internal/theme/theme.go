@@ -0,0 +1,181 @@
+// Package theme loads custom UI color themes for cli-notes from JSON files.
+//
+// A theme file is a flat JSON object that sets every color token used by the
+// terminal UI. Values are ANSI 256-color numbers ("0"–"255") or hex colors
+// ("#rgb" or "#rrggbb"), exactly as Lipgloss accepts them. For example:
+//
+//	{
+//	    "surface": "236",
+//	    "surface_alt": "238",
+//	    "text_primary": "255",
+//	    "text_muted": "250",
+//	    "accent_browse": "39",
+//	    "accent_edit": "44",
+//	    "accent_warn": "214",
+//	    "accent_success": "114",
+//	    "badge_dir": "29",
+//	    "badge_file": "25",
+//	    "badge_pin": "214",
+//	    "badge_pin_text": "16",
+//	    "badge_tags": "37",
+//	    "selected_bg": "255",
+//	    "selected_fg": "236",
+//	    "selection_bg": "230",
+//	    "selection_fg": "17",
+//	    "editor_code": "#87d7ff",
+//	    "markdown_style": "dark"
+//	}
+//
+// Every color key is required and unknown keys are rejected, so a typo cannot
+// silently leave part of the UI on a preset's colors. markdown_style is the
+// only optional key: "dark" (the default) or "light" picks the Glamour style
+// for the preview. The app falls back to the configured theme preset when a
+// file cannot be loaded.
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Theme holds the color tokens the UI styles are built from.
+type Theme struct {
+	// Surface is the editor active-line background.
+	Surface string `json:"surface"`
+	// SurfaceAlt is the editor cursor-line color while the editor is blurred.
+	SurfaceAlt string `json:"surface_alt"`
+	// TextPrimary is the text color on headers, the status bar, and badges.
+	TextPrimary string `json:"text_primary"`
+	// TextMuted colors hints, placeholders, and other de-emphasized text.
+	TextMuted string `json:"text_muted"`
+
+	// AccentBrowse colors the preview pane border, preview header, status
+	// bar, markdown file names, and editor line numbers.
+	AccentBrowse string `json:"accent_browse"`
+	// AccentEdit colors the edit pane border, edit header, edit-mode status
+	// bar, and editor prompt.
+	AccentEdit string `json:"accent_edit"`
+	// AccentWarn colors collapsed-folder markers, removed diff lines, and
+	// code fences in the editor.
+	AccentWarn string `json:"accent_warn"`
+	// AccentSuccess colors folder names, expanded-folder markers, added diff
+	// lines, and met word goals.
+	AccentSuccess string `json:"accent_success"`
+
+	// BadgeDir is the background of the DIR badge.
+	BadgeDir string `json:"badge_dir"`
+	// BadgeFile is the background of the MD badge.
+	BadgeFile string `json:"badge_file"`
+	// BadgePin is the background of the PIN badge.
+	BadgePin string `json:"badge_pin"`
+	// BadgePinText is the text color of the PIN badge.
+	BadgePinText string `json:"badge_pin_text"`
+	// BadgeTags is the background of the TAGS badge.
+	BadgeTags string `json:"badge_tags"`
+
+	// SelectedBg and SelectedFg color the selected row in the tree and in
+	// popup lists.
+	SelectedBg string `json:"selected_bg"`
+	SelectedFg string `json:"selected_fg"`
+	// SelectionBg and SelectionFg color selected text in the editor.
+	SelectionBg string `json:"selection_bg"`
+	SelectionFg string `json:"selection_fg"`
+	// EditorCode colors lines inside fenced code blocks in the editor.
+	EditorCode string `json:"editor_code"`
+
+	// MarkdownStyle is the Glamour style for the preview: MarkdownStyleDark
+	// (also used when empty) or MarkdownStyleLight.
+	MarkdownStyle string `json:"markdown_style,omitempty"`
+}
+
+// Markdown styles a theme can select for the preview.
+const (
+	MarkdownStyleDark  = "dark"
+	MarkdownStyleLight = "light"
+)
+
+// LoadTheme reads and validates the theme file at path.
+func LoadTheme(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("read theme file %q: %w", path, err)
+	}
+	var t Theme
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&t); err != nil {
+		return Theme{}, fmt.Errorf("parse theme file %q: %w", path, err)
+	}
+	if err := t.Validate(); err != nil {
+		return Theme{}, fmt.Errorf("invalid theme file %q: %w", path, err)
+	}
+	return t, nil
+}
+
+// Validate reports the first token that is missing or not a color Lipgloss
+// understands, in the order the keys are documented, then checks
+// markdown_style.
+func (t Theme) Validate() error {
+	for _, field := range t.fields() {
+		value := strings.TrimSpace(field.value)
+		if value == "" {
+			return fmt.Errorf("%s is required", field.key)
+		}
+		if !validColor(value) {
+			return fmt.Errorf("%s: %q is not an ANSI color number (0-255) or hex color", field.key, field.value)
+		}
+	}
+	switch t.MarkdownStyle {
+	case "", MarkdownStyleDark, MarkdownStyleLight:
+	default:
+		return fmt.Errorf("markdown_style: %q must be %q or %q", t.MarkdownStyle, MarkdownStyleDark, MarkdownStyleLight)
+	}
+	return nil
+}
+
+type themeField struct {
+	key   string
+	value string
+}
+
+func (t Theme) fields() []themeField {
+	return []themeField{
+		{"surface", t.Surface},
+		{"surface_alt", t.SurfaceAlt},
+		{"text_primary", t.TextPrimary},
+		{"text_muted", t.TextMuted},
+		{"accent_browse", t.AccentBrowse},
+		{"accent_edit", t.AccentEdit},
+		{"accent_warn", t.AccentWarn},
+		{"accent_success", t.AccentSuccess},
+		{"badge_dir", t.BadgeDir},
+		{"badge_file", t.BadgeFile},
+		{"badge_pin", t.BadgePin},
+		{"badge_pin_text", t.BadgePinText},
+		{"badge_tags", t.BadgeTags},
+		{"selected_bg", t.SelectedBg},
+		{"selected_fg", t.SelectedFg},
+		{"selection_bg", t.SelectionBg},
+		{"selection_fg", t.SelectionFg},
+		{"editor_code", t.EditorCode},
+	}
+}
+
+// validColor reports whether value is an ANSI 256-color number or a #rgb /
+// #rrggbb hex color.
+func validColor(value string) bool {
+	if strings.HasPrefix(value, "#") {
+		hex := value[1:]
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(value)
+	return err == nil && n >= 0 && n <= 255
+}
@@ -0,0 +1,77 @@
+package theme
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const validThemeJSON = `{
+  "surface": "236",
+  "surface_alt": "238",
+  "text_primary": "255",
+  "text_muted": "250",
+  "accent_browse": "#3a7bd5",
+  "accent_edit": "44",
+  "accent_warn": "214",
+  "accent_success": "#8c8",
+  "badge_dir": "29",
+  "badge_file": "25",
+  "badge_pin": "214",
+  "badge_pin_text": "16",
+  "badge_tags": "37",
+  "selected_bg": "255",
+  "selected_fg": "236",
+  "selection_bg": "230",
+  "selection_fg": "17",
+  "editor_code": "117"
+}`
+
+func writeTheme(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "theme.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write theme: %v", err)
+	}
+	return path
+}
+
+func TestLoadThemeReadsAllKeys(t *testing.T) {
+	got, err := LoadTheme(writeTheme(t, validThemeJSON))
+	if err != nil {
+		t.Fatalf("load theme: %v", err)
+	}
+	if got.AccentBrowse != "#3a7bd5" || got.AccentSuccess != "#8c8" || got.BadgePinText != "16" || got.EditorCode != "117" {
+		t.Fatalf("unexpected theme %+v", got)
+	}
+}
+
+func TestLoadThemeRejectsInvalidFiles(t *testing.T) {
+	cases := map[string]struct {
+		content string
+		want    string
+	}{
+		"missing key":   {strings.Replace(validThemeJSON, `"editor_code": "117"`, `"editor_code": ""`, 1), "editor_code is required"},
+		"bad ansi":      {strings.Replace(validThemeJSON, `"surface": "236"`, `"surface": "300"`, 1), "surface:"},
+		"bad hex":       {strings.Replace(validThemeJSON, `"#3a7bd5"`, `"#3a7bd"`, 1), "accent_browse:"},
+		"unknown key":   {strings.Replace(validThemeJSON, `"surface_alt"`, `"surface_alternate"`, 1), "unknown field"},
+		"malformed":     {`{"surface": `, "parse theme file"},
+		"bad style":     {strings.Replace(validThemeJSON, `"editor_code": "117"`, `"editor_code": "117", "markdown_style": "auto"`, 1), "markdown_style:"},
+		"color by name": {strings.Replace(validThemeJSON, `"text_muted": "250"`, `"text_muted": "gray"`, 1), "text_muted:"},
+	}
+	for name, tc := range cases {
+		_, err := LoadTheme(writeTheme(t, tc.content))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", name, tc.want, err)
+		}
+	}
+}
+
+func TestLoadThemeMissingFile(t *testing.T) {
+	_, err := LoadTheme(filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not-exist error, got %v", err)
+	}
+}